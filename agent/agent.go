@@ -24,6 +24,10 @@ type Agent struct {
 	vars      map[string]any    // lazy placeholder overrides, applied at Build time
 	meta      TemplateMeta      // parsed frontmatter (includes Sections)
 	sections  *SectionRegistry  // shared core section registry
+
+	channel       string // originating channel of the current wake (e.g. "discord")
+	channelPrompt string // per-channel instruction snippet; empty = none
+	pinned        string // per-session pinned notes; empty = none
 }
 
 // SetSections sets the shared SectionRegistry for core section assembly.
@@ -36,6 +40,21 @@ func (a *Agent) SetLocation(loc *time.Location) {
 	a.loc = loc
 }
 
+// SetChannel sets the channel the current turn comes from and its prompt
+// snippet. The snippet replaces {{CHANNEL}} in the template, or is appended
+// as a channel_instruction block when the template has no such placeholder.
+func (a *Agent) SetChannel(channel, prompt string) {
+	a.channel = channel
+	a.channelPrompt = strings.TrimSpace(prompt)
+}
+
+// SetPinned sets the session's pinned notes. They replace {{PINNED}} in the
+// template, or are appended as a pinned_context block when the template has
+// no such placeholder.
+func (a *Agent) SetPinned(notes string) {
+	a.pinned = strings.TrimSpace(notes)
+}
+
 // Set records a placeholder replacement applied lazily at Build time.
 // Supported value types: string, time.Time, []string.
 func (a *Agent) Set(key string, value any) *Agent {
@@ -89,6 +108,18 @@ func (a *Agent) Build() string {
 		}
 	}
 
+	// Channel instruction — config-supplied, per originating channel.
+	if a.channelPrompt != "" && !strings.Contains(prompt, "{{CHANNEL}}") {
+		channelHeader := fmt.Sprintf("---\ntype: channel_instruction\nchannel: %s\nprompt: follow the instruction for this channel\n---", a.channel)
+		prompt += "\n\n" + channelHeader + "\n\n" + a.channelPrompt
+	}
+
+	// Pinned notes — per-session, set with the pin tool, never compacted.
+	if a.pinned != "" && !strings.Contains(prompt, "{{PINNED}}") {
+		pinnedHeader := "---\ntype: pinned_context\nprompt: Notes pinned in this session. They stay in every prompt and survive compaction; unpin them when no longer relevant.\n---"
+		prompt += "\n\n" + pinnedHeader + "\n\n" + a.pinned
+	}
+
 	// ── Stage 4: Per-session sections (frontmatter opt-in) ──
 	var consumed map[string]bool
 	if len(a.meta.Sections) > 0 {
@@ -117,6 +148,8 @@ func (a *Agent) Build() string {
 	}
 	prompt = strings.ReplaceAll(prompt, "{{DATE}}", now.Format(dateLayout))
 	prompt = strings.ReplaceAll(prompt, "{{CALENDAR}}", formatCalendar(now))
+	prompt = strings.ReplaceAll(prompt, "{{CHANNEL}}", a.channelPrompt)
+	prompt = strings.ReplaceAll(prompt, "{{PINNED}}", a.pinned)
 
 	for key, value := range a.vars {
 		if consumed != nil && consumed[key] {
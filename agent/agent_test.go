@@ -109,6 +109,7 @@ func TestAllAgentsBuild_NoUnresolvedPlaceholders(t *testing.T) {
 			a.SetSections(secReg)
 			a.SetLocation(now.Location())
 			a.Set("TOOLS", "tool_a, tool_b, tool_c")
+			a.Set("MODEL", "glm-5 via zhipu-cn; input: text; reasoning: on")
 			a.Set("SKILLS", "skill_x: does X\nskill_y: does Y")
 			a.Set("TASK", "Test task content")
 			a.Set(SectionUserMemory, "## User Preferences\n\nTest user preferences")
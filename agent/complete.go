@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// Complete sends prompt to p as a single user message — no system prompt,
+// no tools, no session history, no wake header — and returns the reply.
+// It is the raw mode behind `nagobot run --raw`.
+func Complete(ctx context.Context, p provider.Provider, prompt string) (string, provider.Usage, error) {
+	if p == nil {
+		return "", provider.Usage{}, fmt.Errorf("no provider configured")
+	}
+	result, err := p.Chat(ctx, &provider.Request{
+		Messages: []provider.Message{provider.UserMessage(prompt)},
+	})
+	if err != nil {
+		return "", provider.Usage{}, err
+	}
+	resp, err := result.Wait()
+	if err != nil {
+		return "", provider.Usage{}, err
+	}
+	return strings.TrimSpace(resp.Content), resp.Usage, nil
+}
@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+type recordingProvider struct {
+	req *provider.Request
+}
+
+func (p *recordingProvider) Chat(_ context.Context, req *provider.Request) (provider.ChatResult, error) {
+	p.req = req
+	return provider.NewBasicResult(&provider.Response{
+		Content: " positive\n",
+		Usage:   provider.Usage{PromptTokens: 12, CompletionTokens: 1, TotalTokens: 13},
+	}), nil
+}
+
+func TestCompleteSendsOnlyTheUserPrompt(t *testing.T) {
+	p := &recordingProvider{}
+	out, usage, err := Complete(context.Background(), p, "Classify the sentiment: I love it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "positive" || usage.TotalTokens != 13 {
+		t.Errorf("Complete = %q, %+v", out, usage)
+	}
+	if len(p.req.Tools) != 0 {
+		t.Errorf("tools sent: %d", len(p.req.Tools))
+	}
+	if len(p.req.Messages) != 1 {
+		t.Fatalf("messages = %+v, want only the prompt", p.req.Messages)
+	}
+	m := p.req.Messages[0]
+	if m.Role != "user" || m.Content != "Classify the sentiment: I love it" {
+		t.Errorf("message = %+v, want the bare user prompt", m)
+	}
+}
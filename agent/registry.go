@@ -13,18 +13,28 @@ import (
 
 // AgentDef represents an agent template file under workspace/agents.
 type AgentDef struct {
-	Name             string // Callable name used by dispatch(to=subagent|fork).agent
-	Description      string // Short description shown in system prompt context
-	Specialty        string // Agent specialty declared in frontmatter (e.g. "chat", "toolcall")
-	Provider         string // Provider name declared in frontmatter (optional, used for model-pinned agents)
-	Path             string // Full path to the template file
-	ContextWindowCap int    // Parsed token cap; 0 = no cap
-	TierLossyMode    string // "slide_window" | "" (disabled)
-	TierLossyKeep    int    // slide_window: last N turns to retain
+	Name              string   // Callable name used by dispatch(to=subagent|fork).agent
+	Description       string   // Short description shown in system prompt context
+	Specialty         string   // Agent specialty declared in frontmatter (e.g. "chat", "toolcall")
+	Provider          string   // Provider name declared in frontmatter (optional, used for model-pinned agents)
+	Path              string   // Full path to the template file
+	ContextWindowCap  int      // Parsed token cap; 0 = no cap
+	TierLossyMode     string   // "slide_window" | "" (disabled)
+	TierLossyKeep     int      // slide_window: last N turns to retain
+	Temperature       *float64 // Sampling temperature override; nil = thread default
+	MaxTokens         int      // Output token limit override; 0 = thread default
+	ReasoningEffort   string   // "low" | "medium" | "high" | "" (provider config, then high)
+	MaxToolIterations int      // Tool-call rounds per turn before the runner stops; 0 = runner default
 }
 
 const agentsBuiltinDir = "agents-builtin"
 
+// Accepted range for a per-agent temperature override; values outside are clamped.
+const (
+	minAgentTemperature = 0.0
+	maxAgentTemperature = 2.0
+)
+
 // AgentRegistry loads agent templates from workspace/agents and workspace/agents-builtin.
 type AgentRegistry struct {
 	workspace    string
@@ -153,15 +163,50 @@ func loadAgentsFromDir(dir string, dest map[string]*AgentDef) {
 			}
 		}
 
+		var temperature *float64
+		if meta.Temperature != nil {
+			t := *meta.Temperature
+			if t < minAgentTemperature || t > maxAgentTemperature {
+				clamped := min(max(t, minAgentTemperature), maxAgentTemperature)
+				logger.Warn("temperature out of range, clamping", "path", path, "value", t, "clamped", clamped)
+				t = clamped
+			}
+			temperature = &t
+		}
+
+		maxTokens := meta.MaxTokens
+		if maxTokens < 0 {
+			logger.Warn("invalid max_tokens, ignoring", "path", path, "value", maxTokens)
+			maxTokens = 0
+		}
+
+		maxToolIterations := meta.MaxToolIterations
+		if maxToolIterations < 0 {
+			logger.Warn("invalid max_tool_iterations, ignoring", "path", path, "value", maxToolIterations)
+			maxToolIterations = 0
+		}
+
+		reasoningEffort := strings.ToLower(strings.TrimSpace(meta.ReasoningEffort))
+		switch reasoningEffort {
+		case "", "low", "medium", "high":
+		default:
+			logger.Warn("invalid reasoning_effort, ignoring", "path", path, "value", meta.ReasoningEffort)
+			reasoningEffort = ""
+		}
+
 		dest[normalizeAgentName(name)] = &AgentDef{
-			Name:             name,
-			Description:      strings.TrimSpace(meta.Description),
-			Specialty:        strings.TrimSpace(meta.Specialty),
-			Provider:         strings.TrimSpace(meta.Provider),
-			Path:             path,
-			ContextWindowCap: capTokens,
-			TierLossyMode:    tierLossyMode,
-			TierLossyKeep:    tierLossyKeep,
+			Name:              name,
+			Description:       strings.TrimSpace(meta.Description),
+			Specialty:         strings.TrimSpace(meta.Specialty),
+			Provider:          strings.TrimSpace(meta.Provider),
+			Path:              path,
+			ContextWindowCap:  capTokens,
+			TierLossyMode:     tierLossyMode,
+			TierLossyKeep:     tierLossyKeep,
+			Temperature:       temperature,
+			MaxTokens:         maxTokens,
+			ReasoningEffort:   reasoningEffort,
+			MaxToolIterations: maxToolIterations,
 		}
 	}
 }
@@ -237,6 +282,25 @@ func (d *AgentDef) ClampContextWindow(base int) int {
 	return base
 }
 
+// Defs reloads templates from disk and returns every registered agent sorted
+// by name. Builtin templates override user templates of the same name.
+func (r *AgentRegistry) Defs() []*AgentDef {
+	if r == nil {
+		return nil
+	}
+	r.load()
+	r.mu.RLock()
+	defs := make([]*AgentDef, 0, len(r.agents))
+	for _, def := range r.agents {
+		defs = append(defs, def)
+	}
+	r.mu.RUnlock()
+	sort.Slice(defs, func(i, j int) bool {
+		return strings.ToLower(defs[i].Name) < strings.ToLower(defs[j].Name)
+	})
+	return defs
+}
+
 // Def returns the AgentDef for the given name, or nil if not found.
 func (r *AgentRegistry) Def(name string) *AgentDef {
 	if r == nil {
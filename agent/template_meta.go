@@ -9,15 +9,21 @@ import (
 
 // TemplateMeta holds the YAML frontmatter fields of an agent template.
 type TemplateMeta struct {
-	Name             string   `yaml:"name"`
-	Description      string   `yaml:"description"`
-	Specialty        string   `yaml:"specialty"`
-	Provider         string   `yaml:"provider"`
-	Model            string   `yaml:"model"`                        // deprecated: use Specialty; kept for backward compatibility
-	Sections         []string `yaml:"sections,omitempty"`           // per-session sections to auto-append (e.g. user_memory_section)
-	ContextWindowCap string   `yaml:"context_window_cap,omitempty"` // human-readable cap (e.g. "64k", "200k", "1M") — clamps effective context window for this agent
-	TierLossyMode    string   `yaml:"tier_lossy_mode,omitempty"`    // lossy compression mode: "slide_window" (phase 1) | "ratio" (future)
-	TierLossyKeep    int      `yaml:"tier_lossy_keep,omitempty"`    // slide_window: last N user-assistant turns to retain
+	Name                   string   `yaml:"name"`
+	Description            string   `yaml:"description"`
+	Specialty              string   `yaml:"specialty"`
+	Provider               string   `yaml:"provider"`
+	Model                  string   `yaml:"model"`                         // deprecated: use Specialty; kept for backward compatibility
+	Sections               []string `yaml:"sections,omitempty"`            // per-session sections to auto-append (e.g. user_memory_section)
+	ContextWindowCap       string   `yaml:"context_window_cap,omitempty"`  // human-readable cap (e.g. "64k", "200k", "1M") — clamps effective context window for this agent
+	TierLossyMode          string   `yaml:"tier_lossy_mode,omitempty"`     // lossy compression mode: "slide_window" (phase 1) | "ratio" (future)
+	TierLossyKeep          int      `yaml:"tier_lossy_keep,omitempty"`     // slide_window: last N user-assistant turns to retain
+	Temperature            *float64 `yaml:"temperature,omitempty"`         // sampling temperature override; nil = thread default
+	MaxTokens              int      `yaml:"max_tokens,omitempty"`          // output token limit override; 0 = thread default
+	MaxTokensCamel         int      `yaml:"maxTokens,omitempty"`           // alias of max_tokens, matching config.yaml spelling
+	ReasoningEffort        string   `yaml:"reasoning_effort,omitempty"`    // OpenAI Responses reasoning effort: low | medium | high
+	MaxToolIterations      int      `yaml:"max_tool_iterations,omitempty"` // tool-call rounds per turn before the runner stops; 0 = runner default
+	MaxToolIterationsCamel int      `yaml:"maxToolIterations,omitempty"`   // alias of max_tool_iterations
 }
 
 // ParseTokenAmount parses a human-readable token count.
@@ -64,10 +70,15 @@ func ParseTemplate(content string) (meta TemplateMeta, body string, hasHeader bo
 	if meta.Specialty == "" && meta.Model != "" {
 		meta.Specialty = meta.Model
 	}
+	if meta.MaxTokens == 0 && meta.MaxTokensCamel != 0 {
+		meta.MaxTokens = meta.MaxTokensCamel
+	}
+	if meta.MaxToolIterations == 0 && meta.MaxToolIterationsCamel != 0 {
+		meta.MaxToolIterations = meta.MaxToolIterationsCamel
+	}
 	return meta, body, true, nil
 }
 
-
 func splitFrontMatter(content string) (header string, body string, ok bool) {
 	normalized := strings.ReplaceAll(content, "\r\n", "\n")
 	if !strings.HasPrefix(normalized, "---\n") {
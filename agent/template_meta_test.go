@@ -1,6 +1,10 @@
 package agent
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestParseTokenAmount(t *testing.T) {
 	cases := []struct {
@@ -52,3 +56,59 @@ body`
 		t.Errorf("parsed cap = %d, want 64000", got)
 	}
 }
+
+func TestParseTemplateSampling(t *testing.T) {
+	tpl := `---
+name: coder
+temperature: 0.2
+maxTokens: 4096
+---
+body`
+	meta, _, _, err := ParseTemplate(tpl)
+	if err != nil {
+		t.Fatalf("ParseTemplate: %v", err)
+	}
+	if meta.Temperature == nil || *meta.Temperature != 0.2 {
+		t.Errorf("Temperature = %v, want 0.2", meta.Temperature)
+	}
+	if meta.MaxTokens != 4096 {
+		t.Errorf("MaxTokens = %d, want 4096", meta.MaxTokens)
+	}
+}
+
+func TestRegistrySamplingClamp(t *testing.T) {
+	ws := t.TempDir()
+	agentsDir := filepath.Join(ws, "agents")
+	if err := os.MkdirAll(agentsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"hot.md":   "---\nname: hot\ntemperature: 3.5\nmax_tokens: -1\n---\nbody",
+		"plain.md": "---\nname: plain\n---\nbody",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(agentsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reg := NewRegistry(ws)
+	hot := reg.Def("hot")
+	if hot == nil {
+		t.Fatal("agent hot not loaded")
+	}
+	if hot.Temperature == nil || *hot.Temperature != maxAgentTemperature {
+		t.Errorf("Temperature = %v, want clamped to %v", hot.Temperature, maxAgentTemperature)
+	}
+	if hot.MaxTokens != 0 {
+		t.Errorf("MaxTokens = %d, want 0 (negative ignored)", hot.MaxTokens)
+	}
+
+	plain := reg.Def("plain")
+	if plain == nil {
+		t.Fatal("agent plain not loaded")
+	}
+	if plain.Temperature != nil || plain.MaxTokens != 0 {
+		t.Errorf("plain agent should have no overrides, got temperature=%v max_tokens=%d", plain.Temperature, plain.MaxTokens)
+	}
+}
@@ -0,0 +1,88 @@
+package channel
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
+)
+
+// busyReplyTimeout bounds the send of a busy reply so a slow platform API
+// cannot pile up goroutines while the channel is already saturated.
+const busyReplyTimeout = 15 * time.Second
+
+// backpressure holds an interactive channel's inbound buffer limits.
+type backpressure struct {
+	highWater int    // queue length at which new messages are turned away
+	reply     string // busy reply text
+}
+
+// newBackpressure derives the high-water mark for a buffer of the given
+// capacity from config. The mark is always at least 1 and at most capacity.
+func newBackpressure(cfg *config.Config, capacity int) backpressure {
+	hw := capacity * cfg.GetChannelHighWaterPercent() / 100
+	if hw < 1 {
+		hw = 1
+	}
+	if hw > capacity {
+		hw = capacity
+	}
+	return backpressure{highWater: hw, reply: cfg.GetChannelBusyReply()}
+}
+
+// enqueue delivers m to queue. When the queue is at or above the high-water
+// mark (or full), the message is not queued; instead ch sends the busy reply
+// to the message's chat so the user knows to retry. Returns true if queued.
+func (bp backpressure) enqueue(ch Channel, queue chan *Message, done <-chan struct{}, m *Message) bool {
+	if bp.highWater <= 0 || len(queue) < bp.highWater {
+		select {
+		case queue <- m:
+			return true
+		case <-done:
+			return false
+		default:
+		}
+	}
+
+	logger.Warn("channel buffer above high-water mark, replying busy",
+		"channel", ch.Name(), "depth", len(queue), "highWater", bp.highWater, "capacity", cap(queue))
+	replyTo := ""
+	if m.Metadata != nil {
+		replyTo = strings.TrimSpace(m.Metadata["chat_id"])
+	}
+	if replyTo == "" || bp.reply == "" {
+		return false
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), busyReplyTimeout)
+		defer cancel()
+		if err := ch.Send(ctx, &Response{Text: bp.reply, ReplyTo: replyTo, Metadata: m.Metadata}); err != nil {
+			logger.Warn("busy reply failed", "channel", ch.Name(), "replyTo", replyTo, "err", err)
+		}
+	}()
+	return false
+}
+
+// QueueDepth is the fill level of one channel's inbound message buffer.
+type QueueDepth struct {
+	Channel  string
+	Depth    int
+	Capacity int
+}
+
+// QueueDepths reports the inbound buffer fill of every registered channel,
+// sorted by channel name.
+func (m *Manager) QueueDepths() []QueueDepth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]QueueDepth, 0, len(m.channels))
+	for name, ch := range m.channels {
+		q := ch.Messages()
+		out = append(out, QueueDepth{Channel: name, Depth: len(q), Capacity: cap(q)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Channel < out[j].Channel })
+	return out
+}
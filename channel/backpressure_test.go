@@ -0,0 +1,64 @@
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+// recordingChannel captures responses passed to Send.
+type recordingChannel struct {
+	noopChannel
+	sent chan *Response
+}
+
+func (r *recordingChannel) Send(_ context.Context, resp *Response) error {
+	r.sent <- resp
+	return nil
+}
+
+func TestEnqueueRepliesBusyWhenSaturated(t *testing.T) {
+	cfg := &config.Config{Channels: &config.ChannelsConfig{
+		Backpressure: &config.BackpressureConfig{HighWaterPercent: 50, BusyReply: "busy, retry later"},
+	}}
+	queue := make(chan *Message, 4)
+	bp := newBackpressure(cfg, cap(queue))
+	if bp.highWater != 2 {
+		t.Fatalf("highWater = %d, want 2", bp.highWater)
+	}
+	ch := &recordingChannel{sent: make(chan *Response, 1)}
+	done := make(chan struct{})
+
+	for i := 0; i < 2; i++ {
+		if !bp.enqueue(ch, queue, done, &Message{Metadata: map[string]string{"chat_id": "42"}}) {
+			t.Fatalf("message %d below the high-water mark was not queued", i)
+		}
+	}
+
+	if bp.enqueue(ch, queue, done, &Message{Text: "third", Metadata: map[string]string{"chat_id": "42"}}) {
+		t.Fatal("message at the high-water mark was queued")
+	}
+	if len(queue) != 2 {
+		t.Errorf("queue depth = %d, want 2", len(queue))
+	}
+	select {
+	case resp := <-ch.sent:
+		if resp.Text != "busy, retry later" || resp.ReplyTo != "42" {
+			t.Errorf("busy reply = %+v, want text %q to 42", resp, "busy, retry later")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no busy reply sent for saturated buffer")
+	}
+}
+
+func TestBackpressureDefaults(t *testing.T) {
+	bp := newBackpressure(&config.Config{}, 100)
+	if bp.highWater != config.DefaultChannelHighWaterPercent {
+		t.Errorf("highWater = %d, want %d", bp.highWater, config.DefaultChannelHighWaterPercent)
+	}
+	if bp.reply != config.DefaultChannelBusyReply {
+		t.Errorf("reply = %q, want default", bp.reply)
+	}
+}
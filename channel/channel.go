@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode/utf16"
 	"unicode/utf8"
 
 	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/discordmd"
 	"github.com/linanwx/nagobot/logger"
 )
 
@@ -25,9 +28,10 @@ type Message struct {
 
 // Response represents a response to send back.
 type Response struct {
-	Text     string            // Response text
-	ReplyTo  string            // Message ID to reply to
-	Metadata map[string]string // Channel-specific options
+	Text             string            // Response text
+	ReplyTo          string            // Target chat (channel-specific format)
+	ReplyToMessageID string            // Inbound message to post as a threaded reply to (optional)
+	Metadata         map[string]string // Channel-specific options
 }
 
 // Reconfigurable is an optional interface for channels that support
@@ -64,8 +68,14 @@ type Manager struct {
 	mu          sync.RWMutex
 	channels    map[string]Channel
 	WorkspaceFn func() string // optional: workspace root for resolving relative image paths
+	redactor    atomic.Pointer[Redactor]
+	translator  atomic.Pointer[TranslateFunc]
 }
 
+// TranslateFunc rewrites text bound for channelName, e.g. into the channel
+// users' language. It returns text unchanged when there is nothing to do.
+type TranslateFunc func(ctx context.Context, channelName, text string) string
+
 // NewManager creates a new channel manager.
 func NewManager() *Manager {
 	return &Manager{
@@ -137,7 +147,20 @@ func (m *Manager) SendTo(ctx context.Context, channelName, text, replyTo string)
 	return m.SendResponse(ctx, channelName, &Response{Text: text, ReplyTo: replyTo})
 }
 
-// SendResponse delivers resp via the named channel. After a successful text
+// SetTranslator installs fn for every send made through the manager, so
+// replies, default sinks of system wakes and deliver-mode jobs are all
+// translated in one place. A nil fn disables translation.
+func (m *Manager) SetTranslator(fn TranslateFunc) {
+	if fn == nil {
+		m.translator.Store(nil)
+		return
+	}
+	m.translator.Store(&fn)
+}
+
+// SendResponse delivers resp via the named channel, translated by the
+// installed TranslateFunc and then with channels.redact masking applied to
+// its text. After a successful text
 // send, Markdown image references in resp.Text are dispatched to the channel's
 // ImageSender capability if it implements one.
 func (m *Manager) SendResponse(ctx context.Context, channelName string, resp *Response) error {
@@ -147,6 +170,16 @@ func (m *Manager) SendResponse(ctx context.Context, channelName string, resp *Re
 	if !ok {
 		return fmt.Errorf("channel not found: %s", channelName)
 	}
+	if tr := m.translator.Load(); tr != nil && resp != nil && strings.TrimSpace(resp.Text) != "" {
+		translated := *resp
+		translated.Text = (*tr)(ctx, channelName, resp.Text)
+		resp = &translated
+	}
+	if resp != nil && m.redactor.Load() != nil {
+		redacted := *resp
+		redacted.Text = m.redact(channelName, resp.Text)
+		resp = &redacted
+	}
 	if err := ch.Send(ctx, resp); err != nil {
 		return err
 	}
@@ -247,32 +280,79 @@ func fmtSeconds(s int) string {
 	return ""
 }
 
-// SplitMessage splits a long message into chunks (byte-based maxLen),
-// preferring newline boundaries and avoiding mid-rune splits.
+// Hard per-message limits (characters) enforced by each platform. Configured
+// maxMessageLength values above these are clamped.
+const (
+	telegramHardMaxMessageLength = 4096
+	discordHardMaxMessageLength  = discordmd.MaxMessageLen
+	feishuHardMaxMessageLength   = 30000 // well under the 150 KB text message body limit
+	minMessageLength             = 100   // smaller chunks only spam the chat
+)
+
+// MaxMessageLength returns the outgoing chunk size for a channel: the
+// configured channels.<name>.maxMessageLength, or the channel default when
+// unset, clamped to the platform's hard limit. Unknown channels get 0.
+func MaxMessageLength(cfg *config.Config, channelName string) int {
+	var configured, def, hard int
+	switch channelName {
+	case "telegram":
+		configured, def, hard = cfg.GetTelegramMaxMessageLength(), TelegramMaxMessageLength, telegramHardMaxMessageLength
+	case "discord":
+		configured, def, hard = cfg.GetDiscordMaxMessageLength(), DiscordMaxMessageLength, discordHardMaxMessageLength
+	case "feishu":
+		configured, def, hard = cfg.GetFeishuMaxMessageLength(), feishuMaxMessageLength, feishuHardMaxMessageLength
+	default:
+		return 0
+	}
+	switch {
+	case configured <= 0:
+		return def
+	case configured > hard:
+		logger.Warn("maxMessageLength above platform limit, clamping", "channel", channelName, "configured", configured, "limit", hard)
+		return hard
+	case configured < minMessageLength:
+		logger.Warn("maxMessageLength too small, raising", "channel", channelName, "configured", configured, "min", minMessageLength)
+		return minMessageLength
+	}
+	return configured
+}
+
+// SplitMessage splits a long message into chunks of at most maxLen runes,
+// preferring newline boundaries. Platform limits count characters, not
+// bytes, so CJK or emoji-heavy replies are not split more than needed.
 func SplitMessage(text string, maxLen int) []string {
-	if len(text) <= maxLen {
+	return splitMessage(text, maxLen, func(rune) int { return 1 })
+}
+
+// SplitMessageUTF16 is SplitMessage for platforms whose limit counts UTF-16
+// code units (Telegram): characters outside the BMP, such as most emoji,
+// count as two.
+func SplitMessageUTF16(text string, maxLen int) []string {
+	return splitMessage(text, maxLen, utf16.RuneLen)
+}
+
+// splitMessage splits text into chunks of at most maxLen units, where
+// units(r) is the length of one rune in the platform's measure.
+func splitMessage(text string, maxLen int, units func(rune) int) []string {
+	if textUnits(text, units) <= maxLen {
 		return []string{text}
 	}
 
 	var chunks []string
 	for len(text) > 0 {
-		if len(text) <= maxLen {
+		window := unitPrefixLen(text, maxLen, units)
+		if window == len(text) {
 			chunks = append(chunks, text)
 			break
 		}
 
-		// Try to split at newline within the byte window.
-		splitAt := maxLen
-		if idx := strings.LastIndex(text[:maxLen], "\n"); idx > maxLen/2 {
+		// Try to split at newline within the window.
+		splitAt := window
+		if idx := strings.LastIndex(text[:window], "\n"); idx >= 0 && textUnits(text[:idx], units) > maxLen/2 {
 			splitAt = idx + 1
 		}
-
-		// Avoid splitting in the middle of a multi-byte UTF-8 character.
-		for splitAt > 0 && !utf8.RuneStart(text[splitAt]) {
-			splitAt--
-		}
 		if splitAt == 0 {
-			// Entire prefix is a continuation byte sequence; advance past the rune.
+			// maxLen below one rune: emit one rune at a time rather than loop forever.
 			_, size := utf8.DecodeRuneInString(text)
 			splitAt = size
 		}
@@ -283,3 +363,24 @@ func SplitMessage(text string, maxLen int) []string {
 
 	return chunks
 }
+
+// textUnits returns the length of text in units.
+func textUnits(text string, units func(rune) int) int {
+	n := 0
+	for _, r := range text {
+		n += max(units(r), 1)
+	}
+	return n
+}
+
+// unitPrefixLen returns the byte length of the longest prefix of text that
+// fits in n units without cutting a rune.
+func unitPrefixLen(text string, n int, units func(rune) int) int {
+	for i, r := range text {
+		n -= max(units(r), 1)
+		if n < 0 {
+			return i
+		}
+	}
+	return len(text)
+}
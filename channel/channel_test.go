@@ -0,0 +1,98 @@
+package channel
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+func TestMaxMessageLength_DefaultsAndClamping(t *testing.T) {
+	cfg := &config.Config{Channels: &config.ChannelsConfig{
+		Telegram: &config.TelegramChannelConfig{MaxMessageLength: 10000},
+		Discord:  &config.DiscordChannelConfig{MaxMessageLength: 3500},
+		Feishu:   &config.FeishuChannelConfig{MaxMessageLength: 20},
+	}}
+	cases := []struct {
+		cfg     *config.Config
+		channel string
+		want    int
+	}{
+		{&config.Config{}, "telegram", TelegramMaxMessageLength},
+		{&config.Config{}, "discord", DiscordMaxMessageLength},
+		{&config.Config{}, "feishu", feishuMaxMessageLength},
+		{cfg, "telegram", telegramHardMaxMessageLength}, // above hard limit → clamped
+		{cfg, "discord", discordHardMaxMessageLength},   // above hard limit → clamped
+		{cfg, "feishu", minMessageLength},               // too small → raised
+		{cfg, "web", 0},
+	}
+	for _, c := range cases {
+		if got := MaxMessageLength(c.cfg, c.channel); got != c.want {
+			t.Errorf("MaxMessageLength(%s) = %d, want %d", c.channel, got, c.want)
+		}
+	}
+}
+
+func TestMaxMessageLength_ShorterLimitProducesMoreChunks(t *testing.T) {
+	text := strings.Repeat("a line of reply text\n", 300) // ~6300 bytes
+
+	defaultChunks := SplitMessage(text, MaxMessageLength(&config.Config{}, "discord"))
+	cfg := &config.Config{Channels: &config.ChannelsConfig{
+		Discord: &config.DiscordChannelConfig{MaxMessageLength: 500},
+	}}
+	shortChunks := SplitMessage(text, MaxMessageLength(cfg, "discord"))
+
+	if len(shortChunks) <= len(defaultChunks) {
+		t.Fatalf("configured 500-character limit gave %d chunks, default gave %d; want more", len(shortChunks), len(defaultChunks))
+	}
+	for i, chunk := range shortChunks {
+		if n := utf8.RuneCountInString(chunk); n > 500 {
+			t.Fatalf("chunk %d is %d characters, want <= 500", i, n)
+		}
+	}
+	if got := strings.Join(shortChunks, ""); got != text {
+		t.Fatal("chunks do not reassemble to the original text")
+	}
+}
+
+func TestSplitMessage_CountsRunesNotBytes(t *testing.T) {
+	text := strings.Repeat("你好", 1500) // 3000 runes, 9000 bytes
+
+	chunks := SplitMessage(text, DiscordMaxMessageLength)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !utf8.ValidString(chunk) {
+			t.Fatalf("chunk %d is not valid UTF-8", i)
+		}
+		if n := utf8.RuneCountInString(chunk); n > DiscordMaxMessageLength {
+			t.Fatalf("chunk %d is %d characters, want <= %d", i, n, DiscordMaxMessageLength)
+		}
+	}
+	if got := strings.Join(chunks, ""); got != text {
+		t.Fatal("chunks do not reassemble to the original text")
+	}
+}
+
+func TestSplitMessageUTF16_CountsSurrogatePairs(t *testing.T) {
+	text := strings.Repeat("😀", 3000) // 3000 runes, 6000 UTF-16 code units
+
+	if got := len(SplitMessage(text, TelegramMaxMessageLength)); got != 1 {
+		t.Fatalf("rune split got %d chunks, want 1", got)
+	}
+	chunks := SplitMessageUTF16(text, TelegramMaxMessageLength)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if n := len(utf16.Encode([]rune(chunk))); n > TelegramMaxMessageLength {
+			t.Fatalf("chunk %d is %d UTF-16 units, want <= %d", i, n, TelegramMaxMessageLength)
+		}
+	}
+	if got := strings.Join(chunks, ""); got != text {
+		t.Fatal("chunks do not reassemble to the original text")
+	}
+}
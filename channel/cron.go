@@ -17,15 +17,26 @@ import (
 // CronChannel wraps a cron.Scheduler as a Channel. Each fired job wakes a
 // target session via onDirectWake — independent mode runs cron:<ID> with the
 // configured agent; inject mode wakes WakeSession directly without overriding
-// its agent. Send is a no-op; responses are controlled by the session's own
-// dispatch() calls.
+// its agent. Deliver mode skips the agent entirely and posts the task text to
+// a channel via the configured sender. Send is a no-op; responses are
+// controlled by the session's own dispatch() calls.
 type CronChannel struct {
 	storePath    string
 	seedJobs     []cronpkg.Job // config-defined seeds
 	scheduler    *cronpkg.Scheduler
 	messages     chan *Message
 	done         chan struct{}
-	onDirectWake func(sessionKey string, source msg.WakeSource, message, agentName, deliveryLabel string)
+	onDirectWake func(sessionKey string, source msg.WakeSource, message, agentName, deliveryLabel string, admin bool)
+	sender       CronSender // deliver-mode target; nil disables deliver jobs
+}
+
+// cronStorePollInterval is how often the running scheduler checks the store
+// for edits made by other processes.
+const cronStorePollInterval = 5 * time.Second
+
+// CronSender posts text to a named channel. *Manager satisfies it.
+type CronSender interface {
+	SendTo(ctx context.Context, channelName, text, replyTo string) error
 }
 
 // NewCronChannel creates a CronChannel from config.
@@ -36,13 +47,24 @@ func NewCronChannel(cfg *config.Config) *CronChannel {
 	}
 	ch := &CronChannel{
 		storePath: filepath.Join(workspace, "system", "cron.jsonl"),
-		seedJobs:  cfg.Cron,
+		seedJobs:  adminSeeds(cfg.Cron),
 		messages:  make(chan *Message, 64),
 		done:      make(chan struct{}),
 	}
 	return ch
 }
 
+// adminSeeds copies the config-defined seed jobs and marks them admin: they
+// are written by the operator, unlike jobs created from a chat turn.
+func adminSeeds(seeds []cronpkg.Job) []cronpkg.Job {
+	out := make([]cronpkg.Job, len(seeds))
+	for i, j := range seeds {
+		j.Admin = true
+		out[i] = j
+	}
+	return out
+}
+
 func (c *CronChannel) Name() string { return "cron" }
 
 // SetDirectWake sets a callback invoked on every cron fire. The callback is
@@ -50,11 +72,18 @@ func (c *CronChannel) Name() string { return "cron" }
 // non-empty for independent mode (sets/overrides session agent meta);
 // empty for inject mode (preserves target session's existing agent).
 // deliveryLabel carries mode-specific guidance that appears in the wake
-// frontmatter so the LLM knows where it should dispatch results.
-func (c *CronChannel) SetDirectWake(fn func(sessionKey string, source msg.WakeSource, message, agentName, deliveryLabel string)) {
+// frontmatter so the LLM knows where it should dispatch results. admin is
+// the job's Admin flag: whether the woken turn may use admin-only tools.
+func (c *CronChannel) SetDirectWake(fn func(sessionKey string, source msg.WakeSource, message, agentName, deliveryLabel string, admin bool)) {
 	c.onDirectWake = fn
 }
 
+// SetSender sets the sender used by deliver-mode jobs, which post their task
+// text directly to a channel without running an agent turn.
+func (c *CronChannel) SetSender(s CronSender) {
+	c.sender = s
+}
+
 // FindJob looks up a cron job by ID. Returns zero Job and false if the
 // scheduler hasn't started or the job doesn't exist.
 func (c *CronChannel) FindJob(id string) (cronpkg.Job, bool) {
@@ -78,51 +107,34 @@ func (c *CronChannel) AddJob(job cronpkg.Job) error {
 	return c.scheduler.AddJob(job)
 }
 
-func (c *CronChannel) Start(ctx context.Context) error {
-	factory := func(job *cronpkg.Job) (string, error) {
-		if job == nil {
-			return "", nil
-		}
-		if c.onDirectWake == nil {
-			// Fallback: push through Messages() channel (legacy, not expected in normal wiring).
-			c.messages <- c.buildMessage(job)
-			return "", nil
-		}
+// ListJobs returns the scheduler's persisted jobs, or nil before it starts.
+func (c *CronChannel) ListJobs() []cronpkg.Job {
+	if c.scheduler == nil {
+		return nil
+	}
+	return c.scheduler.ListJobs()
+}
 
-		jobID := strings.TrimSpace(job.ID)
-		if jobID == "" {
-			jobID = "job"
-		}
-		target := strings.TrimSpace(job.WakeSession)
-		task := strings.TrimSpace(job.Task)
+// RemoveJob delegates to the underlying scheduler.
+func (c *CronChannel) RemoveJob(id string) (bool, error) {
+	if c.scheduler == nil {
+		return false, fmt.Errorf("cron scheduler not started")
+	}
+	return c.scheduler.RemoveJob(id)
+}
 
-		if job.DirectWake {
-			// Inject mode: must have target session; agent is ignored (preserve target's meta).
-			if target == "" {
-				logger.Warn("cron: direct_wake without wake_session, skipping", "id", jobID)
-				return "", nil
-			}
-			delivery := "you were woken by cron (inject mode). Caller is cron — output to caller is dropped. " +
-				"Use dispatch(to=user) to message the channel user, or dispatch(to=session, session_key=...) " +
-				"to forward elsewhere."
-			c.onDirectWake(target, msg.WakeCron, task, "", delivery)
-			return "", nil
-		}
+// CatchUp fires catch_up at jobs missed while the process was down. Call it
+// after every channel has started so their deliveries are not lost.
+func (c *CronChannel) CatchUp() {
+	if c.scheduler == nil {
+		return
+	}
+	c.scheduler.CatchUp()
+}
 
-		// Independent mode: run in cron:<jobID> session with configured agent.
-		sessionKey := "cron:" + jobID
-		agent := strings.TrimSpace(job.Agent)
-		var delivery string
-		if target != "" {
-			delivery = "you were woken by cron (independent mode). Caller is cron — output to caller is dropped. " +
-				"After completing your task, dispatch(to=session, session_key=\"" + target + "\") to deliver results."
-		} else {
-			delivery = "you were woken by cron (independent mode). Caller is cron — output to caller is dropped. " +
-				"No delivery target configured; use dispatch explicitly if you need to forward results."
-			logger.Warn("cron: independent mode without wake_session (silent execution)", "id", jobID)
-		}
-		c.onDirectWake(sessionKey, msg.WakeCron, task, agent, delivery)
-		return "", nil
+func (c *CronChannel) Start(ctx context.Context) error {
+	factory := func(job *cronpkg.Job) (string, error) {
+		return c.fire(ctx, job)
 	}
 
 	sch, err := cronpkg.NewScheduler(c.storePath, factory, c.seedJobs)
@@ -135,7 +147,7 @@ func (c *CronChannel) Start(ctx context.Context) error {
 	}
 	c.scheduler.Start()
 
-	// Periodic reload goroutine.
+	// Reload when the store changes outside the scheduler (nagobot cron add/rm/set).
 	go func() {
 		for {
 			select {
@@ -143,9 +155,11 @@ func (c *CronChannel) Start(ctx context.Context) error {
 				return
 			case <-c.done:
 				return
-			case <-time.After(time.Minute):
-				if err := c.scheduler.Load(); err != nil {
+			case <-time.After(cronStorePollInterval):
+				if reloaded, err := c.scheduler.ReloadIfChanged(); err != nil {
 					logger.Warn("failed to reload cron jobs", "err", err)
+				} else if reloaded {
+					logger.Info("cron store changed on disk, jobs reloaded")
 				}
 			}
 		}
@@ -154,6 +168,81 @@ func (c *CronChannel) Start(ctx context.Context) error {
 	return nil
 }
 
+// fire handles a single cron tick. Deliver-mode jobs post their task text
+// straight to the configured channel; every other job wakes a session via
+// onDirectWake.
+func (c *CronChannel) fire(ctx context.Context, job *cronpkg.Job) (string, error) {
+	if job == nil {
+		return "", nil
+	}
+
+	jobID := strings.TrimSpace(job.ID)
+	if jobID == "" {
+		jobID = "job"
+	}
+	target := strings.TrimSpace(job.WakeSession)
+	task := strings.TrimSpace(job.Task)
+
+	if job.Deliver {
+		// Deliver mode: no agent turn — the task text is the message.
+		if c.sender == nil {
+			return "", fmt.Errorf("cron: deliver job %q fired but no sender is configured", jobID)
+		}
+		if err := c.sender.SendTo(ctx, job.Channel, task, job.To); err != nil {
+			return "", fmt.Errorf("cron: deliver job %q to %s: %w", jobID, job.Channel, err)
+		}
+		logger.Info("cron: delivered", "id", jobID, "channel", job.Channel, "to", job.To)
+		return "", nil
+	}
+
+	if c.onDirectWake == nil {
+		// Fallback: push through Messages() channel (legacy, not expected in normal wiring).
+		c.messages <- c.buildMessage(job)
+		return "", nil
+	}
+
+	if job.Heartbeat {
+		// Self-scheduled heartbeat: a silent check-in on the creating session,
+		// woken as a heartbeat so reactions, streaming and footers stay off.
+		if target == "" {
+			logger.Warn("cron: heartbeat job without wake_session, skipping", "id", jobID)
+			return "", nil
+		}
+		delivery := "you were woken by a heartbeat you scheduled with set_heartbeat. Caller is cron — output to caller is dropped. " +
+			"Use dispatch(to=user) only if something needs the user's attention; otherwise end with dispatch({})."
+		c.onDirectWake(target, msg.WakeHeartbeat, task, "", delivery, job.Admin)
+		return "", nil
+	}
+
+	if job.DirectWake {
+		// Inject mode: must have target session; agent is ignored (preserve target's meta).
+		if target == "" {
+			logger.Warn("cron: direct_wake without wake_session, skipping", "id", jobID)
+			return "", nil
+		}
+		delivery := "you were woken by cron (inject mode). Caller is cron — output to caller is dropped. " +
+			"Use dispatch(to=user) to message the channel user, or dispatch(to=session, session_key=...) " +
+			"to forward elsewhere."
+		c.onDirectWake(target, msg.WakeCron, task, "", delivery, job.Admin)
+		return "", nil
+	}
+
+	// Independent mode: run in cron:<jobID> session with configured agent.
+	sessionKey := "cron:" + jobID
+	agent := strings.TrimSpace(job.Agent)
+	var delivery string
+	if target != "" {
+		delivery = "you were woken by cron (independent mode). Caller is cron — output to caller is dropped. " +
+			"After completing your task, dispatch(to=session, session_key=\"" + target + "\") to deliver results."
+	} else {
+		delivery = "you were woken by cron (independent mode). Caller is cron — output to caller is dropped. " +
+			"No delivery target configured; use dispatch explicitly if you need to forward results."
+		logger.Warn("cron: independent mode without wake_session (silent execution)", "id", jobID)
+	}
+	c.onDirectWake(sessionKey, msg.WakeCron, task, agent, delivery, job.Admin)
+	return "", nil
+}
+
 func (c *CronChannel) Stop() error {
 	select {
 	case <-c.done:
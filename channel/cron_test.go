@@ -0,0 +1,95 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+// fakeCronSender records SendTo calls.
+type fakeCronSender struct {
+	calls []fakeSend
+	err   error
+}
+
+type fakeSend struct {
+	channel, text, to string
+}
+
+func (f *fakeCronSender) SendTo(_ context.Context, channelName, text, replyTo string) error {
+	f.calls = append(f.calls, fakeSend{channelName, text, replyTo})
+	return f.err
+}
+
+func TestCronFire_DeliverSkipsAgent(t *testing.T) {
+	sender := &fakeCronSender{}
+	ch := &CronChannel{messages: make(chan *Message, 1)}
+	ch.SetSender(sender)
+	woken := 0
+	ch.SetDirectWake(func(string, msg.WakeSource, string, string, string, bool) { woken++ })
+
+	job := cronpkg.Job{ID: "standup", Task: "Standup in 5 minutes", Deliver: true, Channel: "telegram", To: "123"}
+	if _, err := ch.fire(context.Background(), &job); err != nil {
+		t.Fatalf("fire: %v", err)
+	}
+
+	if woken != 0 {
+		t.Errorf("agent was woken %d times, want 0", woken)
+	}
+	if len(sender.calls) != 1 {
+		t.Fatalf("got %d sends, want 1", len(sender.calls))
+	}
+	got := sender.calls[0]
+	if got.channel != "telegram" || got.to != "123" || got.text != "Standup in 5 minutes" {
+		t.Errorf("unexpected send: %+v", got)
+	}
+}
+
+func TestCronFire_DeliverErrors(t *testing.T) {
+	job := cronpkg.Job{ID: "x", Task: "hi", Deliver: true, Channel: "discord"}
+
+	ch := &CronChannel{}
+	if _, err := ch.fire(context.Background(), &job); err == nil {
+		t.Error("expected error when no sender is configured")
+	}
+
+	ch.SetSender(&fakeCronSender{err: errors.New("boom")})
+	if _, err := ch.fire(context.Background(), &job); err == nil {
+		t.Error("expected send error to propagate")
+	}
+}
+
+func TestCronFire_NonDeliverWakes(t *testing.T) {
+	sender := &fakeCronSender{}
+	ch := &CronChannel{}
+	ch.SetSender(sender)
+	var gotKey, gotAgent string
+	var gotAdmin bool
+	ch.SetDirectWake(func(key string, _ msg.WakeSource, _, agentName, _ string, admin bool) {
+		gotKey, gotAgent, gotAdmin = key, agentName, admin
+	})
+
+	job := cronpkg.Job{ID: "tidy", Task: "tidy up", Agent: "tidyup"}
+	if _, err := ch.fire(context.Background(), &job); err != nil {
+		t.Fatalf("fire: %v", err)
+	}
+	if gotKey != "cron:tidy" || gotAgent != "tidyup" {
+		t.Errorf("wake = (%q, %q), want (cron:tidy, tidyup)", gotKey, gotAgent)
+	}
+	if gotAdmin {
+		t.Error("job without Admin woke an admin turn")
+	}
+	job.Admin = true
+	if _, err := ch.fire(context.Background(), &job); err != nil {
+		t.Fatalf("fire: %v", err)
+	}
+	if !gotAdmin {
+		t.Error("admin job woke a non-admin turn")
+	}
+	if len(sender.calls) != 0 {
+		t.Errorf("sender called %d times for non-deliver job", len(sender.calls))
+	}
+}
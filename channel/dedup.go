@@ -0,0 +1,71 @@
+package channel
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDedupWindow is how long a message ID is remembered when no window
+// is configured.
+const DefaultDedupWindow = 10 * time.Minute
+
+// dedupMaxEntries bounds DedupCache memory regardless of traffic volume.
+const dedupMaxEntries = 10000
+
+// DedupCache remembers recently seen message IDs so redelivered events from
+// at-least-once channels (Feishu, webhooks) are dropped. Entries expire after
+// the window; when full, the oldest entries are evicted first.
+type DedupCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	seen       map[string]time.Time
+	order      []string // insertion order; oldest first
+	now        func() time.Time
+}
+
+// NewDedupCache creates a cache with the given window (<=0 uses DefaultDedupWindow).
+func NewDedupCache(window time.Duration) *DedupCache {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return &DedupCache{
+		window:     window,
+		maxEntries: dedupMaxEntries,
+		seen:       make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// Seen records id and reports whether it was already recorded within the
+// window. Empty IDs are never considered duplicates; a nil cache disables dedup.
+func (c *DedupCache) Seen(id string) bool {
+	if c == nil || id == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictLocked(now)
+	if _, ok := c.seen[id]; ok {
+		return true
+	}
+	c.seen[id] = now
+	c.order = append(c.order, id)
+	return false
+}
+
+// evictLocked drops expired entries and, if still over capacity, the oldest ones.
+func (c *DedupCache) evictLocked(now time.Time) {
+	cutoff := now.Add(-c.window)
+	i := 0
+	for ; i < len(c.order); i++ {
+		id := c.order[i]
+		if ts, ok := c.seen[id]; ok && ts.After(cutoff) && len(c.order)-i < c.maxEntries {
+			break
+		}
+		delete(c.seen, id)
+	}
+	c.order = c.order[i:]
+}
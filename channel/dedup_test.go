@@ -0,0 +1,41 @@
+package channel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupCache_DropsRepeatsWithinWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := NewDedupCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	if c.Seen("a") {
+		t.Fatal("first sighting reported as duplicate")
+	}
+	if !c.Seen("a") {
+		t.Fatal("repeat within window not reported as duplicate")
+	}
+	if c.Seen("b") {
+		t.Fatal("distinct ID reported as duplicate")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if c.Seen("a") {
+		t.Fatal("ID should expire after the window")
+	}
+}
+
+func TestDedupCache_BoundedSize(t *testing.T) {
+	c := NewDedupCache(time.Hour)
+	c.maxEntries = 2
+	c.Seen("a")
+	c.Seen("b")
+	c.Seen("c") // evicts "a"
+	if len(c.seen) > 2 {
+		t.Fatalf("cache holds %d entries, want <= 2", len(c.seen))
+	}
+	if c.Seen("a") {
+		t.Fatal("evicted ID should be accepted again")
+	}
+}
@@ -2,14 +2,17 @@ package channel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/discordmd"
 	"github.com/linanwx/nagobot/logger"
 )
 
@@ -20,14 +23,19 @@ const (
 
 // DiscordChannel implements the Channel interface for Discord.
 type DiscordChannel struct {
-	token         string
-	allowedGuilds map[string]bool // guild ID allowlist, empty = allow all
-	allowedUsers  map[string]bool // user ID allowlist, empty = allow all
-	mediaDir      string // local directory for downloaded media files
-	session       *discordgo.Session
-	messages      chan *Message
-	done          chan struct{}
-	stopOnce      sync.Once
+	token          string
+	mu             sync.RWMutex    // protects allowedGuilds, allowedUsers, requireMention, maxMsgLen and sendRetries
+	allowedGuilds  map[string]bool // guild ID allowlist, empty = allow all
+	allowedUsers   map[string]bool // user ID allowlist, empty = allow all
+	requireMention bool            // guild channels: drop messages that do not @-mention the bot
+	maxMsgLen      int             // characters per outgoing chunk
+	sendRetries    int             // retries per chunk on rate limits and transient errors
+	mediaDir       string          // local directory for downloaded media files
+	session        *discordgo.Session
+	messages       chan *Message
+	backpressure   backpressure
+	done           chan struct{}
+	stopOnce       sync.Once
 }
 
 // NewDiscordChannel creates a new Discord channel from config.
@@ -51,15 +59,39 @@ func NewDiscordChannel(cfg *config.Config) Channel {
 	mediaDir := initMediaDir(cfg)
 
 	return &DiscordChannel{
-		token:         token,
-		allowedGuilds: allowedGuilds,
-		allowedUsers:  allowedUsers,
-		mediaDir:      mediaDir,
-		messages:      make(chan *Message, discordMessageBufferSize),
-		done:          make(chan struct{}),
+		token:          token,
+		allowedGuilds:  allowedGuilds,
+		allowedUsers:   allowedUsers,
+		requireMention: cfg.GetDiscordRequireMention(),
+		maxMsgLen:      MaxMessageLength(cfg, "discord"),
+		sendRetries:    sendRetries(cfg),
+		mediaDir:       mediaDir,
+		messages:       make(chan *Message, discordMessageBufferSize),
+		backpressure:   newBackpressure(cfg, discordMessageBufferSize),
+		done:           make(chan struct{}),
 	}
 }
 
+// Reconfigure updates the guild and user allowlists, the mention
+// requirement, the message length and send retries from a fresh config
+// snapshot.
+func (d *DiscordChannel) Reconfigure(cfg *config.Config) {
+	guilds := make(map[string]bool)
+	for _, id := range cfg.GetDiscordAllowedGuildIDs() {
+		guilds[id] = true
+	}
+	users := make(map[string]bool)
+	for _, id := range cfg.GetDiscordAllowedUserIDs() {
+		users[id] = true
+	}
+	d.mu.Lock()
+	d.allowedGuilds, d.allowedUsers = guilds, users
+	d.requireMention = cfg.GetDiscordRequireMention()
+	d.maxMsgLen = MaxMessageLength(cfg, "discord")
+	d.sendRetries = sendRetries(cfg)
+	d.mu.Unlock()
+}
+
 func (d *DiscordChannel) Name() string { return "discord" }
 
 func (d *DiscordChannel) Start(ctx context.Context) error {
@@ -102,7 +134,7 @@ func (d *DiscordChannel) Stop() error {
 	return nil
 }
 
-func (d *DiscordChannel) Send(_ context.Context, resp *Response) error {
+func (d *DiscordChannel) Send(ctx context.Context, resp *Response) error {
 	if d.session == nil {
 		return fmt.Errorf("discord session not started")
 	}
@@ -111,16 +143,57 @@ func (d *DiscordChannel) Send(_ context.Context, resp *Response) error {
 		return err
 	}
 
-	text := convertTablesToLists(resp.Text)
-	chunks := SplitMessage(text, DiscordMaxMessageLength)
-	for _, chunk := range chunks {
-		if _, err := d.session.ChannelMessageSend(replyTo, chunk); err != nil {
+	text := discordmd.Convert(resp.Text)
+	d.mu.RLock()
+	maxLen := d.maxMsgLen
+	retries := d.sendRetries
+	d.mu.RUnlock()
+	chunks := SplitMessage(text, maxLen)
+	for i, chunk := range chunks {
+		err := sendWithRetry(ctx, "discord", retries, discordRetryAfter, func() error {
+			var err error
+			if i == 0 && resp.ReplyToMessageID != "" {
+				_, err = d.session.ChannelMessageSendReply(replyTo, chunk, discordReplyReference(replyTo, resp.ReplyToMessageID))
+			} else {
+				_, err = d.session.ChannelMessageSend(replyTo, chunk)
+			}
+			return err
+		})
+		if err != nil {
+			// Let the chat know the reply is incomplete rather than stopping silently.
+			_, _ = d.session.ChannelMessageSend(replyTo, sendFailedNotice)
 			return fmt.Errorf("discord send error: %w", err)
 		}
 	}
 	return nil
 }
 
+// discordRetryAfter classifies a REST send error: rate limits wait
+// RetryAfter, 5xx and network errors back off, other HTTP errors are final.
+// discordgo already retries rate limits itself; this covers what it gives up on.
+func discordRetryAfter(err error) (time.Duration, bool) {
+	var rl *discordgo.RateLimitError
+	if errors.As(err, &rl) && rl.RateLimit != nil && rl.TooManyRequests != nil {
+		return rl.RetryAfter, true
+	}
+	var rest *discordgo.RESTError
+	if errors.As(err, &rest) && rest.Response != nil {
+		return 0, rest.Response.StatusCode >= 500
+	}
+	return 0, true
+}
+
+// discordReplyReference references msgID in channelID without failing the
+// send if that message has been deleted.
+func discordReplyReference(channelID, msgID string) *discordgo.MessageReference {
+	failIfNotExists := false
+	return &discordgo.MessageReference{
+		MessageID:       msgID,
+		ChannelID:       channelID,
+		FailIfNotExists: &failIfNotExists,
+	}
+}
+
 // resolveTarget resolves a "dm:{userID}" target to a real DM channel ID.
 // Plain channel IDs pass through unchanged.
 func (d *DiscordChannel) resolveTarget(target string) (string, error) {
@@ -163,141 +236,43 @@ func (d *DiscordChannel) SendImage(_ context.Context, replyTo string, ref ImageR
 	return nil
 }
 
-// Compile-time check: DiscordChannel implements ImageSender.
-var _ ImageSender = (*DiscordChannel)(nil)
-
-// convertTablesToLists converts Markdown tables into numbered list format
-// because Discord's table rendering is poor (misaligned, broken on mobile).
-func convertTablesToLists(text string) string {
-	lines := strings.Split(text, "\n")
-	var result []string
-	inCodeBlock := false
-
-	i := 0
-	for i < len(lines) {
-		line := lines[i]
-		trimmed := strings.TrimSpace(line)
-
-		// Track code blocks — don't touch tables inside them.
-		if strings.HasPrefix(trimmed, "```") {
-			inCodeBlock = !inCodeBlock
-			result = append(result, line)
-			i++
-			continue
-		}
-		if inCodeBlock {
-			result = append(result, line)
-			i++
-			continue
-		}
-
-		// Detect table block: consecutive lines starting with |
-		if strings.HasPrefix(trimmed, "|") {
-			tableStart := i
-			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
-				i++
-			}
-			tableLines := lines[tableStart:i]
-			result = append(result, renderTableAsList(tableLines)...)
-			continue
-		}
-
-		result = append(result, line)
-		i++
-	}
-
-	return strings.Join(result, "\n")
-}
-
-// renderTableAsList converts parsed table lines into a numbered list.
-func renderTableAsList(tableLines []string) []string {
-	var headers []string
-	var dataRows [][]string
-
-	for _, line := range tableLines {
-		cells := parseTableRow(line)
-		if cells == nil {
-			continue
-		}
-		// Skip separator rows (|---|---|)
-		if isSeparatorRow(cells) {
-			continue
-		}
-		if headers == nil {
-			headers = cells
-		} else {
-			dataRows = append(dataRows, cells)
-		}
-	}
-
-	if headers == nil {
-		return tableLines // can't parse, return as-is
+// SendFile uploads path as a Discord attachment with caption as the message
+// text. Target convention matches Send.
+func (d *DiscordChannel) SendFile(_ context.Context, to, path, caption string) error {
+	if d.session == nil {
+		return fmt.Errorf("discord session not started")
 	}
-
-	// Normalize column count.
-	numCols := len(headers)
-	for _, row := range dataRows {
-		if len(row) > numCols {
-			numCols = len(row)
-		}
+	target, err := d.resolveTarget(to)
+	if err != nil {
+		return err
 	}
-	for len(headers) < numCols {
-		headers = append(headers, "")
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file %s: %w", path, err)
 	}
+	defer f.Close()
 
-	rowLabelCol := headers[0] == ""
-	var out []string
-	for i, row := range dataRows {
-		if rowLabelCol && len(row) > 0 && row[0] != "" {
-			out = append(out, fmt.Sprintf("**%d. %s**", i+1, row[0]))
-		} else {
-			out = append(out, fmt.Sprintf("**%d.**", i+1))
-		}
-		startCol := 0
-		if rowLabelCol {
-			startCol = 1
-		}
-		for j := startCol; j < numCols && j < len(row); j++ {
-			h := headers[j]
-			if h == "" {
-				h = fmt.Sprintf("Column %d", j+1)
-			}
-			out = append(out, fmt.Sprintf("• **%s**: %s", h, row[j]))
-		}
-		if i < len(dataRows)-1 {
-			out = append(out, "")
-		}
+	_, err = d.session.ChannelMessageSendComplex(target, &discordgo.MessageSend{
+		Content: caption,
+		Files: []*discordgo.File{{
+			Name:   filepath.Base(path),
+			Reader: f,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("discord file send: %w", err)
 	}
-	return out
+	return nil
 }
 
-// parseTableRow splits a |-delimited row into trimmed cell values.
-func parseTableRow(line string) []string {
-	line = strings.TrimSpace(line)
-	if !strings.HasPrefix(line, "|") {
-		return nil
-	}
-	// Trim leading and trailing |
-	line = strings.TrimPrefix(line, "|")
-	line = strings.TrimSuffix(line, "|")
-	parts := strings.Split(line, "|")
-	cells := make([]string, len(parts))
-	for i, p := range parts {
-		cells[i] = strings.TrimSpace(p)
-	}
-	return cells
-}
+// MaxFileSize returns Discord's attachment limit.
+func (d *DiscordChannel) MaxFileSize() int64 { return discordMaxFileSize }
 
-// isSeparatorRow checks if all cells look like |---|
-func isSeparatorRow(cells []string) bool {
-	for _, c := range cells {
-		cleaned := strings.Trim(c, "-: ")
-		if cleaned != "" {
-			return false
-		}
-	}
-	return true
-}
+// Compile-time checks: DiscordChannel implements ImageSender and FileSender.
+var (
+	_ ImageSender = (*DiscordChannel)(nil)
+	_ FileSender  = (*DiscordChannel)(nil)
+)
 
 func (d *DiscordChannel) Messages() <-chan *Message {
 	return d.messages
@@ -322,16 +297,23 @@ func (d *DiscordChannel) handleMessageCreate(s *discordgo.Session, m *discordgo.
 		return
 	}
 
+	d.mu.RLock()
+	allowedGuilds, allowedUsers, requireMention := d.allowedGuilds, d.allowedUsers, d.requireMention
+	d.mu.RUnlock()
 	// Guild allowlist check.
-	if m.GuildID != "" && len(d.allowedGuilds) > 0 && !d.allowedGuilds[m.GuildID] {
+	if m.GuildID != "" && len(allowedGuilds) > 0 && !allowedGuilds[m.GuildID] {
 		return
 	}
 	// User allowlist check.
-	if len(d.allowedUsers) > 0 && !d.allowedUsers[m.Author.ID] {
+	if len(allowedUsers) > 0 && !allowedUsers[m.Author.ID] {
 		return
 	}
 
-	text := m.Content
+	// Guild mention filter: DMs are always answered.
+	text, mentioned := stripDiscordBotMention(m.Content, m.Mentions, s.State.User.ID)
+	if m.GuildID != "" && requireMention && !mentioned {
+		return
+	}
 
 	// Resolve user mentions from <@userid> to @displayname.
 	for _, u := range m.Mentions {
@@ -440,12 +422,25 @@ func (d *DiscordChannel) handleMessageCreate(s *discordgo.Session, m *discordgo.
 		}
 	}
 
-	select {
-	case d.messages <- msg:
-	case <-d.done:
-	default:
-		logger.Warn("discord message buffer full, dropping message")
+	d.backpressure.enqueue(d, d.messages, d.done, msg)
+}
+
+// stripDiscordBotMention removes the bot's own <@id> mention from text and
+// reports whether the bot was mentioned.
+func stripDiscordBotMention(text string, mentions []*discordgo.User, botID string) (string, bool) {
+	mentioned := false
+	for _, u := range mentions {
+		if u != nil && u.ID == botID {
+			mentioned = true
+			break
+		}
+	}
+	if !mentioned {
+		return text, false
 	}
+	text = strings.ReplaceAll(text, "<@"+botID+">", "")
+	text = strings.ReplaceAll(text, "<@!"+botID+">", "")
+	return strings.TrimSpace(text), true
 }
 
 // threadContext fetches the current channel (and its parent if the channel is a
@@ -0,0 +1,97 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+// Discord embed limits, in characters.
+const (
+	discordEmbedTitleMax       = 256
+	discordEmbedDescriptionMax = 4096
+	discordEmbedFieldsMax      = 25
+	discordEmbedFieldNameMax   = 256
+	discordEmbedFieldValueMax  = 1024
+	discordEmbedTotalMax       = 6000 // all text in one embed combined
+)
+
+// SendEmbed renders embed as one or more Discord embeds, one per message so
+// each stays within the per-message size limit.
+func (d *DiscordChannel) SendEmbed(_ context.Context, to string, embed msg.Embed) error {
+	if d.session == nil {
+		return fmt.Errorf("discord session not started")
+	}
+	target, err := d.resolveTarget(to)
+	if err != nil {
+		return err
+	}
+	for _, e := range discordEmbeds(embed) {
+		if _, err := d.session.ChannelMessageSendEmbed(target, e); err != nil {
+			return fmt.Errorf("discord embed send error: %w", err)
+		}
+	}
+	return nil
+}
+
+var _ EmbedSender = (*DiscordChannel)(nil)
+
+// discordEmbeds converts embed into Discord embeds within the platform
+// limits. An over-long description or more than 25 fields continue in
+// follow-up embeds that repeat the color but not the title; over-long field
+// names and values are truncated.
+func discordEmbeds(embed msg.Embed) []*discordgo.MessageEmbed {
+	var out []*discordgo.MessageEmbed
+	title := truncateRunes(strings.TrimSpace(embed.Title), discordEmbedTitleMax)
+	cur := &discordgo.MessageEmbed{Title: title, Color: embed.Color}
+	size := utf8.RuneCountInString(title)
+	next := func() {
+		out = append(out, cur)
+		cur = &discordgo.MessageEmbed{Color: embed.Color}
+		size = 0
+	}
+
+	if desc := strings.TrimSpace(embed.Description); desc != "" {
+		for _, chunk := range SplitMessage(desc, discordEmbedDescriptionMax) {
+			n := utf8.RuneCountInString(chunk)
+			if cur.Description != "" || size+n > discordEmbedTotalMax {
+				next()
+			}
+			cur.Description = chunk
+			size += n
+		}
+	}
+
+	for _, f := range embed.Fields {
+		name := truncateRunes(strings.TrimSpace(f.Name), discordEmbedFieldNameMax)
+		value := truncateRunes(strings.TrimSpace(f.Value), discordEmbedFieldValueMax)
+		// Discord rejects empty field names and values.
+		if name == "" {
+			name = "\u200b"
+		}
+		if value == "" {
+			value = "\u200b"
+		}
+		n := utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+		if len(cur.Fields) == discordEmbedFieldsMax || size+n > discordEmbedTotalMax {
+			next()
+		}
+		cur.Fields = append(cur.Fields, &discordgo.MessageEmbedField{Name: name, Value: value, Inline: f.Inline})
+		size += n
+	}
+
+	return append(out, cur)
+}
+
+// truncateRunes shortens s to at most limit runes, marking the cut with "…".
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:limit-1]) + "…"
+}
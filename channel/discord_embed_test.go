@@ -0,0 +1,72 @@
+package channel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+func TestDiscordEmbedsFromTitleAndFields(t *testing.T) {
+	embeds := discordEmbeds(msg.Embed{
+		Title:       "Daily report",
+		Description: "All systems nominal.",
+		Color:       0x2ecc71,
+		Fields: []msg.EmbedField{
+			{Name: "Uptime", Value: "99.9%", Inline: true},
+			{Name: "Errors", Value: "", Inline: true},
+		},
+	})
+	if len(embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(embeds))
+	}
+	e := embeds[0]
+	if e.Title != "Daily report" || e.Description != "All systems nominal." || e.Color != 0x2ecc71 {
+		t.Errorf("embed = %+v", e)
+	}
+	if len(e.Fields) != 2 || e.Fields[0].Name != "Uptime" || e.Fields[0].Value != "99.9%" || !e.Fields[0].Inline {
+		t.Fatalf("fields = %+v", e.Fields)
+	}
+	if e.Fields[1].Value == "" {
+		t.Error("empty field value was not replaced; Discord rejects it")
+	}
+}
+
+func TestDiscordEmbedsSplitWithinLimits(t *testing.T) {
+	var fields []msg.EmbedField
+	for i := 0; i < 30; i++ {
+		fields = append(fields, msg.EmbedField{Name: fmt.Sprintf("f%d", i), Value: strings.Repeat("v", 2000)})
+	}
+	embeds := discordEmbeds(msg.Embed{Title: "Big", Description: strings.Repeat("d", 5000), Fields: fields})
+	if len(embeds) < 2 {
+		t.Fatalf("got %d embeds, want the report split", len(embeds))
+	}
+	if embeds[0].Title != "Big" {
+		t.Errorf("first embed title = %q, want Big", embeds[0].Title)
+	}
+	total := 0
+	for i, e := range embeds {
+		size := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+		if utf8.RuneCountInString(e.Description) > discordEmbedDescriptionMax {
+			t.Errorf("embed %d description exceeds limit", i)
+		}
+		if len(e.Fields) > discordEmbedFieldsMax {
+			t.Errorf("embed %d has %d fields", i, len(e.Fields))
+		}
+		for _, f := range e.Fields {
+			if utf8.RuneCountInString(f.Value) > discordEmbedFieldValueMax {
+				t.Errorf("embed %d field %s value exceeds limit", i, f.Name)
+			}
+			size += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+		}
+		if size > discordEmbedTotalMax {
+			t.Errorf("embed %d is %d characters, over %d", i, size, discordEmbedTotalMax)
+		}
+		total += len(e.Fields)
+	}
+	if total != 30 {
+		t.Errorf("fields across embeds = %d, want 30", total)
+	}
+}
@@ -0,0 +1,27 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+// EmbedSender is the optional capability that lets a channel render a
+// structured reply natively. Target convention matches Send's ReplyTo.
+type EmbedSender interface {
+	SendEmbed(ctx context.Context, to string, embed msg.Embed) error
+}
+
+// SendEmbed delivers embed to a chat on the named channel: natively when the
+// channel implements EmbedSender, otherwise as Markdown text via Send.
+func (m *Manager) SendEmbed(ctx context.Context, channelName, to string, embed msg.Embed) error {
+	ch, ok := m.Get(channelName)
+	if !ok {
+		return fmt.Errorf("channel not found: %s", channelName)
+	}
+	if sender, ok := ch.(EmbedSender); ok {
+		return sender.SendEmbed(ctx, to, m.redactEmbed(channelName, embed))
+	}
+	return m.SendResponse(ctx, channelName, &Response{Text: embed.Markdown(), ReplyTo: to})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -21,27 +23,33 @@ import (
 const (
 	feishuMessageBufferSize = 100
 	feishuMaxMessageLength  = 4000
-	feishuDedupTTL          = 5 * time.Minute
 )
 
 // FeishuChannel implements the Channel interface for Feishu (Lark)
 // using the official SDK's WebSocket long connection (no public URL needed).
 type FeishuChannel struct {
 	appID, appSecret string
+	mu               sync.RWMutex    // protects allowedOpenIDs, requireMention, maxMsgLen and botOpenID
 	allowedOpenIDs   map[string]bool // nil or empty = allow all
+	requireMention   bool            // group chats: drop messages that do not @-mention the bot
+	maxMsgLen        int             // characters per outgoing chunk
+	botOpenID        string          // the bot's own open_id, resolved in the background after Start; empty = unknown
 
 	apiClient *lark.Client   // REST client for sending messages
 	wsClient  *larkws.Client // WebSocket client for receiving events
 
-	messages  chan *Message
-	done      chan struct{}
-	cancel    context.CancelFunc
-	startDone chan struct{} // closed when wsClient.Start() returns
-	wg        sync.WaitGroup
+	mediaDir      string                // local directory for downloaded media files
+	fetchResource feishuResourceFetcher // nil = Lark SDK; tests inject a fake
 
-	// Event dedup: Feishu may deliver duplicate events.
-	seenMu   sync.Mutex
-	seen     map[string]time.Time
+	messages     chan *Message
+	backpressure backpressure
+	done         chan struct{}
+	cancel       context.CancelFunc
+	startDone    chan struct{} // closed when wsClient.Start() returns
+	wg           sync.WaitGroup
+
+	// Event dedup: Feishu may redeliver the same event or message.
+	dedup    *DedupCache
 	stopOnce sync.Once
 }
 
@@ -64,10 +72,28 @@ func NewFeishuChannel(cfg *config.Config) Channel {
 		appID:          appID,
 		appSecret:      appSecret,
 		allowedOpenIDs: allowedOpenIDs,
+		requireMention: cfg.GetFeishuRequireMention(),
+		maxMsgLen:      MaxMessageLength(cfg, "feishu"),
+		mediaDir:       initMediaDir(cfg),
 		messages:       make(chan *Message, feishuMessageBufferSize),
+		backpressure:   newBackpressure(cfg, feishuMessageBufferSize),
 		done:           make(chan struct{}),
-		seen:           make(map[string]time.Time),
+		dedup:          NewDedupCache(cfg.GetChannelDedupWindow()),
+	}
+}
+
+// Reconfigure updates the sender allowlist, mention requirement and message
+// length from a fresh config snapshot.
+func (f *FeishuChannel) Reconfigure(cfg *config.Config) {
+	allowed := make(map[string]bool)
+	for _, id := range cfg.GetFeishuAllowedOpenIDs() {
+		allowed[id] = true
 	}
+	f.mu.Lock()
+	f.allowedOpenIDs = allowed
+	f.requireMention = cfg.GetFeishuRequireMention()
+	f.maxMsgLen = MaxMessageLength(cfg, "feishu")
+	f.mu.Unlock()
 }
 
 // Name returns the channel name.
@@ -98,32 +124,24 @@ func (f *FeishuChannel) Start(ctx context.Context) error {
 	f.cancel = cancel
 	f.startDone = make(chan struct{})
 
+	// Resolve the bot's open_id in the background so a slow lookup does not
+	// hold up Start; until it lands, mentions are left in the text.
 	f.wg.Add(1)
 	go func() {
 		defer f.wg.Done()
-		defer close(f.startDone)
-		if err := f.wsClient.Start(startCtx); err != nil {
-			select {
-			case <-f.done:
-				// Normal shutdown — ignore error.
-			default:
-				logger.Error("feishu websocket error", "err", err)
-			}
-		}
+		f.resolveBotOpenID(startCtx)
 	}()
 
-	// Periodic dedup cache cleanup.
 	f.wg.Add(1)
 	go func() {
 		defer f.wg.Done()
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
-		for {
+		defer close(f.startDone)
+		if err := f.wsClient.Start(startCtx); err != nil {
 			select {
 			case <-f.done:
-				return
-			case <-ticker.C:
-				f.cleanupSeen()
+				// Normal shutdown — ignore error.
+			default:
+				logger.Error("feishu websocket error", "err", err)
 			}
 		}
 	}()
@@ -160,46 +178,180 @@ func (f *FeishuChannel) Send(ctx context.Context, resp *Response) error {
 		return fmt.Errorf("feishu api client not started")
 	}
 
-	chunks := SplitMessage(resp.Text, feishuMaxMessageLength)
-	for _, chunk := range chunks {
-		var receiveIDType, receiveID string
-		replyTo := resp.ReplyTo
-		if strings.HasPrefix(replyTo, "p2p:") {
-			receiveIDType = "open_id"
-			receiveID = strings.TrimPrefix(replyTo, "p2p:")
-		} else if strings.HasPrefix(replyTo, "group:") {
-			receiveIDType = "chat_id"
-			receiveID = strings.TrimPrefix(replyTo, "group:")
+	f.mu.RLock()
+	maxLen := f.maxMsgLen
+	f.mu.RUnlock()
+	chunks := SplitMessage(resp.Text, maxLen)
+	for i, chunk := range chunks {
+		content, _ := json.Marshal(map[string]string{"text": chunk})
+		var err error
+		if i == 0 && resp.ReplyToMessageID != "" {
+			err = f.replyMessage(ctx, resp.ReplyToMessageID, "text", string(content))
 		} else {
-			// Fallback: treat as open_id.
-			receiveIDType = "open_id"
-			receiveID = replyTo
+			err = f.createMessage(ctx, resp.ReplyTo, "text", string(content))
+		}
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		content, _ := json.Marshal(map[string]string{"text": chunk})
-		req := larkim.NewCreateMessageReqBuilder().
-			ReceiveIdType(receiveIDType).
-			Body(larkim.NewCreateMessageReqBodyBuilder().
-				ReceiveId(receiveID).
-				MsgType("text").
-				Content(string(content)).
-				Build()).
-			Build()
-
-		result, err := f.apiClient.Im.Message.Create(ctx, req)
+// replyMessage posts one message of msgType as a reply to messageID, in the
+// chat that message belongs to.
+func (f *FeishuChannel) replyMessage(ctx context.Context, messageID, msgType, content string) error {
+	req := larkim.NewReplyMessageReqBuilder().
+		MessageId(messageID).
+		Body(larkim.NewReplyMessageReqBodyBuilder().
+			MsgType(msgType).
+			Content(content).
+			Build()).
+		Build()
+
+	result, err := f.apiClient.Im.Message.Reply(ctx, req)
+	if err != nil {
+		logger.Error("feishu reply error", "err", err, "messageID", messageID)
+		return fmt.Errorf("feishu reply error: %w", err)
+	}
+	if !result.Success() {
+		logger.Error("feishu reply failed", "code", result.Code, "msg", result.Msg, "messageID", messageID)
+		return fmt.Errorf("feishu reply failed: code=%d msg=%s", result.Code, result.Msg)
+	}
+	logger.Info("feishu reply sent", "msgType", msgType, "messageID", messageID)
+	return nil
+}
+
+// feishuReceiveID maps a replyTo target ("p2p:{openID}", "group:{chatID}",
+// or a bare open_id) to the receive_id_type and receive_id pair.
+func feishuReceiveID(replyTo string) (receiveIDType, receiveID string) {
+	if id, ok := strings.CutPrefix(replyTo, "p2p:"); ok {
+		return "open_id", id
+	}
+	if id, ok := strings.CutPrefix(replyTo, "group:"); ok {
+		return "chat_id", id
+	}
+	// Fallback: treat as open_id.
+	return "open_id", replyTo
+}
+
+// createMessage sends one message of msgType with JSON content to replyTo.
+func (f *FeishuChannel) createMessage(ctx context.Context, replyTo, msgType, content string) error {
+	receiveIDType, receiveID := feishuReceiveID(replyTo)
+	req := larkim.NewCreateMessageReqBuilder().
+		ReceiveIdType(receiveIDType).
+		Body(larkim.NewCreateMessageReqBodyBuilder().
+			ReceiveId(receiveID).
+			MsgType(msgType).
+			Content(content).
+			Build()).
+		Build()
+
+	result, err := f.apiClient.Im.Message.Create(ctx, req)
+	if err != nil {
+		logger.Error("feishu send error", "err", err, "receiveIDType", receiveIDType, "receiveID", receiveID)
+		return fmt.Errorf("feishu send error: %w", err)
+	}
+	if !result.Success() {
+		logger.Error("feishu send failed", "code", result.Code, "msg", result.Msg, "receiveIDType", receiveIDType, "receiveID", receiveID)
+		return fmt.Errorf("feishu send failed: code=%d msg=%s", result.Code, result.Msg)
+	}
+	logger.Info("feishu message sent", "msgType", msgType, "receiveIDType", receiveIDType, "receiveID", receiveID)
+	return nil
+}
+
+// SendFile uploads path and sends it to replyTo. Images small enough for the
+// image API are sent inline; everything else goes as a file message. Feishu
+// messages carry no caption, so a non-empty caption follows as text.
+func (f *FeishuChannel) SendFile(ctx context.Context, to, path, caption string) error {
+	if f.apiClient == nil {
+		return fmt.Errorf("feishu api client not started")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var msgType string
+	var content []byte
+	if _, isImage := detectImageFile(path); isImage && info.Size() <= feishuMaxImageSize {
+		key, err := f.uploadImage(ctx, path)
 		if err != nil {
-			logger.Error("feishu send error", "err", err, "receiveIDType", receiveIDType, "receiveID", receiveID)
-			return fmt.Errorf("feishu send error: %w", err)
+			return err
 		}
-		if !result.Success() {
-			logger.Error("feishu send failed", "code", result.Code, "msg", result.Msg, "receiveIDType", receiveIDType, "receiveID", receiveID)
-			return fmt.Errorf("feishu send failed: code=%d msg=%s", result.Code, result.Msg)
+		msgType = "image"
+		content, _ = json.Marshal(feishuImageContent{ImageKey: key})
+	} else {
+		key, err := f.uploadFile(ctx, path)
+		if err != nil {
+			return err
 		}
-		logger.Info("feishu message sent", "receiveIDType", receiveIDType, "receiveID", receiveID)
+		msgType = "file"
+		content, _ = json.Marshal(feishuFileContent{FileKey: key, FileName: filepath.Base(path)})
+	}
+	if err := f.createMessage(ctx, to, msgType, string(content)); err != nil {
+		return err
+	}
+
+	if caption = strings.TrimSpace(caption); caption != "" {
+		text, _ := json.Marshal(feishuTextContent{Text: caption})
+		return f.createMessage(ctx, to, "text", string(text))
 	}
 	return nil
 }
 
+// MaxFileSize returns the im/v1/files upload limit.
+func (f *FeishuChannel) MaxFileSize() int64 { return feishuMaxFileSize }
+
+func (f *FeishuChannel) uploadImage(ctx context.Context, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	req := larkim.NewCreateImageReqBuilder().
+		Body(larkim.NewCreateImageReqBodyBuilder().
+			ImageType(larkim.ImageTypeMessage).
+			Image(file).
+			Build()).
+		Build()
+	result, err := f.apiClient.Im.Image.Create(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("feishu image upload: %w", err)
+	}
+	if !result.Success() || result.Data == nil || result.Data.ImageKey == nil {
+		return "", fmt.Errorf("feishu image upload failed: code=%d msg=%s", result.Code, result.Msg)
+	}
+	return *result.Data.ImageKey, nil
+}
+
+func (f *FeishuChannel) uploadFile(ctx context.Context, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	req := larkim.NewCreateFileReqBuilder().
+		Body(larkim.NewCreateFileReqBodyBuilder().
+			FileType(larkim.FileTypeStream).
+			FileName(filepath.Base(path)).
+			File(file).
+			Build()).
+		Build()
+	result, err := f.apiClient.Im.File.Create(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("feishu file upload: %w", err)
+	}
+	if !result.Success() || result.Data == nil || result.Data.FileKey == nil {
+		return "", fmt.Errorf("feishu file upload failed: code=%d msg=%s", result.Code, result.Msg)
+	}
+	return *result.Data.FileKey, nil
+}
+
+// Compile-time check: FeishuChannel implements FileSender.
+var _ FileSender = (*FeishuChannel)(nil)
+
 // Messages returns the incoming message channel.
 func (f *FeishuChannel) Messages() <-chan *Message {
 	return f.messages
@@ -257,12 +409,14 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 		return
 	}
 
-	// Event dedup.
+	// Dedup: retries reuse the event ID, but redelivered messages may
+	// arrive under a new event, so check the message ID as well.
 	eventID := ""
 	if event.EventV2Base != nil && event.EventV2Base.Header != nil {
 		eventID = event.EventV2Base.Header.EventID
 	}
-	if eventID != "" && !f.markSeen(eventID) {
+	if (eventID != "" && f.dedup.Seen("event:"+eventID)) || f.dedup.Seen("msg:"+messageID) {
+		logger.Debug("feishu dropping duplicate event", "eventID", eventID, "messageID", messageID)
 		return
 	}
 
@@ -270,6 +424,7 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 	content := derefStr(msg.Content)
 
 	var text string
+	var media *feishuPendingMedia
 	metadata := map[string]string{}
 
 	switch msgType {
@@ -287,6 +442,7 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 			return
 		}
 		metadata["media_summary"] = MediaSummary("image", "image_key", c.ImageKey)
+		media = &feishuPendingMedia{kind: "image", resType: "image", key: c.ImageKey, pathKey: "image_path"}
 		text = "[Image received]"
 	case "file":
 		var c feishuFileContent
@@ -296,6 +452,8 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 		}
 		metadata["media_summary"] = MediaSummary("file",
 			"file_key", c.FileKey, "file_name", c.FileName)
+		media = &feishuPendingMedia{kind: "file", resType: "file", key: c.FileKey, pathKey: "file_path",
+			fileName: c.FileName, extra: []string{"file_name", c.FileName}}
 		if c.FileName != "" {
 			text = fmt.Sprintf("[File: %s]", c.FileName)
 		} else {
@@ -310,6 +468,8 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 		metadata["media_summary"] = MediaSummary("video",
 			"file_key", c.FileKey, "file_name", c.FileName,
 			"duration", fmtSeconds(c.Duration))
+		media = &feishuPendingMedia{kind: "video", resType: "file", key: c.FileKey, pathKey: "file_path",
+			fileName: c.FileName, extra: []string{"file_name", c.FileName, "duration", fmtSeconds(c.Duration)}}
 		text = "[Video received]"
 	case "audio":
 		var c feishuAudioContent
@@ -319,6 +479,8 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 		}
 		metadata["media_summary"] = MediaSummary("audio",
 			"file_key", c.FileKey, "duration", fmtSeconds(c.Duration))
+		media = &feishuPendingMedia{kind: "audio", resType: "file", key: c.FileKey, pathKey: "audio_path",
+			fileName: "audio.opus", extra: []string{"duration", fmtSeconds(c.Duration)}}
 		text = "[Audio received]"
 	case "sticker":
 		var c feishuStickerContent
@@ -338,7 +500,10 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 	}
 
 	// Sender allowlist check.
-	if len(f.allowedOpenIDs) > 0 && !f.allowedOpenIDs[openID] {
+	f.mu.RLock()
+	allowed, requireMention, botOpenID := f.allowedOpenIDs, f.requireMention, f.botOpenID
+	f.mu.RUnlock()
+	if len(allowed) > 0 && !allowed[openID] {
 		logger.Warn("feishu message from unauthorized user", "openID", openID)
 		return
 	}
@@ -346,6 +511,19 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 	chatID := derefStr(msg.ChatId)
 	chatType := derefStr(msg.ChatType) // "p2p" or "group"
 
+	// Group mention filter: p2p chats are always answered.
+	if chatType == "group" && requireMention {
+		var mentioned bool
+		text, mentioned = stripFeishuMentions(text, msg.Mentions, botOpenID)
+		if !mentioned {
+			logger.Debug("feishu ignoring group message without bot mention", "chatID", chatID, "messageID", messageID)
+			return
+		}
+		if text == "" {
+			return
+		}
+	}
+
 	var replyTarget string
 	var channelID string
 	if chatType == "group" {
@@ -356,6 +534,13 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 		channelID = "feishu:" + openID
 	}
 
+	// Download only after the filters so dropped messages cost no fetch.
+	if media != nil {
+		if localPath := f.downloadFeishuResource(messageID, *media); localPath != "" {
+			metadata["media_summary"] = MediaSummary(media.kind, append([]string{media.pathKey, localPath}, media.extra...)...)
+		}
+	}
+
 	metadata["chat_id"] = replyTarget
 	metadata["chat_type"] = chatType
 	metadata["message_id"] = messageID
@@ -369,35 +554,73 @@ func (f *FeishuChannel) processMessageEvent(event *larkim.P2MessageReceiveV1) {
 		Metadata:  metadata,
 	}
 
-	select {
-	case f.messages <- m:
-	case <-f.done:
-	default:
-		logger.Warn("feishu message buffer full, dropping message")
-	}
+	f.backpressure.enqueue(f, f.messages, f.done, m)
 }
 
-// markSeen returns true if the eventID is new (first time seen), false if duplicate.
-func (f *FeishuChannel) markSeen(eventID string) bool {
-	f.seenMu.Lock()
-	defer f.seenMu.Unlock()
-	if _, exists := f.seen[eventID]; exists {
-		return false
+// resolveBotOpenID looks up the bot's own open_id so group mentions of the
+// bot can be told apart from mentions of other members. Best effort: on
+// failure any mention counts as a bot mention and none are stripped.
+func (f *FeishuChannel) resolveBotOpenID(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := f.apiClient.Get(ctx, "/open-apis/bot/v3/info", nil, larkcore.AccessTokenTypeTenant)
+	if err != nil {
+		logger.Warn("feishu bot info lookup failed", "err", err)
+		return
+	}
+	var info struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+		Bot  struct {
+			OpenID string `json:"open_id"`
+		} `json:"bot"`
 	}
-	f.seen[eventID] = time.Now()
-	return true
+	if err := json.Unmarshal(resp.RawBody, &info); err != nil || info.Code != 0 || info.Bot.OpenID == "" {
+		logger.Warn("feishu bot info lookup failed", "code", info.Code, "msg", info.Msg, "err", err)
+		return
+	}
+	f.mu.Lock()
+	f.botOpenID = info.Bot.OpenID
+	f.mu.Unlock()
 }
 
-// cleanupSeen removes expired entries from the dedup cache.
-func (f *FeishuChannel) cleanupSeen() {
-	f.seenMu.Lock()
-	defer f.seenMu.Unlock()
-	cutoff := time.Now().Add(-feishuDedupTTL)
-	for id, t := range f.seen {
-		if t.Before(cutoff) {
-			delete(f.seen, id)
+// stripFeishuMentions replaces the "@_user_N" placeholders in text with the
+// mentioned names, removing the bot's own mention, and reports whether the
+// bot was mentioned. With an unknown botOpenID the text is returned as is
+// and any mention counts as the bot.
+func stripFeishuMentions(text string, mentions []*larkim.MentionEvent, botOpenID string) (string, bool) {
+	if botOpenID == "" {
+		for _, m := range mentions {
+			if m != nil {
+				return text, true
+			}
+		}
+		return text, false
+	}
+	mentioned := false
+	for _, m := range mentions {
+		if m == nil {
+			continue
+		}
+		openID := ""
+		if m.Id != nil {
+			openID = derefStr(m.Id.OpenId)
+		}
+		isBot := openID == botOpenID
+		mentioned = mentioned || isBot
+
+		key := derefStr(m.Key)
+		if key == "" {
+			continue
+		}
+		if isBot {
+			text = strings.ReplaceAll(text, key, "")
+		} else {
+			text = strings.ReplaceAll(text, key, "@"+derefStr(m.Name))
 		}
 	}
+	return strings.TrimSpace(text), mentioned
 }
 
 // derefStr safely dereferences a *string pointer.
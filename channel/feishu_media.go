@@ -0,0 +1,101 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+const (
+	feishuMediaTimeout = 30 * time.Second
+	feishuMaxMediaSize = 20 << 20 // 20 MB
+)
+
+// feishuResourceFetcher fetches a message resource (image or file) by key,
+// returning its bytes and the file name the server reports, if any.
+type feishuResourceFetcher func(ctx context.Context, messageID, key, resType string) (io.Reader, string, error)
+
+// feishuPendingMedia is a resource to download once the message has passed
+// the allowlist and mention filters.
+type feishuPendingMedia struct {
+	kind     string   // MediaSummary media type
+	resType  string   // "image" or "file" (video and audio are files)
+	key      string   // image_key or file_key
+	pathKey  string   // summary key for the local path
+	fileName string   // name hint for the extension
+	extra    []string // summary key/value pairs kept alongside the path
+}
+
+// fetchMessageResource downloads a resource with the Lark SDK, or with the
+// injected fetcher when set.
+func (f *FeishuChannel) fetchMessageResource(ctx context.Context, messageID, key, resType string) (io.Reader, string, error) {
+	if f.fetchResource != nil {
+		return f.fetchResource(ctx, messageID, key, resType)
+	}
+	if f.apiClient == nil {
+		return nil, "", fmt.Errorf("feishu client not started")
+	}
+	req := larkim.NewGetMessageResourceReqBuilder().
+		MessageId(messageID).
+		FileKey(key).
+		Type(resType).
+		Build()
+	resp, err := f.apiClient.Im.MessageResource.Get(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	if !resp.Success() {
+		return nil, "", fmt.Errorf("code %d: %s", resp.Code, resp.Msg)
+	}
+	return resp.File, resp.FileName, nil
+}
+
+// downloadFeishuResource saves a message resource to the media directory,
+// returning the absolute local path. A resource key downloaded before is
+// served from disk. Returns empty string on error (caller keeps the key-only
+// placeholder).
+func (f *FeishuChannel) downloadFeishuResource(messageID string, p feishuPendingMedia) string {
+	if p.key == "" {
+		return ""
+	}
+	return cachedMedia(f.mediaDir, "feishu:"+p.key, func(out *os.File) (string, string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), feishuMediaTimeout)
+		defer cancel()
+
+		r, serverName, err := f.fetchMessageResource(ctx, messageID, p.key, p.resType)
+		if err != nil {
+			logger.Warn("feishu: failed to download media", "messageID", messageID, "type", p.resType, "err", err)
+			return "", "", err
+		}
+		data, err := io.ReadAll(io.LimitReader(r, feishuMaxMediaSize+1))
+		if err != nil {
+			logger.Warn("feishu: failed to read media", "messageID", messageID, "err", err)
+			return "", "", err
+		}
+		if len(data) > feishuMaxMediaSize {
+			logger.Warn("feishu: media exceeds size limit", "messageID", messageID, "limitMB", feishuMaxMediaSize>>20)
+			return "", "", fmt.Errorf("media exceeds %d MB", feishuMaxMediaSize>>20)
+		}
+
+		ext := strings.ToLower(filepath.Ext(serverName))
+		if ext == "" {
+			ext = strings.ToLower(filepath.Ext(p.fileName))
+		}
+		if ext == "" {
+			ext = detectExtFromMagic(data)
+		}
+		if _, err := out.Write(data); err != nil {
+			logger.Warn("feishu: failed to write media file", "path", out.Name(), "err", err)
+			return "", "", err
+		}
+		return "feishu", ext, nil
+	})
+}
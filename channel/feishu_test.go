@@ -0,0 +1,175 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+func feishuTextEvent(eventID, messageID, text string) *larkim.P2MessageReceiveV1 {
+	str := func(s string) *string { return &s }
+	return &larkim.P2MessageReceiveV1{
+		EventV2Base: &larkevent.EventV2Base{Header: &larkevent.EventHeader{EventID: eventID}},
+		Event: &larkim.P2MessageReceiveV1Data{
+			Sender: &larkim.EventSender{SenderId: &larkim.UserId{OpenId: str("ou_1")}},
+			Message: &larkim.EventMessage{
+				MessageId:   str(messageID),
+				MessageType: str("text"),
+				Content:     str(`{"text":"` + text + `"}`),
+				ChatId:      str("oc_1"),
+				ChatType:    str("p2p"),
+			},
+		},
+	}
+}
+
+func TestFeishu_DuplicateEventEnqueuedOnce(t *testing.T) {
+	f := &FeishuChannel{
+		messages: make(chan *Message, 10),
+		done:     make(chan struct{}),
+		dedup:    NewDedupCache(0),
+	}
+
+	f.processMessageEvent(feishuTextEvent("ev-1", "om-1", "hello"))
+	f.processMessageEvent(feishuTextEvent("ev-1", "om-1", "hello")) // retry, same event
+	f.processMessageEvent(feishuTextEvent("ev-2", "om-1", "hello")) // redelivery under a new event
+	f.processMessageEvent(feishuTextEvent("ev-3", "om-2", "hello")) // distinct message, same text
+
+	if got := len(f.messages); got != 2 {
+		t.Fatalf("enqueued %d messages, want 2", got)
+	}
+	if m := <-f.messages; m.ID != "om-1" {
+		t.Errorf("first message ID = %q, want om-1", m.ID)
+	}
+	if m := <-f.messages; m.ID != "om-2" {
+		t.Errorf("second message ID = %q, want om-2", m.ID)
+	}
+}
+
+func TestFeishu_RequireMentionDropsUnmentionedGroupMessage(t *testing.T) {
+	str := func(s string) *string { return &s }
+	f := &FeishuChannel{
+		messages:       make(chan *Message, 10),
+		done:           make(chan struct{}),
+		dedup:          NewDedupCache(0),
+		requireMention: true,
+		botOpenID:      "ou_bot",
+	}
+	groupEvent := func(eventID, messageID, text string, mentions ...*larkim.MentionEvent) *larkim.P2MessageReceiveV1 {
+		ev := feishuTextEvent(eventID, messageID, text)
+		ev.Event.Message.ChatType = str("group")
+		ev.Event.Message.Mentions = mentions
+		return ev
+	}
+	mention := func(key, openID, name string) *larkim.MentionEvent {
+		return &larkim.MentionEvent{Key: str(key), Id: &larkim.UserId{OpenId: str(openID)}, Name: str(name)}
+	}
+
+	f.processMessageEvent(groupEvent("ev-1", "om-1", "chatting among ourselves"))
+	f.processMessageEvent(groupEvent("ev-2", "om-2", "@_user_1 look at this", mention("@_user_1", "ou_alice", "Alice")))
+	f.processMessageEvent(groupEvent("ev-3", "om-3", "@_user_1 summarize for @_user_2",
+		mention("@_user_1", "ou_bot", "nagobot"), mention("@_user_2", "ou_alice", "Alice")))
+	f.processMessageEvent(feishuTextEvent("ev-4", "om-4", "p2p needs no mention"))
+
+	if got := len(f.messages); got != 2 {
+		t.Fatalf("enqueued %d messages, want 2", got)
+	}
+	if m := <-f.messages; m.ID != "om-3" || m.Text != "summarize for @Alice" {
+		t.Errorf("group message = %q (%s), want the bot mention stripped", m.Text, m.ID)
+	}
+	if m := <-f.messages; m.ID != "om-4" {
+		t.Errorf("second message ID = %q, want om-4", m.ID)
+	}
+}
+
+func TestFeishu_MentionsKeptUnlessFilteringWithKnownBot(t *testing.T) {
+	str := func(s string) *string { return &s }
+	groupEvent := func(eventID, messageID string) *larkim.P2MessageReceiveV1 {
+		ev := feishuTextEvent(eventID, messageID, "@_user_1 ping @_user_2")
+		ev.Event.Message.ChatType = str("group")
+		ev.Event.Message.Mentions = []*larkim.MentionEvent{
+			{Key: str("@_user_1"), Id: &larkim.UserId{OpenId: str("ou_bot")}, Name: str("nagobot")},
+			{Key: str("@_user_2"), Id: &larkim.UserId{OpenId: str("ou_alice")}, Name: str("Alice")},
+		}
+		return ev
+	}
+	cases := []struct {
+		name           string
+		requireMention bool
+		botOpenID      string
+	}{
+		{"mention not required", false, "ou_bot"},
+		{"bot open_id unknown", true, ""},
+	}
+	for _, c := range cases {
+		f := &FeishuChannel{
+			messages:       make(chan *Message, 10),
+			done:           make(chan struct{}),
+			dedup:          NewDedupCache(0),
+			requireMention: c.requireMention,
+			botOpenID:      c.botOpenID,
+		}
+		f.processMessageEvent(groupEvent("ev-1", "om-1"))
+		if got := len(f.messages); got != 1 {
+			t.Fatalf("%s: enqueued %d messages, want 1", c.name, got)
+		}
+		if m := <-f.messages; m.Text != "@_user_1 ping @_user_2" {
+			t.Errorf("%s: text = %q, want mentions left untouched", c.name, m.Text)
+		}
+	}
+}
+
+func TestFeishu_ImageDownloadedToMediaDir(t *testing.T) {
+	str := func(s string) *string { return &s }
+	png := []byte("\x89PNG\r\n\x1a\nrest-of-image")
+	var gotMessageID, gotKey, gotType string
+	f := &FeishuChannel{
+		messages: make(chan *Message, 10),
+		done:     make(chan struct{}),
+		dedup:    NewDedupCache(0),
+		mediaDir: t.TempDir(),
+		fetchResource: func(_ context.Context, messageID, key, resType string) (io.Reader, string, error) {
+			gotMessageID, gotKey, gotType = messageID, key, resType
+			if key == "img_broken" {
+				return nil, "", errors.New("resource not found")
+			}
+			return bytes.NewReader(png), "", nil
+		},
+	}
+	imageEvent := func(eventID, messageID, key string) *larkim.P2MessageReceiveV1 {
+		ev := feishuTextEvent(eventID, messageID, "")
+		ev.Event.Message.MessageType = str("image")
+		ev.Event.Message.Content = str(`{"image_key":"` + key + `"}`)
+		return ev
+	}
+
+	f.processMessageEvent(imageEvent("ev-1", "om-1", "img_ok"))
+	m := <-f.messages
+	if gotMessageID != "om-1" || gotKey != "img_ok" || gotType != "image" {
+		t.Fatalf("fetch called with (%q, %q, %q)", gotMessageID, gotKey, gotType)
+	}
+	summary := m.Metadata["media_summary"]
+	const prefix = "[Media: image]\nimage_path: "
+	if !strings.HasPrefix(summary, prefix) {
+		t.Fatalf("media_summary = %q, want local image_path", summary)
+	}
+	path := strings.TrimPrefix(summary, prefix)
+	if !strings.HasSuffix(path, ".png") {
+		t.Errorf("path %q should carry the detected .png extension", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, png) {
+		t.Fatalf("downloaded file = %q, %v", data, err)
+	}
+
+	f.processMessageEvent(imageEvent("ev-2", "om-2", "img_broken"))
+	if m := <-f.messages; m.Metadata["media_summary"] != "[Media: image]\nimage_key: img_broken" {
+		t.Errorf("failed download should keep the placeholder, got %q", m.Metadata["media_summary"])
+	}
+}
@@ -0,0 +1,57 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Per-platform upload limits enforced before contacting the API.
+const (
+	telegramMaxFileSize = 50 << 20 // Bot API sendDocument limit
+	discordMaxFileSize  = 10 << 20 // attachment limit for non-boosted servers
+	feishuMaxFileSize   = 30 << 20 // im/v1/files limit
+	feishuMaxImageSize  = 10 << 20 // im/v1/images limit
+)
+
+// FileSender is the optional capability that lets a channel upload a local
+// file as an attachment. Target convention matches Send's ReplyTo.
+type FileSender interface {
+	SendFile(ctx context.Context, to, path, caption string) error
+	// MaxFileSize returns the largest upload the platform accepts, in bytes.
+	MaxFileSize() int64
+}
+
+// SupportsFiles reports whether the named channel implements FileSender.
+func (m *Manager) SupportsFiles(channelName string) bool {
+	ch, ok := m.Get(channelName)
+	if !ok {
+		return false
+	}
+	_, ok = ch.(FileSender)
+	return ok
+}
+
+// SendFile uploads the file at path to a chat on the named channel. It fails
+// if the channel cannot upload files or the file exceeds the channel's cap.
+func (m *Manager) SendFile(ctx context.Context, channelName, to, path, caption string) error {
+	ch, ok := m.Get(channelName)
+	if !ok {
+		return fmt.Errorf("channel not found: %s", channelName)
+	}
+	sender, ok := ch.(FileSender)
+	if !ok {
+		return fmt.Errorf("channel %s does not support file uploads", channelName)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	if limit := sender.MaxFileSize(); limit > 0 && info.Size() > limit {
+		return fmt.Errorf("file is %d bytes, %s accepts at most %d bytes", info.Size(), channelName, limit)
+	}
+	return sender.SendFile(ctx, to, path, m.redact(channelName, caption))
+}
@@ -1,22 +1,24 @@
 package channel
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/linanwx/nagobot/logger"
 )
 
-// initMediaDir creates and returns the media directory path for a config.
-// Returns empty string if workspace is unavailable or mkdir fails.
-func initMediaDir(cfg interface{ WorkspacePath() (string, error) }) string {
+// initMediaDir creates and returns the media directory path for a config and
+// applies its media cache size cap. Returns empty string if workspace is
+// unavailable or mkdir fails.
+func initMediaDir(cfg interface {
+	WorkspacePath() (string, error)
+	GetChannelMediaCacheMB() int
+}) string {
+	mediaCacheMB.Store(int64(cfg.GetChannelMediaCacheMB()))
 	ws, err := cfg.WorkspacePath()
 	if err != nil {
 		return ""
@@ -30,67 +32,49 @@ func initMediaDir(cfg interface{ WorkspacePath() (string, error) }) string {
 }
 
 // downloadMedia downloads a URL to mediaDir, returning the absolute local path.
-// Returns empty string on error (caller should fall back to URL).
+// A URL downloaded before is served from disk. Returns empty string on error
+// (caller should fall back to URL).
 func downloadMedia(mediaDir, url string) string {
-	if mediaDir == "" || url == "" {
-		return ""
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		logger.Warn("failed to download media", "url", url, "err", err)
-		return ""
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		logger.Warn("media download returned non-200", "url", url, "status", resp.StatusCode)
-		return ""
-	}
-
-	// Detect extension: try URL path first, then Content-Type, then fallback.
-	ext := extensionFromURL(url)
-	if ext == "" {
-		ext = extensionFromContentType(resp.Header.Get("Content-Type"))
-	}
-	if ext == "" {
-		ext = ".dat"
-	}
-
-	// Choose filename prefix based on content type.
-	prefix := "media"
-	ct := resp.Header.Get("Content-Type")
-	switch {
-	case strings.HasPrefix(ct, "image/"):
-		prefix = "img"
-	case strings.HasPrefix(ct, "audio/"):
-		prefix = "audio"
-	case strings.HasPrefix(ct, "video/"):
-		prefix = "video"
-	case ct == "application/pdf":
-		prefix = "pdf"
-	}
+	return cachedMedia(mediaDir, url, func(f *os.File) (string, string, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			logger.Warn("failed to download media", "url", url, "err", err)
+			return "", "", err
+		}
+		defer resp.Body.Close()
 
-	buf := make([]byte, 4)
-	rand.Read(buf)
-	fileName := fmt.Sprintf("%s-%s-%s%s", prefix, time.Now().Format("20060102-150405"), hex.EncodeToString(buf), ext)
-	filePath := filepath.Join(mediaDir, fileName)
+		if resp.StatusCode != http.StatusOK {
+			logger.Warn("media download returned non-200", "url", url, "status", resp.StatusCode)
+			return "", "", fmt.Errorf("status %d", resp.StatusCode)
+		}
 
-	f, err := os.Create(filePath)
-	if err != nil {
-		logger.Warn("failed to create media file", "path", filePath, "err", err)
-		return ""
-	}
-	defer f.Close()
+		// Detect extension: try URL path first, then Content-Type, then fallback.
+		ext := extensionFromURL(url)
+		if ext == "" {
+			ext = extensionFromContentType(resp.Header.Get("Content-Type"))
+		}
 
-	const maxMediaSize = 20 << 20 // 20 MB
-	if _, err := io.Copy(f, io.LimitReader(resp.Body, maxMediaSize)); err != nil {
-		logger.Warn("failed to write media file", "path", filePath, "err", err)
-		os.Remove(filePath)
-		return ""
-	}
+		// Choose filename prefix based on content type.
+		prefix := "media"
+		ct := resp.Header.Get("Content-Type")
+		switch {
+		case strings.HasPrefix(ct, "image/"):
+			prefix = "img"
+		case strings.HasPrefix(ct, "audio/"):
+			prefix = "audio"
+		case strings.HasPrefix(ct, "video/"):
+			prefix = "video"
+		case ct == "application/pdf":
+			prefix = "pdf"
+		}
 
-	return filePath
+		const maxMediaSize = 20 << 20 // 20 MB
+		if _, err := io.Copy(f, io.LimitReader(resp.Body, maxMediaSize)); err != nil {
+			logger.Warn("failed to write media file", "path", f.Name(), "err", err)
+			return "", "", err
+		}
+		return prefix, ext, nil
+	})
 }
 
 func extensionFromURL(url string) string {
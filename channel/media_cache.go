@@ -0,0 +1,159 @@
+package channel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+// Downloaded media is content-addressed: a file is named
+// <prefix>-<hash of its source key><ext>, where the key is the remote URL or
+// the platform's file key. A key already on disk is served from there, and
+// concurrent downloads of one key share a single fetch. After each new
+// download the media directory is trimmed to its size cap, least recently
+// used files first (a cache hit refreshes the file's modification time).
+
+// defaultMediaCacheMB caps the media directory when channels.mediaCacheMB is unset.
+const defaultMediaCacheMB = 500
+
+// mediaCacheMB is the size cap set by initMediaDir from config (0 = default,
+// negative disables cleanup). Every channel shares the one media directory.
+var mediaCacheMB atomic.Int64
+
+// mediaFetch writes one media file's content to f and returns the file name
+// prefix (e.g. "img", "feishu") and extension to store it under.
+type mediaFetch func(f *os.File) (prefix, ext string, err error)
+
+// mediaCall is one in-flight download that callers of the same key wait on.
+type mediaCall struct {
+	done chan struct{}
+	path string
+}
+
+var (
+	mediaMu       sync.Mutex
+	mediaInflight = map[string]*mediaCall{}
+)
+
+// cachedMedia returns the local path of the media stored under key in
+// mediaDir, running fetch only when it is not there yet. Returns empty string
+// when the download fails (fetch logs the reason).
+func cachedMedia(mediaDir, key string, fetch mediaFetch) string {
+	if mediaDir == "" || key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:8])
+	flightKey := filepath.Join(mediaDir, hash)
+
+	mediaMu.Lock()
+	if c, ok := mediaInflight[flightKey]; ok {
+		mediaMu.Unlock()
+		<-c.done
+		return c.path
+	}
+	c := &mediaCall{done: make(chan struct{})}
+	mediaInflight[flightKey] = c
+	mediaMu.Unlock()
+
+	c.path = loadOrFetchMedia(mediaDir, hash, fetch)
+
+	mediaMu.Lock()
+	delete(mediaInflight, flightKey)
+	mediaMu.Unlock()
+	close(c.done)
+	return c.path
+}
+
+func loadOrFetchMedia(mediaDir, hash string, fetch mediaFetch) string {
+	if matches, _ := filepath.Glob(filepath.Join(mediaDir, "*-"+hash+".*")); len(matches) > 0 {
+		now := time.Now()
+		_ = os.Chtimes(matches[0], now, now)
+		return matches[0]
+	}
+
+	// Download to a hidden temp file so a partial file is never served.
+	tmp, err := os.CreateTemp(mediaDir, ".download-*")
+	if err != nil {
+		logger.Warn("failed to create media file", "dir", mediaDir, "err", err)
+		return ""
+	}
+	prefix, ext, err := fetch(tmp)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		logger.Warn("failed to write media file", "path", tmp.Name(), "err", closeErr)
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return ""
+	}
+	if ext == "" {
+		ext = ".dat"
+	}
+	filePath := filepath.Join(mediaDir, prefix+"-"+hash+ext)
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		logger.Warn("failed to store media file", "path", filePath, "err", err)
+		os.Remove(tmp.Name())
+		return ""
+	}
+	_ = os.Chmod(filePath, 0644)
+
+	limit := mediaCacheMB.Load()
+	if limit == 0 {
+		limit = defaultMediaCacheMB
+	}
+	if limit > 0 {
+		pruneMediaDir(mediaDir, limit<<20, filePath)
+	}
+	return filePath
+}
+
+// pruneMediaDir deletes the least recently used files in dir until the rest
+// fit in maxBytes. keep (the file just stored) and in-progress downloads are
+// never deleted.
+func pruneMediaDir(dir string, maxBytes int64, keep string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type mediaFile struct {
+		path string
+		size int64
+		mod  time.Time
+	}
+	var files []mediaFile
+	var total int64
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, mediaFile{filepath.Join(dir, e.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+	for _, f := range files {
+		if total <= maxBytes {
+			return
+		}
+		if f.path == keep {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			logger.Warn("failed to delete old media file", "path", f.path, "err", err)
+			continue
+		}
+		total -= f.size
+	}
+}
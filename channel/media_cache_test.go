@@ -0,0 +1,77 @@
+package channel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadMediaSameURLHitsNetworkOnce(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		time.Sleep(20 * time.Millisecond) // let concurrent callers pile up
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n\x1a\nimage"))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	url := srv.URL + "/photo"
+
+	paths := make([]string, 5)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths[i] = downloadMedia(dir, url)
+		}(i)
+	}
+	wg.Wait()
+	again := downloadMedia(dir, url)
+
+	if hits.Load() != 1 {
+		t.Errorf("server hits = %d, want 1", hits.Load())
+	}
+	for _, p := range append(paths, again) {
+		if p == "" || p != paths[0] {
+			t.Fatalf("paths = %q + %q, want one shared path", paths, again)
+		}
+	}
+	if filepath.Ext(paths[0]) != ".png" {
+		t.Errorf("path %q should carry the .png extension", paths[0])
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("media dir has %d entries, want 1", len(entries))
+	}
+
+	if other := downloadMedia(dir, srv.URL+"/other"); other == "" || other == paths[0] || hits.Load() != 2 {
+		t.Errorf("a different URL must be downloaded to its own file: %q, hits=%d", other, hits.Load())
+	}
+}
+
+func TestPruneMediaDirDropsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"old.png", "mid.png", "new.png"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mod := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(path, mod, mod)
+	}
+
+	pruneMediaDir(dir, 250, filepath.Join(dir, "new.png"))
+
+	for name, want := range map[string]bool{"old.png": false, "mid.png": true, "new.png": true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", name, err == nil, want)
+		}
+	}
+}
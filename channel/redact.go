@@ -0,0 +1,151 @@
+package channel
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+// Built-in channels.redact rule names. Any other entry is compiled as a
+// custom regular expression.
+const (
+	RedactEmail      = "email"
+	RedactPhone      = "phone"
+	RedactCreditCard = "creditcard"
+)
+
+var (
+	redactEmailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// International (+CC ...), separated North American (555-123-4567,
+	// (555) 123-4567) and mainland China mobile numbers.
+	redactPhoneRe = regexp.MustCompile(`\+\d{1,3}[ .-]?\(?\d{1,4}\)?(?:[ .-]?\d{2,4}){2,4}|\(?\b\d{3}\)?[ .-]\d{3}[ .-]\d{4}\b|\b1[3-9]\d{9}\b`)
+	// 13-19 digits, optionally grouped by spaces or dashes; matches must
+	// also pass the Luhn check.
+	redactCardRe = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// redactRule masks one kind of sensitive text.
+type redactRule struct {
+	name  string
+	re    *regexp.Regexp
+	valid func(match string) bool // optional extra check on each match
+}
+
+// Redactor masks sensitive text in outgoing messages. A nil Redactor
+// leaves text unchanged.
+type Redactor struct {
+	rules []redactRule
+}
+
+// NewRedactor builds a Redactor from channels.redact entries. Invalid custom
+// patterns are logged and skipped. Returns nil when no rule is usable.
+func NewRedactor(rules []string) *Redactor {
+	var r Redactor
+	for _, entry := range rules {
+		entry = strings.TrimSpace(entry)
+		switch strings.ToLower(entry) {
+		case "":
+			continue
+		case RedactEmail:
+			r.rules = append(r.rules, redactRule{name: RedactEmail, re: redactEmailRe})
+		case RedactPhone:
+			r.rules = append(r.rules, redactRule{name: RedactPhone, re: redactPhoneRe})
+		case RedactCreditCard:
+			r.rules = append(r.rules, redactRule{name: RedactCreditCard, re: redactCardRe, valid: luhnValid})
+		default:
+			re, err := regexp.Compile(entry)
+			if err != nil {
+				logger.Warn("channels.redact: ignoring invalid pattern", "pattern", entry, "err", err)
+				continue
+			}
+			r.rules = append(r.rules, redactRule{name: "custom", re: re})
+		}
+	}
+	if len(r.rules) == 0 {
+		return nil
+	}
+	// Card numbers go first so the phone rule cannot claim part of one.
+	sort.SliceStable(r.rules, func(i, j int) bool {
+		return r.rules[i].name == RedactCreditCard && r.rules[j].name != RedactCreditCard
+	})
+	return &r
+}
+
+// Redact masks every rule match in text and returns the result with the
+// number of matches replaced, by rule name.
+func (r *Redactor) Redact(text string) (string, map[string]int) {
+	if r == nil || text == "" {
+		return text, nil
+	}
+	var counts map[string]int
+	for _, rule := range r.rules {
+		text = rule.re.ReplaceAllStringFunc(text, func(m string) string {
+			if rule.valid != nil && !rule.valid(m) {
+				return m
+			}
+			if counts == nil {
+				counts = make(map[string]int)
+			}
+			counts[rule.name]++
+			return "[REDACTED:" + rule.name + "]"
+		})
+	}
+	return text, counts
+}
+
+// SetRedaction applies channels.redact from cfg to every send made through
+// the manager. Called at startup and on config reload.
+func (m *Manager) SetRedaction(cfg *config.Config) {
+	m.redactor.Store(NewRedactor(cfg.GetChannelRedact()))
+}
+
+// redact masks text bound for channelName and logs when anything was masked.
+func (m *Manager) redact(channelName, text string) string {
+	out, counts := m.redactor.Load().Redact(text)
+	if len(counts) > 0 {
+		logger.Info("outbound text redacted", "channel", channelName, "matches", counts)
+	}
+	return out
+}
+
+// redactEmbed masks every text field of embed.
+func (m *Manager) redactEmbed(channelName string, embed msg.Embed) msg.Embed {
+	if m.redactor.Load() == nil {
+		return embed
+	}
+	embed.Title = m.redact(channelName, embed.Title)
+	embed.Description = m.redact(channelName, embed.Description)
+	fields := make([]msg.EmbedField, len(embed.Fields))
+	for i, f := range embed.Fields {
+		f.Name = m.redact(channelName, f.Name)
+		f.Value = m.redact(channelName, f.Value)
+		fields[i] = f
+	}
+	embed.Fields = fields
+	return embed
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
@@ -0,0 +1,75 @@
+package channel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+func TestRedactorMasksEmailAndCard(t *testing.T) {
+	r := NewRedactor([]string{"email", "phone", "creditcard"})
+	in := "Mail alice.smith@example.com, card 4111 1111 1111 1111, call +1 415-555-0132."
+	out, counts := r.Redact(in)
+	for _, leaked := range []string{"alice.smith@example.com", "4111 1111 1111 1111", "555-0132"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("%q not redacted: %s", leaked, out)
+		}
+	}
+	if counts[RedactEmail] != 1 || counts[RedactCreditCard] != 1 || counts[RedactPhone] != 1 {
+		t.Errorf("counts = %v, want one of each", counts)
+	}
+	if !strings.Contains(out, "[REDACTED:email]") || !strings.Contains(out, "[REDACTED:creditcard]") {
+		t.Errorf("missing masks: %s", out)
+	}
+}
+
+func TestRedactorPassesNormalText(t *testing.T) {
+	r := NewRedactor([]string{"email", "phone", "creditcard"})
+	for _, in := range []string{
+		"The build finished in 42s on 2026-10-17.",
+		"Order 1234567890123 shipped", // 13 digits, fails the Luhn check
+		"Use user@localhost for the dev box",
+	} {
+		if out, counts := r.Redact(in); out != in || len(counts) != 0 {
+			t.Errorf("Redact(%q) = %q, %v; want unchanged", in, out, counts)
+		}
+	}
+}
+
+func TestRedactorCustomPattern(t *testing.T) {
+	r := NewRedactor([]string{`sk-[A-Za-z0-9]{8,}`, "("})
+	out, _ := r.Redact("key is sk-abcdef123456")
+	if out != "key is [REDACTED:custom]" {
+		t.Errorf("custom pattern: %q", out)
+	}
+	if NewRedactor(nil) != nil || NewRedactor([]string{"("}) != nil {
+		t.Error("no usable rules should give a nil Redactor")
+	}
+}
+
+func TestManagerSendResponseRedacts(t *testing.T) {
+	ch := &recordingChannel{sent: make(chan *Response, 2)}
+	m := NewManager()
+	m.Register(ch)
+
+	resp := &Response{Text: "reach me at bob@example.com", ReplyTo: "42"}
+	if err := m.SendResponse(context.Background(), "noop", resp); err != nil {
+		t.Fatal(err)
+	}
+	if got := (<-ch.sent).Text; got != resp.Text {
+		t.Errorf("redaction off by default, got %q", got)
+	}
+
+	m.SetRedaction(&config.Config{Channels: &config.ChannelsConfig{Redact: []string{"email"}}})
+	if err := m.SendResponse(context.Background(), "noop", resp); err != nil {
+		t.Fatal(err)
+	}
+	if got := (<-ch.sent).Text; got != "reach me at [REDACTED:email]" {
+		t.Errorf("sent %q", got)
+	}
+	if resp.Text != "reach me at bob@example.com" {
+		t.Errorf("caller's response was modified: %q", resp.Text)
+	}
+}
@@ -0,0 +1,78 @@
+package channel
+
+import (
+	"context"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
+)
+
+const (
+	// DefaultSendRetries is how many times a failed outgoing chunk is
+	// retried when channels.sendRetries is unset.
+	DefaultSendRetries = 3
+
+	sendRetryBaseDelay = time.Second      // transient-error backoff, doubled per attempt
+	sendRetryMaxWait   = 30 * time.Second // longest retry_after honored before giving up
+
+	// sendFailedNotice replaces a chunk that could not be delivered.
+	sendFailedNotice = "⚠️ Part of this reply could not be delivered."
+)
+
+// sendRetryWait sleeps between attempts; replaced in tests.
+var sendRetryWait = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryClassifier reports whether a send error is worth retrying and how
+// long the platform asked to wait (0 = use backoff).
+type retryClassifier func(err error) (retryAfter time.Duration, retryable bool)
+
+// sendRetries resolves channels.sendRetries: 0 uses DefaultSendRetries,
+// negative disables retries.
+func sendRetries(cfg *config.Config) int {
+	n := cfg.GetChannelSendRetries()
+	switch {
+	case n == 0:
+		return DefaultSendRetries
+	case n < 0:
+		return 0
+	}
+	return n
+}
+
+// sendWithRetry calls send, retrying up to retries times on errors classify
+// accepts. Platform-supplied retry_after delays are honored up to
+// sendRetryMaxWait; other retryable errors back off exponentially.
+func sendWithRetry(ctx context.Context, channelName string, retries int, classify retryClassifier, send func() error) error {
+	delay := sendRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := send()
+		if err == nil || attempt >= retries {
+			return err
+		}
+		wait, ok := classify(err)
+		if !ok {
+			return err
+		}
+		if wait <= 0 {
+			wait = delay
+			delay *= 2
+		}
+		if wait > sendRetryMaxWait {
+			return err
+		}
+		logger.Warn("channel send failed, retrying", "channel", channelName, "attempt", attempt+1, "wait", wait, "err", err)
+		if werr := sendRetryWait(ctx, wait); werr != nil {
+			return err
+		}
+	}
+}
@@ -17,8 +17,9 @@ const socketMessageBufferSize = 100
 // socketInbound is the JSON message sent by a CLI client.
 // When Method is non-empty, the message is treated as a JSON RPC request.
 type socketInbound struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	DryRun bool   `json:"dry_run,omitempty"`
 	// RPC fields
 	ID     string          `json:"id,omitempty"`
 	Method string          `json:"method,omitempty"`
@@ -243,6 +244,9 @@ func (s *SocketChannel) handleConn(client *socketClient) {
 				"chat_id": "cli",
 			},
 		}
+		if req.DryRun {
+			msg.Metadata["dry_run"] = "true"
+		}
 
 		select {
 		case s.messages <- msg:
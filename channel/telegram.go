@@ -2,9 +2,13 @@ package channel
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -20,11 +24,14 @@ const (
 
 // TelegramChannel implements the Channel interface for Telegram.
 type TelegramChannel struct {
-	token      string
-	mu         sync.RWMutex   // protects allowedIDs
-	allowedIDs map[int64]bool // Allowed user/chat IDs (nil = allow all)
-	messages   chan *Message
-	mediaDir   string // Local directory for downloaded media files
+	token        string
+	mu           sync.RWMutex   // protects allowedIDs, maxMsgLen and sendRetries
+	allowedIDs   map[int64]bool // Allowed user/chat IDs (nil = allow all)
+	maxMsgLen    int            // UTF-16 code units per outgoing chunk
+	sendRetries  int            // retries per chunk on rate limits and transient errors
+	messages     chan *Message
+	backpressure backpressure
+	mediaDir     string // Local directory for downloaded media files
 
 	b         *bot.Bot
 	cancel    context.CancelFunc
@@ -50,11 +57,14 @@ func NewTelegramChannel(cfg *config.Config) Channel {
 	mediaDir := initMediaDir(cfg)
 
 	return &TelegramChannel{
-		token:      token,
-		allowedIDs: allowedIDs,
-		messages:   make(chan *Message, telegramMessageBufferSize),
-		mediaDir:   mediaDir,
-		done:       make(chan struct{}),
+		token:        token,
+		allowedIDs:   allowedIDs,
+		maxMsgLen:    MaxMessageLength(cfg, "telegram"),
+		sendRetries:  sendRetries(cfg),
+		messages:     make(chan *Message, telegramMessageBufferSize),
+		backpressure: newBackpressure(cfg, telegramMessageBufferSize),
+		mediaDir:     mediaDir,
+		done:         make(chan struct{}),
 	}
 }
 
@@ -66,6 +76,8 @@ func (t *TelegramChannel) Reconfigure(cfg *config.Config) {
 	}
 	t.mu.Lock()
 	t.allowedIDs = newIDs
+	t.maxMsgLen = MaxMessageLength(cfg, "telegram")
+	t.sendRetries = sendRetries(cfg)
 	t.mu.Unlock()
 }
 
@@ -133,30 +145,136 @@ func (t *TelegramChannel) Send(ctx context.Context, resp *Response) error {
 		return fmt.Errorf("invalid chat ID: %w", err)
 	}
 
-	chunks := SplitMessage(resp.Text, TelegramMaxMessageLength)
+	t.mu.RLock()
+	maxLen := t.maxMsgLen
+	retries := t.sendRetries
+	t.mu.RUnlock()
+	chunks := SplitMessageUTF16(resp.Text, maxLen)
 
+	// Only the first chunk is posted as a reply; the rest follow it.
+	replyToID, _ := strconv.Atoi(resp.ReplyToMessageID)
 	for _, chunk := range chunks {
-		htmlChunk := tgmd.Convert(chunk)
-		_, sendErr := t.b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID:    chatID,
-			Text:      htmlChunk,
-			ParseMode: models.ParseModeHTML,
+		err := sendWithRetry(ctx, "telegram", retries, telegramRetryAfter, func() error {
+			_, err := sendTelegramMarkdown(ctx, t.b, chatID, chunk, replyToID)
+			return err
 		})
-		if sendErr != nil {
-			// Retry without formatting using the original markdown text.
-			_, retryErr := t.b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: chatID,
-				Text:   chunk,
-			})
-			if retryErr != nil {
-				return fmt.Errorf("telegram send error: %w", retryErr)
-			}
+		if err != nil {
+			// Let the chat know the reply is incomplete rather than stopping silently.
+			_, _ = sendTelegramMarkdown(ctx, t.b, chatID, sendFailedNotice, 0)
+			return err
+		}
+		replyToID = 0
+	}
+
+	return nil
+}
+
+// telegramRetryAfter classifies a Bot API send error: 429 waits retry_after,
+// client errors (bad request, forbidden, ...) are final, anything else
+// (network, 5xx) is retried with backoff.
+func telegramRetryAfter(err error) (time.Duration, bool) {
+	var tooMany *bot.TooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return time.Duration(tooMany.RetryAfter) * time.Second, true
+	}
+	var migrate *bot.MigrateError
+	if errors.As(err, &migrate) {
+		return 0, false
+	}
+	for _, final := range []error{bot.ErrorBadRequest, bot.ErrorForbidden, bot.ErrorUnauthorized, bot.ErrorNotFound, bot.ErrorConflict} {
+		if errors.Is(err, final) {
+			return 0, false
 		}
 	}
+	return 0, true
+}
+
+// SendFile uploads path to the chat via sendDocument, with caption below it.
+func (t *TelegramChannel) SendFile(ctx context.Context, to, path, caption string) error {
+	if t.b == nil {
+		return fmt.Errorf("telegram bot not started")
+	}
+	chatID, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID: %w", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file %s: %w", path, err)
+	}
+	defer f.Close()
 
+	if _, err := t.b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:   chatID,
+		Document: &models.InputFileUpload{Filename: filepath.Base(path), Data: f},
+		Caption:  caption,
+	}); err != nil {
+		return fmt.Errorf("telegram file send: %w", err)
+	}
 	return nil
 }
 
+// MaxFileSize returns the Bot API upload limit.
+func (t *TelegramChannel) MaxFileSize() int64 { return telegramMaxFileSize }
+
+// Compile-time check: TelegramChannel implements FileSender.
+var _ FileSender = (*TelegramChannel)(nil)
+
+// SendTelegramMarkdown sends one markdown chunk as Telegram HTML. If Telegram
+// rejects the markup, it retries once with a repaired version (see
+// tgmd.Repair) before falling back to the unformatted markdown text.
+func SendTelegramMarkdown(ctx context.Context, b *bot.Bot, chatID int64, chunk string) (*models.Message, error) {
+	return sendTelegramMarkdown(ctx, b, chatID, chunk, 0)
+}
+
+// telegramSendParams builds sendMessage params, posting as a reply to
+// replyToID when non-zero. The send still succeeds if that message is gone.
+func telegramSendParams(chatID int64, text string, mode models.ParseMode, replyToID int) *bot.SendMessageParams {
+	params := &bot.SendMessageParams{
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: mode,
+	}
+	if replyToID != 0 {
+		params.ReplyParameters = &models.ReplyParameters{
+			MessageID:                replyToID,
+			AllowSendingWithoutReply: true,
+		}
+	}
+	return params
+}
+
+// sendTelegramMarkdown is SendTelegramMarkdown, optionally replying to replyToID.
+func sendTelegramMarkdown(ctx context.Context, b *bot.Bot, chatID int64, chunk string, replyToID int) (*models.Message, error) {
+	htmlChunk := tgmd.Convert(chunk)
+	msg, sendErr := b.SendMessage(ctx, telegramSendParams(chatID, htmlChunk, models.ParseModeHTML, replyToID))
+	if sendErr == nil {
+		return msg, nil
+	}
+	if bot.IsTooManyRequestsError(sendErr) {
+		// Resending right away would hit the limit again; the caller waits.
+		return nil, fmt.Errorf("telegram send error: %w", sendErr)
+	}
+
+	if tgmd.IsParseError(sendErr.Error()) {
+		logger.Warn("telegram rejected HTML", "chatID", chatID, "err", sendErr, "html", htmlChunk)
+		if fixed, ok := tgmd.Repair(htmlChunk, sendErr.Error()); ok {
+			msg, repairErr := b.SendMessage(ctx, telegramSendParams(chatID, fixed, models.ParseModeHTML, replyToID))
+			if repairErr == nil {
+				return msg, nil
+			}
+			logger.Warn("telegram repaired HTML also rejected, sending plain text", "chatID", chatID, "err", repairErr)
+		}
+	}
+
+	// Retry without formatting using the original markdown text.
+	msg, retryErr := b.SendMessage(ctx, telegramSendParams(chatID, chunk, "", replyToID))
+	if retryErr != nil {
+		return nil, fmt.Errorf("telegram send error: %w", retryErr)
+	}
+	return msg, nil
+}
+
 // Messages returns the incoming message channel.
 func (t *TelegramChannel) Messages() <-chan *Message {
 	return t.messages
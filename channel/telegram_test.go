@@ -0,0 +1,73 @@
+package channel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestTelegramSendParamsReplyParameters(t *testing.T) {
+	p := telegramSendParams(42, "hi", models.ParseModeHTML, 7)
+	if p.ReplyParameters == nil {
+		t.Fatal("ReplyParameters not set")
+	}
+	if p.ReplyParameters.MessageID != 7 || !p.ReplyParameters.AllowSendingWithoutReply {
+		t.Errorf("ReplyParameters = %+v", p.ReplyParameters)
+	}
+	if p.ChatID != int64(42) || p.Text != "hi" || p.ParseMode != models.ParseModeHTML {
+		t.Errorf("params = %+v", p)
+	}
+
+	if p := telegramSendParams(42, "hi", "", 0); p.ReplyParameters != nil {
+		t.Errorf("ReplyParameters = %+v, want nil without a reply target", p.ReplyParameters)
+	}
+}
+
+func TestTelegramSendRetriesAfterRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 3","parameters":{"retry_after":3}}`)
+			return
+		}
+		io.WriteString(w, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":42,"type":"private"},"text":"hello"}}`)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	origWait := sendRetryWait
+	sendRetryWait = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { sendRetryWait = origWait }()
+
+	b, err := bot.New("123:test", bot.WithServerURL(srv.URL), bot.WithSkipGetMe())
+	if err != nil {
+		t.Fatalf("bot.New: %v", err)
+	}
+	ch := &TelegramChannel{b: b, maxMsgLen: TelegramMaxMessageLength, sendRetries: DefaultSendRetries}
+	if err := ch.Send(context.Background(), &Response{Text: "hello", ReplyTo: "42"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("sendMessage calls = %d, want 2 (429 then success)", got)
+	}
+	if len(waits) != 1 || waits[0] != 3*time.Second {
+		t.Errorf("waits = %v, want [3s] from retry_after", waits)
+	}
+}
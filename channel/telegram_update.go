@@ -214,12 +214,7 @@ func (t *TelegramChannel) handleUpdate(ctx context.Context, b *bot.Bot, update *
 		}
 	}
 
-	select {
-	case t.messages <- channelMsg:
-	case <-t.done:
-	default:
-		logger.Warn("telegram message buffer full, dropping message")
-	}
+	t.backpressure.enqueue(t, t.messages, t.done, channelMsg)
 }
 
 // telegramReplyContext builds a reply context string from a replied-to message.
@@ -1,7 +1,6 @@
 package channel
 
 import (
-	"bufio"
 	"context"
 	"embed"
 	"encoding/json"
@@ -20,8 +19,8 @@ import (
 
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
-	cronpkg "github.com/linanwx/nagobot/cron"
 	"github.com/linanwx/nagobot/config"
+	cronpkg "github.com/linanwx/nagobot/cron"
 	"github.com/linanwx/nagobot/logger"
 	"github.com/linanwx/nagobot/provider"
 	"github.com/linanwx/nagobot/session"
@@ -48,6 +47,9 @@ type WebChannel struct {
 	wg        sync.WaitGroup
 	server    *http.Server
 
+	users           map[string]string // access token → user name
+	usersConfigured bool              // channels.web.users is set: multi-user mode even if no entry was valid
+
 	mu       sync.RWMutex
 	clients  map[string]*wsClient
 	peers    map[*wsClient]struct{}
@@ -57,6 +59,7 @@ type WebChannel struct {
 	systemPromptFn  func(string) (string, bool)
 	toolDefsFn      func(string) ([]provider.ToolDef, bool)
 	contextBudgetFn func(string) (int, int, bool)
+	sessions        *session.Manager // nil = a file store over the workspace sessions dir
 }
 
 type wsClient struct {
@@ -90,12 +93,14 @@ func NewWebChannel(cfg *config.Config) Channel {
 	}
 
 	return &WebChannel{
-		addr:      addr,
-		workspace: workspace,
-		messages:  make(chan *Message, webMessageBufferSize),
-		done:      make(chan struct{}),
-		clients:   make(map[string]*wsClient),
-		peers:     make(map[*wsClient]struct{}),
+		addr:            addr,
+		workspace:       workspace,
+		users:           loadWebUsers(cfg.GetWebUsers()),
+		usersConfigured: cfg.WebUsersConfigured(),
+		messages:        make(chan *Message, webMessageBufferSize),
+		done:            make(chan struct{}),
+		clients:         make(map[string]*wsClient),
+		peers:           make(map[*wsClient]struct{}),
 	}
 }
 
@@ -117,24 +122,59 @@ func (w *WebChannel) SetContextBudgetFn(fn func(string) (int, int, bool)) {
 	w.contextBudgetFn = fn
 }
 
+// SetSessions routes history and session views through the runtime's
+// session store, so non-file backends (sqlite) are visible too.
+func (w *WebChannel) SetSessions(sessions *session.Manager) {
+	w.sessions = sessions
+}
+
+// sessionStore returns the wired session manager, or a file-store manager
+// over the workspace's sessions directory when none is wired, so every view
+// reads sessions through session.Manager.
+func (w *WebChannel) sessionStore() (*session.Manager, error) {
+	if w.sessions != nil {
+		return w.sessions, nil
+	}
+	return session.NewManager(filepath.Join(w.workspace, sessionsDirName))
+}
+
+// readSession loads key from the session store. It returns os.ErrNotExist
+// for a missing session.
+func (w *WebChannel) readSession(key string) (*session.Session, error) {
+	if w.resolveSessionFile(key, session.SessionFileName) == "" {
+		return nil, fmt.Errorf("invalid session key %q", key)
+	}
+	sessions, err := w.sessionStore()
+	if err != nil {
+		return nil, err
+	}
+	if !sessions.Exists(key) {
+		return nil, os.ErrNotExist
+	}
+	return sessions.Load(key)
+}
+
 // Name returns the channel name.
 func (w *WebChannel) Name() string { return "web" }
 
 // Start starts the web server.
 func (w *WebChannel) Start(ctx context.Context) error {
+	if w.usersConfigured && len(w.users) == 0 {
+		return fmt.Errorf("web channel: channels.web.users is set but no user has a valid name; refusing to serve without authentication")
+	}
 	frontendFS, err := fs.Sub(rawFrontendFS, "web/dist")
 	if err != nil {
 		return fmt.Errorf("failed to load embedded web frontend: %w", err)
 	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/ws", http.HandlerFunc(w.handleWS))
-	mux.Handle("/api/history", http.HandlerFunc(w.handleHistory))
-	mux.Handle("/api/sessions/", http.HandlerFunc(w.handleSessionMessages))
-	mux.Handle("/api/sessions", http.HandlerFunc(w.handleSessions))
-	mux.Handle("/api/config", http.HandlerFunc(w.handleConfig))
-	mux.Handle("/api/heartbeat/", http.HandlerFunc(w.handleHeartbeat))
-	mux.Handle("/", http.FileServer(http.FS(frontendFS)))
+	mux.Handle("/ws", w.authed(w.handleWS))
+	mux.Handle("/api/history", w.authed(w.handleHistory))
+	mux.Handle("/api/sessions/", w.authed(w.handleSessionMessages))
+	mux.Handle("/api/sessions", w.authed(w.handleSessions))
+	mux.Handle("/api/config", w.authed(w.handleConfig))
+	mux.Handle("/api/heartbeat/", w.authed(w.handleHeartbeat))
+	mux.Handle("/", w.withTokenCookie(http.FileServer(http.FS(frontendFS))))
 
 	w.server = &http.Server{
 		Addr:    w.addr,
@@ -233,9 +273,11 @@ func (w *WebChannel) handleWS(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := &wsClient{conn: conn, boundSession: webMainSessionID}
+	home := homeSession(r)
+	user := webUserFrom(r.Context())
+	client := &wsClient{conn: conn, boundSession: home}
 	w.registerPeer(client)
-	w.bindClient(webMainSessionID, client)
+	w.bindClient(home, client)
 
 	w.wg.Add(1)
 	defer w.wg.Done()
@@ -266,6 +308,10 @@ func (w *WebChannel) handleWS(rw http.ResponseWriter, r *http.Request) {
 				_ = wsjson.Write(r.Context(), conn, webOutboundMessage{Type: "error", Error: "invalid session_id"})
 				continue
 			}
+			if !canAccessSession(r, sid) {
+				_ = wsjson.Write(r.Context(), conn, webOutboundMessage{Type: "error", Error: "session not accessible"})
+				continue
+			}
 			client.mu.Lock()
 			oldSession := client.boundSession
 			client.boundSession = sid
@@ -287,7 +333,7 @@ func (w *WebChannel) handleWS(rw http.ResponseWriter, r *http.Request) {
 			sessionID := boundSess
 			channelID := "web:" + sessionID
 			if sid := strings.TrimSpace(req.SessionID); sid != "" {
-				if valid := sanitizeSessionKey(sid); valid != "" {
+				if valid := sanitizeSessionKey(sid); valid != "" && canAccessSession(r, valid) {
 					sessionID = valid
 					channelID = "web:" + valid
 				}
@@ -303,6 +349,13 @@ func (w *WebChannel) handleWS(rw http.ResponseWriter, r *http.Request) {
 					"chat_id": sessionID,
 				},
 			}
+			if user != "" {
+				// The dispatcher routes on web_user, so an authenticated
+				// user always lands in web:<user>.
+				msg.UserID = user
+				msg.Username = user
+				msg.Metadata["web_user"] = user
+			}
 
 			select {
 			case w.messages <- msg:
@@ -383,9 +436,9 @@ type webHistoryMessage struct {
 	Content string `json:"content"`
 }
 
-
 func (w *WebChannel) handleHistory(rw http.ResponseWriter, r *http.Request) {
-	history, err := w.loadHistory()
+	key := homeSession(r)
+	history, err := w.loadHistory(key)
 	if err != nil {
 		http.Error(rw, fmt.Sprintf("failed to load history: %v", err), http.StatusInternalServerError)
 		return
@@ -393,19 +446,18 @@ func (w *WebChannel) handleHistory(rw http.ResponseWriter, r *http.Request) {
 
 	rw.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(rw).Encode(webHistoryEnvelope{
-		SessionID:  webMainSessionID,
-		SessionKey: webMainSessionID,
+		SessionID:  key,
+		SessionKey: key,
 		Messages:   history,
 	})
 }
 
-func (w *WebChannel) loadHistory() ([]webHistoryMessage, error) {
+func (w *WebChannel) loadHistory(key string) ([]webHistoryMessage, error) {
 	if w.workspace == "" {
 		return nil, fmt.Errorf("workspace is not configured")
 	}
 
-	path := filepath.Join(w.workspace, sessionsDirName, "cli", session.SessionFileName)
-	s, err := session.ReadFile(path)
+	s, err := w.readSession(key)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return []webHistoryMessage{}, nil
@@ -465,6 +517,17 @@ func (w *WebChannel) resolveSessionFile(key, filename string) string {
 	return resolved
 }
 
+// apiSessionKey strips a trailing view (system-prompt, tools, stats) from a
+// key parsed from an /api/sessions/ path.
+func apiSessionKey(raw string) string {
+	for _, view := range []string{":system-prompt", ":tools", ":stats"} {
+		if key, ok := strings.CutSuffix(raw, view); ok {
+			return key
+		}
+	}
+	return raw
+}
+
 // --- GET /api/sessions ---
 
 type sessionListEntry struct {
@@ -482,28 +545,19 @@ func (w *WebChannel) handleSessions(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionsDir := filepath.Join(w.workspace, sessionsDirName)
 	summaries := loadWebSummaries(filepath.Join(w.workspace, "system", "sessions_summary.json"))
 	var entries []sessionListEntry
 
-	_ = filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // skip unreadable dirs
-		}
-		if d.IsDir() || d.Name() != session.SessionFileName {
-			return nil
+	addEntry := func(key, dir string, messageCount int, updatedAt time.Time) {
+		if !canAccessSession(r, key) {
+			return
 		}
 
-		key := session.DeriveKeyFromPath(path)
-
-		lineCount := countLines(path)
-		updatedAt, _ := session.ReadUpdatedAt(path)
-
 		// Check for heartbeat.md — only for non-cron/thread sessions active within 2 days.
 		hasHB := false
 		isCronOrThread := strings.HasPrefix(key, "cron:") || strings.Contains(key, ":threads:")
 		if !isCronOrThread {
-			hbPath := filepath.Join(filepath.Dir(path), "heartbeat.md")
+			hbPath := filepath.Join(dir, "heartbeat.md")
 			hbCutoff := time.Now().AddDate(0, 0, -2)
 			if updatedAt.After(hbCutoff) {
 				if fi, err := os.Stat(hbPath); err == nil && fi.Size() > 0 {
@@ -515,15 +569,28 @@ func (w *WebChannel) handleSessions(rw http.ResponseWriter, r *http.Request) {
 		entry := sessionListEntry{
 			Key:          key,
 			UpdatedAt:    updatedAt,
-			MessageCount: lineCount,
+			MessageCount: messageCount,
 			HasHeartbeat: hasHB,
 		}
 		if s, ok := summaries[key]; ok {
 			entry.Summary = s
 		}
 		entries = append(entries, entry)
-		return nil
-	})
+	}
+
+	sessions, err := w.sessionStore()
+	if err == nil {
+		var list []session.SessionSummary
+		if list, err = sessions.List(0); err == nil {
+			for _, sum := range list {
+				addEntry(sum.Key, sessions.Dir(sum.Key), sum.MessageCount, sum.UpdatedAt)
+			}
+		}
+	}
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to list sessions: %v", err), http.StatusInternalServerError)
+		return
+	}
 
 	// Sort by updated_at descending.
 	sort.Slice(entries, func(i, j int) bool {
@@ -559,23 +626,6 @@ func loadWebSummaries(path string) map[string]string {
 	return m
 }
 
-// countLines counts newlines in a file without parsing JSON. Returns 0 on error.
-func countLines(path string) int {
-	f, err := os.Open(path)
-	if err != nil {
-		return 0
-	}
-	defer f.Close()
-	count := 0
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		if len(scanner.Bytes()) > 0 {
-			count++
-		}
-	}
-	return count
-}
-
 // --- GET /api/sessions/{key...} ---
 
 type sessionDetail struct {
@@ -602,6 +652,10 @@ func (w *WebChannel) handleSessionMessages(rw http.ResponseWriter, r *http.Reque
 		http.Error(rw, "missing session key", http.StatusBadRequest)
 		return
 	}
+	if !canAccessSession(r, apiSessionKey(raw)) {
+		http.Error(rw, "forbidden", http.StatusForbidden)
+		return
+	}
 
 	// Route: /api/sessions/{key...}/system-prompt
 	// parseKeyFromPath converts "/" to ":", so the suffix becomes ":system-prompt".
@@ -623,13 +677,12 @@ func (w *WebChannel) handleSessionMessages(rw http.ResponseWriter, r *http.Reque
 	}
 	key := raw
 
-	path := w.resolveSessionFile(key, session.SessionFileName)
-	if path == "" {
+	if w.resolveSessionFile(key, session.SessionFileName) == "" {
 		http.Error(rw, "invalid session key", http.StatusBadRequest)
 		return
 	}
 
-	s, err := session.ReadFile(path)
+	s, err := w.readSession(key)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			http.Error(rw, "session not found", http.StatusNotFound)
@@ -738,12 +791,11 @@ type compressionStats struct {
 }
 
 func (w *WebChannel) handleSessionStats(rw http.ResponseWriter, key string) {
-	path := w.resolveSessionFile(key, session.SessionFileName)
-	if path == "" {
+	if w.resolveSessionFile(key, session.SessionFileName) == "" {
 		http.Error(rw, "invalid session key", http.StatusBadRequest)
 		return
 	}
-	s, err := session.ReadFile(path)
+	s, err := w.readSession(key)
 	if err != nil {
 		http.Error(rw, "session not found", http.StatusNotFound)
 		return
@@ -830,6 +882,10 @@ func (w *WebChannel) handleSessionStats(rw http.ResponseWriter, key string) {
 // --- GET /api/config ---
 
 func (w *WebChannel) handleConfig(rw http.ResponseWriter, r *http.Request) {
+	if webUserFrom(r.Context()) != "" {
+		http.Error(rw, "forbidden", http.StatusForbidden)
+		return
+	}
 	cfg, err := config.Load()
 	if err != nil {
 		http.Error(rw, fmt.Sprintf("failed to load config: %v", err), http.StatusInternalServerError)
@@ -896,6 +952,13 @@ func redactConfig(cfg *config.Config) {
 		if cfg.Channels.Discord != nil && cfg.Channels.Discord.Token != "" {
 			cfg.Channels.Discord.Token = redactedValue
 		}
+		if cfg.Channels.Web != nil {
+			for i := range cfg.Channels.Web.Users {
+				if cfg.Channels.Web.Users[i].Token != "" {
+					cfg.Channels.Web.Users[i].Token = redactedValue
+				}
+			}
+		}
 		if cfg.Channels.Feishu != nil {
 			if cfg.Channels.Feishu.AppSecret != "" {
 				cfg.Channels.Feishu.AppSecret = redactedValue
@@ -903,6 +966,10 @@ func redactConfig(cfg *config.Config) {
 		}
 	}
 
+	if cfg.API != nil && cfg.API.Token != "" {
+		cfg.API.Token = redactedValue
+	}
+
 	// Redact tool keys.
 	if cfg.Tools.Web.Fetch.JinaKey != "" {
 		cfg.Tools.Web.Fetch.JinaKey = redactedValue
@@ -932,6 +999,10 @@ func (w *WebChannel) handleHeartbeat(rw http.ResponseWriter, r *http.Request) {
 		http.Error(rw, "missing session key", http.StatusBadRequest)
 		return
 	}
+	if !canAccessSession(r, key) {
+		http.Error(rw, "forbidden", http.StatusForbidden)
+		return
+	}
 
 	path := w.resolveSessionFile(key, "heartbeat.md")
 	if path == "" {
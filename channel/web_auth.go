@@ -0,0 +1,137 @@
+package channel
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+// webTokenCookie carries the access token for requests the browser makes on
+// its own (WebSocket upgrade, fetch), set when the page is opened with ?token=.
+const webTokenCookie = "nagobot_token"
+
+type webUserKey struct{}
+
+// loadWebUsers validates configured logins, dropping names that are not
+// usable as a session key suffix.
+func loadWebUsers(users map[string]string) map[string]string {
+	out := make(map[string]string, len(users))
+	for token, name := range users {
+		if sanitizeSessionKey(name) == "" || strings.Contains(name, ":") {
+			logger.Warn("web channel: ignoring user with invalid name", "name", name)
+			continue
+		}
+		out[token] = name
+	}
+	return out
+}
+
+// multiUser reports whether connections must authenticate as a configured
+// user. It follows the config, not the valid entries, so a users list whose
+// names were all rejected locks the channel instead of opening it.
+func (w *WebChannel) multiUser() bool {
+	return w.usersConfigured || len(w.users) > 0
+}
+
+// authenticate returns the user a request's token belongs to. The token may
+// come from an "Authorization: Bearer" header, a token query parameter, or
+// the nagobot_token cookie.
+func (w *WebChannel) authenticate(r *http.Request) (string, bool) {
+	token := ""
+	if h, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = strings.TrimSpace(h)
+	}
+	if token == "" {
+		token = strings.TrimSpace(r.URL.Query().Get("token"))
+	}
+	if token == "" {
+		if c, err := r.Cookie(webTokenCookie); err == nil {
+			token = c.Value
+		}
+	}
+	if token == "" {
+		return "", false
+	}
+	return w.lookupToken(token)
+}
+
+// lookupToken returns the user a token belongs to, comparing against every
+// configured token in constant time.
+func (w *WebChannel) lookupToken(token string) (string, bool) {
+	user, found := "", false
+	for t, name := range w.users {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			user, found = name, true
+		}
+	}
+	return user, found
+}
+
+// authed wraps an API or WebSocket handler. In multi-user mode it rejects
+// requests without a valid token and records the user on the request
+// context; in local mode it passes requests through unchanged.
+func (w *WebChannel) authed(h http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if !w.multiUser() {
+			h(rw, r)
+			return
+		}
+		user, ok := w.authenticate(r)
+		if !ok {
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(rw, r.WithContext(context.WithValue(r.Context(), webUserKey{}, user)))
+	}
+}
+
+// withTokenCookie stores a valid ?token= in a cookie so the page's own
+// WebSocket and API requests authenticate without the frontend knowing.
+func (w *WebChannel) withTokenCookie(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if w.multiUser() {
+			if token := strings.TrimSpace(r.URL.Query().Get("token")); token != "" {
+				if _, ok := w.lookupToken(token); ok {
+					http.SetCookie(rw, &http.Cookie{
+						Name:     webTokenCookie,
+						Value:    token,
+						Path:     "/",
+						HttpOnly: true,
+						SameSite: http.SameSiteStrictMode,
+					})
+				}
+			}
+		}
+		h.ServeHTTP(rw, r)
+	})
+}
+
+// webUserFrom returns the authenticated user, or "" in local mode.
+func webUserFrom(ctx context.Context) string {
+	user, _ := ctx.Value(webUserKey{}).(string)
+	return user
+}
+
+// webUserSessionKey is the session an authenticated web user chats in.
+func webUserSessionKey(user string) string {
+	return "web:" + user
+}
+
+// homeSession returns the session a request's connection starts bound to:
+// the user's own session in multi-user mode, the shared cli session otherwise.
+func homeSession(r *http.Request) string {
+	if user := webUserFrom(r.Context()); user != "" {
+		return webUserSessionKey(user)
+	}
+	return webMainSessionID
+}
+
+// canAccessSession reports whether the request may read or chat in key.
+// Authenticated users are confined to their own session.
+func canAccessSession(r *http.Request, key string) bool {
+	user := webUserFrom(r.Context())
+	return user == "" || key == webUserSessionKey(user)
+}
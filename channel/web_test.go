@@ -1,6 +1,7 @@
 package channel
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -8,8 +9,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
 	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/provider"
 	"github.com/linanwx/nagobot/session"
 )
 
@@ -80,3 +85,107 @@ func TestHandleSessionStats_TierTriggerPercents(t *testing.T) {
 		t.Errorf("context_window_tokens = %v, want 200000", got)
 	}
 }
+
+func TestWebMultiUserConnectionsGetOwnSessions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Channels.Web = &config.WebChannelConfig{Users: []config.WebUserConfig{
+		{Name: "alice", Token: "tok-alice"},
+		{Name: "bob", Token: "tok-bob"},
+	}}
+	ch := NewWebChannel(cfg).(*WebChannel)
+	srv := httptest.NewServer(ch.authed(ch.handleWS))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, resp, err := websocket.Dial(ctx, wsURL, nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated dial: err=%v resp=%v, want 401", err, resp)
+	}
+
+	send := func(token, text, sessionID string) *Message {
+		t.Helper()
+		conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+			HTTPHeader: http.Header{"Authorization": []string{"Bearer " + token}},
+		})
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "")
+		if err := wsjson.Write(ctx, conn, webInboundMessage{Type: "message", Text: text, SessionID: sessionID}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		select {
+		case m := <-ch.messages:
+			return m
+		case <-ctx.Done():
+			t.Fatal("message never arrived")
+			return nil
+		}
+	}
+
+	a := send("tok-alice", "hi from alice", "")
+	// Bob tries to post into the shared cli session; he stays in his own.
+	b := send("tok-bob", "hi from bob", "cli")
+	if a.Metadata["web_user"] != "alice" || a.Metadata["chat_id"] != "web:alice" {
+		t.Errorf("alice message = %+v", a)
+	}
+	if b.Metadata["web_user"] != "bob" || b.Metadata["chat_id"] != "web:bob" {
+		t.Errorf("bob message = %+v", b)
+	}
+}
+
+func TestWebHistoryReadsSQLiteStore(t *testing.T) {
+	workspace := t.TempDir()
+	sessionsDir := filepath.Join(workspace, sessionsDirName)
+	store, err := session.OpenStore(session.BackendSQLite, sessionsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := session.NewManagerWithStore(sessionsDir, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sessions.Close()
+	if err := sessions.Append("web:alice", provider.UserMessage("hello from sqlite")); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := NewWebChannel(config.DefaultConfig()).(*WebChannel)
+	ch.workspace = workspace
+	ch.SetSessions(sessions)
+
+	history, err := ch.loadHistory("web:alice")
+	if err != nil {
+		t.Fatalf("loadHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Content != "hello from sqlite" {
+		t.Errorf("history = %+v, want the stored message", history)
+	}
+	if _, err := os.Stat(filepath.Join(sessions.Dir("web:alice"), session.SessionFileName)); err == nil {
+		t.Error("sqlite backend wrote a session.jsonl")
+	}
+}
+
+func TestWebUsersAllInvalidLocksChannel(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Channels.Web = &config.WebChannelConfig{Users: []config.WebUserConfig{
+		{Name: "bad:name", Token: "tok-bad"},
+		{Name: "nobody"}, // no token
+	}}
+	ch := NewWebChannel(cfg).(*WebChannel)
+	if !ch.multiUser() {
+		t.Fatal("configured users with no valid entry fell back to unauthenticated local mode")
+	}
+	if err := ch.Start(context.Background()); err == nil {
+		ch.Stop()
+		t.Fatal("Start succeeded with no valid web user")
+	}
+
+	rw := httptest.NewRecorder()
+	ch.authed(ch.handleConfig)(rw, httptest.NewRequest(http.MethodGet, "/api/config?token=tok-bad", nil))
+	if rw.Code != http.StatusUnauthorized {
+		t.Errorf("/api/config status = %d, want 401", rw.Code)
+	}
+}
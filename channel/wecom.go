@@ -13,7 +13,6 @@ import (
 	"math"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -102,16 +101,18 @@ type wecomMsgBody struct {
 // using the AI Bot WebSocket long connection (no public URL needed).
 type WeComChannel struct {
 	botID, secret  string
+	mu             sync.RWMutex // protects allowedUserIDs
 	allowedUserIDs map[string]bool
 	mediaDir       string
 
 	connMu sync.Mutex
 	conn   *websocket.Conn
 
-	messages chan *Message
-	done     chan struct{}
-	wg       sync.WaitGroup
-	stopOnce sync.Once
+	messages     chan *Message
+	backpressure backpressure
+	done         chan struct{}
+	wg           sync.WaitGroup
+	stopOnce     sync.Once
 
 	// dedup
 	seenMu sync.Mutex
@@ -153,12 +154,24 @@ func NewWeComChannel(cfg *config.Config) Channel {
 		allowedUserIDs: allowed,
 		mediaDir:       initMediaDir(cfg),
 		messages:       make(chan *Message, wecomMessageBufferSize),
+		backpressure:   newBackpressure(cfg, wecomMessageBufferSize),
 		done:           make(chan struct{}),
 		seen:           make(map[string]time.Time),
 		lastReqID:      make(map[string]reqIDEntry),
 	}
 }
 
+// Reconfigure updates the user allowlist from a fresh config snapshot.
+func (w *WeComChannel) Reconfigure(cfg *config.Config) {
+	allowed := make(map[string]bool)
+	for _, id := range cfg.GetWeComAllowedUserIDs() {
+		allowed[id] = true
+	}
+	w.mu.Lock()
+	w.allowedUserIDs = allowed
+	w.mu.Unlock()
+}
+
 func (w *WeComChannel) Name() string             { return "wecom" }
 func (w *WeComChannel) Messages() <-chan *Message { return w.messages }
 
@@ -447,7 +460,10 @@ func (w *WeComChannel) handleMsgCallback(frame wsFrame) {
 	}
 
 	// Allowed user check.
-	if len(w.allowedUserIDs) > 0 && !w.allowedUserIDs[body.From.UserID] {
+	w.mu.RLock()
+	allowed := w.allowedUserIDs
+	w.mu.RUnlock()
+	if len(allowed) > 0 && !allowed[body.From.UserID] {
 		logger.Warn("wecom: message from unauthorized user", "userid", body.From.UserID)
 		return
 	}
@@ -524,11 +540,7 @@ func (w *WeComChannel) handleMsgCallback(frame wsFrame) {
 		msg.Text = fmt.Sprintf("[Unsupported message type: %s]", body.MsgType)
 	}
 
-	select {
-	case w.messages <- msg:
-	default:
-		logger.Warn("wecom: message buffer full, dropping message")
-	}
+	w.backpressure.enqueue(w, w.messages, w.done, msg)
 }
 
 func (w *WeComChannel) handleMixedMsg(items []struct {
@@ -684,62 +696,52 @@ func generateReqID(prefix string) string {
 	return fmt.Sprintf("%s_%d_%s", prefix, time.Now().UnixMilli(), hex.EncodeToString(buf))
 }
 
-// downloadWeComMedia downloads and decrypts an AES-encrypted media file from WeCom.
+// downloadWeComMedia downloads and decrypts an AES-encrypted media file from
+// WeCom. A URL downloaded before is served from disk.
 func downloadWeComMedia(mediaDir, url, aesKey string) string {
-	if mediaDir == "" || url == "" {
-		return ""
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		logger.Warn("wecom: failed to download media", "err", err)
-		return ""
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		logger.Warn("wecom: media download returned non-200", "status", resp.StatusCode)
-		return ""
-	}
+	return cachedMedia(mediaDir, url, func(f *os.File) (string, string, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			logger.Warn("wecom: failed to download media", "err", err)
+			return "", "", err
+		}
+		defer resp.Body.Close()
 
-	const maxMediaSize = 20 << 20 // 20 MB
-	encrypted, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaSize))
-	if err != nil {
-		logger.Warn("wecom: failed to read media", "err", err)
-		return ""
-	}
+		if resp.StatusCode != http.StatusOK {
+			logger.Warn("wecom: media download returned non-200", "status", resp.StatusCode)
+			return "", "", fmt.Errorf("status %d", resp.StatusCode)
+		}
 
-	var content []byte
-	if aesKey != "" {
-		content, err = decryptWeComFile(encrypted, aesKey)
+		const maxMediaSize = 20 << 20 // 20 MB
+		encrypted, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaSize))
 		if err != nil {
-			logger.Warn("wecom: failed to decrypt media", "err", err)
-			return ""
+			logger.Warn("wecom: failed to read media", "err", err)
+			return "", "", err
 		}
-	} else {
-		content = encrypted
-	}
-
-	// Detect extension from content type or default.
-	ext := extensionFromContentType(resp.Header.Get("Content-Type"))
-	if ext == "" {
-		ext = detectExtFromMagic(content)
-	}
-	if ext == "" {
-		ext = ".dat"
-	}
 
-	buf := make([]byte, 4)
-	rand.Read(buf)
-	fileName := fmt.Sprintf("wecom-%s-%s%s", time.Now().Format("20060102-150405"), hex.EncodeToString(buf), ext)
-	filePath := filepath.Join(mediaDir, fileName)
+		var content []byte
+		if aesKey != "" {
+			content, err = decryptWeComFile(encrypted, aesKey)
+			if err != nil {
+				logger.Warn("wecom: failed to decrypt media", "err", err)
+				return "", "", err
+			}
+		} else {
+			content = encrypted
+		}
 
-	if err := os.WriteFile(filePath, content, 0644); err != nil {
-		logger.Warn("wecom: failed to write media file", "err", err)
-		return ""
-	}
+		// Detect extension from content type or default.
+		ext := extensionFromContentType(resp.Header.Get("Content-Type"))
+		if ext == "" {
+			ext = detectExtFromMagic(content)
+		}
 
-	return filePath
+		if _, err := f.Write(content); err != nil {
+			logger.Warn("wecom: failed to write media file", "err", err)
+			return "", "", err
+		}
+		return "wecom", ext, nil
+	})
 }
 
 // decryptWeComFile decrypts AES-256-CBC encrypted data with PKCS#7 padding (block size 32).
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/linanwx/nagobot/agent"
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tools"
+	"github.com/spf13/cobra"
+)
+
+var agentsCmd = &cobra.Command{
+	Use:     "agents",
+	Short:   "List and scaffold agent templates",
+	GroupID: "internal",
+}
+
+// agentNameRe restricts scaffolded agent names to safe file names.
+var agentNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// agentListing is one row of `agents list`.
+type agentListing struct {
+	Name        string
+	Source      string // "user" (agents/) or "builtin" (agents-builtin/)
+	Specialty   string
+	Route       string // resolved provider/model, or "default"
+	Unmapped    bool   // specialty declared but not routable; falls back to the default model
+	Description string
+}
+
+// --- list ---
+
+var agentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agent templates with their model routing",
+	Args:  cobra.NoArgs,
+	RunE:  runAgentsList,
+}
+
+func init() {
+	agentsCmd.AddCommand(agentsListCmd)
+}
+
+func runAgentsList(_ *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	workspace, err := cfg.WorkspacePath()
+	if err != nil {
+		return fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	listings := listAgents(workspace, cfg.Thread.Models)
+	unmapped := 0
+	for _, l := range listings {
+		if l.Unmapped {
+			unmapped++
+		}
+	}
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", "agents list"}, {"status", "ok"},
+		{"count", fmt.Sprintf("%d", len(listings))},
+		{"unmapped", fmt.Sprintf("%d", unmapped)},
+	}, "") + "\n")
+	fmt.Printf("NAME\tSOURCE\tSPECIALTY\tMODEL\tDESCRIPTION\n")
+	for _, l := range listings {
+		model := l.Route
+		if l.Unmapped {
+			model = "UNMAPPED (default)"
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", l.Name, l.Source, l.Specialty, model, l.Description)
+	}
+	if unmapped > 0 {
+		fmt.Printf("\nUNMAPPED agents declare a specialty with no thread.models entry and run on the default model.\n" +
+			"Fix: nagobot set-model --type <specialty> --provider <provider> --model <model>\n")
+	}
+	return nil
+}
+
+// listAgents loads every template under workspace and resolves its specialty
+// against models the same way threads do.
+func listAgents(workspace string, models map[string]*config.ModelConfig) []agentListing {
+	var out []agentListing
+	for _, def := range agent.NewRegistry(workspace).Defs() {
+		l := agentListing{
+			Name:        def.Name,
+			Source:      "user",
+			Specialty:   def.Specialty,
+			Route:       "default",
+			Description: def.Description,
+		}
+		if filepath.Base(filepath.Dir(def.Path)) == "agents-builtin" {
+			l.Source = "builtin"
+		}
+		if mc := provider.RouteSpecialty(models, def.Specialty, def.Provider); mc != nil {
+			l.Route = mc.Provider + "/" + mc.ModelType
+		} else if def.Specialty != "" {
+			l.Unmapped = true
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// --- new ---
+
+var agentsNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Scaffold a new agent template in agents/",
+	Long: `Create agents/<name>.md with standard frontmatter and a starter prompt.
+
+Examples:
+  nagobot agents new translator --description "Translates text between languages"
+  nagobot agents new reviewer --specialty code`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentsNew,
+}
+
+var (
+	agentsNewDescription string
+	agentsNewSpecialty   string
+	agentsNewForce       bool
+)
+
+func init() {
+	agentsNewCmd.Flags().StringVar(&agentsNewDescription, "description", "", "One-line description shown to other agents when choosing a subagent")
+	agentsNewCmd.Flags().StringVar(&agentsNewSpecialty, "specialty", "toolcall", "Specialty used for model routing (thread.models key or provider/model)")
+	agentsNewCmd.Flags().BoolVar(&agentsNewForce, "force", false, "Overwrite an existing template")
+	agentsCmd.AddCommand(agentsNewCmd)
+}
+
+func runAgentsNew(_ *cobra.Command, args []string) error {
+	name := strings.TrimSpace(args[0])
+	if !agentNameRe.MatchString(name) {
+		return fmt.Errorf("invalid agent name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	workspace, err := cfg.WorkspacePath()
+	if err != nil {
+		return fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	specialty := strings.TrimSpace(agentsNewSpecialty)
+	path, err := scaffoldAgent(workspace, name, strings.TrimSpace(agentsNewDescription), specialty, agentsNewForce)
+	if err != nil {
+		return err
+	}
+	route := "default"
+	if mc := provider.RouteSpecialty(cfg.Thread.Models, specialty, ""); mc != nil {
+		route = mc.Provider + "/" + mc.ModelType
+	}
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", "agents new"}, {"status", "created"},
+		{"agent", name}, {"path", path}, {"specialty", specialty}, {"model", route},
+	}, "") + "\n")
+	fmt.Printf("Edit %s to write the agent's prompt.\n", path)
+	return nil
+}
+
+// scaffoldAgent writes agents/<name>.md. Existing templates are only
+// replaced when force is set.
+func scaffoldAgent(workspace, name, description, specialty string, force bool) (string, error) {
+	path := filepath.Join(workspace, "agents", name+".md")
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("agent template already exists: %s (use --force to overwrite)", path)
+	}
+	if description == "" {
+		description = "TODO: one line on what this agent does and when to pick it."
+	}
+	title := strings.ToUpper(name[:1]) + name[1:]
+
+	// Free-text values are quoted: a description like "TODO: ..." holds
+	// ": " and is not a valid plain YAML scalar.
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "name: %s\n", name)
+	fmt.Fprintf(&b, "description: %s\n", strconv.Quote(description))
+	if specialty != "" {
+		fmt.Fprintf(&b, "specialty: %s\n", strconv.Quote(specialty))
+	}
+	b.WriteString("sections:\n  - user_memory_section\n")
+	b.WriteString("---\n\n")
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "You are %s, an agent within the nagobot agent family.\n\n", name)
+	b.WriteString("## Instructions\n\n")
+	b.WriteString("- TODO: describe the agent's responsibilities.\n")
+	b.WriteString("- Use tools when needed.\n")
+	b.WriteString("- Report results and any problems you ran into.\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create agents dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write agent template: %w", err)
+	}
+	return path, nil
+}
+
+// --- register root ---
+
+func init() {
+	rootCmd.AddCommand(agentsCmd)
+}
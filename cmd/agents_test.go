@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/agent"
+	"github.com/linanwx/nagobot/config"
+)
+
+func TestListAgentsFlagsUnmappedSpecialty(t *testing.T) {
+	ws := t.TempDir()
+	dir := filepath.Join(ws, "agents")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("coder", "---\nname: coder\ndescription: Writes code\nspecialty: code\n---\n# Coder\n")
+	write("poet", "---\nname: poet\ndescription: Writes poems\nspecialty: poetry\n---\n# Poet\n")
+	write("plain", "---\nname: plain\ndescription: No specialty\n---\n# Plain\n")
+
+	models := map[string]*config.ModelConfig{
+		"code": {Provider: "deepseek", ModelType: "deepseek-chat"},
+	}
+	got := map[string]agentListing{}
+	for _, l := range listAgents(ws, models) {
+		got[l.Name] = l
+	}
+
+	if l, ok := got["coder"]; !ok || l.Unmapped || l.Route != "deepseek/deepseek-chat" || l.Source != "user" {
+		t.Errorf("coder = %+v, want routed to deepseek/deepseek-chat", l)
+	}
+	if l, ok := got["poet"]; !ok || !l.Unmapped || l.Route != "default" {
+		t.Errorf("poet = %+v, want unmapped", l)
+	}
+	if l, ok := got["plain"]; !ok || l.Unmapped || l.Route != "default" {
+		t.Errorf("plain = %+v, want default without flag", l)
+	}
+}
+
+func TestScaffoldAgentRefusesOverwrite(t *testing.T) {
+	ws := t.TempDir()
+	path, err := scaffoldAgent(ws, "translator", "Translates text", "toolcall", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := listAgents(ws, nil)
+	if len(l) != 1 || l[0].Name != "translator" || l[0].Description != "Translates text" || l[0].Specialty != "toolcall" {
+		t.Fatalf("scaffolded listing = %+v", l)
+	}
+	if _, err := scaffoldAgent(ws, "translator", "", "", false); err == nil {
+		t.Errorf("second scaffold of %s should fail without force", path)
+	}
+	if _, err := scaffoldAgent(ws, "translator", "", "", true); err != nil {
+		t.Errorf("force scaffold: %v", err)
+	}
+}
+
+func TestScaffoldAgentDefaultParses(t *testing.T) {
+	ws := t.TempDir()
+	path, err := scaffoldAgent(ws, "helper", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, _, hasHeader, err := agent.ParseTemplate(string(data))
+	if err != nil || !hasHeader {
+		t.Fatalf("ParseTemplate() = %v, header %v; want the default scaffold to parse", err, hasHeader)
+	}
+	if meta.Name != "helper" || !strings.HasPrefix(meta.Description, "TODO: ") {
+		t.Errorf("meta = %+v", meta)
+	}
+}
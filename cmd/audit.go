@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/tools"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the tool-call audit log",
+	Long: `Read the per-session JSONL audit log of tool calls.
+
+Enable it in config.yaml:
+  logging:
+    audit:
+      enabled: true
+      dir: logs/audit        # optional, relative to the workspace
+      redactTools: [exec]    # optional, record only argument names for these tools`,
+}
+
+var auditTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show the most recent audited tool calls",
+	Long: `Show the most recent audited tool calls, oldest first.
+
+Examples:
+  nagobot audit tail
+  nagobot audit tail --session "telegram:12345" -n 50`,
+	RunE: runAuditTail,
+}
+
+var (
+	auditTailSession string
+	auditTailLines   int
+)
+
+func init() {
+	auditTailCmd.Flags().StringVar(&auditTailSession, "session", "", "Session key (default: all sessions)")
+	auditTailCmd.Flags().IntVarP(&auditTailLines, "lines", "n", 20, "Number of entries to show")
+	auditCmd.AddCommand(auditTailCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAuditTail(_ *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	dir, err := cfg.AuditLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get audit log dir: %w", err)
+	}
+
+	session := strings.TrimSpace(auditTailSession)
+	entries, err := tools.ReadAuditTail(dir, session, auditTailLines)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, e := range entries {
+		status := "ok"
+		if !e.OK {
+			status = "error"
+		}
+		key := e.SessionKey
+		if key == "" {
+			key = "-"
+		}
+		fmt.Fprintf(&sb, "%s  %s  %s  %s  %dms  %s\n",
+			e.Time.Format("2006-01-02 15:04:05"), key, e.Tool, status, e.DurationMs, string(e.Args))
+	}
+	if len(entries) == 0 {
+		sb.WriteString("No audit entries found.")
+		if !cfg.GetAuditLogEnabled() {
+			sb.WriteString(" The audit log is disabled; set logging.audit.enabled: true in config.yaml.")
+		}
+		sb.WriteString("\n")
+	}
+
+	scope := session
+	if scope == "" {
+		scope = "all"
+	}
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", "audit tail"}, {"session", scope}, {"dir", dir}, {"shown", fmt.Sprint(len(entries))},
+	}, sb.String()))
+	return nil
+}
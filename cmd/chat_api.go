@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/thread"
+)
+
+const (
+	chatAPIDefaultTimeout       = 5 * time.Minute
+	chatAPIDefaultMaxConcurrent = 4
+	chatAPISessionPrefix        = "api:"
+	chatAPIDefaultSession       = chatAPISessionPrefix + "default"
+	chatAPIMaxBodyBytes         = 1 << 20
+)
+
+// chatAPI serves POST /v1/chat: each request wakes a session with its
+// message and answers with the turn's reply once the turn finishes. Unlike
+// channels, the caller holds the connection open for the reply.
+type chatAPI struct {
+	threads *thread.Manager
+	token   string
+	timeout time.Duration
+	slots   chan struct{} // one per turn in flight, across sessions
+
+	mu   sync.Mutex
+	busy map[string]bool // sessions with a request in flight
+}
+
+func newChatAPI(threads *thread.Manager, token string, timeout time.Duration, maxConcurrent int) *chatAPI {
+	if timeout <= 0 {
+		timeout = chatAPIDefaultTimeout
+	}
+	if maxConcurrent <= 0 {
+		maxConcurrent = chatAPIDefaultMaxConcurrent
+	}
+	return &chatAPI{
+		threads: threads,
+		token:   token,
+		timeout: timeout,
+		slots:   make(chan struct{}, maxConcurrent),
+		busy:    make(map[string]bool),
+	}
+}
+
+type chatAPIRequest struct {
+	Session string `json:"session,omitempty"`
+	Message string `json:"message"`
+}
+
+type chatAPIResponse struct {
+	Session  string           `json:"session"`
+	Response string           `json:"response"`
+	Usage    thread.TurnUsage `json:"usage"`
+}
+
+func (a *chatAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat", a.handleChat)
+	return mux
+}
+
+func (a *chatAPI) handleChat(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		writeChatAPIError(rw, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if a.token == "" || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(a.token)) != 1 {
+		writeChatAPIError(rw, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req chatAPIRequest
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, chatAPIMaxBodyBytes)).Decode(&req); err != nil {
+		writeChatAPIError(rw, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		writeChatAPIError(rw, http.StatusBadRequest, "message is required")
+		return
+	}
+	key, err := chatAPISessionKey(req.Session)
+	if err != nil {
+		writeChatAPIError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	select {
+	case a.slots <- struct{}{}:
+		defer func() { <-a.slots }()
+	default:
+		writeChatAPIError(rw, http.StatusTooManyRequests, "too many requests in flight; retry later")
+		return
+	}
+	// Queued wakes of one session can be merged into a single turn, which
+	// would leave all but one request without a result.
+	if !a.claim(key) {
+		writeChatAPIError(rw, http.StatusConflict, fmt.Sprintf("session %q already has a request in flight", key))
+		return
+	}
+	defer a.release(key)
+
+	ctx, cancel := context.WithTimeout(r.Context(), a.timeout)
+	defer cancel()
+	done := make(chan thread.TurnResult, 1)
+	var sentMu sync.Mutex
+	var sent []string
+	a.threads.Wake(key, &thread.WakeMessage{
+		Source:  thread.WakeAPI,
+		Message: req.Message,
+		// The reply goes back in the HTTP response, never to a channel.
+		// Replies sent with dispatch are kept in case the turn's own
+		// response is empty.
+		Sink: thread.Sink{
+			Label: "the HTTP API caller; your reply is returned as the response",
+			Send: func(_ context.Context, text string) error {
+				sentMu.Lock()
+				sent = append(sent, text)
+				sentMu.Unlock()
+				return nil
+			},
+		},
+		OnResult: func(res thread.TurnResult) { done <- res },
+	})
+
+	select {
+	case res := <-done:
+		if res.Err != nil {
+			writeChatAPIError(rw, http.StatusInternalServerError, res.Err.Error())
+			return
+		}
+		reply := strings.TrimSpace(res.Response)
+		if reply == "" {
+			sentMu.Lock()
+			reply = strings.TrimSpace(strings.Join(sent, "\n\n"))
+			sentMu.Unlock()
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(chatAPIResponse{Session: key, Response: reply, Usage: res.Usage})
+	case <-ctx.Done():
+		// The turn keeps running and is saved to the session; only the wait ends.
+		writeChatAPIError(rw, http.StatusGatewayTimeout, fmt.Sprintf("turn did not finish within %s", a.timeout))
+	}
+}
+
+func (a *chatAPI) claim(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.busy[key] {
+		return false
+	}
+	a.busy[key] = true
+	return true
+}
+
+func (a *chatAPI) release(key string) {
+	a.mu.Lock()
+	delete(a.busy, key)
+	a.mu.Unlock()
+}
+
+// chatAPISessionKey confines the requested session to the api: namespace,
+// so API callers cannot write into channel, cron or CLI sessions.
+func chatAPISessionKey(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return chatAPIDefaultSession, nil
+	}
+	if !strings.HasPrefix(raw, chatAPISessionPrefix) {
+		raw = chatAPISessionPrefix + raw
+	}
+	return session.ValidateSessionKey(raw)
+}
+
+func writeChatAPIError(rw http.ResponseWriter, status int, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(map[string]string{"error": msg})
+}
+
+// startChatAPI serves the HTTP chat API when api.addr is configured, until
+// ctx is done. It refuses to start without api.token.
+func startChatAPI(ctx context.Context, cfg *config.Config, threads *thread.Manager) {
+	addr := cfg.GetAPIAddr()
+	if addr == "" {
+		return
+	}
+	if cfg.GetAPIToken() == "" {
+		logger.Warn("chat API not started: api.token is required", "addr", addr)
+		return
+	}
+	api := newChatAPI(threads, cfg.GetAPIToken(), cfg.GetAPITimeout(), cfg.GetAPIMaxConcurrent())
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Error("chat API listen failed", "addr", addr, "err", err)
+		return
+	}
+	server := &http.Server{Handler: api.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	logger.Info("chat API started", "addr", ln.Addr().String())
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("chat API server error", "err", err)
+		}
+	}()
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/thread"
+)
+
+func TestChatAPIReturnsTurnReply(t *testing.T) {
+	p := &stubRunProvider{resp: &provider.Response{
+		Content: "Paris",
+		Usage:   provider.Usage{PromptTokens: 40, CompletionTokens: 1, TotalTokens: 41},
+	}}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	srv := httptest.NewServer(newChatAPI(mgr, "s3cret", 0, 0).handler())
+	defer srv.Close()
+
+	post := func(token, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/chat", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := post("wrong", `{"message":"hi"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("bad token: status %d, want 401", resp.StatusCode)
+	}
+	if resp := post("s3cret", `{"session":"../etc","message":"hi"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad session key: status %d, want 400", resp.StatusCode)
+	}
+
+	resp := post("s3cret", `{"session":"api:test","message":"capital of France?"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", resp.StatusCode)
+	}
+	var got chatAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Session != "api:test" || got.Response != "Paris" || got.Usage.TotalTokens != 41 {
+		t.Errorf("response = %+v", got)
+	}
+}
+
+func TestChatAPISessionKeyStaysInAPINamespace(t *testing.T) {
+	for raw, want := range map[string]string{
+		"":             "api:default",
+		"api:test":     "api:test",
+		"cli":          "api:cli",
+		"telegram:42":  "api:telegram:42",
+		" cron:daily ": "api:cron:daily",
+	} {
+		if got, err := chatAPISessionKey(raw); err != nil || got != want {
+			t.Errorf("chatAPISessionKey(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	if _, err := chatAPISessionKey("../etc"); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+}
@@ -23,17 +23,22 @@ var cliClientCmd = &cobra.Command{
 	RunE:  runCLIClient,
 }
 
-var cliMessageFlag string
+var (
+	cliMessageFlag string
+	cliDryRunFlag  bool
+)
 
 func init() {
 	rootCmd.AddCommand(cliClientCmd)
 	cliClientCmd.Flags().StringVarP(&cliMessageFlag, "message", "m", "", "Send a single message and exit (one-shot mode)")
+	cliClientCmd.Flags().BoolVar(&cliDryRunFlag, "dry-run", false, "Simulate mutating tools (write_file, edit_file, exec) instead of running them")
 }
 
 // socketInbound mirrors channel.socketInbound for the client side.
 type socketInbound struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	DryRun bool   `json:"dry_run,omitempty"`
 }
 
 func runCLIClient(cmd *cobra.Command, args []string) error {
@@ -51,7 +56,7 @@ func runCLIClient(cmd *cobra.Command, args []string) error {
 	// One-shot mode: send message, wait for response, exit.
 	if cliMessageFlag != "" {
 		encoder := json.NewEncoder(conn)
-		if err := encoder.Encode(socketInbound{Type: "message", Text: cliMessageFlag}); err != nil {
+		if err := encoder.Encode(socketInbound{Type: "message", Text: cliMessageFlag, DryRun: cliDryRunFlag}); err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
 		decoder := json.NewDecoder(conn)
@@ -157,7 +162,7 @@ func runCLIClient(cmd *cobra.Command, args []string) error {
 				return
 			}
 
-			if err := encoder.Encode(socketInbound{Type: "message", Text: text}); err != nil {
+			if err := encoder.Encode(socketInbound{Type: "message", Text: text, DryRun: cliDryRunFlag}); err != nil {
 				return
 			}
 		}
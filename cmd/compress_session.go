@@ -46,32 +46,39 @@ func runCompressSession(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("input file required (or use --clear)")
 	}
 
+	// The path names the session; its messages live in the configured store,
+	// which need not be the session.jsonl file itself.
+	cfg, cfgErr := config.Load()
+	backend := ""
+	if cfgErr == nil {
+		backend = cfg.GetSessionBackend()
+	}
+	sessionDir := filepath.Dir(sessionFile)
+	key := session.DeriveKeyFromPath(sessionFile)
+	sessions, err := openSessions(backend, sessionsRootOf(sessionDir, key))
+	if err != nil {
+		return err
+	}
+	defer sessions.Close()
+
 	// 1. Read original session (raw — no sanitize, preserves in-progress tool calls).
-	orig, err := session.ReadFileRaw(sessionFile)
+	if !sessions.Exists(key) {
+		return fmt.Errorf("failed to read session file: session %q not found", key)
+	}
+	orig, err := sessions.GetRaw(key)
 	if err != nil {
 		return fmt.Errorf("failed to read session file: %w", err)
 	}
-	orig.Key = session.DeriveKeyFromPath(sessionFile)
 	origCount := len(orig.Messages)
 	var content string // summary text, set in compress path
 	origMessages := make([]provider.Message, origCount)
 	copy(origMessages, orig.Messages)
 
 	// 2. Backup original.
-	origData, err := os.ReadFile(sessionFile)
-	if err != nil {
-		return fmt.Errorf("failed to read session file for backup: %w", err)
-	}
-	sessionDir := filepath.Dir(sessionFile)
-	historyDir := filepath.Join(sessionDir, "history")
-	if err := os.MkdirAll(historyDir, 0755); err != nil {
-		return fmt.Errorf("failed to create history directory: %w", err)
-	}
 	now := time.Now()
-	timestamp := fmt.Sprintf("%d_%s", now.Unix(), now.Format("20060102T150405-0700"))
-	backupPath := filepath.Join(historyDir, timestamp+".jsonl")
-	if err := os.WriteFile(backupPath, origData, 0644); err != nil {
-		return fmt.Errorf("failed to write backup: %w", err)
+	backupPath, err := backupSession(sessionDir, orig, now)
+	if err != nil {
+		return err
 	}
 
 	// 3. Build new messages.
@@ -89,55 +96,26 @@ func runCompressSession(_ *cobra.Command, args []string) error {
 		}
 
 		tailCount := origCount / 4
-		cutoff := origCount - tailCount
-		// Adjust cutoff to avoid splitting a tool_calls→tool sequence.
-		for cutoff > 0 && cutoff < origCount && orig.Messages[cutoff].Role == "tool" {
-			cutoff--
-		}
-		if cutoff > 0 && cutoff < origCount && orig.Messages[cutoff-1].Role == "assistant" && len(orig.Messages[cutoff-1].ToolCalls) > 0 {
-			cutoff--
-		}
-		// Ensure tail starts with a user message (required by some providers).
-		for cutoff < origCount && orig.Messages[cutoff].Role != "user" {
-			cutoff++
-		}
+		cutoff := safeTailCutoff(orig.Messages, origCount-tailCount)
 		tail := orig.Messages[cutoff:]
 		newMessages := make([]provider.Message, 0, len(tail))
 		newMessages = append(newMessages, tail...)
 		orig.Messages = newMessages
 
 		// 4. Append summary to daily memory file.
-		memoryDir := filepath.Join(sessionDir, "memory")
-		if err := os.MkdirAll(memoryDir, 0755); err != nil {
-			logger.Warn("compress-session: failed to create memory directory", "err", err)
-		} else {
-			memoryFile := filepath.Join(memoryDir, now.Format("2006-01-02")+".md")
-			f, err := os.OpenFile(memoryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err != nil {
-				logger.Warn("compress-session: failed to open memory file", "err", err)
-			} else {
-				defer f.Close()
-				// Use separator newline only when appending to existing content.
-				info, _ := f.Stat()
-				sep := ""
-				if info != nil && info.Size() > 0 {
-					sep = "\n"
-				}
-				header := fmt.Sprintf("%s## Compression %s\n\n", sep, now.Format("15:04"))
-				_, _ = f.WriteString(header + content + "\n")
-			}
+		if _, err := session.AppendMemory(sessionDir, "Compression", content, now); err != nil {
+			logger.Warn("compress-session: failed to append memory", "err", err)
 		}
 
 		_ = os.Remove(inputFile)
 	}
 
-	if err := session.WriteFile(sessionFile, orig); err != nil {
+	if err := sessions.Save(orig); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
 	// 5. Record compression metrics.
 	record := buildCompressionRecord(now, orig.Key, origMessages, len(orig.Messages))
-	cfg, cfgErr := config.Load()
 	if cfgErr == nil {
 		if ws, wsErr := cfg.WorkspacePath(); wsErr == nil {
 			store := monitor.NewStore(filepath.Join(ws, "metrics"))
@@ -161,6 +139,64 @@ func runCompressSession(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// backupSession writes s as JSONL to <session_dir>/history/ and returns the
+// backup path. The backup is a file whatever the session backend.
+func backupSession(sessionDir string, s *session.Session, now time.Time) (string, error) {
+	historyDir := filepath.Join(sessionDir, "history")
+	timestamp := fmt.Sprintf("%d_%s", now.Unix(), now.Format("20060102T150405-0700"))
+	backupPath := filepath.Join(historyDir, timestamp+".jsonl")
+	if err := session.WriteFile(backupPath, s); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	return backupPath, nil
+}
+
+// openSessions opens a session manager over the given backend ("" = file).
+func openSessions(backend, sessionsDir string) (*session.Manager, error) {
+	store, err := session.OpenStore(backend, sessionsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session store: %w", err)
+	}
+	return session.NewManagerWithStore(sessionsDir, store)
+}
+
+// openConfigSessions opens the session store configured in cfg, so CLI
+// readers see the same sessions as serve whatever the backend.
+func openConfigSessions(cfg *config.Config) (*session.Manager, error) {
+	sessionsDir, err := cfg.SessionsDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions dir: %w", err)
+	}
+	return openSessions(cfg.GetSessionBackend(), sessionsDir)
+}
+
+// sessionsRootOf returns the sessions directory that sessionDir, the
+// directory of session key, lives in.
+func sessionsRootOf(sessionDir, key string) string {
+	for range strings.Split(key, ":") {
+		sessionDir = filepath.Dir(sessionDir)
+	}
+	return sessionDir
+}
+
+// safeTailCutoff moves cutoff so that messages[cutoff:] neither splits a
+// tool_calls→tool sequence nor starts with anything but a user message
+// (required by some providers). Returns len(messages) if no user message
+// follows.
+func safeTailCutoff(messages []provider.Message, cutoff int) int {
+	n := len(messages)
+	for cutoff > 0 && cutoff < n && messages[cutoff].Role == "tool" {
+		cutoff--
+	}
+	if cutoff > 0 && cutoff < n && messages[cutoff-1].Role == "assistant" && len(messages[cutoff-1].ToolCalls) > 0 {
+		cutoff--
+	}
+	for cutoff < n && messages[cutoff].Role != "user" {
+		cutoff++
+	}
+	return cutoff
+}
+
 func buildCompressionRecord(ts time.Time, sessionKey string, messages []provider.Message, messagesAfter int) monitor.CompressionRecord {
 	roleCounts := map[string]int{}
 	var totalChars, maxChars int
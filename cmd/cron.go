@@ -28,15 +28,17 @@ var setCronCmd = &cobra.Command{
 }
 
 var (
-	setCronID   string
-	setCronExpr string
-	setCronTask string
+	setCronID        string
+	setCronExpr      string
+	setCronTask      string
+	setCronSkipDates []string
 )
 
 func init() {
 	setCronCmd.Flags().StringVar(&setCronID, "id", "", "Unique job ID (required)")
 	setCronCmd.Flags().StringVar(&setCronExpr, "expr", "", "Cron expression, 5-field (required)")
 	setCronCmd.Flags().StringVar(&setCronTask, "task", "", "Task prompt for the job (required)")
+	setCronCmd.Flags().StringSliceVar(&setCronSkipDates, "skip-dates", nil, "Comma-separated YYYY-MM-DD dates on which the job does not fire (e.g. holidays)")
 	_ = setCronCmd.MarkFlagRequired("id")
 	_ = setCronCmd.MarkFlagRequired("expr")
 	_ = setCronCmd.MarkFlagRequired("task")
@@ -49,11 +51,15 @@ func runSetCron(_ *cobra.Command, _ []string) error {
 	if _, err := robfigcron.ParseStandard(expr); err != nil {
 		return fmt.Errorf("invalid cron expression %q: %w", expr, err)
 	}
+	if err := cronsvc.ValidateSkipDates(setCronSkipDates); err != nil {
+		return err
+	}
 	job := cronsvc.Job{
-		ID:   setCronID,
-		Kind: cronsvc.JobKindCron,
-		Expr: expr,
-		Task: setCronTask,
+		ID:        setCronID,
+		Kind:      cronsvc.JobKindCron,
+		Expr:      expr,
+		Task:      setCronTask,
+		SkipDates: setCronSkipDates,
 	}
 	if err := applyCommonJobFlags(&job); err != nil {
 		return err
@@ -66,10 +72,14 @@ func runSetCron(_ *cobra.Command, _ []string) error {
 	if updated {
 		action = "updated"
 	}
-	fmt.Print(tools.CmdOutput([][2]string{
+	fields := [][2]string{
 		{"command", "cron set-cron"}, {"status", action},
 		{"job_id", job.ID}, {"kind", "cron"}, {"schedule", job.Expr},
-	}, ""))
+	}
+	if len(job.SkipDates) > 0 {
+		fields = append(fields, [2]string{"skip_dates", strings.Join(job.SkipDates, ",")})
+	}
+	fmt.Print(tools.CmdOutput(fields, ""))
 	return nil
 }
 
@@ -82,15 +92,17 @@ var setAtCmd = &cobra.Command{
 }
 
 var (
-	setAtID   string
-	setAtTime string
-	setAtTask string
+	setAtID      string
+	setAtTime    string
+	setAtTask    string
+	setAtCatchUp bool
 )
 
 func init() {
 	setAtCmd.Flags().StringVar(&setAtID, "id", "", "Unique job ID (required)")
 	setAtCmd.Flags().StringVar(&setAtTime, "at", "", "Execution time in RFC3339 (required)")
 	setAtCmd.Flags().StringVar(&setAtTask, "task", "", "Task prompt for the job (required)")
+	setAtCmd.Flags().BoolVar(&setAtCatchUp, "catch-up", false, "If the server is down at --at, run once on next startup instead of discarding the job")
 	_ = setAtCmd.MarkFlagRequired("id")
 	_ = setAtCmd.MarkFlagRequired("at")
 	_ = setAtCmd.MarkFlagRequired("task")
@@ -104,10 +116,11 @@ func runSetAt(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("invalid --at time %q: %w", setAtTime, err)
 	}
 	job := cronsvc.Job{
-		ID:     setAtID,
-		Kind:   cronsvc.JobKindAt,
-		AtTime: &t,
-		Task:   setAtTask,
+		ID:      setAtID,
+		Kind:    cronsvc.JobKindAt,
+		AtTime:  &t,
+		Task:    setAtTask,
+		CatchUp: setAtCatchUp,
 	}
 	if err := applyCommonJobFlags(&job); err != nil {
 		return err
@@ -145,30 +158,25 @@ func runCronRemove(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	jobs, err := cronsvc.ReadJobs(storePath)
-	if err != nil {
-		return fmt.Errorf("failed to read cron store: %w", err)
-	}
-
 	removeSet := make(map[string]bool, len(args))
 	for _, id := range args {
 		removeSet[strings.TrimSpace(id)] = true
 	}
 
-	var kept []cronsvc.Job
 	removed := make(map[string]bool)
-	for _, job := range jobs {
-		if removeSet[job.ID] {
-			removed[job.ID] = true
-		} else {
-			kept = append(kept, job)
-		}
-	}
-
-	if len(removed) > 0 {
-		if err := cronsvc.WriteJobs(storePath, kept); err != nil {
-			return fmt.Errorf("failed to write cron store: %w", err)
+	err = cronsvc.ModifyJobs(storePath, func(jobs []cronsvc.Job) ([]cronsvc.Job, error) {
+		var kept []cronsvc.Job
+		for _, job := range jobs {
+			if removeSet[job.ID] {
+				removed[job.ID] = true
+			} else {
+				kept = append(kept, job)
+			}
 		}
+		return kept, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cron store: %w", err)
 	}
 
 	fmt.Print(tools.CmdOutput([][2]string{
@@ -187,6 +195,136 @@ func runCronRemove(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// --- enable / disable ---
+
+var cronEnableCmd = &cobra.Command{
+	Use:   "enable <id>",
+	Short: "Resume a disabled cron job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return setCronJobDisabled("cron enable", args[0], false)
+	},
+}
+
+var cronDisableCmd = &cobra.Command{
+	Use:   "disable <id>",
+	Short: "Pause a cron job without removing it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return setCronJobDisabled("cron disable", args[0], true)
+	},
+}
+
+func init() {
+	cronCmd.AddCommand(cronEnableCmd, cronDisableCmd)
+}
+
+func setCronJobDisabled(command, id string, disabled bool) error {
+	storePath, err := cronStorePath()
+	if err != nil {
+		return err
+	}
+	job, err := cronsvc.UpdateStoredJob(storePath, id, func(j *cronsvc.Job) {
+		j.Disabled = disabled
+	})
+	if err != nil {
+		return err
+	}
+	status := "enabled"
+	if job.Disabled {
+		status = "disabled"
+	}
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", command}, {"status", status}, {"job_id", job.ID},
+	}, "") + "\n")
+	return nil
+}
+
+// --- update ---
+
+var cronUpdateCmd = &cobra.Command{
+	Use:   "update <id>",
+	Short: "Change the schedule or task of an existing job in place",
+	Long: "Change only the given fields of an existing job; everything else (wake session, agent,\n" +
+		"delivery target, silent flag, creation time) is kept. --expr turns the job into a recurring\n" +
+		"cron job and --at into a one-time job. --skip-dates replaces the job's skip dates; pass\n" +
+		"--skip-dates \"\" to clear them.",
+	Args: cobra.ExactArgs(1),
+	RunE: runCronUpdate,
+}
+
+var (
+	cronUpdateExpr      string
+	cronUpdateAt        string
+	cronUpdateTask      string
+	cronUpdateSkipDates []string
+)
+
+func init() {
+	cronUpdateCmd.Flags().StringVar(&cronUpdateExpr, "expr", "", "New cron expression, 5-field")
+	cronUpdateCmd.Flags().StringVar(&cronUpdateAt, "at", "", "New execution time in RFC3339")
+	cronUpdateCmd.Flags().StringVar(&cronUpdateTask, "task", "", "New task prompt")
+	cronUpdateCmd.Flags().StringSliceVar(&cronUpdateSkipDates, "skip-dates", nil, "New comma-separated YYYY-MM-DD skip dates (empty clears)")
+	cronCmd.AddCommand(cronUpdateCmd)
+}
+
+func runCronUpdate(cmd *cobra.Command, args []string) error {
+	expr := strings.TrimSpace(cronUpdateExpr)
+	task := strings.TrimSpace(cronUpdateTask)
+	var at *time.Time
+	if s := strings.TrimSpace(cronUpdateAt); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid --at time %q: %w", s, err)
+		}
+		at = &t
+	}
+	if expr != "" && at != nil {
+		return fmt.Errorf("--expr and --at are mutually exclusive")
+	}
+	setSkipDates := cmd.Flags().Changed("skip-dates")
+	if expr == "" && at == nil && task == "" && !setSkipDates {
+		return fmt.Errorf("nothing to update: pass --expr, --at, --task or --skip-dates")
+	}
+
+	storePath, err := cronStorePath()
+	if err != nil {
+		return err
+	}
+	job, err := cronsvc.UpdateStoredJob(storePath, args[0], func(j *cronsvc.Job) {
+		applyCronUpdate(j, expr, at, task)
+		if setSkipDates {
+			j.SkipDates = cronUpdateSkipDates
+		}
+	})
+	if err != nil {
+		return err
+	}
+	schedule := job.Expr
+	if job.Kind == cronsvc.JobKindAt && job.AtTime != nil {
+		schedule = job.AtTime.Format(time.RFC3339)
+	}
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", "cron update"}, {"status", "updated"},
+		{"job_id", job.ID}, {"kind", job.Kind}, {"schedule", schedule},
+	}, "") + "\n")
+	return nil
+}
+
+// applyCronUpdate sets the non-empty fields on job, switching its kind when
+// the schedule type changes.
+func applyCronUpdate(job *cronsvc.Job, expr string, at *time.Time, task string) {
+	switch {
+	case expr != "":
+		job.Kind, job.Expr, job.AtTime = cronsvc.JobKindCron, expr, nil
+	case at != nil:
+		job.Kind, job.Expr, job.AtTime = cronsvc.JobKindAt, "", at
+	}
+	if task != "" {
+		job.Task = task
+	}
+}
+
 // --- list ---
 
 var cronListCmd = &cobra.Command{
@@ -218,7 +356,7 @@ func runCronList(_ *cobra.Command, _ []string) error {
 	fmt.Print(tools.CmdOutput([][2]string{
 		{"command", "cron list"}, {"status", "ok"}, {"count", fmt.Sprintf("%d", len(jobs))},
 	}, "") + "\n")
-	fmt.Printf("ID\tKIND\tSCHEDULE\tAGENT\tWAKE-SESSION\tDIRECT-WAKE\tTASK\n")
+	fmt.Printf("ID\tKIND\tSCHEDULE\tAGENT\tWAKE-SESSION\tDIRECT-WAKE\tDELIVER\tDISABLED\tTASK\n")
 	for _, job := range jobs {
 		schedule := job.Expr
 		if job.Kind == cronsvc.JobKindAt {
@@ -230,11 +368,84 @@ func runCronList(_ *cobra.Command, _ []string) error {
 		if job.DirectWake {
 			directWake = "true"
 		}
-		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", job.ID, job.Kind, schedule, job.Agent, job.WakeSession, directWake, job.Task)
+		deliver := ""
+		if job.Deliver {
+			deliver = job.Channel
+			if job.To != "" {
+				deliver += ":" + job.To
+			}
+		}
+		disabled := ""
+		if job.Disabled {
+			disabled = "true"
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", job.ID, job.Kind, schedule, job.Agent, job.WakeSession, directWake, deliver, disabled, job.Task)
+	}
+	return nil
+}
+
+// --- preview ---
+
+const (
+	cronPreviewDefaultCount = 5
+	cronPreviewMaxCount     = 50
+)
+
+var cronPreviewCmd = &cobra.Command{
+	Use:   "preview <expr>",
+	Short: "Show the next fire times of a cron expression",
+	Long: "Parse a 5-field cron expression the way set-cron does and print its next fire times\n" +
+		"in the scheduler's timezone (or --tz) and in UTC. Use it to check an expression before creating a job.",
+	Args: cobra.ExactArgs(1),
+	RunE: runCronPreview,
+}
+
+var (
+	cronPreviewTZ    string
+	cronPreviewCount int
+)
+
+func init() {
+	cronPreviewCmd.Flags().StringVar(&cronPreviewTZ, "tz", "", "IANA timezone to evaluate in (default: server local time, which jobs run in)")
+	cronPreviewCmd.Flags().IntVar(&cronPreviewCount, "count", cronPreviewDefaultCount, fmt.Sprintf("Number of fire times to show (max %d)", cronPreviewMaxCount))
+	cronCmd.AddCommand(cronPreviewCmd)
+}
+
+func runCronPreview(_ *cobra.Command, args []string) error {
+	loc := time.Local
+	if tz := strings.TrimSpace(cronPreviewTZ); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("invalid --tz %q: %w", tz, err)
+		}
+		loc = l
 	}
+	count := min(max(cronPreviewCount, 1), cronPreviewMaxCount)
+
+	times, err := cronsvc.NextFireTimes(args[0], loc, time.Now(), count)
+	if err != nil {
+		return err
+	}
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", "cron preview"}, {"status", "ok"},
+		{"schedule", strings.TrimSpace(args[0])}, {"timezone", loc.String()},
+		{"count", fmt.Sprintf("%d", len(times))},
+	}, formatFireTimes(times)) + "\n")
 	return nil
 }
 
+// formatFireTimes lists fire times in their own zone and in UTC.
+func formatFireTimes(times []time.Time) string {
+	if len(times) == 0 {
+		return "The expression never fires."
+	}
+	var sb strings.Builder
+	for _, t := range times {
+		fmt.Fprintf(&sb, "- %s (%s) | %s\n", t.Format(time.RFC3339), t.Format("Mon"), t.UTC().Format(time.RFC3339))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
 // --- register root ---
 
 func init() {
@@ -247,18 +458,41 @@ var (
 	commonAgent       string
 	commonWakeSession string
 	commonDirectWake  bool
+	commonDeliver     bool
+	commonChannel     string
+	commonTo          string
 )
 
 func addCommonJobFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&commonAgent, "agent", "", "Agent template name (independent mode only)")
 	cmd.Flags().StringVar(&commonWakeSession, "wake-session", "", "Independent mode: delivery hint shown in wake's delivery label. Inject mode: required target session receiving the task injection.")
 	cmd.Flags().BoolVar(&commonDirectWake, "direct-wake", false, "Switch to inject mode: inject --task directly into --wake-session without running a cron agent. Requires --wake-session; rejects --agent.")
+	cmd.Flags().BoolVar(&commonDeliver, "deliver", false, "Switch to deliver mode: post --task verbatim to --channel/--to without any agent turn. Requires --channel; rejects --agent, --wake-session, --direct-wake.")
+	cmd.Flags().StringVar(&commonChannel, "channel", "", "Deliver mode: target channel name (telegram, discord, feishu, wecom, socket)")
+	cmd.Flags().StringVar(&commonTo, "to", "", "Deliver mode: channel-specific recipient (e.g. telegram chat ID, discord channel ID)")
 }
 
 func applyCommonJobFlags(job *cronsvc.Job) error {
 	job.Agent = strings.TrimSpace(commonAgent)
 	job.WakeSession = strings.TrimSpace(commonWakeSession)
 	job.DirectWake = commonDirectWake
+	job.Deliver = commonDeliver
+	job.Channel = strings.TrimSpace(commonChannel)
+	job.To = strings.TrimSpace(commonTo)
+	// Jobs set from the CLI are operator-created and run as admin.
+	job.Admin = true
+	if job.Deliver {
+		if job.Channel == "" {
+			return fmt.Errorf("--deliver requires --channel (target channel name)")
+		}
+		if job.Agent != "" || job.WakeSession != "" || job.DirectWake {
+			return fmt.Errorf("--deliver cannot be combined with --agent, --wake-session or --direct-wake (deliver mode runs no agent turn)")
+		}
+		return nil
+	}
+	if job.Channel != "" || job.To != "" {
+		return fmt.Errorf("--channel and --to are only valid with --deliver")
+	}
 	if job.DirectWake {
 		if job.Agent != "" {
 			return fmt.Errorf("--agent cannot be used with --direct-wake (inject mode preserves target session's existing agent)")
@@ -294,25 +528,19 @@ func upsertJob(job cronsvc.Job) (updated bool, err error) {
 	if err != nil {
 		return false, err
 	}
-	existing, err := cronsvc.ReadJobs(storePath)
-	if err != nil {
-		return false, fmt.Errorf("failed to read cron store: %w", err)
-	}
-
 	// Upsert: replace if same ID exists, otherwise append.
-	for i, j := range existing {
-		if j.ID == job.ID {
-			existing[i] = job
-			if err := cronsvc.WriteJobs(storePath, existing); err != nil {
-				return false, fmt.Errorf("failed to write cron store: %w", err)
+	err = cronsvc.ModifyJobs(storePath, func(existing []cronsvc.Job) ([]cronsvc.Job, error) {
+		for i, j := range existing {
+			if j.ID == job.ID {
+				existing[i] = job
+				updated = true
+				return existing, nil
 			}
-			return true, nil
 		}
+		return append(existing, job), nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update cron store: %w", err)
 	}
-
-	existing = append(existing, job)
-	if err := cronsvc.WriteJobs(storePath, existing); err != nil {
-		return false, fmt.Errorf("failed to write cron store: %w", err)
-	}
-	return false, nil
+	return updated, nil
 }
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/monitor"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/thread"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayModel  string
+	replaySave   bool
+	replayDryRun bool
+)
+
+var debugCmd = &cobra.Command{
+	Use:     "debug",
+	Short:   "Developer debugging tools",
+	GroupID: "internal",
+}
+
+var debugReplayCmd = &cobra.Command{
+	Use:   "replay <session>",
+	Short: "Re-run a session's last user turn and compare it with the original",
+	Long: `Re-run the last user message of a session with the same history, optionally
+on a different model, and print the new reply and token usage next to the
+original.
+
+The turn runs in a throwaway copy of the session, so the original is never
+touched unless --save is given; then the replayed turn replaces the original
+(backed up to <session_dir>/history/), so run --save while the session is
+idle. Mutating tools are simulated by default; pass --dry-run=false to run
+them for real.
+
+Examples:
+  nagobot debug replay telegram:12345
+  nagobot debug replay telegram:12345 --model anthropic/claude-sonnet-4-20250514
+  nagobot debug replay cli --save`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDebugReplay,
+}
+
+func init() {
+	debugReplayCmd.Flags().StringVar(&replayModel, "model", "", "Replay on this provider/model instead of the session's usual routing")
+	debugReplayCmd.Flags().BoolVar(&replaySave, "save", false, "Replace the session's last turn with the replayed one")
+	debugReplayCmd.Flags().BoolVar(&replayDryRun, "dry-run", true, "Simulate mutating tools (write_file, edit_file, exec) during the replay")
+	debugCmd.AddCommand(debugReplayCmd)
+	rootCmd.AddCommand(debugCmd)
+}
+
+// replayTurn is the last user turn of a session.
+type replayTurn struct {
+	Index    int       // position of the user message in the session
+	Prompt   string    // user message body, frontmatter stripped
+	Original string    // final assistant reply of the turn, if any
+	At       time.Time // user message timestamp
+}
+
+// lastReplayTurn finds the last real user message in msgs and the reply that
+// closed its turn.
+func lastReplayTurn(msgs []provider.Message) (replayTurn, error) {
+	userMsg, idx, ok := findLastUserMessage(msgs)
+	if !ok {
+		return replayTurn{}, fmt.Errorf("session has no user message to replay")
+	}
+	_, body, _ := thread.SplitFrontmatter(userMsg.Content)
+	turn := replayTurn{Index: idx, Prompt: strings.TrimSpace(body), At: userMsg.Timestamp}
+	if turn.Prompt == "" {
+		return replayTurn{}, fmt.Errorf("last user message is empty")
+	}
+	for _, m := range msgs[idx+1:] {
+		if m.Role == "user" && !thread.IsInjectedUserMessage(m.Content) {
+			break
+		}
+		if m.Role == "assistant" && len(m.ToolCalls) == 0 && strings.TrimSpace(m.Content) != "" {
+			turn.Original = strings.TrimSpace(m.Content)
+		}
+	}
+	return turn, nil
+}
+
+// replayOptions controls replayLastTurn.
+type replayOptions struct {
+	Save   bool // replace the session's last turn with the replayed one
+	DryRun bool // simulate mutating tools during the replay
+}
+
+// replayLastTurn copies the session into a throwaway key, truncates the copy
+// to the history before its last user message and runs that message again
+// through mgr. The original session is only written when opts.Save is set
+// and the replay succeeded; the copy is always removed.
+func replayLastTurn(ctx context.Context, mgr *thread.Manager, sessions *session.Manager, key string, opts replayOptions) (replayTurn, thread.TurnResult, error) {
+	stored, err := sessions.Load(key)
+	if err != nil {
+		return replayTurn{}, thread.TurnResult{}, fmt.Errorf("failed to load session %q: %w", key, err)
+	}
+	if len(stored.Messages) == 0 {
+		return replayTurn{}, thread.TurnResult{}, fmt.Errorf("session %q not found or empty", key)
+	}
+	turn, err := lastReplayTurn(stored.Messages)
+	if err != nil {
+		return replayTurn{}, thread.TurnResult{}, err
+	}
+
+	// Keep the key's channel prefix so the copy gets the same prompt and tools.
+	replayKey := fmt.Sprintf("%s:replay-%d", key, time.Now().UnixNano())
+	if _, err := sessions.Copy(key, replayKey); err != nil {
+		return turn, thread.TurnResult{}, fmt.Errorf("failed to copy session: %w", err)
+	}
+	defer func() {
+		sessions.Delete(replayKey)
+		os.RemoveAll(sessions.Dir(replayKey))
+	}()
+	truncated := &session.Session{
+		Key:      replayKey,
+		Messages: append([]provider.Message(nil), stored.Messages[:turn.Index]...),
+	}
+	if err := sessions.Save(truncated); err != nil {
+		return turn, thread.TurnResult{}, fmt.Errorf("failed to prepare session: %w", err)
+	}
+	if opts.DryRun {
+		session.UpdateMeta(sessions.Dir(replayKey), func(m *session.Meta) { m.DryRun = true })
+	}
+
+	result := runTurn(ctx, mgr, replayKey, turn.Prompt)
+	if !opts.Save || result.Err != nil {
+		return turn, result, nil
+	}
+
+	replayed, err := sessions.GetRaw(replayKey)
+	if err != nil {
+		return turn, result, fmt.Errorf("failed to load replayed session: %w", err)
+	}
+	if _, err := backupSession(sessions.Dir(key), stored, time.Now()); err != nil {
+		return turn, result, err
+	}
+	messages := replayed.Messages
+	for i := turn.Index; i < len(messages); i++ {
+		messages[i].ID = "" // reassigned under the original key on save
+	}
+	if err := sessions.Save(&session.Session{Key: key, Messages: messages, CreatedAt: stored.CreatedAt}); err != nil {
+		return turn, result, fmt.Errorf("failed to save replayed turn into %q: %w", key, err)
+	}
+	return turn, result, nil
+}
+
+// parseReplayModel splits a --model value of the form provider/model. The
+// model part may itself contain slashes (openrouter model IDs).
+func parseReplayModel(s string) (*config.ModelConfig, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	prov, model, ok := strings.Cut(s, "/")
+	if !ok || strings.TrimSpace(prov) == "" || strings.TrimSpace(model) == "" {
+		return nil, fmt.Errorf("invalid --model %q: want provider/model, e.g. anthropic/claude-sonnet-4-20250514", s)
+	}
+	return &config.ModelConfig{Provider: strings.TrimSpace(prov), ModelType: strings.TrimSpace(model)}, nil
+}
+
+func runDebugReplay(cmd *cobra.Command, args []string) error {
+	key := strings.TrimSpace(args[0])
+	override, err := parseReplayModel(replayModel)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	threadMgr, _, _, err := buildThreadManager(cfg, true)
+	if err != nil {
+		return err
+	}
+	sessions := threadMgr.Sessions()
+	if sessions == nil {
+		return fmt.Errorf("session storage is unavailable")
+	}
+	defer sessions.Close()
+	threadMgr.SetModelOverride(override)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	turn, result, err := replayLastTurn(ctx, threadMgr, sessions, key, replayOptions{Save: replaySave, DryRun: replayDryRun})
+	if err != nil {
+		return err
+	}
+	if result.Err != nil {
+		return fmt.Errorf("replay failed: %w", result.Err)
+	}
+
+	var origRecord *monitor.TurnRecord
+	if ws, err := cfg.WorkspacePath(); err == nil {
+		origRecord = findTurnRecord(monitor.NewStore(filepath.Join(ws, "metrics")), key, turn.At)
+	}
+	replayLabel := "default routing"
+	if override != nil {
+		replayLabel = override.Provider + "/" + override.ModelType
+	}
+	return printReplayComparison(cmd.OutOrStdout(), turn, origRecord, result, replayLabel)
+}
+
+// findTurnRecord returns the first metrics record for key that started at or
+// after at: the original run of the replayed turn. Nil if none was recorded.
+func findTurnRecord(store *monitor.Store, key string, at time.Time) *monitor.TurnRecord {
+	if at.IsZero() {
+		return nil
+	}
+	var found *monitor.TurnRecord
+	for _, r := range store.Load(at.Add(-time.Second)) {
+		if r.SessionKey != key {
+			continue
+		}
+		if found == nil || r.Timestamp.Before(found.Timestamp) {
+			rec := r
+			found = &rec
+		}
+	}
+	return found
+}
+
+// printReplayComparison prints token usage for the original and replayed
+// turns in two columns, followed by both replies.
+func printReplayComparison(w io.Writer, turn replayTurn, orig *monitor.TurnRecord, replay thread.TurnResult, replayLabel string) error {
+	origCol := func(v int) string {
+		if orig == nil {
+			return "-"
+		}
+		return fmt.Sprint(v)
+	}
+	origLabel := "unknown"
+	if orig != nil {
+		origLabel = orig.Provider + "/" + orig.Model
+	}
+	var prompt, completion, total int
+	if orig != nil {
+		prompt, completion, total = orig.AccPromptTokens, orig.AccCompletionTokens, orig.AccTotalTokens
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "\tORIGINAL\tREPLAY\n")
+	fmt.Fprintf(tw, "model\t%s\t%s\n", origLabel, replayLabel)
+	fmt.Fprintf(tw, "prompt_tokens\t%s\t%d\n", origCol(prompt), replay.Usage.PromptTokens)
+	fmt.Fprintf(tw, "completion_tokens\t%s\t%d\n", origCol(completion), replay.Usage.CompletionTokens)
+	fmt.Fprintf(tw, "total_tokens\t%s\t%d\n", origCol(total), replay.Usage.TotalTokens)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	original := turn.Original
+	if original == "" {
+		original = "(no reply recorded)"
+	}
+	_, err := fmt.Fprintf(w, "\n--- prompt ---\n%s\n\n--- original ---\n%s\n\n--- replay ---\n%s\n",
+		turn.Prompt, original, strings.TrimSpace(replay.Response))
+	return err
+}
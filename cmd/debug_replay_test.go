@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/thread"
+)
+
+// capturingProvider answers every request with a fixed reply and keeps the
+// messages it was sent.
+type capturingProvider struct {
+	reply    string
+	requests [][]provider.Message
+}
+
+func (p *capturingProvider) Chat(_ context.Context, req *provider.Request) (provider.ChatResult, error) {
+	p.requests = append(p.requests, req.Messages)
+	return provider.NewBasicResult(&provider.Response{
+		Content: p.reply,
+		Usage:   provider.Usage{PromptTokens: 200, CompletionTokens: 5, TotalTokens: 205},
+	}), nil
+}
+
+func replayTestSession(t *testing.T, key string) (*session.Manager, []provider.Message) {
+	t.Helper()
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []provider.Message{
+		provider.UserMessage("---\nsender: user\n---\nwhat is the capital of France?"),
+		provider.AssistantMessage("Paris."),
+		provider.UserMessage("---\nsender: user\n---\nand of Italy?"),
+		provider.AssistantMessage("Milan."),
+	}
+	if err := sessions.Save(&session.Session{Key: key, Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+	return sessions, msgs
+}
+
+func TestReplayLastTurnRestoresSession(t *testing.T) {
+	const key = "telegram:42"
+	sessions, msgs := replayTestSession(t, key)
+	p := &capturingProvider{reply: "Rome."}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p, Sessions: sessions})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	turn, result, err := replayLastTurn(ctx, mgr, sessions, key, replayOptions{DryRun: true})
+	if err != nil || result.Err != nil {
+		t.Fatalf("replayLastTurn: %v / %v", err, result.Err)
+	}
+	if turn.Prompt != "and of Italy?" || turn.Original != "Milan." {
+		t.Errorf("turn = %+v", turn)
+	}
+	if result.Response != "Rome." || result.Usage.TotalTokens != 205 {
+		t.Errorf("result = %+v", result)
+	}
+
+	// The replay saw the earlier history but not the original answer.
+	if len(p.requests) != 1 {
+		t.Fatalf("provider calls = %d, want 1", len(p.requests))
+	}
+	var sent strings.Builder
+	for _, m := range p.requests[0] {
+		sent.WriteString(m.Content + "\n")
+	}
+	if !strings.Contains(sent.String(), "Paris.") || !strings.Contains(sent.String(), "and of Italy?") || strings.Contains(sent.String(), "Milan.") {
+		t.Errorf("replay request did not match the original inputs:\n%s", sent.String())
+	}
+
+	stored, err := sessions.Reload(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Messages) != len(msgs) || stored.Messages[len(msgs)-1].Content != "Milan." {
+		t.Errorf("session was modified without --save: %+v", stored.Messages)
+	}
+
+	var out bytes.Buffer
+	if err := printReplayComparison(&out, turn, nil, result, "stub/model"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"ORIGINAL", "stub/model", "205", "Milan.", "Rome."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("comparison output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestReplayLastTurnSave(t *testing.T) {
+	const key = "telegram:42"
+	sessions, _ := replayTestSession(t, key)
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: &capturingProvider{reply: "Rome."}, Sessions: sessions})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, result, err := replayLastTurn(ctx, mgr, sessions, key, replayOptions{Save: true, DryRun: true}); err != nil || result.Err != nil {
+		t.Fatalf("replayLastTurn: %v / %v", err, result.Err)
+	}
+	stored, err := sessions.Reload(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := stored.Messages[len(stored.Messages)-1]
+	if last.Content != "Rome." {
+		t.Errorf("--save kept last message %q, want the replayed reply", last.Content)
+	}
+	for _, m := range stored.Messages {
+		if m.Content == "Milan." {
+			t.Error("--save left the original reply in the session")
+		}
+	}
+}
+
+// inspectingProvider records what the stores held while the replay ran.
+type inspectingProvider struct {
+	sessions      *session.Manager
+	key           string
+	originalCount int
+	replayKey     string
+	replayDryRun  bool
+}
+
+func (p *inspectingProvider) Chat(_ context.Context, _ *provider.Request) (provider.ChatResult, error) {
+	if s, err := p.sessions.Load(p.key); err == nil {
+		p.originalCount = len(s.Messages)
+	}
+	summaries, _ := p.sessions.List(0)
+	for _, sum := range summaries {
+		if strings.HasPrefix(sum.Key, p.key+":replay-") {
+			p.replayKey = sum.Key
+			p.replayDryRun = session.ReadMeta(p.sessions.Dir(sum.Key)).DryRun
+		}
+	}
+	return provider.NewBasicResult(&provider.Response{Content: "Rome."}), nil
+}
+
+func TestReplayLastTurnRunsInThrowawayCopy(t *testing.T) {
+	const key = "telegram:42"
+	sessions, msgs := replayTestSession(t, key)
+	p := &inspectingProvider{sessions: sessions, key: key}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p, Sessions: sessions})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, result, err := replayLastTurn(ctx, mgr, sessions, key, replayOptions{DryRun: true}); err != nil || result.Err != nil {
+		t.Fatalf("replayLastTurn: %v / %v", err, result.Err)
+	}
+	if p.originalCount != len(msgs) {
+		t.Errorf("original session held %d messages during the replay, want %d", p.originalCount, len(msgs))
+	}
+	if p.replayKey == "" {
+		t.Fatal("replay did not run in a copied session")
+	}
+	if !p.replayDryRun {
+		t.Error("replay copy was not marked dry-run")
+	}
+	if sessions.Exists(p.replayKey) {
+		t.Errorf("replay copy %s was not removed", p.replayKey)
+	}
+	if _, err := os.Stat(sessions.Dir(p.replayKey)); !os.IsNotExist(err) {
+		t.Errorf("replay copy directory left behind: %v", err)
+	}
+}
+
+func TestParseReplayModel(t *testing.T) {
+	mc, err := parseReplayModel("openrouter/moonshotai/kimi-k2.5")
+	if err != nil || mc.Provider != "openrouter" || mc.ModelType != "moonshotai/kimi-k2.5" {
+		t.Errorf("parseReplayModel = %+v, %v", mc, err)
+	}
+	if mc, err := parseReplayModel(""); mc != nil || err != nil {
+		t.Errorf("empty --model = %+v, %v; want no override", mc, err)
+	}
+	if _, err := parseReplayModel("deepseek"); err == nil {
+		t.Error("expected error without a model part")
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/linanwx/nagobot/channel"
 	"github.com/linanwx/nagobot/config"
@@ -24,6 +27,10 @@ type Dispatcher struct {
 	cfg       *config.Config
 	ctx       context.Context
 	previewer media.Previewer
+	dedup     *channel.DedupCache // Drops redelivered messages by channel+message ID.
+
+	hooksMu sync.RWMutex
+	hooks   []MessageHook // nil means DefaultMessageHooks
 }
 
 // NewDispatcher creates a new dispatcher.
@@ -32,10 +39,11 @@ func NewDispatcher(
 	threads *thread.Manager,
 	cfg *config.Config,
 ) *Dispatcher {
-	return &Dispatcher{
-		channels:  channels,
-		threads:   threads,
-		cfg:       cfg,
+	d := &Dispatcher{
+		channels: channels,
+		threads:  threads,
+		cfg:      cfg,
+		dedup:    channel.NewDedupCache(cfg.GetChannelDedupWindow()),
 		previewer: media.NewPreviewer(func() *config.Config {
 			cfg, err := config.Load()
 			if err != nil {
@@ -44,6 +52,12 @@ func NewDispatcher(
 			return cfg
 		}),
 	}
+	if channels != nil {
+		// Outbound translation runs in the channel manager so every sink is
+		// covered, not only replies to dispatched messages.
+		channels.SetTranslator(d.translateOutbound)
+	}
+	return d
 }
 
 // Run starts a goroutine for each channel that reads messages and dispatches
@@ -76,6 +90,15 @@ func (d *Dispatcher) processChannel(ctx context.Context, ch channel.Channel) {
 }
 
 func (d *Dispatcher) dispatch(ctx context.Context, ch channel.Channel, msg *channel.Message) {
+	// At-least-once channels (webhooks, Feishu, Telegram retries) may resend
+	// the same message; answer each ID only once. IDs are only unique per
+	// chat on some platforms (Telegram message IDs), so the chat is part of
+	// the key.
+	if msg.ID != "" && d.dedup.Seen(ch.Name()+":"+msg.ChannelID+":"+msg.ID) {
+		logger.Info("dropping duplicate message", "channel", ch.Name(), "id", msg.ID)
+		return
+	}
+
 	logger.Debug("dispatching message",
 		"channel", ch.Name(),
 		"channelID", msg.ChannelID,
@@ -90,20 +113,40 @@ func (d *Dispatcher) dispatch(ctx context.Context, ch channel.Channel, msg *chan
 	}
 
 	sessionKey := d.route(msg)
+
+	// Intercept /stop and /abort — cancel the running turn, bypass LLM.
+	if isAbortCommand(msg.Text) {
+		d.handleAbort(ctx, ch, msg, sessionKey)
+		return
+	}
+
+	// Intercept /whoami — report the caller's resolved identity, bypass LLM.
+	if isWhoamiCommand(msg.Text) {
+		d.handleWhoami(ctx, ch, msg, sessionKey)
+		return
+	}
+
 	if sd, err := d.cfg.SessionsDir(); err == nil {
 		persistChannelRouting(sd, sessionKey, msg)
 	}
 	sink := d.buildSink(ch, msg)
 	agentName, vars := d.resolveAgentName(sessionKey, msg)
-	userMessage := d.preprocessMessage(msg)
 	source := d.wakeSource(ch)
 
 	d.threads.Wake(sessionKey, &thread.WakeMessage{
 		Source:    source,
-		Message:   userMessage,
+		Message:   msg.Text,
 		Sink:      sink,
 		AgentName: agentName,
 		Vars:      vars,
+		DryRun:    msg.Metadata["dry_run"] == "true",
+		Admin:     d.isAdmin(ch.Name(), msg),
+		Priority:  d.wakePriority(ch, msg),
+		// Hooks may call an LLM (translation, media previews), so they run
+		// in the turn instead of blocking this channel's loop.
+		Prepare: func(ctx context.Context) string {
+			return d.preprocessMessage(ctx, msg)
+		},
 	})
 }
 
@@ -138,6 +181,90 @@ func (d *Dispatcher) handleInit(ctx context.Context, ch channel.Channel, msg *ch
 	}
 }
 
+// isAbortCommand reports whether text is a /stop or /abort command. Telegram
+// group commands may carry a bot suffix ("/stop@mybot").
+func isAbortCommand(text string) bool {
+	cmd, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(text)), "@")
+	return cmd == "/stop" || cmd == "/abort"
+}
+
+// handleAbort cancels the turn running for sessionKey. The aborted turn sends
+// its own "stopped" notice; this only replies when nothing was running.
+func (d *Dispatcher) handleAbort(ctx context.Context, ch channel.Channel, msg *channel.Message, sessionKey string) {
+	if d.threads.Abort(sessionKey) {
+		logger.Info("turn aborted by user", "sessionKey", sessionKey, "user", msg.Username)
+		return
+	}
+	if sink := d.buildSink(ch, msg); !sink.IsZero() {
+		_ = sink.Send(ctx, "Nothing is running in this session.")
+	}
+}
+
+// isWhoamiCommand reports whether text is a /whoami command, with an
+// optional bot suffix like isAbortCommand.
+func isWhoamiCommand(text string) bool {
+	cmd, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(text)), "@")
+	return cmd == "/whoami"
+}
+
+// handleWhoami replies with how the dispatcher sees the caller.
+func (d *Dispatcher) handleWhoami(ctx context.Context, ch channel.Channel, msg *channel.Message, sessionKey string) {
+	if sink := d.buildSink(ch, msg); !sink.IsZero() {
+		_ = sink.Send(ctx, d.whoamiText(ch.Name(), msg, sessionKey))
+	}
+}
+
+// whoamiText describes the caller's identity as resolved by route and
+// resolveAgentName. Admins also see the chat routing and channel allowlists.
+func (d *Dispatcher) whoamiText(channelName string, msg *channel.Message, sessionKey string) string {
+	agentName, _ := d.resolveAgentName(sessionKey, msg)
+	if agentName == "" {
+		agentName = "soul (default)"
+	}
+	admin := d.isAdmin(channelName, msg)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Channel: %s\n", channelName)
+	fmt.Fprintf(&sb, "User ID: %s\n", orNone(msg.UserID))
+	if msg.Username != "" {
+		fmt.Fprintf(&sb, "Username: %s\n", msg.Username)
+	}
+	fmt.Fprintf(&sb, "Session: %s\n", sessionKey)
+	fmt.Fprintf(&sb, "Agent: %s\n", agentName)
+	fmt.Fprintf(&sb, "Admin: %t", admin)
+	if !admin {
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "\n\nChannel ID: %s\n", orNone(msg.ChannelID))
+	fmt.Fprintf(&sb, "Chat type: %s\n", orNone(strings.TrimSpace(msg.Metadata["chat_type"])))
+	sb.WriteString("Allowlists (empty = allow all):\n")
+	telegramIDs := make([]string, 0, len(d.cfg.GetTelegramAllowedIDs()))
+	for _, id := range d.cfg.GetTelegramAllowedIDs() {
+		telegramIDs = append(telegramIDs, fmt.Sprint(id))
+	}
+	for _, list := range []struct {
+		name string
+		ids  []string
+	}{
+		{"telegram.allowedIds", telegramIDs},
+		{"feishu.allowedOpenIds", d.cfg.GetFeishuAllowedOpenIDs()},
+		{"discord.allowedGuildIds", d.cfg.GetDiscordAllowedGuildIDs()},
+		{"discord.allowedUserIds", d.cfg.GetDiscordAllowedUserIDs()},
+		{"wecom.allowedUserIds", d.cfg.GetWeComAllowedUserIDs()},
+	} {
+		fmt.Fprintf(&sb, "- %s: %s\n", list.name, orNone(strings.Join(list.ids, ", ")))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
 // chatGroupTypes defines which chat_type values count as group chats per channel prefix.
 var chatGroupTypes = map[string][]string{
 	"telegram:": {"group", "supergroup"},
@@ -156,8 +283,12 @@ func (d *Dispatcher) route(msg *channel.Message) string {
 		return "cli"
 	}
 
-	// Web channel: "web:main" and "web:cli" → "cli"; "web:{sessionKey}" → route to that session.
+	// Web channel: an authenticated user always gets "web:{user}"; otherwise
+	// "web:main" and "web:cli" → "cli"; "web:{sessionKey}" → route to that session.
 	if suffix, ok := strings.CutPrefix(msg.ChannelID, "web:"); ok {
+		if user := strings.TrimSpace(msg.Metadata["web_user"]); user != "" {
+			return "web:" + user
+		}
 		if suffix == "" || suffix == "main" || suffix == "cli" {
 			return "cli"
 		}
@@ -186,6 +317,17 @@ func (d *Dispatcher) route(msg *channel.Message) string {
 	return sessionKey
 }
 
+// isGroupChat reports whether msg came from a group chat on a chat channel.
+func isGroupChat(msg *channel.Message) bool {
+	chatType := strings.TrimSpace(msg.Metadata["chat_type"])
+	for prefix, groupTypes := range chatGroupTypes {
+		if strings.HasPrefix(msg.ChannelID, prefix) {
+			return slices.Contains(groupTypes, chatType)
+		}
+	}
+	return false
+}
+
 // routeChatChannel routes a chat channel message to a session key.
 // Group chats share a session by channel ID; DMs use per-user keys.
 func (d *Dispatcher) routeChatChannel(msg *channel.Message, prefix string, groupTypes []string) string {
@@ -220,22 +362,60 @@ func (d *Dispatcher) buildSink(ch channel.Channel, msg *channel.Message) thread.
 		replyTo = strings.TrimSpace(msg.ReplyTo)
 	}
 
+	// In group chats the turn's first message is posted as a threaded reply
+	// to the triggering message, so it stays attached in a busy chat.
+	var replyToMessageID atomic.Value
+	if isGroupChat(msg) {
+		replyToMessageID.Store(strings.TrimSpace(msg.ID))
+	}
+
 	sink := thread.Sink{
-		Label:     "your response will be sent to the user via " + channelName,
-		Chunkable: true,
+		Label:       "your response will be sent to the user via " + channelName,
+		Chunkable:   true,
+		UserChannel: true,
 		Send: func(ctx context.Context, response string) error {
 			if strings.TrimSpace(response) == "" {
 				return nil
 			}
-			return manager.SendTo(ctx, channelName, response, replyTo)
+			msgID, _ := replyToMessageID.Swap("").(string)
+			return manager.SendResponse(ctx, channelName, &channel.Response{
+				Text:             response,
+				ReplyTo:          replyTo,
+				ReplyToMessageID: msgID,
+			})
 		},
 	}
 
 	// Build React closure for channels that support it.
 	sink.React = d.buildReactFunc(channelName, manager, msg)
+	sink.SendFile = sendFileFunc(manager, channelName, replyTo)
+	sink.SendEmbed = sendEmbedFunc(manager, channelName, replyTo)
 	return sink
 }
 
+// sendEmbedFunc returns a Sink.SendEmbed closure delivering to replyTo on the
+// named channel (as text where the channel has no embeds), or nil without a
+// channel manager.
+func sendEmbedFunc(manager *channel.Manager, channelName, replyTo string) func(ctx context.Context, embed thread.Embed) error {
+	if manager == nil {
+		return nil
+	}
+	return func(ctx context.Context, embed thread.Embed) error {
+		return manager.SendEmbed(ctx, channelName, replyTo, embed)
+	}
+}
+
+// sendFileFunc returns a Sink.SendFile closure uploading to replyTo on the
+// named channel, or nil if the channel cannot upload files.
+func sendFileFunc(manager *channel.Manager, channelName, replyTo string) func(ctx context.Context, path, caption string) error {
+	if manager == nil || !manager.SupportsFiles(channelName) {
+		return nil
+	}
+	return func(ctx context.Context, path, caption string) error {
+		return manager.SendFile(ctx, channelName, replyTo, path, caption)
+	}
+}
+
 // buildCronSink returns a drop sink for cron-channel messages.
 // Cron-triggered turns must explicitly dispatch() to deliver output; naive
 // text output is discarded. This path is the legacy channel-message fallback;
@@ -271,7 +451,7 @@ var platformEmoji = map[string]map[thread.ReactEvent]string{
 // defaultEmoji is used for CLI/socket/web debugging.
 var defaultEmoji = map[thread.ReactEvent]string{
 	thread.ReactToolCalls: "🔧",
-	thread.ReactStreaming:  "✏️",
+	thread.ReactStreaming: "✏️",
 }
 
 func emojiFor(channelName string, event thread.ReactEvent) string {
@@ -341,47 +521,6 @@ func (d *Dispatcher) resolveAgentName(sessionKey string, msg *channel.Message) (
 	return agentName, vars
 }
 
-// preprocessMessage prepends media summary, previews, and sender name to the user message.
-func (d *Dispatcher) preprocessMessage(msg *channel.Message) string {
-	text := msg.Text
-
-	mediaSummary := msg.Metadata["media_summary"]
-	if mediaSummary != "" {
-		// Generate fast media previews for downloaded media files.
-		previews := d.generateMediaPreviews(mediaSummary)
-		if previews != "" {
-			text = previews + "\n\n" + mediaSummary + "\n\n" + text
-		} else {
-			text = mediaSummary + "\n\n" + text
-		}
-	}
-
-	// Prepend quoted reply context so the AI knows what message was replied to.
-	if rc := msg.Metadata["reply_context"]; rc != "" {
-		text = truncate(rc, 500) + "\n\n" + text
-	}
-
-	// For group chats, prepend sender name so the AI can distinguish players.
-	chatType := strings.TrimSpace(msg.Metadata["chat_type"])
-	if chatType == "group" || chatType == "supergroup" {
-		sender := strings.TrimSpace(msg.Username)
-		if sender == "" {
-			sender = strings.TrimSpace(msg.Metadata["first_name"])
-		}
-		if sender != "" {
-			text = "[" + sender + "]: " + text
-		}
-	}
-
-	// For Discord thread / forum-post messages, prepend a header with post title
-	// and applied tags so the LLM keeps the topic in focus on every turn.
-	if header := threadHeader(msg.Metadata); header != "" {
-		text = header + "\n" + text
-	}
-
-	return text
-}
-
 // threadHeader formats a one-line context header for Discord thread / forum
 // messages. Returns "" when the message has no thread metadata.
 func threadHeader(meta map[string]string) string {
@@ -422,7 +561,7 @@ var mediaPathRe = regexp.MustCompile(`(?m)^(image_path|audio_path):\s*(.+)$`)
 // generateMediaPreviews extracts media file paths from a media summary string,
 // calls the previewer for each, and returns formatted preview tags.
 // Returns empty string if no previews were generated or previewer is nil.
-func (d *Dispatcher) generateMediaPreviews(mediaSummary string) string {
+func (d *Dispatcher) generateMediaPreviews(ctx context.Context, mediaSummary string) string {
 	if d.previewer == nil {
 		return ""
 	}
@@ -445,7 +584,7 @@ func (d *Dispatcher) generateMediaPreviews(mediaSummary string) string {
 			mediaType = media.MediaTypeAudio
 		}
 
-		description, err := d.previewer.Preview(d.ctx, filePath, mediaType)
+		description, err := d.previewer.Preview(ctx, filePath, mediaType)
 		if err != nil {
 			logger.Error("media preview failed",
 				"file", filePath,
@@ -469,6 +608,25 @@ func (d *Dispatcher) wakeSource(ch channel.Channel) thread.WakeSource {
 	return thread.WakeSource(ch.Name())
 }
 
+// wakePriority raises messages from the configured admin so they are handled
+// ahead of a queued user flood in shared sessions. Zero keeps the source default.
+func (d *Dispatcher) wakePriority(ch channel.Channel, msg *channel.Message) int {
+	if d.isAdmin(ch.Name(), msg) {
+		return thread.PriorityHigh
+	}
+	return 0
+}
+
+// isAdmin reports whether msg comes from the configured admin. Only Feishu
+// has an admin setting (feishu.adminOpenId).
+func (d *Dispatcher) isAdmin(channelName string, msg *channel.Message) bool {
+	if channelName != "feishu" || msg == nil {
+		return false
+	}
+	admin := d.cfg.GetFeishuAdminOpenID()
+	return admin != "" && msg.UserID == admin
+}
+
 // persistChannelRouting writes channel routing metadata to meta.json for
 // channels that need routing info beyond what the session key provides
 // (e.g., Discord DM needs "dm:{userID}" to create a DM channel on send,
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/linanwx/nagobot/channel"
+)
+
+// MessageHook transforms the text of an inbound message. Hooks run as the
+// first step of the woken turn, not on the channel's dispatch loop, so ctx
+// is the turn's context and /stop cancels slow hooks. A hook receives the
+// text produced by the previous hook and returns the replacement;
+// msg.Metadata may be read or updated for later hooks.
+type MessageHook func(ctx context.Context, msg *channel.Message, text string) string
+
+// DefaultMessageHooks returns the built-in preprocessing chain, in order:
+// channel translation, media previews and summary, quoted reply context,
+// group sender name, and the Discord thread header. After translation each
+// hook prepends to the text, so the last hook's output ends up first.
+func (d *Dispatcher) DefaultMessageHooks() []MessageHook {
+	return []MessageHook{
+		d.translateInboundHook,
+		d.mediaSummaryHook,
+		replyContextHook,
+		groupSenderHook,
+		threadHeaderHook,
+	}
+}
+
+// SetMessageHooks replaces the preprocessing chain. Include
+// DefaultMessageHooks() to keep the built-in behavior; nil restores it.
+func (d *Dispatcher) SetMessageHooks(hooks ...MessageHook) {
+	d.hooksMu.Lock()
+	d.hooks = hooks
+	d.hooksMu.Unlock()
+}
+
+// AddMessageHook appends h to the end of the preprocessing chain.
+func (d *Dispatcher) AddMessageHook(h MessageHook) {
+	if h == nil {
+		return
+	}
+	d.hooksMu.Lock()
+	if d.hooks == nil {
+		d.hooks = d.DefaultMessageHooks()
+	}
+	d.hooks = append(d.hooks, h)
+	d.hooksMu.Unlock()
+}
+
+// preprocessMessage runs the message hook chain over the user message.
+func (d *Dispatcher) preprocessMessage(ctx context.Context, msg *channel.Message) string {
+	d.hooksMu.RLock()
+	hooks := d.hooks
+	d.hooksMu.RUnlock()
+	if hooks == nil {
+		hooks = d.DefaultMessageHooks()
+	}
+
+	text := msg.Text
+	for _, h := range hooks {
+		if h != nil {
+			text = h(ctx, msg, text)
+		}
+	}
+	return text
+}
+
+// mediaSummaryHook prepends fast media previews and the media summary.
+func (d *Dispatcher) mediaSummaryHook(ctx context.Context, msg *channel.Message, text string) string {
+	mediaSummary := msg.Metadata["media_summary"]
+	if mediaSummary == "" {
+		return text
+	}
+	// Generate fast media previews for downloaded media files.
+	if previews := d.generateMediaPreviews(ctx, mediaSummary); previews != "" {
+		return previews + "\n\n" + mediaSummary + "\n\n" + text
+	}
+	return mediaSummary + "\n\n" + text
+}
+
+// replyContextHook prepends quoted reply context so the AI knows what message
+// was replied to.
+func replyContextHook(_ context.Context, msg *channel.Message, text string) string {
+	if rc := msg.Metadata["reply_context"]; rc != "" {
+		return truncate(rc, 500) + "\n\n" + text
+	}
+	return text
+}
+
+// groupSenderHook prepends the sender name in group chats so the AI can
+// distinguish players.
+func groupSenderHook(_ context.Context, msg *channel.Message, text string) string {
+	chatType := strings.TrimSpace(msg.Metadata["chat_type"])
+	if chatType != "group" && chatType != "supergroup" {
+		return text
+	}
+	sender := strings.TrimSpace(msg.Username)
+	if sender == "" {
+		sender = strings.TrimSpace(msg.Metadata["first_name"])
+	}
+	if sender == "" {
+		return text
+	}
+	return "[" + sender + "]: " + text
+}
+
+// threadHeaderHook prepends the post title and applied tags of Discord thread
+// / forum-post messages so the LLM keeps the topic in focus on every turn.
+func threadHeaderHook(_ context.Context, msg *channel.Message, text string) string {
+	if header := threadHeader(msg.Metadata); header != "" {
+		return header + "\n" + text
+	}
+	return text
+}
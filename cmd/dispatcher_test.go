@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/linanwx/nagobot/channel"
+	"github.com/linanwx/nagobot/config"
 	"github.com/linanwx/nagobot/media"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/thread"
 )
 
 func TestThreadHeader_None(t *testing.T) {
@@ -68,7 +72,7 @@ func TestPreprocessMessage_ForumPostHeader(t *testing.T) {
 			"applied_tags": "Bug",
 		},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	// header line first, then sender + text on next line
 	headerIdx := strings.Index(got, "[Forum post")
 	senderIdx := strings.Index(got, "[Nansen]: I'm stuck")
@@ -93,7 +97,7 @@ func TestPreprocessMessage_NoThreadHeader(t *testing.T) {
 		Username: "Alice",
 		Metadata: map[string]string{"chat_type": "group"},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	if strings.Contains(got, "[Forum post") || strings.Contains(got, "[Thread ") {
 		t.Errorf("unexpected thread header: %s", got)
 	}
@@ -107,7 +111,7 @@ func TestPreprocessMessage_ReplyContext(t *testing.T) {
 			"reply_context": "[Reply to Alice]: Original message here",
 		},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	if !strings.Contains(got, "[Reply to Alice]: Original message here") {
 		t.Errorf("reply context not found in output: %s", got)
 	}
@@ -131,7 +135,7 @@ func TestPreprocessMessage_ReplyContextTruncated(t *testing.T) {
 			"reply_context": longContent,
 		},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	if strings.Contains(got, longContent) {
 		t.Errorf("reply context should have been truncated")
 	}
@@ -146,7 +150,7 @@ func TestPreprocessMessage_NoReplyContext(t *testing.T) {
 		Text:     "Hello",
 		Metadata: map[string]string{},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	if got != "Hello" {
 		t.Errorf("expected plain text, got %q", got)
 	}
@@ -162,7 +166,7 @@ func TestPreprocessMessage_ReplyWithGroupSender(t *testing.T) {
 			"chat_type":     "group",
 		},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	if !strings.Contains(got, "[Reply to Alice]: Some point") {
 		t.Errorf("missing reply context: %s", got)
 	}
@@ -240,7 +244,7 @@ func TestGenerateMediaPreviews_ImagePath(t *testing.T) {
 		},
 	}
 	summary := "[Media: photo]\nimage_path: /tmp/media/img-20260322-120000-abcd.jpg"
-	got := d.generateMediaPreviews(summary)
+	got := d.generateMediaPreviews(context.Background(), summary)
 	if !strings.Contains(got, "media_preview") {
 		t.Errorf("expected media_preview tag, got: %s", got)
 	}
@@ -258,7 +262,7 @@ func TestGenerateMediaPreviews_AudioPath(t *testing.T) {
 		},
 	}
 	summary := "[Media: voice]\naudio_path: /tmp/media/audio-20260322-120000-abcd.ogg\nduration: 5s"
-	got := d.generateMediaPreviews(summary)
+	got := d.generateMediaPreviews(context.Background(), summary)
 	if !strings.Contains(got, "audio_preview") {
 		t.Errorf("expected audio_preview tag, got: %s", got)
 	}
@@ -276,7 +280,7 @@ func TestGenerateMediaPreviews_PreviewError(t *testing.T) {
 		},
 	}
 	summary := "[Media: photo]\nimage_path: /tmp/media/img.jpg"
-	got := d.generateMediaPreviews(summary)
+	got := d.generateMediaPreviews(context.Background(), summary)
 	if !strings.Contains(got, "media_preview failed") {
 		t.Errorf("expected error tag, got: %s", got)
 	}
@@ -291,7 +295,7 @@ func TestGenerateMediaPreviews_NoMediaPaths(t *testing.T) {
 	}
 	// Summary without image_path or audio_path
 	summary := "[Media: sticker]\nemoji: 😀\nsticker_set: MyStickers"
-	got := d.generateMediaPreviews(summary)
+	got := d.generateMediaPreviews(context.Background(), summary)
 	if got != "" {
 		t.Errorf("expected empty string for non-media summary, got: %s", got)
 	}
@@ -299,7 +303,7 @@ func TestGenerateMediaPreviews_NoMediaPaths(t *testing.T) {
 
 func TestGenerateMediaPreviews_NilPreviewer(t *testing.T) {
 	d := &Dispatcher{previewer: nil}
-	got := d.generateMediaPreviews("[Media: photo]\nimage_path: /tmp/photo.jpg")
+	got := d.generateMediaPreviews(context.Background(), "[Media: photo]\nimage_path: /tmp/photo.jpg")
 	if got != "" {
 		t.Errorf("expected empty string for nil previewer, got: %s", got)
 	}
@@ -315,7 +319,7 @@ func TestGenerateMediaPreviews_MultipleMedia(t *testing.T) {
 		},
 	}
 	summary := "[Media: photo]\nimage_path: /tmp/media/img1.jpg\n\n[Media: voice]\naudio_path: /tmp/media/audio1.ogg"
-	got := d.generateMediaPreviews(summary)
+	got := d.generateMediaPreviews(context.Background(), summary)
 	if !strings.Contains(got, "media_preview") {
 		t.Errorf("expected media_preview tag, got: %s", got)
 	}
@@ -338,7 +342,7 @@ func TestPreprocessMessage_WithMediaPreview(t *testing.T) {
 			"media_summary": "[Media: photo]\nimage_path: /tmp/media/img.jpg",
 		},
 	}
-	got := d.preprocessMessage(msg)
+	got := d.preprocessMessage(context.Background(), msg)
 	// Order: preview, then media_summary, then text
 	previewIdx := strings.Index(got, "media_preview")
 	summaryIdx := strings.Index(got, "[Media: photo]")
@@ -353,3 +357,198 @@ func TestPreprocessMessage_WithMediaPreview(t *testing.T) {
 		t.Errorf("media_summary should come before user text")
 	}
 }
+
+func TestIsAbortCommand(t *testing.T) {
+	for text, want := range map[string]bool{
+		"/stop":         true,
+		" /ABORT ":      true,
+		"/stop@nagobot": true,
+		"/stopwatch":    false,
+		"please /stop":  false,
+		"stop":          false,
+	} {
+		if got := isAbortCommand(text); got != want {
+			t.Errorf("isAbortCommand(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestWhoamiText_AdminVsUser(t *testing.T) {
+	cfg := &config.Config{Channels: &config.ChannelsConfig{
+		Feishu: &config.FeishuChannelConfig{
+			AdminOpenID:    "ou_admin",
+			AllowedOpenIDs: []string{"ou_admin", "ou_alice"},
+		},
+	}}
+	d := &Dispatcher{cfg: cfg, threads: thread.NewManager(&thread.ThreadConfig{})}
+
+	user := &channel.Message{ChannelID: "feishu:oc_1", UserID: "ou_alice", Username: "Alice",
+		Metadata: map[string]string{"chat_type": "p2p"}}
+	got := d.whoamiText("feishu", user, d.route(user))
+	for _, want := range []string{"Channel: feishu", "User ID: ou_alice", "Session: feishu:ou_alice", "Agent: soul (default)", "Admin: false"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("user view missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Allowlists") {
+		t.Errorf("user view should not list allowlists:\n%s", got)
+	}
+
+	admin := &channel.Message{ChannelID: "feishu:oc_1", UserID: "ou_admin",
+		Metadata: map[string]string{"chat_type": "p2p", "agent": "ops"}}
+	got = d.whoamiText("feishu", admin, d.route(admin))
+	for _, want := range []string{"Agent: ops", "Admin: true", "Chat type: p2p", "feishu.allowedOpenIds: ou_admin, ou_alice", "telegram.allowedIds: (none)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("admin view missing %q:\n%s", want, got)
+		}
+	}
+
+	if !isWhoamiCommand("/whoami@nagobot") || isWhoamiCommand("/whoami please") {
+		t.Error("isWhoamiCommand mismatch")
+	}
+}
+
+func TestRouteWebUsersGetDistinctSessions(t *testing.T) {
+	d := &Dispatcher{}
+	alice := &channel.Message{ChannelID: "web:web:alice", UserID: "alice", Metadata: map[string]string{"web_user": "alice"}}
+	bob := &channel.Message{ChannelID: "web:cli", UserID: "bob", Metadata: map[string]string{"web_user": "bob"}}
+	local := &channel.Message{ChannelID: "web:cli", Metadata: map[string]string{}}
+	if got := d.route(alice); got != "web:alice" {
+		t.Errorf("alice routed to %q, want web:alice", got)
+	}
+	if got := d.route(bob); got != "web:bob" {
+		t.Errorf("bob routed to %q, want web:bob", got)
+	}
+	if got := d.route(local); got != "cli" {
+		t.Errorf("local web routed to %q, want cli", got)
+	}
+}
+
+func TestIsGroupChat(t *testing.T) {
+	cases := []struct {
+		msg  channel.Message
+		want bool
+	}{
+		{channel.Message{ChannelID: "telegram:-100", Metadata: map[string]string{"chat_type": "supergroup"}}, true},
+		{channel.Message{ChannelID: "telegram:5", Metadata: map[string]string{"chat_type": "private"}}, false},
+		{channel.Message{ChannelID: "discord:9", Metadata: map[string]string{"chat_type": "group"}}, true},
+		{channel.Message{ChannelID: "web:alice", Metadata: map[string]string{"chat_type": "group"}}, false},
+		{channel.Message{ChannelID: "feishu:p2p"}, false},
+	}
+	for _, c := range cases {
+		if got := isGroupChat(&c.msg); got != c.want {
+			t.Errorf("isGroupChat(%s, %q) = %v, want %v", c.msg.ChannelID, c.msg.Metadata["chat_type"], got, c.want)
+		}
+	}
+}
+
+// namedChannel is a channel.Channel stub that only reports its name.
+type namedChannel string
+
+func (c namedChannel) Name() string                                { return string(c) }
+func (namedChannel) Start(context.Context) error                   { return nil }
+func (namedChannel) Stop() error                                   { return nil }
+func (namedChannel) Send(context.Context, *channel.Response) error { return nil }
+func (namedChannel) Messages() <-chan *channel.Message             { return nil }
+
+// userMessageProvider reports the last user message of each request.
+type userMessageProvider struct {
+	seen chan string
+}
+
+func (p *userMessageProvider) Chat(_ context.Context, req *provider.Request) (provider.ChatResult, error) {
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			p.seen <- req.Messages[i].Content
+			break
+		}
+	}
+	return provider.NewBasicResult(&provider.Response{Content: "ok"}), nil
+}
+
+func TestMessageHookTransformsTextBeforeWake(t *testing.T) {
+	p := &userMessageProvider{seen: make(chan string, 1)}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+	cfg := &config.Config{}
+	cfg.Thread.Workspace = t.TempDir()
+	d := &Dispatcher{cfg: cfg, threads: mgr}
+
+	stripPrefix := func(_ context.Context, _ *channel.Message, text string) string {
+		return strings.TrimPrefix(text, "!ask ")
+	}
+	d.SetMessageHooks(append([]MessageHook{stripPrefix}, d.DefaultMessageHooks()...)...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	d.dispatch(ctx, namedChannel("telegram"), &channel.Message{
+		ChannelID: "telegram:-100",
+		UserID:    "42",
+		Username:  "Bob",
+		Text:      "!ask what time is it",
+		Metadata:  map[string]string{"chat_type": "group"},
+	})
+
+	select {
+	case got := <-p.seen:
+		if strings.Contains(got, "!ask") {
+			t.Errorf("prefix not stripped:\n%s", got)
+		}
+		if !strings.Contains(got, "[Bob]: what time is it") {
+			t.Errorf("default hooks should still tag the sender after the custom hook:\n%s", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message never reached the provider")
+	}
+}
+
+func TestAddMessageHookKeepsDefaults(t *testing.T) {
+	d := &Dispatcher{}
+	d.AddMessageHook(func(_ context.Context, _ *channel.Message, text string) string { return strings.ToUpper(text) })
+	got := d.preprocessMessage(context.Background(), &channel.Message{
+		Text:     "hi",
+		Username: "Alice",
+		Metadata: map[string]string{"chat_type": "group"},
+	})
+	if got != "[ALICE]: HI" {
+		t.Errorf("got %q, want the default sender tag followed by the appended hook", got)
+	}
+}
+
+func TestDispatchDedupIsPerChat(t *testing.T) {
+	p := &userMessageProvider{seen: make(chan string, 4)}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+	cfg := &config.Config{}
+	cfg.Thread.Workspace = t.TempDir()
+	d := &Dispatcher{cfg: cfg, threads: mgr, dedup: channel.NewDedupCache(time.Minute)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	for _, m := range []*channel.Message{
+		{ID: "7", ChannelID: "telegram:1", UserID: "1", Text: "first chat"},
+		{ID: "7", ChannelID: "telegram:2", UserID: "2", Text: "second chat"},
+		{ID: "7", ChannelID: "telegram:1", UserID: "1", Text: "first chat"}, // redelivery
+	} {
+		d.dispatch(ctx, namedChannel("telegram"), m)
+	}
+
+	got := map[string]bool{}
+	for len(got) < 2 {
+		select {
+		case text := <-p.seen:
+			for _, want := range []string{"first chat", "second chat"} {
+				if strings.Contains(text, want) {
+					got[want] = true
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("answered %v, want both chats", got)
+		}
+	}
+	select {
+	case text := <-p.seen:
+		t.Errorf("redelivered message answered again: %s", text)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/channel"
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/provider"
+)
+
+// translateRoute is the thread.models key that selects the translation model;
+// unmapped, the default model translates.
+const translateRoute = "translate"
+
+// translateTimeout bounds one translation call.
+const translateTimeout = 60 * time.Second
+
+// protectedSpan matches text the translator must leave untouched: fenced code
+// blocks, inline code and @-mentions.
+var protectedSpan = regexp.MustCompile("(?s)```.*?```|~~~.*?~~~|`[^`\n]+`|@[^\\s@]+")
+
+// translateInboundHook translates the user's text into the agent's working
+// language on channels with channels.<name>.translate set. It runs first, on
+// the raw message text.
+func (d *Dispatcher) translateInboundHook(ctx context.Context, msg *channel.Message, text string) string {
+	channelName, _, _ := strings.Cut(msg.ChannelID, ":")
+	tc := d.cfg.GetChannelTranslate(channelName)
+	if tc == nil {
+		return text
+	}
+	return d.translate(ctx, text, tc.Source, tc.Target)
+}
+
+// translateOutbound translates outgoing text back into the users' language
+// before it is sent on channelName. Installed as the channel manager's
+// TranslateFunc by NewDispatcher.
+func (d *Dispatcher) translateOutbound(ctx context.Context, channelName, text string) string {
+	tc := d.cfg.GetChannelTranslate(channelName)
+	if tc == nil {
+		return text
+	}
+	return d.translate(ctx, text, tc.Target, tc.Source)
+}
+
+// translate translates text from one language to another with the translate
+// route's model. Code and @-mentions are swapped for numbered markers first
+// and restored afterwards. On any failure the original text is returned.
+func (d *Dispatcher) translate(ctx context.Context, text, from, to string) string {
+	var spans []string
+	masked := protectedSpan.ReplaceAllStringFunc(text, func(s string) string {
+		spans = append(spans, s)
+		return translateMarker(len(spans) - 1)
+	})
+	if strings.TrimSpace(protectedSpan.ReplaceAllString(text, "")) == "" {
+		return text
+	}
+
+	out, err := d.callTranslator(ctx, masked, from, to)
+	if err != nil {
+		logger.Warn("translation failed, passing text through", "from", from, "to", to, "err", err)
+		return text
+	}
+	for i, s := range spans {
+		marker := translateMarker(i)
+		if !strings.Contains(out, marker) {
+			logger.Warn("translation dropped a protected span, passing text through", "from", from, "to", to, "marker", marker)
+			return text
+		}
+		out = strings.Replace(out, marker, s, 1)
+	}
+	return out
+}
+
+func translateMarker(i int) string {
+	return "⟦" + strconv.Itoa(i) + "⟧"
+}
+
+func (d *Dispatcher) callTranslator(ctx context.Context, text, from, to string) (string, error) {
+	if d.threads == nil {
+		return "", fmt.Errorf("no thread manager")
+	}
+	prov, err := d.threads.ProviderForRoute(translateRoute)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, translateTimeout)
+	defer cancel()
+
+	result, err := prov.Chat(ctx, &provider.Request{Messages: []provider.Message{
+		provider.SystemMessage(fmt.Sprintf("You are a translator. Translate the user's message from %s to %s. "+
+			"Keep every marker like ⟦0⟧ exactly as written and in place; it stands for code or a mention. "+
+			"Keep Markdown formatting. Reply with the translation only, no notes.", from, to)),
+		provider.UserMessage(text),
+	}})
+	if err != nil {
+		return "", err
+	}
+	resp, err := result.Wait()
+	if err != nil {
+		return "", err
+	}
+	out := strings.TrimSpace(resp.Content)
+	if out == "" {
+		return "", fmt.Errorf("empty translation")
+	}
+	return out, nil
+}
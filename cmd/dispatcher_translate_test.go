@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/channel"
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/thread"
+)
+
+// translatingProvider serves both the translator (system prompt starting
+// "You are a translator") and the agent, whose user messages it reports.
+type translatingProvider struct {
+	dict  map[string]string
+	agent chan string
+}
+
+func (p *translatingProvider) Chat(_ context.Context, req *provider.Request) (provider.ChatResult, error) {
+	last := req.Messages[len(req.Messages)-1].Content
+	if strings.HasPrefix(req.Messages[0].Content, "You are a translator") {
+		for from, to := range p.dict {
+			last = strings.ReplaceAll(last, from, to)
+		}
+		return provider.NewBasicResult(&provider.Response{Content: last}), nil
+	}
+	p.agent <- last
+	return provider.NewBasicResult(&provider.Response{Content: "Looks fine:\n```go\nfmt.Println(\"Looks fine\")\n```"}), nil
+}
+
+// captureChannel records the text of every response sent on it.
+type captureChannel struct {
+	namedChannel
+	sent chan string
+}
+
+func (c *captureChannel) Send(_ context.Context, resp *channel.Response) error {
+	c.sent <- resp.Text
+	return nil
+}
+
+func TestChannelTranslateInboundAndOutbound(t *testing.T) {
+	p := &translatingProvider{
+		dict:  map[string]string{"帮我看看": "please check", "Looks fine": "看起来没问题"},
+		agent: make(chan string, 1),
+	}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+	cfg := &config.Config{}
+	cfg.Thread.Workspace = t.TempDir()
+	cfg.Channels = &config.ChannelsConfig{Feishu: &config.FeishuChannelConfig{
+		ChannelTranslateConfig: config.ChannelTranslateConfig{Translate: &config.TranslateConfig{Source: "Chinese"}},
+	}}
+	ch := &captureChannel{namedChannel: "feishu", sent: make(chan string, 1)}
+	channels := channel.NewManager()
+	channels.Register(ch)
+	d := &Dispatcher{cfg: cfg, threads: mgr, channels: channels}
+	channels.SetTranslator(d.translateOutbound)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	d.dispatch(ctx, ch, &channel.Message{
+		ChannelID: "feishu:oc_1",
+		UserID:    "ou_1",
+		Text:      "@bot 帮我看看 `帮我看看()`\n```\n// 帮我看看\n```",
+		Metadata:  map[string]string{"chat_id": "oc_1"},
+	})
+
+	select {
+	case got := <-p.agent:
+		if !strings.Contains(got, "@bot please check `帮我看看()`\n```\n// 帮我看看\n```") {
+			t.Errorf("inbound not translated with code and mention intact:\n%s", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message never reached the agent")
+	}
+	select {
+	case got := <-ch.sent:
+		if got != "看起来没问题:\n```go\nfmt.Println(\"Looks fine\")\n```" {
+			t.Errorf("outbound = %q, want the prose translated and the code fence intact", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reply never sent")
+	}
+}
+
+// stallingTranslator blocks every translation until its context ends.
+type stallingTranslator struct {
+	started   chan struct{}
+	cancelled chan struct{}
+}
+
+func (p *stallingTranslator) Chat(ctx context.Context, req *provider.Request) (provider.ChatResult, error) {
+	if strings.HasPrefix(req.Messages[0].Content, "You are a translator") {
+		close(p.started)
+		<-ctx.Done()
+		close(p.cancelled)
+		return nil, ctx.Err()
+	}
+	return provider.NewBasicResult(&provider.Response{Content: "ok"}), nil
+}
+
+func TestSlowTranslationDoesNotBlockStop(t *testing.T) {
+	p := &stallingTranslator{started: make(chan struct{}), cancelled: make(chan struct{})}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+	cfg := &config.Config{}
+	cfg.Thread.Workspace = t.TempDir()
+	cfg.Channels = &config.ChannelsConfig{Feishu: &config.FeishuChannelConfig{
+		ChannelTranslateConfig: config.ChannelTranslateConfig{Translate: &config.TranslateConfig{Source: "Chinese"}},
+	}}
+	ch := &captureChannel{namedChannel: "feishu", sent: make(chan string, 4)}
+	channels := channel.NewManager()
+	channels.Register(ch)
+	d := &Dispatcher{cfg: cfg, threads: mgr, channels: channels}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	dispatched := make(chan struct{})
+	go func() {
+		d.dispatch(ctx, ch, &channel.Message{ChannelID: "feishu:oc_1", UserID: "ou_1", Text: "帮我看看", Metadata: map[string]string{"chat_id": "oc_1"}})
+		close(dispatched)
+	}()
+	select {
+	case <-dispatched:
+	case <-time.After(2 * time.Second):
+		t.Fatal("dispatch waited for the translation")
+	}
+	select {
+	case <-p.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("translation never started")
+	}
+
+	d.dispatch(ctx, ch, &channel.Message{ChannelID: "feishu:oc_1", UserID: "ou_1", Text: "/stop", Metadata: map[string]string{"chat_id": "oc_1"}})
+	select {
+	case <-p.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("/stop did not cancel the translation")
+	}
+}
+
+func TestChannelTranslateCronWokenTurn(t *testing.T) {
+	p := &translatingProvider{
+		dict:  map[string]string{"Looks fine": "看起来没问题"},
+		agent: make(chan string, 1),
+	}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+	cfg := &config.Config{}
+	cfg.Thread.Workspace = t.TempDir()
+	cfg.Channels = &config.ChannelsConfig{Feishu: &config.FeishuChannelConfig{
+		ChannelTranslateConfig: config.ChannelTranslateConfig{Translate: &config.TranslateConfig{Source: "Chinese"}},
+	}}
+	ch := &captureChannel{namedChannel: "feishu", sent: make(chan string, 1)}
+	channels := channel.NewManager()
+	channels.Register(ch)
+	NewDispatcher(channels, mgr, cfg)
+	sessionsDir, _ := cfg.SessionsDir()
+	mgr.SetDefaultSinkFor(buildDefaultSinkFor(channels, cfg, sessionsDir, mgr, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	// A cron wake with no sink of its own falls back to the
+	// session's default sink, which posts straight through the manager.
+	mgr.Wake("feishu:ou_1", &thread.WakeMessage{Source: thread.WakeCron, Message: "check the build"})
+
+	select {
+	case <-p.agent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cron wake never reached the agent")
+	}
+	select {
+	case got := <-ch.sent:
+		if !strings.HasPrefix(got, "看起来没问题") {
+			t.Errorf("outbound = %q, want the cron turn's reply translated", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reply never sent")
+	}
+}
@@ -126,7 +126,7 @@ func (s *heartbeatScheduler) scan(ctx context.Context) {
 	postponed := loadPostponeConfig(filepath.Join(workspace, "system", "heartbeat-postpone.json"))
 
 	opts := listSessionsOpts{Days: 2, UserOnly: true}
-	sessions, err := collectSessions(cfg, opts)
+	sessions, err := collectSessions(cfg, s.mgr.Sessions(), opts)
 	if err != nil {
 		logger.Warn("heartbeat scan: collectSessions failed", "err", err)
 		return
@@ -293,7 +293,7 @@ func (s *heartbeatScheduler) Status() []hbStatusEntry {
 	postponed := loadPostponeConfig(filepath.Join(workspace, "system", "heartbeat-postpone.json"))
 
 	opts := listSessionsOpts{Days: 2, UserOnly: true}
-	sessions, err := collectSessions(cfg, opts)
+	sessions, err := collectSessions(cfg, s.mgr.Sessions(), opts)
 	if err != nil {
 		return nil
 	}
@@ -51,6 +51,21 @@ func runListMemoryFiles(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get sessions dir: %w", err)
 	}
+	// Memory notes stay in session directories whatever the backend; the
+	// store maps each directory back to its real session key.
+	sessions, err := openSessions(cfg.GetSessionBackend(), sessionsDir)
+	if err != nil {
+		return err
+	}
+	defer sessions.Close()
+	stored, err := sessions.List(0)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	keyByDir := make(map[string]string, len(stored))
+	for _, sum := range stored {
+		keyByDir[sessions.Dir(sum.Key)] = sum.Key
+	}
 
 	today := time.Now().Format("2006-01-02")
 	cutoff := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
@@ -83,8 +98,10 @@ func runListMemoryFiles(_ *cobra.Command, _ []string) error {
 		}
 
 		sessionDir := filepath.Dir(dir)
-		sessionFile := filepath.Join(sessionDir, session.SessionFileName)
-		key := deriveSessionKey(sessionsDir, sessionFile)
+		key, ok := keyByDir[sessionDir]
+		if !ok {
+			key = deriveSessionKey(sessionsDir, filepath.Join(sessionDir, session.SessionFileName))
+		}
 
 		candidates = append(candidates, memoryFileEntry{
 			SessionKey: key,
@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -87,8 +86,13 @@ func runListSessions(_ *cobra.Command, _ []string) error {
 	}
 
 	if output == nil {
-		// Fallback to file scanning.
-		output, err = collectSessions(cfg, opts)
+		// Fallback: read the session store directly.
+		sessions, err := openConfigSessions(cfg)
+		if err != nil {
+			return err
+		}
+		defer sessions.Close()
+		output, err = collectSessions(cfg, sessions, opts)
 		if err != nil {
 			return err
 		}
@@ -153,47 +157,46 @@ func encodeSessionsOutput(output *listSessionsOutput) error {
 	return enc.Encode(wrapper)
 }
 
-// collectSessions scans session files on disk and returns a summary.
+// collectSessions lists sessions from the session store and returns a summary.
 // IsRunning defaults to false (only populated via RPC from a running serve).
-func collectSessions(cfg *config.Config, opts listSessionsOpts) (*listSessionsOutput, error) {
+func collectSessions(cfg *config.Config, sessions *session.Manager, opts listSessionsOpts) (*listSessionsOutput, error) {
 	days := opts.Days
 	workspace, err := cfg.WorkspacePath()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workspace: %w", err)
 	}
+	if sessions == nil {
+		return nil, fmt.Errorf("session store unavailable")
+	}
 
-	sessionsDir, err := cfg.SessionsDir()
+	stored, err := sessions.List(0)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sessions dir: %w", err)
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
 	summaries := loadSummariesFile(filepath.Join(workspace, "system", "sessions_summary.json"))
 	msgCounts := loadMessageCounts(filepath.Join(workspace, "system", "message_counts.json"))
 	cutoff := time.Now().AddDate(0, 0, -days)
 
 	var all []sessionEntry
-	total := 0
+	total := len(stored)
 
-	_ = filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil || d.IsDir() || d.Name() != session.SessionFileName {
-			return nil
-		}
-		key := deriveSessionKey(sessionsDir, path)
-		total++
+	for _, sum := range stored {
+		key := sum.Key
 
-		// Early exit for cron/threads — before any file I/O.
+		// Early exit for cron/threads — before loading any messages.
 		if opts.UserOnly && (strings.HasPrefix(key, "cron:") || strings.Contains(key, ":threads:")) {
-			return nil
+			continue
 		}
 
-		// Lightweight time check — reads only last line, no full deserialization.
-		updatedAt, _ := session.ReadUpdatedAt(path)
+		// Lightweight time check from the store listing, no deserialization.
+		updatedAt := sum.UpdatedAt
 		if updatedAt.IsZero() || updatedAt.Before(cutoff) {
-			return nil
+			continue
 		}
 
-		s, err := session.ReadFile(path)
+		s, err := sessions.Load(key)
 		if err != nil {
-			return nil
+			continue
 		}
 
 		tz := cfg.SessionTimezone(key)
@@ -203,9 +206,8 @@ func collectSessions(cfg *config.Config, opts listSessionsOpts) (*listSessionsOu
 		}
 
 		// Check for non-empty heartbeat file in the session directory.
-		sessionDir := filepath.Dir(path)
 		hasHeartbeat := false
-		if data, readErr := os.ReadFile(filepath.Join(sessionDir, "heartbeat.md")); readErr == nil {
+		if data, readErr := os.ReadFile(filepath.Join(sessions.Dir(key), "heartbeat.md")); readErr == nil {
 			hasHeartbeat = len(strings.TrimSpace(string(data))) > 0
 		}
 
@@ -220,7 +222,7 @@ func collectSessions(cfg *config.Config, opts listSessionsOpts) (*listSessionsOu
 		}
 
 		if opts.UserOnly && lastUserActiveAt == nil {
-			return nil
+			continue
 		}
 
 		entry := sessionEntry{
@@ -247,12 +249,11 @@ func collectSessions(cfg *config.Config, opts listSessionsOpts) (*listSessionsOu
 		}
 
 		if opts.ChangedOnly && (!entry.ChangedSinceSummary || entry.MessageCount == 0) {
-			return nil
+			continue
 		}
 
 		all = append(all, entry)
-		return nil
-	})
+	}
 
 	output := &listSessionsOutput{
 		Sessions:      all,
@@ -3,6 +3,8 @@ package cmd
 import (
 	"testing"
 	"time"
+
+	"github.com/linanwx/nagobot/provider"
 )
 
 func TestNeedSummaryFilter(t *testing.T) {
@@ -46,3 +48,26 @@ func TestNeedSummaryFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestCollectSessionsSQLite(t *testing.T) {
+	cfg, sessions := newSQLiteSessions(t)
+	user := provider.UserMessage("hello")
+	user.Source = "telegram"
+	if err := sessions.Append("telegram:42", user, provider.AssistantMessage("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if err := sessions.Append("cron:tidy", provider.UserMessage("tidy up")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := collectSessions(cfg, sessions, listSessionsOpts{Days: 2, UserOnly: true})
+	if err != nil {
+		t.Fatalf("collectSessions: %v", err)
+	}
+	if out.TotalSessions != 2 || len(out.Sessions) != 1 {
+		t.Fatalf("output = %+v, want 2 total and only the user session shown", out)
+	}
+	if got := out.Sessions[0]; got.Key != "telegram:42" || got.MessageCount != 2 || got.LastUserActiveAt == nil {
+		t.Errorf("entry = %+v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// exec runs in a per-session work directory, so bundled skills and agents
+// must not call workspace scripts by a relative path.
+func TestTemplateExecCallsUseWorkspaceScriptPaths(t *testing.T) {
+	for _, root := range []string{"templates/skills", "templates/agents"} {
+		err := fs.WalkDir(templateFS, root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".md") {
+				return err
+			}
+			data, err := templateFS.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for i, line := range strings.Split(string(data), "\n") {
+				if !strings.Contains(line, "exec:") || !strings.Contains(line, "scripts/") {
+					continue
+				}
+				if !strings.Contains(line, "{{WORKSPACE}}/scripts/") && !strings.Contains(line, "<workspace>/scripts/") {
+					t.Errorf("%s:%d runs a script by a relative path: %s", path, i+1, strings.TrimSpace(line))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -2,12 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/linanwx/nagobot/config"
 	"github.com/linanwx/nagobot/provider"
-	"github.com/linanwx/nagobot/session"
 	"github.com/linanwx/nagobot/tools"
 	"github.com/spf13/cobra"
 )
@@ -44,12 +42,7 @@ func runReadSession(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-	workspace, err := cfg.WorkspacePath()
-	if err != nil {
-		return fmt.Errorf("failed to get workspace: %w", err)
-	}
-
-	messages, totalCount, err := loadSessionMessages(workspace, key)
+	messages, totalCount, err := loadSessionMessages(cfg, key)
 	if err != nil {
 		return err
 	}
@@ -116,14 +109,20 @@ func runReadSession(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-// loadSessionMessages reads a session JSONL file and returns raw messages + total count.
-func loadSessionMessages(workspace, key string) ([]provider.Message, int, error) {
-	sessionsDir := filepath.Join(workspace, "sessions")
-	sessionPath := filepath.Join(session.SessionDir(sessionsDir, key), session.SessionFileName)
-
-	s, err := session.ReadFile(sessionPath)
+// loadSessionMessages reads a session from the configured store and returns
+// raw messages + total count.
+func loadSessionMessages(cfg *config.Config, key string) ([]provider.Message, int, error) {
+	sessions, err := openConfigSessions(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer sessions.Close()
+	if !sessions.Exists(key) {
+		return nil, 0, fmt.Errorf("session %q not found", key)
+	}
+	s, err := sessions.Load(key)
 	if err != nil {
-		return nil, 0, fmt.Errorf("session %q not found: %w", key, err)
+		return nil, 0, fmt.Errorf("failed to load session %q: %w", key, err)
 	}
 	return s.Messages, len(s.Messages), nil
 }
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/linanwx/nagobot/channel"
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/thread"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// reloadOnSIGHUP re-reads config each time the process receives SIGHUP and
+// applies it via reloadConfig. Runs until ctx is cancelled.
+func reloadOnSIGHUP(ctx context.Context, startup *config.Config, chMgr *channel.Manager, threadMgr *thread.Manager) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	current := startup
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hupCh:
+			next, err := config.Load()
+			if err != nil {
+				logger.Error("config reload failed, keeping current config", "err", err)
+				continue
+			}
+			restart := reloadConfig(current, next, chMgr, threadMgr)
+			current = next
+			if len(restart) > 0 {
+				logger.Warn("config reloaded; some changes require a restart", "settings", restart)
+			} else {
+				logger.Info("config reloaded")
+			}
+		}
+	}
+}
+
+// restartOnlySettings are read once when a channel connects; changing them
+// on a running channel has no effect until restart.
+var restartOnlySettings = []struct {
+	name string
+	get  func(*config.Config) string
+}{
+	{"channels.telegram.token", (*config.Config).GetTelegramToken},
+	{"channels.discord.token", (*config.Config).GetDiscordToken},
+	{"channels.feishu.appId", (*config.Config).GetFeishuAppID},
+	{"channels.feishu.appSecret", (*config.Config).GetFeishuAppSecret},
+	{"channels.wecom.botId", (*config.Config).GetWeComBotID},
+	{"channels.wecom.secret", (*config.Config).GetWeComSecret},
+	{"channels.web.addr", (*config.Config).GetWebAddr},
+}
+
+// reloadConfig applies the hot-reloadable parts of next to the running
+// service: channel allowlists, the exec tool timeout, provider defaults and
+// sampling, the log level, secret masking, and outbound redaction. Model overrides and session
+// agents (meta.json) are already read per turn. Returns the restart-only
+// settings that differ between prev and next; each is logged.
+func reloadConfig(prev, next *config.Config, chMgr *channel.Manager, threadMgr *thread.Manager) []string {
+	logger.SetSecrets(next.Secrets())
+	if level := next.Logging.Level; level != "" {
+		logger.SetLevel(level)
+	}
+
+	chMgr.SetRedaction(next)
+	chMgr.Each(func(ch channel.Channel) {
+		if rc, ok := ch.(channel.Reconfigurable); ok {
+			rc.Reconfigure(next)
+		}
+	})
+
+	if err := tools.SetWebHTTPConfig(webHTTPConfig(next)); err != nil {
+		logger.Warn("config reload: web proxy not applied", "err", err)
+	}
+
+	if threadMgr != nil {
+		if err := threadMgr.ReloadConfig(next); err != nil {
+			logger.Warn("config reload: provider settings not applied", "err", err)
+		}
+	}
+
+	var restart []string
+	for _, s := range restartOnlySettings {
+		old := s.get(prev)
+		if old == "" || old == s.get(next) {
+			// A newly added token is picked up by refreshChannelsLoop.
+			continue
+		}
+		logger.Warn("config reload: setting changed, restart required", "setting", s.name)
+		restart = append(restart, s.name)
+	}
+	return restart
+}
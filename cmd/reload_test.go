@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/linanwx/nagobot/channel"
+	"github.com/linanwx/nagobot/config"
+)
+
+// allowlistChannel records the Telegram allowlist pushed by Reconfigure.
+type allowlistChannel struct {
+	allowed []int64
+}
+
+func (c *allowlistChannel) Name() string                                  { return "telegram" }
+func (c *allowlistChannel) Start(context.Context) error                   { return nil }
+func (c *allowlistChannel) Stop() error                                   { return nil }
+func (c *allowlistChannel) Send(context.Context, *channel.Response) error { return nil }
+func (c *allowlistChannel) Messages() <-chan *channel.Message             { return nil }
+func (c *allowlistChannel) Reconfigure(cfg *config.Config)                { c.allowed = cfg.GetTelegramAllowedIDs() }
+
+func telegramConfig(token string, allowed ...int64) *config.Config {
+	return &config.Config{Channels: &config.ChannelsConfig{
+		Telegram: &config.TelegramChannelConfig{Token: token, AllowedIDs: allowed},
+	}}
+}
+
+func TestReloadConfigAppliesAllowlist(t *testing.T) {
+	ch := &allowlistChannel{allowed: []int64{1}}
+	chMgr := channel.NewManager()
+	chMgr.Register(ch)
+
+	prev := telegramConfig("tok", 1)
+	next := telegramConfig("tok", 1, 2)
+	if restart := reloadConfig(prev, next, chMgr, nil); len(restart) != 0 {
+		t.Errorf("restart required = %v, want none", restart)
+	}
+	if !slices.Equal(ch.allowed, []int64{1, 2}) {
+		t.Errorf("allowlist = %v, want [1 2]", ch.allowed)
+	}
+}
+
+func TestReloadConfigReportsTokenChange(t *testing.T) {
+	chMgr := channel.NewManager()
+	restart := reloadConfig(telegramConfig("old"), telegramConfig("new"), chMgr, nil)
+	if !slices.Equal(restart, []string{"channels.telegram.token"}) {
+		t.Errorf("restart required = %v, want [channels.telegram.token]", restart)
+	}
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"io/fs"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -23,33 +21,36 @@ type resumeCandidate struct {
 	key   string
 	body  string
 	agent string
+	admin bool // the interrupted message came from an admin wake
 }
 
 // scanInterruptedSessions identifies sessions that were interrupted mid-execution.
-// This is a pure read operation — no wakes are sent.
-func scanInterruptedSessions(sessionsDir string) []resumeCandidate {
+// This is a pure read operation — no wakes are sent. Sessions are read through
+// the session store, so the scan works with every backend.
+func scanInterruptedSessions(sessions *session.Manager) []resumeCandidate {
+	if sessions == nil {
+		return nil
+	}
+	summaries, err := sessions.List(0)
+	if err != nil {
+		logger.Warn("resume: failed to list sessions", "err", err)
+		return nil
+	}
 	cutoff := time.Now().Add(-resumeMaxAge)
 	var candidates []resumeCandidate
 
-	_ = filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil || d.IsDir() || d.Name() != session.SessionFileName {
-			return nil
-		}
-
-		key := session.DeriveKeyFromPath(path)
+	for _, sum := range summaries {
+		key := sum.Key
 
 		// Layer 1: prefix filter — resumable channels + their child threads.
 		if !isResumableSessionKey(key) {
-			return nil
+			continue
 		}
 
-		// Layer 2: timestamp filter — read only the last message's timestamp.
-		lastMsg, err := session.ReadLastMessage(path)
-		if err != nil {
-			return nil // empty or unreadable — skip
-		}
-		if lastMsg.Timestamp.Before(cutoff) {
-			return nil
+		// Layer 2: timestamp filter — the listing already carries the last
+		// update time, so stale sessions are skipped without loading them.
+		if sum.UpdatedAt.Before(cutoff) {
+			continue
 		}
 
 		// Layer 3: full load → find the resumable user message, then check
@@ -57,35 +58,34 @@ func scanInterruptedSessions(sessionsDir string) []resumeCandidate {
 		// message, because a system turn (e.g. heartbeat) may have been
 		// interrupted after the user's turn already completed — checking the
 		// tail would incorrectly resume with an old user message.
-		sess, err := session.ReadFile(path)
-		if err != nil {
-			return nil
+		sess, err := sessions.Load(key)
+		if err != nil || len(sess.Messages) == 0 {
+			continue
 		}
 
 		origMsg, userIdx, ok := findLastUserMessage(sess.Messages)
-		body := ""
-		agent := ""
 		if !ok || isUserTurnComplete(sess.Messages, userIdx) {
-			return nil
+			continue
 		}
 
-		body = origMsg.Content
+		body := origMsg.Content
 		if runes := []rune(body); len(runes) > 1000 {
 			body = string(runes[:1000]) + "\n... (truncated)"
 		}
+		agent := ""
 		if yamlBlock, _, fmOk := thread.SplitFrontmatter(origMsg.Content); fmOk {
 			agent = thread.ExtractFrontmatterValue(yamlBlock, "agent")
 		}
 
+		lastMsg := sess.Messages[len(sess.Messages)-1]
 		logger.Info("found interrupted session",
 			"sessionKey", key,
 			"lastRole", lastMsg.Role,
 			"lastTimestamp", lastMsg.Timestamp.Format(time.RFC3339),
 			"agent", agent,
 		)
-		candidates = append(candidates, resumeCandidate{key: key, body: body, agent: agent})
-		return nil
-	})
+		candidates = append(candidates, resumeCandidate{key: key, body: body, agent: agent, admin: origMsg.Admin})
+	}
 	return candidates
 }
 
@@ -97,6 +97,7 @@ func sendResumeWakes(mgr *thread.Manager, candidates []resumeCandidate) {
 			Source:    thread.WakeResume,
 			Message:   c.body,
 			AgentName: c.agent,
+			Admin:     c.admin,
 		})
 	}
 	if len(candidates) > 0 {
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/linanwx/nagobot/config"
 	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
 	"github.com/linanwx/nagobot/thread"
 )
 
@@ -287,3 +291,39 @@ func TestExtractAgentFromLastMessage(t *testing.T) {
 		}
 	})
 }
+
+// newSQLiteSessions opens a sqlite-backed session manager in a temp workspace.
+func newSQLiteSessions(t *testing.T) (*config.Config, *session.Manager) {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Thread.Workspace = t.TempDir()
+	cfg.Thread.SessionBackend = session.BackendSQLite
+	sessions, err := openConfigSessions(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sessions.Close() })
+	return cfg, sessions
+}
+
+func TestScanInterruptedSessionsSQLite(t *testing.T) {
+	_, sessions := newSQLiteSessions(t)
+	interrupted := provider.UserMessage("summarize my inbox")
+	interrupted.Source, interrupted.Admin = "telegram", true
+	if err := sessions.Append("telegram:42", interrupted); err != nil {
+		t.Fatal(err)
+	}
+	done := provider.UserMessage("hi")
+	done.Source = "discord"
+	if err := sessions.Append("discord:7", done, provider.AssistantMessage("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := scanInterruptedSessions(sessions)
+	if len(got) != 1 || got[0].key != "telegram:42" || got[0].body != "summarize my inbox" || !got[0].admin {
+		t.Fatalf("candidates = %+v, want the interrupted admin telegram turn", got)
+	}
+	if _, err := os.Stat(filepath.Join(sessions.Dir("telegram:42"), session.SessionFileName)); err == nil {
+		t.Error("sqlite backend wrote a session.jsonl")
+	}
+}
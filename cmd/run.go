@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/linanwx/nagobot/agent"
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/thread"
+	"github.com/spf13/cobra"
+)
+
+// runStatelessSessionKey names the thread for one-shot runs without
+// --session. Sessions are disabled for those runs, so nothing is persisted.
+const runStatelessSessionKey = "cli:run"
+
+var (
+	runSessionKey string
+	runJSON       bool
+	runRaw        bool
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [prompt | -]",
+	Short: "Run a single agent turn and print the reply",
+	Long: `Run a single agent turn without the serve loop and print the reply.
+
+The prompt is taken from the arguments, or from stdin when the only
+argument is "-" or none is given. Without --session the turn is stateless:
+no history is loaded or saved. With --raw the prompt goes to the configured
+provider/model as a bare completion: no system prompt, tools, or wake
+header. Exits non-zero if the turn fails.
+
+Examples:
+  nagobot run "summarize today's news"
+  echo "translate to French: good morning" | nagobot run -
+  nagobot run --session cli:notes --json "what did I ask earlier?"
+  nagobot run --raw "Classify the sentiment (positive/negative): I love it"`,
+	RunE: runOneShot,
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runSessionKey, "session", "", "Run in this session, loading and saving its history")
+	runCmd.Flags().BoolVar(&runJSON, "json", false, "Print {response, usage} as JSON")
+	runCmd.Flags().BoolVar(&runRaw, "raw", false, "Send the prompt alone, without system prompt, tools or session")
+	rootCmd.AddCommand(runCmd)
+}
+
+func runOneShot(cmd *cobra.Command, args []string) error {
+	prompt, err := readRunPrompt(args, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	sessionKey := strings.TrimSpace(runSessionKey)
+	if runRaw {
+		if sessionKey != "" {
+			return fmt.Errorf("--raw cannot be combined with --session")
+		}
+		return runRawCompletion(cmd, cfg, prompt)
+	}
+	threadMgr, _, _, err := buildThreadManager(cfg, sessionKey != "")
+	if err != nil {
+		return err
+	}
+	if sessionKey == "" {
+		sessionKey = runStatelessSessionKey
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result := runTurn(ctx, threadMgr, sessionKey, prompt)
+	if result.Err != nil {
+		return result.Err
+	}
+	return printRunResult(cmd.OutOrStdout(), result, runJSON)
+}
+
+// runRawCompletion sends prompt to the default provider/model via
+// agent.Complete and prints the reply.
+func runRawCompletion(cmd *cobra.Command, cfg *config.Config, prompt string) error {
+	factory, err := provider.NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		return fmt.Errorf("failed to create provider factory: %w", err)
+	}
+	prov, err := factory.Create("", "")
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	response, usage, err := agent.Complete(ctx, prov, prompt)
+	if err != nil {
+		return err
+	}
+	return printRunResult(cmd.OutOrStdout(), thread.TurnResult{
+		Response: response,
+		Usage: thread.TurnUsage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+			CachedTokens:     usage.CachedTokens,
+			ReasoningTokens:  usage.ReasoningTokens,
+		},
+	}, runJSON)
+}
+
+// readRunPrompt returns the prompt from args, or from stdin when args are
+// empty or a single "-".
+func readRunPrompt(args []string, stdin io.Reader) (string, error) {
+	prompt := strings.Join(args, " ")
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read prompt from stdin: %w", err)
+		}
+		prompt = string(data)
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return "", fmt.Errorf("prompt is empty.\nFix: nagobot run \"<prompt>\" or echo \"<prompt>\" | nagobot run -")
+	}
+	return prompt, nil
+}
+
+// runTurn wakes sessionKey with prompt and waits for the turn to finish.
+// The manager runs only for the duration of the call.
+func runTurn(ctx context.Context, mgr *thread.Manager, sessionKey, prompt string) thread.TurnResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go mgr.Run(ctx)
+
+	done := make(chan thread.TurnResult, 1)
+	mgr.Wake(sessionKey, &thread.WakeMessage{
+		Source:   thread.WakeCLI,
+		Message:  prompt,
+		OnResult: func(r thread.TurnResult) { done <- r },
+	})
+	select {
+	case r := <-done:
+		return r
+	case <-ctx.Done():
+		return thread.TurnResult{Err: ctx.Err()}
+	}
+}
+
+func printRunResult(w io.Writer, result thread.TurnResult, asJSON bool) error {
+	if !asJSON {
+		_, err := fmt.Fprintln(w, strings.TrimSpace(result.Response))
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Response string           `json:"response"`
+		Usage    thread.TurnUsage `json:"usage"`
+	}{strings.TrimSpace(result.Response), result.Usage})
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/thread"
+)
+
+type stubRunProvider struct {
+	resp *provider.Response
+	err  error
+}
+
+func (p *stubRunProvider) Chat(context.Context, *provider.Request) (provider.ChatResult, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return provider.NewBasicResult(p.resp), nil
+}
+
+func TestRunTurnReturnsResponseAndUsage(t *testing.T) {
+	p := &stubRunProvider{resp: &provider.Response{
+		Content: "Bonjour",
+		Usage:   provider.Usage{PromptTokens: 120, CompletionTokens: 3, TotalTokens: 123},
+	}}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := runTurn(ctx, mgr, runStatelessSessionKey, "translate to French: good morning")
+	if result.Err != nil {
+		t.Fatalf("runTurn: %v", result.Err)
+	}
+	if result.Response != "Bonjour" {
+		t.Errorf("response = %q, want %q", result.Response, "Bonjour")
+	}
+	if result.Usage.TotalTokens != 123 {
+		t.Errorf("usage = %+v, want 123 total tokens", result.Usage)
+	}
+
+	var out bytes.Buffer
+	if err := printRunResult(&out, result, true); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Response string           `json:"response"`
+		Usage    thread.TurnUsage `json:"usage"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("--json output is not JSON: %v\n%s", err, out.String())
+	}
+	if got.Response != "Bonjour" || got.Usage.PromptTokens != 120 {
+		t.Errorf("--json output = %+v", got)
+	}
+}
+
+func TestRunTurnReportsProviderError(t *testing.T) {
+	p := &stubRunProvider{err: errors.New("upstream down")}
+	mgr := thread.NewManager(&thread.ThreadConfig{DefaultProvider: p})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := runTurn(ctx, mgr, runStatelessSessionKey, "hello")
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "upstream down") {
+		t.Errorf("err = %v, want the provider error", result.Err)
+	}
+}
+
+func TestReadRunPrompt(t *testing.T) {
+	if got, _ := readRunPrompt([]string{"-"}, strings.NewReader("  from stdin\n")); got != "from stdin" {
+		t.Errorf("stdin prompt = %q", got)
+	}
+	if got, _ := readRunPrompt([]string{"hello", "world"}, strings.NewReader("ignored")); got != "hello world" {
+		t.Errorf("arg prompt = %q", got)
+	}
+	if _, err := readRunPrompt(nil, strings.NewReader("  ")); err == nil {
+		t.Error("expected an error for an empty prompt")
+	}
+}
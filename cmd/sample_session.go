@@ -33,12 +33,7 @@ func runSampleSession(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-	workspace, err := cfg.WorkspacePath()
-	if err != nil {
-		return fmt.Errorf("failed to get workspace: %w", err)
-	}
-
-	messages, totalCount, err := loadSessionMessages(workspace, key)
+	messages, totalCount, err := loadSessionMessages(cfg, key)
 	if err != nil {
 		return err
 	}
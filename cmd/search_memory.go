@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -89,10 +88,11 @@ func runKeywordSearch(args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-	sessionsDir, err := cfg.SessionsDir()
+	sessions, err := openConfigSessions(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get sessions dir: %w", err)
+		return err
 	}
+	defer sessions.Close()
 	cutoff := time.Now().AddDate(0, 0, -searchMemoryDays)
 
 	// Parse --after / --before time filters.
@@ -112,39 +112,29 @@ func runKeywordSearch(args []string) error {
 		}
 	}
 
-	// Collect all session directories.
-	type sessionDir struct {
-		key string
-		dir string
+	// Collect recently active sessions from the store.
+	stored, err := sessions.List(0)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
 	}
-	var dirs []sessionDir
-
-	_ = filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil || d.IsDir() || d.Name() != session.SessionFileName {
-			return nil
+	var keys []string
+	for _, sum := range stored {
+		if searchMemorySession != "" && sum.Key != searchMemorySession {
+			continue
 		}
-		dir := filepath.Dir(path)
-		key := deriveSessionKey(sessionsDir, path)
-
-		if searchMemorySession != "" && key != searchMemorySession {
-			return nil
-		}
-
-		// Check recency via last message timestamp.
-		if ts, err := session.ReadUpdatedAt(path); err != nil || ts.IsZero() || ts.Before(cutoff) {
-			return nil
+		// Check recency via the store's last update time.
+		if sum.UpdatedAt.IsZero() || sum.UpdatedAt.Before(cutoff) {
+			continue
 		}
-
-		dirs = append(dirs, sessionDir{key: key, dir: dir})
-		return nil
-	})
+		keys = append(keys, sum.Key)
+	}
 
 	// Merge messages from session + history, dedup by ID.
 	var allHits []searchHit
 	scanned := 0
 
-	for _, sd := range dirs {
-		merged := mergeSessionMessages(sd.dir)
+	for _, key := range keys {
+		merged := mergeSessionMessages(sessions, key)
 		for _, m := range merged {
 			scanned++
 			if m.Content == "" {
@@ -171,7 +161,7 @@ func runKeywordSearch(args []string) error {
 				ts = m.Timestamp.Format(time.RFC3339)
 			}
 			allHits = append(allHits, searchHit{
-				SessionKey: sd.key,
+				SessionKey: key,
 				MessageID:  m.ID,
 				Role:       m.Role,
 				Timestamp:  ts,
@@ -227,10 +217,11 @@ func runContextBrowse(_ []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-	sessionsDir, err := cfg.SessionsDir()
+	sessions, err := openConfigSessions(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get sessions dir: %w", err)
+		return err
 	}
+	defer sessions.Close()
 
 	// Derive session key from message ID.
 	// Message ID format: sessionKey:unixMillis:seq (e.g. "telegram:5358956630:1772795279732:197")
@@ -240,10 +231,7 @@ func runContextBrowse(_ []string) error {
 		return fmt.Errorf("cannot derive session key from message ID %q", searchMemoryContext)
 	}
 
-	// Build session directory path.
-	sessionDir := session.SessionDir(sessionsDir, sessionKey)
-
-	merged := mergeSessionMessages(sessionDir)
+	merged := mergeSessionMessages(sessions, sessionKey)
 	if len(merged) == 0 {
 		return fmt.Errorf("no messages found for session %q", sessionKey)
 	}
@@ -312,14 +300,14 @@ func deriveSessionKeyFromMsgID(msgID string) string {
 	return strings.Join(parts[:len(parts)-2], ":")
 }
 
-// mergeSessionMessages loads session.jsonl + all history/*.jsonl, deduplicates by message ID.
-// For messages without an ID (legacy format), uses a content hash as dedup key.
-func mergeSessionMessages(dir string) []provider.Message {
+// mergeSessionMessages loads the stored session + all history/*.jsonl backups,
+// deduplicates by message ID. For messages without an ID (legacy format), uses
+// a content hash as dedup key.
+func mergeSessionMessages(sessions *session.Manager, key string) []provider.Message {
 	seen := make(map[string]bool)
 	var all []provider.Message
 
-	addMessages := func(path string) {
-		s, err := session.ReadFile(path)
+	addMessages := func(s *session.Session, err error) {
 		if err != nil {
 			return
 		}
@@ -338,17 +326,18 @@ func mergeSessionMessages(dir string) []provider.Message {
 	}
 
 	// Load history first (older), then current session (newer overrides if no ID).
-	historyDir := filepath.Join(dir, "history")
+	// Backups are files whatever the backend (see backupSession).
+	historyDir := filepath.Join(sessions.Dir(key), "history")
 	if entries, err := os.ReadDir(historyDir); err == nil {
 		for _, e := range entries {
 			if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
-				addMessages(filepath.Join(historyDir, e.Name()))
+				addMessages(session.ReadFile(filepath.Join(historyDir, e.Name())))
 			}
 		}
 	}
 
 	// Current session.
-	addMessages(filepath.Join(dir, session.SessionFileName))
+	addMessages(sessions.Load(key))
 
 	return all
 }
@@ -7,10 +7,8 @@ import (
 	"strings"
 
 	"github.com/go-telegram/bot"
-	"github.com/go-telegram/bot/models"
 	"github.com/linanwx/nagobot/channel"
 	"github.com/linanwx/nagobot/config"
-	"github.com/linanwx/nagobot/tgmd"
 	"github.com/linanwx/nagobot/tools"
 	"github.com/spf13/cobra"
 )
@@ -62,28 +60,14 @@ func runSend(_ *cobra.Command, _ []string) error {
 	}
 
 	ctx := context.Background()
-	chunks := channel.SplitMessage(strings.TrimSpace(sendText), channel.TelegramMaxMessageLength)
+	chunks := channel.SplitMessageUTF16(strings.TrimSpace(sendText), channel.MaxMessageLength(cfg, "telegram"))
 	var lastMsgID int
 	for _, chunk := range chunks {
-		htmlChunk := tgmd.Convert(chunk)
-		resp, sendErr := b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID:    chatID,
-			Text:      htmlChunk,
-			ParseMode: models.ParseModeHTML,
-		})
-		if sendErr != nil {
-			// Retry without formatting.
-			resp, retryErr := b.SendMessage(ctx, &bot.SendMessageParams{
-				ChatID: chatID,
-				Text:   chunk,
-			})
-			if retryErr != nil {
-				return fmt.Errorf("telegram send error: %w", retryErr)
-			}
-			if resp != nil {
-				lastMsgID = resp.ID
-			}
-		} else if resp != nil {
+		resp, err := channel.SendTelegramMarkdown(ctx, b, chatID, chunk)
+		if err != nil {
+			return err
+		}
+		if resp != nil {
 			lastMsgID = resp.ID
 		}
 	}
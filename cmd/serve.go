@@ -46,6 +46,11 @@ Examples:
 	RunE: runServe,
 }
 
+// shutdownDrainTimeout bounds how long shutdown waits for in-flight turns.
+// Kept under launchd/systemd stop timeouts so the drain finishes before a
+// hard kill.
+const shutdownDrainTimeout = 15 * time.Second
+
 var (
 	serveTelegram bool
 	serveFeishu   bool
@@ -81,6 +86,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 	chManager := channel.NewManager()
 	chManager.WorkspaceFn = func() string { return workspace }
+	chManager.SetRedaction(cfg)
 
 	// Socket channel is always started for CLI client connections.
 	socketPath, err := config.SocketPath()
@@ -122,7 +128,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return nil, fmt.Errorf("load config: %w", err)
 			}
-			output, err := collectSessions(latestCfg, p)
+			output, err := collectSessions(latestCfg, threadMgr.Sessions(), p)
 			if err != nil {
 				return nil, err
 			}
@@ -177,6 +183,13 @@ func runServe(cmd *cobra.Command, args []string) error {
 	threadMgr.SetDefaultAgentFor(buildDefaultAgentFor(threadMgr))
 	sessionsDir, _ := cfg.SessionsDir()
 	threadMgr.SetDefaultSinkFor(buildDefaultSinkFor(chManager, cfg, sessionsDir, threadMgr, cronCh.FindJob))
+	threadMgr.SetChannelQueuesFn(func() []tools.HealthChannelQueueInfo {
+		var out []tools.HealthChannelQueueInfo
+		for _, q := range chManager.QueueDepths() {
+			out = append(out, tools.HealthChannelQueueInfo{Channel: q.Channel, Depth: q.Depth, Capacity: q.Capacity})
+		}
+		return out
+	})
 
 	// Wire system prompt and context budget lookups for the web dashboard.
 	if ch, ok := chManager.Get("web"); ok {
@@ -184,14 +197,18 @@ func runServe(cmd *cobra.Command, args []string) error {
 			webCh.SetSystemPromptFn(threadMgr.SystemPrompt)
 			webCh.SetToolDefsFn(threadMgr.ToolDefs)
 			webCh.SetContextBudgetFn(threadMgr.ContextBudget)
+			if sessions := threadMgr.Sessions(); sessions != nil {
+				webCh.SetSessions(sessions)
+			}
 		}
 	}
 
 	// Wire cron fires: every cron tick invokes this callback. A drop sink is
 	// attached so the cron-triggered turn's default output goes nowhere — the
 	// model must dispatch() explicitly. The deliveryLabel is mode-specific
-	// guidance rendered in the wake frontmatter.
-	cronCh.SetDirectWake(func(sessionKey string, source thread.WakeSource, message, agentName, deliveryLabel string) {
+	// guidance rendered in the wake frontmatter. admin comes from the job: only
+	// config, CLI and admin-created jobs may use admin-only tools.
+	cronCh.SetDirectWake(func(sessionKey string, source thread.WakeSource, message, agentName, deliveryLabel string, admin bool) {
 		dropSink := thread.Sink{
 			Label: deliveryLabel,
 			Send: func(_ context.Context, response string) error {
@@ -206,12 +223,22 @@ func runServe(cmd *cobra.Command, args []string) error {
 			Source:    source,
 			Message:   message,
 			AgentName: agentName,
+			Admin:     admin,
 			Sink:      dropSink,
 		})
 	})
 
+	// Deliver-mode cron jobs post straight to a channel, no agent turn.
+	cronCh.SetSender(chManager)
+
 	// Register shared tools.
 	threadMgr.RegisterTool(tools.NewCheckSessionTool(threadMgr))
+	threadMgr.RegisterTool(tools.NewThreadStatusTool(threadMgr))
+	threadMgr.RegisterTool(tools.NewRemindTool(cronCh))
+	threadMgr.RegisterTool(tools.NewScheduleMessageTool(cronCh))
+	threadMgr.RegisterTool(tools.NewListRemindersTool(cronCh))
+	threadMgr.RegisterTool(tools.NewCancelReminderTool(cronCh))
+	threadMgr.RegisterTool(tools.NewSetHeartbeatTool(cronCh))
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -234,21 +261,30 @@ func runServe(cmd *cobra.Command, args []string) error {
 	// Start thread manager run loop in background.
 	go threadMgr.Run(ctx)
 
-	// Resume interrupted sessions: scan immediately, send wakes after 15s delay
-	// to let channels stabilize (so defaultSink can deliver).
+	// Missed catch_up at jobs fire only now that every channel is up.
+	cronCh.CatchUp()
+
+	// Synchronous HTTP chat API (POST /v1/chat), when api.addr is set.
+	startChatAPI(ctx, cfg, threadMgr)
+
+	// Resume interrupted sessions and surface tool calls cut off by a crash:
+	// scan immediately, send wakes after 15s delay to let channels stabilize
+	// (so defaultSink can deliver).
 	go func() {
 		sessionsDir, err := cfg.SessionsDir()
 		if err != nil {
 			logger.Error("resume: failed to get sessions dir", "err", err)
 			return
 		}
-		candidates := scanInterruptedSessions(sessionsDir)
-		if len(candidates) == 0 {
+		candidates := scanInterruptedSessions(threadMgr.Sessions())
+		recoveries := scanToolJournals(sessionsDir)
+		if len(candidates) == 0 && len(recoveries) == 0 {
 			return
 		}
 		select {
 		case <-time.After(15 * time.Second):
 			sendResumeWakes(threadMgr, candidates)
+			sendJournalRecoveryWakes(threadMgr, recoveries)
 		case <-ctx.Done():
 		}
 	}()
@@ -271,9 +307,17 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	// Hot-reload: periodically check config for new/removed channel tokens.
 	go refreshChannelsLoop(ctx, chManager, dispatcher)
+	// SIGHUP re-reads config and applies the settings that can change live.
+	go reloadOnSIGHUP(ctx, cfg, chManager, threadMgr)
 
 	dispatcher.Run(ctx)
 
+	// The dispatcher has stopped accepting channel messages. Let in-flight
+	// turns finish and deliver before the channels go away.
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), shutdownDrainTimeout)
+	threadMgr.Drain(drainCtx)
+	drainCancel()
+
 	threadMgr.Shutdown()
 
 	if err := chManager.StopAll(); err != nil {
@@ -318,11 +362,13 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 					wakeMsg := sysmsg.BuildSystemMessage("child_completed", map[string]string{
 						"child_session": sessionKey,
 					}, strings.TrimSpace(response))
+					dryRun := readSessionMeta(sessionsDir, sessionKey).DryRun
 					threadMgr.Wake(parentKey, &thread.WakeMessage{
 						Source:           thread.WakeSession,
 						Message:          wakeMsg,
 						CallerSessionKey: sessionKey,
-						Sink:             thread.BuildPairedSessionSink(threadMgr, parentKey, sessionKey),
+						Sink:             thread.BuildPairedSessionSink(threadMgr, parentKey, sessionKey, dryRun, nil),
+						DryRun:           dryRun,
 					})
 					return nil
 				},
@@ -350,14 +396,17 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 			userID := strings.TrimPrefix(sessionKey, "telegram:")
 			if userID != "" {
 				return thread.Sink{
-					Label:      "your response will be sent to telegram user " + userID,
-					Chunkable: true,
+					Label:       "your response will be sent to telegram user " + userID,
+					Chunkable:   true,
+					UserChannel: true,
 					Send: func(ctx context.Context, response string) error {
 						if strings.TrimSpace(response) == "" {
 							return nil
 						}
 						return chMgr.SendTo(ctx, "telegram", response, userID)
 					},
+					SendFile:  sendFileFunc(chMgr, "telegram", userID),
+					SendEmbed: sendEmbedFunc(chMgr, "telegram", userID),
 				}
 			}
 		}
@@ -367,14 +416,17 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 			openID := strings.TrimPrefix(sessionKey, "feishu:")
 			if openID != "" {
 				return thread.Sink{
-					Label:      "your response will be sent to feishu user " + openID,
-					Chunkable: true,
+					Label:       "your response will be sent to feishu user " + openID,
+					Chunkable:   true,
+					UserChannel: true,
 					Send: func(ctx context.Context, response string) error {
 						if strings.TrimSpace(response) == "" {
 							return nil
 						}
 						return chMgr.SendTo(ctx, "feishu", response, "p2p:"+openID)
 					},
+					SendFile:  sendFileFunc(chMgr, "feishu", "p2p:"+openID),
+					SendEmbed: sendEmbedFunc(chMgr, "feishu", "p2p:"+openID),
 				}
 			}
 		}
@@ -388,14 +440,17 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 					replyTo = r.DiscordDM.ReplyTo
 				}
 				return thread.Sink{
-					Label:      "your response will be sent to discord channel " + channelID,
-					Chunkable: true,
+					Label:       "your response will be sent to discord channel " + channelID,
+					Chunkable:   true,
+					UserChannel: true,
 					Send: func(ctx context.Context, response string) error {
 						if strings.TrimSpace(response) == "" {
 							return nil
 						}
 						return chMgr.SendTo(ctx, "discord", response, replyTo)
 					},
+					SendFile:  sendFileFunc(chMgr, "discord", replyTo),
+					SendEmbed: sendEmbedFunc(chMgr, "discord", replyTo),
 				}
 			}
 		}
@@ -414,8 +469,9 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 					persistedReqID = r.WeCom.ReqID
 				}
 				return thread.Sink{
-					Label:     label,
-					Chunkable: true,
+					Label:       label,
+					Chunkable:   true,
+					UserChannel: true,
 					Send: func(ctx context.Context, response string) error {
 						if strings.TrimSpace(response) == "" {
 							return nil
@@ -436,8 +492,9 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 		if sessionKey == "cli" {
 			if _, ok := chMgr.Get("socket"); ok {
 				return thread.Sink{
-					Label:      "your response will be sent to the CLI client via socket",
-					Chunkable: true,
+					Label:       "your response will be sent to the CLI client via socket",
+					Chunkable:   true,
+					UserChannel: true,
 					Send: func(ctx context.Context, response string) error {
 						if strings.TrimSpace(response) == "" {
 							return nil
@@ -452,7 +509,6 @@ func buildDefaultSinkFor(chMgr *channel.Manager, cfg *config.Config, sessionsDir
 	}
 }
 
-
 type serveTargets struct {
 	telegram, feishu, discord, web, wecom bool
 }
@@ -514,9 +570,9 @@ func refreshChannelsLoop(ctx context.Context, chMgr *channel.Manager, dispatcher
 
 // channelSpec describes a dynamically loadable channel.
 type channelSpec struct {
-	name      string
-	hasToken  func(*config.Config) bool
-	newCh     func(*config.Config) channel.Channel
+	name     string
+	hasToken func(*config.Config) bool
+	newCh    func(*config.Config) channel.Channel
 }
 
 var dynamicChannels = []channelSpec{
@@ -531,6 +587,9 @@ func refreshChannels(ctx context.Context, chMgr *channel.Manager, dispatcher *Di
 	if err != nil {
 		return
 	}
+	// Pick up keys added since startup so new credentials are masked too.
+	logger.SetSecrets(cfg.Secrets())
+	chMgr.SetRedaction(cfg)
 
 	for _, spec := range dynamicChannels {
 		registered := chMgr.Has(spec.name)
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/monitor"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/thread"
+	"github.com/linanwx/nagobot/tools"
+	"github.com/spf13/cobra"
+)
+
+const defaultCompactTargetTokens = 32000
+
+var compactTargetTokens int
+
+var sessionCmd = &cobra.Command{
+	Use:     "session",
+	Short:   "Maintain stored sessions",
+	GroupID: "internal",
+}
+
+var sessionCompactCmd = &cobra.Command{
+	Use:   "compact <key>",
+	Short: "Summarize old messages and shrink a session below a token target",
+	Long: `Summarize a session's older messages with the summary model and keep
+only the recent turns, so the session fits within --target-tokens.
+
+The summary is appended to the session's daily memory file, and the original
+is backed up to <session_dir>/history/. Sessions already under the target are
+left unchanged. Run it while the session is idle.
+
+The model is taken from thread.models.summary in config.yaml when set,
+otherwise the default provider/model is used.
+
+Example:
+  nagobot session compact telegram:12345 --target-tokens 20000`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionCompact,
+}
+
+func init() {
+	sessionCompactCmd.Flags().IntVar(&compactTargetTokens, "target-tokens", defaultCompactTargetTokens, "Estimated token count to shrink the session below")
+	sessionCmd.AddCommand(sessionCompactCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
+
+// compactSummarizer summarizes the messages a compaction drops.
+type compactSummarizer func(ctx context.Context, msgs []provider.Message) (string, error)
+
+// compactResult describes a compaction. Messages is nil when Skipped is set.
+type compactResult struct {
+	Messages     []provider.Message
+	Summary      string
+	TokensBefore int
+	TokensAfter  int
+	Skipped      string
+}
+
+// compactMessages keeps the most recent turns that fit in half of
+// targetTokens and summarizes everything before them. The cut lands on a user
+// message outside any tool_calls→tool sequence; if the tail alone exceeds the
+// budget, the last turn is kept whole.
+func compactMessages(ctx context.Context, msgs []provider.Message, targetTokens int, summarize compactSummarizer) (compactResult, error) {
+	res := compactResult{TokensBefore: estimateSessionTokens(msgs)}
+	res.TokensAfter = res.TokensBefore
+	if res.TokensBefore <= targetTokens {
+		res.Skipped = "already under target"
+		return res, nil
+	}
+
+	budget := targetTokens / 2
+	cutoff := len(msgs)
+	for cutoff > 0 && estimateSessionTokens(msgs[cutoff-1:]) <= budget {
+		cutoff--
+	}
+	cutoff = safeTailCutoff(msgs, cutoff)
+	if cutoff >= len(msgs) {
+		// No user message after the budget line: keep the last turn.
+		for cutoff = len(msgs) - 1; cutoff > 0 && msgs[cutoff].Role != "user"; cutoff-- {
+		}
+	}
+	if cutoff <= 0 {
+		res.Skipped = "no earlier turns to summarize"
+		return res, nil
+	}
+
+	summary, err := summarize(ctx, msgs[:cutoff])
+	if err != nil {
+		return res, err
+	}
+	res.Summary = summary
+	res.Messages = append([]provider.Message{}, msgs[cutoff:]...)
+	res.TokensAfter = estimateSessionTokens(res.Messages)
+	return res, nil
+}
+
+// estimateSessionTokens estimates messages as the runtime sends them, with
+// Tier 1 compressed content applied.
+func estimateSessionTokens(msgs []provider.Message) int {
+	return thread.EstimateMessagesTokens(thread.ApplyCompressed(msgs))
+}
+
+func runSessionCompact(_ *cobra.Command, args []string) error {
+	key := args[0]
+	if compactTargetTokens <= 0 {
+		return fmt.Errorf("--target-tokens must be > 0")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	sessionsDir, err := cfg.SessionsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions dir: %w", err)
+	}
+	sessions, err := openSessions(cfg.GetSessionBackend(), sessionsDir)
+	if err != nil {
+		return err
+	}
+	defer sessions.Close()
+	if !sessions.Exists(key) {
+		return fmt.Errorf("session %q not found", key)
+	}
+	s, err := sessions.GetRaw(key)
+	if err != nil {
+		return fmt.Errorf("failed to read session %q: %w", key, err)
+	}
+	sessionDir := sessions.Dir(key)
+
+	summarize := func(ctx context.Context, msgs []provider.Message) (string, error) {
+		factory, err := provider.NewFactory(func() *config.Config { return cfg })
+		if err != nil {
+			return "", fmt.Errorf("failed to create provider factory: %w", err)
+		}
+		prov, err := factory.CreateForRoute(tools.SessionSummaryRoute)
+		if err != nil {
+			return "", fmt.Errorf("failed to create provider: %w", err)
+		}
+		summary, _, err := tools.SummarizeMessages(ctx, prov, msgs, 0)
+		return summary, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), summarizeSessionTimeout)
+	defer cancel()
+	res, err := compactMessages(ctx, s.Messages, compactTargetTokens, summarize)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]any{
+		"session_key":     key,
+		"target_tokens":   compactTargetTokens,
+		"tokens_before":   res.TokensBefore,
+		"messages_before": len(s.Messages),
+	}
+	if res.Skipped != "" {
+		fields["skipped"] = res.Skipped
+		fmt.Print(tools.CmdResult("session-compact", fields, "") + "\n")
+		return nil
+	}
+
+	now := time.Now()
+	backupPath, err := backupSession(sessionDir, s, now)
+	if err != nil {
+		return err
+	}
+	origMessages := s.Messages
+	s.Messages = res.Messages
+	if err := sessions.Save(s); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if _, err := session.AppendMemory(sessionDir, "Compression", res.Summary, now); err != nil {
+		logger.Warn("session compact: failed to append memory", "err", err)
+	}
+	if ws, err := cfg.WorkspacePath(); err == nil {
+		store := monitor.NewStore(filepath.Join(ws, "metrics"))
+		store.RecordCompression(buildCompressionRecord(now, key, origMessages, len(res.Messages)))
+	}
+
+	fields["tokens_after"] = res.TokensAfter
+	fields["messages_after"] = len(res.Messages)
+	fields["backup"] = backupPath
+	fmt.Print(tools.CmdResult("session-compact", fields, res.Summary) + "\n")
+	return nil
+}
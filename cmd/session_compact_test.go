@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+func compactTestSession(turns int) []provider.Message {
+	filler := strings.Repeat("lorem ipsum dolor sit amet ", 40)
+	var msgs []provider.Message
+	for i := 0; i < turns; i++ {
+		call := provider.ToolCall{ID: "call-" + string(rune('a'+i)), Type: "function"}
+		call.Function.Name = "read_file"
+		msgs = append(msgs,
+			provider.UserMessage("question "+string(rune('a'+i))),
+			provider.Message{Role: "assistant", ToolCalls: []provider.ToolCall{call}},
+			provider.Message{Role: "tool", ToolCallID: call.ID, Content: filler},
+			provider.AssistantMessage("answer "+string(rune('a'+i))),
+		)
+	}
+	return msgs
+}
+
+func TestCompactMessagesSummarizesOldTurns(t *testing.T) {
+	msgs := compactTestSession(10)
+	var summarized []provider.Message
+	stub := func(_ context.Context, m []provider.Message) (string, error) {
+		summarized = m
+		return "earlier questions a-f answered", nil
+	}
+
+	before := estimateSessionTokens(msgs)
+	target := before / 2
+	res, err := compactMessages(context.Background(), msgs, target, stub)
+	if err != nil {
+		t.Fatalf("compactMessages: %v", err)
+	}
+	if res.Skipped != "" {
+		t.Fatalf("unexpected skip: %s", res.Skipped)
+	}
+	if res.Summary != "earlier questions a-f answered" {
+		t.Errorf("summary = %q", res.Summary)
+	}
+	if len(res.Messages) >= len(msgs) || len(res.Messages) == 0 {
+		t.Fatalf("messages after = %d, before = %d", len(res.Messages), len(msgs))
+	}
+	if len(summarized)+len(res.Messages) != len(msgs) {
+		t.Errorf("summarized %d + kept %d != %d", len(summarized), len(res.Messages), len(msgs))
+	}
+	if res.TokensAfter > target || res.TokensBefore != before {
+		t.Errorf("tokens before/after = %d/%d, target %d", res.TokensBefore, res.TokensAfter, target)
+	}
+	if res.Messages[0].Role != "user" {
+		t.Errorf("kept tail starts with %q, want user", res.Messages[0].Role)
+	}
+	last := res.Messages[len(res.Messages)-1]
+	if last.Content != "answer j" {
+		t.Errorf("last kept message = %q, want the most recent turn", last.Content)
+	}
+}
+
+func TestCompactMessagesSkipsUnderTarget(t *testing.T) {
+	msgs := compactTestSession(2)
+	called := false
+	stub := func(context.Context, []provider.Message) (string, error) {
+		called = true
+		return "x", nil
+	}
+	res, err := compactMessages(context.Background(), msgs, 1_000_000, stub)
+	if err != nil {
+		t.Fatalf("compactMessages: %v", err)
+	}
+	if res.Skipped == "" || called || res.Messages != nil {
+		t.Errorf("expected skip without summarizing, got %+v (called=%v)", res, called)
+	}
+}
+
+func TestSafeTailCutoffKeepsToolPairs(t *testing.T) {
+	msgs := compactTestSession(3)
+	// Cuts inside the first turn (including on its tool result) move to the
+	// start of the next turn.
+	for _, start := range []int{1, 2, 3} {
+		if got := safeTailCutoff(msgs, start); got != 4 {
+			t.Errorf("safeTailCutoff(%d) = %d, want 4", start, got)
+		}
+	}
+}
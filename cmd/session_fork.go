@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/tools"
+	"github.com/spf13/cobra"
+)
+
+var sessionForkCmd = &cobra.Command{
+	Use:   "fork <src> <dst>",
+	Short: "Copy a session's messages into a new session",
+	Long: `Deep-copy the messages of session <src> into a new session <dst>, so an
+alternative can be explored without affecting the original. The agent, pins
+and model override in meta.json are copied too; channel routing is not.
+
+<dst> must be a new key (letters, digits, - _ . separated by ':'); an
+existing session is never overwritten.
+
+Example:
+  nagobot session fork telegram:12345 telegram:12345:experiment`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSessionFork,
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionForkCmd)
+}
+
+func runSessionFork(_ *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	sessionsDir, err := cfg.SessionsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions dir: %w", err)
+	}
+	mgr, err := openSessions(cfg.GetSessionBackend(), sessionsDir)
+	if err != nil {
+		return err
+	}
+	defer mgr.Close()
+
+	n, err := mgr.Copy(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Print(tools.CmdResult("session-fork", map[string]any{
+		"source":   args[0],
+		"new_key":  args[1],
+		"messages": n,
+	}, "") + "\n")
+	return nil
+}
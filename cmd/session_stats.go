@@ -84,7 +84,7 @@ func runSessionStats(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get workspace: %w", err)
 	}
 
-	messages, _, err := loadSessionMessages(workspace, key)
+	messages, _, err := loadSessionMessages(cfg, key)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/config"
+	sessionPkg "github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/tools"
+	"github.com/spf13/cobra"
+)
+
+var setDryRunCmd = &cobra.Command{
+	Use:     "set-dry-run",
+	Short:   "Enable or disable dry-run mode for a session",
+	GroupID: "internal",
+	Long: `Toggle dry-run mode for a session key in its meta.json.
+
+In dry-run mode, mutating tools (write_file, edit_file, apply_patch, exec) describe
+what they would do instead of doing it. Read-only tools behave normally. The
+setting takes effect on the next message in that session.
+
+Examples:
+  nagobot set-dry-run --session "discord:123456" --enabled true
+  nagobot set-dry-run --session "discord:123456" --enabled false`,
+	RunE: runSetDryRun,
+}
+
+var (
+	setDryRunSession string
+	setDryRunEnabled string
+)
+
+func init() {
+	setDryRunCmd.Flags().StringVar(&setDryRunSession, "session", "", "Session key (required)")
+	setDryRunCmd.Flags().StringVar(&setDryRunEnabled, "enabled", "", "Enable/disable dry-run mode (true/false, required)")
+	_ = setDryRunCmd.MarkFlagRequired("session")
+	_ = setDryRunCmd.MarkFlagRequired("enabled")
+	rootCmd.AddCommand(setDryRunCmd)
+}
+
+func runSetDryRun(_ *cobra.Command, _ []string) error {
+	session := strings.TrimSpace(setDryRunSession)
+	if session == "" {
+		return fmt.Errorf("--session is required")
+	}
+
+	var enabled bool
+	switch strings.ToLower(strings.TrimSpace(setDryRunEnabled)) {
+	case "true", "1", "yes":
+		enabled = true
+	case "false", "0", "no":
+		enabled = false
+	default:
+		return fmt.Errorf("invalid --enabled value %q (want true or false)", setDryRunEnabled)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	sessionsDir, err := cfg.SessionsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions dir: %w", err)
+	}
+	sessionDir := sessionPkg.SessionDir(sessionsDir, session)
+	sessionPkg.UpdateMeta(sessionDir, func(m *sessionPkg.Meta) {
+		m.DryRun = enabled
+	})
+
+	fmt.Print(tools.CmdOutput([][2]string{
+		{"command", "set-dry-run"}, {"status", "ok"}, {"session", session}, {"dry_run", fmt.Sprint(enabled)},
+	}, fmt.Sprintf("Set dry_run=%t for session %q.", enabled, session)) + "\n")
+	return nil
+}
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/linanwx/nagobot/config"
-	"github.com/linanwx/nagobot/session"
 	"github.com/linanwx/nagobot/tools"
 	"github.com/spf13/cobra"
 )
@@ -57,13 +56,23 @@ func runSetSummary(_ *cobra.Command, args []string) error {
 	}
 
 	// Cleanup entries whose session hasn't been active in 7+ days.
-	sessionsDir := filepath.Join(workspace, "sessions")
+	sessions, err := openConfigSessions(cfg)
+	if err != nil {
+		return err
+	}
+	defer sessions.Close()
+	stored, err := sessions.List(0)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	updatedAt := make(map[string]time.Time, len(stored))
+	for _, sum := range stored {
+		updatedAt[sum.Key] = sum.UpdatedAt
+	}
 	cutoff := time.Now().AddDate(0, 0, -7)
 	var cleaned []string
 	for k := range summaries {
-		sessionPath := filepath.Join(sessionsDir, filepath.FromSlash(strings.ReplaceAll(k, ":", "/")), session.SessionFileName)
-		ts, readErr := session.ReadUpdatedAt(sessionPath)
-		if readErr != nil || ts.IsZero() || ts.Before(cutoff) {
+		if ts := updatedAt[k]; ts.IsZero() || ts.Before(cutoff) {
 			cleaned = append(cleaned, k)
 			delete(summaries, k)
 		}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/tools"
+	"github.com/spf13/cobra"
+)
+
+const summarizeSessionTimeout = 2 * time.Minute
+
+var (
+	summarizeLast        int
+	summarizeWriteMemory bool
+)
+
+var summarizeSessionCmd = &cobra.Command{
+	Use:   "summarize-session <key>",
+	Short: "Summarize a session's conversation with a lightweight model",
+	Long: `Summarize a session's conversation as concise bullets.
+
+The model is taken from thread.models.summary in config.yaml when set,
+otherwise the default provider/model is used.
+
+Examples:
+  nagobot summarize-session telegram:12345
+  nagobot summarize-session telegram:12345 --last 5 --write-memory`,
+	GroupID: "internal",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runSummarizeSession,
+}
+
+func init() {
+	summarizeSessionCmd.Flags().IntVar(&summarizeLast, "last", 0, "Summarize only the last N turns (0 = whole session)")
+	summarizeSessionCmd.Flags().BoolVar(&summarizeWriteMemory, "write-memory", false, "Append the summary to the session's daily memory file")
+	rootCmd.AddCommand(summarizeSessionCmd)
+}
+
+func runSummarizeSession(_ *cobra.Command, args []string) error {
+	key := args[0]
+	if summarizeLast < 0 {
+		return fmt.Errorf("--last must be >= 0")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	sessions, err := openConfigSessions(cfg)
+	if err != nil {
+		return err
+	}
+	defer sessions.Close()
+	if !sessions.Exists(key) {
+		return fmt.Errorf("session %q not found", key)
+	}
+	s, err := sessions.Load(key)
+	if err != nil {
+		return fmt.Errorf("failed to load session %q: %w", key, err)
+	}
+	sessionDir := sessions.Dir(key)
+
+	factory, err := provider.NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		return fmt.Errorf("failed to create provider factory: %w", err)
+	}
+	prov, err := factory.CreateForRoute(tools.SessionSummaryRoute)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), summarizeSessionTimeout)
+	defer cancel()
+	summary, used, err := tools.SummarizeMessages(ctx, prov, s.Messages, summarizeLast)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]any{"session_key": key, "messages": used}
+	if summarizeWriteMemory {
+		path, err := session.AppendMemory(sessionDir, "Summary", summary, time.Now())
+		if err != nil {
+			return err
+		}
+		fields["memory_file"] = path
+	}
+	fmt.Print(tools.CmdResult("summarize-session", fields, summary) + "\n")
+	return nil
+}
@@ -63,6 +63,21 @@ func buildThreadManager(cfg *config.Config, enableSessions bool) (*thread.Manage
 	toolLogsDir := filepath.Join(workspace, "logs", "tool_calls")
 	toolRegistry.SetLogsDir(toolLogsDir)
 	tools.CleanupLogsDir(toolLogsDir)
+	if cfg.GetAuditLogEnabled() {
+		if auditDir, err := cfg.AuditLogDir(); err != nil {
+			logger.Warn("failed to resolve audit log dir, audit log disabled", "err", err)
+		} else {
+			toolRegistry.SetAuditLog(tools.NewAuditLog(auditDir, cfg.GetAuditRedactTools()))
+		}
+	}
+	if cfg.GetToolJournalEnabled() {
+		toolRegistry.SetToolJournal(tools.NewToolJournal())
+	}
+	toolRegistry.SetTimeouts(cfg.GetToolTimeouts())
+	toolRegistry.SetMaxResultBytes(cfg.GetToolMaxResultBytes())
+	if err := tools.SetWebHTTPConfig(webHTTPConfig(cfg)); err != nil {
+		logger.Warn("invalid web proxy, web tools use the environment proxy", "err", err)
+	}
 	// Build search providers (all registered; availability checked at call time via Available())
 	searchProviders := map[string]tools.SearchProvider{
 		"duckduckgo": &tools.DuckDuckGoProvider{},
@@ -77,6 +92,15 @@ func buildThreadManager(cfg *config.Config, enableSessions bool) (*thread.Manage
 				return c.GetSearchKey("brave")
 			},
 		},
+		"searxng": &tools.SearXNGProvider{
+			URLFn: func() string {
+				c, err := config.Load()
+				if err != nil {
+					return ""
+				}
+				return c.GetSearxngURL()
+			},
+		},
 		"opensearch": &tools.OpenSearchProvider{
 			KeyFn: func() string {
 				c, err := config.Load()
@@ -192,30 +216,54 @@ func buildThreadManager(cfg *config.Config, enableSessions bool) (*thread.Manage
 	}
 
 	toolRegistry.RegisterDefaultTools(workspace, tools.DefaultToolsConfig{
-		ExecTimeout:         cfg.GetExecTimeout(),
-		WebSearchMaxResults: cfg.GetWebSearchMaxResults(),
-		WebSearchGuide:      webSearchGuide,
-		SearchProviders:     searchProviders,
-		SearchHealthChecker: searchHealthChecker,
-		FetchProviders:      fetchProviders,
-		FetchHealthChecker:  fetchHealthChecker,
-		WebFetchGuide:       webFetchGuide,
-		RestrictToWorkspace: cfg.GetExecRestrictToWorkspace(),
-		Skills:              skillRegistry,
-		LogsDir:             logsDir,
+		ExecTimeout:            cfg.GetExecTimeout(),
+		ExecConfirmDestructive: cfg.GetExecConfirmDestructive(),
+		WebSearchMaxResults:    cfg.GetWebSearchMaxResults(),
+		WebSearchGuide:         webSearchGuide,
+		WebSearchCacheTTL:      cfg.GetWebSearchCacheTTL(),
+		SearchProviders:        searchProviders,
+		SearchBackend:          cfg.GetWebSearchBackend(),
+		SearchHealthChecker:    searchHealthChecker,
+		FetchProviders:         fetchProviders,
+		FetchHealthChecker:     fetchHealthChecker,
+		WebFetchGuide:          webFetchGuide,
+		RestrictToWorkspace:    cfg.GetExecRestrictToWorkspace(),
+		Skills:                 skillRegistry,
+		SkillsDir:              skillsDir,
+		SkillAllowedHosts:      cfg.GetLoadSkillAllowedHosts(),
+		LogsDir:                logsDir,
+		SummaryProvider: func() (provider.Provider, error) {
+			return providerFactory.CreateForRoute(tools.SessionSummaryRoute)
+		},
+		Embedder: &tools.OpenAIEmbedder{
+			SettingsFn: func() tools.EmbeddingSettings {
+				c, err := config.Load()
+				if err != nil {
+					return tools.EmbeddingSettings{}
+				}
+				e := c.GetEmbeddingsConfig()
+				return tools.EmbeddingSettings{APIBase: e.APIBase, APIKey: e.APIKey, Model: e.Model}
+			},
+		},
 	})
 
 	agentRegistry := agent.NewRegistry(workspace)
 
 	var sessions *session.Manager
 	if enableSessions {
-		sessions, err = session.NewManager(sessionsDir)
+		var store session.Store
+		store, err = session.OpenStore(cfg.GetSessionBackend(), sessionsDir)
+		if err == nil {
+			sessions, err = session.NewManagerWithStore(sessionsDir, store)
+		}
 		if err != nil {
-			logger.Warn("session manager unavailable", "err", err)
+			logger.Warn("session manager unavailable", "backend", cfg.GetSessionBackend(), "err", err)
 		}
 		if sessions != nil {
 			countsPath := filepath.Join(workspace, "system", "message_counts.json")
 			sessions.Counts = session.NewMessageCounts(countsPath)
+			toolRegistry.Register(tools.NewListSessionsTool(sessions))
+			toolRegistry.Register(tools.NewForkSessionTool(sessions))
 		}
 	}
 
@@ -257,6 +305,28 @@ func buildThreadManager(cfg *config.Config, enableSessions bool) (*thread.Manage
 		return info
 	}
 
+	// Per-channel prompt snippets re-read config each turn so edits apply
+	// without a restart.
+	channelPromptFor := func(channelName string) string {
+		pc := cfgFn().GetChannelPrompt(channelName)
+		if text := strings.TrimSpace(pc.Prompt); text != "" {
+			return text
+		}
+		path := strings.TrimSpace(pc.PromptFile)
+		if path == "" {
+			return ""
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workspace, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Warn("channel prompt file unreadable", "channel", channelName, "path", path, "err", err)
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+
 	return thread.NewManager(&thread.ThreadConfig{
 		DefaultProvider:     defaultProvider,
 		ProviderName:        cfg.Thread.Provider,
@@ -272,6 +342,11 @@ func buildThreadManager(cfg *config.Config, enableSessions bool) (*thread.Manage
 		MaxCompletionTokens:  cfg.Thread.MaxTokens,
 		Sessions:            sessions,
 		HealthChannelsFn:    healthChannelsFn,
+		ChannelPromptFor:    channelPromptFor,
+		ChannelToolsFor: func(channelName string) ([]string, []string) {
+			tc := cfgFn().GetChannelTools(channelName)
+			return tc.AllowTools, tc.DenyTools
+		},
 		ProviderFactory:     providerFactory,
 		Models:              cfg.Thread.Models,
 		ModelsFn: func() map[string]*config.ModelConfig {
@@ -284,9 +359,24 @@ func buildThreadManager(cfg *config.Config, enableSessions bool) (*thread.Manage
 		SessionTimezoneFor:  cfg.SessionTimezone,
 		MetricsStore:        metricsStore,
 		Sections:            initSectionRegistry(workspace),
+		MaxConcurrentTurns:     cfg.GetMaxConcurrentTurns(),
+		SubagentMaxConcurrency: cfg.GetSubagentMaxConcurrent(),
+		SubagentTimeout:        cfg.GetSubagentTimeout(),
+		SubagentMaxPerTurn:     cfg.GetSubagentMaxPerTurn(),
+		SubagentMaxPerHour:     cfg.GetSubagentMaxPerHour(),
+		IdleEvictAfter:         cfg.GetThreadIdleEvictAfter(),
+		ShowMetricsFooter:      cfg.Thread.ShowMetricsFooter,
+		ShowReasoning:          cfg.Thread.ShowReasoning,
+		MaxContinuations:       cfg.GetMaxContinuations(),
+		ToolResultMaxTokens:    cfg.GetToolResultMaxTokens(),
 	}), searchHealthChecker, fetchHealthChecker, nil
 }
 
+// webHTTPConfig extracts the web tools' User-Agent and proxy settings.
+func webHTTPConfig(cfg *config.Config) tools.WebHTTPConfig {
+	return tools.WebHTTPConfig{UserAgent: cfg.GetWebUserAgent(), Proxy: cfg.GetWebProxy()}
+}
+
 func initSectionRegistry(workspace string) *agent.SectionRegistry {
 	dir := filepath.Join(workspace, "system", "sections")
 	reg := agent.NewSectionRegistry(dir)
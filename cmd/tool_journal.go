@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/thread"
+	sysmsg "github.com/linanwx/nagobot/thread/msg"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// journalRecovery is a session whose tool journal has intents without a
+// result: mutating tool calls that were running when the process died.
+type journalRecovery struct {
+	key        string
+	sessionDir string
+	pending    []tools.JournalEntry
+}
+
+// scanToolJournals finds sessions with unfinished tool journal intents.
+// This is a pure read operation — no wakes are sent.
+func scanToolJournals(sessionsDir string) []journalRecovery {
+	var out []journalRecovery
+	_ = filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || d.IsDir() || d.Name() != tools.JournalFileName {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		pending, err := tools.PendingJournalEntries(dir)
+		if err != nil {
+			logger.Warn("tool journal unreadable", "path", path, "err", err)
+			return nil
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		key := session.DeriveKeyFromPath(filepath.Join(dir, session.SessionFileName))
+		logger.Info("found interrupted tool calls", "sessionKey", key, "count", len(pending))
+		out = append(out, journalRecovery{key: key, sessionDir: dir, pending: pending})
+		return nil
+	})
+	return out
+}
+
+// sendJournalRecoveryWakes tells each session which tool calls were cut off
+// so the agent can check their effects, then clears the surfaced journals.
+func sendJournalRecoveryWakes(mgr *thread.Manager, recoveries []journalRecovery) {
+	for _, r := range recoveries {
+		mgr.Wake(r.key, &thread.WakeMessage{
+			Source:  thread.WakeResume,
+			Message: journalRecoveryMessage(r.pending),
+			Admin:   journalAdmin(r.pending),
+		})
+		if err := tools.ClearJournal(r.sessionDir); err != nil {
+			logger.Warn("failed to clear tool journal", "sessionKey", r.key, "err", err)
+		}
+	}
+}
+
+// journalAdmin reports whether the interrupted calls came from an admin turn,
+// so the recovery wake gets the privileges of the turn it reconciles.
+func journalAdmin(pending []tools.JournalEntry) bool {
+	for _, e := range pending {
+		if !e.Admin {
+			return false
+		}
+	}
+	return len(pending) > 0
+}
+
+// journalRecoveryMessage lists interrupted calls for the agent to reconcile.
+func journalRecoveryMessage(pending []tools.JournalEntry) string {
+	var sb strings.Builder
+	sb.WriteString("The process stopped while these tool calls were running. Their side effects may be partial or complete. " +
+		"Inspect the current state (files, git status, processes) before retrying any of them, and tell the user only if it matters.\n")
+	for _, e := range pending {
+		fmt.Fprintf(&sb, "\n- %s at %s: %s", e.Tool, e.Time.Format(time.RFC3339), string(e.Args))
+	}
+	return sysmsg.BuildSystemMessage("tool_journal_recovery", map[string]string{
+		"interrupted_calls": fmt.Sprintf("%d", len(pending)),
+	}, sb.String())
+}
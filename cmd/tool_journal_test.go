@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/tools"
+)
+
+func TestScanToolJournalsFindsInterruptedCalls(t *testing.T) {
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	j := tools.NewToolJournal()
+
+	crashedDir := session.SessionDir(sessionsDir, "telegram:42")
+	j.Begin(crashedDir, "apply_patch", json.RawMessage(`{"patch":"*** Begin Patch"}`), false)
+
+	cleanDir := session.SessionDir(sessionsDir, "discord:7")
+	id := j.Begin(cleanDir, "exec", json.RawMessage(`{"command":"ls"}`), false)
+	j.Finish(cleanDir, id, true, "ok")
+
+	got := scanToolJournals(sessionsDir)
+	if len(got) != 1 {
+		t.Fatalf("scanToolJournals() = %+v, want one session", got)
+	}
+	if got[0].key != "telegram:42" || len(got[0].pending) != 1 || got[0].pending[0].Tool != "apply_patch" {
+		t.Fatalf("recovery = %+v, want telegram:42 with the apply_patch intent", got[0])
+	}
+
+	msg := journalRecoveryMessage(got[0].pending)
+	if !strings.Contains(msg, "tool_journal_recovery") || !strings.Contains(msg, "apply_patch") {
+		t.Fatalf("recovery message missing details: %s", msg)
+	}
+}
@@ -33,6 +33,7 @@ type Config struct {
 	Logging   LoggingConfig   `json:"logging,omitempty" yaml:"logging,omitempty"`
 	Cron      []cronpkg.Job   `json:"cron,omitempty" yaml:"cron,omitempty"`
 	SkillHub SkillHubConfig `json:"skillHub,omitempty" yaml:"skillHub,omitempty"`
+	API      *APIConfig     `json:"api,omitempty" yaml:"api,omitempty"` // synchronous HTTP chat API served by `nagobot serve`
 	Env      map[string]string `json:"env,omitempty" yaml:"env,omitempty"` // injected into os.Environ on Load; overrides existing env
 
 	// Hot-reload support for sessionTimezones.
@@ -124,6 +125,15 @@ type SkillHubConfig struct {
 	URL string `json:"url,omitempty" yaml:"url,omitempty"` // defaults to https://clawhub.ai
 }
 
+// APIConfig enables the synchronous HTTP chat API (POST /v1/chat): one
+// request runs one agent turn and returns its reply.
+type APIConfig struct {
+	Addr           string `json:"addr,omitempty" yaml:"addr,omitempty"`                     // listen address, e.g. 127.0.0.1:18090; empty disables the API
+	Token          string `json:"token,omitempty" yaml:"token,omitempty"`                   // required Bearer token; the API does not start without one
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"` // how long a request waits for its turn (default 300)
+	MaxConcurrent  int    `json:"maxConcurrent,omitempty" yaml:"maxConcurrent,omitempty"`   // turns in flight at once; more get 429 (default 4)
+}
+
 // ThreadConfig contains thread runtime defaults.
 type ThreadConfig struct {
 	Provider            string                  `json:"provider" yaml:"provider"` // openrouter, anthropic, deepseek, moonshot-cn, moonshot-global, xai
@@ -135,6 +145,31 @@ type ThreadConfig struct {
 	ContextWindowTokens int                     `json:"contextWindowTokens,omitempty" yaml:"contextWindowTokens,omitempty"` // defaults to 300000
 	Models              map[string]*ModelConfig `json:"models,omitempty" yaml:"models,omitempty"`                           // model type → provider/model mapping
 	Preview             *PreviewConfig          `json:"preview,omitempty" yaml:"preview,omitempty"`                         // override preview provider/model
+	Subagents           *SubagentsConfig        `json:"subagents,omitempty" yaml:"subagents,omitempty"`                     // subagent concurrency/timeout limits
+	MaxConcurrentTurns  int                     `json:"maxConcurrentTurns,omitempty" yaml:"maxConcurrentTurns,omitempty"`   // agent turns calling providers at once across all sessions (default 16)
+	IdleEvictMinutes    int                     `json:"idleEvictMinutes,omitempty" yaml:"idleEvictMinutes,omitempty"`       // free a session's thread after this many idle minutes; rebuilt from the saved session on the next message (default 180, negative disables)
+	CircuitBreaker      *CircuitBreakerConfig   `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`           // fail fast on a provider that keeps erroring
+	ShowMetricsFooter   bool                    `json:"showMetricsFooter,omitempty" yaml:"showMetricsFooter,omitempty"`     // append "— model · in/out tok · latency" to replies delivered to user channels
+	ShowReasoning       bool                    `json:"showReasoning,omitempty" yaml:"showReasoning,omitempty"`             // deliver model reasoning to the user (default off: stripped from replies)
+	MaxContinuations    int                     `json:"maxContinuations,omitempty" yaml:"maxContinuations,omitempty"`       // continue replies cut off by maxTokens up to this many times (default 2, negative disables)
+	ToolResultMaxTokens int                     `json:"toolResultMaxTokens,omitempty" yaml:"toolResultMaxTokens,omitempty"` // truncate each tool result to about this many tokens (default 16000, negative disables)
+	SessionBackend      string                  `json:"sessionBackend,omitempty" yaml:"sessionBackend,omitempty"`           // where session messages are stored: "file" (default, session.jsonl per session) or "sqlite" (sessions/sessions.db)
+}
+
+// CircuitBreakerConfig tunes the per-provider circuit breaker. Zero or
+// negative values fall back to the provider package defaults.
+type CircuitBreakerConfig struct {
+	FailureThreshold int `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty"` // consecutive transport errors, 5xx or 429s before opening (default 5)
+	CooldownSeconds  int `json:"cooldownSeconds,omitempty" yaml:"cooldownSeconds,omitempty"`   // how long to fail fast before probing again (default 60)
+}
+
+// SubagentsConfig bounds subagent threads spawned via dispatch(to=subagent).
+// Zero or negative values fall back to the thread package defaults.
+type SubagentsConfig struct {
+	MaxConcurrent  int `json:"maxConcurrent,omitempty" yaml:"maxConcurrent,omitempty"`   // max subagent turns running at once (default 4)
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"` // per-turn deadline for a subagent (default 600)
+	MaxPerTurn     int `json:"maxPerTurn,omitempty" yaml:"maxPerTurn,omitempty"`         // max dispatch(to=subagent) calls in one turn (default 10)
+	MaxPerHour     int `json:"maxPerHour,omitempty" yaml:"maxPerHour,omitempty"`         // max dispatch(to=subagent) calls per session in a rolling hour (default 60)
 }
 
 // PreviewConfig overrides the default preview priority chain.
@@ -182,8 +217,10 @@ type OAuthTokenConfig struct {
 
 // ProviderConfig contains API credentials for a provider.
 type ProviderConfig struct {
-	APIKey  string `json:"apiKey" yaml:"apiKey"`
-	APIBase string `json:"apiBase,omitempty" yaml:"apiBase,omitempty"` // optional custom base URL
+	APIKey          string `json:"apiKey" yaml:"apiKey"`
+	APIBase         string `json:"apiBase,omitempty" yaml:"apiBase,omitempty"`                 // optional custom base URL
+	ReasoningEffort string `json:"reasoningEffort,omitempty" yaml:"reasoningEffort,omitempty"` // OpenAI Responses only: low | medium | high; empty = high
+	SDKMaxRetries   *int   `json:"sdkMaxRetries,omitempty" yaml:"sdkMaxRetries,omitempty"`     // transport-level retries of the HTTP client; nil = built-in default, 0 = fail fast
 }
 
 // GetProviderConfig returns the provider config for a given name, or nil if not found.
@@ -225,22 +262,63 @@ func (p *ProvidersConfig) GetProviderConfig(name string) *ProviderConfig {
 
 // ToolsConfig contains tool-related configuration.
 type ToolsConfig struct {
-	Web  WebToolsConfig  `json:"web,omitempty" yaml:"web,omitempty"`
-	Exec ExecToolsConfig `json:"exec,omitempty" yaml:"exec,omitempty"`
+	Web            WebToolsConfig     `json:"web,omitempty" yaml:"web,omitempty"`
+	Exec           ExecToolsConfig    `json:"exec,omitempty" yaml:"exec,omitempty"`
+	Journal        *ToolJournalConfig `json:"journal,omitempty" yaml:"journal,omitempty"`               // write-ahead journal of mutating tool calls
+	LoadSkill      *LoadSkillConfig   `json:"loadSkill,omitempty" yaml:"loadSkill,omitempty"`           // runtime skill loading
+	Embeddings     *EmbeddingsConfig  `json:"embeddings,omitempty" yaml:"embeddings,omitempty"`         // semantic recall over memory notes
+	Timeout        int                `json:"timeout,omitempty" yaml:"timeout,omitempty"`               // default per-call tool timeout in seconds (default 600; negative disables)
+	Timeouts       map[string]int     `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`             // tool name → per-call timeout in seconds, overriding the default (negative disables)
+	MaxResultBytes int                `json:"maxResultBytes,omitempty" yaml:"maxResultBytes,omitempty"` // hard cap on any single tool result (default 1 MiB; negative disables)
+}
+
+// LoadSkillConfig controls the load_skill tool. Workspace files are always
+// accepted; URLs only when their host (or a parent domain) is listed.
+type LoadSkillConfig struct {
+	AllowedHosts []string `json:"allowedHosts,omitempty" yaml:"allowedHosts,omitempty"`
+}
+
+// EmbeddingsConfig configures the OpenAI-compatible embeddings endpoint used
+// by the recall tool. Without a model, recall falls back to keyword search.
+type EmbeddingsConfig struct {
+	APIKey  string `json:"apiKey,omitempty" yaml:"apiKey,omitempty"`
+	APIBase string `json:"apiBase,omitempty" yaml:"apiBase,omitempty"` // default https://api.openai.com/v1
+	Model   string `json:"model,omitempty" yaml:"model,omitempty"`     // e.g. text-embedding-3-small
+}
+
+// ToolJournalConfig controls the per-session journal of mutating tool calls
+// (exec, write_file, edit_file, apply_patch). Calls interrupted by a crash are
+// surfaced to their session on the next start.
+type ToolJournalConfig struct {
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 }
 
 // LoggingConfig contains logging configuration.
 type LoggingConfig struct {
-	Enabled *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	Level   string `json:"level,omitempty" yaml:"level,omitempty"`   // debug, info, warn, error
-	Stdout  bool   `json:"stdout,omitempty" yaml:"stdout,omitempty"` // log to stdout
-	File    string `json:"file,omitempty" yaml:"file,omitempty"`     // log file path
+	Enabled *bool           `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Level   string          `json:"level,omitempty" yaml:"level,omitempty"`   // debug, info, warn, error
+	Stdout  bool            `json:"stdout,omitempty" yaml:"stdout,omitempty"` // log to stdout
+	File    string          `json:"file,omitempty" yaml:"file,omitempty"`     // log file path
+	Audit   *AuditLogConfig `json:"audit,omitempty" yaml:"audit,omitempty"`   // per-session JSONL audit log of tool calls
+	// Transcript is a directory that receives every provider request and
+	// response as JSON, secrets redacted. Debug only: empty (the default)
+	// disables it; relative paths resolve against the workspace.
+	Transcript string `json:"transcript,omitempty" yaml:"transcript,omitempty"`
+}
+
+// AuditLogConfig controls the per-session JSONL audit log of tool calls.
+type AuditLogConfig struct {
+	Enabled     bool     `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Dir         string   `json:"dir,omitempty" yaml:"dir,omitempty"`                 // defaults to <workspace>/logs/audit; relative paths resolve against the workspace
+	RedactTools []string `json:"redactTools,omitempty" yaml:"redactTools,omitempty"` // tool names whose arguments are not recorded
 }
 
 // WebToolsConfig contains web tool configuration.
 type WebToolsConfig struct {
-	Search SearchConfig `json:"search,omitempty" yaml:"search,omitempty"`
-	Fetch  FetchConfig  `json:"fetch,omitempty" yaml:"fetch,omitempty"`
+	Search    SearchConfig `json:"search,omitempty" yaml:"search,omitempty"`
+	Fetch     FetchConfig  `json:"fetch,omitempty" yaml:"fetch,omitempty"`
+	UserAgent string       `json:"userAgent,omitempty" yaml:"userAgent,omitempty"` // User-Agent for scraping search and fetch requests (default: a desktop browser)
+	Proxy     string       `json:"proxy,omitempty" yaml:"proxy,omitempty"`         // proxy URL for web tools; empty uses HTTP_PROXY/HTTPS_PROXY
 }
 
 // FetchConfig contains web fetch configuration.
@@ -250,19 +328,28 @@ type FetchConfig struct {
 
 // SearchConfig contains web search configuration.
 type SearchConfig struct {
-	Keys       map[string]string `json:"keys,omitempty" yaml:"keys,omitempty"`             // provider_name -> API key
-	MaxResults int               `json:"maxResults,omitempty" yaml:"maxResults,omitempty"`
+	Keys            map[string]string `json:"keys,omitempty" yaml:"keys,omitempty"` // provider_name -> API key
+	MaxResults      int               `json:"maxResults,omitempty" yaml:"maxResults,omitempty"`
+	CacheTTLSeconds int               `json:"cacheTTLSeconds,omitempty" yaml:"cacheTTLSeconds,omitempty"` // identical searches within this window are served from cache (default 300)
+	Backend         string            `json:"backend,omitempty" yaml:"backend,omitempty"`                 // source used when web_search is called without one (default "duckduckgo")
+	SearxngURL      string            `json:"searxngUrl,omitempty" yaml:"searxngUrl,omitempty"`           // base URL of a SearXNG instance, e.g. https://searx.example.org
 }
 
 // ExecToolsConfig contains exec tool configuration.
 type ExecToolsConfig struct {
-	Timeout             int  `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // seconds
-	RestrictToWorkspace bool `json:"restrictToWorkspace,omitempty" yaml:"restrictToWorkspace,omitempty"` // restrict to workspace
+	Timeout             int   `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // seconds
+	RestrictToWorkspace bool  `json:"restrictToWorkspace,omitempty" yaml:"restrictToWorkspace,omitempty"` // restrict to workspace
+	ConfirmDestructive  *bool `json:"confirmDestructive,omitempty" yaml:"confirmDestructive,omitempty"`   // ask before mkfs, dd, git reset --hard, ... on non-admin turns (default true)
 }
 
 // ChannelsConfig contains channel configurations.
 type ChannelsConfig struct {
 	SessionTimezones map[string]string `json:"sessionTimezones,omitempty" yaml:"sessionTimezones,omitempty"` // sessionKey → IANA timezone (e.g. "Asia/Shanghai")
+	DedupWindowSeconds int `json:"dedupWindowSeconds,omitempty" yaml:"dedupWindowSeconds,omitempty"` // how long inbound message IDs are remembered for redelivery dedup (default 600)
+	SendRetries int `json:"sendRetries,omitempty" yaml:"sendRetries,omitempty"` // retries for Telegram/Discord sends failing with rate limits or transient errors (default 3; negative disables)
+	MediaCacheMB int `json:"mediaCacheMB,omitempty" yaml:"mediaCacheMB,omitempty"` // size cap for downloaded media in {workspace}/media; least recently used files are deleted beyond it (default 500; negative disables cleanup)
+	Redact []string `json:"redact,omitempty" yaml:"redact,omitempty"` // outbound masking rules: email, phone, creditcard, or a custom regex (default off)
+	Backpressure *BackpressureConfig `json:"backpressure,omitempty" yaml:"backpressure,omitempty"` // busy replies when inbound buffers fill up
 	Telegram    *TelegramChannelConfig `json:"telegram" yaml:"telegram"`
 	Feishu      *FeishuChannelConfig   `json:"feishu,omitempty" yaml:"feishu,omitempty"`
 	Discord     *DiscordChannelConfig  `json:"discord,omitempty" yaml:"discord,omitempty"`
@@ -270,31 +357,95 @@ type ChannelsConfig struct {
 	WeCom       *WeComChannelConfig    `json:"wecom,omitempty" yaml:"wecom,omitempty"`
 }
 
+// BackpressureConfig controls how interactive channels react when their
+// inbound message buffer fills up faster than the dispatcher drains it.
+type BackpressureConfig struct {
+	HighWaterPercent int    `json:"highWaterPercent,omitempty" yaml:"highWaterPercent,omitempty"` // buffer fill (1-100) at which new messages get a busy reply (default 80)
+	BusyReply        string `json:"busyReply,omitempty" yaml:"busyReply,omitempty"`               // text sent instead of queueing (default: a short "busy, retry" notice)
+}
+
+// ChannelPromptConfig adds a per-channel instruction to the system prompt of
+// turns that come from that channel. Prompt wins over PromptFile.
+type ChannelPromptConfig struct {
+	Prompt     string `json:"prompt,omitempty" yaml:"prompt,omitempty"`         // inline instruction text
+	PromptFile string `json:"promptFile,omitempty" yaml:"promptFile,omitempty"` // path to a markdown file; relative paths resolve against the workspace
+}
+
+// ChannelToolsConfig narrows the tools offered to turns that come from a
+// channel. Deny wins over Allow; turns started by an admin user are exempt.
+type ChannelToolsConfig struct {
+	AllowTools []string `json:"allowTools,omitempty" yaml:"allowTools,omitempty"` // only these tools are offered (empty = all)
+	DenyTools  []string `json:"denyTools,omitempty" yaml:"denyTools,omitempty"`   // these tools are never offered
+}
+
+// ChannelTranslateConfig opts a channel into machine translation for users
+// who write in another language than the agent's prompts.
+type ChannelTranslateConfig struct {
+	Translate *TranslateConfig `json:"translate,omitempty" yaml:"translate,omitempty"`
+}
+
+// TranslateConfig translates inbound text from Source into Target before it
+// reaches the agent, and replies from Target back into Source before they are
+// sent. Code and @-mentions are left untouched. The model is the one mapped
+// to the "translate" route in thread.models (default model otherwise).
+type TranslateConfig struct {
+	Source string `json:"source" yaml:"source"`                     // the users' language, e.g. "Chinese"
+	Target string `json:"target,omitempty" yaml:"target,omitempty"` // the agent's working language (default English)
+}
+
 // TelegramChannelConfig contains Telegram bot configuration.
 type TelegramChannelConfig struct {
-	Token      string  `json:"token" yaml:"token"`           // Bot token from BotFather
-	AllowedIDs []int64 `json:"allowedIds" yaml:"allowedIds"` // Allowed user/chat IDs
+	Token            string  `json:"token" yaml:"token"`                                           // Bot token from BotFather
+	AllowedIDs       []int64 `json:"allowedIds" yaml:"allowedIds"`                                 // Allowed user/chat IDs
+	MaxMessageLength int     `json:"maxMessageLength,omitempty" yaml:"maxMessageLength,omitempty"` // characters per outgoing chunk (default and max 4096)
+
+	ChannelPromptConfig    `yaml:",inline"`
+	ChannelToolsConfig     `yaml:",inline"`
+	ChannelTranslateConfig `yaml:",inline"`
 }
 
 // FeishuChannelConfig contains Feishu (Lark) bot configuration.
 // Uses WebSocket long connection (no public URL needed).
 type FeishuChannelConfig struct {
-	AppID          string   `json:"appId" yaml:"appId"`
-	AppSecret      string   `json:"appSecret" yaml:"appSecret"`
-	AdminOpenID    string   `json:"adminOpenId,omitempty" yaml:"adminOpenId,omitempty"`
-	AllowedOpenIDs []string `json:"allowedOpenIds,omitempty" yaml:"allowedOpenIds,omitempty"` // empty = allow all
+	AppID            string   `json:"appId" yaml:"appId"`
+	AppSecret        string   `json:"appSecret" yaml:"appSecret"`
+	AdminOpenID      string   `json:"adminOpenId,omitempty" yaml:"adminOpenId,omitempty"`
+	AllowedOpenIDs   []string `json:"allowedOpenIds,omitempty" yaml:"allowedOpenIds,omitempty"`     // empty = allow all
+	RequireMention   bool     `json:"requireMention,omitempty" yaml:"requireMention,omitempty"`     // group chats: only respond when @-mentioned
+	MaxMessageLength int      `json:"maxMessageLength,omitempty" yaml:"maxMessageLength,omitempty"` // characters per outgoing chunk (default 4000, max 30000)
+
+	ChannelPromptConfig    `yaml:",inline"`
+	ChannelToolsConfig     `yaml:",inline"`
+	ChannelTranslateConfig `yaml:",inline"`
 }
 
 // DiscordChannelConfig contains Discord bot configuration.
 type DiscordChannelConfig struct {
-	Token           string   `json:"token" yaml:"token"`
-	AllowedGuildIDs []string `json:"allowedGuildIds,omitempty" yaml:"allowedGuildIds,omitempty"`
-	AllowedUserIDs  []string `json:"allowedUserIds,omitempty" yaml:"allowedUserIds,omitempty"`
+	Token            string   `json:"token" yaml:"token"`
+	AllowedGuildIDs  []string `json:"allowedGuildIds,omitempty" yaml:"allowedGuildIds,omitempty"`
+	AllowedUserIDs   []string `json:"allowedUserIds,omitempty" yaml:"allowedUserIds,omitempty"`
+	RequireMention   bool     `json:"requireMention,omitempty" yaml:"requireMention,omitempty"`     // guild channels: only respond when @-mentioned
+	MaxMessageLength int      `json:"maxMessageLength,omitempty" yaml:"maxMessageLength,omitempty"` // characters per outgoing chunk (default and max 2000)
+
+	ChannelPromptConfig    `yaml:",inline"`
+	ChannelToolsConfig     `yaml:",inline"`
+	ChannelTranslateConfig `yaml:",inline"`
 }
 
 // WebChannelConfig contains Web chat configuration.
 type WebChannelConfig struct {
-	Addr string `json:"addr,omitempty" yaml:"addr,omitempty"` // default: 127.0.0.1:18080
+	Addr  string          `json:"addr,omitempty" yaml:"addr,omitempty"`   // default: 127.0.0.1:18080
+	Users []WebUserConfig `json:"users,omitempty" yaml:"users,omitempty"` // when set, connections must present a user's token and each user chats in its own web:<name> session
+
+	ChannelPromptConfig    `yaml:",inline"`
+	ChannelToolsConfig     `yaml:",inline"`
+	ChannelTranslateConfig `yaml:",inline"`
+}
+
+// WebUserConfig is one web channel login: the token identifies the user.
+type WebUserConfig struct {
+	Name  string `json:"name" yaml:"name"`   // session key suffix: letters, digits, '-', '_', '.'
+	Token string `json:"token" yaml:"token"` // secret passed as a Bearer token, ?token= or the nagobot_token cookie
 }
 
 // WeComChannelConfig contains WeCom (WeChat Work) AI Bot configuration.
@@ -303,4 +454,8 @@ type WeComChannelConfig struct {
 	BotID          string   `json:"botId" yaml:"botId"`
 	Secret         string   `json:"secret" yaml:"secret"`
 	AllowedUserIDs []string `json:"allowedUserIds,omitempty" yaml:"allowedUserIds,omitempty"` // empty = allow all
+
+	ChannelPromptConfig    `yaml:",inline"`
+	ChannelToolsConfig     `yaml:",inline"`
+	ChannelTranslateConfig `yaml:",inline"`
 }
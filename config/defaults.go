@@ -186,5 +186,9 @@ func cronJobEqual(a, b cronpkg.Job) bool {
 		a.Agent == b.Agent &&
 		a.WakeSession == b.WakeSession &&
 		a.Silent == b.Silent &&
-		a.DirectWake == b.DirectWake
+		a.DirectWake == b.DirectWake &&
+		a.Deliver == b.Deliver &&
+		a.CatchUp == b.CatchUp &&
+		a.Channel == b.Channel &&
+		a.To == b.To
 }
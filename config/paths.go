@@ -85,3 +85,55 @@ func (c *Config) EnsureWorkspace() error {
 	}
 	return os.MkdirAll(ws, 0755)
 }
+
+// AuditLogDir returns the tool-call audit log directory.
+func (c *Config) AuditLogDir() (string, error) {
+	ws, err := c.WorkspacePath()
+	if err != nil {
+		return "", err
+	}
+	var dir string
+	if c.Logging.Audit != nil {
+		dir = strings.TrimSpace(c.Logging.Audit.Dir)
+	}
+	if dir == "" {
+		return filepath.Join(ws, "logs", "audit"), nil
+	}
+	return resolveWorkspaceDir(ws, dir)
+}
+
+// TranscriptEnv enables the provider transcript dump without editing config;
+// its value is the target directory and takes precedence over logging.transcript.
+const TranscriptEnv = "NAGOBOT_DEBUG_TRANSCRIPT"
+
+// TranscriptDir returns the provider transcript directory, or "" when the
+// transcript dump is off (the default).
+func (c *Config) TranscriptDir() (string, error) {
+	dir := strings.TrimSpace(os.Getenv(TranscriptEnv))
+	if dir == "" {
+		dir = strings.TrimSpace(c.Logging.Transcript)
+	}
+	if dir == "" {
+		return "", nil
+	}
+	ws, err := c.WorkspacePath()
+	if err != nil {
+		return "", err
+	}
+	return resolveWorkspaceDir(ws, dir)
+}
+
+// resolveWorkspaceDir expands ~ in dir and resolves relative paths against ws.
+func resolveWorkspaceDir(ws, dir string) (string, error) {
+	switch {
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, strings.TrimPrefix(dir, "~")), nil
+	case filepath.IsAbs(dir):
+		return filepath.Clean(dir), nil
+	}
+	return filepath.Join(ws, dir), nil
+}
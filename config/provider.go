@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/linanwx/nagobot/logger"
 )
@@ -94,6 +95,107 @@ func (c *Config) GetContextWindowTokens() int {
 	return c.Thread.ContextWindowTokens
 }
 
+// GetMaxConcurrentTurns returns how many agent turns may run at once across
+// all sessions. Zero means use the thread package default.
+func (c *Config) GetMaxConcurrentTurns() int {
+	if c == nil || c.Thread.MaxConcurrentTurns <= 0 {
+		return 0
+	}
+	return c.Thread.MaxConcurrentTurns
+}
+
+// GetMaxContinuations returns how many times a reply cut off by the output
+// token limit is continued. Zero means use the thread package default;
+// negative disables continuation.
+func (c *Config) GetMaxContinuations() int {
+	if c == nil {
+		return 0
+	}
+	return c.Thread.MaxContinuations
+}
+
+// GetToolResultMaxTokens returns the token cap for a single tool result.
+// Zero means use the thread package default; negative disables the cap.
+func (c *Config) GetToolResultMaxTokens() int {
+	if c == nil {
+		return 0
+	}
+	return c.Thread.ToolResultMaxTokens
+}
+
+// GetSessionBackend returns the configured session storage backend
+// ("file" or "sqlite"). Empty means the file backend.
+func (c *Config) GetSessionBackend() string {
+	if c == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(c.Thread.SessionBackend))
+}
+
+// GetSubagentMaxConcurrent returns how many subagent turns may run at once.
+// Zero means use the thread package default.
+func (c *Config) GetSubagentMaxConcurrent() int {
+	if c == nil || c.Thread.Subagents == nil || c.Thread.Subagents.MaxConcurrent <= 0 {
+		return 0
+	}
+	return c.Thread.Subagents.MaxConcurrent
+}
+
+// GetSubagentTimeout returns the per-turn deadline for subagent threads.
+// Zero means use the thread package default.
+func (c *Config) GetSubagentTimeout() time.Duration {
+	if c == nil || c.Thread.Subagents == nil || c.Thread.Subagents.TimeoutSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.Thread.Subagents.TimeoutSeconds) * time.Second
+}
+
+// GetSubagentMaxPerTurn returns how many subagents one turn may dispatch.
+// Zero means use the thread package default.
+func (c *Config) GetSubagentMaxPerTurn() int {
+	if c == nil || c.Thread.Subagents == nil || c.Thread.Subagents.MaxPerTurn <= 0 {
+		return 0
+	}
+	return c.Thread.Subagents.MaxPerTurn
+}
+
+// GetSubagentMaxPerHour returns how many subagents one session may dispatch
+// in a rolling hour. Zero means use the thread package default.
+func (c *Config) GetSubagentMaxPerHour() int {
+	if c == nil || c.Thread.Subagents == nil || c.Thread.Subagents.MaxPerHour <= 0 {
+		return 0
+	}
+	return c.Thread.Subagents.MaxPerHour
+}
+
+// GetThreadIdleEvictAfter returns how long a session's thread may sit idle
+// before it is torn down. Zero means use the thread package default;
+// negative disables eviction.
+func (c *Config) GetThreadIdleEvictAfter() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.Thread.IdleEvictMinutes) * time.Minute
+}
+
+// GetCircuitBreakerThreshold returns how many consecutive provider failures
+// open the circuit breaker. Zero means use the provider package default.
+func (c *Config) GetCircuitBreakerThreshold() int {
+	if c == nil || c.Thread.CircuitBreaker == nil || c.Thread.CircuitBreaker.FailureThreshold <= 0 {
+		return 0
+	}
+	return c.Thread.CircuitBreaker.FailureThreshold
+}
+
+// GetCircuitBreakerCooldown returns how long an open breaker fails fast before
+// probing the provider again. Zero means use the provider package default.
+func (c *Config) GetCircuitBreakerCooldown() time.Duration {
+	if c == nil || c.Thread.CircuitBreaker == nil || c.Thread.CircuitBreaker.CooldownSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.Thread.CircuitBreaker.CooldownSeconds) * time.Second
+}
+
 // GetWebAddr returns the configured web channel listen address.
 func (c *Config) GetWebAddr() string {
 	if c == nil || c.Channels == nil || c.Channels.Web == nil {
@@ -102,6 +204,202 @@ func (c *Config) GetWebAddr() string {
 	return strings.TrimSpace(c.Channels.Web.Addr)
 }
 
+// GetWebUsers returns the web channel logins as token → user name, skipping
+// entries missing either field. Whether multi-user mode is on follows
+// WebUsersConfigured, not this map.
+func (c *Config) GetWebUsers() map[string]string {
+	if c == nil || c.Channels == nil || c.Channels.Web == nil {
+		return nil
+	}
+	users := make(map[string]string, len(c.Channels.Web.Users))
+	for _, u := range c.Channels.Web.Users {
+		name, token := strings.TrimSpace(u.Name), strings.TrimSpace(u.Token)
+		if name != "" && token != "" {
+			users[token] = name
+		}
+	}
+	return users
+}
+
+// WebUsersConfigured reports whether channels.web.users lists any entry,
+// valid or not. The web channel then requires authentication.
+func (c *Config) WebUsersConfigured() bool {
+	return c != nil && c.Channels != nil && c.Channels.Web != nil && len(c.Channels.Web.Users) > 0
+}
+
+// GetChannelDedupWindow returns how long inbound message IDs are remembered
+// to drop redelivered events. Zero means use the channel package default.
+func (c *Config) GetChannelDedupWindow() time.Duration {
+	if c == nil || c.Channels == nil || c.Channels.DedupWindowSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.Channels.DedupWindowSeconds) * time.Second
+}
+
+// GetChannelSendRetries returns how many times a failed outgoing chunk is
+// retried. Zero means use the channel package default; negative disables.
+func (c *Config) GetChannelSendRetries() int {
+	if c == nil || c.Channels == nil {
+		return 0
+	}
+	return c.Channels.SendRetries
+}
+
+// GetAPIAddr returns the listen address of the HTTP chat API, or "" when the
+// API is disabled.
+func (c *Config) GetAPIAddr() string {
+	if c == nil || c.API == nil {
+		return ""
+	}
+	return strings.TrimSpace(c.API.Addr)
+}
+
+// GetAPIToken returns the Bearer token the HTTP chat API requires.
+func (c *Config) GetAPIToken() string {
+	if c == nil || c.API == nil {
+		return ""
+	}
+	return strings.TrimSpace(c.API.Token)
+}
+
+// GetAPITimeout returns how long an HTTP chat API request waits for its
+// turn. Zero means use the cmd package default.
+func (c *Config) GetAPITimeout() time.Duration {
+	if c == nil || c.API == nil || c.API.TimeoutSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.API.TimeoutSeconds) * time.Second
+}
+
+// GetAPIMaxConcurrent returns how many HTTP chat API turns may run at once.
+// Zero means use the cmd package default.
+func (c *Config) GetAPIMaxConcurrent() int {
+	if c == nil || c.API == nil || c.API.MaxConcurrent <= 0 {
+		return 0
+	}
+	return c.API.MaxConcurrent
+}
+
+// GetChannelMediaCacheMB returns the size cap, in MB, of the shared media
+// download directory. Zero means use the channel package default; negative
+// disables cleanup.
+func (c *Config) GetChannelMediaCacheMB() int {
+	if c == nil || c.Channels == nil {
+		return 0
+	}
+	return c.Channels.MediaCacheMB
+}
+
+// GetChannelRedact returns the outbound redaction rules applied to every
+// channel send. Empty means redaction is off.
+func (c *Config) GetChannelRedact() []string {
+	if c == nil || c.Channels == nil {
+		return nil
+	}
+	return c.Channels.Redact
+}
+
+// DefaultChannelHighWaterPercent is the inbound buffer fill, in percent, at
+// which interactive channels start answering with a busy reply.
+const DefaultChannelHighWaterPercent = 80
+
+// DefaultChannelBusyReply is sent to users whose message arrives while the
+// channel's inbound buffer is above the high-water mark.
+const DefaultChannelBusyReply = "I'm busy with other messages right now and couldn't take this one. Please send it again in a minute."
+
+// GetChannelHighWaterPercent returns the inbound buffer fill (1-100) at which
+// interactive channels reply "busy" instead of queueing.
+func (c *Config) GetChannelHighWaterPercent() int {
+	if c == nil || c.Channels == nil || c.Channels.Backpressure == nil {
+		return DefaultChannelHighWaterPercent
+	}
+	p := c.Channels.Backpressure.HighWaterPercent
+	if p <= 0 || p > 100 {
+		return DefaultChannelHighWaterPercent
+	}
+	return p
+}
+
+// GetChannelBusyReply returns the text sent when a message is turned away
+// because the channel's inbound buffer is saturated.
+func (c *Config) GetChannelBusyReply() string {
+	if c == nil || c.Channels == nil || c.Channels.Backpressure == nil {
+		return DefaultChannelBusyReply
+	}
+	if r := strings.TrimSpace(c.Channels.Backpressure.BusyReply); r != "" {
+		return r
+	}
+	return DefaultChannelBusyReply
+}
+
+// GetChannelPrompt returns the per-channel prompt settings for channel
+// ("telegram", "discord", "feishu", "wecom", "web"). Unknown or unconfigured
+// channels return the zero value.
+func (c *Config) GetChannelPrompt(channel string) ChannelPromptConfig {
+	return c.channelSettings(channel).ChannelPromptConfig
+}
+
+// GetChannelTools returns the per-channel tool allow/deny lists for channel.
+// Unknown or unconfigured channels return the zero value (all tools).
+func (c *Config) GetChannelTools(channel string) ChannelToolsConfig {
+	return c.channelSettings(channel).ChannelToolsConfig
+}
+
+// GetChannelTranslate returns the translation settings for channel, or nil
+// when the channel does not translate. A missing target defaults to English.
+func (c *Config) GetChannelTranslate(channel string) *TranslateConfig {
+	t := c.channelSettings(channel).Translate
+	if t == nil || strings.TrimSpace(t.Source) == "" {
+		return nil
+	}
+	out := TranslateConfig{Source: strings.TrimSpace(t.Source), Target: strings.TrimSpace(t.Target)}
+	if out.Target == "" {
+		out.Target = "English"
+	}
+	if strings.EqualFold(out.Source, out.Target) {
+		return nil
+	}
+	return &out
+}
+
+// sharedChannelConfig holds the settings every chat channel's config embeds.
+type sharedChannelConfig struct {
+	ChannelPromptConfig
+	ChannelToolsConfig
+	ChannelTranslateConfig
+}
+
+// channelSettings returns the shared settings of the named chat channel.
+func (c *Config) channelSettings(channel string) sharedChannelConfig {
+	if c == nil || c.Channels == nil {
+		return sharedChannelConfig{}
+	}
+	ch := c.Channels
+	switch channel {
+	case "telegram":
+		if ch.Telegram != nil {
+			return sharedChannelConfig{ch.Telegram.ChannelPromptConfig, ch.Telegram.ChannelToolsConfig, ch.Telegram.ChannelTranslateConfig}
+		}
+	case "discord":
+		if ch.Discord != nil {
+			return sharedChannelConfig{ch.Discord.ChannelPromptConfig, ch.Discord.ChannelToolsConfig, ch.Discord.ChannelTranslateConfig}
+		}
+	case "feishu":
+		if ch.Feishu != nil {
+			return sharedChannelConfig{ch.Feishu.ChannelPromptConfig, ch.Feishu.ChannelToolsConfig, ch.Feishu.ChannelTranslateConfig}
+		}
+	case "wecom":
+		if ch.WeCom != nil {
+			return sharedChannelConfig{ch.WeCom.ChannelPromptConfig, ch.WeCom.ChannelToolsConfig, ch.WeCom.ChannelTranslateConfig}
+		}
+	case "web":
+		if ch.Web != nil {
+			return sharedChannelConfig{ch.Web.ChannelPromptConfig, ch.Web.ChannelToolsConfig, ch.Web.ChannelTranslateConfig}
+		}
+	}
+	return sharedChannelConfig{}
+}
+
 // GetTelegramToken returns the Telegram bot token (env overrides config).
 func (c *Config) GetTelegramToken() string {
 	if v := strings.TrimSpace(os.Getenv("TELEGRAM_BOT_TOKEN")); v != "" {
@@ -159,6 +457,42 @@ func (c *Config) GetFeishuAllowedOpenIDs() []string {
 	return c.Channels.Feishu.AllowedOpenIDs
 }
 
+// GetTelegramMaxMessageLength returns the configured Telegram chunk size.
+// Zero means use the channel default.
+func (c *Config) GetTelegramMaxMessageLength() int {
+	if c == nil || c.Channels == nil || c.Channels.Telegram == nil {
+		return 0
+	}
+	return c.Channels.Telegram.MaxMessageLength
+}
+
+// GetFeishuMaxMessageLength returns the configured Feishu chunk size.
+// Zero means use the channel default.
+func (c *Config) GetFeishuMaxMessageLength() int {
+	if c == nil || c.Channels == nil || c.Channels.Feishu == nil {
+		return 0
+	}
+	return c.Channels.Feishu.MaxMessageLength
+}
+
+// GetDiscordMaxMessageLength returns the configured Discord chunk size.
+// Zero means use the channel default.
+func (c *Config) GetDiscordMaxMessageLength() int {
+	if c == nil || c.Channels == nil || c.Channels.Discord == nil {
+		return 0
+	}
+	return c.Channels.Discord.MaxMessageLength
+}
+
+// GetFeishuRequireMention reports whether Feishu group messages must
+// @-mention the bot to be handled.
+func (c *Config) GetFeishuRequireMention() bool {
+	if c == nil || c.Channels == nil || c.Channels.Feishu == nil {
+		return false
+	}
+	return c.Channels.Feishu.RequireMention
+}
+
 // GetDiscordToken returns the Discord bot token (env overrides config).
 func (c *Config) GetDiscordToken() string {
 	if v := strings.TrimSpace(os.Getenv("DISCORD_BOT_TOKEN")); v != "" {
@@ -186,6 +520,15 @@ func (c *Config) GetDiscordAllowedUserIDs() []string {
 	return c.Channels.Discord.AllowedUserIDs
 }
 
+// GetDiscordRequireMention reports whether Discord guild messages must
+// @-mention the bot to be handled.
+func (c *Config) GetDiscordRequireMention() bool {
+	if c == nil || c.Channels == nil || c.Channels.Discord == nil {
+		return false
+	}
+	return c.Channels.Discord.RequireMention
+}
+
 // GetWeComBotID returns the WeCom AI Bot ID (env overrides config).
 func (c *Config) GetWeComBotID() string {
 	if v := strings.TrimSpace(os.Getenv("WECOM_BOT_ID")); v != "" {
@@ -323,6 +666,32 @@ func (c *Config) GetExecRestrictToWorkspace() bool {
 	return c.Tools.Exec.RestrictToWorkspace
 }
 
+// GetExecConfirmDestructive reports whether exec asks for confirmation
+// before likely-destructive commands on non-admin turns. Defaults to true.
+func (c *Config) GetExecConfirmDestructive() bool {
+	if c == nil || c.Tools.Exec.ConfirmDestructive == nil {
+		return true
+	}
+	return *c.Tools.Exec.ConfirmDestructive
+}
+
+// GetWebUserAgent returns the User-Agent configured for web tools.
+func (c *Config) GetWebUserAgent() string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimSpace(c.Tools.Web.UserAgent)
+}
+
+// GetWebProxy returns the proxy URL configured for web tools. Empty means
+// use the HTTP_PROXY/HTTPS_PROXY environment.
+func (c *Config) GetWebProxy() string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimSpace(c.Tools.Web.Proxy)
+}
+
 // GetWebSearchMaxResults returns the web search max results.
 func (c *Config) GetWebSearchMaxResults() int {
 	if c == nil {
@@ -331,6 +700,32 @@ func (c *Config) GetWebSearchMaxResults() int {
 	return c.Tools.Web.Search.MaxResults
 }
 
+// GetWebSearchCacheTTL returns how long web search results are cached.
+// Zero means use the tools package default.
+func (c *Config) GetWebSearchCacheTTL() time.Duration {
+	if c == nil || c.Tools.Web.Search.CacheTTLSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.Tools.Web.Search.CacheTTLSeconds) * time.Second
+}
+
+// GetWebSearchBackend returns the search source used when web_search is
+// called without one. Defaults to "duckduckgo".
+func (c *Config) GetWebSearchBackend() string {
+	if c == nil || strings.TrimSpace(c.Tools.Web.Search.Backend) == "" {
+		return "duckduckgo"
+	}
+	return strings.TrimSpace(c.Tools.Web.Search.Backend)
+}
+
+// GetSearxngURL returns the base URL of the configured SearXNG instance.
+func (c *Config) GetSearxngURL() string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(c.Tools.Web.Search.SearxngURL), "/")
+}
+
 // GetSearchKey returns the API key for a specific search provider.
 func (c *Config) GetSearchKey(provider string) string {
 	if c == nil || c.Tools.Web.Search.Keys == nil {
@@ -358,7 +753,115 @@ func (c *Config) BuildLoggerConfig() logger.Config {
 		Level:   c.Logging.Level,
 		Stdout:  c.Logging.Stdout,
 		File:    c.Logging.File,
+		Secrets: c.Secrets(),
+	}
+}
+
+// Secrets returns every configured credential (provider API keys, OAuth
+// tokens, channel tokens and secrets, tool keys) so logs can mask them.
+func (c *Config) Secrets() []string {
+	if c == nil {
+		return nil
+	}
+	var out []string
+	p := c.Providers
+	for _, pc := range []*ProviderConfig{
+		p.OpenRouter, p.Anthropic, p.DeepSeek, p.MoonshotCN, p.MoonshotGlobal,
+		p.ZhipuCN, p.ZhipuGlobal, p.MinimaxCN, p.MinimaxGlobal,
+		p.SiliconflowCN, p.SiliconflowGlobal, p.OpenAI, p.Gemini, p.XAI, p.MiMo,
+	} {
+		if pc != nil {
+			out = append(out, pc.APIKey)
+		}
+	}
+	for _, oc := range []*OAuthTokenConfig{p.OpenAIOAuth, p.AnthropicOAuth} {
+		if oc != nil {
+			out = append(out, oc.AccessToken, oc.RefreshToken)
+		}
+	}
+	if ch := c.Channels; ch != nil {
+		if ch.Telegram != nil {
+			out = append(out, ch.Telegram.Token)
+		}
+		if ch.Feishu != nil {
+			out = append(out, ch.Feishu.AppSecret)
+		}
+		if ch.Discord != nil {
+			out = append(out, ch.Discord.Token)
+		}
+		if ch.WeCom != nil {
+			out = append(out, ch.WeCom.Secret)
+		}
+	}
+	for _, key := range c.Tools.Web.Search.Keys {
+		out = append(out, key)
+	}
+	out = append(out, c.Tools.Web.Fetch.JinaKey)
+	return out
+}
+
+// GetAuditLogEnabled reports whether tool calls are written to the audit log.
+func (c *Config) GetAuditLogEnabled() bool {
+	return c != nil && c.Logging.Audit != nil && c.Logging.Audit.Enabled
+}
+
+// GetAuditRedactTools returns tool names whose arguments the audit log redacts.
+func (c *Config) GetAuditRedactTools() []string {
+	if c == nil || c.Logging.Audit == nil {
+		return nil
+	}
+	return c.Logging.Audit.RedactTools
+}
+
+// GetToolJournalEnabled reports whether mutating tool calls are journaled.
+func (c *Config) GetToolJournalEnabled() bool {
+	return c != nil && c.Tools.Journal != nil && c.Tools.Journal.Enabled
+}
+
+// GetToolTimeouts returns the default per-call tool timeout and per-tool
+// overrides. A zero default means the tools package default; negative
+// values disable the timeout.
+func (c *Config) GetToolTimeouts() (time.Duration, map[string]time.Duration) {
+	if c == nil {
+		return 0, nil
+	}
+	def := time.Duration(c.Tools.Timeout) * time.Second
+	var perTool map[string]time.Duration
+	for name, secs := range c.Tools.Timeouts {
+		if secs == 0 {
+			continue
+		}
+		if perTool == nil {
+			perTool = make(map[string]time.Duration)
+		}
+		perTool[name] = time.Duration(secs) * time.Second
+	}
+	return def, perTool
+}
+
+// GetToolMaxResultBytes returns tools.maxResultBytes. Zero means the tools
+// package default; negative disables the cap.
+func (c *Config) GetToolMaxResultBytes() int {
+	if c == nil {
+		return 0
+	}
+	return c.Tools.MaxResultBytes
+}
+
+// GetLoadSkillAllowedHosts returns the hosts load_skill may fetch from.
+func (c *Config) GetLoadSkillAllowedHosts() []string {
+	if c == nil || c.Tools.LoadSkill == nil {
+		return nil
+	}
+	return c.Tools.LoadSkill.AllowedHosts
+}
+
+// GetEmbeddingsConfig returns tools.embeddings, or a zero value when unset.
+func (c *Config) GetEmbeddingsConfig() EmbeddingsConfig {
+	if c == nil || c.Tools.Embeddings == nil {
+		return EmbeddingsConfig{}
 	}
+	return *c.Tools.Embeddings
 }
 
 // SetLoggingLevel sets the logging level.
@@ -0,0 +1,34 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	robfigcron "github.com/robfig/cron/v3"
+)
+
+// NextFireTimes parses expr with the same 5-field parser set-cron validates
+// with and returns its next count fire times after from, evaluated in loc
+// (the scheduler's local zone for stored jobs). A CRON_TZ= prefix in expr
+// takes precedence over loc, as it does when the job runs.
+func NextFireTimes(expr string, loc *time.Location, from time.Time, count int) ([]time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	sched, err := robfigcron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	times := make([]time.Time, 0, count)
+	t := from.In(loc)
+	for len(times) < count {
+		t = sched.Next(t)
+		if t.IsZero() {
+			break // expression never fires (e.g. Feb 30)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextFireTimesDaily(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	from := time.Date(2026, 3, 1, 10, 30, 0, 0, loc) // after today's 09:00
+
+	got, err := NextFireTimes("0 9 * * *", loc, from, 3)
+	if err != nil {
+		t.Fatalf("NextFireTimes: %v", err)
+	}
+	want := []string{
+		"2026-03-02T09:00:00+08:00",
+		"2026-03-03T09:00:00+08:00",
+		"2026-03-04T09:00:00+08:00",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d times, want %d: %v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if s := got[i].Format(time.RFC3339); s != w {
+			t.Errorf("time %d = %s, want %s", i, s, w)
+		}
+	}
+	if utc := got[0].UTC().Format(time.RFC3339); utc != "2026-03-02T01:00:00Z" {
+		t.Errorf("first fire in UTC = %s, want 2026-03-02T01:00:00Z", utc)
+	}
+}
+
+func TestNextFireTimesInvalidExpression(t *testing.T) {
+	_, err := NextFireTimes("0 25 * * *", time.UTC, time.Now(), 5)
+	if err == nil || !strings.Contains(err.Error(), "invalid cron expression") {
+		t.Fatalf("err = %v, want invalid cron expression", err)
+	}
+}
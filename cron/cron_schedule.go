@@ -2,7 +2,9 @@ package cron
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	gocron "github.com/go-co-op/gocron/v2"
 	"github.com/linanwx/nagobot/logger"
@@ -18,12 +20,7 @@ func (s *Scheduler) scheduleLocked(job Job) (func(), error) {
 		registered, err := s.cron.NewJob(
 			gocron.CronJob(job.Expr, false),
 			gocron.NewTask(func(j Job) {
-				if s.factory == nil {
-					return
-				}
-				if _, runErr := s.factory(&j); runErr != nil {
-					logger.Warn("cron job execution failed", "id", j.ID, "err", runErr)
-				}
+				s.runCronJob(j, time.Now())
 			}, job),
 			gocron.WithName(job.ID),
 		)
@@ -58,13 +55,42 @@ func (s *Scheduler) scheduleLocked(job Job) (func(), error) {
 	return nil, fmt.Errorf("unsupported job kind: %s", job.Kind)
 }
 
+// runCronJob fires a recurring job, unless now falls on one of its skip
+// dates in the job's timezone.
+func (s *Scheduler) runCronJob(j Job, now time.Time) {
+	if day := now.In(jobLocation(j.Expr)).Format(SkipDateLayout); slices.Contains(j.SkipDates, day) {
+		logger.Info("cron job skipped: skip date", "id", j.ID, "date", day)
+		return
+	}
+	if s.factory == nil {
+		return
+	}
+	if _, runErr := s.factory(&j); runErr != nil {
+		logger.Warn("cron job execution failed", "id", j.ID, "err", runErr)
+	}
+}
+
+// jobLocation returns the zone a cron expression fires in: its CRON_TZ= or
+// TZ= prefix if present, otherwise server local time.
+func jobLocation(expr string) *time.Location {
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(expr), prefix); ok {
+			name, _, _ := strings.Cut(rest, " ")
+			if loc, err := time.LoadLocation(name); err == nil {
+				return loc
+			}
+		}
+	}
+	return time.Local
+}
+
 func (s *Scheduler) finalizeAtJobLocked(jobID string) {
 	if strings.TrimSpace(jobID) == "" {
 		return
 	}
 	s.unscheduleLocked(jobID)
 	delete(s.jobs, jobID)
-	if err := s.saveLocked(); err != nil {
+	if err := s.saveLocked(nil, []string{jobID}); err != nil {
 		logger.Warn("failed to persist cron store after at job execution", "id", jobID, "err", err)
 	}
 }
@@ -2,6 +2,8 @@ package cron
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/linanwx/nagobot/logger"
@@ -21,18 +23,25 @@ func (s *Scheduler) Load() error {
 
 	// Schedule store jobs first (high priority, persisted).
 	now := time.Now().UTC()
-	dirty := false
+	var expiredIDs []string
+	var missed []Job
 	for _, raw := range list {
 		job := Normalize(raw)
 		ok, expired := ValidateStored(job, now)
 		if !ok {
 			if expired {
-				dirty = true
+				expiredIDs = append(expiredIDs, job.ID)
+				if job.CatchUp {
+					missed = append(missed, job)
+				}
 			}
 			continue
 		}
 
 		s.jobs[job.ID] = job
+		if job.Disabled {
+			continue
+		}
 		cancel, err := s.scheduleLocked(job)
 		if err != nil {
 			logger.Warn("failed to schedule job from store", "id", job.ID, "kind", job.Kind, "err", err)
@@ -64,14 +73,48 @@ func (s *Scheduler) Load() error {
 		// NOT added to s.jobs — seeds are not persisted
 	}
 
-	if dirty {
-		if err := s.saveLocked(); err != nil {
+	if len(expiredIDs) > 0 {
+		if err := s.saveLocked(nil, expiredIDs); err != nil {
 			logger.Warn("failed to save cron store after pruning expired at jobs", "err", err)
 		}
 	}
+	if len(missed) > 0 && s.factory != nil {
+		if s.caughtUp {
+			// Outside s.mu: the factory may call back into the scheduler.
+			go s.runCatchUp(missed)
+		} else {
+			s.missed = append(s.missed, missed...)
+		}
+	}
 	return nil
 }
 
+// CatchUp fires the catch_up at jobs that Load found overdue. The caller
+// runs it once the channels are up, so deliveries and wakes have somewhere
+// to go; from then on Load fires missed jobs itself.
+func (s *Scheduler) CatchUp() {
+	s.mu.Lock()
+	missed := s.missed
+	s.missed = nil
+	s.caughtUp = true
+	s.mu.Unlock()
+	if len(missed) > 0 && s.factory != nil {
+		go s.runCatchUp(missed)
+	}
+}
+
+// runCatchUp fires at jobs that came due while the process was down. Each
+// job has already been pruned from the store, so it runs at most once.
+func (s *Scheduler) runCatchUp(jobs []Job) {
+	for i := range jobs {
+		job := jobs[i]
+		logger.Info("catching up missed at job", "id", job.ID, "at", job.AtTime.Format(time.RFC3339))
+		if _, err := s.factory(&job); err != nil {
+			logger.Warn("catch-up at job execution failed", "id", job.ID, "err", err)
+		}
+	}
+}
+
 func (s *Scheduler) Start() {
 	if s.cron != nil {
 		s.cron.Start()
@@ -89,9 +132,13 @@ func (s *Scheduler) AddJob(job Job) error {
 		return fmt.Errorf("invalid job: id=%q kind=%q", job.ID, job.Kind)
 	}
 
-	cancel, err := s.scheduleLocked(job)
-	if err != nil {
-		return fmt.Errorf("schedule job %q: %w", job.ID, err)
+	var cancel func()
+	if !job.Disabled {
+		var err error
+		cancel, err = s.scheduleLocked(job)
+		if err != nil {
+			return fmt.Errorf("schedule job %q: %w", job.ID, err)
+		}
 	}
 
 	// Unschedule any previous job with the same ID.
@@ -101,13 +148,32 @@ func (s *Scheduler) AddJob(job Job) error {
 	if cancel != nil {
 		s.cancels[job.ID] = cancel
 	}
-	if err := s.saveLocked(); err != nil {
+	if err := s.saveLocked([]Job{job}, nil); err != nil {
 		return fmt.Errorf("persist job %q: %w", job.ID, err)
 	}
 	logger.Info("job added", "id", job.ID, "kind", job.Kind)
 	return nil
 }
 
+// RemoveJob unschedules a persisted job and drops it from the store.
+// It reports false when no such job exists.
+func (s *Scheduler) RemoveJob(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id = strings.TrimSpace(id)
+	if _, ok := s.jobs[id]; !ok {
+		return false, nil
+	}
+	s.unscheduleLocked(id)
+	delete(s.jobs, id)
+	if err := s.saveLocked(nil, []string{id}); err != nil {
+		return true, fmt.Errorf("persist removal of job %q: %w", id, err)
+	}
+	logger.Info("job removed", "id", id)
+	return true, nil
+}
+
 // FindJob returns the job with the given ID from the persisted store or seed jobs.
 func (s *Scheduler) FindJob(id string) (Job, bool) {
 	s.mu.Lock()
@@ -123,6 +189,18 @@ func (s *Scheduler) FindJob(id string) (Job, bool) {
 	return Job{}, false
 }
 
+// ListJobs returns the persisted jobs sorted by ID. Seed jobs are not included.
+func (s *Scheduler) ListJobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, j)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
 func (s *Scheduler) Stop() {
 	s.mu.Lock()
 	s.resetLocked()
@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad_CatchUpFiresMissedAtJob(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "cron.jsonl")
+	past := time.Now().Add(-time.Hour).UTC()
+	if err := WriteJobs(store, []Job{
+		{ID: "reminder", Kind: JobKindAt, AtTime: &past, Task: "Take the 9am pill", CatchUp: true},
+		{ID: "stale", Kind: JobKindAt, AtTime: &past, Task: "discard me"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	fired := make(chan string, 2)
+	s, err := NewScheduler(store, func(job *Job) (string, error) {
+		fired <- job.ID
+		return "", nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	if err := s.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	select {
+	case id := <-fired:
+		t.Fatalf("%q fired from Load; catch-up must wait for CatchUp", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	s.CatchUp()
+	select {
+	case id := <-fired:
+		if id != "reminder" {
+			t.Fatalf("fired %q, want reminder", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("catch-up job did not fire")
+	}
+	select {
+	case id := <-fired:
+		t.Fatalf("unexpected second run %q; non-catch-up job should be discarded", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	jobs, err := ReadJobs(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("store still has %d jobs, want both pruned", len(jobs))
+	}
+}
+
+func TestRunCronJobSkipsSkipDates(t *testing.T) {
+	var fired []string
+	s := &Scheduler{factory: func(job *Job) (string, error) {
+		fired = append(fired, job.ID)
+		return "", nil
+	}}
+	now := time.Now()
+	today := now.Format(SkipDateLayout)
+	tomorrow := now.AddDate(0, 0, 1).Format(SkipDateLayout)
+
+	s.runCronJob(Job{ID: "holiday", Kind: JobKindCron, Expr: "0 8 * * *", SkipDates: []string{today}}, now)
+	s.runCronJob(Job{ID: "workday", Kind: JobKindCron, Expr: "0 8 * * *", SkipDates: []string{tomorrow}}, now)
+	if len(fired) != 1 || fired[0] != "workday" {
+		t.Errorf("fired = %v, want only workday", fired)
+	}
+
+	// Skip dates are read in the expression's CRON_TZ zone: 23:30 UTC on
+	// Dec 24 is already Dec 25 in Tokyo.
+	fired = nil
+	at := time.Date(2026, 12, 24, 23, 30, 0, 0, time.UTC)
+	s.runCronJob(Job{ID: "tokyo", Kind: JobKindCron, Expr: "CRON_TZ=Asia/Tokyo 30 8 * * *", SkipDates: []string{"2026-12-25"}}, at)
+	if len(fired) != 0 {
+		t.Errorf("fired = %v, want the Tokyo holiday skipped", fired)
+	}
+}
@@ -5,9 +5,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"time"
+
+	robfigcron "github.com/robfig/cron/v3"
 )
 
 // ReadJobs reads all jobs from a JSONL file.
@@ -65,20 +71,202 @@ func WriteJobs(path string, jobs []Job) error {
 	return os.Rename(tmp, path)
 }
 
+// ErrJobNotFound is returned by UpdateStoredJob for an unknown job ID.
+var ErrJobNotFound = errors.New("job not found")
+
+// storeStamp identifies one version of the store file by modification time
+// and size. The zero value stands for a missing file.
+type storeStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func statStore(path string) storeStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return storeStamp{}
+	}
+	return storeStamp{mod: info.ModTime(), size: info.Size()}
+}
+
+func (a storeStamp) same(b storeStamp) bool {
+	return a.mod.Equal(b.mod) && a.size == b.size
+}
+
+// lockStore takes the exclusive lock guarding read-modify-write cycles on the
+// store at path (an flock on path+".lock") and returns its release func.
+func lockStore(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("lock cron store: %w", err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// ModifyJobs reads the store, passes its jobs to modify and writes back the
+// result, all under the store lock, so concurrent writers (the CLI and a
+// running scheduler) never drop each other's changes. Nothing is written if
+// modify returns an error.
+func ModifyJobs(path string, modify func([]Job) ([]Job, error)) error {
+	_, err := modifyStore(path, modify)
+	return err
+}
+
+// modifyStore is ModifyJobs that also returns the stamp of the file it wrote,
+// taken before the lock is released.
+func modifyStore(path string, modify func([]Job) ([]Job, error)) (storeStamp, error) {
+	unlock, err := lockStore(path)
+	if err != nil {
+		return storeStamp{}, err
+	}
+	defer unlock()
+
+	jobs, err := ReadJobs(path)
+	if err != nil {
+		return storeStamp{}, err
+	}
+	jobs, err = modify(jobs)
+	if err != nil {
+		return storeStamp{}, err
+	}
+	if err := WriteJobs(path, jobs); err != nil {
+		return storeStamp{}, err
+	}
+	return statStore(path), nil
+}
+
+// UpdateStoredJob applies update to the stored job with the given ID and
+// writes it back in place, keeping every field update does not touch
+// (creation time, wake session, delivery target, ...). The result must still
+// be a valid job; a cron expression is parsed and an at time must be in the
+// future.
+func UpdateStoredJob(path, id string, update func(*Job)) (Job, error) {
+	id = strings.TrimSpace(id)
+	var updated Job
+	err := ModifyJobs(path, func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			if jobs[i].ID != id {
+				continue
+			}
+			job := jobs[i]
+			update(&job)
+			job = Normalize(job)
+			job.ID = id
+			if err := validateSchedule(job); err != nil {
+				return nil, err
+			}
+			if ok, _ := ValidateStored(job, time.Now().UTC()); !ok {
+				return nil, fmt.Errorf("invalid job %q: check task and schedule fields", id)
+			}
+			jobs[i] = job
+			updated = job
+			return jobs, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	})
+	if err != nil {
+		return Job{}, err
+	}
+	return updated, nil
+}
+
+// validateSchedule reports an unparsable cron expression, a past at time or
+// a malformed skip date.
+func validateSchedule(job Job) error {
+	if err := ValidateSkipDates(job.SkipDates); err != nil {
+		return err
+	}
+	switch job.Kind {
+	case JobKindCron:
+		if _, err := robfigcron.ParseStandard(job.Expr); err != nil {
+			return fmt.Errorf("invalid cron expression %q: %w", job.Expr, err)
+		}
+	case JobKindAt:
+		if job.AtTime != nil && !job.AtTime.After(time.Now()) {
+			return fmt.Errorf("at time %s is in the past", job.AtTime.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// readStore returns the stored jobs and records the file's stamp. The stamp
+// is taken first, so a write racing the read only causes one extra reload.
 func (s *Scheduler) readStore() ([]Job, error) {
 	if s.storePath == "" {
 		return nil, nil
 	}
-	return ReadJobs(s.storePath)
+	stamp := statStore(s.storePath)
+	list, err := ReadJobs(s.storePath)
+	if err != nil {
+		return nil, err
+	}
+	s.stamp = stamp
+	s.stale = false
+	return list, nil
 }
 
-func (s *Scheduler) saveLocked() error {
+// saveLocked persists the scheduler's own changes: put upserts jobs and drop
+// removes IDs. They are applied to the store as it is on disk now, not to
+// s.jobs, so jobs another process added or edited since the last load
+// survive. If such edits are found they are loaded on the next
+// ReloadIfChanged.
+func (s *Scheduler) saveLocked(put []Job, drop []string) error {
 	if s.storePath == "" {
 		return nil
 	}
-	list := make([]Job, 0, len(s.jobs))
-	for _, job := range s.jobs {
-		list = append(list, job)
+	external := false
+	stamp, err := modifyStore(s.storePath, func(jobs []Job) ([]Job, error) {
+		external = !statStore(s.storePath).same(s.stamp)
+		replace := make(map[string]Job, len(put))
+		for _, job := range put {
+			replace[job.ID] = job
+		}
+		out := jobs[:0]
+		for _, job := range jobs {
+			if slices.Contains(drop, job.ID) {
+				continue
+			}
+			if r, ok := replace[job.ID]; ok {
+				job = r
+				delete(replace, job.ID)
+			}
+			out = append(out, job)
+		}
+		for _, job := range put {
+			if _, ok := replace[job.ID]; ok {
+				out = append(out, job)
+				delete(replace, job.ID)
+			}
+		}
+		return out, nil
+	})
+	if err != nil {
+		return err
+	}
+	s.stamp = stamp
+	s.stale = s.stale || external
+	return nil
+}
+
+// ReloadIfChanged reloads the store when another process (e.g. `nagobot
+// cron add`) changed it since the scheduler last read or wrote it, and
+// reports whether it did.
+func (s *Scheduler) ReloadIfChanged() (bool, error) {
+	s.mu.Lock()
+	changed := s.storePath != "" && (s.stale || !statStore(s.storePath).same(s.stamp))
+	s.mu.Unlock()
+	if !changed {
+		return false, nil
 	}
-	return WriteJobs(s.storePath, list)
+	return true, s.Load()
 }
@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateStoredJob_DisableAndEnable(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "cron.jsonl")
+	if err := WriteJobs(store, []Job{{ID: "daily", Kind: JobKindCron, Expr: "0 9 * * *", Task: "standup"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewScheduler(store, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	job, err := UpdateStoredJob(store, "daily", func(j *Job) { j.Disabled = true })
+	if err != nil || !job.Disabled {
+		t.Fatalf("disable: job=%+v err=%v", job, err)
+	}
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, scheduled := s.cancels["daily"]; scheduled {
+		t.Error("disabled job was scheduled")
+	}
+	if _, ok := s.FindJob("daily"); !ok {
+		t.Error("disabled job dropped from the scheduler's jobs; it must stay persisted")
+	}
+
+	if _, err := UpdateStoredJob(store, "daily", func(j *Job) { j.Disabled = false }); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, scheduled := s.cancels["daily"]; !scheduled {
+		t.Error("re-enabled job was not scheduled")
+	}
+}
+
+func TestUpdateStoredJob_TaskOnlyKeepsOtherFields(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "cron.jsonl")
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	orig := Job{
+		ID: "digest", Kind: JobKindCron, Expr: "0 8 * * 1", Task: "old task",
+		WakeSession: "telegram:42", Silent: true, CreatedAt: created,
+	}
+	if err := WriteJobs(store, []Job{orig, {ID: "other", Expr: "* * * * *", Task: "x"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := UpdateStoredJob(store, "digest", func(j *Job) { j.Task = "new task" }); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := ReadJobs(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("store has %d jobs, want 2", len(jobs))
+	}
+	got := jobs[0]
+	want := orig
+	want.Task = "new task"
+	if got.ID != want.ID || got.Task != want.Task || got.Expr != want.Expr || got.WakeSession != want.WakeSession ||
+		got.Silent != want.Silent || !got.CreatedAt.Equal(created) {
+		t.Errorf("updated job = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdateStoredJob_Rejects(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "cron.jsonl")
+	if err := WriteJobs(store, []Job{{ID: "daily", Expr: "0 9 * * *", Task: "standup"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpdateStoredJob(store, "missing", func(*Job) {}); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("unknown id: err = %v, want ErrJobNotFound", err)
+	}
+	if _, err := UpdateStoredJob(store, "daily", func(j *Job) { j.Expr = "every tuesday" }); err == nil {
+		t.Error("invalid expression accepted")
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, err := UpdateStoredJob(store, "daily", func(j *Job) { j.Kind, j.Expr, j.AtTime = JobKindAt, "", &past }); err == nil {
+		t.Error("past at time accepted")
+	}
+	if _, err := UpdateStoredJob(store, "daily", func(j *Job) { j.SkipDates = []string{"25/12/2026"} }); err == nil {
+		t.Error("malformed skip date accepted")
+	}
+	jobs, _ := ReadJobs(store)
+	if len(jobs) != 1 || jobs[0].Expr != "0 9 * * *" {
+		t.Errorf("rejected updates changed the store: %+v", jobs)
+	}
+}
+
+func TestConcurrentCLIAndSchedulerWritesKeepBothChanges(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "cron.jsonl")
+	if err := WriteJobs(store, []Job{{ID: "existing", Kind: JobKindCron, Expr: "0 9 * * *", Task: "keep"}}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewScheduler(store, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	if err := s.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { // the running scheduler (remind, schedule_message, ...)
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			if err := s.AddJob(Job{ID: fmt.Sprintf("sched-%02d", i), Kind: JobKindCron, Expr: "0 9 * * *", Task: "s"}); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	go func() { // `nagobot cron set-cron` in another process
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			job := Job{ID: fmt.Sprintf("cli-%02d", i), Kind: JobKindCron, Expr: "0 9 * * *", Task: "c"}
+			if err := ModifyJobs(store, func(jobs []Job) ([]Job, error) { return append(jobs, job), nil }); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	jobs, err := ReadJobs(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2*n+1 {
+		t.Fatalf("store has %d jobs, want %d: %+v", len(jobs), 2*n+1, jobs)
+	}
+
+	reloaded, err := s.ReloadIfChanged()
+	if err != nil || !reloaded {
+		t.Fatalf("ReloadIfChanged = %v, %v; want a reload after external writes", reloaded, err)
+	}
+	if _, ok := s.FindJob("cli-00"); !ok {
+		t.Error("scheduler did not load the CLI's job")
+	}
+	if reloaded, _ := s.ReloadIfChanged(); reloaded {
+		t.Error("reloaded again with no new changes")
+	}
+
+	// A scheduler removal must not resurrect or drop anything else.
+	if _, err := s.RemoveJob("sched-00"); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := ReadJobs(store); len(jobs) != 2*n {
+		t.Errorf("after removal store has %d jobs, want %d", len(jobs), 2*n)
+	}
+}
@@ -15,16 +15,25 @@ const (
 )
 
 type Job struct {
-	ID          string     `json:"id" yaml:"id"`
-	Kind        string     `json:"kind,omitempty" yaml:"kind,omitempty"`
-	Expr        string     `json:"expr,omitempty" yaml:"expr,omitempty"`
-	AtTime      *time.Time `json:"at_time,omitempty" yaml:"at_time,omitempty"`
-	Task        string     `json:"task" yaml:"task"`
-	Agent       string     `json:"agent,omitempty" yaml:"agent,omitempty"`
-	WakeSession string     `json:"wake_session,omitempty" yaml:"wake_session,omitempty"`
-	Silent      bool       `json:"silent,omitempty" yaml:"silent,omitempty"`
-	DirectWake  bool       `json:"direct_wake,omitempty" yaml:"direct_wake,omitempty"`
-	CreatedAt   time.Time  `json:"created_at" yaml:"created_at,omitempty"`
+	ID                string     `json:"id" yaml:"id"`
+	Kind              string     `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Expr              string     `json:"expr,omitempty" yaml:"expr,omitempty"`
+	AtTime            *time.Time `json:"at_time,omitempty" yaml:"at_time,omitempty"`
+	Task              string     `json:"task" yaml:"task"`
+	Agent             string     `json:"agent,omitempty" yaml:"agent,omitempty"`
+	WakeSession       string     `json:"wake_session,omitempty" yaml:"wake_session,omitempty"`
+	Silent            bool       `json:"silent,omitempty" yaml:"silent,omitempty"`
+	DirectWake        bool       `json:"direct_wake,omitempty" yaml:"direct_wake,omitempty"`
+	Deliver           bool       `json:"deliver,omitempty" yaml:"deliver,omitempty"`                 // post Task verbatim to Channel/To; no agent turn
+	Channel           string     `json:"channel,omitempty" yaml:"channel,omitempty"`                 // deliver mode: target channel name (telegram, discord, ...)
+	To                string     `json:"to,omitempty" yaml:"to,omitempty"`                           // deliver mode: channel-specific recipient (chat ID, user ID, ...)
+	CatchUp           bool       `json:"catch_up,omitempty" yaml:"catch_up,omitempty"`               // at jobs: if missed while down, fire once after channels start instead of discarding
+	Disabled          bool       `json:"disabled,omitempty" yaml:"disabled,omitempty"`               // kept in the store but not scheduled
+	Heartbeat         bool       `json:"heartbeat,omitempty" yaml:"heartbeat,omitempty"`             // self-scheduled check-in: wakes WakeSession as a heartbeat, not a cron wake
+	SkipDates         []string   `json:"skip_dates,omitempty" yaml:"skip_dates,omitempty"`           // cron jobs: YYYY-MM-DD days (in the job's timezone) on which fires are skipped
+	CreatorSessionKey string     `json:"creator_session,omitempty" yaml:"creator_session,omitempty"` // session whose tool call created the job (remind, schedule_message)
+	Admin             bool       `json:"admin,omitempty" yaml:"admin,omitempty"`                     // fires run as admin: created by config, the CLI, or an admin turn
+	CreatedAt         time.Time  `json:"created_at" yaml:"created_at,omitempty"`
 }
 
 type ThreadFactory func(job *Job) (string, error)
@@ -33,7 +42,10 @@ type ThreadFactory func(job *Job) (string, error)
 //   - seedJobs: config-defined defaults, scheduled but not persisted (not in s.jobs)
 //   - jobs: store-sourced (cron.jsonl), persisted via saveLocked()
 //
-// Both share s.cancels for teardown on resetLocked().
+// Both share s.cancels for teardown on resetLocked(). The store is shared
+// with the `nagobot cron` CLI: writes go through the store lock, saves only
+// apply the scheduler's own changes, and ReloadIfChanged picks up edits made
+// by other processes.
 type Scheduler struct {
 	cron      gocron.Scheduler
 	factory   ThreadFactory
@@ -41,6 +53,10 @@ type Scheduler struct {
 	seedJobs  []Job // config-defined seeds, not persisted
 	cancels   map[string]func()
 	storePath string
+	stamp     storeStamp // store file as last read or written by this scheduler
+	stale     bool       // a save merged external edits not yet loaded into s.jobs
+	missed    []Job      // catch_up at jobs pruned by Load before CatchUp was called
+	caughtUp  bool       // CatchUp has run; later Loads fire missed jobs at once
 	mu        sync.Mutex
 }
 
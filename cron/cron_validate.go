@@ -1,14 +1,32 @@
 package cron
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
+// SkipDateLayout is the format of Job.SkipDates entries.
+const SkipDateLayout = "2006-01-02"
+
+// ValidateSkipDates reports the first entry that is not a YYYY-MM-DD date.
+func ValidateSkipDates(dates []string) error {
+	for _, d := range dates {
+		if _, err := time.Parse(SkipDateLayout, strings.TrimSpace(d)); err != nil {
+			return fmt.Errorf("invalid skip date %q: want YYYY-MM-DD", d)
+		}
+	}
+	return nil
+}
+
 func ValidateStored(job Job, now time.Time) (ok bool, expiredAt bool) {
 	if job.ID == "" || job.Task == "" {
 		return false, false
 	}
+	if job.Deliver && job.Channel == "" {
+		return false, false
+	}
 	switch job.Kind {
 	case JobKindCron:
 		return job.Expr != "", false
@@ -31,6 +49,10 @@ func Normalize(job Job) Job {
 	job.Task = strings.TrimSpace(job.Task)
 	job.Agent = strings.TrimSpace(job.Agent)
 	job.WakeSession = strings.TrimSpace(job.WakeSession)
+	job.CreatorSessionKey = strings.TrimSpace(job.CreatorSessionKey)
+	job.Channel = strings.TrimSpace(job.Channel)
+	job.To = strings.TrimSpace(job.To)
+	job.SkipDates = normalizeSkipDates(job.SkipDates)
 	if job.AtTime != nil {
 		utc := job.AtTime.UTC()
 		job.AtTime = &utc
@@ -48,3 +70,16 @@ func Normalize(job Job) Job {
 	}
 	return job
 }
+
+// normalizeSkipDates trims, sorts and de-duplicates skip dates, returning nil
+// when none are left.
+func normalizeSkipDates(dates []string) []string {
+	var out []string
+	for _, d := range dates {
+		if d = strings.TrimSpace(d); d != "" {
+			out = append(out, d)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
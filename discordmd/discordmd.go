@@ -0,0 +1,315 @@
+// Package discordmd adapts standard Markdown for Discord messages.
+//
+// Discord renders its own Markdown dialect: bold, italic, strikethrough,
+// inline code, code blocks, headings, quotes and lists work as written, but
+// there is no HTML and no GFM table or task-list syntax. Convert therefore
+// keeps the source text and rewrites only what Discord would mangle:
+//   - Tables become an aligned code block when they are small and plain, or
+//     a numbered field list otherwise (cell formatting then still renders)
+//   - Task-list checkboxes become ✅ / ☐
+package discordmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	// MaxMessageLen is Discord's message length limit in characters.
+	MaxMessageLen = 2000
+
+	// codeTableMaxWidth is the widest row, in display columns, rendered as a
+	// code block; wider tables wrap badly on mobile and use field lists.
+	codeTableMaxWidth = 60
+	// codeTableMaxBytes keeps a code-block table well inside one message so
+	// splitting never cuts through its fences.
+	codeTableMaxBytes = MaxMessageLen * 3 / 4
+)
+
+// Convert rewrites tables and task lists in markdown for Discord and leaves
+// everything else untouched.
+func Convert(markdown string) string {
+	source := []byte(markdown)
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var edits []edit
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *east.Table:
+			// Tables nested in quotes or lists keep their source: replacing
+			// whole lines would drop the container's prefixes.
+			if _, top := v.Parent().(*ast.Document); top {
+				if e, ok := tableEdit(v, source); ok {
+					edits = append(edits, e)
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *east.TaskCheckBox:
+			if e, ok := checkBoxEdit(v, source); ok {
+				edits = append(edits, e)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(edits) == 0 {
+		return markdown
+	}
+
+	slices.SortFunc(edits, func(a, b edit) int { return a.start - b.start })
+	var b strings.Builder
+	b.Grow(len(markdown))
+	pos := 0
+	for _, e := range edits {
+		if e.start < pos {
+			continue
+		}
+		b.Write(source[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.stop
+	}
+	b.Write(source[pos:])
+	return b.String()
+}
+
+// edit replaces source[start:stop] with text.
+type edit struct {
+	start, stop int
+	text        string
+}
+
+// checkBoxEdit replaces the "[x]" / "[ ]" marker that starts the checkbox's
+// text block.
+func checkBoxEdit(box *east.TaskCheckBox, source []byte) (edit, bool) {
+	parent := box.Parent()
+	if parent == nil || parent.Lines().Len() == 0 {
+		return edit{}, false
+	}
+	start := parent.Lines().At(0).Start
+	if start+3 > len(source) {
+		return edit{}, false
+	}
+	switch strings.ToLower(string(source[start : start+3])) {
+	case "[x]", "[ ]":
+	default:
+		return edit{}, false
+	}
+	mark := "\u2610" // ☐
+	if box.IsChecked {
+		mark = "\u2705" // ✅
+	}
+	return edit{start: start, stop: start + 3, text: mark}, true
+}
+
+// ---------------------------------------------------------------------------
+// Tables
+// ---------------------------------------------------------------------------
+
+// tableCell is one cell: its raw Markdown and its plain text.
+type tableCell struct {
+	raw, plain string
+}
+
+type table struct {
+	headers []tableCell
+	rows    [][]tableCell
+	align   []east.Alignment
+	styled  bool // some cell has formatting that a code block would lose
+}
+
+// tableEdit replaces the table's full source lines with its rendering.
+func tableEdit(t *east.Table, source []byte) (edit, bool) {
+	tbl, start, stop := parseTable(t, source)
+	if len(tbl.headers) == 0 || start < 0 {
+		return edit{}, false
+	}
+	for start > 0 && source[start-1] != '\n' {
+		start--
+	}
+	for stop < len(source) && source[stop] != '\n' {
+		stop++
+	}
+	rendered, ok := renderCodeTable(tbl)
+	if !ok {
+		if len(tbl.rows) == 0 {
+			return edit{}, false
+		}
+		rendered = renderFieldList(tbl)
+	}
+	return edit{start: start, stop: stop, text: rendered}, true
+}
+
+// parseTable collects the cells of t, padding short rows, and returns the
+// source span covered by its cells.
+func parseTable(t *east.Table, source []byte) (tbl table, start, stop int) {
+	start = -1
+	tbl.align = t.Alignments
+	for row := t.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []tableCell
+		for c := row.FirstChild(); c != nil; c = c.NextSibling() {
+			lines := c.Lines()
+			var raw string
+			if lines.Len() > 0 {
+				first, last := lines.At(0), lines.At(lines.Len()-1)
+				raw = strings.TrimSpace(string(source[first.Start:last.Stop]))
+				if start < 0 || first.Start < start {
+					start = first.Start
+				}
+				stop = max(stop, last.Stop)
+			}
+			raw = strings.ReplaceAll(raw, `\|`, "|")
+			cells = append(cells, tableCell{raw: raw, plain: strings.ReplaceAll(plainText(c, source), `\|`, "|")})
+			if hasFormatting(c) {
+				tbl.styled = true
+			}
+		}
+		if _, ok := row.(*east.TableHeader); ok {
+			tbl.headers = cells
+		} else {
+			tbl.rows = append(tbl.rows, cells)
+		}
+	}
+
+	numCols := len(tbl.headers)
+	for _, r := range tbl.rows {
+		numCols = max(numCols, len(r))
+	}
+	for len(tbl.headers) < numCols {
+		tbl.headers = append(tbl.headers, tableCell{})
+	}
+	for i := range tbl.rows {
+		for len(tbl.rows[i]) < numCols {
+			tbl.rows[i] = append(tbl.rows[i], tableCell{})
+		}
+	}
+	return tbl, start, stop
+}
+
+// hasFormatting reports whether n contains inline nodes other than plain text.
+func hasFormatting(n ast.Node) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c.(type) {
+		case *ast.Text, *ast.String:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func plainText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+		case *ast.String:
+			b.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
+
+// renderCodeTable renders a plain, narrow table as column-aligned text in a
+// code block. ok is false when the table should be a field list instead.
+func renderCodeTable(tbl table) (string, bool) {
+	if tbl.styled {
+		return "", false
+	}
+	widths := make([]int, len(tbl.headers))
+	for _, r := range append([][]tableCell{tbl.headers}, tbl.rows...) {
+		for j, c := range r {
+			if strings.Contains(c.plain, "```") {
+				return "", false
+			}
+			widths[j] = max(widths[j], runewidth.StringWidth(c.plain))
+		}
+	}
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	if total > codeTableMaxWidth {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	writeRow := func(cells []tableCell) {
+		var line strings.Builder
+		for j, c := range cells {
+			if j > 0 {
+				line.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[j]-runewidth.StringWidth(c.plain))
+			if j < len(tbl.align) && tbl.align[j] == east.AlignRight {
+				line.WriteString(pad + c.plain)
+			} else {
+				line.WriteString(c.plain + pad)
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	writeRow(tbl.headers)
+	rule := make([]string, len(widths))
+	for j, w := range widths {
+		rule[j] = strings.Repeat("-", max(w, 1))
+	}
+	b.WriteString(strings.Join(rule, "  "))
+	b.WriteByte('\n')
+	for _, r := range tbl.rows {
+		writeRow(r)
+	}
+	b.WriteString("```")
+
+	if b.Len() > codeTableMaxBytes {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// renderFieldList renders each row as a numbered entry with one
+// "• **header**: value" line per cell, keeping the cells' Markdown. An empty
+// first header marks a row-label column, which becomes the entry title.
+func renderFieldList(tbl table) string {
+	rowLabelCol := tbl.headers[0].raw == ""
+	var out []string
+	for i, row := range tbl.rows {
+		if rowLabelCol && row[0].raw != "" {
+			out = append(out, fmt.Sprintf("**%d. %s**", i+1, row[0].raw))
+		} else {
+			out = append(out, fmt.Sprintf("**%d.**", i+1))
+		}
+		startCol := 0
+		if rowLabelCol {
+			startCol = 1
+		}
+		for j := startCol; j < len(row); j++ {
+			h := tbl.headers[j].raw
+			if h == "" {
+				h = fmt.Sprintf("Column %d", j+1)
+			}
+			out = append(out, fmt.Sprintf("• **%s**: %s", h, row[j].raw))
+		}
+		if i < len(tbl.rows)-1 {
+			out = append(out, "")
+		}
+	}
+	return strings.Join(out, "\n")
+}
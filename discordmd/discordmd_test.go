@@ -0,0 +1,97 @@
+package discordmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNativeMarkdownUnchanged(t *testing.T) {
+	md := "# Title\n\nHello **bold**, *italic*, ~~gone~~ and `code`.\n\n> quoted\n\n- one\n- two\n\n```go\nfmt.Println(\"| not | a | table |\")\n```"
+	if got := Convert(md); got != md {
+		t.Errorf("Discord-native markdown was modified:\n got: %q\nwant: %q", got, md)
+	}
+}
+
+func TestTableAsAlignedCodeBlock(t *testing.T) {
+	md := "Ages:\n\n| Name | Age |\n|------|----:|\n| Alice | 30 |\n| Bob | 5 |\n\nDone."
+	got := Convert(md)
+	want := "Ages:\n\n```\nName   Age\n-----  ---\nAlice   30\nBob      5\n```\n\nDone."
+	if got != want {
+		t.Errorf("table rendering:\n got: %q\nwant: %q", got, want)
+	}
+	if len(got) > MaxMessageLen {
+		t.Errorf("rendered length %d exceeds %d", len(got), MaxMessageLen)
+	}
+}
+
+func TestTableCJKAlignment(t *testing.T) {
+	got := Convert("| 名称 | 值 |\n|---|---|\n| 温度 | 21 |\n| humidity | 40 |")
+	lines := strings.Split(got, "\n")
+	// Wide runes count as two columns, so the value column lines up.
+	if len(lines) != 6 || lines[3] != "温度      21" || lines[4] != "humidity  40" {
+		t.Errorf("CJK table misaligned:\n%s", got)
+	}
+}
+
+func TestStyledTableAsFieldList(t *testing.T) {
+	md := "| 作用 | 原理 |\n|------|------|\n| **抗氧化** | 清除自由基 |\n| **抗炎** | 抑制炎症因子 |"
+	got := Convert(md)
+	for _, want := range []string{"**1.**", "• **作用**: **抗氧化**", "• **原理**: 清除自由基", "**2.**", "• **作用**: **抗炎**"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "|") || strings.Contains(got, "```") {
+		t.Errorf("styled table should become a field list:\n%s", got)
+	}
+}
+
+func TestWideTableAsFieldList(t *testing.T) {
+	long := strings.Repeat("word ", 15)
+	got := Convert("|  | Detail |\n|---|---|\n| Plan A | " + long + "|\n| Plan B | short |")
+	for _, want := range []string{"**1. Plan A**", "• **Detail**: " + strings.TrimSpace(long), "**2. Plan B**", "• **Detail**: short"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestLargeTableFitsInOneMessage(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("| ID | Status |\n|---|---|\n")
+	for i := 0; i < 200; i++ {
+		b.WriteString("| task-0001 | pending |\n")
+	}
+	got := Convert(b.String())
+	if strings.Contains(got, "```") {
+		t.Errorf("a table longer than one message should not be a code block (%d bytes)", len(got))
+	}
+	for _, chunk := range strings.Split(got, "\n\n") {
+		if len(chunk) > MaxMessageLen {
+			t.Errorf("field-list entry of %d bytes exceeds %d", len(chunk), MaxMessageLen)
+		}
+	}
+}
+
+func TestTaskList(t *testing.T) {
+	md := "Today:\n\n- [x] write tests\n- [ ] ship **it**\n  - [X] nested done\n\nNot a task: [x] inline."
+	got := Convert(md)
+	want := "Today:\n\n- ✅ write tests\n- ☐ ship **it**\n  - ✅ nested done\n\nNot a task: [x] inline."
+	if got != want {
+		t.Errorf("task list rendering:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestTableInsideCodeBlockUnchanged(t *testing.T) {
+	md := "```\n| Name | Age |\n|------|-----|\n| Alice | 30 |\n```"
+	if got := Convert(md); got != md {
+		t.Errorf("table inside code block was modified:\n got: %q\nwant: %q", got, md)
+	}
+}
+
+func TestEscapedPipeInCell(t *testing.T) {
+	got := Convert("| Expr | Meaning |\n|---|---|\n| a \\| b | either |")
+	if !strings.Contains(got, "a | b  either") {
+		t.Errorf("escaped pipe not restored:\n%s", got)
+	}
+}
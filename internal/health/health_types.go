@@ -20,13 +20,48 @@ type Snapshot struct {
 	Thread        *ThreadInfo    `json:"thread,omitempty" yaml:"thread,omitempty"`
 	Session       *SessionInfo   `json:"session,omitempty" yaml:"session,omitempty"`
 	Sessions      *SessionsInfo  `json:"sessions,omitempty" yaml:"sessions,omitempty"`
+	ProviderProbe *ProviderProbe `json:"providerProbe,omitempty" yaml:"provider_probe,omitempty"`
+	Breakers      []BreakerInfo  `json:"breakers,omitempty" yaml:"breakers,omitempty"`
 	Channels      *ChannelsInfo   `json:"channels,omitempty" yaml:"channels,omitempty"`
+	ChannelQueues []ChannelQueueInfo `json:"channelQueues,omitempty" yaml:"channel_queues,omitempty"`
+	Turns         *TurnsInfo         `json:"turns,omitempty" yaml:"turns,omitempty"`
 	Cron          *CronInfo      `json:"cron,omitempty" yaml:"cron,omitempty"`
 	LogHealth     *LogHealth       `json:"logHealth,omitempty" yaml:"log_health,omitempty"`
 	AllThreads []msg.ThreadInfo `json:"allThreads,omitempty" yaml:"all_threads,omitempty"`
 	WorkspaceTree *WorkspaceTree  `json:"workspaceTree,omitempty" yaml:"workspace_tree,omitempty"`
 }
 
+// ProviderProbe is the result of a live connectivity check against the
+// LLM provider (only populated by deep health checks).
+type ProviderProbe struct {
+	Reachable bool   `json:"reachable" yaml:"reachable"`
+	LatencyMs int64  `json:"latencyMs" yaml:"latency_ms"`
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// BreakerInfo is the circuit breaker state of one LLM provider.
+type BreakerInfo struct {
+	Provider            string `json:"provider" yaml:"provider"`
+	State               string `json:"state" yaml:"state"` // closed, open, half-open
+	ConsecutiveFailures int    `json:"consecutiveFailures" yaml:"consecutive_failures"`
+	RetryAt             string `json:"retryAt,omitempty" yaml:"retry_at,omitempty"`
+	LastError           string `json:"lastError,omitempty" yaml:"last_error,omitempty"`
+}
+
+// ChannelQueueInfo is the fill level of one channel's inbound message buffer.
+type ChannelQueueInfo struct {
+	Channel  string `json:"channel" yaml:"channel"`
+	Depth    int    `json:"depth" yaml:"depth"`
+	Capacity int    `json:"capacity" yaml:"capacity"`
+}
+
+// TurnsInfo is the global agent-turn limiter state.
+type TurnsInfo struct {
+	InFlight int `json:"inFlight" yaml:"in_flight"` // turns currently executing
+	Waiting  int `json:"waiting" yaml:"waiting"`    // ready turns queued for a slot
+	Limit    int `json:"limit" yaml:"limit"`        // thread.maxConcurrentTurns
+}
+
 // MemoryInfo contains memory statistics in MB.
 type MemoryInfo struct {
 	AllocMB      float64 `json:"allocMB" yaml:"alloc_mb"`
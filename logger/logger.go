@@ -17,6 +17,7 @@ type Config struct {
 	Level   string
 	Stdout  bool
 	File    string
+	Secrets []string // Configured API keys and tokens masked in every log line.
 }
 
 var (
@@ -36,6 +37,7 @@ func Init(cfg Config, configDir string) error {
 	defer mu.Unlock()
 
 	savedCfg = cfg
+	SetSecrets(cfg.Secrets)
 
 	if !cfg.Enabled {
 		enabled = false
@@ -78,11 +80,22 @@ func Restore() {
 	rebuild()
 }
 
+// SetLevel changes the minimum log level ("debug", "info", "warn", "error")
+// without reopening the log file. No-op while logging is disabled.
+func SetLevel(level string) {
+	mu.Lock()
+	defer mu.Unlock()
+	savedCfg.Level = level
+	if enabled {
+		rebuild()
+	}
+}
+
 // rebuild reconstructs the slog handler from current state.
 // Must be called with mu held.
 func rebuild() {
 	level := parseLevel(savedCfg.Level)
-	opts := &slog.HandlerOptions{Level: level}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
 
 	var writers []io.Writer
 	if intercept != nil {
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redactMask replaces every secret found in a log value.
+const redactMask = "[REDACTED]"
+
+// minSecretLen skips configured values too short to be credentials; masking
+// them would mangle ordinary words.
+const minSecretLen = 8
+
+// secretPatterns match credentials regardless of configuration: OpenAI-style
+// sk- keys, bearer tokens, and key/authorization fields in JSON or headers.
+// The last pattern keeps group 1 (the field name) and masks the value.
+var (
+	skKeyPattern    = regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{16,}`)
+	bearerPattern   = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=\-]{8,}`)
+	keyFieldPattern = regexp.MustCompile(`(?i)("?(?:api[_-]?key|x-api-key|authorization|access[_-]?token|refresh[_-]?token)"?\s*[:=]\s*"?)(?:(?:bearer|basic|token)\s+)?[^\s"',}&]+`)
+)
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string // configured secret values, longest first
+)
+
+// SetSecrets replaces the configured secret values (API keys, tokens) that
+// Redact masks. Values shorter than 8 characters are ignored.
+func SetSecrets(values []string) {
+	var list []string
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < minSecretLen || seen[v] {
+			continue
+		}
+		seen[v] = true
+		list = append(list, v)
+	}
+	// Longest first so a key containing another key is masked whole.
+	sort.Slice(list, func(i, j int) bool { return len(list[i]) > len(list[j]) })
+	secretsMu.Lock()
+	secrets = list
+	secretsMu.Unlock()
+}
+
+// Redact masks configured secrets and anything that looks like an API key,
+// bearer token, or authorization value in s.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	secretsMu.RLock()
+	for _, v := range secrets {
+		s = strings.ReplaceAll(s, v, redactMask)
+	}
+	secretsMu.RUnlock()
+	s = skKeyPattern.ReplaceAllString(s, redactMask)
+	s = keyFieldPattern.ReplaceAllString(s, "${1}"+redactMask)
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redactMask)
+	return s
+}
+
+// redactAttr is the slog ReplaceAttr hook: it redacts string and error values
+// (including the message) before they reach any writer.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if r := Redact(a.Value.String()); r != a.Value.String() {
+			a.Value = slog.StringValue(r)
+		}
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			if r := Redact(v.Error()); r != v.Error() {
+				a.Value = slog.StringValue(r)
+			}
+		case []byte:
+			a.Value = slog.StringValue(Redact(string(v)))
+		}
+	}
+	return a
+}
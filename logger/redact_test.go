@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogLineMasksConfiguredKey(t *testing.T) {
+	dir := t.TempDir()
+	key := "dsk-4f9a1c77e2b04d3e"
+	if err := Init(Config{Enabled: true, Level: "debug", File: "test.log", Secrets: []string{key, "short"}}, dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		SetSecrets(nil)
+		_ = Init(Config{Enabled: false}, dir)
+	})
+
+	Error("provider request error",
+		"body", `{"error":"invalid key `+key+`"}`,
+		"err", errors.New("401: Authorization: Bearer abcdefghijklmnop"),
+		"raw", `{"api_key":"plain-value-123","model":"short"}`,
+	)
+	Info("using key " + key)
+
+	data, err := os.ReadFile(filepath.Join(dir, "test.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, leak := range []string{key, "abcdefghijklmnop", "plain-value-123"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("log leaked %q:\n%s", leak, out)
+		}
+	}
+	if !strings.Contains(out, redactMask) {
+		t.Errorf("log has no mask:\n%s", out)
+	}
+	// Values under the minimum length are not treated as secrets.
+	if !strings.Contains(out, "short") {
+		t.Errorf("short configured value should not be masked:\n%s", out)
+	}
+}
+
+func TestRedactPatterns(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"key sk-abcdefghijklmnopqrstu end", "key [REDACTED] end"},
+		{"Authorization: Bearer eyJhbGciOi.payload", "Authorization: [REDACTED]"},
+		{"curl -H 'x-api-key=abc123456'", "curl -H 'x-api-key=[REDACTED]'"},
+		{"nothing secret here", "nothing secret here"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
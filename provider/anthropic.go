@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -77,9 +78,10 @@ func anthropicRateLimitMiddleware(req *http.Request, next aoption.MiddlewareNext
 
 func init() {
 	shared := ProviderRegistration{
-		Models:       []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"},
-		VisionModels: []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"},
-		PDFModels:    []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"},
+		Models:          []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"},
+		VisionModels:    []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"},
+		PDFModels:       []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"},
+		ReasoningModels: anthropicReasoningModels,
 		ContextWindows: map[string]int{
 			"claude-sonnet-4-6": 1048576,
 			"claude-opus-4-6":   1048576,
@@ -117,13 +119,11 @@ const (
 	anthropicThinkingDefaultBudget = 2048
 )
 
+// anthropicReasoningModels run with extended thinking enabled.
+var anthropicReasoningModels = []string{"claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5"}
+
 func anthropicThinkingEnabled(modelType string) bool {
-	switch strings.TrimSpace(modelType) {
-	case "claude-sonnet-4-6", "claude-opus-4-6", "claude-haiku-4-5":
-		return true
-	default:
-		return false
-	}
+	return slices.Contains(anthropicReasoningModels, strings.TrimSpace(modelType))
 }
 
 func anthropicRequestTemperature(thinkingEnabled bool, configured float64) (float64, bool) {
@@ -192,6 +192,16 @@ func newAnthropicProvider(apiKey, apiBase, modelType, modelName string, maxToken
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *AnthropicProvider) SetMaxRetries(n int) {
+	p.client = anthropic.NewClient(append(slices.Clone(p.client.Options), aoption.WithMaxRetries(n))...)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *AnthropicProvider) SetTransport(rt http.RoundTripper) {
+	p.client = anthropic.NewClient(append(slices.Clone(p.client.Options), aoption.WithHTTPClient(&http.Client{Transport: rt}))...)
+}
+
 func anthropicInputChars(systemPrompt string, messages []Message) int {
 	total := len(systemPrompt)
 	for _, m := range messages {
@@ -474,10 +484,14 @@ func toAnthropicMessages(messages []Message) (string, []anthropic.MessageParam,
 					OfText: &anthropic.TextBlockParam{Text: "(empty)"},
 				})
 			}
-			pendingToolResults = append(pendingToolResults, anthropic.ContentBlockParamUnion{OfToolResult: &anthropic.ToolResultBlockParam{
+			block := &anthropic.ToolResultBlockParam{
 				ToolUseID: m.ToolCallID,
 				Content:   content,
-			}})
+			}
+			if m.IsError {
+				block.IsError = anthropic.Bool(true)
+			}
+			pendingToolResults = append(pendingToolResults, anthropic.ContentBlockParamUnion{OfToolResult: block})
 		default:
 			return "", nil, fmt.Errorf("unsupported message role: %s", m.Role)
 		}
@@ -497,7 +511,9 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 	}
 	inputChars := anthropicInputChars(systemPrompt, req.Messages)
 	tools := toAnthropicTools(req.Tools)
-	thinkingEnabled := anthropicThinkingEnabled(p.modelType)
+	// Thinking needs a budget of at least 1024 tokens, so a capped request
+	// (e.g. Ping) runs without it.
+	thinkingEnabled := anthropicThinkingEnabled(p.modelType) && req.MaxTokens <= 0
 
 	logger.Info(
 		"anthropic request",
@@ -513,6 +529,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 	if maxTokens <= 0 {
 		maxTokens = anthropicFallbackMaxTokens
 	}
+	maxTokens = req.outputCap(maxTokens)
 	if thinkingEnabled && maxTokens <= anthropicThinkingMinBudget {
 		logger.Warn(
 			"anthropic max_tokens adjusted for thinking constraints",
@@ -643,6 +660,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 					bs.signature.WriteString(event.Delta.Signature)
 				case "input_json_delta":
 					bs.args.WriteString(event.Delta.PartialJSON)
+					adapter.EmitToolCallArgs(int(event.Index), bs.id, event.Delta.PartialJSON)
 				}
 
 			case "content_block_stop":
@@ -672,12 +690,17 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 						Data: bs.data,
 					})
 				case "tool_use":
+					// A call with no input streams no input_json_delta.
+					args := bs.args.String()
+					if strings.TrimSpace(args) == "" {
+						args = "{}"
+					}
 					toolCalls = append(toolCalls, ToolCall{
 						ID:   bs.id,
 						Type: "function",
 						Function: FunctionCall{
 							Name:      bs.name,
-							Arguments: bs.args.String(),
+							Arguments: args,
 						},
 					})
 				}
@@ -689,7 +712,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 		}
 		if err := stream.Err(); err != nil {
 			logger.Error("anthropic stream error", "provider", "anthropic", "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(ClassifyContextLength("anthropic", fmt.Errorf("request failed: %w", err)))
 		}
 
 		content := strings.Join(textParts, "\n")
@@ -724,6 +747,7 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 		resp.ReasoningContent = reasoningContent
 		resp.ReasoningDetails = reasoningDetailsJSON
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = stopReason
 		resp.Usage = Usage{
 			PromptTokens:     totalInput,
 			CompletionTokens: int(completionTokens),
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// anthropicSSETranscript is a canned /v1/messages stream: a thinking block,
+// two text deltas, a tool_use whose input arrives in fragments, and a
+// no-input tool_use.
+var anthropicSSETranscript = []string{
+	`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-6","content":[],"usage":{"input_tokens":100,"cache_creation_input_tokens":20,"cache_read_input_tokens":30,"output_tokens":1}}}`,
+	`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":"","signature":""}}`,
+	`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"check the weather"}}`,
+	`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig-1"}}`,
+	`{"type":"content_block_stop","index":0}`,
+	`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+	`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Let me "}}`,
+	`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"look."}}`,
+	`{"type":"content_block_stop","index":1}`,
+	`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"weather","input":{}}}`,
+	`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+	`{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+	`{"type":"content_block_stop","index":2}`,
+	`{"type":"content_block_start","index":3,"content_block":{"type":"tool_use","id":"toolu_2","name":"now","input":{}}}`,
+	`{"type":"content_block_stop","index":3}`,
+	`{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":42}}`,
+	`{"type":"message_stop"}`,
+}
+
+func TestAnthropicStreamAssemblesResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, data := range anthropicSSETranscript {
+			var ev struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal([]byte(data), &ev)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+		}
+	}))
+	defer srv.Close()
+
+	p := newAnthropicProvider("test-key", srv.URL, "claude-sonnet-4-6", "", 4096, 0)
+	p.SetMaxRetries(0)
+	res, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("weather in Paris?")}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	stream, ok := res.(StreamChatResult)
+	if !ok {
+		t.Fatalf("result is %T, want StreamChatResult", res)
+	}
+
+	var text strings.Builder
+	var firstTool string
+	for {
+		d, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		switch d.Type {
+		case DeltaText:
+			text.WriteString(d.Text)
+		case DeltaToolCall:
+			if firstTool == "" {
+				firstTool = d.ToolName
+			}
+		}
+	}
+	if text.String() != "Let me look." {
+		t.Errorf("streamed text = %q", text.String())
+	}
+	if firstTool != "weather" {
+		t.Errorf("first tool delta = %q, want weather", firstTool)
+	}
+
+	resp, err := res.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if resp.Content != "Let me look." {
+		t.Errorf("content = %q", resp.Content)
+	}
+	if resp.ReasoningContent != "check the weather" {
+		t.Errorf("reasoning = %q", resp.ReasoningContent)
+	}
+	var details []anthropicThinkingDetail
+	if err := json.Unmarshal(resp.ReasoningDetails, &details); err != nil || len(details) != 1 || details[0].Signature != "sig-1" {
+		t.Errorf("reasoning details = %s (%v)", resp.ReasoningDetails, err)
+	}
+	if len(resp.ToolCalls) != 2 {
+		t.Fatalf("tool calls = %+v", resp.ToolCalls)
+	}
+	if tc := resp.ToolCalls[0]; tc.ID != "toolu_1" || tc.Function.Name != "weather" || tc.Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("tool call 0 = %+v", tc)
+	}
+	if tc := resp.ToolCalls[1]; tc.Function.Name != "now" || tc.Function.Arguments != "{}" {
+		t.Errorf("tool call 1 = %+v, want empty-object arguments", tc)
+	}
+	if resp.FinishReason != "tool_use" {
+		t.Errorf("finish reason = %q", resp.FinishReason)
+	}
+	want := Usage{PromptTokens: 150, CompletionTokens: 42, TotalTokens: 192, CachedTokens: 30}
+	if resp.Usage != want {
+		t.Errorf("usage = %+v, want %+v", resp.Usage, want)
+	}
+}
+
+func TestToAnthropicMessagesSetsToolResultIsError(t *testing.T) {
+	failed := ToolResultMessage("toolu_1", "exec", "command failed")
+	failed.IsError = true
+	msgs := []Message{
+		UserMessage("run it"),
+		AssistantMessageWithTools("", "", nil, []ToolCall{
+			{ID: "toolu_1", Type: "function", Function: FunctionCall{Name: "exec", Arguments: `{}`}},
+			{ID: "toolu_2", Type: "function", Function: FunctionCall{Name: "exec", Arguments: `{}`}},
+		}),
+		failed,
+		ToolResultMessage("toolu_2", "exec", "ok"),
+	}
+	_, out, err := toAnthropicMessages(msgs)
+	if err != nil {
+		t.Fatalf("toAnthropicMessages: %v", err)
+	}
+	raw, err := json.Marshal(out[len(out)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(raw)
+	if strings.Count(body, `"is_error":true`) != 1 {
+		t.Errorf("want exactly one is_error block, got %s", body)
+	}
+}
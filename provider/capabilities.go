@@ -0,0 +1,30 @@
+package provider
+
+import "slices"
+
+// ModelCapabilities describes what a registered provider+model pair can do,
+// as declared in its ProviderRegistration.
+type ModelCapabilities struct {
+	Vision     bool // image input
+	Audio      bool // audio input
+	PDF        bool // PDF document input
+	Reasoning  bool // thinking/reasoning mode is enabled for the model
+	ToolCalls  bool // function calling; true for every registered model
+	JSONMode   bool // the API offers a JSON output mode
+	MaxContext int  // context window in tokens, 0 if unknown
+}
+
+// Capabilities returns the capabilities of a provider+model pair. Unknown
+// pairs report no capabilities.
+func Capabilities(providerName, modelType string) ModelCapabilities {
+	key := providerName + ":" + modelType
+	return ModelCapabilities{
+		Vision:     visionCapable[key],
+		Audio:      audioCapable[key],
+		PDF:        pdfCapable[key],
+		Reasoning:  reasoningCapable[key],
+		ToolCalls:  slices.Contains(providerModelTypes[providerName], modelType),
+		JSONMode:   jsonModeCapable[key],
+		MaxContext: providerModelContextWindows[key],
+	}
+}
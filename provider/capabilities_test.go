@@ -0,0 +1,25 @@
+package provider
+
+import "testing"
+
+func TestCapabilitiesGLM5(t *testing.T) {
+	for _, prov := range []string{"zhipu-cn", "zhipu-global"} {
+		caps := Capabilities(prov, "glm-5")
+		if !caps.Reasoning || !caps.ToolCalls || caps.MaxContext != 200000 {
+			t.Errorf("%s glm-5 = %+v, want reasoning, tool calls and a 200000-token window", prov, caps)
+		}
+		if caps.Vision {
+			t.Errorf("%s glm-5 reports vision", prov)
+		}
+	}
+	if caps := Capabilities("openrouter", "z-ai/glm-5"); !caps.Reasoning || caps.MaxContext != 200000 {
+		t.Errorf("openrouter z-ai/glm-5 = %+v", caps)
+	}
+	// glm-5-turbo runs without thinking.
+	if caps := Capabilities("zhipu-cn", "glm-5-turbo"); caps.Reasoning || caps.MaxContext != 202752 {
+		t.Errorf("zhipu-cn glm-5-turbo = %+v", caps)
+	}
+	if caps := Capabilities("zhipu-cn", "gpt-5.4"); caps != (ModelCapabilities{}) {
+		t.Errorf("unregistered pair = %+v, want zero", caps)
+	}
+}
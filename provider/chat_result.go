@@ -10,15 +10,18 @@ import (
 type DeltaType int
 
 const (
-	DeltaText     DeltaType = iota // Text content delta
-	DeltaToolCall                  // First tool call detected
+	DeltaText         DeltaType = iota // Text content delta
+	DeltaToolCall                      // First tool call detected
+	DeltaToolCallArgs                  // Tool-call argument fragment
 )
 
 // StreamDelta is a single unit emitted by a streaming provider.
 type StreamDelta struct {
-	Type     DeltaType
-	Text     string // DeltaText: the text chunk
-	ToolName string // DeltaToolCall: name of the first tool
+	Type       DeltaType
+	Text       string // DeltaText: the text chunk; DeltaToolCallArgs: the argument fragment
+	ToolName   string // DeltaToolCall: name of the first tool
+	ToolIndex  int    // DeltaToolCallArgs: position of the call within the response
+	ToolCallID string // DeltaToolCallArgs: call ID, when the provider has sent it
 }
 
 // ChatResult is returned by Provider.Chat(). All providers return at least this.
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = time.Minute
+)
+
+// ErrCircuitOpen is returned (wrapped) when a provider call is rejected
+// without being attempted because the provider's breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Breaker states.
+const (
+	BreakerClosed   = "closed"    // calls pass through
+	BreakerOpen     = "open"      // calls fail fast until the cooldown ends
+	BreakerHalfOpen = "half-open" // one probe call is in flight
+)
+
+// CircuitBreaker stops calls to a provider after consecutive failures. Once
+// threshold failures occur in a row it opens and rejects calls for cooldown,
+// then lets a single probe through: success closes it, failure reopens it.
+type CircuitBreaker struct {
+	name string
+	now  func() time.Time
+
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+	lastErr   string
+}
+
+// NewCircuitBreaker creates a closed breaker. Non-positive threshold or
+// cooldown fall back to the defaults (5 failures, 1 minute).
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	cb := &CircuitBreaker{name: name, now: time.Now, state: BreakerClosed}
+	cb.SetLimits(threshold, cooldown)
+	return cb
+}
+
+// SetLimits updates the threshold and cooldown (hot reload). Non-positive
+// values fall back to the defaults.
+func (cb *CircuitBreaker) SetLimits(threshold int, cooldown time.Duration) {
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	cb.mu.Lock()
+	cb.threshold, cb.cooldown = threshold, cooldown
+	cb.mu.Unlock()
+}
+
+// Allow reports whether a call may proceed. It returns an error wrapping
+// ErrCircuitOpen while the breaker is open or a half-open probe is running.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case BreakerOpen:
+		retryAt := cb.openedAt.Add(cb.cooldown)
+		if cb.now().Before(retryAt) {
+			return fmt.Errorf("%w: provider %s failed %d times in a row (last error: %s); retrying after %s",
+				ErrCircuitOpen, cb.name, cb.failures, cb.lastErr, retryAt.Format(time.TimeOnly))
+		}
+		cb.state = BreakerHalfOpen
+		logger.Info("circuit breaker half-open, probing provider", "provider", cb.name)
+		return nil
+	case BreakerHalfOpen:
+		return fmt.Errorf("%w: provider %s is being probed after repeated failures", ErrCircuitOpen, cb.name)
+	}
+	return nil
+}
+
+// Record updates the breaker with the outcome of an allowed call. Context
+// cancellation and deadlines are the caller giving up, not a provider
+// failure, and are ignored (an abandoned half-open probe lets the next call
+// probe instead). Only transport errors, 5xx and 429 count as failures (see
+// isProviderFailure); any other API error means the provider answered and
+// counts as a success.
+func (cb *CircuitBreaker) Record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		if cb.state == BreakerHalfOpen {
+			cb.state = BreakerOpen
+			cb.openedAt = cb.now().Add(-cb.cooldown)
+		}
+		return
+	}
+	if !isProviderFailure(err) {
+		if cb.state != BreakerClosed {
+			logger.Info("circuit breaker closed, provider recovered", "provider", cb.name)
+		}
+		cb.state, cb.failures, cb.lastErr = BreakerClosed, 0, ""
+		return
+	}
+	cb.failures++
+	cb.lastErr = err.Error()
+	if cb.state == BreakerHalfOpen || cb.failures >= cb.threshold {
+		if cb.state != BreakerOpen {
+			logger.Warn("circuit breaker opened", "provider", cb.name,
+				"failures", cb.failures, "cooldown", cb.cooldown, "err", err)
+		}
+		cb.state = BreakerOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// BreakerStatus is a point-in-time view of a breaker for health output.
+type BreakerStatus struct {
+	Provider            string
+	State               string
+	ConsecutiveFailures int
+	RetryAt             time.Time // zero unless open
+	LastError           string
+}
+
+// Status returns the breaker's current state.
+func (cb *CircuitBreaker) Status() BreakerStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	s := BreakerStatus{
+		Provider:            cb.name,
+		State:               cb.state,
+		ConsecutiveFailures: cb.failures,
+		LastError:           cb.lastErr,
+	}
+	if cb.state == BreakerOpen {
+		s.RetryAt = cb.openedAt.Add(cb.cooldown)
+	}
+	return s
+}
+
+// WithCircuitBreaker wraps p so every call goes through cb. Errors returned
+// by Chat and by the result's Wait both count as failures.
+func WithCircuitBreaker(p Provider, cb *CircuitBreaker) Provider {
+	if p == nil || cb == nil {
+		return p
+	}
+	return &breakerProvider{inner: p, cb: cb}
+}
+
+type breakerProvider struct {
+	inner Provider
+	cb    *CircuitBreaker
+}
+
+func (p *breakerProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
+	if err := p.cb.Allow(); err != nil {
+		return nil, err
+	}
+	result, err := p.inner.Chat(ctx, req)
+	if err != nil {
+		p.cb.Record(err)
+		return nil, err
+	}
+	if stream, ok := result.(StreamChatResult); ok {
+		return &breakerStreamResult{StreamChatResult: stream, cb: p.cb}, nil
+	}
+	return &breakerResult{ChatResult: result, cb: p.cb}, nil
+}
+
+// breakerResult records the outcome once the response completes.
+type breakerResult struct {
+	ChatResult
+	cb   *CircuitBreaker
+	once sync.Once
+}
+
+func (r *breakerResult) Wait() (*Response, error) {
+	resp, err := r.ChatResult.Wait()
+	r.once.Do(func() { r.cb.Record(err) })
+	return resp, err
+}
+
+type breakerStreamResult struct {
+	StreamChatResult
+	cb   *CircuitBreaker
+	once sync.Once
+}
+
+func (r *breakerStreamResult) Wait() (*Response, error) {
+	resp, err := r.StreamChatResult.Wait()
+	r.once.Do(func() { r.cb.Record(err) })
+	return resp, err
+}
+
+// breakerSet holds one breaker per provider name, shared by every provider
+// instance the factory creates so failures accumulate across turns.
+type breakerSet struct {
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+func (s *breakerSet) get(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cb, ok := s.breakers[name]
+	if !ok {
+		if s.breakers == nil {
+			s.breakers = make(map[string]*CircuitBreaker)
+		}
+		cb = NewCircuitBreaker(name, threshold, cooldown)
+		s.breakers[name] = cb
+		return cb
+	}
+	cb.SetLimits(threshold, cooldown)
+	return cb
+}
+
+func (s *breakerSet) statuses() []BreakerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]BreakerStatus, 0, len(s.breakers))
+	for _, cb := range s.breakers {
+		out = append(out, cb.Status())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type flakyProvider struct {
+	calls int
+	err   error
+}
+
+func (p *flakyProvider) Chat(context.Context, *Request) (ChatResult, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return NewBasicResult(&Response{Content: "ok"}), nil
+}
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cb := NewCircuitBreaker("deepseek", 3, time.Minute)
+	cb.now = func() time.Time { return now }
+	inner := &flakyProvider{err: errors.New("503 service unavailable")}
+	p := WithCircuitBreaker(inner, cb)
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.Chat(context.Background(), &Request{}); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: err = %v, want the provider error", i+1, err)
+		}
+	}
+	if inner.calls != 3 {
+		t.Fatalf("inner calls = %d, want 3", inner.calls)
+	}
+
+	// Open: fail fast without touching the provider.
+	_, err := p.Chat(context.Background(), &Request{})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if inner.calls != 3 {
+		t.Fatalf("open breaker called the provider (calls = %d)", inner.calls)
+	}
+	if s := cb.Status(); s.State != BreakerOpen || s.ConsecutiveFailures != 3 {
+		t.Errorf("status = %+v", s)
+	}
+
+	// After the cooldown a single probe goes through; success closes it.
+	now = now.Add(time.Minute + time.Second)
+	inner.err = nil
+	result, err := p.Chat(context.Background(), &Request{})
+	if err != nil {
+		t.Fatalf("probe err = %v", err)
+	}
+	if _, err := result.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 4 || cb.Status().State != BreakerClosed {
+		t.Errorf("after probe: calls = %d, state = %s", inner.calls, cb.Status().State)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cb := NewCircuitBreaker("deepseek", 1, time.Minute)
+	cb.now = func() time.Time { return now }
+	p := WithCircuitBreaker(&flakyProvider{err: errors.New("timeout")}, cb)
+
+	_, _ = p.Chat(context.Background(), &Request{})
+	now = now.Add(2 * time.Minute)
+	if _, err := p.Chat(context.Background(), &Request{}); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe should reach the provider, got %v", err)
+	}
+	if _, err := p.Chat(context.Background(), &Request{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("failed probe should reopen the breaker, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresRequestErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound} {
+		cb := NewCircuitBreaker("openai", 2, time.Minute)
+		bad := fmt.Errorf("request failed: %w", NewAPIError("openai", status, "", "rejected"))
+		for i := 0; i < 3; i++ {
+			cb.Record(bad)
+		}
+		if s := cb.Status(); s.State != BreakerClosed || s.ConsecutiveFailures != 0 {
+			t.Errorf("after %ds: status = %+v, want closed with no failures", status, s)
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresDeadlines(t *testing.T) {
+	cb := NewCircuitBreaker("openai", 2, time.Minute)
+	for i := 0; i < 3; i++ {
+		cb.Record(fmt.Errorf("turn timed out: %w", context.DeadlineExceeded))
+	}
+	if s := cb.Status(); s.State != BreakerClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("after deadlines: status = %+v, want closed with no failures", s)
+	}
+}
+
+func TestCircuitBreakerCountsRateLimitsAndServerErrors(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		cb := NewCircuitBreaker("openai", 2, time.Minute)
+		for i := 0; i < 2; i++ {
+			cb.Record(NewAPIError("openai", status, "", "unavailable"))
+		}
+		if s := cb.Status(); s.State != BreakerOpen {
+			t.Errorf("after two %ds: state = %s, want open", status, s.State)
+		}
+	}
+}
@@ -18,7 +18,9 @@ const deepSeekAPIBase = "https://api.deepseek.com"
 
 func init() {
 	RegisterProvider("deepseek", ProviderRegistration{
-		Models: []string{"deepseek-v4-pro", "deepseek-v4-flash"},
+		Models:          []string{"deepseek-v4-pro", "deepseek-v4-flash"},
+		ReasoningModels: []string{"deepseek-v4-pro", "deepseek-v4-flash"}, // always thinking; see Chat
+		JSONModeModels:  []string{"deepseek-v4-pro", "deepseek-v4-flash"},
 		ContextWindows: map[string]int{
 			"deepseek-v4-pro":   1000000,
 			"deepseek-v4-flash": 1000000,
@@ -143,6 +145,7 @@ type DeepSeekProvider struct {
 	maxTokens   int
 	temperature float64
 	client      *http.Client
+	maxRetries  int // retries of doPost on network errors and 429/5xx; 0 unless providers.<name>.sdkMaxRetries is set
 }
 
 func newDeepSeekProvider(apiKey, apiBase, modelType, modelName string, maxTokens int, temperature float64) *DeepSeekProvider {
@@ -167,6 +170,17 @@ func newDeepSeekProvider(apiKey, apiBase, modelType, modelName string, maxTokens
 	}
 }
 
+// SetMaxRetries sets how often a failed request is resent before Chat gives
+// up, matching the SDK clients' transport-level retries.
+func (p *DeepSeekProvider) SetMaxRetries(n int) {
+	p.maxRetries = n
+}
+
+// SetTransport routes the provider's requests through rt.
+func (p *DeepSeekProvider) SetTransport(rt http.RoundTripper) {
+	p.client.Transport = rt
+}
+
 func (p *DeepSeekProvider) endpoint() string {
 	return p.apiBase + "/chat/completions"
 }
@@ -201,8 +215,8 @@ func (p *DeepSeekProvider) buildRequest(req *Request, thinkingEnabled, streaming
 		Tools:    req.Tools,
 		Stream:   streaming,
 	}
-	if p.maxTokens > 0 {
-		r.MaxTokens = p.maxTokens
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		r.MaxTokens = maxTokens
 	}
 	if p.temperature != 0 && !thinkingEnabled {
 		t := p.temperature
@@ -222,14 +236,15 @@ func (p *DeepSeekProvider) doPost(ctx context.Context, body any) (*http.Response
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(), bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
-
-	return p.client.Do(httpReq)
+	return doWithRetry(ctx, "deepseek", p.maxRetries, func() (*http.Response, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(), bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+		return p.client.Do(httpReq)
+	})
 }
 
 // chatSync handles non-streaming completion.
@@ -249,9 +264,9 @@ func (p *DeepSeekProvider) chatSync(ctx context.Context, dsReq dsRequest, start
 	if httpResp.StatusCode != http.StatusOK {
 		var apiErr dsErrorResp
 		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
-			return nil, fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, apiErr.Error.Message)
+			return nil, ClassifyContextLength("deepseek", fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, apiErr.Error.Message))
 		}
-		return nil, fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, string(body))
+		return nil, ClassifyContextLength("deepseek", fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, string(body)))
 	}
 
 	var resp dsResponse
@@ -292,6 +307,7 @@ func (p *DeepSeekProvider) chatSync(ctx context.Context, dsReq dsRequest, start
 		Content:          finalContent,
 		ReasoningContent: reasoningText,
 		ToolCalls:        choice.Message.ToolCalls,
+		FinishReason:     choice.FinishReason,
 		Usage: Usage{
 			PromptTokens:     u.PromptTokens,
 			CompletionTokens: u.CompletionTokens,
@@ -317,9 +333,9 @@ func (p *DeepSeekProvider) chatStream(ctx context.Context, dsReq dsRequest, star
 		body, _ := io.ReadAll(httpResp.Body)
 		var apiErr dsErrorResp
 		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
-			return nil, fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, apiErr.Error.Message)
+			return nil, ClassifyContextLength("deepseek", fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, apiErr.Error.Message))
 		}
-		return nil, fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, string(body))
+		return nil, ClassifyContextLength("deepseek", fmt.Errorf("deepseek API error (%d): %s", httpResp.StatusCode, string(body)))
 	}
 
 	resp := &Response{
@@ -405,6 +421,7 @@ func (p *DeepSeekProvider) chatStream(ctx context.Context, dsReq dsRequest, star
 					acc.name = tc.Function.Name
 				}
 				acc.args.WriteString(tc.Function.Arguments)
+				adapter.EmitToolCallArgs(tc.Index, acc.id, tc.Function.Arguments)
 			}
 
 			if chunk.Choices[0].FinishReason != nil {
@@ -461,6 +478,7 @@ func (p *DeepSeekProvider) chatStream(ctx context.Context, dsReq dsRequest, star
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = finishReason
 		resp.Usage = Usage{
 			PromptTokens:     usage.PromptTokens,
 			CompletionTokens: usage.CompletionTokens,
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+	"github.com/openai/openai-go/v3"
+)
+
+// APIError is an HTTP error returned by a provider's API. Wrappers (circuit
+// breaker, retries, fallbacks) inspect it with errors.As instead of matching
+// error strings.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Code       string // provider error code or type, if any (e.g. "rate_limit_exceeded")
+	Message    string // human-readable message from the provider, redacted
+	Retryable  bool   // 429 and 5xx: the same request may succeed later
+}
+
+func (e *APIError) Error() string {
+	code := ""
+	if e.Code != "" {
+		code = " " + e.Code
+	}
+	return fmt.Sprintf("%s API error (%d%s): %s", e.Provider, e.StatusCode, code, e.Message)
+}
+
+// NewAPIError builds an APIError, deriving Retryable from the status code.
+// The message is redacted so it is safe to log.
+func NewAPIError(providerName string, statusCode int, code, message string) *APIError {
+	return &APIError{
+		Provider:   providerName,
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    logger.Redact(message),
+		Retryable:  retryableStatus(statusCode),
+	}
+}
+
+// retryableStatus reports whether a request that failed with status may
+// succeed if sent again: rate limits and server-side errors.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// Backoff between retries of a failed request (see doWithRetry).
+const (
+	httpRetryBaseDelay = 500 * time.Millisecond
+	httpRetryMaxDelay  = 8 * time.Second
+)
+
+// doWithRetry calls send and resends up to maxRetries times, with exponential
+// backoff, on network errors and retryable statuses. Hand-rolled HTTP clients
+// use it for the transport-level retries the SDK clients do themselves.
+func doWithRetry(ctx context.Context, providerName string, maxRetries int, send func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := send()
+		if attempt >= maxRetries || ctx.Err() != nil || (err == nil && !retryableStatus(resp.StatusCode)) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		delay := min(httpRetryBaseDelay<<attempt, httpRetryMaxDelay)
+		logger.Warn("provider request failed, retrying", "provider", providerName, "attempt", attempt+1, "delay", delay, "err", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// isProviderFailure reports whether err means the provider itself is failing:
+// a transport error (no APIError), a 5xx or a 429. Other API errors (bad
+// request, auth, unknown model, context overflow) come from a provider that
+// answered, and context cancellation or deadlines are the caller giving up.
+func isProviderFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsContextLengthError(err) {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+}
+
+// IsRetryable reports whether err wraps a retryable APIError. Errors that are
+// not APIErrors (network failures, stream resets) are treated as retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable
+	}
+	return true
+}
+
+// apiErrorFromBody builds an APIError from a non-2xx response body, pulling
+// message and code from the common {"error": {...}} envelope when present.
+func apiErrorFromBody(providerName string, statusCode int, body []byte) *APIError {
+	var envelope struct {
+		Error struct {
+			Message string `json:"message"`
+			Code    any    `json:"code"`
+			Type    string `json:"type"`
+		} `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	code := ""
+	if json.Unmarshal(body, &envelope) == nil && envelope.Error.Message != "" {
+		message = envelope.Error.Message
+		code = errorCode(envelope.Error.Code, envelope.Error.Type)
+	}
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return NewAPIError(providerName, statusCode, code, message)
+}
+
+// errorCode picks the provider's error code, which some APIs send as a
+// number, falling back to the error type.
+func errorCode(code any, typ string) string {
+	switch c := code.(type) {
+	case string:
+		if c != "" {
+			return c
+		}
+	case float64:
+		return fmt.Sprintf("%.0f", c)
+	}
+	return typ
+}
+
+// sdkAPIError converts an openai-go SDK error into an APIError. Other errors
+// are returned unchanged.
+func sdkAPIError(providerName string, err error) error {
+	var sdkErr *openai.Error
+	if !errors.As(err, &sdkErr) {
+		return err
+	}
+	message := sdkErr.Message
+	if message == "" {
+		message = strings.TrimSpace(sdkErr.RawJSON())
+	}
+	if message == "" {
+		message = http.StatusText(sdkErr.StatusCode)
+	}
+	return ClassifyContextLength(providerName,
+		NewAPIError(providerName, sdkErr.StatusCode, errorCode(sdkErr.Code, sdkErr.Type), message))
+}
+
+// ContextLengthError reports that the provider rejected a request because the
+// prompt does not fit the model's context window. Sending the same request
+// again cannot succeed; the history has to shrink first.
+type ContextLengthError struct {
+	Provider string
+	Err      error // the underlying provider error
+}
+
+func (e *ContextLengthError) Error() string { return e.Err.Error() }
+
+func (e *ContextLengthError) Unwrap() error { return e.Err }
+
+// IsContextLengthError reports whether err wraps a ContextLengthError.
+func IsContextLengthError(err error) bool {
+	var clErr *ContextLengthError
+	return errors.As(err, &clErr)
+}
+
+// contextLengthMarkers are lower-cased fragments of the context overflow
+// messages providers send; none of them return a dedicated status code.
+var contextLengthMarkers = []string{
+	"context_length_exceeded",              // openai, openrouter, deepseek
+	"maximum context length",               // openai-compatible APIs
+	"prompt is too long",                   // anthropic
+	"exceeds the maximum number of tokens", // gemini
+	"exceeded model token limit",           // moonshot
+	"context window exceeds limit",         // minimax
+	"reduce the length of the messages",    // openai-compatible APIs
+	"input length exceeds",                 // zhipu, siliconflow
+}
+
+// ClassifyContextLength wraps err in a ContextLengthError when it is a context
+// overflow reported by providerName. Other errors, including retryable ones
+// (rate limits also mention tokens), are returned unchanged.
+func ClassifyContextLength(providerName string, err error) error {
+	if err == nil || IsContextLengthError(err) {
+		return err
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Retryable {
+		return err
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range contextLengthMarkers {
+		if strings.Contains(msg, marker) {
+			return &ContextLengthError{Provider: providerName, Err: err}
+		}
+	}
+	return err
+}
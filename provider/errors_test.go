@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// openRouterErrorResult sends one chat request to a server that always
+// answers with status and body, and returns the error from Wait.
+func openRouterErrorResult(t *testing.T, status int, body string) error {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("retry-after-ms", "1") // keep SDK retries fast
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newOpenRouterProvider("sk-test", srv.URL, "test-model", "", 64, 0)
+	result, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("hi")}})
+	if err == nil {
+		_, err = result.Wait()
+	}
+	if err == nil {
+		t.Fatalf("status %d: expected an error", status)
+	}
+	return err
+}
+
+func TestAPIErrorRateLimitIsRetryable(t *testing.T) {
+	err := openRouterErrorResult(t, http.StatusTooManyRequests,
+		`{"error":{"message":"Rate limit exceeded","code":429}}`)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want an APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || !apiErr.Retryable {
+		t.Errorf("APIError = %+v, want retryable 429", apiErr)
+	}
+	if apiErr.Provider != "openrouter" || apiErr.Message != "Rate limit exceeded" {
+		t.Errorf("APIError = %+v, want provider and message preserved", apiErr)
+	}
+	if !IsRetryable(err) {
+		t.Error("IsRetryable = false for a 429")
+	}
+}
+
+func TestAPIErrorUnauthorizedIsNotRetryable(t *testing.T) {
+	err := openRouterErrorResult(t, http.StatusUnauthorized,
+		`{"error":{"message":"No auth credentials found","code":401}}`)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want an APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Retryable {
+		t.Errorf("APIError = %+v, want non-retryable 401", apiErr)
+	}
+	if IsRetryable(err) {
+		t.Error("IsRetryable = true for a 401")
+	}
+}
+
+func TestAPIErrorFromBody(t *testing.T) {
+	e := apiErrorFromBody("openai", http.StatusServiceUnavailable,
+		[]byte(`{"error":{"message":"overloaded","type":"server_error"}}`))
+	if e.Message != "overloaded" || e.Code != "server_error" || !e.Retryable {
+		t.Errorf("APIError = %+v", e)
+	}
+	e = apiErrorFromBody("openai", http.StatusBadRequest, []byte("plain text"))
+	if e.Message != "plain text" || e.Retryable {
+		t.Errorf("APIError = %+v", e)
+	}
+}
+
+func TestContextLengthErrorClassified(t *testing.T) {
+	err := openRouterErrorResult(t, http.StatusBadRequest,
+		`{"error":{"message":"This endpoint's maximum context length is 131072 tokens. However, you requested about 140000 tokens. Please reduce the length of either one.","code":400}}`)
+	var clErr *ContextLengthError
+	if !errors.As(err, &clErr) || clErr.Provider != "openrouter" {
+		t.Fatalf("err = %v, want a ContextLengthError from openrouter", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("underlying APIError lost: %v", err)
+	}
+	if IsRetryable(err) || isProviderFailure(err) {
+		t.Error("a context overflow should be neither retryable nor a provider failure")
+	}
+
+	// Rate limits mention tokens too but must stay retryable.
+	err = openRouterErrorResult(t, http.StatusTooManyRequests,
+		`{"error":{"message":"Rate limit exceeded: maximum context length tokens per minute","code":429}}`)
+	if IsContextLengthError(err) {
+		t.Errorf("429 classified as a context overflow: %v", err)
+	}
+	if IsContextLengthError(ClassifyContextLength("gemini", errors.New("gemini API error (400): invalid argument"))) {
+		t.Error("unrelated 400 classified as a context overflow")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/logger"
 )
 
 const (
@@ -19,11 +20,13 @@ const (
 // Factory creates provider instances for the requested provider/model.
 type Factory struct {
 	cfgFn            func() *config.Config // returns latest config (re-reads from disk)
+	mu               sync.RWMutex          // guards the fallback fields below; Reload swaps them
 	fallbackCfg      *config.Config        // startup config used as fallback
 	defaultProv      string                // startup default (fallback only)
 	defaultModel     string                // startup default (fallback only)
 	maxTokens        int
 	temperature      float64
+	breakers         breakerSet // per-provider circuit breakers, shared across created instances
 }
 
 // NewFactory builds a provider factory. cfgFn is called on each Create() to
@@ -34,34 +37,99 @@ func NewFactory(cfgFn func() *config.Config) (*Factory, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config is nil")
 	}
+	f := &Factory{cfgFn: cfgFn}
+	if err := f.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Reload replaces the fallback config, default provider/model, and sampling
+// defaults with cfg (e.g. on SIGHUP). Circuit breaker state is kept. On a
+// validation error the previous values stay in place.
+func (f *Factory) Reload(cfg *config.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
 
 	defaultProv := strings.TrimSpace(cfg.GetProvider())
 	if defaultProv == "" {
-		return nil, fmt.Errorf("default provider is required")
+		return fmt.Errorf("default provider is required")
 	}
 
 	defaultModel := strings.TrimSpace(cfg.GetModelType())
 	if defaultModel == "" {
-		return nil, fmt.Errorf("default model type is required")
+		return fmt.Errorf("default model type is required")
 	}
 
 	if err := ValidateProviderModelType(defaultProv, defaultModel); err != nil {
-		return nil, err
+		return err
 	}
 
-	return &Factory{
-		cfgFn:       cfgFn,
-		fallbackCfg: cfg,
-		defaultProv: defaultProv,
-		defaultModel: defaultModel,
-		maxTokens:   cfg.GetMaxTokens(),
-		temperature: cfg.GetTemperature(),
-	}, nil
+	f.mu.Lock()
+	f.fallbackCfg = cfg
+	f.defaultProv = defaultProv
+	f.defaultModel = defaultModel
+	f.maxTokens = cfg.GetMaxTokens()
+	f.temperature = cfg.GetTemperature()
+	f.mu.Unlock()
+	return nil
+}
+
+// Sampling overrides the config-wide sampling parameters for one provider
+// instance. Zero values keep the thread defaults.
+type Sampling struct {
+	MaxTokens       int      // 0 = thread default
+	Temperature     *float64 // nil = thread default
+	ReasoningEffort string   // "" = provider config, then the provider default
 }
 
 // Create builds a provider instance for provider/model. Empty values fall back
 // to the latest default from config (hot-reloaded from disk).
 func (f *Factory) Create(providerName, modelType string) (Provider, error) {
+	return f.CreateWithSampling(providerName, modelType, Sampling{})
+}
+
+// CreateGuarded is CreateWithSampling with the result wrapped in the resolved
+// provider's circuit breaker, so repeated failures across turns fail fast
+// instead of hammering a provider that is down.
+func (f *Factory) CreateGuarded(providerName, modelType string, sampling Sampling) (Provider, error) {
+	p, err := f.CreateWithSampling(providerName, modelType, sampling)
+	if err != nil {
+		return nil, err
+	}
+	cfg := f.latestConfig()
+	name, _, err := f.resolveProviderModel(cfg, providerName, modelType)
+	if err != nil {
+		return nil, err
+	}
+	return WithCircuitBreaker(p, f.breakers.get(name, cfg.GetCircuitBreakerThreshold(), cfg.GetCircuitBreakerCooldown())), nil
+}
+
+// BreakerStatuses returns the state of every provider breaker created so far,
+// sorted by provider name.
+func (f *Factory) BreakerStatuses() []BreakerStatus {
+	if f == nil {
+		return nil
+	}
+	return f.breakers.statuses()
+}
+
+// CreateForRoute builds the provider mapped to route in thread.models (e.g.
+// "summary"), falling back to the default provider/model when unmapped.
+func (f *Factory) CreateForRoute(route string) (Provider, error) {
+	if f == nil {
+		return nil, fmt.Errorf("provider factory is nil")
+	}
+	if mc := f.latestConfig().Thread.Models[route]; mc != nil {
+		return f.Create(mc.Provider, mc.ModelType)
+	}
+	return f.Create("", "")
+}
+
+// CreateWithSampling is Create with per-call sampling overrides (e.g. from an
+// agent template's frontmatter).
+func (f *Factory) CreateWithSampling(providerName, modelType string, sampling Sampling) (Provider, error) {
 	if f == nil {
 		return nil, fmt.Errorf("provider factory is nil")
 	}
@@ -98,7 +166,34 @@ func (f *Factory) Create(providerName, modelType string) (Provider, error) {
 	}
 
 	apiBase := providerAPIBase(cfg, providerName)
-	p := reg.Constructor(apiKey, apiBase, modelType, modelName, f.maxTokens, f.temperature)
+	f.mu.RLock()
+	maxTokens, temperature := f.maxTokens, f.temperature
+	f.mu.RUnlock()
+	if sampling.MaxTokens > 0 {
+		maxTokens = sampling.MaxTokens
+	}
+	if sampling.Temperature != nil {
+		temperature = *sampling.Temperature
+	}
+	p := reg.Constructor(apiKey, apiBase, modelType, modelName, maxTokens, temperature)
+
+	if pc := providerConfigFor(cfg, providerName); pc != nil && pc.SDKMaxRetries != nil {
+		if setter, ok := p.(MaxRetriesSetter); ok {
+			setter.SetMaxRetries(max(*pc.SDKMaxRetries, 0))
+		}
+	}
+
+	effort := strings.TrimSpace(sampling.ReasoningEffort)
+	if effort == "" {
+		if pc := providerConfigFor(cfg, providerName); pc != nil {
+			effort = strings.TrimSpace(pc.ReasoningEffort)
+		}
+	}
+	if effort != "" {
+		if setter, ok := p.(ReasoningEffortSetter); ok {
+			setter.SetReasoningEffort(effort)
+		}
+	}
 
 	// Set account ID only for OAuth-based provider.
 	if providerName == "openai-oauth" {
@@ -107,6 +202,21 @@ func (f *Factory) Create(providerName, modelType string) (Provider, error) {
 				setter.SetAccountID(token.AccountID)
 			}
 		}
+		if setter, ok := p.(TokenSourceSetter); ok {
+			setter.SetTokenSource(func(rejected string) string {
+				c := f.latestConfig()
+				if rejected != "" {
+					return forceRefreshOAuthToken(c, providerName, rejected)
+				}
+				return oauthAccessToken(c, providerName)
+			})
+		}
+	}
+
+	if dir, err := cfg.TranscriptDir(); err != nil {
+		logger.Warn("failed to resolve transcript dir, transcript disabled", "err", err)
+	} else if dir != "" && !EnableTranscript(p, dir, providerName+"/"+modelName) {
+		logger.Warn("provider does not support transcripts", "provider", providerName)
 	}
 
 	return p, nil
@@ -116,7 +226,9 @@ func (f *Factory) Create(providerName, modelType string) (Provider, error) {
 func (f *Factory) latestConfig() *config.Config {
 	cfg := f.cfgFn()
 	if cfg == nil {
+		f.mu.RLock()
 		cfg = f.fallbackCfg
+		f.mu.RUnlock()
 	}
 	return cfg
 }
@@ -124,11 +236,15 @@ func (f *Factory) latestConfig() *config.Config {
 // resolveProviderModel resolves provider name and model type using precedence:
 // explicit args → latest config → startup defaults.
 func (f *Factory) resolveProviderModel(cfg *config.Config, providerName, modelType string) (string, string, error) {
+	f.mu.RLock()
+	defaultProv, defaultModel := f.defaultProv, f.defaultModel
+	f.mu.RUnlock()
+
 	providerName = strings.TrimSpace(providerName)
 	if providerName == "" {
 		providerName = strings.TrimSpace(cfg.GetProvider())
 		if providerName == "" {
-			providerName = f.defaultProv
+			providerName = defaultProv
 		}
 	}
 
@@ -138,8 +254,8 @@ func (f *Factory) resolveProviderModel(cfg *config.Config, providerName, modelTy
 			modelType = strings.TrimSpace(cfg.GetModelType())
 		}
 		if modelType == "" {
-			if providerName == f.defaultProv {
-				modelType = f.defaultModel
+			if providerName == defaultProv {
+				modelType = defaultModel
 			} else {
 				models := SupportedModelsForProvider(providerName)
 				if len(models) == 0 {
@@ -213,6 +329,22 @@ func oauthAccessToken(cfg *config.Config, providerName string) string {
 	return token.AccessToken
 }
 
+// forceRefreshOAuthToken refreshes the token after the server rejected it
+// (e.g. with a 401) even though it had not expired locally. If another caller
+// already replaced the rejected token, the newer one is returned as is.
+func forceRefreshOAuthToken(cfg *config.Config, providerName, rejected string) string {
+	oauthRefreshMu.Lock()
+	defer oauthRefreshMu.Unlock()
+	token := cfg.GetOAuthToken(providerName)
+	if token == nil || token.RefreshToken == "" {
+		return ""
+	}
+	if token.AccessToken != "" && token.AccessToken != rejected {
+		return token.AccessToken
+	}
+	return oauthRefresher(cfg, providerName)
+}
+
 // oauthRefreshMu protects concurrent access to the refresh flow.
 var oauthRefreshMu sync.Mutex
 
@@ -265,3 +397,30 @@ func ProviderAPIKeyForPreview(cfg *config.Config, providerName string) string {
 func ProviderAPIBaseForPreview(cfg *config.Config, providerName string) string {
 	return providerAPIBase(cfg, providerName)
 }
+
+// RouteSpecialty resolves an agent specialty to a concrete provider/model:
+// an explicit thread.models entry first, then an implicit "provider/model"
+// specialty, then a bare supported model name (provider from providerHint or
+// the model registry). Returns nil when the specialty falls back to the
+// default model.
+func RouteSpecialty(models map[string]*config.ModelConfig, specialty, providerHint string) *config.ModelConfig {
+	if specialty == "" {
+		return nil
+	}
+	if mc, ok := models[specialty]; ok && mc != nil {
+		return mc
+	}
+	if prov, model, ok := strings.Cut(specialty, "/"); ok && IsSupportedModel(model) {
+		return &config.ModelConfig{Provider: prov, ModelType: model}
+	}
+	if IsSupportedModel(specialty) {
+		prov := providerHint
+		if prov == "" {
+			prov = ProviderForModel(specialty)
+		}
+		if prov != "" {
+			return &config.ModelConfig{Provider: prov, ModelType: specialty}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+func TestFactoryCreateWithSamplingTemperature(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+	cfg := &config.Config{}
+	cfg.Thread.Provider = "deepseek"
+	cfg.Thread.ModelType = "deepseek-v4-flash"
+	cfg.Thread.Temperature = 1.0
+	cfg.Thread.MaxTokens = 8192
+
+	f, err := NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		t.Fatalf("NewFactory: %v", err)
+	}
+
+	temp := 0.2
+	p, err := f.CreateWithSampling("", "", Sampling{Temperature: &temp, MaxTokens: 2048})
+	if err != nil {
+		t.Fatalf("CreateWithSampling: %v", err)
+	}
+	ds, ok := p.(*DeepSeekProvider)
+	if !ok {
+		t.Fatalf("provider type = %T, want *DeepSeekProvider", p)
+	}
+	req := ds.buildRequest(&Request{Messages: []Message{UserMessage("hi")}}, false, false)
+	if req.Temperature == nil || *req.Temperature != 0.2 {
+		t.Errorf("request temperature = %v, want 0.2", req.Temperature)
+	}
+	if req.MaxTokens != 2048 {
+		t.Errorf("request max_tokens = %d, want 2048", req.MaxTokens)
+	}
+
+	// No overrides: thread defaults apply.
+	p, err = f.Create("", "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	req = p.(*DeepSeekProvider).buildRequest(&Request{Messages: []Message{UserMessage("hi")}}, false, false)
+	if req.Temperature == nil || *req.Temperature != 1.0 {
+		t.Errorf("default temperature = %v, want 1.0", req.Temperature)
+	}
+	if req.MaxTokens != 8192 {
+		t.Errorf("default max_tokens = %d, want 8192", req.MaxTokens)
+	}
+}
+
+func TestFactoryAppliesSDKMaxRetries(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Retry-After-Ms", "1")
+		http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		retries  *int
+		wantHits int32
+	}{
+		{nil, 1 + sdkMaxRetries},
+		{intPtr(0), 1},
+		{intPtr(4), 5},
+	} {
+		hits.Store(0)
+		cfg := &config.Config{}
+		cfg.Thread.Provider = "zhipu-cn"
+		cfg.Thread.ModelType = "glm-5"
+		cfg.Providers.ZhipuCN = &config.ProviderConfig{APIKey: "test-key", APIBase: srv.URL, SDKMaxRetries: tc.retries}
+		f, err := NewFactory(func() *config.Config { return cfg })
+		if err != nil {
+			t.Fatalf("NewFactory: %v", err)
+		}
+		p, err := f.Create("", "")
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if res, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("hi")}}); err == nil {
+			res.Wait()
+		}
+		if got := hits.Load(); got != tc.wantHits {
+			t.Errorf("sdkMaxRetries=%v: server hit %d times, want %d", tc.retries, got, tc.wantHits)
+		}
+	}
+}
+
+func TestOpenAIProviderRetriesConfiguredTimes(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	p := newOpenAIProvider("test-key", srv.URL, "gpt-5.4", "", 0, 0)
+	if _, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("hi")}}); err == nil {
+		t.Fatal("Chat succeeded against a failing server")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("default: server hit %d times, want 1", got)
+	}
+
+	hits.Store(0)
+	p.SetMaxRetries(1)
+	if _, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("hi")}}); err == nil {
+		t.Fatal("Chat succeeded against a failing server")
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("maxRetries=1: server hit %d times, want 2", got)
+	}
+}
+
+func TestHandRolledProvidersRetryConfiguredTimes(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	for name, p := range map[string]Provider{
+		"deepseek": newDeepSeekProvider("test-key", srv.URL, "deepseek-v4-flash", "", 0, 0),
+		"gemini":   newGeminiProvider("test-key", srv.URL, "gemini-3-flash-preview", "", 0, 0),
+		"mimo":     newMiMoProvider("test-key", srv.URL, "mimo-v2-flash", "", 0, 0),
+	} {
+		setter, ok := p.(MaxRetriesSetter)
+		if !ok {
+			t.Errorf("%s: does not implement MaxRetriesSetter", name)
+			continue
+		}
+		for _, retries := range []int{0, 1} {
+			hits.Store(0)
+			setter.SetMaxRetries(retries)
+			if res, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("hi")}}); err == nil {
+				res.Wait()
+			}
+			if got := hits.Load(); got != int32(retries+1) {
+				t.Errorf("%s maxRetries=%d: server hit %d times, want %d", name, retries, got, retries+1)
+			}
+		}
+	}
+}
+
+func intPtr(n int) *int { return &n }
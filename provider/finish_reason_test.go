@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// openRouterStreamResponse serves chunks as one SSE chat completion stream
+// and returns the assembled response.
+func openRouterStreamResponse(t *testing.T, chunks ...string) *Response {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newOpenRouterProvider("sk-test", srv.URL, "test-model", "", 64, 0)
+	result, err := p.Chat(context.Background(), &Request{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	resp, err := result.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	return resp
+}
+
+func streamChunk(delta, finishReason string) string {
+	fr := "null"
+	if finishReason != "" {
+		fr = `"` + finishReason + `"`
+	}
+	return fmt.Sprintf(`{"id":"gen-1","object":"chat.completion.chunk","created":1,"model":"test-model","choices":[{"index":0,"delta":%s,"finish_reason":%s}]}`, delta, fr)
+}
+
+func TestOpenRouterFinishReasonStop(t *testing.T) {
+	resp := openRouterStreamResponse(t,
+		streamChunk(`{"role":"assistant","content":"Hello"}`, ""),
+		streamChunk(`{}`, "stop"),
+	)
+	if resp.FinishReason != "stop" {
+		t.Errorf("FinishReason = %q, want %q", resp.FinishReason, "stop")
+	}
+	if resp.Truncated() {
+		t.Error("a stopped response should not be truncated")
+	}
+}
+
+func TestOpenRouterFinishReasonToolCalls(t *testing.T) {
+	resp := openRouterStreamResponse(t,
+		streamChunk(`{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"exec","arguments":"{}"}}]}`, ""),
+		streamChunk(`{}`, "tool_calls"),
+	)
+	if resp.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q, want %q", resp.FinishReason, "tool_calls")
+	}
+	if !resp.HasToolCalls() {
+		t.Error("expected the tool call to be assembled")
+	}
+}
+
+func TestResponseTruncated(t *testing.T) {
+	for _, reason := range []string{"length", "max_tokens", "MAX_TOKENS"} {
+		if !(&Response{FinishReason: reason}).Truncated() {
+			t.Errorf("FinishReason %q should be truncated", reason)
+		}
+	}
+	for _, reason := range []string{"", "stop", "tool_calls", "end_turn"} {
+		if (&Response{FinishReason: reason}).Truncated() {
+			t.Errorf("FinishReason %q should not be truncated", reason)
+		}
+	}
+}
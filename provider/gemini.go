@@ -18,10 +18,12 @@ const geminiAPIBase = "https://generativelanguage.googleapis.com/v1beta"
 
 func init() {
 	RegisterProvider("gemini", ProviderRegistration{
-		Models:       []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
-		VisionModels: []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
-		AudioModels:  []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
-		PDFModels:    []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
+		Models:          []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
+		VisionModels:    []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
+		AudioModels:     []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
+		PDFModels:       []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
+		ReasoningModels: []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
+		JSONModeModels:  []string{"gemini-3-flash-preview", "gemini-3.1-flash-lite-preview"},
 		ContextWindows: map[string]int{
 			"gemini-3-flash-preview":       1048576,
 			"gemini-3.1-flash-lite-preview": 1048576,
@@ -129,6 +131,7 @@ type GeminiProvider struct {
 	maxTokens   int
 	temperature float64
 	client      *http.Client
+	maxRetries  int // retries of doPost on network errors and 429/5xx; 0 unless providers.<name>.sdkMaxRetries is set
 }
 
 func newGeminiProvider(apiKey, apiBase, modelType, modelName string, maxTokens int, temperature float64) *GeminiProvider {
@@ -151,6 +154,17 @@ func newGeminiProvider(apiKey, apiBase, modelType, modelName string, maxTokens i
 	}
 }
 
+// SetMaxRetries sets how often a failed request is resent before Chat gives
+// up, matching the SDK clients' transport-level retries.
+func (p *GeminiProvider) SetMaxRetries(n int) {
+	p.maxRetries = n
+}
+
+// SetTransport routes the provider's requests through rt.
+func (p *GeminiProvider) SetTransport(rt http.RoundTripper) {
+	p.client.Transport = rt
+}
+
 func (p *GeminiProvider) syncEndpoint() string {
 	return p.apiBase + "/models/" + p.modelName + ":generateContent"
 }
@@ -171,15 +185,16 @@ func (p *GeminiProvider) Chat(ctx context.Context, req *Request) (ChatResult, er
 	if err != nil {
 		return nil, fmt.Errorf("convert messages: %w", err)
 	}
-	gmReq := p.buildRequest(sysInstruction, contents, req.Tools)
+	gmReq := p.buildRequest(sysInstruction, contents, req)
 	return p.chatStream(ctx, gmReq, start)
 }
 
-func (p *GeminiProvider) buildRequest(sysInstruction *gmContent, contents []gmContent, tools []ToolDef) gmRequest {
+func (p *GeminiProvider) buildRequest(sysInstruction *gmContent, contents []gmContent, req *Request) gmRequest {
 	maxTokens := p.maxTokens
 	if maxTokens < 16384 {
 		maxTokens = 16384
 	}
+	maxTokens = req.outputCap(maxTokens)
 
 	r := gmRequest{
 		SystemInstruction: sysInstruction,
@@ -191,7 +206,7 @@ func (p *GeminiProvider) buildRequest(sysInstruction *gmContent, contents []gmCo
 				IncludeThoughts: true,
 			},
 		},
-		Tools: toGeminiTools(tools),
+		Tools: toGeminiTools(req.Tools),
 	}
 
 	temp := 1.0
@@ -205,13 +220,15 @@ func (p *GeminiProvider) doPost(ctx context.Context, url string, body any) (*htt
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-goog-api-key", p.apiKey)
-	return p.client.Do(httpReq)
+	return doWithRetry(ctx, "gemini", p.maxRetries, func() (*http.Response, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("x-goog-api-key", p.apiKey)
+		return p.client.Do(httpReq)
+	})
 }
 
 // chatSync handles non-streaming completion.
@@ -231,9 +248,9 @@ func (p *GeminiProvider) chatSync(ctx context.Context, gmReq gmRequest, start ti
 	if httpResp.StatusCode != http.StatusOK {
 		var apiResp gmResponse
 		if json.Unmarshal(body, &apiResp) == nil && apiResp.Error != nil {
-			return nil, fmt.Errorf("gemini API error (%d): %s", apiResp.Error.Code, apiResp.Error.Message)
+			return nil, ClassifyContextLength("gemini", fmt.Errorf("gemini API error (%d): %s", apiResp.Error.Code, apiResp.Error.Message))
 		}
-		return nil, fmt.Errorf("gemini API error (%d): %s", httpResp.StatusCode, string(body))
+		return nil, ClassifyContextLength("gemini", fmt.Errorf("gemini API error (%d): %s", httpResp.StatusCode, string(body)))
 	}
 
 	var resp gmResponse
@@ -257,9 +274,9 @@ func (p *GeminiProvider) chatStream(ctx context.Context, gmReq gmRequest, start
 		body, _ := io.ReadAll(httpResp.Body)
 		var apiResp gmResponse
 		if json.Unmarshal(body, &apiResp) == nil && apiResp.Error != nil {
-			return nil, fmt.Errorf("gemini API error (%d): %s", apiResp.Error.Code, apiResp.Error.Message)
+			return nil, ClassifyContextLength("gemini", fmt.Errorf("gemini API error (%d): %s", apiResp.Error.Code, apiResp.Error.Message))
 		}
-		return nil, fmt.Errorf("gemini API error (%d): %s", httpResp.StatusCode, string(body))
+		return nil, ClassifyContextLength("gemini", fmt.Errorf("gemini API error (%d): %s", httpResp.StatusCode, string(body)))
 	}
 
 	resp := &Response{ProviderLabel: "gemini", ModelLabel: p.modelName}
@@ -378,6 +395,7 @@ func (p *GeminiProvider) chatStream(ctx context.Context, gmReq gmRequest, start
 		resp.ReasoningContent = reasoningText
 		resp.ReasoningDetails = reasoningDetails
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = finishReason
 		resp.Usage = Usage{
 			PromptTokens:     usage.PromptTokenCount,
 			CompletionTokens: usage.CandidatesTokenCount,
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,7 +19,8 @@ const mimoAPIBase = "https://api.xiaomimimo.com/v1"
 
 func init() {
 	RegisterProvider("mimo", ProviderRegistration{
-		Models: []string{"mimo-v2.5-pro", "mimo-v2.5", "mimo-v2-pro", "mimo-v2-flash", "mimo-v2-omni"},
+		Models:          []string{"mimo-v2.5-pro", "mimo-v2.5", "mimo-v2-pro", "mimo-v2-flash", "mimo-v2-omni"},
+		ReasoningModels: mimoReasoningModels,
 		ContextWindows: map[string]int{
 			"mimo-v2.5-pro": 1048576,
 			"mimo-v2.5":     1048576,
@@ -122,6 +124,7 @@ type MiMoProvider struct {
 	maxTokens   int
 	temperature float64
 	client      *http.Client
+	maxRetries  int // retries of doPost on network errors and 429/5xx; 0 unless providers.<name>.sdkMaxRetries is set
 }
 
 func newMiMoProvider(apiKey, apiBase, modelType, modelName string, maxTokens int, temperature float64) *MiMoProvider {
@@ -147,15 +150,29 @@ func newMiMoProvider(apiKey, apiBase, modelType, modelName string, maxTokens int
 	}
 }
 
+// SetMaxRetries sets how often a failed request is resent before Chat gives
+// up, matching the SDK clients' transport-level retries.
+func (p *MiMoProvider) SetMaxRetries(n int) {
+	p.maxRetries = n
+}
+
+// SetTransport routes the provider's requests through rt.
+func (p *MiMoProvider) SetTransport(rt http.RoundTripper) {
+	p.client.Transport = rt
+}
+
 func (p *MiMoProvider) endpoint() string {
 	return p.apiBase + "/chat/completions"
 }
 
-// reasoningDefaultsOn reports whether this MiMo model has reasoning enabled by
-// default. v2.5 Pro and v2.5 both support reasoning; v2 pro and omni do; v2 flash does not.
+// mimoReasoningModels have reasoning enabled by default. v2.5 Pro and v2.5
+// both support reasoning; v2 pro and omni do; v2 flash does not.
+var mimoReasoningModels = []string{"mimo-v2.5-pro", "mimo-v2.5", "mimo-v2-pro", "mimo-v2-omni"}
+
+// mimoReasoningDefaultsOn reports whether this MiMo model has reasoning
+// enabled by default.
 func mimoReasoningDefaultsOn(modelType string) bool {
-	mt := strings.TrimSpace(modelType)
-	return mt == "mimo-v2.5-pro" || mt == "mimo-v2.5" || mt == "mimo-v2-pro" || mt == "mimo-v2-omni"
+	return slices.Contains(mimoReasoningModels, strings.TrimSpace(modelType))
 }
 
 // Chat sends a chat completion request to MiMo.
@@ -186,8 +203,8 @@ func (p *MiMoProvider) buildRequest(req *Request, thinkingEnabled, streaming boo
 		Tools:    req.Tools,
 		Stream:   streaming,
 	}
-	if p.maxTokens > 0 {
-		r.MaxTokens = p.maxTokens
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		r.MaxTokens = maxTokens
 	}
 	// MiMo accepts temperature alongside reasoning; pass through when configured.
 	if p.temperature != 0 {
@@ -212,14 +229,15 @@ func (p *MiMoProvider) doPost(ctx context.Context, body any) (*http.Response, er
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(), bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
-
-	return p.client.Do(httpReq)
+	return doWithRetry(ctx, "mimo", p.maxRetries, func() (*http.Response, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(), bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+		return p.client.Do(httpReq)
+	})
 }
 
 // chatStream handles streaming completion with SSE parsing.
@@ -235,9 +253,9 @@ func (p *MiMoProvider) chatStream(ctx context.Context, mmReq mmRequest, start ti
 		body, _ := io.ReadAll(httpResp.Body)
 		var apiErr mmErrorResp
 		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
-			return nil, fmt.Errorf("mimo API error (%d): %s", httpResp.StatusCode, apiErr.Error.Message)
+			return nil, ClassifyContextLength("mimo", fmt.Errorf("mimo API error (%d): %s", httpResp.StatusCode, apiErr.Error.Message))
 		}
-		return nil, fmt.Errorf("mimo API error (%d): %s", httpResp.StatusCode, string(body))
+		return nil, ClassifyContextLength("mimo", fmt.Errorf("mimo API error (%d): %s", httpResp.StatusCode, string(body)))
 	}
 
 	resp := &Response{
@@ -321,6 +339,7 @@ func (p *MiMoProvider) chatStream(ctx context.Context, mmReq mmRequest, start ti
 					acc.name = tc.Function.Name
 				}
 				acc.args.WriteString(tc.Function.Arguments)
+				adapter.EmitToolCallArgs(tc.Index, acc.id, tc.Function.Arguments)
 			}
 
 			if chunk.Choices[0].FinishReason != nil {
@@ -374,6 +393,7 @@ func (p *MiMoProvider) chatStream(ctx context.Context, mmReq mmRequest, start ti
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = finishReason
 		resp.Usage = Usage{
 			PromptTokens:     usage.PromptTokens,
 			CompletionTokens: usage.CompletionTokens,
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,7 +29,8 @@ var minimaxModelAPINames = map[string]string{
 
 func init() {
 	RegisterProvider("minimax-cn", ProviderRegistration{
-		Models: []string{"minimax-m2.5", "minimax-m2.7"},
+		Models:          []string{"minimax-m2.5", "minimax-m2.7"},
+		ReasoningModels: minimaxReasoningModels,
 		ContextWindows: map[string]int{
 			"minimax-m2.5": 196608,
 			"minimax-m2.7": 204800,
@@ -40,7 +43,8 @@ func init() {
 	})
 
 	RegisterProvider("minimax-global", ProviderRegistration{
-		Models: []string{"minimax-m2.5", "minimax-m2.7"},
+		Models:          []string{"minimax-m2.5", "minimax-m2.7"},
+		ReasoningModels: minimaxReasoningModels,
 		ContextWindows: map[string]int{
 			"minimax-m2.5": 196608,
 			"minimax-m2.7": 204800,
@@ -65,9 +69,11 @@ type MinimaxProvider struct {
 	client       openai.Client
 }
 
+// minimaxReasoningModels run with thinking enabled.
+var minimaxReasoningModels = []string{"minimax-m2.5", "minimax-m2.7"}
+
 func minimaxThinkingEnabled(modelType string) bool {
-	mt := strings.TrimSpace(modelType)
-	return mt == "minimax-m2.5" || mt == "minimax-m2.7"
+	return slices.Contains(minimaxReasoningModels, strings.TrimSpace(modelType))
 }
 
 func minimaxRequestTemperature(modelType string, configured float64) (float64, bool) {
@@ -138,6 +144,16 @@ func newMinimaxProvider(providerName, apiKey, apiBase, defaultBase, modelType, m
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *MinimaxProvider) SetMaxRetries(n int) {
+	p.client = withOpenAISDKMaxRetries(p.client, n)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *MinimaxProvider) SetTransport(rt http.RoundTripper) {
+	p.client = withOpenAISDKTransport(p.client, rt)
+}
+
 // Chat sends a chat completion request to Minimax.
 func (p *MinimaxProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
 	start := time.Now()
@@ -164,8 +180,8 @@ func (p *MinimaxProvider) Chat(ctx context.Context, req *Request) (ChatResult, e
 		Messages: messages,
 		Tools:    toOpenAIChatTools(req.Tools),
 	}
-	if p.maxTokens > 0 {
-		chatReq.MaxTokens = openai.Int(int64(p.maxTokens))
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		chatReq.MaxTokens = openai.Int(int64(maxTokens))
 	}
 
 	requestTemp, forced := minimaxRequestTemperature(p.modelType, p.temperature)
@@ -199,7 +215,7 @@ func (p *MinimaxProvider) Chat(ctx context.Context, req *Request) (ChatResult, e
 		chatResp, streamReasoning, _, _, err := openAIStreamChat(ctx, p.client, chatReq, adapter, requestOpts...)
 		if err != nil {
 			logger.Error("minimax request send error", "provider", p.providerName, "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(fmt.Errorf("request failed: %w", sdkAPIError(p.providerName, err)))
 			return
 		}
 
@@ -249,6 +265,7 @@ func (p *MinimaxProvider) Chat(ctx context.Context, req *Request) (ChatResult, e
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = string(choice.FinishReason)
 		resp.Usage = Usage{
 			PromptTokens:     int(chatResp.Usage.PromptTokens),
 			CompletionTokens: int(chatResp.Usage.CompletionTokens),
@@ -4,6 +4,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -20,8 +21,10 @@ const (
 
 func init() {
 	RegisterProvider("moonshot-cn", ProviderRegistration{
-		Models:       []string{"kimi-k2.5"},
-		VisionModels: []string{"kimi-k2.5"},
+		Models:          []string{"kimi-k2.5"},
+		VisionModels:    []string{"kimi-k2.5"},
+		ReasoningModels: []string{"kimi-k2.5"},
+		JSONModeModels:  []string{"kimi-k2.5"},
 		ContextWindows: map[string]int{
 			"kimi-k2.5": 262144,
 		},
@@ -33,8 +36,10 @@ func init() {
 	})
 
 	RegisterProvider("moonshot-global", ProviderRegistration{
-		Models:       []string{"kimi-k2.5"},
-		VisionModels: []string{"kimi-k2.5"},
+		Models:          []string{"kimi-k2.5"},
+		VisionModels:    []string{"kimi-k2.5"},
+		ReasoningModels: []string{"kimi-k2.5"},
+		JSONModeModels:  []string{"kimi-k2.5"},
 		ContextWindows: map[string]int{
 			"kimi-k2.5": 262144,
 		},
@@ -90,6 +95,16 @@ func newMoonshotProvider(providerName, apiKey, apiBase, defaultBase, modelType,
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *MoonshotProvider) SetMaxRetries(n int) {
+	p.client = withOpenAISDKMaxRetries(p.client, n)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *MoonshotProvider) SetTransport(rt http.RoundTripper) {
+	p.client = withOpenAISDKTransport(p.client, rt)
+}
+
 // Chat sends a chat completion request to Moonshot.
 func (p *MoonshotProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
 	start := time.Now()
@@ -114,8 +129,8 @@ func (p *MoonshotProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 		Messages: messages,
 		Tools:    toOpenAIChatTools(req.Tools),
 	}
-	if p.maxTokens > 0 {
-		chatReq.MaxTokens = openai.Int(int64(p.maxTokens))
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		chatReq.MaxTokens = openai.Int(int64(maxTokens))
 	}
 	requestTemp, forced := moonshotRequestTemperature(p.modelType, p.temperature)
 	if requestTemp != 0 {
@@ -147,7 +162,7 @@ func (p *MoonshotProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 		chatResp, streamReasoning, _, _, err := openAIStreamChat(ctx, p.client, chatReq, adapter, requestOpts...)
 		if err != nil {
 			logger.Error("moonshot request send error", "provider", p.providerName, "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(ClassifyContextLength(p.providerName, fmt.Errorf("request failed: %w", err)))
 			return
 		}
 
@@ -197,6 +212,7 @@ func (p *MoonshotProvider) Chat(ctx context.Context, req *Request) (ChatResult,
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = string(choice.FinishReason)
 		resp.Usage = Usage{
 			PromptTokens:     int(chatResp.Usage.PromptTokens),
 			CompletionTokens: int(chatResp.Usage.CompletionTokens),
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+// oauthTestServer serves a token endpoint that always issues "fresh-token" and
+// a Responses endpoint that accepts only validToken.
+func oauthTestServer(t *testing.T, validToken string, refreshes *int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			atomic.AddInt32(refreshes, 1)
+			_ = r.ParseForm()
+			if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "fresh-token", "expires_in": 3600})
+		case "/responses":
+			if r.Header.Get("Authorization") != "Bearer "+validToken {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(`data: {"type":"response.output_item.done","item":{"type":"message","content":[{"type":"output_text","text":"hello"}]}}` + "\n\n" +
+				`data: {"type":"response.completed","response":{"usage":{"input_tokens":1,"output_tokens":1,"total_tokens":2}}}` + "\n\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// useTestRefresher swaps in a refresher that exchanges the refresh token at
+// tokenURL, mirroring the cmd package's RefreshOAuthToken.
+func useTestRefresher(t *testing.T, tokenURL string) {
+	t.Helper()
+	prev := oauthRefresher
+	t.Cleanup(func() { oauthRefresher = prev })
+	SetOAuthRefresher(func(cfg *config.Config, providerName string) string {
+		old := cfg.GetOAuthToken(providerName)
+		resp, err := http.PostForm(tokenURL, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {old.RefreshToken}})
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		var tr struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&tr) != nil {
+			return ""
+		}
+		cfg.SetOAuthToken(providerName, &config.OAuthTokenConfig{
+			AccessToken:  tr.AccessToken,
+			RefreshToken: old.RefreshToken,
+			ExpiresAt:    time.Now().Unix() + tr.ExpiresIn,
+			AccountID:    old.AccountID,
+		})
+		return tr.AccessToken
+	})
+}
+
+func newOAuthTestProvider(t *testing.T, cfg *config.Config, srvURL string) *OpenAIProvider {
+	t.Helper()
+	f, err := NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		t.Fatalf("NewFactory: %v", err)
+	}
+	p, err := f.Create("openai-oauth", "gpt-5.4")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	op := p.(*OpenAIProvider)
+	op.chatGPTBase = srvURL
+	return op
+}
+
+func oauthTestConfig(expiresAt int64) *config.Config {
+	cfg := &config.Config{}
+	cfg.Thread.Provider = "openai-oauth"
+	cfg.Thread.ModelType = "gpt-5.4"
+	cfg.Providers.OpenAIOAuth = &config.OAuthTokenConfig{
+		AccessToken:  "old-token",
+		RefreshToken: "refresh-1",
+		ExpiresAt:    expiresAt,
+		AccountID:    "acct-1",
+	}
+	return cfg
+}
+
+func chatText(t *testing.T, p *OpenAIProvider) string {
+	t.Helper()
+	res, err := p.Chat(t.Context(), &Request{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	resp, err := res.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	return strings.TrimSpace(resp.Content)
+}
+
+func TestOpenAIOAuthRefreshesNearExpiryToken(t *testing.T) {
+	var refreshes int32
+	srv := oauthTestServer(t, "fresh-token", &refreshes)
+	useTestRefresher(t, srv.URL+"/token")
+
+	cfg := oauthTestConfig(time.Now().Add(time.Hour).Unix())
+	p := newOAuthTestProvider(t, cfg, srv.URL)
+	if n := atomic.LoadInt32(&refreshes); n != 0 {
+		t.Fatalf("unexpected refresh at create: %d", n)
+	}
+
+	// The provider outlives the token: it is now inside the expiry grace window.
+	cfg.Providers.OpenAIOAuth.ExpiresAt = time.Now().Unix() + 5
+
+	if got := chatText(t, p); got != "hello" {
+		t.Errorf("content = %q, want hello", got)
+	}
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("refreshes = %d, want 1", n)
+	}
+	if got := cfg.Providers.OpenAIOAuth.AccessToken; got != "fresh-token" {
+		t.Errorf("stored token = %q, want fresh-token", got)
+	}
+
+	// The refreshed token is reused without another exchange.
+	chatText(t, p)
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("refreshes after second request = %d, want 1", n)
+	}
+}
+
+func TestOpenAIOAuthRefreshesOn401(t *testing.T) {
+	var refreshes int32
+	srv := oauthTestServer(t, "fresh-token", &refreshes)
+	useTestRefresher(t, srv.URL+"/token")
+
+	// Token looks valid locally but the server has revoked it.
+	cfg := oauthTestConfig(time.Now().Add(time.Hour).Unix())
+	p := newOAuthTestProvider(t, cfg, srv.URL)
+
+	if got := chatText(t, p); got != "hello" {
+		t.Errorf("content = %q, want hello", got)
+	}
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("refreshes = %d, want 1", n)
+	}
+}
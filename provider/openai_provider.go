@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linanwx/nagobot/logger"
@@ -29,8 +30,10 @@ func init() {
 	// "openai" — API key auth, hits api.openai.com directly. Context windows
 	// reflect the model's full API capacity.
 	RegisterProvider("openai", ProviderRegistration{
-		Models:       models,
-		VisionModels: models,
+		Models:          models,
+		VisionModels:    models,
+		ReasoningModels: models,
+		JSONModeModels:  models,
 		ContextWindows: map[string]int{
 			"gpt-5.5":       1048576,
 			"gpt-5.4":       1048576,
@@ -51,8 +54,10 @@ func init() {
 	// model's underlying capacity. Values sourced from
 	// GET /backend-api/codex/models?client_version=1.0.0.
 	RegisterProvider("openai-oauth", ProviderRegistration{
-		Models:       models,
-		VisionModels: models,
+		Models:          models,
+		VisionModels:    models,
+		ReasoningModels: models,
+		JSONModeModels:  models,
 		ContextWindows: map[string]int{
 			"gpt-5.5":       272000,
 			"gpt-5.4":       272000,
@@ -76,6 +81,23 @@ type OpenAIProvider struct {
 	temperature float64
 	httpClient  *http.Client
 	accountID   string // ChatGPT account ID from OAuth id_token
+	chatGPTBase string // ChatGPT backend base URL used when accountID is set
+	effort      string // reasoning effort (low|medium|high); empty = openaiDefaultReasoningEffort
+	maxRetries  int    // retries of post on network errors and 429/5xx; 0 unless providers.<name>.sdkMaxRetries is set
+
+	tokenMu     sync.Mutex
+	tokenSource func(rejected string) string // OAuth token refresh; nil for static API keys
+}
+
+// SetMaxRetries sets how often a failed request is resent before Chat gives
+// up, matching the SDK clients' transport-level retries.
+func (p *OpenAIProvider) SetMaxRetries(n int) {
+	p.maxRetries = n
+}
+
+// SetTransport routes the provider's requests through rt.
+func (p *OpenAIProvider) SetTransport(rt http.RoundTripper) {
+	p.httpClient.Transport = rt
 }
 
 // SetAccountID sets the ChatGPT account ID for OAuth-based requests.
@@ -83,6 +105,44 @@ func (p *OpenAIProvider) SetAccountID(id string) {
 	p.accountID = id
 }
 
+// openaiDefaultReasoningEffort is sent when neither the agent nor the
+// provider config sets a reasoning effort.
+const openaiDefaultReasoningEffort = "high"
+
+// SetReasoningEffort sets the Responses API reasoning effort. Unknown values
+// are ignored so the default applies.
+func (p *OpenAIProvider) SetReasoningEffort(effort string) {
+	effort = strings.ToLower(strings.TrimSpace(effort))
+	switch effort {
+	case "", "low", "medium", "high":
+		p.effort = effort
+	default:
+		logger.Warn("unsupported openai reasoning effort, using default", "effort", effort, "default", openaiDefaultReasoningEffort)
+	}
+}
+
+// SetTokenSource installs an OAuth token source consulted before each request
+// and again, with the rejected token, after a 401.
+func (p *OpenAIProvider) SetTokenSource(fn func(rejected string) string) {
+	p.tokenMu.Lock()
+	p.tokenSource = fn
+	p.tokenMu.Unlock()
+}
+
+// accessToken returns the bearer token for the next request, refreshing it
+// through the token source when one is set. rejected names a token the server
+// just refused, forcing a refresh.
+func (p *OpenAIProvider) accessToken(rejected string) string {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+	if p.tokenSource != nil {
+		if tok := p.tokenSource(rejected); tok != "" {
+			p.apiKey = tok
+		}
+	}
+	return p.apiKey
+}
+
 func newOpenAIProvider(apiKey, apiBase, modelType, modelName string, maxTokens int, temperature float64) *OpenAIProvider {
 	if modelName == "" {
 		modelName = modelType
@@ -101,6 +161,7 @@ func newOpenAIProvider(apiKey, apiBase, modelType, modelName string, maxTokens i
 		maxTokens:   maxTokens,
 		temperature: temperature,
 		httpClient:  &http.Client{Timeout: 5 * time.Minute},
+		chatGPTBase: openAIChatGPTBase,
 	}
 }
 
@@ -123,34 +184,35 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req *Request) (ChatResult, er
 		return nil, fmt.Errorf("failed to build request: %w", err)
 	}
 
-	// Use ChatGPT backend when authenticated via OAuth (account ID present).
-	base := p.baseURL
-	if p.accountID != "" {
-		base = openAIChatGPTBase
-	}
-	url := base + "/responses"
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "text/event-stream")
-	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
-	if p.accountID != "" {
-		httpReq.Header.Set("ChatGPT-Account-ID", p.accountID)
-	}
-
-	httpResp, err := p.httpClient.Do(httpReq)
+	token := p.accessToken("")
+	httpResp, err := p.postWithRetry(ctx, body, token)
 	if err != nil {
 		logger.Error("openai request error", "provider", "openai", "err", err)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
+	// An OAuth token can be revoked or expire early; refresh once and retry.
+	if httpResp.StatusCode == http.StatusUnauthorized && p.hasTokenSource() {
+		httpResp.Body.Close()
+		if fresh := p.accessToken(token); fresh != "" && fresh != token {
+			logger.Info("openai access token rejected, retrying with refreshed token", "provider", "openai")
+			httpResp, err = p.postWithRetry(ctx, body, fresh)
+			if err != nil {
+				logger.Error("openai request error", "provider", "openai", "err", err)
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+		} else {
+			return nil, fmt.Errorf("request failed: %w", NewAPIError("openai", http.StatusUnauthorized, "",
+				"unauthorized (OAuth token refresh failed; run: nagobot auth openai)"))
+		}
+	}
+
 	if httpResp.StatusCode != http.StatusOK {
 		defer httpResp.Body.Close()
 		errBody, _ := io.ReadAll(httpResp.Body)
-		logger.Error("openai request error", "provider", "openai", "status", httpResp.StatusCode, "body", string(errBody))
-		return nil, fmt.Errorf("request failed: %d %s", httpResp.StatusCode, string(errBody))
+		body := logger.Redact(string(errBody))
+		logger.Error("openai request error", "provider", "openai", "status", httpResp.StatusCode, "body", body)
+		return nil, ClassifyContextLength("openai", fmt.Errorf("request failed: %w", apiErrorFromBody("openai", httpResp.StatusCode, []byte(body))))
 	}
 
 	providerLabel := "openai"
@@ -196,6 +258,39 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req *Request) (ChatResult, er
 	return adapter.Result(), nil
 }
 
+// postWithRetry is post, resent up to maxRetries times (see doWithRetry).
+func (p *OpenAIProvider) postWithRetry(ctx context.Context, body []byte, token string) (*http.Response, error) {
+	return doWithRetry(ctx, "openai", p.maxRetries, func() (*http.Response, error) {
+		return p.post(ctx, body, token)
+	})
+}
+
+// post sends the Responses API request with the given bearer token. The
+// ChatGPT backend is used when authenticated via OAuth (account ID present).
+func (p *OpenAIProvider) post(ctx context.Context, body []byte, token string) (*http.Response, error) {
+	base := p.baseURL
+	if p.accountID != "" {
+		base = p.chatGPTBase
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/responses", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	if p.accountID != "" {
+		httpReq.Header.Set("ChatGPT-Account-ID", p.accountID)
+	}
+	return p.httpClient.Do(httpReq)
+}
+
+func (p *OpenAIProvider) hasTokenSource() bool {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+	return p.tokenSource != nil
+}
+
 // buildRequestBody converts internal Request to Responses API JSON.
 func (p *OpenAIProvider) buildRequestBody(req *Request) ([]byte, error) {
 	// Extract system messages into instructions.
@@ -341,16 +436,18 @@ func (p *OpenAIProvider) buildRequestBody(req *Request) ([]byte, error) {
 		tools = append(tools, tool)
 	}
 
+	effort := p.effort
+	if effort == "" {
+		effort = openaiDefaultReasoningEffort
+	}
+	reasoning := map[string]any{"effort": effort, "summary": "auto"}
 	body := map[string]any{
-		"model":  p.modelName,
-		"input":  input,
-		"stream": true,
-		"store":  false,
-		"include": []string{"reasoning.encrypted_content"},
-		"reasoning": map[string]any{
-			"effort":  "high",
-			"summary": "auto",
-		},
+		"model":     p.modelName,
+		"input":     input,
+		"stream":    true,
+		"store":     false,
+		"include":   []string{"reasoning.encrypted_content"},
+		"reasoning": reasoning,
 	}
 	if p.modelName == "gpt-5.4" || p.modelName == "gpt-5.5" {
 		body["text"] = map[string]any{"verbosity": "low"}
@@ -366,8 +463,8 @@ func (p *OpenAIProvider) buildRequestBody(req *Request) ([]byte, error) {
 	// Mini/nano models do not support temperature.
 	noTemp := p.accountID != "" || p.modelName == "gpt-5.4-mini" || p.modelName == "gpt-5.4-nano"
 	if p.accountID == "" {
-		if p.maxTokens > 0 {
-			body["max_output_tokens"] = p.maxTokens
+		if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+			body["max_output_tokens"] = maxTokens
 		}
 		if p.temperature != 0 && !noTemp {
 			body["temperature"] = p.temperature
@@ -381,11 +478,13 @@ func (p *OpenAIProvider) buildRequestBody(req *Request) ([]byte, error) {
 // It populates the adapter's Response directly and emits deltas via the adapter.
 // We collect response.output_text.delta events for streaming text delivery,
 // response.output_item.done events for complete output items, and
-// response.completed for usage data.
+// response.completed (or response.incomplete) for usage data and the
+// finish reason.
 func (p *OpenAIProvider) parseSSEStream(httpResp *http.Response, adapter *streamAdapter) error {
 	resp := adapter.resp
 	var content strings.Builder
 	var reasoning strings.Builder
+	var summaryParts strings.Builder // streamed reasoning summaries; used when items carry none
 	var reasoningItems []json.RawMessage
 	var toolCallSignaled bool
 	var toolCalls []ToolCall
@@ -410,6 +509,10 @@ func (p *OpenAIProvider) parseSSEStream(httpResp *http.Response, adapter *stream
 			Type     string         `json:"type"`
 			Delta    string         `json:"delta,omitempty"`
 			Item     map[string]any `json:"item,omitempty"`
+			Part     struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"part,omitempty"`
 			Response struct {
 				Usage struct {
 					InputTokens        int `json:"input_tokens"`
@@ -422,6 +525,10 @@ func (p *OpenAIProvider) parseSSEStream(httpResp *http.Response, adapter *stream
 						ReasoningTokens int `json:"reasoning_tokens"`
 					} `json:"output_tokens_details"`
 				} `json:"usage"`
+				Status            string `json:"status"`
+				IncompleteDetails *struct {
+					Reason string `json:"reason"`
+				} `json:"incomplete_details,omitempty"`
 				Error *responsesAPIError `json:"error,omitempty"`
 			} `json:"response,omitempty"`
 		}
@@ -443,7 +550,26 @@ func (p *OpenAIProvider) parseSSEStream(httpResp *http.Response, adapter *stream
 			}
 			p.extractOutputItem(event.Item, &content, &toolCalls, &reasoning, &reasoningItems)
 
-		case "response.completed", "response.done":
+		case "response.reasoning_summary_part.done":
+			if event.Part.Type == "summary_text" && event.Part.Text != "" {
+				if summaryParts.Len() > 0 {
+					summaryParts.WriteString("\n")
+				}
+				summaryParts.WriteString(event.Part.Text)
+			}
+
+		case "response.completed", "response.done", "response.incomplete":
+			resp.FinishReason = "stop"
+			if event.Response.Status == "incomplete" {
+				resp.FinishReason = "incomplete"
+				if d := event.Response.IncompleteDetails; d != nil && d.Reason != "" {
+					resp.FinishReason = d.Reason
+				}
+				// Match the chat completions vocabulary for a token-limit stop.
+				if resp.FinishReason == "max_output_tokens" {
+					resp.FinishReason = "length"
+				}
+			}
 			resp.Usage = Usage{
 				PromptTokens:     event.Response.Usage.InputTokens,
 				CompletionTokens: event.Response.Usage.OutputTokens,
@@ -455,7 +581,7 @@ func (p *OpenAIProvider) parseSSEStream(httpResp *http.Response, adapter *stream
 		case "response.failed":
 			errInfo := event.Response.Error
 			if errInfo != nil {
-				return fmt.Errorf("API error [%s]: %s", errInfo.Code, errInfo.Message)
+				return ClassifyContextLength("openai", fmt.Errorf("API error [%s]: %s", errInfo.Code, errInfo.Message))
 			}
 			return fmt.Errorf("API returned response.failed")
 		}
@@ -472,7 +598,13 @@ func (p *OpenAIProvider) parseSSEStream(httpResp *http.Response, adapter *stream
 
 	resp.Content = content.String()
 	resp.ReasoningContent = reasoning.String()
+	if resp.ReasoningContent == "" {
+		resp.ReasoningContent = summaryParts.String()
+	}
 	resp.ToolCalls = toolCalls
+	if resp.FinishReason == "stop" && len(toolCalls) > 0 {
+		resp.FinishReason = "tool_calls"
+	}
 
 	return nil
 }
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+func reasoningField(t *testing.T, p *OpenAIProvider) map[string]any {
+	t.Helper()
+	body, err := p.buildRequestBody(&Request{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("buildRequestBody: %v", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	r, _ := m["reasoning"].(map[string]any)
+	return r
+}
+
+func TestOpenAIReasoningEffortInBody(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	cfg := &config.Config{}
+	cfg.Thread.Provider = "openai"
+	cfg.Thread.ModelType = "gpt-5.4"
+	cfg.Providers.OpenAI = &config.ProviderConfig{ReasoningEffort: "low"}
+	f, err := NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		t.Fatalf("NewFactory: %v", err)
+	}
+
+	p, err := f.Create("", "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := reasoningField(t, p.(*OpenAIProvider))["effort"]; got != "low" {
+		t.Errorf("effort from config = %v, want low", got)
+	}
+
+	// Per-agent override wins over provider config.
+	p, err = f.CreateWithSampling("", "", Sampling{ReasoningEffort: "high"})
+	if err != nil {
+		t.Fatalf("CreateWithSampling: %v", err)
+	}
+	if got := reasoningField(t, p.(*OpenAIProvider))["effort"]; got != "high" {
+		t.Errorf("effort from agent = %v, want high", got)
+	}
+
+	// Unset everywhere: falls back to high, as before effort was configurable.
+	cfg.Providers.OpenAI.ReasoningEffort = ""
+	p, err = f.Create("", "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	r := reasoningField(t, p.(*OpenAIProvider))
+	if r["effort"] != openaiDefaultReasoningEffort {
+		t.Errorf("default effort = %v, want %s", r["effort"], openaiDefaultReasoningEffort)
+	}
+	if r["summary"] != "auto" {
+		t.Errorf("summary = %v, want auto", r["summary"])
+	}
+}
+
+func TestOpenAIParseSSEReasoningSummaryParts(t *testing.T) {
+	stream := strings.Join([]string{
+		`data: {"type":"response.reasoning_summary_part.done","part":{"type":"summary_text","text":"Thinking about it."}}`,
+		`data: {"type":"response.output_item.done","item":{"type":"reasoning","summary":[]}}`,
+		`data: {"type":"response.output_item.done","item":{"type":"message","content":[{"type":"output_text","text":"answer"}]}}`,
+		`data: {"type":"response.completed","response":{"usage":{}}}`,
+	}, "\n\n") + "\n\n"
+
+	p := newOpenAIProvider("k", "", "gpt-5.4", "", 0, 0)
+	resp := &Response{}
+	adapter := newStreamAdapter(t.Context(), resp)
+	go func() {
+		defer adapter.Finish()
+		if err := p.parseSSEStream(&http.Response{Body: io.NopCloser(strings.NewReader(stream))}, adapter); err != nil {
+			adapter.SetError(err)
+		}
+	}()
+	got, err := adapter.Result().Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if got.ReasoningContent != "Thinking about it." {
+		t.Errorf("ReasoningContent = %q", got.ReasoningContent)
+	}
+	if got.Content != "answer" {
+		t.Errorf("Content = %q", got.Content)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -199,12 +201,25 @@ var openRouterModels = map[string]openRouterModelMeta{
 	},
 }
 
+// openRouterReasoningModels returns the models sent with ThinkingOpts, sorted.
+func openRouterReasoningModels() []string {
+	var models []string
+	for model, meta := range openRouterModels {
+		if len(meta.ThinkingOpts) > 0 {
+			models = append(models, model)
+		}
+	}
+	slices.Sort(models)
+	return models
+}
+
 func init() {
 	RegisterProvider("openrouter", ProviderRegistration{
-		Models:       []string{"moonshotai/kimi-k2.5", "anthropic/claude-sonnet-4.6", "anthropic/claude-opus-4.6", "anthropic/claude-haiku-4.5", "z-ai/glm-5", "z-ai/glm-5.1", "z-ai/glm-5-turbo", "minimax/minimax-m2.5", "minimax/minimax-m2.7", "qwen/qwen3.5-35b-a3b", "qwen/qwen3.5-flash-02-23", "qwen/qwen3.6-plus:free", "google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview", "x-ai/grok-4.1-fast", "openai/gpt-5.4-mini", "xiaomi/mimo-v2.5-pro", "xiaomi/mimo-v2.5", "xiaomi/mimo-v2-pro", "xiaomi/mimo-v2-omni"},
-		VisionModels: []string{"moonshotai/kimi-k2.5", "anthropic/claude-sonnet-4.6", "anthropic/claude-opus-4.6", "anthropic/claude-haiku-4.5", "qwen/qwen3.5-35b-a3b", "qwen/qwen3.5-flash-02-23", "qwen/qwen3.6-plus:free", "google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview", "x-ai/grok-4.1-fast", "openai/gpt-5.4-mini", "xiaomi/mimo-v2.5", "xiaomi/mimo-v2-omni"},
-		AudioModels:  []string{"google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview", "xiaomi/mimo-v2.5", "xiaomi/mimo-v2-omni"},
-		PDFModels:    []string{"anthropic/claude-sonnet-4.6", "anthropic/claude-opus-4.6", "anthropic/claude-haiku-4.5", "google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview"},
+		Models:          []string{"moonshotai/kimi-k2.5", "anthropic/claude-sonnet-4.6", "anthropic/claude-opus-4.6", "anthropic/claude-haiku-4.5", "z-ai/glm-5", "z-ai/glm-5.1", "z-ai/glm-5-turbo", "minimax/minimax-m2.5", "minimax/minimax-m2.7", "qwen/qwen3.5-35b-a3b", "qwen/qwen3.5-flash-02-23", "qwen/qwen3.6-plus:free", "google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview", "x-ai/grok-4.1-fast", "openai/gpt-5.4-mini", "xiaomi/mimo-v2.5-pro", "xiaomi/mimo-v2.5", "xiaomi/mimo-v2-pro", "xiaomi/mimo-v2-omni"},
+		VisionModels:    []string{"moonshotai/kimi-k2.5", "anthropic/claude-sonnet-4.6", "anthropic/claude-opus-4.6", "anthropic/claude-haiku-4.5", "qwen/qwen3.5-35b-a3b", "qwen/qwen3.5-flash-02-23", "qwen/qwen3.6-plus:free", "google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview", "x-ai/grok-4.1-fast", "openai/gpt-5.4-mini", "xiaomi/mimo-v2.5", "xiaomi/mimo-v2-omni"},
+		AudioModels:     []string{"google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview", "xiaomi/mimo-v2.5", "xiaomi/mimo-v2-omni"},
+		PDFModels:       []string{"anthropic/claude-sonnet-4.6", "anthropic/claude-opus-4.6", "anthropic/claude-haiku-4.5", "google/gemini-3-flash-preview", "google/gemini-3.1-flash-lite-preview"},
+		ReasoningModels: openRouterReasoningModels(),
 		ContextWindows: map[string]int{
 			"moonshotai/kimi-k2.5":          262144,
 			"anthropic/claude-sonnet-4.6":   1048576,
@@ -272,6 +287,16 @@ func newOpenRouterProvider(apiKey, apiBase, modelType, modelName string, maxToke
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *OpenRouterProvider) SetMaxRetries(n int) {
+	p.client = withOpenAISDKMaxRetries(p.client, n)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *OpenRouterProvider) SetTransport(rt http.RoundTripper) {
+	p.client = withOpenAISDKTransport(p.client, rt)
+}
+
 
 
 
@@ -545,6 +570,19 @@ func fromOpenAIChatToolCalls(calls []openai.ChatCompletionMessageToolCallUnion)
 	return result
 }
 
+// withOpenAISDKMaxRetries rebuilds an OpenAI SDK client with a different
+// transport-level retry count. Later options win, so the client's existing
+// key, base URL and headers are kept.
+func withOpenAISDKMaxRetries(client openai.Client, n int) openai.Client {
+	return openai.NewClient(append(slices.Clone(client.Options), oaioption.WithMaxRetries(n))...)
+}
+
+// withOpenAISDKTransport rebuilds an OpenAI SDK client whose requests go
+// through rt, keeping the client's other options.
+func withOpenAISDKTransport(client openai.Client, rt http.RoundTripper) openai.Client {
+	return openai.NewClient(append(slices.Clone(client.Options), oaioption.WithHTTPClient(&http.Client{Transport: rt}))...)
+}
+
 // openAIStreamChat executes a streaming chat completion via the OpenAI SDK.
 // It emits text and tool-call deltas through the adapter and accumulates the
 // full response. Returns the accumulated ChatCompletion and any reasoning
@@ -605,6 +643,9 @@ func openAIStreamChat(ctx context.Context, client openai.Client, params openai.C
 				adapter.EmitToolCall(name)
 			}
 		}
+		for _, tc := range delta.ToolCalls {
+			adapter.EmitToolCallArgs(int(tc.Index), tc.ID, tc.Function.Arguments)
+		}
 		// Accumulate reasoning from non-standard extra fields.
 		// Models return reasoning text in different delta fields:
 		//   - "reasoning_content": DeepSeek, Moonshot, Zhipu, Minimax
@@ -659,8 +700,8 @@ func (p *OpenRouterProvider) Chat(ctx context.Context, req *Request) (ChatResult
 		Messages: messages,
 		Tools:    toOpenAIChatTools(req.Tools),
 	}
-	if p.maxTokens > 0 {
-		chatReq.MaxTokens = openai.Int(int64(p.maxTokens))
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		chatReq.MaxTokens = openai.Int(int64(maxTokens))
 	}
 	if p.temperature != 0 {
 		chatReq.Temperature = openai.Float(p.temperature)
@@ -707,7 +748,7 @@ func (p *OpenRouterProvider) Chat(ctx context.Context, req *Request) (ChatResult
 		chatResp, streamReasoning, upstream, cost, err := openAIStreamChat(ctx, p.client, chatReq, adapter, requestOpts...)
 		if err != nil {
 			logger.Error("openrouter request send error", "provider", "openrouter", "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(fmt.Errorf("request failed: %w", sdkAPIError("openrouter", err)))
 			return
 		}
 
@@ -760,6 +801,7 @@ func (p *OpenRouterProvider) Chat(ctx context.Context, req *Request) (ChatResult
 		resp.ReasoningContent = reasoningText
 		resp.ReasoningDetails = reasoningDetails
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = string(choice.FinishReason)
 		resp.Usage = Usage{
 			PromptTokens:     int(chatResp.Usage.PromptTokens),
 			CompletionTokens: int(chatResp.Usage.CompletionTokens),
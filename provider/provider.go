@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"os"
 	"regexp"
 	"sort"
@@ -28,10 +29,74 @@ type AccountIDSetter interface {
 	SetAccountID(id string)
 }
 
+// TokenSourceSetter is optionally implemented by OAuth-backed providers so the
+// access token can be refreshed between requests. The source returns a valid
+// token; a non-empty rejected token (e.g. one that just got a 401) forces a
+// refresh even if it looks unexpired locally.
+type TokenSourceSetter interface {
+	SetTokenSource(fn func(rejected string) string)
+}
+
+// ReasoningEffortSetter is optionally implemented by providers whose API
+// accepts a reasoning effort level (low, medium, high).
+type ReasoningEffortSetter interface {
+	SetReasoningEffort(effort string)
+}
+
+// MaxRetriesSetter is optionally implemented by providers whose HTTP client
+// retries failed requests itself (separate from the thread-level retry).
+type MaxRetriesSetter interface {
+	SetMaxRetries(n int)
+}
+
+// TransportSetter is optionally implemented by providers so their HTTP
+// traffic can be routed through a wrapping transport (e.g. the transcript).
+type TransportSetter interface {
+	SetTransport(rt http.RoundTripper)
+}
+
+// Pinger is optionally implemented by providers that have a cheaper
+// reachability check than a chat request (e.g. a models endpoint).
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// pingMaxTokens caps the output of Ping's fallback chat request. 16 is the
+// smallest max_output_tokens the OpenAI Responses API accepts.
+const pingMaxTokens = 16
+
+// Ping checks that p is reachable. Providers implementing Pinger use their
+// own check; all others get a minimal one-message chat request capped at a
+// handful of output tokens.
+func Ping(ctx context.Context, p Provider) error {
+	if p == nil {
+		return errors.New("no provider configured")
+	}
+	if pinger, ok := p.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	res, err := p.Chat(ctx, &Request{Messages: []Message{UserMessage("Reply with OK.")}, MaxTokens: pingMaxTokens})
+	if err != nil {
+		return err
+	}
+	_, err = res.Wait()
+	return err
+}
+
 // Request represents a chat completion request.
 type Request struct {
-	Messages []Message
-	Tools    []ToolDef
+	Messages  []Message
+	Tools     []ToolDef
+	MaxTokens int // Optional output cap below the provider's configured maxTokens (e.g. Ping). Zero = configured.
+}
+
+// outputCap returns the output token limit to send: configured, lowered to
+// r.MaxTokens when that is set and smaller. Zero means no limit is sent.
+func (r *Request) outputCap(configured int) int {
+	if r.MaxTokens > 0 && (configured <= 0 || r.MaxTokens < configured) {
+		return r.MaxTokens
+	}
+	return configured
 }
 
 // Message represents a chat message in OpenAI format (internal canonical format).
@@ -44,6 +109,7 @@ type Message struct {
 	ToolCalls        []ToolCall      `json:"tool_calls,omitempty"`        // for assistant messages
 	ToolCallID       string     `json:"tool_call_id,omitempty"`      // for tool result messages
 	Name             string     `json:"name,omitempty"`              // tool name for tool results
+	IsError          bool       `json:"is_error,omitempty"`          // tool result reports a failed call
 	ID               string     `json:"id,omitempty"`                // unique message identifier
 	Timestamp        time.Time  `json:"timestamp,omitempty"`         // when message was created
 	Compressed       string     `json:"compressed,omitempty"`        // compressed version of content
@@ -52,6 +118,7 @@ type Message struct {
 	HeartbeatTrim    bool       `json:"heartbeat_trim,omitempty"`    // Tier 1 flag: heartbeat turn marked for send-time removal
 	SkipTrim         bool       `json:"skip_trim,omitempty"`         // tool result must not be compressed (e.g. compression summary)
 	Source           string     `json:"source,omitempty"`            // wake source that triggered this message
+	Admin            bool       `json:"admin,omitempty"`             // triggering wake was admin (carried into resume wakes)
 	OriginalContent  string     `json:"original_content,omitempty"`  // pre-rephrase content (set by rephrase agent)
 }
 
@@ -93,6 +160,7 @@ type Response struct {
 	ReasoningContent string          // reasoning text (provider-specific)
 	ReasoningDetails json.RawMessage // opaque reasoning details (Gemini thought_signature)
 	ToolCalls        []ToolCall      // tool calls (if any)
+	FinishReason     string          // provider's stop reason as reported (e.g. "stop", "length", "max_tokens")
 	Usage            Usage           // token usage
 	Quota            *Quota          // rate-limit quota (optional, provider-specific)
 	ProviderLabel    string          // effective provider name for metrics (e.g. "openai" vs "openai-oauth")
@@ -104,6 +172,16 @@ func (r *Response) HasToolCalls() bool {
 	return len(r.ToolCalls) > 0
 }
 
+// Truncated reports whether the model stopped because it hit its output
+// token limit rather than finishing the answer.
+func (r *Response) Truncated() bool {
+	switch r.FinishReason {
+	case "length", "max_tokens", "MAX_TOKENS":
+		return true
+	}
+	return false
+}
+
 // Usage represents token usage information.
 type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
@@ -131,14 +209,16 @@ type ProviderConstructor func(apiKey, apiBase, modelType, modelName string, maxT
 
 // ProviderRegistration defines metadata and constructor for a provider.
 type ProviderRegistration struct {
-	Models         []string
-	VisionModels   []string       // Subset of Models that support image input.
-	AudioModels    []string       // Subset of Models that support audio input.
-	PDFModels      []string       // Subset of Models that support PDF document input.
-	ContextWindows map[string]int // model key -> context window size in tokens
-	EnvKey         string
-	EnvBase        string
-	Constructor    ProviderConstructor
+	Models          []string
+	VisionModels    []string       // Subset of Models that support image input.
+	AudioModels     []string       // Subset of Models that support audio input.
+	PDFModels       []string       // Subset of Models that support PDF document input.
+	ReasoningModels []string       // Subset of Models that run with thinking/reasoning enabled.
+	JSONModeModels  []string       // Subset of Models whose API offers a JSON output mode.
+	ContextWindows  map[string]int // model key -> context window size in tokens
+	EnvKey          string
+	EnvBase         string
+	Constructor     ProviderConstructor
 }
 
 // supportedModelTypes is the whitelist of supported model types.
@@ -156,6 +236,12 @@ var audioCapable = map[string]bool{}
 // pdfCapable tracks provider:model pairs that support PDF document input.
 var pdfCapable = map[string]bool{}
 
+// reasoningCapable tracks provider:model pairs that run with reasoning enabled.
+var reasoningCapable = map[string]bool{}
+
+// jsonModeCapable tracks provider:model pairs whose API offers a JSON output mode.
+var jsonModeCapable = map[string]bool{}
+
 // providerModelContextWindows maps "provider:model" to context window size in tokens.
 // Keyed per provider so that a model accessed via different routes (e.g. openai vs
 // openai-oauth) can have different effective limits — OAuth via the ChatGPT codex
@@ -185,24 +271,11 @@ func RegisterProvider(name string, reg ProviderRegistration) {
 	reg.Models = models
 	reg.EnvKey = strings.TrimSpace(reg.EnvKey)
 	reg.EnvBase = strings.TrimSpace(reg.EnvBase)
-	for _, vm := range reg.VisionModels {
-		vm = strings.TrimSpace(vm)
-		if vm != "" {
-			visionCapable[name+":"+vm] = true
-		}
-	}
-	for _, am := range reg.AudioModels {
-		am = strings.TrimSpace(am)
-		if am != "" {
-			audioCapable[name+":"+am] = true
-		}
-	}
-	for _, pm := range reg.PDFModels {
-		pm = strings.TrimSpace(pm)
-		if pm != "" {
-			pdfCapable[name+":"+pm] = true
-		}
-	}
+	markCapable(visionCapable, name, reg.VisionModels)
+	markCapable(audioCapable, name, reg.AudioModels)
+	markCapable(pdfCapable, name, reg.PDFModels)
+	markCapable(reasoningCapable, name, reg.ReasoningModels)
+	markCapable(jsonModeCapable, name, reg.JSONModeModels)
 	for k, v := range reg.ContextWindows {
 		k = strings.TrimSpace(k)
 		if k == "" {
@@ -214,6 +287,16 @@ func RegisterProvider(name string, reg ProviderRegistration) {
 	providerModelTypes[name] = append([]string(nil), models...)
 }
 
+// markCapable records each of models as provider:model in set.
+func markCapable(set map[string]bool, providerName string, models []string) {
+	for _, m := range models {
+		m = strings.TrimSpace(m)
+		if m != "" {
+			set[providerName+":"+m] = true
+		}
+	}
+}
+
 // SupportedProviders returns all supported provider names in sorted order.
 func SupportedProviders() []string {
 	names := make([]string, 0, len(providerModelTypes))
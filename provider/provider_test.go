@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingProvider captures the last request and answers "OK".
+type recordingProvider struct {
+	req *Request
+}
+
+func (p *recordingProvider) Chat(_ context.Context, req *Request) (ChatResult, error) {
+	p.req = req
+	return NewBasicResult(&Response{Content: "OK"}), nil
+}
+
+func TestPingCapsOutputTokens(t *testing.T) {
+	p := &recordingProvider{}
+	if err := Ping(context.Background(), p); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if p.req == nil || p.req.MaxTokens != pingMaxTokens {
+		t.Fatalf("ping request = %+v, want MaxTokens %d", p.req, pingMaxTokens)
+	}
+
+	ds := newDeepSeekProvider("key", "", "deepseek-v4", "deepseek-v4", 8192, 1.0)
+	if got := ds.buildRequest(p.req, false, false).MaxTokens; got != pingMaxTokens {
+		t.Errorf("capped max_tokens = %d, want %d", got, pingMaxTokens)
+	}
+	if got := ds.buildRequest(&Request{Messages: p.req.Messages}, false, false).MaxTokens; got != 8192 {
+		t.Errorf("uncapped max_tokens = %d, want 8192", got)
+	}
+}
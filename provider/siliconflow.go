@@ -4,6 +4,8 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,7 +22,8 @@ const (
 
 func init() {
 	RegisterProvider("siliconflow-cn", ProviderRegistration{
-		Models: []string{"Pro/zai-org/GLM-5.1"},
+		Models:          []string{"Pro/zai-org/GLM-5.1"},
+		ReasoningModels: siliconflowReasoningModels,
 		ContextWindows: map[string]int{
 			"Pro/zai-org/GLM-5.1": 202752,
 		},
@@ -32,7 +35,8 @@ func init() {
 	})
 
 	RegisterProvider("siliconflow-global", ProviderRegistration{
-		Models: []string{"zai-org/GLM-5.1"},
+		Models:          []string{"zai-org/GLM-5.1"},
+		ReasoningModels: siliconflowReasoningModels,
 		ContextWindows: map[string]int{
 			"zai-org/GLM-5.1": 202752,
 		},
@@ -56,9 +60,11 @@ type SiliconflowProvider struct {
 	client       openai.Client
 }
 
+// siliconflowReasoningModels run with thinking enabled (CN and global IDs).
+var siliconflowReasoningModels = []string{"Pro/zai-org/GLM-5.1", "zai-org/GLM-5.1"}
+
 func siliconflowThinkingEnabled(modelType string) bool {
-	m := strings.TrimSpace(modelType)
-	return m == "Pro/zai-org/GLM-5.1" || m == "zai-org/GLM-5.1"
+	return slices.Contains(siliconflowReasoningModels, strings.TrimSpace(modelType))
 }
 
 func siliconflowRequestTemperature(modelType string, configured float64) (float64, bool) {
@@ -92,6 +98,16 @@ func newSiliconflowProvider(providerName, apiKey, apiBase, defaultBase, modelTyp
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *SiliconflowProvider) SetMaxRetries(n int) {
+	p.client = withOpenAISDKMaxRetries(p.client, n)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *SiliconflowProvider) SetTransport(rt http.RoundTripper) {
+	p.client = withOpenAISDKTransport(p.client, rt)
+}
+
 // Chat sends a chat completion request to SiliconFlow.
 func (p *SiliconflowProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
 	start := time.Now()
@@ -118,8 +134,8 @@ func (p *SiliconflowProvider) Chat(ctx context.Context, req *Request) (ChatResul
 		Messages: messages,
 		Tools:    toOpenAIChatTools(req.Tools),
 	}
-	if p.maxTokens > 0 {
-		chatReq.MaxTokens = openai.Int(int64(p.maxTokens))
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		chatReq.MaxTokens = openai.Int(int64(maxTokens))
 	}
 
 	requestTemp, forced := siliconflowRequestTemperature(p.modelType, p.temperature)
@@ -159,7 +175,7 @@ func (p *SiliconflowProvider) Chat(ctx context.Context, req *Request) (ChatResul
 		chatResp, streamReasoning, _, _, err := openAIStreamChat(ctx, p.client, chatReq, adapter)
 		if err != nil {
 			logger.Error("siliconflow request send error", "provider", p.providerName, "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(ClassifyContextLength(p.providerName, fmt.Errorf("request failed: %w", err)))
 			return
 		}
 
@@ -201,6 +217,7 @@ func (p *SiliconflowProvider) Chat(ctx context.Context, req *Request) (ChatResul
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = string(choice.FinishReason)
 		resp.Usage = Usage{
 			PromptTokens:     int(chatResp.Usage.PromptTokens),
 			CompletionTokens: int(chatResp.Usage.CompletionTokens),
@@ -55,6 +55,18 @@ func (a *streamAdapter) EmitToolCall(name string) {
 	}
 }
 
+// EmitToolCallArgs sends a tool-call argument fragment for the call at
+// index. No-op if cancelled or finished.
+func (a *streamAdapter) EmitToolCallArgs(index int, id, fragment string) {
+	if fragment == "" {
+		return
+	}
+	select {
+	case a.ch <- StreamDelta{Type: DeltaToolCallArgs, Text: fragment, ToolIndex: index, ToolCallID: id}:
+	case <-a.ctx.Done():
+	}
+}
+
 // SetError records a stream error. Call before Finish().
 // Wait() will return this error after draining the channel.
 func (a *streamAdapter) SetError(err error) {
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+// transcriptSeq orders transcript files written within the same millisecond.
+var transcriptSeq atomic.Uint64
+
+// transcriptSecretHeaders are masked in transcript files regardless of value.
+var transcriptSecretHeaders = []string{
+	"Authorization", "Proxy-Authorization", "X-Api-Key", "Api-Key",
+	"X-Goog-Api-Key", "Cookie", "Set-Cookie",
+}
+
+// EnableTranscript routes p's HTTP traffic through a transport that writes
+// every request and response body, exactly as sent over the wire, as JSON
+// files under dir; credential headers are masked and the rest is passed
+// through logger.Redact. label (usually "provider/model") is recorded in each
+// file and its name. p keeps its own type, so optional interfaces such as
+// Pinger stay visible. Returns false when p does not implement
+// TransportSetter. Debug only: the files hold full conversation content.
+func EnableTranscript(p Provider, dir, label string) bool {
+	setter, ok := p.(TransportSetter)
+	if !ok || strings.TrimSpace(dir) == "" {
+		return false
+	}
+	setter.SetTransport(&transcriptTransport{base: http.DefaultTransport, dir: dir, label: label})
+	return true
+}
+
+type transcriptTransport struct {
+	base  http.RoundTripper
+	dir   string
+	label string
+}
+
+// transcriptEntry is the JSON shape of one transcript file.
+type transcriptEntry struct {
+	Provider string      `json:"provider"`
+	Time     time.Time   `json:"time"`
+	Method   string      `json:"method,omitempty"`
+	URL      string      `json:"url,omitempty"`
+	Status   int         `json:"status,omitempty"`
+	Header   http.Header `json:"header,omitempty"`
+	Body     string      `json:"body,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+func (t *transcriptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	prefix := fmt.Sprintf("%s-%06d-%s", time.Now().Format("20060102-150405.000"),
+		transcriptSeq.Add(1), strings.NewReplacer("/", "_", ":", "_").Replace(t.label))
+
+	body, req, err := snapshotRequestBody(req)
+	entry := transcriptEntry{Method: req.Method, URL: req.URL.String(), Header: req.Header, Body: string(body)}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	t.write(prefix+"-request.json", entry)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.write(prefix+"-response.json", transcriptEntry{Error: err.Error()})
+		return nil, err
+	}
+	// The body is recorded as the caller reads it, so streamed responses
+	// reach the caller unchanged and land in the file once complete.
+	status, header := resp.StatusCode, resp.Header
+	resp.Body = &transcriptBody{ReadCloser: resp.Body, record: func(body []byte, err error) {
+		entry := transcriptEntry{Status: status, Header: header, Body: string(body)}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		t.write(prefix+"-response.json", entry)
+	}}
+	return resp, nil
+}
+
+// snapshotRequestBody returns req's body bytes and a request that can still
+// be sent. It prefers GetBody so the original request is left untouched.
+func snapshotRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, req, err
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		return data, req, err
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(data))
+	return data, clone, err
+}
+
+// write stores entry as dir/name. Failures are logged and never reach the caller.
+func (t *transcriptTransport) write(name string, entry transcriptEntry) {
+	entry.Provider = t.label
+	entry.Time = time.Now()
+	entry.Header = maskSecretHeaders(entry.Header)
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		logger.Warn("transcript encode failed", "file", name, "err", err)
+		return
+	}
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		logger.Warn("transcript dir create failed", "dir", t.dir, "err", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, name), []byte(logger.Redact(string(data))), 0o600); err != nil {
+		logger.Warn("transcript write failed", "file", name, "err", err)
+	}
+}
+
+// maskSecretHeaders returns a copy of h with credential headers masked.
+func maskSecretHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range transcriptSecretHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out.Set(name, "[REDACTED]")
+		}
+	}
+	return out
+}
+
+// transcriptBody buffers a response body as it is read and records it once,
+// at EOF, on a read error, or when the caller closes it early.
+type transcriptBody struct {
+	io.ReadCloser
+	buf    bytes.Buffer
+	record func([]byte, error)
+	once   sync.Once
+}
+
+func (b *transcriptBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.once.Do(func() { b.record(b.buf.Bytes(), nil) })
+	} else if err != nil {
+		b.once.Do(func() { b.record(b.buf.Bytes(), err) })
+	}
+	return n, err
+}
+
+func (b *transcriptBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.record(b.buf.Bytes(), nil) })
+	return err
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/config"
+)
+
+// wireProvider posts the request messages as JSON and returns the raw reply.
+type wireProvider struct {
+	client http.Client
+	url    string
+}
+
+func (p *wireProvider) SetTransport(rt http.RoundTripper) { p.client.Transport = rt }
+
+func (p *wireProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
+	body, _ := json.Marshal(req.Messages)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer secret-token")
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return NewBasicResult(&Response{Content: string(data)}), nil
+}
+
+func TestTranscriptWritesWireRequestAndResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(data), "sk-abcdefghijklmnopqrstuvwxyz") {
+			t.Errorf("server got %q, want the unredacted request", data)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "transcripts")
+	p := &wireProvider{url: srv.URL}
+	if !EnableTranscript(p, dir, "deepseek/deepseek-v4-flash") {
+		t.Fatal("EnableTranscript = false for a TransportSetter")
+	}
+	result, err := p.Chat(context.Background(), &Request{Messages: []Message{
+		UserMessage("my key is sk-abcdefghijklmnopqrstuvwxyz"),
+	}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp, err := result.Wait(); err != nil || !strings.Contains(resp.Content, `"content":"ok"`) {
+		t.Fatalf("Wait = %+v, %v; want the server reply", resp, err)
+	}
+
+	reqFiles, _ := filepath.Glob(filepath.Join(dir, "*-deepseek_deepseek-v4-flash-request.json"))
+	respFiles, _ := filepath.Glob(filepath.Join(dir, "*-deepseek_deepseek-v4-flash-response.json"))
+	if len(reqFiles) != 1 || len(respFiles) != 1 {
+		t.Fatalf("request files %v, response files %v; want one of each", reqFiles, respFiles)
+	}
+	req, _ := os.ReadFile(reqFiles[0])
+	for _, secret := range []string{"sk-abcdefghijklmnopqrstuvwxyz", "secret-token"} {
+		if strings.Contains(string(req), secret) {
+			t.Errorf("request transcript leaks %q:\n%s", secret, req)
+		}
+	}
+	if !strings.Contains(string(req), `"method": "POST"`) || !strings.Contains(string(req), `my key is`) {
+		t.Errorf("request transcript missing the wire request:\n%s", req)
+	}
+	resp, _ := os.ReadFile(respFiles[0])
+	if !strings.Contains(string(resp), `"status": 200`) || !strings.Contains(string(resp), `\"choices\"`) {
+		t.Errorf("response transcript missing the wire body:\n%s", resp)
+	}
+}
+
+func TestEnableTranscriptNeedsTransportSetter(t *testing.T) {
+	if EnableTranscript(&flakyProvider{}, t.TempDir(), "stub/model") {
+		t.Error("EnableTranscript = true for a provider without SetTransport")
+	}
+}
+
+func TestFactoryTranscriptOffByDefault(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+	t.Setenv(config.TranscriptEnv, "")
+	cfg := &config.Config{}
+	cfg.Thread.Provider = "deepseek"
+	cfg.Thread.ModelType = "deepseek-v4-flash"
+	f, err := NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		t.Fatalf("NewFactory: %v", err)
+	}
+
+	transport := func() http.RoundTripper {
+		p, err := f.Create("", "")
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ds, ok := p.(*DeepSeekProvider)
+		if !ok {
+			t.Fatalf("provider = %T, want *DeepSeekProvider unwrapped", p)
+		}
+		return ds.client.Transport
+	}
+	if rt := transport(); rt != nil {
+		t.Errorf("transport = %T, want default when transcripts are off", rt)
+	}
+	t.Setenv(config.TranscriptEnv, t.TempDir())
+	if _, ok := transport().(*transcriptTransport); !ok {
+		t.Errorf("transcript transport not installed when %s is set", config.TranscriptEnv)
+	}
+}
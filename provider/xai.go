@@ -4,6 +4,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/linanwx/nagobot/logger"
@@ -28,6 +29,16 @@ func init() {
 			"grok-4.20-0309-reasoning",
 			"grok-4.20-0309-non-reasoning",
 		},
+		ReasoningModels: []string{
+			"grok-4-1-fast-reasoning",
+			"grok-4.20-0309-reasoning",
+		},
+		JSONModeModels: []string{
+			"grok-4-1-fast-reasoning",
+			"grok-4-1-fast-non-reasoning",
+			"grok-4.20-0309-reasoning",
+			"grok-4.20-0309-non-reasoning",
+		},
 		ContextWindows: map[string]int{
 			"grok-4-1-fast-reasoning":      2000000,
 			"grok-4-1-fast-non-reasoning":  2000000,
@@ -77,6 +88,16 @@ func newXAIProvider(apiKey, apiBase, modelType, modelName string, maxTokens int,
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *XAIProvider) SetMaxRetries(n int) {
+	p.client = withOpenAISDKMaxRetries(p.client, n)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *XAIProvider) SetTransport(rt http.RoundTripper) {
+	p.client = withOpenAISDKTransport(p.client, rt)
+}
+
 // Chat sends a chat completion request to xAI.
 func (p *XAIProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
 	start := time.Now()
@@ -100,8 +121,8 @@ func (p *XAIProvider) Chat(ctx context.Context, req *Request) (ChatResult, error
 		Messages: messages,
 		Tools:    toOpenAIChatTools(req.Tools),
 	}
-	if p.maxTokens > 0 {
-		chatReq.MaxTokens = openai.Int(int64(p.maxTokens))
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		chatReq.MaxTokens = openai.Int(int64(maxTokens))
 	}
 	if p.temperature != 0 {
 		chatReq.Temperature = openai.Float(p.temperature)
@@ -116,7 +137,7 @@ func (p *XAIProvider) Chat(ctx context.Context, req *Request) (ChatResult, error
 		chatResp, streamReasoning, _, _, err := openAIStreamChat(ctx, p.client, chatReq, adapter)
 		if err != nil {
 			logger.Error("xai request send error", "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(ClassifyContextLength("xai", fmt.Errorf("request failed: %w", err)))
 			return
 		}
 
@@ -164,6 +185,7 @@ func (p *XAIProvider) Chat(ctx context.Context, req *Request) (ChatResult, error
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = string(choice.FinishReason)
 		resp.Usage = Usage{
 			PromptTokens:     int(chatResp.Usage.PromptTokens),
 			CompletionTokens: int(chatResp.Usage.CompletionTokens),
@@ -4,6 +4,8 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,7 +22,9 @@ const (
 
 func init() {
 	RegisterProvider("zhipu-cn", ProviderRegistration{
-		Models: []string{"glm-5", "glm-5.1", "glm-5-turbo"},
+		Models:          []string{"glm-5", "glm-5.1", "glm-5-turbo"},
+		ReasoningModels: zhipuReasoningModels,
+		JSONModeModels:  []string{"glm-5", "glm-5.1", "glm-5-turbo"},
 		ContextWindows: map[string]int{
 			"glm-5":       200000,
 			"glm-5.1":     200000,
@@ -34,7 +38,9 @@ func init() {
 	})
 
 	RegisterProvider("zhipu-global", ProviderRegistration{
-		Models: []string{"glm-5", "glm-5.1", "glm-5-turbo"},
+		Models:          []string{"glm-5", "glm-5.1", "glm-5-turbo"},
+		ReasoningModels: zhipuReasoningModels,
+		JSONModeModels:  []string{"glm-5", "glm-5.1", "glm-5-turbo"},
 		ContextWindows: map[string]int{
 			"glm-5":       200000,
 			"glm-5.1":     200000,
@@ -60,9 +66,11 @@ type ZhipuProvider struct {
 	client       openai.Client
 }
 
+// zhipuReasoningModels run with thinking enabled.
+var zhipuReasoningModels = []string{"glm-5", "glm-5.1"}
+
 func zhipuThinkingEnabled(modelType string) bool {
-	m := strings.TrimSpace(modelType)
-	return m == "glm-5" || m == "glm-5.1"
+	return slices.Contains(zhipuReasoningModels, strings.TrimSpace(modelType))
 }
 
 func zhipuRequestTemperature(modelType string, configured float64) (float64, bool) {
@@ -96,6 +104,16 @@ func newZhipuProvider(providerName, apiKey, apiBase, defaultBase, modelType, mod
 	}
 }
 
+// SetMaxRetries sets the SDK client's transport-level retry count.
+func (p *ZhipuProvider) SetMaxRetries(n int) {
+	p.client = withOpenAISDKMaxRetries(p.client, n)
+}
+
+// SetTransport routes the SDK client's requests through rt.
+func (p *ZhipuProvider) SetTransport(rt http.RoundTripper) {
+	p.client = withOpenAISDKTransport(p.client, rt)
+}
+
 // Chat sends a chat completion request to Zhipu.
 func (p *ZhipuProvider) Chat(ctx context.Context, req *Request) (ChatResult, error) {
 	start := time.Now()
@@ -122,8 +140,8 @@ func (p *ZhipuProvider) Chat(ctx context.Context, req *Request) (ChatResult, err
 		Messages: messages,
 		Tools:    toOpenAIChatTools(req.Tools),
 	}
-	if p.maxTokens > 0 {
-		chatReq.MaxTokens = openai.Int(int64(p.maxTokens))
+	if maxTokens := req.outputCap(p.maxTokens); maxTokens > 0 {
+		chatReq.MaxTokens = openai.Int(int64(maxTokens))
 	}
 
 	requestTemp, forced := zhipuRequestTemperature(p.modelType, p.temperature)
@@ -142,8 +160,11 @@ func (p *ZhipuProvider) Chat(ctx context.Context, req *Request) (ChatResult, err
 
 	requestOpts := []oaioption.RequestOption{}
 	if thinkingEnabled {
+		// clear_thinking=false keeps the reasoning_content of earlier assistant
+		// turns (replayed from the session) in the model's context.
 		requestOpts = append(requestOpts,
 			oaioption.WithJSONSet("extra_body.thinking.type", "enabled"),
+			oaioption.WithJSONSet("extra_body.thinking.clear_thinking", false),
 		)
 	}
 
@@ -156,7 +177,7 @@ func (p *ZhipuProvider) Chat(ctx context.Context, req *Request) (ChatResult, err
 		chatResp, streamReasoning, _, _, err := openAIStreamChat(ctx, p.client, chatReq, adapter, requestOpts...)
 		if err != nil {
 			logger.Error("zhipu request send error", "provider", p.providerName, "err", err)
-			adapter.SetError(fmt.Errorf("request failed: %w", err))
+			adapter.SetError(fmt.Errorf("request failed: %w", sdkAPIError(p.providerName, err)))
 			return
 		}
 
@@ -198,6 +219,7 @@ func (p *ZhipuProvider) Chat(ctx context.Context, req *Request) (ChatResult, err
 		resp.Content = finalContent
 		resp.ReasoningContent = reasoningText
 		resp.ToolCalls = toolCalls
+		resp.FinishReason = string(choice.FinishReason)
 		resp.Usage = Usage{
 			PromptTokens:     int(chatResp.Usage.PromptTokens),
 			CompletionTokens: int(chatResp.Usage.CompletionTokens),
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestZhipuRequestReplaysReasoningContent(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		http.Error(w, `{"error":{"message":"stop"}}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	p := newZhipuProvider("zhipu-cn", "test-key", srv.URL, "", "glm-5", "", 0, 0)
+	p.SetMaxRetries(0)
+	res, err := p.Chat(context.Background(), &Request{Messages: []Message{
+		UserMessage("2+2?"),
+		{Role: "assistant", Content: "4", ReasoningContent: "add two and two"},
+		UserMessage("and times 3?"),
+	}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	res.Wait()
+
+	var payload struct {
+		Messages []struct {
+			Role             string `json:"role"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"messages"`
+		ExtraBody struct {
+			Thinking map[string]any `json:"thinking"`
+		} `json:"extra_body"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal request %q: %v", body, err)
+	}
+	if len(payload.Messages) != 3 || payload.Messages[1].Role != "assistant" {
+		t.Fatalf("messages = %+v", payload.Messages)
+	}
+	if got := payload.Messages[1].ReasoningContent; got != "add two and two" {
+		t.Errorf("assistant reasoning_content = %q, want replayed", got)
+	}
+	if v, ok := payload.ExtraBody.Thinking["clear_thinking"]; !ok || v != false {
+		t.Errorf("thinking = %v, want clear_thinking=false", payload.ExtraBody.Thinking)
+	}
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// maxSessionKeyLen bounds user-supplied session keys.
+const maxSessionKeyLen = 128
+
+// ErrSessionExists is returned by Copy when the destination already holds a
+// session.
+var ErrSessionExists = errors.New("session already exists")
+
+// ValidateSessionKey trims raw and checks that it is a usable session key:
+// colon-separated segments of letters, digits, '-', '_' and '.', none empty
+// or dot-only, at most 128 bytes in total.
+func ValidateSessionKey(raw string) (string, error) {
+	key := strings.TrimSpace(raw)
+	if key == "" {
+		return "", fmt.Errorf("session key is empty")
+	}
+	if len(key) > maxSessionKeyLen {
+		return "", fmt.Errorf("session key longer than %d bytes", maxSessionKeyLen)
+	}
+	for _, seg := range strings.Split(key, ":") {
+		if strings.Trim(seg, ".") == "" {
+			return "", fmt.Errorf("session key %q has an empty segment", key)
+		}
+		for _, r := range seg {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+				continue
+			}
+			return "", fmt.Errorf("session key %q contains %q (allowed: letters, digits, - _ . and :)", key, r)
+		}
+	}
+	return key, nil
+}
+
+// Copy deep-copies srcKey's messages into a new session dstKey, together with
+// the conversation settings in meta.json (agent, pins, model override, dry
+// run, rephrase). Channel routing and token statistics are not copied. The
+// copy shares nothing with the source, so later turns in either session leave
+// the other unchanged. Returns the number of messages copied.
+//
+// dstKey must pass ValidateSessionKey and must not name an existing session.
+func (m *Manager) Copy(srcKey, dstKey string) (int, error) {
+	srcKey = normalizeSessionKey(srcKey)
+	dstKey, err := ValidateSessionKey(dstKey)
+	if err != nil {
+		return 0, err
+	}
+	if dstKey == srcKey {
+		return 0, fmt.Errorf("source and destination are the same session")
+	}
+	dstDir := SessionDir(m.sessionsDir, dstKey)
+	if dstDir == SessionDir(m.sessionsDir, srcKey) {
+		return 0, fmt.Errorf("%s maps to the same directory as %s", dstKey, srcKey)
+	}
+	if _, err := os.Stat(dstDir); err == nil {
+		return 0, fmt.Errorf("%s: %w", dstKey, ErrSessionExists)
+	}
+	if existing, err := m.store.Get(dstKey); err != nil {
+		return 0, fmt.Errorf("copy: load %s: %w", dstKey, err)
+	} else if len(existing.Messages) > 0 {
+		return 0, fmt.Errorf("%s: %w", dstKey, ErrSessionExists)
+	}
+
+	src, err := m.Get(srcKey)
+	if err != nil {
+		return 0, fmt.Errorf("copy: load %s: %w", srcKey, err)
+	}
+	// Append extends the cached slice in place, so clone under the lock.
+	m.mu.RLock()
+	msgs := make([]provider.Message, len(src.Messages))
+	for i, msg := range src.Messages {
+		msg.Media = slices.Clone(msg.Media)
+		msg.ToolCalls = slices.Clone(msg.ToolCalls)
+		msg.ReasoningDetails = slices.Clone(msg.ReasoningDetails)
+		msg.ID = "" // IDs embed the session key; Save assigns new ones.
+		msgs[i] = msg
+	}
+	m.mu.RUnlock()
+	if len(msgs) == 0 {
+		return 0, fmt.Errorf("session %s has no messages", srcKey)
+	}
+
+	if err := m.Save(&Session{Key: dstKey, Messages: msgs, CreatedAt: src.CreatedAt}); err != nil {
+		return 0, fmt.Errorf("copy: save %s: %w", dstKey, err)
+	}
+	srcMeta := ReadMeta(SessionDir(m.sessionsDir, srcKey))
+	WriteMeta(dstDir, Meta{
+		Agent:    srcMeta.Agent,
+		Rephrase: srcMeta.Rephrase,
+		DryRun:   srcMeta.DryRun,
+		Pins:     slices.Clone(srcMeta.Pins),
+		Model:    srcMeta.Model,
+	})
+	return len(msgs), nil
+}
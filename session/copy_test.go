@@ -0,0 +1,93 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+func TestCopyForkIsIndependentOfSource(t *testing.T) {
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	mgr, err := NewManager(sessionsDir)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	src := "telegram:1"
+	call := provider.ToolCall{ID: "c1", Type: "function", Function: provider.FunctionCall{Name: "exec", Arguments: `{"command":"ls"}`}}
+	if err := mgr.Append(src,
+		provider.UserMessage("plan the trip"),
+		provider.AssistantMessageWithTools("", "", nil, []provider.ToolCall{call}),
+		provider.ToolResultMessage("c1", "exec", "notes.md"),
+		provider.AssistantMessage("Let's go to Kyoto."),
+	); err != nil {
+		t.Fatal(err)
+	}
+	UpdateMeta(SessionDir(sessionsDir, src), func(m *Meta) {
+		m.Agent = "planner"
+		m.DiscordDM = &DiscordDMMeta{ReplyTo: "dm:1"}
+	})
+
+	n, err := mgr.Copy(src, "telegram:1:osaka")
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("copied %d messages, want 4", n)
+	}
+
+	fork, _ := mgr.Get("telegram:1:osaka")
+	fork.Messages[1].ToolCalls[0].Function.Name = "mutated"
+	if err := mgr.Append("telegram:1:osaka", provider.UserMessage("what about Osaka instead?"), provider.AssistantMessage("Osaka it is.")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := mgr.Reload(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Messages) != 4 || got.Messages[3].Content != "Let's go to Kyoto." {
+		t.Fatalf("source changed after appending to the fork: %+v", got.Messages)
+	}
+	if name := got.Messages[1].ToolCalls[0].Function.Name; name != "exec" {
+		t.Errorf("source tool call = %q, want exec", name)
+	}
+	forked, err := mgr.Reload("telegram:1:osaka")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forked.Messages) != 6 || forked.Messages[0].ID == got.Messages[0].ID {
+		t.Errorf("fork has %d messages (want 6), first ID %q vs source %q", len(forked.Messages), forked.Messages[0].ID, got.Messages[0].ID)
+	}
+
+	meta := ReadMeta(SessionDir(sessionsDir, "telegram:1:osaka"))
+	if meta.Agent != "planner" || meta.DiscordDM != nil {
+		t.Errorf("fork meta = %+v, want the agent without channel routing", meta)
+	}
+}
+
+func TestCopyRefusesExistingOrInvalidDestination(t *testing.T) {
+	mgr, err := NewManager(filepath.Join(t.TempDir(), "sessions"))
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := mgr.Append("cli", provider.UserMessage("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Append("cli:other", provider.UserMessage("keep me")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mgr.Copy("cli", "cli:other"); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("Copy onto existing session: err = %v, want ErrSessionExists", err)
+	}
+	for _, key := range []string{"", "cli:../etc", "a::b", "cli/x", "cli"} {
+		if _, err := mgr.Copy("cli", key); err == nil {
+			t.Errorf("Copy to %q succeeded, want an error", key)
+		}
+	}
+	if _, err := mgr.Copy("empty", "empty:copy"); err == nil {
+		t.Error("Copy of an empty session succeeded, want an error")
+	}
+}
@@ -109,21 +109,34 @@ func WriteFile(path string, s *Session) error {
 		return err
 	}
 
-	tmp := path + ".tmp"
-	f, err := os.Create(tmp)
+	// A unique temp name keeps concurrent writers of the same session from
+	// truncating or renaming each other's half-written file.
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	tmp := f.Name()
 	if err := writeJSONL(f, s.Messages); err != nil {
 		f.Close()
 		os.Remove(tmp)
 		return err
 	}
+	// Flush to stable storage before the rename so a crash cannot leave the
+	// new name pointing at a file whose contents never reached disk.
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
 	if err := f.Close(); err != nil {
 		os.Remove(tmp)
 		return err
 	}
-	return os.Rename(tmp, path)
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
 }
 
 // deriveTimestamps sets CreatedAt/UpdatedAt from message timestamps.
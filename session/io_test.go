@@ -92,3 +92,53 @@ func TestReadLastMessage(t *testing.T) {
 		}
 	})
 }
+
+func TestWriteFileNeverExposesPartialFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "atomic", "session.jsonl")
+
+	const size = 200
+	snapshot := func(tag string) *Session {
+		msgs := make([]provider.Message, size)
+		for i := range msgs {
+			msgs[i] = provider.UserMessage(tag + " " + string(bytes.Repeat([]byte("x"), 512)))
+		}
+		return &Session{Key: "atomic", Messages: msgs}
+	}
+	if err := WriteFile(path, snapshot("initial")); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := WriteFile(path, snapshot("rewrite")); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+				return
+			}
+		}
+	}()
+
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		s, err := ReadFileRaw(path)
+		if err != nil {
+			t.Fatalf("ReadFileRaw() error = %v", err)
+		}
+		if len(s.Messages) != size {
+			t.Fatalf("observed partially written session: %d of %d messages", len(s.Messages), size)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only session.jsonl after writes, found %d entries", len(entries))
+	}
+}
@@ -1,7 +1,6 @@
 package session
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -50,25 +49,43 @@ type Session struct {
 	UpdatedAt time.Time          `json:"updated_at"`
 }
 
-// Manager manages conversation sessions.
+// Manager manages conversation sessions: an in-memory cache over a Store.
 type Manager struct {
 	sessionsDir string
+	store       Store
 	cache       map[string]*Session
 	mu          sync.RWMutex
 	Counts      *MessageCounts // cumulative message counter (nil-safe)
 }
 
-// NewManager creates a new session manager rooted at the given sessions directory.
+// NewManager creates a new session manager rooted at the given sessions
+// directory, persisting messages as session.jsonl files.
 func NewManager(sessionsDir string) (*Manager, error) {
+	store, err := NewFileStore(sessionsDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagerWithStore(sessionsDir, store)
+}
+
+// NewManagerWithStore creates a session manager persisting messages to store.
+// sessionsDir still holds per-session meta.json and memory notes.
+func NewManagerWithStore(sessionsDir string, store Store) (*Manager, error) {
 	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
 		return nil, err
 	}
 	return &Manager{
 		sessionsDir: sessionsDir,
+		store:       store,
 		cache:       make(map[string]*Session),
 	}, nil
 }
 
+// Close releases the underlying store.
+func (m *Manager) Close() error {
+	return m.store.Close()
+}
+
 // Get returns a session by key, creating one if it doesn't exist.
 func (m *Manager) Get(key string) (*Session, error) {
 	key = normalizeSessionKey(key)
@@ -80,7 +97,7 @@ func (m *Manager) Get(key string) (*Session, error) {
 	}
 	m.mu.RUnlock()
 
-	s, err := m.loadFromDisk(key)
+	s, err := m.store.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -94,11 +111,11 @@ func (m *Manager) Get(key string) (*Session, error) {
 	return s, nil
 }
 
-// Reload forces loading session state from disk and refreshes cache.
+// Reload forces loading session state from the store and refreshes cache.
 func (m *Manager) Reload(key string) (*Session, error) {
 	key = normalizeSessionKey(key)
 
-	s, err := m.loadFromDisk(key)
+	s, err := m.store.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +125,35 @@ func (m *Manager) Reload(key string) (*Session, error) {
 	return s, nil
 }
 
-// Save atomically rewrites the full session file (temp + rename).
+// Load reads key straight from the store without touching the cache, for
+// readers (dashboards, summaries) that look at sessions no thread holds.
+func (m *Manager) Load(key string) (*Session, error) {
+	return m.store.Get(normalizeSessionKey(key))
+}
+
+// GetRaw loads key straight from the store without sanitizing or caching,
+// for maintenance commands that rewrite history mid-turn.
+func (m *Manager) GetRaw(key string) (*Session, error) {
+	return m.store.GetRaw(normalizeSessionKey(key))
+}
+
+// Exists reports whether the store holds messages for key. Sessions only
+// created in the cache by Get are not counted.
+func (m *Manager) Exists(key string) bool {
+	ok, err := m.store.Exists(normalizeSessionKey(key))
+	return err == nil && ok
+}
+
+// Evict drops key from the in-memory cache; the next Get reloads it from the
+// store. Used when an idle thread is torn down.
+func (m *Manager) Evict(key string) {
+	key = normalizeSessionKey(key)
+	m.mu.Lock()
+	delete(m.cache, key)
+	m.mu.Unlock()
+}
+
+// Save atomically rewrites the full session (file store: temp + rename).
 // Used for compression and clear operations. For normal turns, use Append.
 func (m *Manager) Save(s *Session) error {
 	s.Key = normalizeSessionKey(s.Key)
@@ -118,27 +163,8 @@ func (m *Manager) Save(s *Session) error {
 		s.UpdatedAt = time.Now()
 	}
 
-	path := m.sessionPath(s.Key)
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
-	tmp := path + ".tmp"
-	f, err := os.Create(tmp)
-	if err != nil {
-		return err
-	}
-	if err := writeJSONL(f, s.Messages); err != nil {
-		f.Close()
-		os.Remove(tmp)
-		return err
-	}
-	if err := f.Close(); err != nil {
-		os.Remove(tmp)
-		return err
-	}
-	if err := os.Rename(tmp, path); err != nil {
+	// Store I/O runs outside m.mu; only the cache swap below is locked.
+	if err := m.store.Save(s); err != nil {
 		return err
 	}
 
@@ -149,8 +175,8 @@ func (m *Manager) Save(s *Session) error {
 	return nil
 }
 
-// Append persists new messages by appending to the session file.
-// Creates the file if it doesn't exist. Updates the in-memory cache.
+// Append persists new messages by appending to the stored session.
+// Creates the session if it doesn't exist. Updates the in-memory cache.
 func (m *Manager) Append(key string, msgs ...provider.Message) error {
 	if len(msgs) == 0 {
 		return nil
@@ -158,18 +184,7 @@ func (m *Manager) Append(key string, msgs ...provider.Message) error {
 	key = normalizeSessionKey(key)
 	EnsureMessageIDs(key, msgs)
 
-	path := m.sessionPath(key)
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return err
-	}
-
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	if err := writeJSONL(f, msgs); err != nil {
+	if err := m.store.Append(key, msgs); err != nil {
 		return err
 	}
 
@@ -214,8 +229,8 @@ func (m *Manager) RephraseLastAssistant(key, original, rephrased string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Load from disk under lock to get the latest state.
-	sess, err := m.loadFromDisk(key)
+	// Load from the store under lock to get the latest state.
+	sess, err := m.store.Get(key)
 	if err != nil {
 		return fmt.Errorf("rephrase: load session %s: %w", key, err)
 	}
@@ -238,8 +253,7 @@ func (m *Manager) RephraseLastAssistant(key, original, rephrased string) error {
 	}
 
 	// Write atomically and update cache under the same lock.
-	path := m.sessionPath(key)
-	if err := WriteFile(path, sess); err != nil {
+	if err := m.store.Save(sess); err != nil {
 		return fmt.Errorf("rephrase: save session %s: %w", key, err)
 	}
 	m.cache[key] = sess
@@ -267,7 +281,7 @@ func (m *Manager) CreateFork(parentKey, purpose string) (string, error) {
 	forkKey := parentKey + ForkSessionInfix + purpose
 
 	// Load parent session.
-	parent, err := m.loadFromDisk(parentKey)
+	parent, err := m.store.Get(parentKey)
 	if err != nil {
 		return "", fmt.Errorf("fork: load parent %s: %w", parentKey, err)
 	}
@@ -275,9 +289,12 @@ func (m *Manager) CreateFork(parentKey, purpose string) (string, error) {
 	// Strip messages.
 	stripped := ForkMessages(parent.Messages)
 
-	// Delete existing fork session directory (full recreate each time).
+	// Delete existing fork session and its directory (full recreate each time).
 	forkDir := filepath.Dir(m.sessionPath(forkKey))
 	os.RemoveAll(forkDir)
+	if err := m.store.Delete(forkKey); err != nil {
+		return "", fmt.Errorf("fork: delete %s: %w", forkKey, err)
+	}
 
 	// Write fork session.
 	fork := &Session{
@@ -295,52 +312,33 @@ func (m *Manager) sessionPath(key string) string {
 }
 
 // PathForKey returns the on-disk session file path for a session key.
+// With a non-file store the path's directory still holds meta.json and memory.
 func (m *Manager) PathForKey(key string) string {
 	return m.sessionPath(key)
 }
 
-func (m *Manager) loadFromDisk(key string) (*Session, error) {
-	key = normalizeSessionKey(key)
-
-	path := m.sessionPath(key)
-	f, err := os.Open(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			now := time.Now()
-			return &Session{
-				Key:       key,
-				Messages:  []provider.Message{},
-				CreatedAt: now,
-				UpdatedAt: now,
-			}, nil
-		}
-		return nil, err
-	}
-	defer f.Close()
+// Dir returns the directory holding key's meta.json and memory notes.
+func (m *Manager) Dir(key string) string {
+	return SessionDir(m.sessionsDir, key)
+}
 
-	messages, err := readJSONL(f)
-	if err != nil {
-		return nil, err
-	}
-	if messages == nil {
-		messages = []provider.Message{}
-	}
-	messages = provider.SanitizeMessages(messages)
+// Delete removes the session's messages from the store and drops it from the cache.
+func (m *Manager) Delete(key string) error {
+	key = normalizeSessionKey(key)
 
-	s := &Session{
-		Key:      key,
-		Messages: messages,
-	}
-	deriveTimestamps(s)
-	if s.CreatedAt.IsZero() {
-		s.CreatedAt = time.Now()
-	}
-	if s.UpdatedAt.IsZero() {
-		s.UpdatedAt = s.CreatedAt
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.store.Delete(key); err != nil {
+		return err
 	}
+	delete(m.cache, key)
+	return nil
+}
 
-	EnsureMessageIDs(key, s.Messages)
-	return s, nil
+// List returns up to limit stored sessions, most recently updated first.
+// A limit <= 0 returns all sessions.
+func (m *Manager) List(limit int) ([]SessionSummary, error) {
+	return m.store.List(limit)
 }
 
 func normalizeSessionKey(key string) string {
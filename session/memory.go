@@ -0,0 +1,37 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MemoryDirName is the per-session directory holding daily memory notes.
+const MemoryDirName = "memory"
+
+// AppendMemory appends a "## <heading> HH:MM" section to the session's daily
+// memory file (<sessionDir>/memory/YYYY-MM-DD.md) and returns the file path.
+func AppendMemory(sessionDir, heading, content string, now time.Time) (string, error) {
+	memoryDir := filepath.Join(sessionDir, MemoryDirName)
+	if err := os.MkdirAll(memoryDir, 0755); err != nil {
+		return "", fmt.Errorf("create memory directory: %w", err)
+	}
+	memoryFile := filepath.Join(memoryDir, now.Format("2006-01-02")+".md")
+	f, err := os.OpenFile(memoryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("open memory file: %w", err)
+	}
+	defer f.Close()
+
+	// Use separator newline only when appending to existing content.
+	sep := ""
+	if info, _ := f.Stat(); info != nil && info.Size() > 0 {
+		sep = "\n"
+	}
+	header := fmt.Sprintf("%s## %s %s\n\n", sep, heading, now.Format("15:04"))
+	if _, err := f.WriteString(header + content + "\n"); err != nil {
+		return "", fmt.Errorf("write memory file: %w", err)
+	}
+	return memoryFile, nil
+}
@@ -25,8 +25,11 @@ const ForkSessionInfix = ":fork:"
 type Meta struct {
 	Agent     string          `json:"agent,omitempty"`      // Explicitly assigned agent name.
 	Rephrase  bool            `json:"rephrase,omitempty"`   // Enable rephrase agent for this session.
+	DryRun    bool            `json:"dry_run,omitempty"`    // Simulate mutating tools instead of running them.
 	DiscordDM *DiscordDMMeta  `json:"discord_dm,omitempty"` // Discord DM routing.
 	WeCom     *WeComMeta      `json:"wecom,omitempty"`      // WeCom routing.
+	Pins      []Pin           `json:"pins,omitempty"`       // Notes injected into every prompt; see pins.go.
+	Model     *ModelOverride  `json:"model,omitempty"`      // Provider/model used instead of normal routing.
 
 	// TokenEstimateRatios records the last MaxTokenRatioSamples observations of
 	// (real total tokens) / (estimated total tokens) per "provider/model" key.
@@ -42,6 +45,12 @@ type TokenRatioSample struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ModelOverride pins a session to one provider and model (set_model tool).
+type ModelOverride struct {
+	Provider  string `json:"provider"`
+	ModelType string `json:"model_type"`
+}
+
 // DiscordDMMeta holds Discord DM routing metadata.
 type DiscordDMMeta struct {
 	ReplyTo string `json:"reply_to"`
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/provider"
+)
+
+// openSQLiteStore opens {sessionsDir}/sessions.db. When the database does not
+// exist yet, the session.jsonl histories already in sessionsDir are imported
+// first, so switching thread.sessionBackend to sqlite keeps every
+// conversation. The jsonl files are left in place as a fallback copy.
+func openSQLiteStore(sessionsDir string) (Store, error) {
+	path := filepath.Join(sessionsDir, SQLiteFileName)
+	_, statErr := os.Stat(path)
+	fresh := errors.Is(statErr, os.ErrNotExist)
+
+	st, err := NewSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return st, nil
+	}
+	n, err := ImportFileSessions(sessionsDir, st)
+	if err != nil {
+		// Drop the half-filled database so the next start imports again.
+		st.Close()
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			os.Remove(path + suffix)
+		}
+		return nil, fmt.Errorf("import session files into %s: %w", SQLiteFileName, err)
+	}
+	if n > 0 {
+		logger.Info("imported session files into sqlite store", "sessions", n, "db", path)
+	}
+	return st, nil
+}
+
+// ImportFileSessions copies every {sessionsDir}/**/session.jsonl into dst,
+// skipping keys dst already holds, and returns how many were imported.
+// Messages are copied unsanitized so the import is lossless.
+func ImportFileSessions(sessionsDir string, dst Store) (int, error) {
+	imported := 0
+	err := filepath.WalkDir(sessionsDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, fs.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if d.IsDir() || d.Name() != SessionFileName {
+			return nil
+		}
+		rel, err := filepath.Rel(sessionsDir, filepath.Dir(path))
+		if err != nil || rel == "." {
+			return nil
+		}
+		s, err := ReadFileRaw(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", keyFromRelDir(rel), err)
+		}
+		key := storedKey(sessionsDir, filepath.Dir(path), s.Messages)
+		if key == "" {
+			key = keyFromRelDir(rel)
+		}
+		if ok, err := dst.Exists(key); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		s.Key = key
+		if err := dst.Save(s); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		imported++
+		return nil
+	})
+	return imported, err
+}
+
+// storedKey returns the session key recorded in a session file's messages.
+// Directory names are sanitized (see SessionDir), so they cannot always be
+// turned back into the key; message IDs embed the key they were written
+// under (see generateMessageID). The newest ID whose key maps back to dir
+// wins, which skips IDs a fork or copy carried over from another session.
+// Returns "" when no message records a matching key.
+func storedKey(sessionsDir, dir string, messages []provider.Message) string {
+	dir = filepath.Clean(dir)
+	for i := len(messages) - 1; i >= 0; i-- {
+		key := keyFromMessageID(messages[i].ID)
+		if key != "" && filepath.Clean(SessionDir(sessionsDir, key)) == dir {
+			return key
+		}
+	}
+	return ""
+}
+
+// keyFromMessageID strips the unixMillis and seq suffixes from a message ID
+// ("telegram:123:1709571234567:000001" → "telegram:123").
+func keyFromMessageID(id string) string {
+	parts := strings.Split(id, ":")
+	if len(parts) < 3 {
+		return ""
+	}
+	for _, p := range parts[len(parts)-2:] {
+		if _, err := strconv.ParseInt(p, 10, 64); err != nil {
+			return ""
+		}
+	}
+	return strings.Join(parts[:len(parts)-2], ":")
+}
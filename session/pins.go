@@ -0,0 +1,81 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Bounds on a session's pinned notes. Pins are sent with every request, so
+// they are kept small.
+const (
+	MaxPins        = 20
+	MaxPinnedRunes = 4000 // all pins together
+)
+
+// Pin is a note kept in every system prompt of its session. Pins live in
+// meta.json, outside the message history, so compaction never touches them.
+type Pin struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReadPins returns the pinned notes of the session in sessionDir, oldest first.
+func ReadPins(sessionDir string) []Pin {
+	return ReadMeta(sessionDir).Pins
+}
+
+// AddPin pins text to the session in sessionDir. It fails when the session
+// already has MaxPins pins or the pins would exceed MaxPinnedRunes.
+func AddPin(sessionDir, text string) (Pin, error) {
+	text = strings.TrimSpace(text)
+	if sessionDir == "" {
+		return Pin{}, fmt.Errorf("no session directory")
+	}
+	if text == "" {
+		return Pin{}, fmt.Errorf("pin text is empty")
+	}
+	var pin Pin
+	var err error
+	UpdateMeta(sessionDir, func(m *Meta) {
+		if len(m.Pins) >= MaxPins {
+			err = fmt.Errorf("session already has %d pins (max %d); unpin one first", len(m.Pins), MaxPins)
+			return
+		}
+		total := utf8.RuneCountInString(text)
+		next := 1
+		for _, p := range m.Pins {
+			total += utf8.RuneCountInString(p.Text)
+			if n, convErr := strconv.Atoi(strings.TrimPrefix(p.ID, "p")); convErr == nil && n >= next {
+				next = n + 1
+			}
+		}
+		if total > MaxPinnedRunes {
+			err = fmt.Errorf("pins would total %d characters (max %d); shorten the note or unpin others", total, MaxPinnedRunes)
+			return
+		}
+		pin = Pin{ID: "p" + strconv.Itoa(next), Text: text, CreatedAt: time.Now()}
+		m.Pins = append(m.Pins, pin)
+	})
+	return pin, err
+}
+
+// RemovePin unpins id from the session in sessionDir. Reports whether a pin
+// was removed.
+func RemovePin(sessionDir, id string) bool {
+	id = strings.TrimSpace(id)
+	removed := false
+	UpdateMeta(sessionDir, func(m *Meta) {
+		for i, p := range m.Pins {
+			if p.ID == id {
+				m.Pins = append(m.Pins[:i], m.Pins[i+1:]...)
+				removed = true
+				return
+			}
+		}
+	})
+	return removed
+}
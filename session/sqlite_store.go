@@ -0,0 +1,248 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// sqliteSchema keeps one row per session plus one row per message. Messages
+// are stored as the same JSON a session.jsonl line holds, so the two backends
+// round-trip identically. The updated_at index makes "most recently active
+// sessions" an index scan instead of a walk over every session.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	key           TEXT PRIMARY KEY,
+	created_at    INTEGER NOT NULL,
+	updated_at    INTEGER NOT NULL,
+	message_count INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS sessions_updated_at ON sessions(updated_at DESC);
+CREATE TABLE IF NOT EXISTS messages (
+	session_key TEXT NOT NULL,
+	seq         INTEGER NOT NULL,
+	data        TEXT NOT NULL,
+	PRIMARY KEY (session_key, seq)
+);
+`
+
+// SQLiteStore keeps all sessions in a single SQLite database.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if needed) the database at path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open session db: %w", err)
+	}
+	// A single connection serializes writers inside the process and avoids
+	// SQLITE_BUSY between our own goroutines.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init session db: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Get implements Store.
+func (st *SQLiteStore) Get(key string) (*Session, error) {
+	return st.load(key, true)
+}
+
+// GetRaw implements Store.
+func (st *SQLiteStore) GetRaw(key string) (*Session, error) {
+	return st.load(key, false)
+}
+
+// Exists implements Store.
+func (st *SQLiteStore) Exists(key string) (bool, error) {
+	var one int
+	err := st.db.QueryRow(`SELECT 1 FROM sessions WHERE key = ?`, normalizeSessionKey(key)).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (st *SQLiteStore) load(key string, sanitize bool) (*Session, error) {
+	key = normalizeSessionKey(key)
+
+	var createdAt, updatedAt int64
+	err := st.db.QueryRow(`SELECT created_at, updated_at FROM sessions WHERE key = ?`, key).Scan(&createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return newEmptySession(key), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := st.db.Query(`SELECT data FROM messages WHERE session_key = ? ORDER BY seq`, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []provider.Message
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var msg provider.Message
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			continue // same tolerance as readJSONL
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return finishLoad(key, messages, time.Unix(0, createdAt), time.Unix(0, updatedAt), sanitize), nil
+}
+
+// Save implements Store.
+func (st *SQLiteStore) Save(s *Session) error {
+	key := normalizeSessionKey(s.Key)
+	EnsureMessageIDs(key, s.Messages)
+
+	tx, err := st.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM messages WHERE session_key = ?`, key); err != nil {
+		return err
+	}
+	if err := insertMessages(tx, key, 0, s.Messages); err != nil {
+		return err
+	}
+
+	createdAt, updatedAt := s.CreatedAt, s.UpdatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO sessions (key, created_at, updated_at, message_count) VALUES (?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET updated_at = excluded.updated_at, message_count = excluded.message_count`,
+		key, createdAt.UnixNano(), updatedAt.UnixNano(), len(s.Messages)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Append implements Store.
+func (st *SQLiteStore) Append(key string, msgs []provider.Message) error {
+	key = normalizeSessionKey(key)
+
+	tx, err := st.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var next int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(seq) + 1, 0) FROM messages WHERE session_key = ?`, key).Scan(&next); err != nil {
+		return err
+	}
+	if err := insertMessages(tx, key, next, msgs); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	updatedAt := now
+	if last := msgs[len(msgs)-1].Timestamp; !last.IsZero() {
+		updatedAt = last
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO sessions (key, created_at, updated_at, message_count) VALUES (?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET updated_at = excluded.updated_at, message_count = message_count + ?`,
+		key, now.UnixNano(), updatedAt.UnixNano(), len(msgs), len(msgs)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete implements Store.
+func (st *SQLiteStore) Delete(key string) error {
+	key = normalizeSessionKey(key)
+
+	tx, err := st.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM messages WHERE session_key = ?`, key); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE key = ?`, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// List implements Store from the updated_at index.
+func (st *SQLiteStore) List(limit int) ([]SessionSummary, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := st.db.Query(`SELECT key, message_count, updated_at FROM sessions ORDER BY updated_at DESC, key LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SessionSummary
+	for rows.Next() {
+		var sum SessionSummary
+		var updatedAt int64
+		if err := rows.Scan(&sum.Key, &sum.MessageCount, &updatedAt); err != nil {
+			return nil, err
+		}
+		sum.Channel = KeyChannel(sum.Key)
+		sum.UpdatedAt = time.Unix(0, updatedAt)
+		out = append(out, sum)
+	}
+	return out, rows.Err()
+}
+
+// Close implements Store.
+func (st *SQLiteStore) Close() error {
+	return st.db.Close()
+}
+
+func insertMessages(tx *sql.Tx, key string, seq int, msgs []provider.Message) error {
+	stmt, err := tx.Prepare(`INSERT INTO messages (session_key, seq, data) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, msg := range msgs {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(key, seq, string(data)); err != nil {
+			return err
+		}
+		seq++
+	}
+	return nil
+}
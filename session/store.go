@@ -0,0 +1,282 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// Store persists session messages. Manager layers its in-memory cache on top
+// of a Store; per-session meta.json and memory notes stay in the session
+// directory regardless of the backend.
+type Store interface {
+	// Get loads the session for key. A missing session yields an empty one.
+	Get(key string) (*Session, error)
+	// GetRaw loads the session for key without sanitizing its messages, so
+	// in-progress tool calls survive a rewrite (compress-session).
+	GetRaw(key string) (*Session, error)
+	// Exists reports whether messages are stored for key.
+	Exists(key string) (bool, error)
+	// Save replaces the full message list of s.
+	Save(s *Session) error
+	// Append adds messages to the end of the session, creating it if needed.
+	Append(key string, msgs []provider.Message) error
+	// Delete removes the session's messages. Deleting a missing key is not an error.
+	Delete(key string) error
+	// List returns up to limit sessions, most recently updated first.
+	// A limit <= 0 returns all sessions.
+	List(limit int) ([]SessionSummary, error)
+	// Close releases resources held by the store.
+	Close() error
+}
+
+// SessionSummary is a lightweight view of a stored session for listings.
+type SessionSummary struct {
+	Key          string    `json:"key"`
+	Channel      string    `json:"channel"` // channel the session's messages arrive on (key prefix)
+	MessageCount int       `json:"message_count"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Session storage backends selectable via config (thread.sessionBackend).
+const (
+	BackendFile   = "file"
+	BackendSQLite = "sqlite"
+)
+
+// SQLiteFileName is the database file the SQLite backend keeps in the sessions directory.
+const SQLiteFileName = "sessions.db"
+
+// OpenStore opens the named backend rooted at sessionsDir.
+// An empty backend selects the file store.
+func OpenStore(backend, sessionsDir string) (Store, error) {
+	switch backend {
+	case "", BackendFile:
+		return NewFileStore(sessionsDir)
+	case BackendSQLite:
+		return openSQLiteStore(sessionsDir)
+	default:
+		return nil, errors.New("unknown session backend: " + backend)
+	}
+}
+
+// FileStore keeps each session as {sessionsDir}/{key path}/session.jsonl.
+type FileStore struct {
+	sessionsDir string
+}
+
+// NewFileStore creates a file store rooted at sessionsDir.
+func NewFileStore(sessionsDir string) (*FileStore, error) {
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{sessionsDir: sessionsDir}, nil
+}
+
+func (st *FileStore) path(key string) string {
+	return filepath.Join(SessionDir(st.sessionsDir, key), SessionFileName)
+}
+
+// Get implements Store.
+func (st *FileStore) Get(key string) (*Session, error) {
+	return st.load(key, true)
+}
+
+// GetRaw implements Store.
+func (st *FileStore) GetRaw(key string) (*Session, error) {
+	return st.load(key, false)
+}
+
+func (st *FileStore) load(key string, sanitize bool) (*Session, error) {
+	key = normalizeSessionKey(key)
+	f, err := os.Open(st.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return newEmptySession(key), nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	messages, err := readJSONL(f)
+	if err != nil {
+		return nil, err
+	}
+	return finishLoad(key, messages, time.Time{}, time.Time{}, sanitize), nil
+}
+
+// Exists implements Store.
+func (st *FileStore) Exists(key string) (bool, error) {
+	_, err := os.Stat(st.path(normalizeSessionKey(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Save implements Store.
+func (st *FileStore) Save(s *Session) error {
+	return WriteFile(st.path(s.Key), s)
+}
+
+// Append implements Store.
+func (st *FileStore) Append(key string, msgs []provider.Message) error {
+	path := st.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeJSONL(f, msgs)
+}
+
+// Delete implements Store. Only session.jsonl is removed; meta.json and
+// memory notes in the session directory are left alone.
+func (st *FileStore) Delete(key string) error {
+	err := os.Remove(st.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List implements Store by walking the sessions directory. Cost grows with
+// the number of sessions; the SQLite store answers from an index instead.
+func (st *FileStore) List(limit int) ([]SessionSummary, error) {
+	var out []SessionSummary
+	err := filepath.WalkDir(st.sessionsDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, fs.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if d.IsDir() || d.Name() != SessionFileName {
+			return nil
+		}
+		rel, err := filepath.Rel(st.sessionsDir, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		updatedAt, _ := ReadUpdatedAt(path)
+		if updatedAt.IsZero() {
+			if info, err := d.Info(); err == nil {
+				updatedAt = info.ModTime()
+			}
+		}
+		key := keyFromRelDir(rel)
+		out = append(out, SessionSummary{
+			Key:          key,
+			Channel:      KeyChannel(key),
+			MessageCount: countLines(path),
+			UpdatedAt:    updatedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSummaries(out)
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Close implements Store.
+func (st *FileStore) Close() error { return nil }
+
+// keyFromRelDir turns a session directory relative to sessionsDir back into
+// a session key ("telegram/123" → "telegram:123").
+func keyFromRelDir(rel string) string {
+	return strings.ReplaceAll(filepath.ToSlash(rel), "/", ":")
+}
+
+// KeyChannel returns the channel part of a session key: the segment before
+// the first ':' ("telegram:123" → "telegram", "cli:threads:x" → "cli").
+func KeyChannel(key string) string {
+	key = normalizeSessionKey(key)
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// countLines counts non-empty lines in a session file without decoding them.
+func countLines(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	n := 0
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// sortSummaries orders summaries most recently updated first, ties by key.
+func sortSummaries(out []SessionSummary) {
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].Key < out[j].Key
+	})
+}
+
+// newEmptySession returns a fresh session with no messages.
+func newEmptySession(key string) *Session {
+	now := time.Now()
+	return &Session{
+		Key:       key,
+		Messages:  []provider.Message{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// finishLoad builds a Session from stored messages: sanitizes them (unless
+// sanitize is false), derives timestamps (falling back to the given ones) and
+// backfills message IDs.
+func finishLoad(key string, messages []provider.Message, createdAt, updatedAt time.Time, sanitize bool) *Session {
+	if messages == nil {
+		messages = []provider.Message{}
+	}
+	if sanitize {
+		messages = provider.SanitizeMessages(messages)
+	}
+
+	s := &Session{
+		Key:       key,
+		Messages:  messages,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+	deriveTimestamps(s)
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = s.CreatedAt
+	}
+
+	EnsureMessageIDs(key, s.Messages)
+	return s
+}
@@ -0,0 +1,357 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// storeBackends opens each Store implementation in a fresh temp directory so
+// the same contract runs against all of them.
+var storeBackends = map[string]func(t *testing.T) Store{
+	BackendFile: func(t *testing.T) Store {
+		st, err := NewFileStore(filepath.Join(t.TempDir(), "sessions"))
+		if err != nil {
+			t.Fatalf("NewFileStore() error = %v", err)
+		}
+		return st
+	},
+	BackendSQLite: func(t *testing.T) Store {
+		st, err := NewSQLiteStore(filepath.Join(t.TempDir(), "sessions", SQLiteFileName))
+		if err != nil {
+			t.Fatalf("NewSQLiteStore() error = %v", err)
+		}
+		t.Cleanup(func() { st.Close() })
+		return st
+	},
+}
+
+func forEachStore(t *testing.T, fn func(t *testing.T, st Store)) {
+	for name, open := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			fn(t, open(t))
+		})
+	}
+}
+
+func TestStoreGetMissingReturnsEmpty(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		s, err := st.Get("nobody:here")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if s.Key != "nobody:here" || len(s.Messages) != 0 {
+			t.Fatalf("Get() = %+v, want empty session", s)
+		}
+	})
+}
+
+func TestStoreSaveGetRoundTripsMessageJSON(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		ts := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		in := &Session{
+			Key: "telegram:42",
+			Messages: []provider.Message{
+				{Role: "user", Content: "weather?", Timestamp: ts},
+				{Role: "assistant", Content: "", ToolCalls: []provider.ToolCall{{
+					ID: "call_1", Type: "function",
+					Function: provider.FunctionCall{Name: "web_search", Arguments: `{"q":"weather"}`},
+				}}, Timestamp: ts.Add(time.Second)},
+				{Role: "tool", ToolCallID: "call_1", Name: "web_search", Content: "sunny", Timestamp: ts.Add(2 * time.Second)},
+				{Role: "assistant", Content: "Sunny.", Compressed: "sunny", Timestamp: ts.Add(3 * time.Second)},
+			},
+		}
+		if err := st.Save(in); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		got, err := st.Get("telegram:42")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(got.Messages) != len(in.Messages) {
+			t.Fatalf("got %d messages, want %d", len(got.Messages), len(in.Messages))
+		}
+		for i := range in.Messages {
+			want, have := in.Messages[i], got.Messages[i]
+			if have.ID != want.ID || have.Role != want.Role || have.Content != want.Content ||
+				have.ToolCallID != want.ToolCallID || have.Compressed != want.Compressed ||
+				!have.Timestamp.Equal(want.Timestamp) || len(have.ToolCalls) != len(want.ToolCalls) {
+				t.Fatalf("message %d = %+v, want %+v", i, have, want)
+			}
+		}
+		if got.Messages[1].ToolCalls[0].Function.Arguments != `{"q":"weather"}` {
+			t.Fatalf("tool call arguments = %q", got.Messages[1].ToolCalls[0].Function.Arguments)
+		}
+		if !got.CreatedAt.Equal(ts) || !got.UpdatedAt.Equal(ts.Add(3*time.Second)) {
+			t.Fatalf("timestamps = %v/%v, want derived from messages", got.CreatedAt, got.UpdatedAt)
+		}
+	})
+}
+
+func TestStoreRoundTripsReasoningContent(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		in := &Session{
+			Key: "telegram:7",
+			Messages: []provider.Message{
+				provider.UserMessage("2+2?"),
+				{Role: "assistant", Content: "4", ReasoningContent: "add two and two"},
+			},
+		}
+		if err := st.Save(in); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		got, err := st.Get("telegram:7")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(got.Messages) != 2 || got.Messages[1].ReasoningContent != "add two and two" {
+			t.Fatalf("messages = %+v, want reasoning_content kept", got.Messages)
+		}
+	})
+}
+
+func TestStoreSaveReplacesMessages(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		key := "discord:7"
+		if err := st.Save(&Session{Key: key, Messages: []provider.Message{
+			provider.UserMessage("one"), provider.AssistantMessage("two"),
+		}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := st.Save(&Session{Key: key, Messages: []provider.Message{provider.UserMessage("only")}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		got, err := st.Get(key)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(got.Messages) != 1 || got.Messages[0].Content != "only" {
+			t.Fatalf("Messages = %+v, want single 'only'", got.Messages)
+		}
+	})
+}
+
+func TestStoreAppendExtendsSession(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		key := "cli"
+		first := []provider.Message{provider.UserMessage("hi")}
+		second := []provider.Message{provider.AssistantMessage("hello"), provider.UserMessage("bye")}
+		EnsureMessageIDs(key, first)
+		EnsureMessageIDs(key, second)
+		if err := st.Append(key, first); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if err := st.Append(key, second); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		got, err := st.Get(key)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		want := []string{"hi", "hello", "bye"}
+		if len(got.Messages) != len(want) {
+			t.Fatalf("got %d messages, want %d", len(got.Messages), len(want))
+		}
+		for i, c := range want {
+			if got.Messages[i].Content != c {
+				t.Fatalf("Messages[%d].Content = %q, want %q", i, got.Messages[i].Content, c)
+			}
+		}
+	})
+}
+
+func TestStoreDelete(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		key := "feishu:abc"
+		if err := st.Save(&Session{Key: key, Messages: []provider.Message{provider.UserMessage("x")}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := st.Delete(key); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		got, err := st.Get(key)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(got.Messages) != 0 {
+			t.Fatalf("Messages after Delete = %+v, want none", got.Messages)
+		}
+		if err := st.Delete(key); err != nil {
+			t.Fatalf("Delete() of missing key error = %v", err)
+		}
+		list, err := st.List(0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(list) != 0 {
+			t.Fatalf("List() after Delete = %+v, want empty", list)
+		}
+	})
+}
+
+func TestStoreListMostRecentFirst(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		for i, offset := range []int{2, 0, 3, 1} {
+			key := fmt.Sprintf("telegram:%d", i)
+			msgs := []provider.Message{
+				{Role: "user", Content: "a", Timestamp: base},
+				{Role: "assistant", Content: "b", Timestamp: base.Add(time.Duration(offset) * time.Hour)},
+			}
+			if err := st.Save(&Session{Key: key, Messages: msgs, UpdatedAt: msgs[1].Timestamp}); err != nil {
+				t.Fatalf("Save(%s) error = %v", key, err)
+			}
+		}
+
+		all, err := st.List(0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		wantOrder := []string{"telegram:2", "telegram:0", "telegram:3", "telegram:1"}
+		if len(all) != len(wantOrder) {
+			t.Fatalf("List() returned %d sessions, want %d", len(all), len(wantOrder))
+		}
+		for i, key := range wantOrder {
+			if all[i].Key != key {
+				t.Fatalf("List()[%d].Key = %q, want %q (all=%+v)", i, all[i].Key, key, all)
+			}
+			if all[i].Channel != "telegram" {
+				t.Fatalf("List()[%d].Channel = %q, want telegram", i, all[i].Channel)
+			}
+			if all[i].MessageCount != 2 {
+				t.Fatalf("List()[%d].MessageCount = %d, want 2", i, all[i].MessageCount)
+			}
+		}
+
+		top, err := st.List(2)
+		if err != nil {
+			t.Fatalf("List(2) error = %v", err)
+		}
+		if len(top) != 2 || top[0].Key != "telegram:2" || top[1].Key != "telegram:0" {
+			t.Fatalf("List(2) = %+v, want telegram:2, telegram:0", top)
+		}
+	})
+}
+
+func TestManagerWithStoreReloadAndDelete(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		mgr, err := NewManagerWithStore(filepath.Join(t.TempDir(), "sessions"), st)
+		if err != nil {
+			t.Fatalf("NewManagerWithStore() error = %v", err)
+		}
+		key := "web:u1"
+		if err := mgr.Append(key, provider.UserMessage("hi")); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		got, err := mgr.Reload(key)
+		if err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		if len(got.Messages) != 1 || got.Messages[0].Content != "hi" {
+			t.Fatalf("Reload().Messages = %+v", got.Messages)
+		}
+		if err := mgr.Delete(key); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		got, err = mgr.Get(key)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if len(got.Messages) != 0 {
+			t.Fatalf("Get() after Delete = %+v, want empty", got.Messages)
+		}
+	})
+}
+
+func TestStoreExistsAndGetRaw(t *testing.T) {
+	forEachStore(t, func(t *testing.T, st Store) {
+		key := "telegram:7"
+		if ok, err := st.Exists(key); err != nil || ok {
+			t.Fatalf("Exists() before save = %v, %v; want false", ok, err)
+		}
+		// An unanswered tool call: Get sanitizes it away, GetRaw keeps it.
+		pending := provider.Message{Role: "assistant", ToolCalls: []provider.ToolCall{{
+			ID: "call_1", Type: "function", Function: provider.FunctionCall{Name: "exec", Arguments: "{}"},
+		}}}
+		if err := st.Append(key, []provider.Message{provider.UserMessage("run it"), pending}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if ok, err := st.Exists(key); err != nil || !ok {
+			t.Fatalf("Exists() after append = %v, %v; want true", ok, err)
+		}
+		raw, err := st.GetRaw(key)
+		if err != nil {
+			t.Fatalf("GetRaw() error = %v", err)
+		}
+		if len(raw.Messages) != 2 || len(raw.Messages[1].ToolCalls) != 1 {
+			t.Fatalf("GetRaw().Messages = %+v, want the pending tool call kept", raw.Messages)
+		}
+	})
+}
+
+func TestOpenSQLiteStoreImportsSessionFiles(t *testing.T) {
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	for key, text := range map[string]string{"telegram:42": "hello", "cli:threads:t1": "subtask"} {
+		s := &Session{Key: key, Messages: []provider.Message{provider.UserMessage(text)}}
+		if err := WriteFile(filepath.Join(SessionDir(sessionsDir, key), SessionFileName), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := OpenStore(BackendSQLite, sessionsDir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	got, err := st.Get("cli:threads:t1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Content != "subtask" {
+		t.Fatalf("imported Messages = %+v", got.Messages)
+	}
+	list, err := st.List(0)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List() = %+v, %v; want both sessions", list, err)
+	}
+
+	// Only a fresh database imports: a session deleted afterwards stays gone.
+	if err := st.Delete("telegram:42"); err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+	st, err = OpenStore(BackendSQLite, sessionsDir)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer st.Close()
+	if ok, _ := st.Exists("telegram:42"); ok {
+		t.Error("reopening the database imported session files again")
+	}
+}
+
+func TestImportFileSessionsKeepsStoredKey(t *testing.T) {
+	sessionsDir := filepath.Join(t.TempDir(), "sessions")
+	// The directory sanitizes "@" and " " away; the message IDs keep the key.
+	key := "feishu:ou@x y"
+	s := &Session{Key: key, Messages: []provider.Message{provider.UserMessage("hi")}}
+	if err := WriteFile(filepath.Join(SessionDir(sessionsDir, key), SessionFileName), s); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := NewSQLiteStore(filepath.Join(t.TempDir(), SQLiteFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if n, err := ImportFileSessions(sessionsDir, st); err != nil || n != 1 {
+		t.Fatalf("ImportFileSessions() = %d, %v; want 1", n, err)
+	}
+	if ok, _ := st.Exists(key); !ok {
+		list, _ := st.List(0)
+		t.Errorf("imported sessions = %+v, want key %q", list, key)
+	}
+}
@@ -286,13 +286,15 @@ func loadYAMLSkill(path string) (*Skill, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseYAMLSkill(data, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+}
 
+func parseYAMLSkill(data []byte, slug string) (*Skill, error) {
 	var skill Skill
 	if err := yaml.Unmarshal(data, &skill); err != nil {
 		return nil, err
 	}
 
-	slug := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 	skill.Slug = slug
 	if skill.Name == "" {
 		skill.Name = slug
@@ -308,7 +310,10 @@ func loadMarkdownSkill(path string, slug string) (*Skill, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseMarkdownSkill(data, slug)
+}
 
+func parseMarkdownSkill(data []byte, slug string) (*Skill, error) {
 	content := string(data)
 
 	// Check for frontmatter
@@ -344,6 +349,66 @@ func loadMarkdownSkill(path string, slug string) (*Skill, error) {
 	return &skill, nil
 }
 
+// isYAMLSkillFile reports whether fileName is a flat YAML skill.
+func isYAMLSkillFile(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// ParseSkill parses skill content the way the directory loader does: a
+// .yaml/.yml fileName is a YAML skill, anything else is Markdown with
+// optional frontmatter. slug is the invocation key.
+func ParseSkill(fileName, slug string, data []byte) (*Skill, error) {
+	if isYAMLSkillFile(fileName) {
+		return parseYAMLSkill(data, slug)
+	}
+	return parseMarkdownSkill(data, slug)
+}
+
+// Validate reports an error when the skill lacks a name or prompt.
+func (s *Skill) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("skill has no name")
+	}
+	if strings.TrimSpace(s.Prompt) == "" {
+		return fmt.Errorf("skill %q has no prompt", s.Name)
+	}
+	return nil
+}
+
+// Install parses and validates skill content, writes it into dir so it is
+// picked up on restart, and registers it. Markdown skills are stored as
+// dir/{slug}/SKILL.md, YAML skills as dir/{slug}.yaml.
+func (r *Registry) Install(dir, fileName, slug string, data []byte) (*Skill, error) {
+	skill, err := ParseSkill(fileName, slug, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := skill.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, slug+".yaml")
+	if !isYAMLSkillFile(fileName) {
+		skill.Dir = filepath.Join(dir, slug)
+		path = filepath.Join(skill.Dir, "SKILL.md")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	r.Register(skill)
+	return skill, nil
+}
+
 // BuildPromptSection builds a compact skill summary for the system prompt.
 // Full skill prompts are loaded on demand via the use_skill tool.
 func (r *Registry) BuildPromptSection() string {
@@ -376,6 +441,16 @@ func (r *Registry) SkillNames() []string {
 	return names
 }
 
+// SkillDescription returns the description of a skill by slug, or "" if unknown.
+func (r *Registry) SkillDescription(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s, ok := r.skills[name]; ok {
+		return s.Description
+	}
+	return ""
+}
+
 // GetSkillPrompt returns the full prompt and directory for a skill by slug.
 func (r *Registry) GetSkillPrompt(name string) (prompt string, dir string, ok bool) {
 	r.mu.RLock()
@@ -0,0 +1,56 @@
+package tgmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// ErrNoTable is returned by TableToCSV when the input has no GFM table.
+var ErrNoTable = errors.New("no markdown table found")
+
+// TableToCSV extracts every GFM table in markdown as CSV text, one string per
+// table in document order. The header row comes first; short rows are padded
+// to the widest row, matching what Convert renders. Escaped pipes (\|) become
+// literal pipes in the cell.
+func TableToCSV(markdown string) ([]string, error) {
+	source := []byte(markdown)
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	doc := md.Parser().Parse(text.NewReader(source))
+	r := &renderer{source: source}
+
+	var tables []string
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		t, ok := n.(*east.Table)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		headers, dataRows := r.tableCells(t)
+		if len(headers) == 0 {
+			return ast.WalkSkipChildren, nil
+		}
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		if err := w.Write(headers); err != nil {
+			return ast.WalkStop, err
+		}
+		if err := w.WriteAll(dataRows); err != nil {
+			return ast.WalkStop, err
+		}
+		tables = append(tables, b.String())
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, ErrNoTable
+	}
+	return tables, nil
+}
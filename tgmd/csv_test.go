@@ -0,0 +1,37 @@
+package tgmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTableToCSV(t *testing.T) {
+	md := "Intro text.\n\n| Name | Note |\n|------|------|\n| Alice | a \\| b |\n| Bob, Jr. |\n"
+	got, err := TableToCSV(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("tables = %d, want 1", len(got))
+	}
+	expect(t, got[0], "Name,Note\nAlice,a | b\n\"Bob, Jr.\",\n")
+}
+
+func TestTableToCSVCJK(t *testing.T) {
+	md := "| 名前 | 年齢 |\n|------|------|\n| 太郎 | 30 |\n\n| 都市 |\n|---|\n| 東京 |"
+	got, err := TableToCSV(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("tables = %d, want 2", len(got))
+	}
+	expect(t, got[0], "名前,年齢\n太郎,30\n")
+	expect(t, got[1], "都市\n東京\n")
+}
+
+func TestTableToCSVNoTable(t *testing.T) {
+	if _, err := TableToCSV("just **text**"); !errors.Is(err, ErrNoTable) {
+		t.Errorf("err = %v, want ErrNoTable", err)
+	}
+}
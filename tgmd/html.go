@@ -0,0 +1,173 @@
+package tgmd
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Raw HTML in the Markdown source (inline tags and HTML blocks) is sanitized
+// rather than escaped wholesale: tags Telegram supports pass through, rebuilt
+// with only validated attributes, and everything else is escaped so it shows
+// as literal text. Allowed tags must nest correctly; an unmatched closing tag
+// is escaped and tags left open are closed at the end of their container:
+// the paragraph for inline tags, the document for HTML blocks.
+
+// rawTag matches one complete tag token and captures the closing slash, the
+// name, the attribute text, and a trailing self-closing slash.
+var rawTag = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:\s+[^\s"'=/>]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*)\s*(/?)>$`)
+
+// rawAttr matches one attribute and captures its name and its value
+// (double-quoted, single-quoted, or bare).
+var rawAttr = regexp.MustCompile(`([^\s"'=/>]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+
+// rawEntity matches the character references Telegram understands.
+var rawEntity = regexp.MustCompile(`^&(?:lt|gt|amp|quot|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+// codeClass is the only attribute allowed on <code>.
+var codeClass = regexp.MustCompile(`^language-[A-Za-z0-9_+#.-]+$`)
+
+// allowedTags is Telegram's HTML subset accepted from raw HTML.
+var allowedTags = map[string]bool{
+	"b": true, "i": true, "u": true, "s": true, "code": true, "pre": true,
+	"a": true, "blockquote": true, "tg-spoiler": true,
+}
+
+// htmlSanitizer tracks the raw HTML tags opened so far so closers can be
+// matched and leftovers closed.
+type htmlSanitizer struct {
+	open []string
+}
+
+// tag rebuilds one raw tag token. ok is false when the token is not an
+// allowed, well-placed tag and must be escaped instead. base is the stack
+// depth of the enclosing container; closers cannot reach below it.
+func (s *htmlSanitizer) tag(raw string, base int) (string, bool) {
+	m := rawTag.FindStringSubmatch(raw)
+	if m == nil {
+		return "", false
+	}
+	closing, name, attrs, selfClosing := m[1] == "/", strings.ToLower(m[2]), m[3], m[4] == "/"
+	if !allowedTags[name] || selfClosing {
+		return "", false
+	}
+
+	if closing {
+		if strings.TrimSpace(attrs) != "" || len(s.open) <= base || s.open[len(s.open)-1] != name {
+			return "", false
+		}
+		s.open = s.open[:len(s.open)-1]
+		return "</" + name + ">", true
+	}
+
+	// Telegram entities can't nest inside code, pre (except <pre><code>) or a link in a link.
+	if n := len(s.open); n > 0 {
+		top := s.open[n-1]
+		if top == "code" || (top == "pre" && name != "code") {
+			return "", false
+		}
+	}
+	if name == "a" && s.isOpen("a") {
+		return "", false
+	}
+
+	out, ok := rebuildTag(name, attrs)
+	if ok {
+		s.open = append(s.open, name)
+	}
+	return out, ok
+}
+
+func (s *htmlSanitizer) isOpen(name string) bool {
+	for _, t := range s.open {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// closeTo closes every tag opened above depth base.
+func (s *htmlSanitizer) closeTo(base int) string {
+	var b strings.Builder
+	for len(s.open) > base {
+		b.WriteString("</" + s.open[len(s.open)-1] + ">")
+		s.open = s.open[:len(s.open)-1]
+	}
+	return b.String()
+}
+
+// rebuildTag renders an opening tag with only the attributes Telegram accepts:
+// href on <a> (http, https, tg or mailto; required), a language-* class on
+// <code>, and expandable on <blockquote>. Other attributes are dropped.
+func rebuildTag(name, attrs string) (string, bool) {
+	var out string
+	for _, a := range rawAttr.FindAllStringSubmatch(attrs, -1) {
+		key := strings.ToLower(a[1])
+		val := html.UnescapeString(a[2] + a[3] + a[4])
+		switch {
+		case name == "a" && key == "href" && safeHref(val):
+			out = ` href="` + escapeAttr(val) + `"`
+		case name == "code" && key == "class" && codeClass.MatchString(val):
+			out = ` class="` + val + `"`
+		case name == "blockquote" && key == "expandable":
+			out = " expandable"
+		}
+	}
+	if name == "a" && out == "" {
+		return "", false
+	}
+	return "<" + name + out + ">", true
+}
+
+// safeHref reports whether href uses a scheme Telegram links may carry.
+func safeHref(href string) bool {
+	lower := strings.ToLower(strings.TrimSpace(href))
+	for _, scheme := range []string{"http://", "https://", "tg://", "mailto:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeAttr(s string) string {
+	return strings.ReplaceAll(escapeHTML(s), `"`, "&quot;")
+}
+
+// block sanitizes the source of an HTML block: allowed tags pass through,
+// other tags and stray '<' and '>' are escaped, and '&' is escaped unless it
+// starts a character reference. Tags it leaves open stay open across the
+// following blocks (e.g. a <blockquote> line, Markdown, then </blockquote>)
+// until matched or closed at the end of the document.
+func (s *htmlSanitizer) block(src string) string {
+	var b strings.Builder
+	b.Grow(len(src))
+	for i := 0; i < len(src); {
+		switch src[i] {
+		case '<':
+			if end := strings.IndexAny(src[i+1:], "<>"); end >= 0 && src[i+1+end] == '>' {
+				tok := src[i : i+end+2]
+				if out, ok := s.tag(tok, 0); ok {
+					b.WriteString(out)
+					i += len(tok)
+					continue
+				}
+			}
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '&':
+			if ent := rawEntity.FindString(src[i:]); ent != "" {
+				b.WriteString(ent)
+				i += len(ent)
+				continue
+			}
+			b.WriteString("&amp;")
+		default:
+			b.WriteByte(src[i])
+		}
+		i++
+	}
+	return b.String()
+}
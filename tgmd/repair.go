@@ -0,0 +1,92 @@
+package tgmd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// telegramErrOffset extracts the byte offset from Telegram's entity parse
+// errors, e.g. `can't parse entities: Unexpected end tag at byte offset 42`.
+var telegramErrOffset = regexp.MustCompile(`byte offset (\d+)`)
+
+// IsParseError reports whether err text is Telegram rejecting the HTML markup
+// (as opposed to a network or permission failure).
+func IsParseError(errText string) bool {
+	return strings.Contains(strings.ToLower(errText), "can't parse entities")
+}
+
+// Repair attempts to fix HTML that Telegram rejected with errText. When the
+// error names a byte offset, only the tag at that offset is touched: an
+// unsupported tag is escaped so it shows as literal text, any other tag is
+// removed (keeping its content). Otherwise the whole string gets a balancing
+// pass. Returns the repaired HTML and whether anything changed.
+func Repair(html, errText string) (string, bool) {
+	if m := telegramErrOffset.FindStringSubmatch(errText); m != nil {
+		if off, err := strconv.Atoi(m[1]); err == nil {
+			if fixed, ok := repairAt(html, off, strings.Contains(errText, "Unsupported start tag")); ok {
+				return fixed, true
+			}
+		}
+	}
+	fixed := Balance(html)
+	return fixed, fixed != html
+}
+
+// repairAt fixes the tag starting at byte offset off.
+func repairAt(html string, off int, escape bool) (string, bool) {
+	if off < 0 || off >= len(html) || html[off] != '<' {
+		return html, false
+	}
+	end := strings.IndexByte(html[off:], '>')
+	if end < 0 {
+		// Unterminated tag: escape the stray '<'.
+		return html[:off] + "&lt;" + html[off+1:], true
+	}
+	end += off + 1
+	if escape {
+		return html[:off] + "&lt;" + html[off+1:end-1] + "&gt;" + html[end:], true
+	}
+	return html[:off] + html[end:], true
+}
+
+// htmlTag matches an opening or closing tag and captures the tag name.
+var htmlTag = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9-]*)[^<>]*>`)
+
+// Balance drops closing tags with no matching opener and closes any tags
+// still open at the end, so the result nests correctly.
+func Balance(html string) string {
+	var b strings.Builder
+	b.Grow(len(html))
+	var stack []string
+	last := 0
+	for _, loc := range htmlTag.FindAllStringSubmatchIndex(html, -1) {
+		b.WriteString(html[last:loc[0]])
+		last = loc[1]
+		tag := html[loc[0]:loc[1]]
+		name := strings.ToLower(html[loc[2]:loc[3]])
+		if !strings.HasPrefix(tag, "</") {
+			stack = append(stack, name)
+			b.WriteString(tag)
+			continue
+		}
+		// Closing tag: must match an open tag; close anything opened inside it.
+		i := len(stack) - 1
+		for i >= 0 && stack[i] != name {
+			i--
+		}
+		if i < 0 {
+			continue // stray closer
+		}
+		for j := len(stack) - 1; j > i; j-- {
+			b.WriteString("</" + stack[j] + ">")
+		}
+		b.WriteString(tag)
+		stack = stack[:i]
+	}
+	b.WriteString(html[last:])
+	for j := len(stack) - 1; j >= 0; j-- {
+		b.WriteString("</" + stack[j] + ">")
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package tgmd
+
+import "testing"
+
+func TestRepair_UnexpectedEndTagAtOffset(t *testing.T) {
+	html := "<b>bold</b></i> and <code>x</code>"
+	got, ok := Repair(html, "Bad Request: can't parse entities: Unexpected end tag at byte offset 11")
+	if !ok {
+		t.Fatal("expected repair")
+	}
+	expect(t, got, "<b>bold</b> and <code>x</code>")
+}
+
+func TestRepair_UnclosedStartTagAtOffset(t *testing.T) {
+	html := "<b>keep</b> <i>oops and <code>x</code>"
+	got, ok := Repair(html, `Bad Request: can't parse entities: Can't find end tag corresponding to start tag "i" at byte offset 12`)
+	if !ok {
+		t.Fatal("expected repair")
+	}
+	expect(t, got, "<b>keep</b> oops and <code>x</code>")
+}
+
+func TestRepair_UnsupportedTagIsEscaped(t *testing.T) {
+	html := "use <T> here <b>ok</b>"
+	got, ok := Repair(html, `Bad Request: can't parse entities: Unsupported start tag "t" at byte offset 4`)
+	if !ok {
+		t.Fatal("expected repair")
+	}
+	expect(t, got, "use &lt;T&gt; here <b>ok</b>")
+}
+
+func TestRepair_NoOffsetBalances(t *testing.T) {
+	html := "<b>bold <i>both</b> tail</u>"
+	got, ok := Repair(html, "Bad Request: can't parse entities")
+	if !ok {
+		t.Fatal("expected repair")
+	}
+	expect(t, got, "<b>bold <i>both</i></b> tail")
+}
+
+func TestRepair_WellFormedUnchanged(t *testing.T) {
+	html := Convert("**bold** and `code`")
+	if _, ok := Repair(html, "can't parse entities"); ok {
+		t.Fatalf("well-formed HTML should not change: %q", html)
+	}
+}
+
+func TestIsParseError(t *testing.T) {
+	if !IsParseError("Bad Request: can't parse entities: Unexpected end tag at byte offset 3") {
+		t.Error("expected parse error")
+	}
+	if IsParseError("Forbidden: bot was blocked by the user") {
+		t.Error("unexpected parse error")
+	}
+}
@@ -9,6 +9,9 @@
 //   - Tables become readable list blocks
 //   - Images become links
 //   - Horizontal rules become a line of em-dashes
+//
+// Raw HTML keeps Telegram's own tag subset (b, i, u, s, code, pre, a,
+// blockquote, tg-spoiler) with validated attributes; other tags are escaped.
 package tgmd
 
 import (
@@ -31,6 +34,7 @@ func Convert(markdown string) string {
 
 	r := &renderer{source: source}
 	r.walkBlock(doc)
+	r.buf.WriteString(r.html.closeTo(0))
 	return strings.TrimRight(r.buf.String(), "\n ")
 }
 
@@ -38,6 +42,8 @@ type renderer struct {
 	source    []byte
 	buf       bytes.Buffer
 	listDepth int
+	html      htmlSanitizer // raw HTML tags passed through and still open
+	htmlBase  int           // len(html.open) when the current inline container began
 }
 
 // ---------------------------------------------------------------------------
@@ -94,6 +100,7 @@ func (r *renderer) block(node ast.Node) {
 		r.buf.WriteString("<blockquote>")
 		sub := &renderer{source: r.source}
 		sub.walkBlock(n)
+		sub.buf.WriteString(sub.html.closeTo(0))
 		r.buf.WriteString(strings.TrimRight(sub.buf.String(), "\n "))
 		r.buf.WriteString("</blockquote>\n\n")
 
@@ -123,8 +130,17 @@ func (r *renderer) block(node ast.Node) {
 		r.buf.WriteString("——————————\n\n")
 
 	case *ast.HTMLBlock:
-		// Escape raw HTML so it doesn't break Telegram's parser.
-		r.writeLines(n)
+		// Keep Telegram's tag subset; escape the rest so it can't break the parser.
+		var src strings.Builder
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			src.Write(seg.Value(r.source))
+		}
+		if n.HasClosure() {
+			src.Write(n.ClosureLine.Value(r.source))
+		}
+		r.buf.WriteString(r.html.block(src.String()))
 		r.buf.WriteString("\n")
 
 	default:
@@ -139,8 +155,7 @@ func (r *renderer) block(node ast.Node) {
 	}
 }
 
-// writeLines writes the source lines of a block node (code block, HTML block)
-// with HTML escaping.
+// writeLines writes the source lines of a code block with HTML escaping.
 func (r *renderer) writeLines(n ast.Node) {
 	lines := n.Lines()
 	for i := 0; i < lines.Len(); i++ {
@@ -153,10 +168,15 @@ func (r *renderer) writeLines(n ast.Node) {
 // Inline rendering
 // ---------------------------------------------------------------------------
 
+// inlines renders n's children and closes any raw HTML tags they left open.
 func (r *renderer) inlines(n ast.Node) {
+	base := r.htmlBase
+	r.htmlBase = len(r.html.open)
 	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
 		r.inline(c)
 	}
+	r.buf.WriteString(r.html.closeTo(r.htmlBase))
+	r.htmlBase = base
 }
 
 func (r *renderer) inline(node ast.Node) {
@@ -214,10 +234,16 @@ func (r *renderer) inline(node ast.Node) {
 			escapeHTML(string(n.Destination)), escapeHTML(alt))
 
 	case *ast.RawHTML:
-		// Escape raw HTML to avoid breaking Telegram's parser.
+		// Pass through Telegram's tag subset; escape anything else.
+		var raw strings.Builder
 		for i := 0; i < n.Segments.Len(); i++ {
 			seg := n.Segments.At(i)
-			r.buf.WriteString(escapeHTML(string(seg.Value(r.source))))
+			raw.Write(seg.Value(r.source))
+		}
+		if out, ok := r.html.tag(raw.String(), r.htmlBase); ok {
+			r.buf.WriteString(out)
+		} else {
+			r.buf.WriteString(escapeHTML(raw.String()))
 		}
 
 	default:
@@ -327,6 +353,41 @@ func (r *renderer) listItemContent(item *ast.ListItem) {
 // ---------------------------------------------------------------------------
 
 func (r *renderer) table(t *east.Table) {
+	headers, dataRows := r.tableCells(t)
+	if len(headers) == 0 {
+		return
+	}
+	// Fallback for malformed "header-only" tables: keep one shell row.
+	if len(dataRows) == 0 {
+		dataRows = [][]string{make([]string, len(headers))}
+	}
+
+	for i, row := range dataRows {
+		fmt.Fprintf(&r.buf, "<b>%d.</b>\n", i+1)
+		for j, cell := range row {
+			h := strings.TrimSpace(headers[j])
+			if h != "" {
+				r.buf.WriteString("• <b>")
+				r.buf.WriteString(escapeHTML(h))
+				r.buf.WriteString("</b>: ")
+				r.buf.WriteString(escapeHTML(cell))
+			} else {
+				r.buf.WriteString("• ")
+				r.buf.WriteString(escapeHTML(cell))
+			}
+			r.buf.WriteByte('\n')
+		}
+		if i < len(dataRows)-1 {
+			r.buf.WriteByte('\n')
+		}
+	}
+	r.buf.WriteByte('\n')
+}
+
+// tableCells extracts the plain-text cells of a GFM table, padding short rows
+// so every row has the same column count. headers is empty-celled when the
+// table has no header row; it is nil only when the table has no rows at all.
+func (r *renderer) tableCells(t *east.Table) (headers []string, dataRows [][]string) {
 	var rows [][]string
 	headerIdx := -1
 
@@ -338,11 +399,11 @@ func (r *renderer) table(t *east.Table) {
 		case *east.TableHeader:
 			isHeader = true
 			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
-				cells = append(cells, r.textContent(cell))
+				cells = append(cells, r.cellText(cell))
 			}
 		case *east.TableRow:
 			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
-				cells = append(cells, r.textContent(cell))
+				cells = append(cells, r.cellText(cell))
 			}
 		default:
 			continue
@@ -354,7 +415,7 @@ func (r *renderer) table(t *east.Table) {
 	}
 
 	if len(rows) == 0 {
-		return
+		return nil, nil
 	}
 
 	// Normalise column count.
@@ -370,35 +431,17 @@ func (r *renderer) table(t *east.Table) {
 		}
 	}
 
-	headers := make([]string, numCols)
-	dataRows := rows
+	headers = make([]string, numCols)
+	dataRows = rows
 	if headerIdx >= 0 && headerIdx < len(rows) {
 		copy(headers, rows[headerIdx])
 		dataRows = append(rows[:headerIdx], rows[headerIdx+1:]...)
 	}
-	// Fallback for malformed "header-only" tables: keep one shell row.
-	if len(dataRows) == 0 {
-		dataRows = [][]string{make([]string, numCols)}
-	}
+	return headers, dataRows
+}
 
-	for i, row := range dataRows {
-		fmt.Fprintf(&r.buf, "<b>%d.</b>\n", i+1)
-		for j, cell := range row {
-			h := strings.TrimSpace(headers[j])
-			if h != "" {
-				r.buf.WriteString("• <b>")
-				r.buf.WriteString(escapeHTML(h))
-				r.buf.WriteString("</b>: ")
-				r.buf.WriteString(escapeHTML(cell))
-			} else {
-				r.buf.WriteString("• ")
-				r.buf.WriteString(escapeHTML(cell))
-			}
-			r.buf.WriteByte('\n')
-		}
-		if i < len(dataRows)-1 {
-			r.buf.WriteByte('\n')
-		}
-	}
-	r.buf.WriteByte('\n')
+// cellText returns a table cell's plain text. GFM requires pipes inside cells
+// to be written as \|; goldmark keeps the backslash, so it is dropped here.
+func (r *renderer) cellText(cell ast.Node) string {
+	return strings.ReplaceAll(r.textContent(cell), `\|`, "|")
 }
@@ -243,3 +243,36 @@ func expect(t *testing.T, got, want string) {
 		t.Errorf("\n got: %q\nwant: %q", got, want)
 	}
 }
+
+func TestRawHTMLAllowedTagsPassThrough(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"say <b>x</b> now", "say <b>x</b> now"},
+		{"<b>Title</b>\n\nbody", "<b>Title</b>\n\nbody"},
+		{"<tg-spoiler>secret</tg-spoiler> 1 < 2", "<tg-spoiler>secret</tg-spoiler> 1 &lt; 2"},
+		{`<a href="https://e.com/?a=1&amp;b=2" onclick="x">link</a>`, `<a href="https://e.com/?a=1&amp;b=2">link</a>`},
+		{"<blockquote>\n\nquoted *md*\n\n</blockquote>", "<blockquote>\n\nquoted <i>md</i>\n\n</blockquote>"},
+		{"<pre>\nif a < b && c\n</pre>", "<pre>\nif a &lt; b &amp;&amp; c\n</pre>"},
+		{"a <i>left open", "a <i>left open</i>"},
+	}
+	for _, tt := range tests {
+		if got := Convert(tt.in); got != tt.want {
+			t.Errorf("Convert(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRawHTMLDisallowedTagsEscaped(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"<div>hi</div>", "&lt;div&gt;hi&lt;/div&gt;"},
+		{"x </b> y", "x &lt;/b&gt; y"},
+		{`<a href="javascript:alert(1)">x</a>`, `&lt;a href="javascript:alert(1)"&gt;x&lt;/a&gt;`},
+		{`<code class="language-go">x <b>y</b></code>`, `<code class="language-go">x &lt;b&gt;y&lt;/b&gt;</code>`},
+		{"**<i>x**</i>", "<b><i>x</i></b>&lt;/i&gt;"},
+	}
+	for _, tt := range tests {
+		if got := Convert(tt.in); got != tt.want {
+			t.Errorf("Convert(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package thread
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManagerAbortStopsRunningTurn(t *testing.T) {
+	p := &slowProvider{started: make(chan struct{}), release: make(chan struct{})}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p})
+
+	var mu sync.Mutex
+	var delivered []string
+	done := make(chan struct{}, 1)
+	sink := Sink{
+		Label: "test",
+		Send: func(_ context.Context, response string) error {
+			mu.Lock()
+			delivered = append(delivered, response)
+			mu.Unlock()
+			done <- struct{}{}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	if mgr.Abort("test:abort") {
+		t.Fatal("Abort with no thread should report false")
+	}
+	mgr.Wake("test:abort", &WakeMessage{Source: WakeTelegram, Message: "loop forever", Sink: sink})
+
+	select {
+	case <-p.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("turn never started")
+	}
+	if !mgr.Abort("test:abort") {
+		t.Fatal("Abort should report a running turn")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("aborted turn did not finish promptly")
+	}
+	mu.Lock()
+	got := strings.Join(delivered, "\n")
+	mu.Unlock()
+	if !strings.Contains(got, "type: stopped") || strings.Contains(got, "type: error") {
+		t.Errorf("delivered = %q, want a stopped notice and no error", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for mgr.ActiveCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if mgr.ActiveCount() != 0 {
+		t.Fatal("thread still running after abort")
+	}
+	if mgr.Abort("test:abort") {
+		t.Error("Abort on an idle thread should report false")
+	}
+}
@@ -0,0 +1,82 @@
+package thread
+
+import (
+	"slices"
+
+	sysmsg "github.com/linanwx/nagobot/thread/msg"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// activeTools returns the tools offered in the current turn: the
+// channel-filtered set while a restricted turn runs, otherwise all tools.
+func (t *Thread) activeTools() *tools.Registry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.turnTools != nil {
+		return t.turnTools
+	}
+	return t.tools
+}
+
+// toolScope returns the names of the current turn's tools when the turn is
+// restricted, or nil when every tool is offered. Wakes this turn sends to
+// other sessions carry it as WakeMessage.AllowedTools, so a restricted turn
+// cannot reach a denied tool by dispatching to an unrestricted session.
+func (t *Thread) toolScope() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.turnTools == nil {
+		return nil
+	}
+	return t.turnTools.Names()
+}
+
+// channelTools returns the tools for a turn woken by wakeSource, narrowed by
+// the allow/deny lists of its channel (see promptChannel) and by the limit
+// the wake inherited from a restricted caller. Turns an admin user started
+// themselves keep every tool; system wakes on a channel's session are
+// restricted like the channel's users, since they act on that conversation.
+func (t *Thread) channelTools(wakeSource string) *tools.Registry {
+	if t.tools == nil {
+		return nil
+	}
+	filtered := t.channelFilteredTools(wakeSource)
+	t.mu.Lock()
+	inherited := t.inheritedTools
+	t.mu.Unlock()
+	if inherited == nil {
+		return filtered
+	}
+	if filtered == nil {
+		filtered = t.tools
+	}
+	// Deny everything outside the inherited set; an empty set allows nothing.
+	var deny []string
+	for _, name := range filtered.Names() {
+		if !slices.Contains(inherited, name) {
+			deny = append(deny, name)
+		}
+	}
+	return filtered.Filter(nil, deny)
+}
+
+// channelFilteredTools applies the channel's allow/deny lists, returning nil
+// when the turn is unrestricted.
+func (t *Thread) channelFilteredTools(wakeSource string) *tools.Registry {
+	fn := t.cfg().ChannelToolsFor
+	if fn == nil {
+		return nil
+	}
+	if t.isAdmin() && sysmsg.IsUserVisibleSource(WakeSource(wakeSource)) {
+		return nil
+	}
+	channel := t.promptChannel(wakeSource)
+	if channel == "" {
+		return nil
+	}
+	allow, deny := fn(channel)
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil
+	}
+	return t.tools.Filter(allow, deny)
+}
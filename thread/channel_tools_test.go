@@ -0,0 +1,185 @@
+package thread
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// namedTool is a no-op tool with the given name.
+type namedTool string
+
+func (n namedTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: string(n)}}
+}
+
+func (n namedTool) Run(context.Context, json.RawMessage) string { return "ok" }
+
+// offeredTools runs one turn and returns the tool names sent to the provider.
+func offeredTools(t *testing.T, mgr *Manager, p *scriptedProvider, sessionKey string, wake *WakeMessage) []string {
+	t.Helper()
+	done := make(chan TurnResult, 1)
+	wake.OnResult = func(r TurnResult) { done <- r }
+	mgr.Wake(sessionKey, wake)
+	select {
+	case r := <-done:
+		if r.Err != nil {
+			t.Fatalf("%s: turn failed: %v", sessionKey, r.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s: turn did not finish", sessionKey)
+	}
+	req := p.requests[len(p.requests)-1]
+	names := make([]string, 0, len(req.Tools))
+	for _, d := range req.Tools {
+		names = append(names, d.Function.Name)
+	}
+	return names
+}
+
+func TestChannelToolsDenyExecOnDiscord(t *testing.T) {
+	reg := tools.NewRegistry()
+	reg.Register(namedTool("exec"))
+	reg.Register(namedTool("read_file"))
+	p := &scriptedProvider{responses: []*provider.Response{{Content: "done"}}}
+	mgr := NewManager(&ThreadConfig{
+		DefaultProvider: p,
+		Tools:           reg,
+		ChannelToolsFor: func(channel string) ([]string, []string) {
+			if channel == "discord" {
+				return nil, []string{"exec"}
+			}
+			return nil, nil
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	discord := offeredTools(t, mgr, p, "discord:1", &WakeMessage{Source: WakeDiscord, Message: "run ls"})
+	if slices.Contains(discord, "exec") || !slices.Contains(discord, "read_file") {
+		t.Errorf("discord turn tools = %v, want read_file without exec", discord)
+	}
+	// A system wake on the discord session is restricted too.
+	heartbeat := offeredTools(t, mgr, p, "discord:1", &WakeMessage{Source: WakeHeartbeat, Message: "check in"})
+	if slices.Contains(heartbeat, "exec") {
+		t.Errorf("heartbeat on a discord session offered exec: %v", heartbeat)
+	}
+	// Admin users are exempt.
+	admin := offeredTools(t, mgr, p, "discord:1", &WakeMessage{Source: WakeDiscord, Message: "run ls", Admin: true})
+	if !slices.Contains(admin, "exec") {
+		t.Errorf("admin discord turn tools = %v, want exec", admin)
+	}
+	telegram := offeredTools(t, mgr, p, "telegram:2", &WakeMessage{Source: WakeTelegram, Message: "run ls"})
+	if !slices.Contains(telegram, "exec") {
+		t.Errorf("telegram turn tools = %v, want exec", telegram)
+	}
+}
+
+func TestRestrictedTurnLimitsSessionItWakes(t *testing.T) {
+	reg := tools.NewRegistry()
+	reg.Register(namedTool("exec"))
+	reg.Register(namedTool("read_file"))
+	p := &scriptedProvider{responses: []*provider.Response{{Content: "done"}}}
+	mgr := NewManager(&ThreadConfig{
+		DefaultProvider: p,
+		Tools:           reg,
+		ChannelToolsFor: func(channel string) ([]string, []string) {
+			if channel == "discord" {
+				return nil, []string{"exec"}
+			}
+			return nil, nil
+		},
+	})
+	discord, err := mgr.NewThread("discord:1", "")
+	if err != nil {
+		t.Fatalf("NewThread: %v", err)
+	}
+	discord.turnTools = discord.channelTools(string(WakeDiscord))
+	scope := discord.toolScope()
+	if scope == nil || slices.Contains(scope, "exec") {
+		t.Fatalf("discord tool scope = %v, want read_file only", scope)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	// dispatch(to=session, session_key="cli") from the discord turn must not
+	// reach exec through the unrestricted cli session.
+	cli := offeredTools(t, mgr, p, "cli", &WakeMessage{
+		Source:           WakeSession,
+		Message:          "run ls",
+		CallerSessionKey: "discord:1",
+		AllowedTools:     scope,
+	})
+	if slices.Contains(cli, "exec") || !slices.Contains(cli, "read_file") {
+		t.Errorf("cli turn woken by discord offered %v, want read_file without exec", cli)
+	}
+	direct := offeredTools(t, mgr, p, "cli", &WakeMessage{Source: WakeCLI, Message: "run ls"})
+	if !slices.Contains(direct, "exec") {
+		t.Errorf("direct cli turn tools = %v, want exec", direct)
+	}
+}
+
+// adminProbe records RuntimeContext.Admin for each call.
+type adminProbe struct{ seen []bool }
+
+func (p *adminProbe) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "probe"}}
+}
+
+func (p *adminProbe) Run(ctx context.Context, _ json.RawMessage) string {
+	p.seen = append(p.seen, tools.RuntimeContextFrom(ctx).Admin)
+	return "ok"
+}
+
+// jobRecorder captures jobs added by the remind tool.
+type jobRecorder struct{ jobs []cronpkg.Job }
+
+func (r *jobRecorder) AddJob(job cronpkg.Job) error {
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func TestRemindFromNonAdminFiresNonAdminTurn(t *testing.T) {
+	jobs := &jobRecorder{}
+	probe := &adminProbe{}
+	reg := tools.NewRegistry()
+	reg.Register(tools.NewRemindTool(jobs))
+	reg.Register(probe)
+	call := func(name, args string) *provider.Response {
+		return &provider.Response{ToolCalls: []provider.ToolCall{{ID: name, Type: "function", Function: provider.FunctionCall{Name: name, Arguments: args}}}}
+	}
+	p := &scriptedProvider{responses: []*provider.Response{
+		call("remind", `{"delay":"30m","message":"stretch"}`),
+		{Content: "reminder set"},
+		call("probe", `{}`),
+		{Content: "done"},
+	}}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, Tools: reg})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	offeredTools(t, mgr, p, "telegram:42", &WakeMessage{Source: WakeTelegram, Message: "remind me to stretch"})
+	if len(jobs.jobs) != 1 {
+		t.Fatalf("remind added %d jobs, want 1", len(jobs.jobs))
+	}
+	job := jobs.jobs[0]
+	if job.Admin {
+		t.Fatalf("reminder from a non-admin turn is admin: %+v", job)
+	}
+
+	// The reminder fires as cron does: a cron wake carrying the job's flag.
+	offeredTools(t, mgr, p, job.WakeSession, &WakeMessage{Source: WakeCron, Message: job.Task, Admin: job.Admin})
+	if len(probe.seen) != 1 || probe.seen[0] {
+		t.Errorf("reminder turn admin = %v, want [false]", probe.seen)
+	}
+}
@@ -375,6 +375,7 @@ var expiredToolHeaderOnly = map[string]bool{
 	"web_search": true,
 	"grep":       true,
 	"health":     true,
+	"sysinfo":    true,
 }
 
 // computeWakeCompressed returns the Compressed value for a user message with
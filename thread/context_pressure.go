@@ -79,6 +79,61 @@ func (t *Thread) contextBudget() ContextThresholds {
 	return ComputeContextThresholds(contextWindow)
 }
 
+// completionReserve returns the output tokens a turn reserves from the
+// context window: the agent's max_tokens override when set, otherwise the
+// thread-wide MaxCompletionTokens, matching what the provider is asked for.
+func (t *Thread) completionReserve() int {
+	if n := t.agentSampling().MaxTokens; n > 0 {
+		return n
+	}
+	return t.cfg().MaxCompletionTokens
+}
+
+// preflightContext checks that the part of a turn history truncation cannot
+// shrink (system prompt, the new user message, the turn's tool definitions)
+// fits the model's input budget: the context window minus the completion
+// reserve.
+// Returns an actionable error for the user when it does not.
+func (t *Thread) preflightContext(systemPrompt, userMessage string) error {
+	contextWindow := t.contextBudget().ContextWindow
+	if contextWindow <= 0 {
+		return nil
+	}
+	inputBudget := contextWindow - t.completionReserve()
+	userTokens := EstimateMessageTokens(provider.UserMessage(userMessage))
+	fixedTokens := EstimateMessageTokens(provider.SystemMessage(systemPrompt)) +
+		userTokens + EstimateToolDefsTokens(t.activeTools().Defs())
+	if fixedTokens <= inputBudget {
+		return nil
+	}
+	logger.Warn("turn rejected by context preflight",
+		"threadID", t.id,
+		"sessionKey", t.sessionKey,
+		"userTokens", userTokens,
+		"requiredTokens", fixedTokens,
+		"inputBudget", inputBudget,
+		"contextWindowTokens", contextWindow,
+	)
+	return fmt.Errorf("your message is ~%s tokens; with the system prompt and tools this turn needs ~%s, over the model's ~%s token input limit. Split the message into smaller parts or switch to a model with a larger context window",
+		compactCount(userTokens), compactCount(fixedTokens), compactCount(max(inputBudget, 0)))
+}
+
+// contextOverflowError handles a turn the provider rejected for exceeding the
+// context window, which local estimates can miss: it compacts the session
+// right away instead of waiting for the idle scan, and returns an actionable
+// error for the user.
+func (t *Thread) contextOverflowError(err error) error {
+	logger.Warn("provider rejected turn: context window exceeded",
+		"threadID", t.id,
+		"sessionKey", t.sessionKey,
+		"err", err,
+	)
+	if t.mgr != nil && t.cfg().Sessions != nil {
+		t.mgr.tryTier1Compress(t.sessionKey)
+	}
+	return fmt.Errorf("the conversation no longer fits the model's context window, so older tool output was compacted. Send your message again; if it still fails, start a new session or switch to a model with a larger context window (%w)", err)
+}
+
 // PressureStatus returns "ok", "warning", or "pressure" based on token usage.
 func PressureStatus(usedTokens int, ct ContextThresholds) string {
 	if ct.ContextWindow <= 0 {
@@ -1,10 +1,19 @@
 package thread
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/linanwx/nagobot/agent"
 	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
 )
 
 func TestComputeContextThresholds(t *testing.T) {
@@ -178,3 +187,169 @@ func TestEstimateMessageTokens_ReasoningTrimmedSkipsAll(t *testing.T) {
 			tokens, baseTokens)
 	}
 }
+
+// countingProvider counts calls so a test can assert the provider was never reached.
+type countingProvider struct{ calls atomic.Int32 }
+
+func (p *countingProvider) Chat(context.Context, *provider.Request) (provider.ChatResult, error) {
+	p.calls.Add(1)
+	return provider.NewBasicResult(&provider.Response{Content: "ok"}), nil
+}
+
+func TestPreflightRejectsOversizedMessage(t *testing.T) {
+	p := &countingProvider{}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, ContextWindowTokens: 2000, MaxCompletionTokens: 500})
+
+	delivered := make(chan string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	mgr.Wake("test:preflight", &WakeMessage{
+		Source:  WakeTelegram,
+		Message: strings.Repeat("lorem ipsum dolor sit amet ", 2000),
+		Sink: Sink{Send: func(_ context.Context, response string) error {
+			delivered <- response
+			return nil
+		}},
+	})
+
+	select {
+	case got := <-delivered:
+		if !strings.Contains(got, "your message is ~") || !strings.Contains(got, "~1.5k token input limit") {
+			t.Errorf("delivered = %q, want the preflight error", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("preflight error was never delivered")
+	}
+	if n := p.calls.Load(); n != 0 {
+		t.Errorf("provider called %d times, want 0", n)
+	}
+}
+
+// overflowProvider rejects every request with a context-length error.
+type overflowProvider struct{}
+
+func (overflowProvider) Chat(context.Context, *provider.Request) (provider.ChatResult, error) {
+	return nil, &provider.ContextLengthError{Provider: "stub", Err: errors.New("maximum context length is 8192 tokens")}
+}
+
+func TestContextLengthErrorCompactsSession(t *testing.T) {
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const key = "telegram:overflow"
+	call := provider.ToolCall{ID: "call_1", Type: "function", Function: provider.FunctionCall{Name: "exec", Arguments: "{}"}}
+	if err := sessions.Save(&session.Session{Key: key, Messages: []provider.Message{
+		provider.UserMessage("list the logs"),
+		provider.AssistantMessageWithTools("", "", nil, []provider.ToolCall{call}),
+		provider.ToolResultMessage("call_1", "exec", strings.Repeat("log line\n", 1000)),
+		provider.AssistantMessage("here they are"),
+		provider.UserMessage("thanks"),
+		provider.AssistantMessage("sure"),
+		provider.UserMessage("again"),
+		provider.AssistantMessage("done"),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewManager(&ThreadConfig{DefaultProvider: overflowProvider{}, Sessions: sessions})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	done := make(chan TurnResult, 1)
+	mgr.Wake(key, &WakeMessage{Source: WakeTelegram, Message: "one more", OnResult: func(r TurnResult) { done <- r }})
+
+	select {
+	case r := <-done:
+		if !provider.IsContextLengthError(r.Err) || !strings.Contains(r.Err.Error(), "Send your message again") {
+			t.Errorf("turn error = %v, want an actionable context-length error", r.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("turn did not finish")
+	}
+	stored, err := sessions.Reload(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Messages[2].Compressed == "" {
+		t.Error("old tool result was not compacted after the overflow")
+	}
+}
+
+func TestPinnedNotesSurviveCompaction(t *testing.T) {
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const key = "telegram:pins"
+	call := provider.ToolCall{ID: "call_1", Type: "function", Function: provider.FunctionCall{Name: "exec", Arguments: "{}"}}
+	if err := sessions.Save(&session.Session{Key: key, Messages: []provider.Message{
+		provider.UserMessage("deploy only to staging, never to prod"),
+		provider.AssistantMessageWithTools("", "", nil, []provider.ToolCall{call}),
+		provider.ToolResultMessage("call_1", "exec", strings.Repeat("log line\n", 1000)),
+		provider.AssistantMessage("deployed to staging"),
+		provider.UserMessage("thanks"),
+		provider.AssistantMessage("sure"),
+		provider.UserMessage("again"),
+		provider.AssistantMessage("done"),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewManager(&ThreadConfig{Sessions: sessions})
+	pin, err := session.AddPin(mgr.SessionDir(key), "Deploy only to staging, never to prod.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr.tryTier1Compress(key)
+
+	stored, err := sessions.Reload(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Messages[2].Compressed == "" {
+		t.Fatal("expected the old tool result to be compacted")
+	}
+	th, err := mgr.NewThread(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt := th.buildSystemPrompt(string(WakeTelegram))
+	if !strings.Contains(prompt, "["+pin.ID+"] Deploy only to staging, never to prod.") {
+		t.Errorf("pinned note missing from the prompt after compaction:\n%s", prompt)
+	}
+}
+
+func TestPreflightUsesAgentMaxTokens(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspace, "agents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tpl := "---\nname: big\nmax_tokens: 1200\n---\nbody"
+	if err := os.WriteFile(filepath.Join(workspace, "agents", "big.md"), []byte(tpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(&ThreadConfig{
+		DefaultProvider:     &countingProvider{},
+		Agents:              agent.NewRegistry(workspace),
+		ContextWindowTokens: 2000,
+		MaxCompletionTokens: 100,
+	})
+	msg := strings.Repeat("lorem ipsum dolor sit amet ", 100)
+
+	plain, err := mgr.NewThread("test:plain", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.preflightContext("", msg); err != nil {
+		t.Fatalf("default agent: preflight = %v, want nil under the global reserve", err)
+	}
+	big, err := mgr.NewThread("test:big", "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := big.preflightContext("", msg); err == nil {
+		t.Fatal("max_tokens agent: preflight = nil, want rejection under its 1200-token reserve")
+	}
+}
@@ -3,8 +3,8 @@ package thread
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/linanwx/nagobot/logger"
 	"github.com/linanwx/nagobot/session"
@@ -51,7 +51,8 @@ func (t *Thread) AgentExists(name string) bool {
 	return cfg.Agents.Def(name) != nil
 }
 
-// SessionExists reports whether a session with the given key is persisted on disk.
+// SessionExists reports whether a session with the given key is persisted in
+// the session store.
 func (t *Thread) SessionExists(key string) bool {
 	key = strings.TrimSpace(key)
 	if key == "" {
@@ -61,12 +62,7 @@ func (t *Thread) SessionExists(key string) bool {
 	if cfg.Sessions == nil {
 		return false
 	}
-	path := cfg.Sessions.PathForKey(key)
-	if path == "" {
-		return false
-	}
-	_, err := os.Stat(path)
-	return err == nil
+	return cfg.Sessions.Exists(key)
 }
 
 // SendToCaller delivers body directly to the current wake's sink —
@@ -86,8 +82,9 @@ func (t *Thread) SendToCaller(ctx context.Context, body string) error {
 
 // CreateOrWakeSubagent creates (or wakes existing) a subagent thread at
 // {current}:threads:{taskID}. The optional agent name overrides any previously
-// persisted agent on the session meta.
-func (t *Thread) CreateOrWakeSubagent(_ context.Context, agentName, taskID, body string) (string, string, error) {
+// persisted agent on the session meta. timeout bounds the woken turn; zero
+// falls back to the manager's subagent timeout.
+func (t *Thread) CreateOrWakeSubagent(_ context.Context, agentName, taskID, body string, timeout time.Duration) (string, string, error) {
 	taskID = strings.TrimSpace(taskID)
 	if taskID == "" {
 		return "", "", fmt.Errorf("task_id is required")
@@ -101,13 +98,34 @@ func (t *Thread) CreateOrWakeSubagent(_ context.Context, agentName, taskID, body
 	}
 	key := parent + ":threads:" + taskID
 
-	note, err := t.createOrWake(key, agentName, body, false, "")
+	if err := t.reserveSubagentSpawn(parent); err != nil {
+		return "", "", err
+	}
+	note, err := t.createOrWake(key, agentName, body, false, "", timeout)
 	if err != nil {
 		return "", "", err
 	}
 	return key, note, nil
 }
 
+// reserveSubagentSpawn counts one subagent or fork dispatch against the
+// per-turn limit and the hourly limit of parent's root session, failing once
+// either is used up so a model stuck in a fan-out loop is told to stop.
+// Charging the root means nested subagents and forks share one budget.
+func (t *Thread) reserveSubagentSpawn(parent string) error {
+	limit := t.mgr.subagentMaxPerTurn()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.subagentSpawns >= limit {
+		return fmt.Errorf("subagent limit reached: this turn already dispatched %d subagents or forks (thread.subagents.maxPerTurn); wait for their results instead of spawning more", t.subagentSpawns)
+	}
+	if err := t.mgr.reserveSubagentSpawn(rootSessionKey(parent), time.Now()); err != nil {
+		return err
+	}
+	t.subagentSpawns++
+	return nil
+}
+
 // CreateOrWakeFork creates (or wakes existing) a fork session at
 // {current}:fork:{taskID}. On new creation, the current session's history is
 // copied (stripped) via session.CreateFork. Agent name overrides meta.
@@ -129,7 +147,10 @@ func (t *Thread) CreateOrWakeFork(_ context.Context, agentName, taskID, body str
 	}
 	key := parent + ":fork:" + taskID
 
-	note, err := t.createOrWake(key, agentName, body, true, t.sessionKey)
+	if err := t.reserveSubagentSpawn(parent); err != nil {
+		return "", "", err
+	}
+	note, err := t.createOrWake(key, agentName, body, true, t.sessionKey, 0)
 	if err != nil {
 		return "", "", err
 	}
@@ -148,8 +169,10 @@ func (t *Thread) WakeSession(_ context.Context, sessionKey, body string) error {
 	t.mgr.Wake(sessionKey, &WakeMessage{
 		Source:           WakeSession,
 		Message:          body,
-		Sink:             BuildPairedSessionSink(t.mgr, sessionKey, t.sessionKey),
+		Sink:             t.buildSinkToCaller(sessionKey),
 		CallerSessionKey: t.sessionKey,
+		DryRun:           t.isDryRun(),
+		AllowedTools:     t.toolScope(),
 	})
 	return nil
 }
@@ -157,7 +180,7 @@ func (t *Thread) WakeSession(_ context.Context, sessionKey, body string) error {
 // buildSinkToCaller returns a recursive paired sink attached to a wake going
 // from THIS thread to `targetSession`. See BuildPairedSessionSink for semantics.
 func (t *Thread) buildSinkToCaller(targetSession string) Sink {
-	return BuildPairedSessionSink(t.mgr, targetSession, t.sessionKey)
+	return BuildPairedSessionSink(t.mgr, targetSession, t.sessionKey, t.isDryRun(), t.toolScope())
 }
 
 // BuildPairedSessionSink constructs a recursive session-to-session paired sink.
@@ -172,7 +195,11 @@ func (t *Thread) buildSinkToCaller(targetSession string) Sink {
 //   - dispatch(to=user) — redirect to channel user
 //   - dispatch(to=<any>) with SignalHalt — any explicit dispatch suppresses
 //     the per-wake sink via SetSuppressSink
-func BuildPairedSessionSink(mgr *Manager, selfKey, peerKey string) Sink {
+//
+// dryRun and allowedTools are carried on every wake in the exchange, so a
+// dry-run or tool-restricted turn's peers stay simulated and restricted in
+// both directions (nil allowedTools = no limit).
+func BuildPairedSessionSink(mgr *Manager, selfKey, peerKey string, dryRun bool, allowedTools []string) Sink {
 	return Sink{
 		Label: "your reply will be forwarded to caller session " + peerKey,
 		Send: func(_ context.Context, response string) error {
@@ -184,7 +211,9 @@ func BuildPairedSessionSink(mgr *Manager, selfKey, peerKey string) Sink {
 				Source:           WakeSession,
 				Message:          response,
 				CallerSessionKey: selfKey,
-				Sink:             BuildPairedSessionSink(mgr, peerKey, selfKey),
+				Sink:             BuildPairedSessionSink(mgr, peerKey, selfKey, dryRun, allowedTools),
+				DryRun:           dryRun,
+				AllowedTools:     allowedTools,
 			})
 			return nil
 		},
@@ -242,17 +271,10 @@ func (t *Thread) SignalHalt() {
 //   - session exists → optionally update meta agent, enqueue wake, return "resumed"
 //   - session missing → if forkFrom != "", create fork from that source; else fresh spawn.
 //     Then enqueue wake. Returns "created" or "forked-from:<src>".
-func (t *Thread) createOrWake(key, agentName, body string, isFork bool, forkFrom string) (string, error) {
+func (t *Thread) createOrWake(key, agentName, body string, isFork bool, forkFrom string, timeout time.Duration) (string, error) {
 	cfg := t.cfg()
 	note := ""
-	exists := false
-	if cfg.Sessions != nil {
-		if path := cfg.Sessions.PathForKey(key); path != "" {
-			if _, err := os.Stat(path); err == nil {
-				exists = true
-			}
-		}
-	}
+	exists := cfg.Sessions != nil && cfg.Sessions.Exists(key)
 
 	if exists {
 		// Override agent meta if explicitly specified.
@@ -291,6 +313,9 @@ func (t *Thread) createOrWake(key, agentName, body string, isFork bool, forkFrom
 		AgentName:        agentName,
 		Sink:             t.buildSinkToCaller(key),
 		CallerSessionKey: t.sessionKey,
+		Timeout:          timeout,
+		DryRun:           t.isDryRun(),
+		AllowedTools:     t.toolScope(),
 	})
 	return note, nil
 }
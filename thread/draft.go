@@ -0,0 +1,63 @@
+package thread
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// beginDraft switches the current turn to draft mode: streaming and
+// intermediate deliveries are held instead of sent, so a long answer reaches
+// the user once, complete, via finalizeDraft (or as the turn's final reply).
+func (t *Thread) beginDraft() {
+	t.mu.Lock()
+	t.drafting = true
+	t.mu.Unlock()
+}
+
+// isDrafting returns whether the current turn is holding its replies.
+func (t *Thread) isDrafting() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.drafting
+}
+
+// holdDraft keeps an intermediate reply produced while drafting.
+func (t *Thread) holdDraft(text string) {
+	t.mu.Lock()
+	t.draftParts = append(t.draftParts, text)
+	t.mu.Unlock()
+}
+
+// finalizeDraft sends the complete reply once and ends draft mode. An empty
+// text sends the held intermediate replies instead. The runner's end-of-turn
+// delivery is suppressed so the answer is not sent twice.
+func (t *Thread) finalizeDraft(ctx context.Context, sink Sink, text string) error {
+	t.mu.Lock()
+	if !t.drafting {
+		t.mu.Unlock()
+		return fmt.Errorf("no draft in progress")
+	}
+	if strings.TrimSpace(text) == "" {
+		text = strings.Join(t.draftParts, "\n\n")
+	}
+	t.mu.Unlock()
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("draft is empty: pass the complete reply as text")
+	}
+	if err := sink.WithRetry(3).Send(ctx, text); err != nil {
+		return err
+	}
+	t.resetDraft()
+	t.markDefaultReplyForwarded()
+	t.SetSuppressSink()
+	return nil
+}
+
+// resetDraft leaves draft mode and drops held replies at turn end.
+func (t *Thread) resetDraft() {
+	t.mu.Lock()
+	t.drafting = false
+	t.draftParts = nil
+	t.mu.Unlock()
+}
@@ -0,0 +1,87 @@
+package thread
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// sentCountTool records how many replies the sink had delivered when it ran.
+type sentCountTool struct {
+	sent func() int
+	seen chan int
+}
+
+func (p *sentCountTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "probe"}}
+}
+
+func (p *sentCountTool) Run(context.Context, json.RawMessage) string {
+	p.seen <- p.sent()
+	return "ok"
+}
+
+func toolCallResponse(content, id, name, args string) *provider.Response {
+	return &provider.Response{Content: content, ToolCalls: []provider.ToolCall{{
+		ID: id, Type: "function",
+		Function: provider.FunctionCall{Name: name, Arguments: args},
+	}}}
+}
+
+func TestDraftHeldUntilFinalize(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		toolCallResponse("", "c1", "draft_reply", `{"action":"start"}`),
+		toolCallResponse("Half-formed answer, still checking.", "c2", "probe", `{}`),
+		toolCallResponse("", "c3", "draft_reply", `{"action":"finalize","text":"Complete answer."}`),
+		{Content: "Sent above."},
+	}}
+
+	delivered := make(chan string, 4)
+	probe := &sentCountTool{sent: func() int { return len(delivered) }, seen: make(chan int, 1)}
+	reg := tools.NewRegistry()
+	reg.Register(&tools.DraftReplyTool{})
+	reg.Register(probe)
+
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, Tools: reg})
+	sink := Sink{
+		Label:     "test",
+		Chunkable: true,
+		Send: func(_ context.Context, response string) error {
+			delivered <- response
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	mgr.Wake("telegram:1", &WakeMessage{Source: WakeTelegram, Message: "long question", Sink: sink})
+
+	select {
+	case n := <-probe.seen:
+		if n != 0 {
+			t.Fatalf("%d replies delivered while drafting, want 0", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("probe tool never ran")
+	}
+
+	select {
+	case got := <-delivered:
+		if got != "Complete answer." {
+			t.Fatalf("delivered = %q, want the finalized reply", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("finalized reply not delivered")
+	}
+
+	select {
+	case extra := <-delivered:
+		t.Fatalf("unexpected delivery after finalize: %q", extra)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
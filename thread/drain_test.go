@@ -0,0 +1,105 @@
+package thread
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// slowProvider blocks each call until release is closed or ctx ends.
+type slowProvider struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (p *slowProvider) Chat(ctx context.Context, _ *provider.Request) (provider.ChatResult, error) {
+	p.once.Do(func() { close(p.started) })
+	select {
+	case <-p.release:
+		return provider.NewBasicResult(&provider.Response{Content: "slow answer"}), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestManagerDrainDeliversInFlightTurn(t *testing.T) {
+	p := &slowProvider{started: make(chan struct{}), release: make(chan struct{})}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p})
+
+	var mu sync.Mutex
+	var delivered []string
+	sink := Sink{
+		Label: "test",
+		Send: func(_ context.Context, response string) error {
+			mu.Lock()
+			delivered = append(delivered, response)
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go mgr.Run(ctx)
+	mgr.Wake("test:drain", &WakeMessage{Source: WakeTelegram, Message: "hello", Sink: sink})
+
+	select {
+	case <-p.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("turn never started")
+	}
+
+	// Shutdown begins while the turn is still waiting on the provider.
+	cancel()
+	if n := mgr.ActiveCount(); n != 1 {
+		t.Fatalf("ActiveCount = %d, want 1", n)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		close(p.release)
+	}()
+
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer drainCancel()
+	drained, abandoned := mgr.Drain(drainCtx)
+	if drained != 1 || abandoned != 0 {
+		t.Errorf("Drain = (%d, %d), want (1, 0)", drained, abandoned)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 1 || delivered[0] != "slow answer" {
+		t.Errorf("delivered = %q, want the in-flight answer", delivered)
+	}
+}
+
+func TestManagerDrainAbandonsAtDeadline(t *testing.T) {
+	p := &slowProvider{started: make(chan struct{}), release: make(chan struct{})}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go mgr.Run(ctx)
+	mgr.Wake("test:abandon", &WakeMessage{Source: WakeTelegram, Message: "hello", Sink: Sink{
+		Send: func(context.Context, string) error { return nil },
+	}})
+	<-p.started
+	cancel()
+
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer drainCancel()
+	if drained, abandoned := mgr.Drain(drainCtx); drained != 0 || abandoned != 1 {
+		t.Errorf("Drain = (%d, %d), want (0, 1)", drained, abandoned)
+	}
+
+	// The abandoned turn is cancelled and the thread returns to idle.
+	deadline := time.Now().Add(5 * time.Second)
+	for mgr.ActiveCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("abandoned turn was not cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,93 @@
+package thread
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+)
+
+func waitIdle(t *testing.T, mgr *Manager) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for mgr.ActiveCount() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("turn never finished")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManagerEvictsIdleThreadAndRebuildsOnWake(t *testing.T) {
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &scriptedProvider{responses: []*provider.Response{{Content: "first answer"}, {Content: "second answer"}}}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, Sessions: sessions, IdleEvictAfter: time.Millisecond})
+
+	delivered := make(chan string, 2)
+	sink := Sink{Label: "test", Send: func(_ context.Context, response string) error {
+		delivered <- response
+		return nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	const key = "telegram:evict"
+	mgr.Wake(key, &WakeMessage{Source: WakeTelegram, Message: "remember the number 42", Sink: sink})
+	if got := <-delivered; got != "first answer" {
+		t.Fatalf("first reply = %q", got)
+	}
+	waitIdle(t, mgr)
+
+	time.Sleep(5 * time.Millisecond)
+	mgr.gc()
+	if mgr.HasThread(key) {
+		t.Fatal("idle thread was not evicted")
+	}
+
+	mgr.Wake(key, &WakeMessage{Source: WakeTelegram, Message: "what was the number?", Sink: sink})
+	select {
+	case got := <-delivered:
+		if got != "second answer" {
+			t.Fatalf("second reply = %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("rebuilt thread never replied")
+	}
+	if !mgr.HasThread(key) {
+		t.Fatal("wake did not recreate the thread")
+	}
+
+	var history []string
+	for _, m := range p.requests[1].Messages {
+		history = append(history, m.Content)
+	}
+	joined := strings.Join(history, "\n")
+	if !strings.Contains(joined, "remember the number 42") || !strings.Contains(joined, "first answer") {
+		t.Fatalf("rebuilt thread lost history:\n%s", joined)
+	}
+}
+
+func TestManagerGCKeepsRunningThread(t *testing.T) {
+	p := &slowProvider{started: make(chan struct{}), release: make(chan struct{})}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, IdleEvictAfter: time.Nanosecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	mgr.Wake("test:busy", &WakeMessage{Source: WakeTelegram, Message: "hello"})
+	<-p.started
+	time.Sleep(time.Millisecond)
+	mgr.gc()
+	if !mgr.HasThread("test:busy") {
+		t.Fatal("gc evicted a thread with a turn in flight")
+	}
+	close(p.release)
+	waitIdle(t, mgr)
+}
@@ -2,6 +2,7 @@ package thread
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime/debug"
@@ -9,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/linanwx/nagobot/config"
 	"github.com/linanwx/nagobot/logger"
 	"github.com/linanwx/nagobot/provider"
 	"github.com/linanwx/nagobot/session"
@@ -21,8 +23,13 @@ type Manager struct {
 	cfg            *ThreadConfig
 	mu             sync.Mutex
 	threads        map[string]*Thread
-	maxConcurrency int
-	signal         chan struct{} // aggregated notification from all threads
+	turnSem        chan struct{}      // bounds turns in flight across all threads
+	subagentSem    chan struct{}      // bounds subagent turns on top of turnSem
+	signal         chan struct{}      // aggregated notification from all threads
+	cancelTurns    context.CancelFunc // aborts in-flight turns; set by Run, fired by Drain on deadline
+
+	spawnMu  sync.Mutex
+	spawnLog map[string][]time.Time // session key → subagent dispatch times within the last hour
 }
 
 // NewManager creates a thread manager.
@@ -33,11 +40,89 @@ func NewManager(cfg *ThreadConfig) *Manager {
 	return &Manager{
 		cfg:            cfg,
 		threads:        make(map[string]*Thread),
-		maxConcurrency: defaultMaxConcurrency,
+		turnSem:        make(chan struct{}, maxConcurrentTurns(cfg)),
+		subagentSem:    make(chan struct{}, subagentMaxConcurrency(cfg)),
 		signal:         make(chan struct{}, 1),
 	}
 }
 
+// maxConcurrentTurns returns the configured global turn limit, falling back
+// to the default for unset or non-positive values.
+func maxConcurrentTurns(cfg *ThreadConfig) int {
+	if cfg.MaxConcurrentTurns > 0 {
+		return cfg.MaxConcurrentTurns
+	}
+	return defaultMaxConcurrency
+}
+
+// subagentMaxConcurrency returns the configured subagent concurrency limit,
+// falling back to the default for unset or non-positive values.
+func subagentMaxConcurrency(cfg *ThreadConfig) int {
+	if cfg.SubagentMaxConcurrency > 0 {
+		return cfg.SubagentMaxConcurrency
+	}
+	return defaultSubagentMaxConcurrency
+}
+
+// subagentTimeout returns the per-turn deadline applied to subagent threads.
+func (m *Manager) subagentTimeout() time.Duration {
+	if m.cfg.SubagentTimeout > 0 {
+		return m.cfg.SubagentTimeout
+	}
+	return defaultSubagentTimeout
+}
+
+// subagentMaxPerTurn returns how many subagents one turn may dispatch.
+func (m *Manager) subagentMaxPerTurn() int {
+	if m.cfg.SubagentMaxPerTurn > 0 {
+		return m.cfg.SubagentMaxPerTurn
+	}
+	return defaultSubagentMaxPerTurn
+}
+
+// reserveSubagentSpawn records a subagent or fork dispatch charged to the
+// root session sessionKey, or fails when that session's tree already
+// dispatched its hourly allowance.
+func (m *Manager) reserveSubagentSpawn(sessionKey string, now time.Time) error {
+	limit := m.cfg.SubagentMaxPerHour
+	if limit <= 0 {
+		limit = defaultSubagentMaxPerHour
+	}
+	m.spawnMu.Lock()
+	defer m.spawnMu.Unlock()
+	recent := m.spawnLog[sessionKey][:0]
+	for _, at := range m.spawnLog[sessionKey] {
+		if now.Sub(at) < time.Hour {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= limit {
+		m.spawnLog[sessionKey] = recent
+		return fmt.Errorf("subagent limit reached: this session and its subagents dispatched %d subagents or forks in the last hour (thread.subagents.maxPerHour); do the remaining work yourself or try again later", len(recent))
+	}
+	if m.spawnLog == nil {
+		m.spawnLog = make(map[string][]time.Time)
+	}
+	m.spawnLog[sessionKey] = append(recent, now)
+	return nil
+}
+
+// rootSessionKey strips every subagent (:threads:) and fork (:fork:) suffix
+// from key, leaving the session the dispatch tree started from.
+func rootSessionKey(key string) string {
+	for _, marker := range []string{":threads:", ":fork:"} {
+		if i := strings.Index(key, marker); i >= 0 {
+			key = key[:i]
+		}
+	}
+	return key
+}
+
+// isSubagentSession reports whether key belongs to a dispatch(to=subagent) thread.
+func isSubagentSession(key string) bool {
+	return strings.Contains(key, ":threads:")
+}
+
 // Shutdown performs cleanup of managed resources (e.g. flushes message counts).
 func (m *Manager) Shutdown() {
 	if m.cfg.Sessions != nil && m.cfg.Sessions.Counts != nil {
@@ -46,9 +131,15 @@ func (m *Manager) Shutdown() {
 }
 
 // Run is the manager's main scheduling loop. It picks runnable threads and
-// runs them up to maxConcurrency in parallel. Blocks until ctx is cancelled.
+// runs them up to the turn limit in parallel. Blocks until ctx is cancelled.
+// Cancelling ctx stops scheduling new turns; turns already in flight keep
+// running until they finish or Drain gives up on them.
 func (m *Manager) Run(ctx context.Context) {
-	sem := make(chan struct{}, m.maxConcurrency)
+	turnCtx, cancelTurns := context.WithCancel(context.WithoutCancel(ctx))
+	m.mu.Lock()
+	m.cancelTurns = cancelTurns
+	m.mu.Unlock()
+
 	ticker := time.NewTicker(gcInterval)
 	defer ticker.Stop()
 	for {
@@ -56,7 +147,7 @@ func (m *Manager) Run(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-m.signal:
-			m.scheduleReady(ctx, sem)
+			m.scheduleReady(turnCtx)
 		case <-ticker.C:
 			m.gc()
 			m.runCompressionScan()
@@ -64,22 +155,96 @@ func (m *Manager) Run(ctx context.Context) {
 	}
 }
 
-// gc removes idle threads that have been inactive beyond the TTL.
+// InFlightTurns returns how many turns hold a slot under the turn limit
+// (i.e. are executing, not waiting for a slot) and the limit itself.
+func (m *Manager) InFlightTurns() (inFlight, limit int) {
+	return len(m.turnSem), cap(m.turnSem)
+}
+
+// ActiveCount returns the number of threads currently executing a turn or
+// waiting for a slot to execute one.
+func (m *Manager) ActiveCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, t := range m.threads {
+		if t.state == threadRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// Drain blocks until no turn is in flight or ctx expires, whichever comes
+// first. Call it after the Run context is cancelled so no new turns start.
+// Turns still running at the deadline are cancelled. Returns how many of the
+// turns active at the start finished (drained) and how many were cut off
+// (abandoned).
+func (m *Manager) Drain(ctx context.Context) (drained, abandoned int) {
+	start := m.ActiveCount()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		active := m.ActiveCount()
+		if active == 0 {
+			logger.Info("thread drain complete", "drained", start)
+			return start, 0
+		}
+		select {
+		case <-ctx.Done():
+			m.mu.Lock()
+			cancel := m.cancelTurns
+			m.mu.Unlock()
+			if cancel != nil {
+				cancel()
+			}
+			drained = max(start-active, 0)
+			logger.Warn("thread drain deadline reached, abandoning in-flight turns",
+				"drained", drained, "abandoned", active)
+			return drained, active
+		case <-ticker.C:
+		}
+	}
+}
+
+// idleEvictAfter returns how long a thread may stay idle before gc evicts
+// it; <= 0 disables eviction.
+func (m *Manager) idleEvictAfter() time.Duration {
+	if m.cfg.IdleEvictAfter == 0 {
+		return defaultThreadTTL
+	}
+	return m.cfg.IdleEvictAfter
+}
+
+// gc evicts threads idle beyond the idle TTL, dropping their cached session
+// too. Every turn has already persisted its messages, so the next Wake
+// rebuilds the thread from the saved session. Threads running a turn, with
+// queued messages, or mid-Wake are kept.
 func (m *Manager) gc() {
+	ttl := m.idleEvictAfter()
+	if ttl <= 0 {
+		return
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for key, t := range m.threads {
-		if t.state == threadIdle && !t.hasMessages() && time.Since(t.lastActiveAt) > defaultThreadTTL {
+		if t.state == threadIdle && t.wakers == 0 && !t.hasMessages() && time.Since(t.lastActiveAt) > ttl {
 			delete(m.threads, key)
+			if m.cfg.Sessions != nil {
+				m.cfg.Sessions.Evict(key)
+			}
 			logger.Debug("thread gc", "sessionKey", key, "threadID", t.id)
 		}
 	}
 }
 
 // scheduleReady scans threads and starts goroutines for any that are idle with
-// pending messages.
-func (m *Manager) scheduleReady(ctx context.Context, sem chan struct{}) {
+// pending messages. Each goroutine takes a turnSem slot before running, so
+// turns beyond the limit queue here while their inboxes keep accepting
+// (and merging) new messages.
+func (m *Manager) scheduleReady(ctx context.Context) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -88,9 +253,18 @@ func (m *Manager) scheduleReady(ctx context.Context, sem chan struct{}) {
 			t.state = threadRunning
 
 			go func(thread *Thread) {
-				sem <- struct{}{}
+				// Subagents take their own slot first so a burst of them
+				// cannot occupy every global slot while queued.
+				subagent := isSubagentSession(thread.sessionKey)
+				if subagent {
+					m.subagentSem <- struct{}{}
+				}
+				m.turnSem <- struct{}{}
 				defer func() {
-					<-sem
+					<-m.turnSem
+					if subagent {
+						<-m.subagentSem
+					}
 					if r := recover(); r != nil {
 						logger.Error("thread panic recovered",
 							"threadID", thread.id,
@@ -149,12 +323,22 @@ func (m *Manager) Wake(sessionKey string, msg *WakeMessage) {
 	if sessionKey == "" {
 		sessionKey = "cli"
 	}
-	t, err := m.NewThread(sessionKey, msg.AgentName)
+	// Pin the thread until the message is queued so gc cannot evict it in
+	// between and strand the message in a detached inbox.
+	m.mu.Lock()
+	t, err := m.newThreadLocked(sessionKey, msg.AgentName)
+	if err == nil {
+		t.wakers++
+	}
+	m.mu.Unlock()
 	if err != nil {
 		logger.Error("failed to create thread", "sessionKey", sessionKey, "agent", msg.AgentName, "err", err)
 		return
 	}
 	t.Enqueue(msg)
+	m.mu.Lock()
+	t.wakers--
+	m.mu.Unlock()
 	m.notify()
 }
 
@@ -167,7 +351,11 @@ func (m *Manager) NewThread(sessionKey, agentName string) (*Thread, error) {
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	return m.newThreadLocked(sessionKey, agentName)
+}
 
+// newThreadLocked is NewThread with m.mu held and sessionKey trimmed.
+func (m *Manager) newThreadLocked(sessionKey, agentName string) (*Thread, error) {
 	if t, ok := m.threads[sessionKey]; ok {
 		return t, nil
 	}
@@ -217,6 +405,53 @@ func (m *Manager) SetDefaultAgentFor(fn func(string) string) {
 	m.cfg.DefaultAgentFor = fn
 }
 
+// SetChannelQueuesFn configures how the health tool reads channel inbound
+// buffer depths. Channels are created after the manager, so this is late-bound.
+func (m *Manager) SetChannelQueuesFn(fn func() []tools.HealthChannelQueueInfo) {
+	m.cfg.ChannelQueuesFn = fn
+}
+
+// SetModelOverride routes every turn to mc regardless of agent specialty.
+// Nil restores normal routing.
+func (m *Manager) SetModelOverride(mc *config.ModelConfig) {
+	m.cfg.ModelOverride = mc
+}
+
+// Sessions returns the session manager, or nil when sessions are disabled.
+func (m *Manager) Sessions() *session.Manager {
+	return m.cfg.Sessions
+}
+
+// ProviderForRoute returns the provider mapped to route in thread.models,
+// falling back to the default provider without a factory.
+func (m *Manager) ProviderForRoute(route string) (provider.Provider, error) {
+	if m.cfg.ProviderFactory != nil {
+		return m.cfg.ProviderFactory.CreateForRoute(route)
+	}
+	if m.cfg.DefaultProvider == nil {
+		return nil, fmt.Errorf("no provider configured")
+	}
+	return m.cfg.DefaultProvider, nil
+}
+
+// ReloadConfig applies the hot-reloadable thread settings from cfg: provider
+// defaults and sampling (via the provider factory) and the exec tool timeout
+// and destructive-command guard.
+func (m *Manager) ReloadConfig(cfg *config.Config) error {
+	if m.cfg.Tools != nil {
+		if t, ok := m.cfg.Tools.Get("exec"); ok {
+			if exec, ok := t.(*tools.ExecTool); ok {
+				exec.SetDefaultTimeout(cfg.GetExecTimeout())
+				exec.SetConfirmDestructive(cfg.GetExecConfirmDestructive())
+			}
+		}
+	}
+	if m.cfg.ProviderFactory != nil {
+		return m.cfg.ProviderFactory.Reload(cfg)
+	}
+	return nil
+}
+
 // RegisterTool adds a tool to the shared tool registry.
 func (m *Manager) RegisterTool(t tools.Tool) {
 	if m.cfg.Tools != nil {
@@ -224,6 +459,19 @@ func (m *Manager) RegisterTool(t tools.Tool) {
 	}
 }
 
+// Abort cancels the turn currently running for sessionKey. The turn stops at
+// its next cancellation point, keeps what it already saved, and sends a
+// "stopped" notice to its sink. Returns false when no turn is running.
+func (m *Manager) Abort(sessionKey string) bool {
+	m.mu.Lock()
+	t, ok := m.threads[sessionKey]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	return t.abort()
+}
+
 // HasThread reports whether a thread exists for the given session key.
 func (m *Manager) HasThread(key string) bool {
 	m.mu.Lock()
@@ -275,7 +523,7 @@ func (m *Manager) SystemPrompt(sessionKey string) (string, bool) {
 	if !ok {
 		return "", false
 	}
-	return t.buildSystemPrompt(), true
+	return t.buildSystemPrompt(""), true
 }
 
 // ToolDefs returns the current tool definitions for the thread identified by
@@ -303,13 +551,16 @@ func (m *Manager) SessionStatus(sessionKey string) tools.SessionStatusInfo {
 	if m.cfg.Sessions != nil {
 		dir := m.SessionDir(sessionKey)
 		info.SessionDir = dir
-		path := m.cfg.Sessions.PathForKey(sessionKey)
-		if st, err := os.Stat(path); err == nil {
+		if m.cfg.Sessions.Exists(sessionKey) {
 			info.Exists = true
-			info.FileSizeBytes = st.Size()
-			info.LastModified = st.ModTime()
-			if s, readErr := session.ReadFile(path); readErr == nil {
+			if s, err := m.cfg.Sessions.Load(sessionKey); err == nil {
 				info.MessageCount = len(s.Messages)
+				info.LastModified = s.UpdatedAt
+			}
+			// File store only; other backends keep no per-session file.
+			if st, err := os.Stat(m.cfg.Sessions.PathForKey(sessionKey)); err == nil {
+				info.FileSizeBytes = st.Size()
+				info.LastModified = st.ModTime()
 			}
 		}
 		if dir != "" {
@@ -0,0 +1,43 @@
+package thread
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// metricsFooter renders the debugging footer appended to delivered replies
+// when thread.showMetricsFooter is set, e.g. "— glm-5 · 1.2k/420 tok · 3.1s".
+// It is plain text so it survives markdown conversion on every channel.
+func metricsFooter(model string, usage provider.Usage, elapsed time.Duration) string {
+	parts := make([]string, 0, 3)
+	if model = strings.TrimSpace(model); model != "" {
+		parts = append(parts, model)
+	}
+	parts = append(parts,
+		fmt.Sprintf("%s/%s tok", compactCount(usage.PromptTokens), compactCount(usage.CompletionTokens)),
+		fmt.Sprintf("%.1fs", elapsed.Seconds()),
+	)
+	return "— " + strings.Join(parts, " · ")
+}
+
+// compactCount formats n as "420", "1.2k" or "3.4M".
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// showMetricsFooter reports whether this turn's reply gets a metrics footer:
+// only when enabled in config and delivered to a user channel, and never for
+// heartbeat wakes or turns whose delivery was silenced.
+func (t *Thread) showMetricsFooter(sink Sink) bool {
+	return t.cfg().ShowMetricsFooter && sink.UserChannel && !t.IsHeartbeatWake() && !t.isSinkSuppressed()
+}
@@ -0,0 +1,76 @@
+package thread
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+func TestMetricsFooterFormat(t *testing.T) {
+	got := metricsFooter("glm-5", provider.Usage{PromptTokens: 1234, CompletionTokens: 420}, 3100*time.Millisecond)
+	if want := "— glm-5 · 1.2k/420 tok · 3.1s"; got != want {
+		t.Errorf("footer = %q, want %q", got, want)
+	}
+}
+
+// deliverOneReply runs a single telegram wake and returns what the sink got.
+func deliverOneReply(t *testing.T, showFooter bool) string {
+	t.Helper()
+	return deliverOneReplyTo(t, "telegram:1", showFooter, true)
+}
+
+// deliverOneReplyTo runs a single wake on key through a sink that is or is
+// not a user channel and returns what the sink got.
+func deliverOneReplyTo(t *testing.T, key string, showFooter, userChannel bool) string {
+	t.Helper()
+	p := &scriptedProvider{responses: []*provider.Response{{
+		Content:    "hello there",
+		ModelLabel: "glm-5",
+		Usage:      provider.Usage{PromptTokens: 1500, CompletionTokens: 42},
+	}}}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, ShowMetricsFooter: showFooter})
+	delivered := make(chan string, 1)
+	sink := Sink{
+		Label:       "test",
+		UserChannel: userChannel,
+		Send: func(_ context.Context, response string) error {
+			delivered <- response
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	mgr.Wake(key, &WakeMessage{Source: WakeTelegram, Message: "hi", Sink: sink})
+
+	select {
+	case got := <-delivered:
+		return got
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply delivered")
+		return ""
+	}
+}
+
+func TestMetricsFooterAppendedWhenEnabled(t *testing.T) {
+	got := deliverOneReply(t, true)
+	if !strings.HasPrefix(got, "hello there\n\n— glm-5 · 1.5k/42 tok · ") || !strings.HasSuffix(got, "s") {
+		t.Errorf("delivered = %q, want reply followed by metrics footer", got)
+	}
+}
+
+func TestMetricsFooterAbsentWhenDisabled(t *testing.T) {
+	if got := deliverOneReply(t, false); got != "hello there" {
+		t.Errorf("delivered = %q, want reply without footer", got)
+	}
+}
+
+func TestMetricsFooterOnlyOnUserChannelSinks(t *testing.T) {
+	if got := deliverOneReplyTo(t, "telegram:1:threads:worker", true, false); got != "hello there" {
+		t.Errorf("delivered = %q, want session-to-session reply without footer", got)
+	}
+}
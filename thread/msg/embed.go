@@ -0,0 +1,35 @@
+package msg
+
+import "strings"
+
+// Embed is a structured reply (e.g. a report) that channels with rich
+// formatting render natively, such as a Discord embed. Channels without
+// embeds deliver Markdown() as plain text.
+type Embed struct {
+	Title       string
+	Description string
+	Fields      []EmbedField
+	Color       int // RGB, e.g. 0x2ecc71; 0 = platform default
+}
+
+// EmbedField is one titled value in an Embed.
+type EmbedField struct {
+	Name   string
+	Value  string
+	Inline bool // hint: render side by side with neighbouring inline fields
+}
+
+// Markdown renders the embed as Markdown for text-only channels.
+func (e Embed) Markdown() string {
+	var b strings.Builder
+	if title := strings.TrimSpace(e.Title); title != "" {
+		b.WriteString("**" + title + "**\n\n")
+	}
+	if desc := strings.TrimSpace(e.Description); desc != "" {
+		b.WriteString(desc + "\n\n")
+	}
+	for _, f := range e.Fields {
+		b.WriteString("**" + strings.TrimSpace(f.Name) + "**\n" + strings.TrimSpace(f.Value) + "\n\n")
+	}
+	return strings.TrimSpace(b.String())
+}
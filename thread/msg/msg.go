@@ -69,6 +69,19 @@ type Sink struct {
 	Send      func(ctx context.Context, response string) error
 	React     ReactFunc // Optional: fire-and-forget emoji reaction on the source message.
 	Chunkable bool      // True for sinks that accept chunked streaming delivery (telegram, discord, feishu, cli).
+
+	// UserChannel marks sinks that deliver to a person on a chat channel,
+	// as opposed to another session or a programmatic caller. Only these
+	// replies get the metrics footer.
+	UserChannel bool
+
+	// SendFile optionally uploads a local file to the same destination.
+	// Nil when the underlying channel cannot upload files.
+	SendFile func(ctx context.Context, path, caption string) error
+
+	// SendEmbed optionally delivers a structured reply to the same
+	// destination, as a native embed where the channel has one.
+	SendEmbed func(ctx context.Context, embed Embed) error
 }
 
 // IsZero reports whether the sink has no delivery function.
@@ -138,6 +151,8 @@ const (
 	WakeFeishu         WakeSource = "feishu"
 	WakeWeCom          WakeSource = "wecom"
 	WakeSocket         WakeSource = "socket"
+	WakeCLI            WakeSource = "cli" // one-shot `nagobot run`
+	WakeAPI            WakeSource = "api" // synchronous HTTP chat API (POST /v1/chat)
 	WakeSession        WakeSource = "session" // another session woke us; caller in WakeMessage.CallerSessionKey
 	WakeCron           WakeSource = "cron"
 	WakeCompression    WakeSource = "compression"
@@ -147,10 +162,10 @@ const (
 )
 
 // IsUserVisibleSource reports whether the given source represents a real
-// user-initiated channel (telegram, discord, cli, web, feishu).
+// user-initiated channel (telegram, discord, cli, web, feishu, api).
 func IsUserVisibleSource(source WakeSource) bool {
 	switch source {
-	case WakeTelegram, WakeDiscord, WakeWeb, WakeFeishu, WakeWeCom, WakeSocket:
+	case WakeTelegram, WakeDiscord, WakeWeb, WakeFeishu, WakeWeCom, WakeSocket, WakeCLI, WakeAPI:
 		return true
 	}
 	return false
@@ -178,14 +193,67 @@ func CallerKindFromSource(source WakeSource) CallerKind {
 	return CallerKindSystem
 }
 
+// Wake queue priorities. Higher values are dequeued first; order within a
+// priority stays FIFO.
+const (
+	PriorityNormal = 1  // User channels and most system wakes.
+	PriorityHigh   = 10 // Cron and admin wakes, so they don't starve behind a user flood.
+)
+
 // WakeMessage is an item in a thread's wake queue.
 type WakeMessage struct {
 	Source            WakeSource        // Wake source.
 	Message           string            // Wake payload text.
+	Prepare           func(ctx context.Context) string // Optional: builds the payload at the start of the turn, under its context so /stop cancels it (e.g. translation). Message is then the raw preview.
 	Sink              Sink              // Per-wake sink. Zero value = no per-wake delivery.
 	AgentName         string            // Optional agent name override for this wake.
 	Vars              map[string]string // Optional vars override for this wake.
 	Sender            string            // Optional sender override (e.g. rephrase inherits original sender).
 	CallerSessionKey  string            // For Source=WakeSession: the session that woke us. Empty otherwise.
+	DryRun            bool              // Run mutating tools in simulation mode for this turn.
+	Admin             bool              // Sent by the configured admin; unlocks admin-only tools for this turn.
+	AllowedTools      []string          // Tools the waking session's turn was limited to; this turn is narrowed to them too. Nil = no inherited limit.
+	Timeout           time.Duration     // Per-turn deadline. Zero = subagent default for subagent sessions, none otherwise.
+	Priority          int               // Queue priority; higher drains first. Zero = default for Source (see EffectivePriority).
+	EnqueuedAt        time.Time         // Set by Thread.Enqueue; orders batched subagent results by completion.
 	OnComplete        func(response string) // Called after the turn completes with the full response text.
+	OnResult          func(TurnResult)      // Called after the turn completes with the response, usage, and error.
+}
+
+// TurnResult describes a finished turn for callers that need more than the
+// response text, such as one-shot CLI runs.
+type TurnResult struct {
+	Response string
+	Usage    TurnUsage
+	Err      error // nil unless the turn failed
+}
+
+// TurnUsage is the token usage of one turn, summed across its LLM calls.
+type TurnUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	CachedTokens     int `json:"cached_tokens,omitempty"`
+	ReasoningTokens  int `json:"reasoning_tokens,omitempty"`
+}
+
+// Text returns the payload text for the turn: Prepare's result when set,
+// Message otherwise.
+func (m *WakeMessage) Text(ctx context.Context) string {
+	if m.Prepare != nil {
+		return m.Prepare(ctx)
+	}
+	return m.Message
+}
+
+// EffectivePriority returns m.Priority, or the default for m.Source when
+// unset: cron wakes run at PriorityHigh, everything else at PriorityNormal.
+func (m *WakeMessage) EffectivePriority() int {
+	if m.Priority != 0 {
+		return m.Priority
+	}
+	if m.Source == WakeCron {
+		return PriorityHigh
+	}
+	return PriorityNormal
 }
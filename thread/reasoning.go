@@ -0,0 +1,94 @@
+package thread
+
+import "strings"
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// stripThinkTags removes reasoning that a provider folded into content as
+// <think>…</think> blocks. An unclosed <think> drops the rest of the text; a
+// stray </think> with no opening tag (some models omit it) drops everything
+// before it.
+func stripThinkTags(s string) string {
+	if !strings.Contains(s, thinkOpenTag) && !strings.Contains(s, thinkCloseTag) {
+		return s
+	}
+	if open, end := strings.Index(s, thinkOpenTag), strings.Index(s, thinkCloseTag); end >= 0 && (open < 0 || end < open) {
+		s = s[end+len(thinkCloseTag):]
+	}
+	var b strings.Builder
+	for {
+		open := strings.Index(s, thinkOpenTag)
+		if open < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:open])
+		rest := s[open+len(thinkOpenTag):]
+		end := strings.Index(rest, thinkCloseTag)
+		if end < 0 {
+			break
+		}
+		s = rest[end+len(thinkCloseTag):]
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// thinkTagFilter removes <think>…</think> blocks from streamed text. A tag
+// can be split across deltas, so a trailing fragment that may start a tag is
+// held back until the next delta decides it.
+type thinkTagFilter struct {
+	inThink bool
+	pending string
+}
+
+// Write consumes one delta and returns the text that is safe to show.
+func (f *thinkTagFilter) Write(delta string) string {
+	s := f.pending + delta
+	f.pending = ""
+	var out strings.Builder
+	for s != "" {
+		tag := thinkOpenTag
+		if f.inThink {
+			tag = thinkCloseTag
+		}
+		if i := strings.Index(s, tag); i >= 0 {
+			if !f.inThink {
+				out.WriteString(s[:i])
+			}
+			s = s[i+len(tag):]
+			f.inThink = !f.inThink
+			continue
+		}
+		keep := partialTagSuffix(s, tag)
+		if !f.inThink {
+			out.WriteString(s[:len(s)-keep])
+		}
+		f.pending = s[len(s)-keep:]
+		break
+	}
+	return out.String()
+}
+
+// Flush returns held-back text at the end of a stream.
+func (f *thinkTagFilter) Flush() string {
+	s := f.pending
+	f.pending = ""
+	if f.inThink {
+		return ""
+	}
+	return s
+}
+
+// partialTagSuffix returns the length of the longest suffix of s that is a
+// proper prefix of tag.
+func partialTagSuffix(s, tag string) int {
+	for n := min(len(s), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
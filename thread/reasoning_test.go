@@ -0,0 +1,89 @@
+package thread
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+func TestStripThinkTags(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain answer", "plain answer"},
+		{"<think>plan it</think>\n\nthe answer", "the answer"},
+		{"a<think>x</think>b<think>y</think>c", "abc"},
+		{"answer<think>cut off", "answer"},
+		{"leaked reasoning</think>answer", "answer"},
+	}
+	for _, tt := range tests {
+		if got := stripThinkTags(tt.in); got != tt.want {
+			t.Errorf("stripThinkTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestThinkTagFilterAcrossDeltas(t *testing.T) {
+	var f thinkTagFilter
+	var out strings.Builder
+	for _, d := range []string{"<thi", "nk>secret", " plan</th", "ink>Hello", " world <", "b>"} {
+		out.WriteString(f.Write(d))
+	}
+	out.WriteString(f.Flush())
+	if got := out.String(); got != "Hello world <b>" {
+		t.Errorf("filtered = %q, want %q", got, "Hello world <b>")
+	}
+}
+
+// deliverReasoningTurn runs one telegram wake against responses and returns
+// everything the sink received.
+func deliverReasoningTurn(t *testing.T, showReasoning bool, responses ...*provider.Response) []string {
+	t.Helper()
+	p := &scriptedProvider{responses: responses}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, ShowReasoning: showReasoning})
+	var sent []string
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	mgr.Wake("telegram:1", &WakeMessage{
+		Source:  WakeTelegram,
+		Message: "hi",
+		Sink: Sink{Send: func(_ context.Context, response string) error {
+			sent = append(sent, response)
+			return nil
+		}},
+		OnComplete: func(string) { close(done) },
+	})
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("turn did not complete")
+	}
+	return sent
+}
+
+func TestReasoningNotDeliveredWhenHidden(t *testing.T) {
+	sent := deliverReasoningTurn(t, false,
+		&provider.Response{ReasoningContent: "secret plan"},
+		&provider.Response{Content: "<think>more secret</think>the answer", ReasoningContent: "secret plan"},
+	)
+	if len(sent) != 1 || sent[0] != "the answer" {
+		t.Fatalf("sent = %q, want only the answer", sent)
+	}
+	for _, s := range sent {
+		if strings.Contains(s, "secret") {
+			t.Errorf("reasoning reached the sink: %q", s)
+		}
+	}
+}
+
+func TestReasoningDeliveredWhenShown(t *testing.T) {
+	sent := deliverReasoningTurn(t, true, &provider.Response{ReasoningContent: "answer in reasoning"})
+	if len(sent) != 1 || sent[0] != "answer in reasoning" {
+		t.Errorf("sent = %q, want the reasoning fallback", sent)
+	}
+}
@@ -20,23 +20,38 @@ import (
 
 // run executes one thread turn. Called by RunOnce; callers must not invoke
 // this directly.
-func (t *Thread) run(ctx context.Context, userMessage string, sink Sink, callerKey string, injectFn func() []provider.Message, wakeSource string) (string, error) {
+func (t *Thread) run(ctx context.Context, userMessage string, sink Sink, callerKey string, injectFn func() []provider.Message, wakeSource string) (string, provider.Usage, error) {
 	userMessage = strings.TrimSpace(userMessage)
 	if userMessage == "" {
-		return "", nil
+		return "", provider.Usage{}, nil
 	}
 
 	cfg := t.cfg()
-	systemPrompt := t.buildSystemPrompt()
+	turnTools := t.channelTools(wakeSource)
+	t.mu.Lock()
+	t.turnTools = turnTools
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.turnTools = nil
+		t.mu.Unlock()
+	}()
+	systemPrompt := t.buildSystemPrompt(wakeSource)
+	// Reject before write-ahead so an oversized message never enters history.
+	if err := t.preflightContext(systemPrompt, userMessage); err != nil {
+		return "", provider.Usage{}, err
+	}
 	sess := t.loadSession()
 	messages, turnUserMessages := t.buildMessageHistory(ctx, systemPrompt, userMessage, sess)
 
 	// Write-ahead: persist user messages before LLM call so they survive a crash.
 	if sess != nil {
-		if wakeSource != "" {
-			for i := range turnUserMessages {
+		admin := t.isAdmin()
+		for i := range turnUserMessages {
+			if wakeSource != "" {
 				turnUserMessages[i].Source = wakeSource
 			}
+			turnUserMessages[i].Admin = admin
 		}
 		if err := cfg.Sessions.Append(t.sessionKey, turnUserMessages...); err != nil {
 			logger.Warn("write-ahead save failed", "key", t.sessionKey, "err", err)
@@ -55,18 +70,32 @@ func (t *Thread) run(ctx context.Context, userMessage string, sink Sink, callerK
 		t.mu.Unlock()
 	}()
 
-	runCtx := tools.WithRuntimeContext(ctx, tools.RuntimeContext{
+	caps := t.currentCapabilities()
+	rt := tools.RuntimeContext{
 		SessionKey:            t.sessionKey,
 		Workspace:             cfg.Workspace,
 		SessionDir:            t.mgr.SessionDir(t.sessionKey),
-		SupportsVision:        t.currentModelSupportsVision(),
-		SupportsAudio:         t.currentModelSupportsAudio(),
-		SupportsPDF:           t.currentModelSupportsPDF(),
+		SupportsVision:        caps.Vision,
+		SupportsAudio:         caps.Audio,
+		SupportsPDF:           caps.PDF,
 		ImageReaderConfigured: cfg.Agents != nil && cfg.Agents.Def("imagereader") != nil,
 		AudioReaderConfigured: cfg.Agents != nil && cfg.Agents.Def("audioreader") != nil,
 		PDFReaderConfigured:   cfg.Agents != nil && cfg.Agents.Def("pdfreader") != nil,
-	})
+		DryRun:                t.isDryRun(),
+		Admin:                 t.isAdmin(),
+		SendFile:              sink.SendFile,
+		SendEmbed:             sink.SendEmbed,
+		ModelOverrideChanged:  t.invalidateModelOverride,
+	}
+	if !sink.IsZero() {
+		rt.BeginDraft = t.beginDraft
+		rt.FinalizeDraft = func(ctx context.Context, text string) error {
+			return t.finalizeDraft(ctx, sink, text)
+		}
+	}
+	runCtx := tools.WithRuntimeContext(ctx, rt)
 	t.resetHaltLoop()
+	defer t.clearScratchpad()
 	t.mu.Lock()
 	t.currentSink = sink
 	t.currentCallerKey = callerKey
@@ -79,7 +108,7 @@ func (t *Thread) run(ctx context.Context, userMessage string, sink Sink, callerK
 	}()
 	p := t.resolveProvider()
 	if p == nil {
-		return noProviderMessage(), nil
+		return noProviderMessage(), provider.Usage{}, nil
 	}
 
 	// Incremental persistence: save each message as it arrives during the agentic loop.
@@ -98,7 +127,10 @@ func (t *Thread) run(ctx context.Context, userMessage string, sink Sink, callerK
 	response, _, usage, _, providerLabel, modelLabel, err := t.executeRunner(ctx, runCtx, p, metrics, messages, sink, injectFn, persistMsg)
 	if err != nil {
 		t.recordTurn(metrics, "", "", "", usage, true)
-		return "", err
+		if provider.IsContextLengthError(err) {
+			err = t.contextOverflowError(err)
+		}
+		return "", usage, err
 	}
 	providerName, modelName := providerLabel, modelLabel
 	if providerName == "" || modelName == "" {
@@ -111,11 +143,21 @@ func (t *Thread) run(ctx context.Context, userMessage string, sink Sink, callerK
 	}
 	t.mu.Unlock()
 	t.recordTurn(metrics, providerName, modelName, agentName, usage, false)
-	return response, nil
+	return response, usage, nil
+}
+
+// clearScratchpad drops the scratchpad notes kept during the turn.
+func (t *Thread) clearScratchpad() {
+	if tool, ok := t.tools.Get("scratchpad"); ok {
+		if sp, ok := tool.(*tools.ScratchpadTool); ok {
+			sp.Clear(t.sessionKey)
+		}
+	}
 }
 
 // buildSystemPrompt assembles the system prompt from the active agent.
-func (t *Thread) buildSystemPrompt() string {
+// wakeSource selects the per-channel prompt snippet; see promptChannel.
+func (t *Thread) buildSystemPrompt(wakeSource string) string {
 	t.mu.Lock()
 	activeAgent := t.Agent
 	t.mu.Unlock()
@@ -127,11 +169,19 @@ func (t *Thread) buildSystemPrompt() string {
 	skillsSection := t.buildSkillsSection()
 	activeAgent.SetLocation(t.location())
 	activeAgent.SetSections(t.cfg().Sections)
-	activeAgent.Set("TOOLS", t.tools.Names())
+	activeAgent.Set("TOOLS", t.activeTools().Names())
+	activeAgent.Set("MODEL", t.buildModelSection())
 	activeAgent.Set("SKILLS", skillsSection)
 	activeAgent.Set(agent.SectionUserMemory, t.buildUserSection())
 	activeAgent.Set(agent.SectionHeartbeatPrompt, t.buildHeartbeatSection())
 	activeAgent.Set(agent.SectionMemoryIndex, t.buildMemoryIndexSection())
+	channel := t.promptChannel(wakeSource)
+	channelPrompt := ""
+	if fn := t.cfg().ChannelPromptFor; fn != nil && channel != "" {
+		channelPrompt = fn(channel)
+	}
+	activeAgent.SetChannel(channel, channelPrompt)
+	activeAgent.SetPinned(t.buildPinnedSection())
 	prompt := activeAgent.Build()
 	if strings.TrimSpace(prompt) == "" {
 		return "You are a helpful AI assistant."
@@ -139,6 +189,21 @@ func (t *Thread) buildSystemPrompt() string {
 	return prompt
 }
 
+// promptChannel returns the channel whose prompt snippet applies to a turn:
+// the wake source when a channel user woke the thread, otherwise the channel
+// the session belongs to (from its key prefix, e.g. "discord:123"). Returns
+// "" when neither names a chat channel.
+func (t *Thread) promptChannel(wakeSource string) string {
+	if sysmsg.IsUserVisibleSource(WakeSource(wakeSource)) {
+		return wakeSource
+	}
+	prefix, _, _ := strings.Cut(t.sessionKey, ":")
+	if sysmsg.IsUserVisibleSource(WakeSource(prefix)) {
+		return prefix
+	}
+	return ""
+}
+
 // buildMessageHistory assembles the full message list for the LLM request,
 // including system prompt, session history, user message, and hook injections.
 // Returns the full messages slice and the turn-specific user messages (for write-ahead).
@@ -152,8 +217,8 @@ func (t *Thread) buildMessageHistory(ctx context.Context, systemPrompt, userMess
 	// Compute precise session budget by subtracting known overhead from context window.
 	systemPromptTokens := EstimateMessageTokens(messages[0])
 	userMsgTokens := EstimateTextTokens(userMessage) + 6
-	toolDefsTokens := EstimateToolDefsTokens(t.tools.Defs())
-	maxCompletionTokens := t.cfg().MaxCompletionTokens
+	toolDefsTokens := EstimateToolDefsTokens(t.activeTools().Defs())
+	maxCompletionTokens := t.completionReserve()
 	sessionBudget := int(float64(contextWindowTokens-systemPromptTokens-userMsgTokens-toolDefsTokens-maxCompletionTokens) * 0.96)
 	if sessionBudget < 0 {
 		sessionBudget = 0
@@ -210,14 +275,20 @@ func (t *Thread) buildMessageHistory(ctx context.Context, systemPrompt, userMess
 // executeRunner runs the agentic loop with streaming and message callbacks.
 func (t *Thread) executeRunner(ctx, runCtx context.Context, p provider.Provider, metrics *ExecMetrics, messages []provider.Message, sink Sink, injectFn func() []provider.Message, persistMsg func(provider.Message)) (response string, intermediates []provider.Message, usage provider.Usage, quota *provider.Quota, providerLabel string, modelLabel string, err error) {
 	contextWindowTokens := t.contextBudget().ContextWindow
-	maxCompletionTokens := t.cfg().MaxCompletionTokens
+	maxCompletionTokens := t.completionReserve()
 	loopBudget := int(float64(contextWindowTokens-maxCompletionTokens) * 0.9)
 	if loopBudget < 0 {
 		loopBudget = 0
 	}
-	runner := NewRunner(p, t.tools, metrics, loopBudget)
+	runner := NewRunner(p, t.activeTools(), metrics, loopBudget)
 	runner.ShouldHalt(t.isHaltLoop)
 	runner.SetUserVisible(sysmsg.IsUserVisibleSource(t.lastWakeSource))
+	runner.SetSessionKey(t.sessionKey)
+	runner.SetMaxIterations(t.agentMaxToolIterations())
+	runner.SetMaxContinuations(t.cfg().MaxContinuations)
+	runner.SetToolResultMaxTokens(t.cfg().ToolResultMaxTokens)
+	showReasoning := t.cfg().ShowReasoning
+	runner.SetHideReasoning(!showReasoning)
 
 	// Persist per-call estimation accuracy ratios into the session's meta.json.
 	if cfg := t.cfg(); cfg.Sessions != nil && t.sessionKey != "" {
@@ -247,14 +318,25 @@ func (t *Thread) executeRunner(ctx, runCtx context.Context, p provider.Provider,
 	useStreaming := !t.IsHeartbeatWake() && !sink.IsZero() && sink.Chunkable
 	if useStreaming {
 		streamer = NewMarkdownStreamer(sink, ctx, streamFlushThreshold)
+		var thinkFilter thinkTagFilter
 		runner.OnStream(func(streamID, delta string) {
-			if ctx.Err() != nil || t.isSinkSuppressed() {
+			if ctx.Err() != nil || t.isSinkSuppressed() || t.isDrafting() {
 				return
 			}
 			if delta == "" {
+				if !showReasoning {
+					if rest := thinkFilter.Flush(); rest != "" {
+						streamer.OnDelta(rest)
+					}
+				}
 				streamer.Flush() // end-of-stream signal: flush remaining buffer
 				return
 			}
+			if !showReasoning {
+				if delta = thinkFilter.Write(delta); delta == "" {
+					return
+				}
+			}
 			streamer.OnDelta(delta)
 		})
 	}
@@ -271,17 +353,43 @@ func (t *Thread) executeRunner(ctx, runCtx context.Context, p provider.Provider,
 			return
 		}
 
-		// 2. Delivery (non-streaming path).
-		if sink.IsZero() || t.isSinkSuppressed() || !isUserFacingContent(m.Content) {
+		// 2. Delivery (non-streaming path). Reasoning folded into content
+		// is stripped unless thread.showReasoning is on.
+		reply := m.Content
+		if !showReasoning {
+			reply = stripThinkTags(reply)
+		}
+		if sink.IsZero() || t.isSinkSuppressed() || !isUserFacingContent(reply) {
+			return
+		}
+		drafting := t.isDrafting()
+		if drafting && len(m.ToolCalls) > 0 {
+			// Drafting: hold intermediate content until finalize.
+			t.holdDraft(reply)
 			return
 		}
-		if streamer != nil && streamer.DidSend() {
-			return // streaming already delivered this content
+		footer := ""
+		if len(m.ToolCalls) == 0 && t.showMetricsFooter(sink) {
+			model := runner.ModelLabel()
+			if model == "" {
+				_, model = t.resolvedProviderModel()
+			}
+			footer = metricsFooter(model, runner.TotalUsage(), time.Since(metrics.TurnStart))
+		}
+		if streamer != nil && streamer.DidSend() && !drafting {
+			// Streaming already delivered this content; the footer follows
+			// as its own message.
+			if footer != "" {
+				if err := sink.Send(ctx, footer); err != nil {
+					logger.Warn("metrics footer delivery failed", "key", t.sessionKey, "sink", sink.Label, "err", err)
+				}
+			}
+			return
 		}
 		if len(m.ToolCalls) > 0 {
 			// Intermediate: deliver for chunkable sinks only.
 			if sink.Chunkable {
-				if err := sink.Send(ctx, m.Content); err != nil {
+				if err := sink.Send(ctx, reply); err != nil {
 					logger.Warn("intermediate delivery failed", "key", t.sessionKey, "sink", sink.Label, "err", err)
 				} else {
 					t.markDefaultReplyForwarded()
@@ -289,7 +397,11 @@ func (t *Thread) executeRunner(ctx, runCtx context.Context, p provider.Provider,
 			}
 		} else {
 			// Final response: deliver with retry.
-			if err := sink.WithRetry(3).Send(ctx, m.Content); err != nil {
+			content := reply
+			if footer != "" {
+				content += "\n\n" + footer
+			}
+			if err := sink.WithRetry(3).Send(ctx, content); err != nil {
 				logger.Warn("final delivery failed", "key", t.sessionKey, "sink", sink.Label, "err", err)
 			} else {
 				t.markDefaultReplyForwarded()
@@ -335,6 +447,19 @@ func (t *Thread) buildUserSection() string {
 	return fmt.Sprintf("---\ntype: user_preference\nfile_path: %s\nprompt: %s\n---\n\n%s", absPath, prompt, text)
 }
 
+// buildPinnedSection lists the session's pinned notes, one per line.
+func (t *Thread) buildPinnedSection() string {
+	if t.mgr == nil {
+		return ""
+	}
+	pins := session.ReadPins(t.mgr.SessionDir(t.sessionKey))
+	lines := make([]string, 0, len(pins))
+	for _, p := range pins {
+		lines = append(lines, "- ["+p.ID+"] "+p.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // buildHeartbeatSection resolves the per-session heartbeat.md into a YAML-frontmattered section.
 func (t *Thread) buildHeartbeatSection() string {
 	sessionPath, ok := t.sessionFilePath()
@@ -477,11 +602,18 @@ func ApplyCompressedMessage(m provider.Message) provider.Message {
 	return m
 }
 
-// resolvedModelConfig returns the model config for the current agent's model type,
+// resolvedModelConfig returns the model config for the current agent's model type
+// (or the manager-wide ModelOverride, then the session's set_model override),
 // or nil if the agent uses the default provider.
 // Uses ModelsFn for hot-reload if available, falling back to the startup snapshot.
 func (t *Thread) resolvedModelConfig() *config.ModelConfig {
 	cfg := t.cfg()
+	if cfg.ModelOverride != nil {
+		return cfg.ModelOverride
+	}
+	if mo := t.sessionModelOverride(); mo != nil {
+		return mo
+	}
 	if t.Agent == nil || cfg.Agents == nil {
 		return nil
 	}
@@ -493,33 +625,35 @@ func (t *Thread) resolvedModelConfig() *config.ModelConfig {
 	if cfg.ModelsFn != nil {
 		models = cfg.ModelsFn()
 	}
-	// Explicit routing table lookup.
-	if len(models) > 0 {
-		if mc, ok := models[def.Specialty]; ok && mc != nil {
-			return mc
-		}
-	}
-	// Implicit: specialty "provider/model" format → auto-route.
-	if prov, model, ok := strings.Cut(def.Specialty, "/"); ok && provider.IsSupportedModel(model) {
-		return &config.ModelConfig{
-			Provider:  prov,
-			ModelType: model,
-		}
+	return provider.RouteSpecialty(models, def.Specialty, def.Provider)
+}
+
+// sessionModelOverride returns the provider/model chosen for this session
+// with the set_model tool, or nil when none is set. meta.json is read once
+// and cached until invalidateModelOverride.
+func (t *Thread) sessionModelOverride() *config.ModelConfig {
+	if t.mgr == nil {
+		return nil
 	}
-	// Implicit: bare model name with provider from frontmatter or registry lookup.
-	if provider.IsSupportedModel(def.Specialty) {
-		prov := def.Provider
-		if prov == "" {
-			prov = provider.ProviderForModel(def.Specialty)
-		}
-		if prov != "" {
-			return &config.ModelConfig{
-				Provider:  prov,
-				ModelType: def.Specialty,
-			}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.modelOverrideLoaded {
+		t.modelOverride = nil
+		mo := session.ReadMeta(t.mgr.SessionDir(t.sessionKey)).Model
+		if mo != nil && mo.Provider != "" && mo.ModelType != "" {
+			t.modelOverride = &config.ModelConfig{Provider: mo.Provider, ModelType: mo.ModelType}
 		}
+		t.modelOverrideLoaded = true
 	}
-	return nil
+	return t.modelOverride
+}
+
+// invalidateModelOverride drops the cached set_model override so the next
+// sessionModelOverride re-reads meta.json.
+func (t *Thread) invalidateModelOverride() {
+	t.mu.Lock()
+	t.modelOverrideLoaded = false
+	t.mu.Unlock()
 }
 
 func noProviderMessage() string {
@@ -579,32 +713,38 @@ func (t *Thread) recordTurn(metrics *ExecMetrics, providerName, modelName, agent
 	})
 }
 
-// currentModelSupportsVision returns whether the current thread's model supports vision.
-func (t *Thread) currentModelSupportsVision() bool {
-	mc := t.resolvedModelConfig()
-	if mc != nil {
-		return provider.SupportsVision(mc.Provider, mc.ModelType)
-	}
-	cfg := t.cfg()
-	return provider.SupportsVision(cfg.ProviderName, cfg.ModelName)
+// currentCapabilities returns the capabilities of the thread's current model.
+func (t *Thread) currentCapabilities() provider.ModelCapabilities {
+	return provider.Capabilities(t.resolvedProviderModel())
 }
 
-func (t *Thread) currentModelSupportsAudio() bool {
-	mc := t.resolvedModelConfig()
-	if mc != nil {
-		return provider.SupportsAudio(mc.Provider, mc.ModelType)
+// buildModelSection describes the current model to itself: what input it
+// can read, whether reasoning is on and how large its context window is.
+// Replaces {{MODEL}} in the system prompt.
+func (t *Thread) buildModelSection() string {
+	provName, modelName := t.resolvedProviderModel()
+	if modelName == "" {
+		return "unknown"
 	}
-	cfg := t.cfg()
-	return provider.SupportsAudio(cfg.ProviderName, cfg.ModelName)
-}
-
-func (t *Thread) currentModelSupportsPDF() bool {
-	mc := t.resolvedModelConfig()
-	if mc != nil {
-		return provider.SupportsPDF(mc.Provider, mc.ModelType)
+	caps := provider.Capabilities(provName, modelName)
+	inputs := []string{"text"}
+	if caps.Vision {
+		inputs = append(inputs, "images")
 	}
-	cfg := t.cfg()
-	return provider.SupportsPDF(cfg.ProviderName, cfg.ModelName)
+	if caps.Audio {
+		inputs = append(inputs, "audio")
+	}
+	if caps.PDF {
+		inputs = append(inputs, "PDF")
+	}
+	parts := []string{modelName + " via " + provName, "input: " + strings.Join(inputs, ", ")}
+	if caps.Reasoning {
+		parts = append(parts, "reasoning: on")
+	}
+	if window := t.contextBudget().ContextWindow; window > 0 {
+		parts = append(parts, "context window: "+compactCount(window)+" tokens")
+	}
+	return strings.Join(parts, "; ")
 }
 
 // resolveProvider returns the provider for the current agent's model type,
@@ -613,9 +753,10 @@ func (t *Thread) currentModelSupportsPDF() bool {
 func (t *Thread) resolveProvider() provider.Provider {
 	cfg := t.cfg()
 
+	sampling := t.agentSampling()
 	mc := t.resolvedModelConfig()
 	if mc != nil && cfg.ProviderFactory != nil {
-		p, err := cfg.ProviderFactory.Create(mc.Provider, mc.ModelType)
+		p, err := cfg.ProviderFactory.CreateGuarded(mc.Provider, mc.ModelType, sampling)
 		if err == nil {
 			return p
 		}
@@ -624,7 +765,7 @@ func (t *Thread) resolveProvider() provider.Provider {
 
 	// Always try factory for default provider (picks up config changes).
 	if cfg.ProviderFactory != nil {
-		p, err := cfg.ProviderFactory.Create("", "")
+		p, err := cfg.ProviderFactory.CreateGuarded("", "", sampling)
 		if err == nil {
 			return p
 		}
@@ -633,6 +774,34 @@ func (t *Thread) resolveProvider() provider.Provider {
 	return t.provider
 }
 
+// agentSampling returns the current agent's sampling overrides (temperature,
+// max_tokens, reasoning_effort) from its template frontmatter. Unset fields
+// keep the thread defaults.
+func (t *Thread) agentSampling() provider.Sampling {
+	cfg := t.cfg()
+	if t.Agent == nil || cfg.Agents == nil {
+		return provider.Sampling{}
+	}
+	def := cfg.Agents.Def(t.Agent.Name)
+	if def == nil {
+		return provider.Sampling{}
+	}
+	return provider.Sampling{MaxTokens: def.MaxTokens, Temperature: def.Temperature, ReasoningEffort: def.ReasoningEffort}
+}
+
+// agentMaxToolIterations returns the current agent's max_tool_iterations
+// override, or 0 for the runner default.
+func (t *Thread) agentMaxToolIterations() int {
+	cfg := t.cfg()
+	if t.Agent == nil || cfg.Agents == nil {
+		return 0
+	}
+	if def := cfg.Agents.Def(t.Agent.Name); def != nil {
+		return def.MaxToolIterations
+	}
+	return 0
+}
+
 func (t *Thread) buildTools() *tools.Registry {
 	cfg := t.cfg()
 	reg := tools.NewRegistry()
@@ -661,6 +830,34 @@ func (t *Thread) buildTools() *tools.Registry {
 		ThreadsListFn: func() []tools.ThreadInfo {
 			return t.mgr.ListThreads()
 		},
+		PingFn: func(ctx context.Context) error {
+			return provider.Ping(ctx, t.resolveProvider())
+		},
+		BreakersFn: func() []tools.HealthBreakerInfo {
+			var out []tools.HealthBreakerInfo
+			for _, s := range cfg.ProviderFactory.BreakerStatuses() {
+				info := tools.HealthBreakerInfo{
+					Provider:            s.Provider,
+					State:               s.State,
+					ConsecutiveFailures: s.ConsecutiveFailures,
+					LastError:           s.LastError,
+				}
+				if !s.RetryAt.IsZero() {
+					info.RetryAt = s.RetryAt.Format(time.RFC3339)
+				}
+				out = append(out, info)
+			}
+			return out
+		},
+		QueuesFn: cfg.ChannelQueuesFn,
+		TurnsFn: func() *tools.HealthTurnsInfo {
+			inFlight, limit := t.mgr.InFlightTurns()
+			return &tools.HealthTurnsInfo{
+				InFlight: inFlight,
+				Waiting:  max(t.mgr.ActiveCount()-inFlight, 0),
+				Limit:    limit,
+			}
+		},
 		CtxFn: func() tools.HealthRuntimeContext {
 			sessionPath, _ := t.sessionFilePath() // ok ignored: empty path is acceptable
 			t.mu.Lock()
@@ -681,7 +878,23 @@ func (t *Thread) buildTools() *tools.Registry {
 		},
 	})
 
+	reg.Register(&tools.SysInfoTool{
+		Workspace:  cfg.Workspace,
+		ModelFn:    t.resolvedProviderModel,
+		LocationFn: t.location,
+	})
 	reg.Register(tools.NewDispatchTool(t))
+	var sessions tools.SessionReader
+	if cfg.Sessions != nil {
+		sessions = cfg.Sessions
+	}
+	reg.Register(tools.NewSummarizeSessionTool(sessions, func() (provider.Provider, error) {
+		if cfg.ProviderFactory == nil {
+			return t.resolveProvider(), nil
+		}
+		return cfg.ProviderFactory.CreateForRoute(tools.SessionSummaryRoute)
+	}))
+	reg.Register(tools.NewDescribeToolsTool(reg))
 
 	return reg
 }
@@ -16,9 +16,27 @@ import (
 
 // maxIterations caps the agent loop. If the model fails to terminate
 // (no final response, no halt-requesting tool) within this many tool
-// iterations, the loop aborts to prevent runaway token spend.
+// iterations, the loop stops to prevent runaway token spend. Agents can
+// override it with max_tool_iterations in their frontmatter.
 const maxIterations = 100
 
+// emptyResponseNudge is sent once when the model ends a turn with no text
+// and no tool calls, so the user is not left without a reply.
+const emptyResponseNudge = "Your last reply was empty. Please provide the final answer to the user now."
+
+// defaultMaxContinuations caps how many times a final reply cut off by the
+// output token limit is continued before it is returned as is.
+const defaultMaxContinuations = 2
+
+// continuationPrompt asks the model to resume a reply cut off by the output
+// token limit.
+const continuationPrompt = "Your previous reply was cut off by the output token limit. Continue exactly where you left off, without repeating anything or adding a preamble."
+
+// defaultToolResultMaxTokens caps the estimated size of a single tool result
+// added to the conversation. The per-tool byte caps (exec, web_fetch,
+// read_file) are far looser than what one result should take of the window.
+const defaultToolResultMaxTokens = 16000
+
 // Runner is a generic agent loop executor.
 type Runner struct {
 	provider       provider.Provider
@@ -39,6 +57,15 @@ type Runner struct {
 	modelLabel      string             // effective model name from last response
 	userVisible     bool               // true when the current turn was triggered by a user-visible message
 	iterations      int                // number of tool-call iterations completed
+	maxIterations   int                // tool-call iteration cap; 0 = maxIterations
+	sessionKey      string             // for logging only
+	partial         string             // latest non-empty assistant text, returned if the cap is hit
+	emptyRetried    bool               // true once an empty final response has been retried
+	hideReasoning   bool               // never fall back to reasoning_content as the answer
+	maxContinuations int               // cap on continuations of truncated replies; 0 = default, < 0 = off
+	continuations   int                // continuations requested so far
+	continued       string             // text of truncated replies awaiting their continuation
+	toolResultMaxTokens int            // per-result token cap; 0 = default, < 0 = off
 }
 
 // RunnerEvent identifies a lifecycle event in the agentic loop.
@@ -56,6 +83,10 @@ const (
 // Chat(). An empty delta signals the end of the stream (Chat() returned).
 func (r *Runner) OnStream(fn func(streamID, delta string)) { r.onStream = fn }
 
+// SetHideReasoning stops the runner from using reasoning_content as the
+// answer when a final response has empty content; it nudges instead.
+func (r *Runner) SetHideReasoning(hide bool) { r.hideReasoning = hide }
+
 // OnEvent sets a callback for lifecycle events (tool calls, etc.).
 // Each event fires at most once per Chat() round.
 func (r *Runner) OnEvent(fn func(event RunnerEvent, detail string)) { r.onEvent = fn }
@@ -81,6 +112,22 @@ func (r *Runner) OnEstimationSample(fn func(providerName, modelName string, rati
 	r.onEstimationSample = fn
 }
 
+// SetMaxIterations overrides the tool-call iteration cap. Non-positive
+// values keep the default (maxIterations).
+func (r *Runner) SetMaxIterations(n int) { r.maxIterations = n }
+
+// SetMaxContinuations sets how many times a final reply cut off by the
+// output token limit is continued. Zero keeps the default; negative
+// disables continuation.
+func (r *Runner) SetMaxContinuations(n int) { r.maxContinuations = n }
+
+// SetToolResultMaxTokens caps the estimated tokens of each tool result added
+// to the conversation. Zero uses the default; negative disables the cap.
+func (r *Runner) SetToolResultMaxTokens(n int) { r.toolResultMaxTokens = n }
+
+// SetSessionKey labels the runner's log lines with the session it serves.
+func (r *Runner) SetSessionKey(key string) { r.sessionKey = key }
+
 // SetUserVisible marks this runner as handling a user-visible turn.
 func (r *Runner) SetUserVisible(v bool) { r.userVisible = v }
 
@@ -120,9 +167,10 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 			return "", ctx.Err()
 		}
 
-		if r.iterations >= maxIterations {
-			logger.Warn("max iterations reached, aborting agent loop", "iterations", r.iterations)
-			return "", fmt.Errorf("max iterations (%d) reached without final response", maxIterations)
+		if limit := r.iterationLimit(); r.iterations >= limit {
+			logger.Warn("max tool iterations reached, stopping agent loop",
+				"sessionKey", r.sessionKey, "iterations", r.iterations, "limit", limit)
+			return r.capMessage(limit), nil
 		}
 
 		if r.metrics != nil {
@@ -150,6 +198,7 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 		var streamID string
 		streamingSignaled := false
 		toolCallSignaled := false
+		var streamedArgs toolArgsAssembler
 
 		if stream, ok := result.(provider.StreamChatResult); ok {
 			streamID = RandomHex(8)
@@ -183,6 +232,8 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 						toolCallSignaled = true
 						r.onEvent(EventToolCalls, delta.ToolName)
 					}
+				case provider.DeltaToolCallArgs:
+					streamedArgs.add(delta)
 				}
 			}
 		}
@@ -214,6 +265,55 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 		// Log estimation accuracy for calibration.
 		r.logEstimationAccuracy(messages, resp)
 
+		// A final reply cut off by the output token limit: ask the model to
+		// continue and join the pieces into one reply.
+		if !resp.HasToolCalls() && resp.Truncated() && strings.TrimSpace(resp.Content) != "" {
+			if limit := r.continuationLimit(); r.continuations < limit {
+				r.continuations++
+				logger.Warn("response hit output token limit, requesting continuation",
+					"sessionKey", r.sessionKey, "provider", resp.ProviderLabel, "model", resp.ModelLabel,
+					"finishReason", resp.FinishReason, "continuation", r.continuations, "limit", limit)
+				r.continued += resp.Content
+				messages = append(messages,
+					provider.AssistantMessageWithTools(resp.Content, resp.ReasoningContent, resp.ReasoningDetails, nil),
+					provider.Message{
+						Role:    "user",
+						Content: msg.BuildSystemMessage("continuation", nil, continuationPrompt),
+						Source:  "system",
+					})
+				continue
+			}
+		}
+		if r.continued != "" {
+			resp.Content = r.continued + resp.Content
+			r.continued = ""
+		}
+
+		if !resp.HasToolCalls() && strings.TrimSpace(resp.Content) == "" {
+			// Some reasoning models put the whole answer in reasoning_content
+			// and leave content empty. Use the reasoning if there is any;
+			// otherwise nudge the model once for a real answer.
+			if reasoning := strings.TrimSpace(resp.ReasoningContent); reasoning != "" && !r.hideReasoning {
+				logger.Warn("empty final response, falling back to reasoning content",
+					"provider", resp.ProviderLabel, "model", resp.ModelLabel, "reasoningChars", len(reasoning))
+				resp.Content = reasoning
+			} else if !r.emptyRetried {
+				r.emptyRetried = true
+				logger.Warn("empty final response, retrying with nudge",
+					"provider", resp.ProviderLabel, "model", resp.ModelLabel)
+				nudge := provider.Message{
+					Role:    "user",
+					Content: msg.BuildSystemMessage("empty_response", nil, emptyResponseNudge),
+					Source:  "system",
+				}
+				messages = append(messages, nudge)
+				if r.onMessage != nil {
+					r.onMessage(nudge)
+				}
+				continue
+			}
+		}
+
 		if !resp.HasToolCalls() {
 			// Fallback: fire EventStreaming for final response if not already signaled.
 			if resp.Content != "" && !streamingSignaled && r.onEvent != nil {
@@ -239,18 +339,15 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 			r.onEvent(EventToolCalls, resp.ToolCalls[0].Function.Name)
 		}
 
-		// Sanitize malformed tool call arguments before persistence.
-		// Some models (e.g. Qwen) occasionally produce invalid JSON in streaming.
-		// Replace with "{}" so the session history stays valid; generate a
-		// descriptive error result instead of executing the tool.
-		invalidArgs := make(map[string]string) // tc.ID → original malformed args
-		for i, tc := range resp.ToolCalls {
-			if !json.Valid([]byte(tc.Function.Arguments)) {
-				invalidArgs[tc.ID] = tc.Function.Arguments
-				resp.ToolCalls[i].Function.Arguments = "{}"
-				logger.Warn("sanitized malformed tool call arguments",
-					"tool", tc.Function.Name, "original", tc.Function.Arguments)
-			}
+		// Finalize tool call arguments before persistence or execution.
+		// Some models (e.g. Qwen) occasionally produce invalid JSON in
+		// streaming, and a provider can mis-assemble streamed fragments. A tool
+		// only runs on a complete JSON object; anything else gets a
+		// descriptive error result instead.
+		invalidArgs := finalizeToolCalls(resp.ToolCalls, &streamedArgs) // tc.ID → original malformed args
+
+		if strings.TrimSpace(resp.Content) != "" {
+			r.partial = resp.Content
 		}
 
 		assistantMsg := provider.AssistantMessageWithTools(resp.Content, resp.ReasoningContent, resp.ReasoningDetails, resp.ToolCalls)
@@ -266,20 +363,30 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 			}
 
 			start := time.Now()
-			var result string
+			var res tools.ToolResult
 			if orig, bad := invalidArgs[tc.ID]; bad {
-				result = fmt.Sprintf("Error: malformed tool call arguments (invalid JSON).\nOriginal: %s\nExpected: valid JSON object for %s.", orig, tc.Function.Name)
+				res = tools.ToolResult{
+					Content: fmt.Sprintf("Error: malformed tool call arguments (invalid JSON).\nOriginal: %s\nExpected: valid JSON object for %s.", orig, tc.Function.Name),
+					IsError: true,
+				}
 			} else {
 				toolCtx := provider.WithAssistantContent(ctx, resp.Content)
-				result = r.tools.Run(toolCtx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
+				res = r.tools.RunResult(toolCtx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
 			}
-			if tools.IsToolError(result) {
+			result := res.Content
+			if res.IsError {
 				logger.Error("tool error", "tool", tc.Function.Name, "err", result)
 			}
-			toolMsg := provider.ToolResultMessage(tc.ID, tc.Function.Name, result)
+			skipTrim := false
 			if yamlBlock, _, ok := SplitFrontmatter(result); ok && ExtractFrontmatterValue(yamlBlock, "skip_trim") == "true" {
-				toolMsg.SkipTrim = true
+				skipTrim = true
 			}
+			if !skipTrim {
+				result = truncateToolResult(result, r.toolResultTokenLimit())
+			}
+			toolMsg := provider.ToolResultMessage(tc.ID, tc.Function.Name, result)
+			toolMsg.SkipTrim = skipTrim
+			toolMsg.IsError = res.IsError
 			messages = append(messages, toolMsg)
 			if r.onMessage != nil {
 				r.onMessage(toolMsg)
@@ -291,7 +398,7 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 					ArgsSummary:   truncateStr(tc.Function.Arguments, 200),
 					ResultPreview: truncateStr(result, 200),
 					DurationMs:    time.Since(start).Milliseconds(),
-					Error:         tools.IsToolError(result),
+					Error:         res.IsError,
 				})
 			}
 		}
@@ -331,6 +438,71 @@ func (r *Runner) RunWithMessages(ctx context.Context, messages []provider.Messag
 	}
 }
 
+func (r *Runner) iterationLimit() int {
+	if r.maxIterations > 0 {
+		return r.maxIterations
+	}
+	return maxIterations
+}
+
+func (r *Runner) continuationLimit() int {
+	if r.maxContinuations < 0 {
+		return 0
+	}
+	if r.maxContinuations > 0 {
+		return r.maxContinuations
+	}
+	return defaultMaxContinuations
+}
+
+func (r *Runner) toolResultTokenLimit() int {
+	if r.toolResultMaxTokens < 0 {
+		return 0
+	}
+	if r.toolResultMaxTokens > 0 {
+		return r.toolResultMaxTokens
+	}
+	return defaultToolResultMaxTokens
+}
+
+// truncateToolResult cuts result to about limit estimated tokens, keeping the
+// head (where the result frontmatter lives) and ending on a line boundary
+// when one is near. A note with the omitted token count is appended. A limit
+// of 0 disables truncation.
+func truncateToolResult(result string, limit int) string {
+	total := EstimateTextTokens(result)
+	if limit <= 0 || total <= limit {
+		return result
+	}
+	runes := []rune(result)
+	keep := len(runes) * limit / total
+	for keep > 0 && EstimateTextTokens(string(runes[:keep])) > limit {
+		keep = keep * 9 / 10
+	}
+	head := string(runes[:keep])
+	if i := strings.LastIndexByte(head, '\n'); i > len(head)*3/4 {
+		head = head[:i]
+	}
+	omitted := total - EstimateTextTokens(head)
+	return fmt.Sprintf("%s\n\n(truncated, %d tokens omitted — re-run with a narrower query)", head, omitted)
+}
+
+// capMessage builds the final assistant reply for a turn that hit the
+// iteration cap: a short explanation plus the best partial answer so far.
+// It is emitted via onMessage like any final response.
+func (r *Runner) capMessage(limit int) string {
+	content := fmt.Sprintf("I stopped after %d tool-call rounds, the limit for this turn, before finishing the task.", limit)
+	if partial := strings.TrimSpace(r.partial); partial != "" {
+		content += "\n\nProgress so far:\n\n" + partial
+	} else {
+		content += " Ask me to continue, or narrow the request."
+	}
+	if r.onMessage != nil {
+		r.onMessage(provider.AssistantMessageWithTools(content, "", nil, nil))
+	}
+	return content
+}
+
 // trimLoopMessages removes the oldest tool-call + tool-result pairs when
 // the total estimated tokens exceed contextBudget. It preserves the system
 // prompt (messages[0]) and never removes the last assistant+tool group.
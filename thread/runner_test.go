@@ -0,0 +1,379 @@
+package thread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// scriptedProvider returns the queued responses in order and records requests.
+type scriptedProvider struct {
+	responses []*provider.Response
+	requests  []*provider.Request
+}
+
+func (p *scriptedProvider) Chat(_ context.Context, req *provider.Request) (provider.ChatResult, error) {
+	p.requests = append(p.requests, req)
+	resp := p.responses[0]
+	if len(p.responses) > 1 {
+		p.responses = p.responses[1:]
+	}
+	return provider.NewBasicResult(resp), nil
+}
+
+func TestRunnerRetriesEmptyFinalResponse(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		{Content: "  \n"},
+		{Content: "the answer"},
+	}}
+	r := NewRunner(p, tools.NewRegistry(), nil, 0)
+	var emitted []provider.Message
+	r.OnMessage(func(m provider.Message) { emitted = append(emitted, m) })
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("question")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if out != "the answer" {
+		t.Errorf("response = %q, want %q", out, "the answer")
+	}
+	if len(p.requests) != 2 {
+		t.Fatalf("provider calls = %d, want 2", len(p.requests))
+	}
+	retry := p.requests[1].Messages
+	if last := retry[len(retry)-1]; last.Role != "user" || !strings.Contains(last.Content, "final answer") {
+		t.Errorf("retry should end with the nudge, got %+v", last)
+	}
+	// The empty reply itself is never emitted; the nudge and the answer are.
+	if len(emitted) != 2 || emitted[1].Content != "the answer" {
+		t.Errorf("emitted = %+v", emitted)
+	}
+}
+
+// failingTool always returns an error result.
+type failingTool struct{}
+
+func (failingTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "fail"}}
+}
+
+func (failingTool) RunResult(context.Context, json.RawMessage) tools.ToolResult {
+	return tools.ErrorResult("fail", "disk full")
+}
+
+func (f failingTool) Run(ctx context.Context, args json.RawMessage) string {
+	return f.RunResult(ctx, args).Content
+}
+
+func TestRunnerFlagsToolErrorOnMessage(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		toolCallResponse("", "c1", "fail", `{}`),
+		toolCallResponse("", "c2", "fail", `not json`),
+		{Content: "done"},
+	}}
+	reg := tools.NewRegistry()
+	reg.Register(failingTool{})
+	r := NewRunner(p, reg, nil, 0)
+
+	if _, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("go")}); err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if len(p.requests) != 3 {
+		t.Fatalf("provider calls = %d, want 3", len(p.requests))
+	}
+	var flagged int
+	for _, m := range p.requests[2].Messages {
+		if m.Role == "tool" {
+			if !m.IsError {
+				t.Errorf("tool message %s not flagged as error: %q", m.ToolCallID, m.Content)
+			}
+			flagged++
+		}
+	}
+	if flagged != 2 {
+		t.Errorf("saw %d tool messages, want 2", flagged)
+	}
+}
+
+func TestRunnerEmptyResponseRetriesOnlyOnce(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{{Content: ""}}}
+	r := NewRunner(p, tools.NewRegistry(), nil, 0)
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("question")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if out != "" || len(p.requests) != 2 {
+		t.Errorf("response = %q after %d calls, want empty after 2", out, len(p.requests))
+	}
+}
+
+func TestRunnerEmptyResponseFallsBackToReasoning(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		{Content: "", ReasoningContent: "answer hidden in reasoning"},
+	}}
+	r := NewRunner(p, tools.NewRegistry(), nil, 0)
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("question")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if out != "answer hidden in reasoning" {
+		t.Errorf("response = %q", out)
+	}
+	if len(p.requests) != 1 {
+		t.Errorf("provider calls = %d, want 1 (no retry needed)", len(p.requests))
+	}
+}
+
+// loopTool always succeeds, so a model that keeps calling it never finishes.
+type loopTool struct{ calls int }
+
+func (l *loopTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "poll"}}
+}
+
+func (l *loopTool) Run(context.Context, json.RawMessage) string {
+	l.calls++
+	return "still pending"
+}
+
+func TestRunnerContinuesTruncatedResponse(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		{Content: "The answer is forty", FinishReason: "length"},
+		{Content: "-two.", FinishReason: "stop"},
+	}}
+	r := NewRunner(p, tools.NewRegistry(), nil, 0)
+	var emitted []provider.Message
+	r.OnMessage(func(m provider.Message) { emitted = append(emitted, m) })
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("question")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if out != "The answer is forty-two." {
+		t.Errorf("response = %q, want the joined pieces", out)
+	}
+	if len(p.requests) != 2 {
+		t.Fatalf("provider calls = %d, want 2", len(p.requests))
+	}
+	cont := p.requests[1].Messages
+	if prev := cont[len(cont)-2]; prev.Role != "assistant" || prev.Content != "The answer is forty" {
+		t.Errorf("continuation should replay the cut-off reply, got %+v", prev)
+	}
+	if last := cont[len(cont)-1]; last.Role != "user" || !strings.Contains(last.Content, "cut off") {
+		t.Errorf("continuation should end with the continue prompt, got %+v", last)
+	}
+	// Only the joined reply is emitted.
+	if len(emitted) != 1 || emitted[0].Content != "The answer is forty-two." {
+		t.Errorf("emitted = %+v", emitted)
+	}
+}
+
+func TestRunnerContinuationLimit(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{{Content: "more ", FinishReason: "length"}}}
+	r := NewRunner(p, tools.NewRegistry(), nil, 0)
+	r.SetMaxContinuations(1)
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("question")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if out != "more more " || len(p.requests) != 2 {
+		t.Errorf("response = %q after %d calls, want two pieces after 2", out, len(p.requests))
+	}
+
+	p = &scriptedProvider{responses: []*provider.Response{{Content: "cut", FinishReason: "length"}}}
+	r = NewRunner(p, tools.NewRegistry(), nil, 0)
+	r.SetMaxContinuations(-1)
+	if out, _ := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("question")}); out != "cut" || len(p.requests) != 1 {
+		t.Errorf("disabled: response = %q after %d calls, want %q after 1", out, len(p.requests), "cut")
+	}
+}
+
+func TestRunnerStopsAtIterationCapWithPartialAnswer(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{{
+		Content: "Checked 3 of 10 servers so far.",
+		ToolCalls: []provider.ToolCall{{
+			ID: "c1", Type: "function",
+			Function: provider.FunctionCall{Name: "poll", Arguments: `{}`},
+		}},
+	}}}
+	tool := &loopTool{}
+	reg := tools.NewRegistry()
+	reg.Register(tool)
+	r := NewRunner(p, reg, nil, 0)
+	r.SetMaxIterations(3)
+	var emitted []provider.Message
+	r.OnMessage(func(m provider.Message) { emitted = append(emitted, m) })
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("check all servers")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if len(p.requests) != 3 || tool.calls != 3 {
+		t.Errorf("provider calls = %d, tool calls = %d, want 3 each", len(p.requests), tool.calls)
+	}
+	if !strings.Contains(out, "3 tool-call rounds") || !strings.Contains(out, "Checked 3 of 10 servers") {
+		t.Errorf("response = %q, want cap notice with partial answer", out)
+	}
+	if last := emitted[len(emitted)-1]; last.Role != "assistant" || last.Content != out || len(last.ToolCalls) != 0 {
+		t.Errorf("last emitted = %+v, want final assistant cap message", last)
+	}
+}
+
+// streamedResponse replays deltas through Recv, then returns resp from Wait.
+type streamedResponse struct {
+	deltas []provider.StreamDelta
+	resp   *provider.Response
+}
+
+func (s *streamedResponse) Recv() (provider.StreamDelta, error) {
+	if len(s.deltas) == 0 {
+		return provider.StreamDelta{}, io.EOF
+	}
+	d := s.deltas[0]
+	s.deltas = s.deltas[1:]
+	return d, nil
+}
+
+func (s *streamedResponse) Wait() (*provider.Response, error) { return s.resp, nil }
+func (s *streamedResponse) Cancel()                           {}
+
+// streamingProvider returns the queued results in order.
+type streamingProvider struct{ results []provider.ChatResult }
+
+func (p *streamingProvider) Chat(context.Context, *provider.Request) (provider.ChatResult, error) {
+	r := p.results[0]
+	p.results = p.results[1:]
+	return r, nil
+}
+
+// argsTool records the raw arguments of every call.
+type argsTool struct{ calls []string }
+
+func (a *argsTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "read"}}
+}
+
+func (a *argsTool) Run(_ context.Context, args json.RawMessage) string {
+	a.calls = append(a.calls, string(args))
+	return "ok"
+}
+
+func TestRunnerRunsToolOnceWithCompleteStreamedArgs(t *testing.T) {
+	argDelta := func(frag string) provider.StreamDelta {
+		return provider.StreamDelta{Type: provider.DeltaToolCallArgs, Text: frag, ToolIndex: 0, ToolCallID: "c1"}
+	}
+	p := &streamingProvider{results: []provider.ChatResult{
+		&streamedResponse{
+			deltas: []provider.StreamDelta{
+				{Type: provider.DeltaToolCall, ToolName: "read"},
+				argDelta(`{"path":`),
+				argDelta(` "notes.txt"`),
+				argDelta(`}`),
+			},
+			// The provider finalized before the last fragment arrived.
+			resp: &provider.Response{ToolCalls: []provider.ToolCall{{
+				ID: "c1", Type: "function",
+				Function: provider.FunctionCall{Name: "read", Arguments: `{"path": "notes.txt"`},
+			}}},
+		},
+		provider.NewBasicResult(&provider.Response{Content: "done"}),
+	}}
+	tool := &argsTool{}
+	reg := tools.NewRegistry()
+	reg.Register(tool)
+	r := NewRunner(p, reg, nil, 0)
+
+	out, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("read notes")})
+	if err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if out != "done" {
+		t.Errorf("response = %q, want %q", out, "done")
+	}
+	if len(tool.calls) != 1 || tool.calls[0] != `{"path": "notes.txt"}` {
+		t.Errorf("tool calls = %q, want exactly one with the full arguments", tool.calls)
+	}
+}
+
+func TestRunnerSkipsToolWithIncompleteArgs(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		{ToolCalls: []provider.ToolCall{{
+			ID: "c1", Type: "function",
+			Function: provider.FunctionCall{Name: "read", Arguments: `{"path": "no`},
+		}}},
+		{Content: "gave up"},
+	}}
+	tool := &argsTool{}
+	reg := tools.NewRegistry()
+	reg.Register(tool)
+	r := NewRunner(p, reg, nil, 0)
+	var emitted []provider.Message
+	r.OnMessage(func(m provider.Message) { emitted = append(emitted, m) })
+
+	if _, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("read")}); err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	if len(tool.calls) != 0 {
+		t.Errorf("tool ran with incomplete arguments: %q", tool.calls)
+	}
+	if len(emitted) < 2 || emitted[0].ToolCalls[0].Function.Arguments != "{}" ||
+		emitted[1].Role != "tool" || !strings.Contains(emitted[1].Content, "malformed tool call arguments") {
+		t.Errorf("emitted = %+v, want sanitized call and error result", emitted)
+	}
+}
+
+// bigTool returns a fixed, large result.
+type bigTool struct{ result string }
+
+func (b *bigTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "dump"}}
+}
+
+func (b *bigTool) Run(context.Context, json.RawMessage) string { return b.result }
+
+func TestRunnerTruncatesOverBudgetToolResult(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; sb.Len() < 40000; i++ {
+		fmt.Fprintf(&sb, "line %d: some log output from a noisy command\n", i)
+	}
+	p := &scriptedProvider{responses: []*provider.Response{
+		{ToolCalls: []provider.ToolCall{{
+			ID: "c1", Type: "function",
+			Function: provider.FunctionCall{Name: "dump", Arguments: `{}`},
+		}}},
+		{Content: "done"},
+	}}
+	reg := tools.NewRegistry()
+	reg.Register(&bigTool{result: sb.String()})
+	r := NewRunner(p, reg, nil, 0)
+	r.SetToolResultMaxTokens(500)
+
+	if _, err := r.RunWithMessages(context.Background(), []provider.Message{provider.UserMessage("dump the logs")}); err != nil {
+		t.Fatalf("RunWithMessages: %v", err)
+	}
+	msgs := p.requests[1].Messages
+	got := msgs[len(msgs)-1]
+	if got.Role != "tool" {
+		t.Fatalf("last message role = %q, want tool", got.Role)
+	}
+	if !strings.HasPrefix(got.Content, "line 0: ") || !strings.Contains(got.Content, "tokens omitted") {
+		t.Errorf("tool result should keep the head and carry a truncation note, got tail %q", got.Content[len(got.Content)-120:])
+	}
+	// The note itself adds a few tokens on top of the budget.
+	if tokens := EstimateTextTokens(got.Content); tokens > 520 {
+		t.Errorf("truncated result is %d tokens, want about 500", tokens)
+	}
+
+	if small := truncateToolResult("short result", 500); small != "short result" {
+		t.Errorf("under-budget result changed: %q", small)
+	}
+}
@@ -0,0 +1,136 @@
+package thread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/config"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// chatCompletionServer answers OpenAI-compatible chat completions with reply
+// and counts the requests it receives.
+func chatCompletionServer(t *testing.T, reply string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		var req struct {
+			Model  string `json:"model"`
+			Stream bool   `json:"stream"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":%q,\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":%q},\"finish_reason\":\"stop\"}]}\n\n", req.Model, reply)
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"c1","object":"chat.completion","created":1,"model":%q,"choices":[{"index":0,"message":{"role":"assistant","content":%q},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12}}`, req.Model, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSetModelRoutesNextTurn(t *testing.T) {
+	var defaultHits, overrideHits atomic.Int32
+	defaultSrv := chatCompletionServer(t, "from deepseek", &defaultHits)
+	overrideSrv := chatCompletionServer(t, "from glm", &overrideHits)
+
+	cfg := &config.Config{}
+	cfg.Thread.Provider = "deepseek"
+	cfg.Thread.ModelType = "deepseek-v4-flash"
+	cfg.Providers.DeepSeek = &config.ProviderConfig{APIKey: "test-key", APIBase: defaultSrv.URL}
+	cfg.Providers.ZhipuCN = &config.ProviderConfig{APIKey: "test-key", APIBase: overrideSrv.URL}
+	factory, err := provider.NewFactory(func() *config.Config { return cfg })
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(&ThreadConfig{ProviderFactory: factory, Sessions: sessions, Tools: tools.NewRegistry()})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	const key = "telegram:42"
+	turn := func() string {
+		t.Helper()
+		done := make(chan TurnResult, 1)
+		mgr.Wake(key, &WakeMessage{Source: WakeTelegram, Message: "hello", OnResult: func(r TurnResult) { done <- r }})
+		select {
+		case r := <-done:
+			if r.Err != nil {
+				t.Fatalf("turn failed: %v", r.Err)
+			}
+			return r.Response
+		case <-time.After(10 * time.Second):
+			t.Fatal("turn did not finish")
+		}
+		return ""
+	}
+
+	if got := turn(); got != "from deepseek" || defaultHits.Load() == 0 || overrideHits.Load() != 0 {
+		t.Fatalf("first turn = %q (default hits %d, override hits %d), want the default provider", got, defaultHits.Load(), overrideHits.Load())
+	}
+
+	mgr.mu.Lock()
+	th := mgr.threads[key]
+	mgr.mu.Unlock()
+	toolCtx := tools.WithRuntimeContext(ctx, tools.RuntimeContext{
+		SessionKey:           key,
+		SessionDir:           mgr.SessionDir(key),
+		Admin:                true,
+		ModelOverrideChanged: th.invalidateModelOverride,
+	})
+	args, _ := json.Marshal(map[string]string{"provider": "zhipu-cn", "model_type": "glm-5"})
+	if out := (&tools.SetModelTool{}).Run(toolCtx, args); session.ReadMeta(mgr.SessionDir(key)).Model == nil {
+		t.Fatalf("set_model did not store the override: %s", out)
+	}
+	defaultBefore := defaultHits.Load()
+	if got := turn(); got != "from glm" || overrideHits.Load() == 0 || defaultHits.Load() != defaultBefore {
+		t.Errorf("turn after set_model = %q (override hits %d), want the overridden provider", got, overrideHits.Load())
+	}
+
+	(&tools.ResetModelTool{}).Run(toolCtx, nil)
+	if got := turn(); got != "from deepseek" {
+		t.Errorf("turn after reset_model = %q, want the default provider", got)
+	}
+}
+
+func TestSessionModelOverrideIsCachedUntilInvalidated(t *testing.T) {
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(&ThreadConfig{Sessions: sessions})
+	th, err := mgr.NewThread("telegram:7", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := mgr.SessionDir("telegram:7")
+	if mo := th.sessionModelOverride(); mo != nil {
+		t.Fatalf("override before set_model = %+v, want nil", mo)
+	}
+
+	session.UpdateMeta(dir, func(m *session.Meta) {
+		m.Model = &session.ModelOverride{Provider: "zhipu-cn", ModelType: "glm-5"}
+	})
+	if mo := th.sessionModelOverride(); mo != nil {
+		t.Fatalf("override re-read without invalidation: %+v", mo)
+	}
+	th.invalidateModelOverride()
+	if mo := th.sessionModelOverride(); mo == nil || mo.Provider != "zhipu-cn" || mo.ModelType != "glm-5" {
+		t.Fatalf("override after invalidation = %+v", mo)
+	}
+}
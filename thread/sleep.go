@@ -69,3 +69,46 @@ func (t *Thread) checkAndResetDefaultReplyForwarded() bool {
 	t.defaultReplyForwarded = false
 	return v
 }
+
+// setDryRun marks whether the current turn simulates mutating tools.
+func (t *Thread) setDryRun(v bool) {
+	t.mu.Lock()
+	t.dryRun = v
+	t.mu.Unlock()
+}
+
+// isDryRun returns whether the current turn is a dry run.
+func (t *Thread) isDryRun() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.dryRun
+}
+
+// setInheritedTools records the tool limit the current wake carries from
+// the session that sent it.
+func (t *Thread) setInheritedTools(names []string) {
+	t.mu.Lock()
+	t.inheritedTools = names
+	t.mu.Unlock()
+}
+
+// setAdmin marks whether the current turn may use admin-only tools.
+func (t *Thread) setAdmin(v bool) {
+	t.mu.Lock()
+	t.admin = v
+	t.mu.Unlock()
+}
+
+// resetSubagentSpawns clears the per-turn subagent dispatch count.
+func (t *Thread) resetSubagentSpawns() {
+	t.mu.Lock()
+	t.subagentSpawns = 0
+	t.mu.Unlock()
+}
+
+// isAdmin returns whether the current turn may use admin-only tools.
+func (t *Thread) isAdmin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.admin
+}
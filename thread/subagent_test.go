@@ -0,0 +1,174 @@
+package thread
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/tools"
+)
+
+func TestSubagentTurnTimesOut(t *testing.T) {
+	p := &slowProvider{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(p.release)
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p})
+
+	delivered := make(chan string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	mgr.Wake("cli:threads:slow", &WakeMessage{
+		Source:  WakeSession,
+		Message: "take your time",
+		Timeout: 100 * time.Millisecond,
+		Sink: Sink{Send: func(_ context.Context, response string) error {
+			delivered <- response
+			return nil
+		}},
+	})
+
+	select {
+	case got := <-delivered:
+		if !strings.Contains(got, "timed out after 100ms") {
+			t.Errorf("delivered = %q, want a timeout error", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout error was never delivered")
+	}
+}
+
+func TestSubagentConcurrencyFromConfig(t *testing.T) {
+	if n := cap(NewManager(&ThreadConfig{SubagentMaxConcurrency: 2}).subagentSem); n != 2 {
+		t.Errorf("configured cap = %d, want 2", n)
+	}
+	if n := cap(NewManager(&ThreadConfig{SubagentMaxConcurrency: -1}).subagentSem); n != defaultSubagentMaxConcurrency {
+		t.Errorf("fallback cap = %d, want %d", n, defaultSubagentMaxConcurrency)
+	}
+}
+
+func TestSubagentSpawnLimitPerTurn(t *testing.T) {
+	mgr := NewManager(&ThreadConfig{SubagentMaxPerTurn: 2, SubagentMaxPerHour: 3})
+	th, err := mgr.NewThread("telegram:1", "")
+	if err != nil {
+		t.Fatalf("NewThread: %v", err)
+	}
+	for i, task := range []string{"a", "b"} {
+		if _, _, err := th.CreateOrWakeSubagent(context.Background(), "", task, "work", 0); err != nil {
+			t.Fatalf("spawn %d: %v", i+1, err)
+		}
+	}
+	_, _, err = th.CreateOrWakeSubagent(context.Background(), "", "c", "work", 0)
+	if err == nil || !strings.Contains(err.Error(), "maxPerTurn") {
+		t.Fatalf("third spawn in one turn: err = %v, want the per-turn limit", err)
+	}
+
+	// A new turn gets a fresh allowance, but the hourly cap still applies.
+	th.resetSubagentSpawns()
+	if _, _, err := th.CreateOrWakeSubagent(context.Background(), "", "c", "work", 0); err != nil {
+		t.Fatalf("spawn in next turn: %v", err)
+	}
+	_, _, err = th.CreateOrWakeSubagent(context.Background(), "", "d", "work", 0)
+	if err == nil || !strings.Contains(err.Error(), "maxPerHour") {
+		t.Fatalf("fourth spawn in an hour: err = %v, want the hourly limit", err)
+	}
+}
+
+func TestSubagentHourlyLimitCoversNestedSpawnsAndForks(t *testing.T) {
+	sessions, err := session.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sessions.Save(&session.Session{Key: "telegram:1", Messages: []provider.Message{provider.UserMessage("hi")}}); err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(&ThreadConfig{Sessions: sessions, SubagentMaxPerTurn: 10, SubagentMaxPerHour: 3})
+	root, err := mgr.NewThread("telegram:1", "")
+	if err != nil {
+		t.Fatalf("NewThread: %v", err)
+	}
+	child, err := mgr.NewThread("telegram:1:threads:a", "")
+	if err != nil {
+		t.Fatalf("NewThread: %v", err)
+	}
+	grandchild, err := mgr.NewThread("telegram:1:threads:a:threads:b", "")
+	if err != nil {
+		t.Fatalf("NewThread: %v", err)
+	}
+
+	if _, _, err := root.CreateOrWakeSubagent(context.Background(), "", "a", "work", 0); err != nil {
+		t.Fatalf("root spawn: %v", err)
+	}
+	if _, _, err := child.CreateOrWakeSubagent(context.Background(), "", "b", "work", 0); err != nil {
+		t.Fatalf("nested spawn: %v", err)
+	}
+	if _, _, err := root.CreateOrWakeFork(context.Background(), "", "f", "work"); err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	_, _, err = grandchild.CreateOrWakeSubagent(context.Background(), "", "c", "work", 0)
+	if err == nil || !strings.Contains(err.Error(), "maxPerHour") {
+		t.Fatalf("fourth dispatch from the tree: err = %v, want the root's hourly limit", err)
+	}
+}
+
+func TestRootSessionKey(t *testing.T) {
+	for key, want := range map[string]string{
+		"telegram:1":                      "telegram:1",
+		"telegram:1:threads:a":            "telegram:1",
+		"telegram:1:threads:a:threads:b":  "telegram:1",
+		"cli:fork:x:threads:y":            "cli",
+		"discord:9:threads:a:fork:review": "discord:9",
+	} {
+		if got := rootSessionKey(key); got != want {
+			t.Errorf("rootSessionKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+// dryRunProbe is an exec stand-in that reports each call's dry-run flag.
+type dryRunProbe chan bool
+
+func (p dryRunProbe) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "exec"}}
+}
+
+func (p dryRunProbe) Run(ctx context.Context, _ json.RawMessage) string {
+	p <- tools.RuntimeContextFrom(ctx).DryRun
+	return "ok"
+}
+
+func TestDryRunParentSubagentExecIsDryRun(t *testing.T) {
+	probe := make(dryRunProbe, 4)
+	reg := tools.NewRegistry()
+	reg.Register(probe)
+	p := &scriptedProvider{responses: []*provider.Response{
+		{ToolCalls: []provider.ToolCall{{ID: "c1", Type: "function", Function: provider.FunctionCall{Name: "exec", Arguments: `{"command":"rm -rf build"}`}}}},
+		{Content: "done"},
+	}}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, Tools: reg})
+	parent, err := mgr.NewThread("cli", "")
+	if err != nil {
+		t.Fatalf("NewThread: %v", err)
+	}
+	parent.setDryRun(true)
+	if _, _, err := parent.CreateOrWakeSubagent(context.Background(), "", "cleanup", "clean the build", 0); err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	parent.setDryRun(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	select {
+	case dryRun := <-probe:
+		if !dryRun {
+			t.Error("subagent of a dry-run turn ran exec for real")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subagent never called exec")
+	}
+}
@@ -0,0 +1,51 @@
+package thread
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tools"
+)
+
+func TestThreadStatusReflectsRunningTurn(t *testing.T) {
+	p := &scriptedProvider{responses: []*provider.Response{
+		toolCallResponse("", "c1", "read_file", `{}`),
+		toolCallResponse("", "c2", "thread_status", `{}`),
+		{Content: "busy reading"},
+	}}
+	reg := tools.NewRegistry()
+	reg.Register(namedTool("read_file"))
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, Tools: reg})
+	mgr.RegisterTool(tools.NewThreadStatusTool(mgr))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+
+	done := make(chan TurnResult, 1)
+	mgr.Wake("telegram:7", &WakeMessage{Source: WakeTelegram, Message: "what are you doing?", OnResult: func(r TurnResult) { done <- r }})
+	select {
+	case r := <-done:
+		if r.Err != nil {
+			t.Fatalf("turn failed: %v", r.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("turn did not finish")
+	}
+
+	if len(p.requests) != 3 {
+		t.Fatalf("provider calls = %d, want 3", len(p.requests))
+	}
+	msgs := p.requests[2].Messages
+	status := msgs[len(msgs)-1]
+	if status.Role != "tool" {
+		t.Fatalf("last message role = %q, want the thread_status result", status.Role)
+	}
+	for _, want := range []string{"state: running", "session_key: telegram:7", "last_tool: read_file", "current_tool: thread_status", "iterations: 2"} {
+		if !strings.Contains(status.Content, want) {
+			t.Errorf("thread_status result missing %q:\n%s", want, status.Content)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package thread
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -59,3 +60,33 @@ func TestThreadSet(t *testing.T) {
 		t.Fatal("agent should be initialized")
 	}
 }
+
+func TestSystemPromptIncludesChannelSnippet(t *testing.T) {
+	mgr := NewManager(&ThreadConfig{
+		ChannelPromptFor: func(channel string) string {
+			if channel == "discord" {
+				return "Keep replies casual."
+			}
+			return ""
+		},
+	})
+	discord, err := mgr.NewThread("discord:1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	telegram, err := mgr.NewThread("telegram:2", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p := discord.buildSystemPrompt(string(WakeDiscord)); !strings.Contains(p, "Keep replies casual.") || !strings.Contains(p, "channel: discord") {
+		t.Errorf("discord wake prompt missing channel snippet:\n%s", p)
+	}
+	// Non-channel wakes fall back to the session's channel.
+	if p := discord.buildSystemPrompt(string(WakeHeartbeat)); !strings.Contains(p, "Keep replies casual.") {
+		t.Errorf("heartbeat wake on a discord session missing channel snippet:\n%s", p)
+	}
+	if p := telegram.buildSystemPrompt(string(WakeTelegram)); strings.Contains(p, "Keep replies casual.") || strings.Contains(p, "channel_instruction") {
+		t.Errorf("telegram wake prompt should have no channel snippet:\n%s", p)
+	}
+}
@@ -0,0 +1,107 @@
+package thread
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tools"
+)
+
+// toolArgsAssembler collects tool-call argument fragments streamed by the
+// provider so the Runner can check the provider's own assembly against them.
+type toolArgsAssembler struct {
+	calls map[int]*streamedToolArgs // stream index → fragments
+}
+
+type streamedToolArgs struct {
+	id   string
+	args strings.Builder
+}
+
+// add records one DeltaToolCallArgs delta.
+func (a *toolArgsAssembler) add(d provider.StreamDelta) {
+	if a.calls == nil {
+		a.calls = make(map[int]*streamedToolArgs)
+	}
+	c, ok := a.calls[d.ToolIndex]
+	if !ok {
+		c = &streamedToolArgs{}
+		a.calls[d.ToolIndex] = c
+	}
+	if d.ToolCallID != "" {
+		c.id = d.ToolCallID
+	}
+	c.args.WriteString(d.Text)
+}
+
+// lookup returns the streamed arguments for the i-th tool call with the given
+// ID. Calls are matched by ID when the stream carried one, otherwise by
+// position among the streamed calls.
+func (a *toolArgsAssembler) lookup(i int, id string) (string, bool) {
+	if len(a.calls) == 0 {
+		return "", false
+	}
+	indices := make([]int, 0, len(a.calls))
+	for idx, c := range a.calls {
+		if id != "" && c.id == id {
+			return c.args.String(), true
+		}
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	if i < len(indices) {
+		if c := a.calls[indices[i]]; c.id == "" || id == "" {
+			return c.args.String(), true
+		}
+	}
+	return "", false
+}
+
+// completeToolArgs reports whether args is a complete JSON object, the only
+// shape a tool accepts. Empty arguments count as {}.
+func completeToolArgs(args string) bool {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return true
+	}
+	var obj map[string]json.RawMessage
+	return json.Unmarshal([]byte(args), &obj) == nil && obj != nil
+}
+
+// finalizeToolCalls settles each tool call's arguments before anything runs.
+// Arguments the provider assembled incompletely are replaced by the streamed
+// fragments when those form a complete object, then by a light repair
+// (tools.RepairArgs). Calls whose arguments never became a complete object
+// are rewritten to "{}" (so session history stays valid) and returned as
+// tc.ID → original arguments; the caller reports an error result for them
+// instead of running the tool.
+func finalizeToolCalls(calls []provider.ToolCall, streamed *toolArgsAssembler) map[string]string {
+	invalid := make(map[string]string)
+	for i, tc := range calls {
+		args := tc.Function.Arguments
+		if !completeToolArgs(args) {
+			if s, ok := streamed.lookup(i, tc.ID); ok && completeToolArgs(s) {
+				logger.Warn("tool call arguments incomplete, using streamed arguments",
+					"tool", tc.Function.Name, "assembled", args, "streamed", s)
+				args = s
+			} else if r, ok := tools.RepairArgs(args); ok && completeToolArgs(r) {
+				logger.Warn("repaired malformed tool call arguments", "tool", tc.Function.Name, "original", args)
+				args = r
+			}
+		}
+		if strings.TrimSpace(args) == "" {
+			args = "{}"
+		}
+		if !completeToolArgs(args) {
+			invalid[tc.ID] = args
+			args = "{}"
+			logger.Warn("skipping tool call with malformed arguments",
+				"tool", tc.Function.Name, "original", invalid[tc.ID])
+		}
+		calls[i].Function.Arguments = args
+	}
+	return invalid
+}
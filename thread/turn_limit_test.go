@@ -0,0 +1,92 @@
+package thread
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// gatedProvider tracks how many Chat calls run at once; each blocks until
+// release is closed.
+type gatedProvider struct {
+	mu      sync.Mutex
+	current int
+	peak    int
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *gatedProvider) Chat(ctx context.Context, _ *provider.Request) (provider.ChatResult, error) {
+	p.mu.Lock()
+	p.current++
+	p.peak = max(p.peak, p.current)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.current--
+		p.mu.Unlock()
+	}()
+	p.entered <- struct{}{}
+	select {
+	case <-p.release:
+		return provider.NewBasicResult(&provider.Response{Content: "done"}), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestManagerLimitsConcurrentTurns(t *testing.T) {
+	const limit, sessions = 2, 5
+	p := &gatedProvider{entered: make(chan struct{}, sessions), release: make(chan struct{})}
+	mgr := NewManager(&ThreadConfig{DefaultProvider: p, MaxConcurrentTurns: limit})
+
+	delivered := make(chan string, sessions)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mgr.Run(ctx)
+	for i := 0; i < sessions; i++ {
+		mgr.Wake(fmt.Sprintf("test:limit:%d", i), &WakeMessage{Source: WakeTelegram, Message: "hi", Sink: Sink{
+			Send: func(_ context.Context, response string) error {
+				delivered <- response
+				return nil
+			},
+		}})
+	}
+
+	for i := 0; i < limit; i++ {
+		select {
+		case <-p.entered:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of %d turns started", i, limit)
+		}
+	}
+	select {
+	case <-p.entered:
+		t.Fatal("a turn started beyond the limit")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if inFlight, capacity := mgr.InFlightTurns(); inFlight != limit || capacity != limit {
+		t.Errorf("InFlightTurns = (%d, %d), want (%d, %d)", inFlight, capacity, limit, limit)
+	}
+	if n := mgr.ActiveCount(); n != sessions {
+		t.Errorf("ActiveCount = %d, want %d (running plus queued)", n, sessions)
+	}
+
+	close(p.release)
+	for i := 0; i < sessions; i++ {
+		select {
+		case <-delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of %d turns delivered", i, sessions)
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.peak != limit {
+		t.Errorf("peak concurrent turns = %d, want %d", p.peak, limit)
+	}
+}
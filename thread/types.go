@@ -1,6 +1,7 @@
 package thread
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -17,6 +18,9 @@ import (
 // Sink is an alias for msg.Sink.
 type Sink = msg.Sink
 
+// Embed is a structured reply delivered via Sink.SendEmbed.
+type Embed = msg.Embed
+
 // ReactFunc is an alias for msg.ReactFunc.
 type ReactFunc = msg.ReactFunc
 
@@ -35,6 +39,12 @@ var NewReactFunc = msg.NewReactFunc
 // WakeMessage is an alias for msg.WakeMessage.
 type WakeMessage = msg.WakeMessage
 
+// TurnResult is an alias for msg.TurnResult.
+type TurnResult = msg.TurnResult
+
+// TurnUsage is an alias for msg.TurnUsage.
+type TurnUsage = msg.TurnUsage
+
 // WakeSource is an alias for msg.WakeSource.
 type WakeSource = msg.WakeSource
 
@@ -45,6 +55,8 @@ const (
 	WakeDiscord     = msg.WakeDiscord
 	WakeFeishu      = msg.WakeFeishu
 	WakeWeCom       = msg.WakeWeCom
+	WakeCLI         = msg.WakeCLI
+	WakeAPI         = msg.WakeAPI
 	WakeSession     = msg.WakeSession
 	WakeCron        = msg.WakeCron
 	WakeCompression = msg.WakeCompression
@@ -53,6 +65,12 @@ const (
 	WakeRephrase    = msg.WakeRephrase
 )
 
+// Wake priority constants re-exported from msg package.
+const (
+	PriorityNormal = msg.PriorityNormal
+	PriorityHigh   = msg.PriorityHigh
+)
+
 // threadState represents the runtime state of a thread.
 type threadState int
 
@@ -62,10 +80,11 @@ const (
 )
 
 const (
-	defaultMaxConcurrency = 16
+	defaultMaxConcurrency = 16 // overridable via thread.maxConcurrentTurns
 	defaultInboxSize      = 64
-	defaultThreadTTL      = 3 * time.Hour
+	defaultThreadTTL      = 3 * time.Hour // idle time before gc evicts a thread; overridable via thread.idleEvictMinutes
 	gcInterval            = 5 * time.Minute
+	drainPollInterval     = 50 * time.Millisecond
 	streamFlushThreshold  = 600 // minimum unsent bytes before attempting a streamer split
 
 	// Tier 1: mechanical tool-result compression (idle ≥5 min, no token threshold)
@@ -75,30 +94,52 @@ const (
 	tier2IdleMin = 30 * time.Minute
 )
 
+// Subagent limits, overridable via thread.subagents in config.yaml.
+const (
+	defaultSubagentMaxConcurrency = 4
+	defaultSubagentTimeout        = 10 * time.Minute
+	defaultSubagentMaxPerTurn     = 10
+	defaultSubagentMaxPerHour     = 60
+)
+
 // ThreadConfig contains shared dependencies for creating threads.
 type ThreadConfig struct {
-	DefaultProvider     provider.Provider
-	ProviderName        string
-	ModelName           string
-	Tools               *tools.Registry
-	Skills              *skills.Registry
-	Agents              *agent.AgentRegistry
-	Workspace           string
-	SkillsDir           string
-	BuiltinSkillsDir    string
-	SessionsDir         string
-	ContextWindowTokens int
-	MaxCompletionTokens int
-	Sessions            *session.Manager
-	DefaultSinkFor      func(sessionKey string) Sink
-	DefaultAgentFor     func(sessionKey string) string // Session key → default agent name
-	HealthChannelsFn    func() *tools.HealthChannelsInfo
-	ProviderFactory     *provider.Factory                     // For per-agent model routing
-	Models              map[string]*config.ModelConfig        // Model type → provider/model mapping (startup snapshot)
-	ModelsFn            func() map[string]*config.ModelConfig // Hot-reload: returns latest Models from config
-	SessionTimezoneFor  func(sessionKey string) string        // Session key → IANA timezone
-	MetricsStore        *monitor.Store                        // Turn metrics storage (optional)
-	Sections            *agent.SectionRegistry                // Shared section registry for prompt assembly
+	DefaultProvider        provider.Provider
+	ProviderName           string
+	ModelName              string
+	Tools                  *tools.Registry
+	Skills                 *skills.Registry
+	Agents                 *agent.AgentRegistry
+	Workspace              string
+	SkillsDir              string
+	BuiltinSkillsDir       string
+	SessionsDir            string
+	ContextWindowTokens    int
+	MaxCompletionTokens    int
+	Sessions               *session.Manager
+	DefaultSinkFor         func(sessionKey string) Sink
+	DefaultAgentFor        func(sessionKey string) string // Session key → default agent name
+	HealthChannelsFn       func() *tools.HealthChannelsInfo
+	ChannelPromptFor       func(channel string) string // Channel name → per-channel system prompt snippet
+	ChannelQueuesFn        func() []tools.HealthChannelQueueInfo // Channel inbound buffer depths; set once channels exist
+	ProviderFactory        *provider.Factory                     // For per-agent model routing
+	Models                 map[string]*config.ModelConfig        // Model type → provider/model mapping (startup snapshot)
+	ModelsFn               func() map[string]*config.ModelConfig // Hot-reload: returns latest Models from config
+	SessionTimezoneFor     func(sessionKey string) string        // Session key → IANA timezone
+	MetricsStore           *monitor.Store                        // Turn metrics storage (optional)
+	Sections               *agent.SectionRegistry                // Shared section registry for prompt assembly
+	MaxConcurrentTurns     int                                   // Max turns in flight across all threads; <= 0 uses the default
+	SubagentMaxConcurrency int                                   // Max subagent turns in flight; <= 0 uses the default
+	SubagentTimeout        time.Duration                         // Per-turn deadline for subagents; <= 0 uses the default
+	SubagentMaxPerTurn     int                                   // Max dispatch(to=subagent) calls per turn; <= 0 uses the default
+	SubagentMaxPerHour     int                                   // Max dispatch(to=subagent) calls per session per rolling hour; <= 0 uses the default
+	IdleEvictAfter         time.Duration                         // Idle time before gc evicts a thread; 0 uses the default, < 0 disables
+	ShowMetricsFooter      bool                                  // Append a model/token/latency footer to replies delivered to user channels
+	ShowReasoning          bool                                  // Deliver model reasoning to the user; off strips it from replies
+	MaxContinuations       int                                   // Continuations of replies cut off by the output token limit; 0 uses the default, < 0 disables
+	ToolResultMaxTokens    int                                   // Token cap per tool result added to the conversation; 0 uses the default, < 0 disables
+	ChannelToolsFor        func(channel string) (allow, deny []string) // Channel name → tool allow/deny lists; nil = no restriction
+	ModelOverride          *config.ModelConfig                   // Forces every agent onto this provider/model (debug replay); nil uses normal routing
 }
 
 // Thread is a single execution unit with an agent, wake queue, and optional session.
@@ -115,11 +156,12 @@ type Thread struct {
 	state  threadState
 	inbox  chan *WakeMessage // Buffered wake queue.
 	signal chan struct{}     // Shared with Manager for notification.
+	wakers int               // Wake calls between thread lookup and Enqueue; guarded by Manager.mu, blocks eviction.
 
 	mu               sync.Mutex
 	hooks                 []turnHook
 	postHooks             []postTurnHook // Hooks run after each turn; returned messages are appended to session.jsonl.
-	pending               []*WakeMessage // Non-mergeable messages deferred by tryMerge (avoids channel requeue deadlock).
+	pending               []*WakeMessage // Messages drained from inbox, awaiting dequeue by priority (avoids channel requeue deadlock).
 	defaultSink           Sink           // Fallback sink when WakeMessage.Sink is nil.
 	lastActiveAt          time.Time      // Last time this thread completed work (used by GC).
 	lastUserActiveAt      time.Time      // Last time a real user interacted (used by compression).
@@ -129,13 +171,26 @@ type Thread struct {
 	defaultReplyForwarded bool           // When true, the default sink actually delivered assistant text this turn (reset after each turn). Used by implicitCallerForwardHook.
 	currentSink           Sink           // Current turn's active sink (set by run(), cleared on turn end). Used by dispatch(to=caller:*).
 	currentCallerKey      string         // Caller session key for the current wake; empty for user/system wakes.
+	dryRun                bool           // Current turn runs mutating tools in simulation mode (set by RunOnce, reset after each turn).
+	admin                 bool           // Current turn may use admin-only tools (set by RunOnce, reset after each turn).
+	subagentSpawns        int            // dispatch(to=subagent) calls in the current turn (reset after each turn).
+	turnTools             *tools.Registry // Channel-filtered tools for the current turn; nil = all tools (set by run(), cleared on turn end).
+	inheritedTools        []string       // Tool limit carried by the current wake (WakeMessage.AllowedTools; set by RunOnce, reset after each turn).
+	drafting              bool           // Current turn holds user-facing replies until draft_reply finalize (reset after each turn).
+	draftParts            []string       // Intermediate replies held while drafting.
 
 	execMetrics           *ExecMetrics // Non-nil only while a turn is executing.
 	lastCompressAttemptAt time.Time    // Last time tier 2 compression was enqueued (prevents duplicate enqueue).
 	lastCompressedAt      time.Time    // Last time tier 2 compression completed successfully.
 
+	turnCancel context.CancelFunc // Cancels the turn in flight; nil when idle. Used by Manager.Abort.
+	aborted    bool               // Set by abort; RunOnce reports a user stop instead of an error.
+
 	memoryIndexCache   string    // Cached buildMemoryIndexSection result.
 	memoryIndexModTime time.Time // Directory modtime when cache was built.
+
+	modelOverride       *config.ModelConfig // Cached set_model override from meta.json; nil = none.
+	modelOverrideLoaded bool                // modelOverride is current; cleared when set_model/reset_model writes.
 }
 
 // ToolCallRecord is an alias for msg.ToolCallRecord.
@@ -2,7 +2,10 @@ package thread
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,6 +20,9 @@ func (t *Thread) Enqueue(msg *WakeMessage) {
 	if msg == nil {
 		return
 	}
+	if msg.EnqueuedAt.IsZero() {
+		msg.EnqueuedAt = time.Now()
+	}
 	t.inbox <- msg
 	// Non-blocking notify: if signal already has a pending notification, skip.
 	select {
@@ -31,44 +37,172 @@ func (t *Thread) hasMessages() bool {
 	return len(t.pending) > 0 || len(t.inbox) > 0
 }
 
-// tryMerge drains the inbox for consecutive messages with the same
-// Source + AgentName + Vars, concatenating their Message fields and
-// keeping the last Sink.  Non-mergeable messages are stored in t.pending
-// (instead of requeuing to the channel) to avoid deadlock when the inbox
-// buffer is full.
+// tryMerge folds every queued message with the same Source + AgentName +
+// Vars + priority into first, concatenating their Message fields and keeping
+// the last Sink. Non-mergeable messages stay in t.pending (instead of being
+// requeued to the channel) to avoid deadlock when the inbox buffer is full.
 func (t *Thread) tryMerge(first *WakeMessage) *WakeMessage {
+	t.drainInbox()
 	merged := 0
-	var deferred []*WakeMessage
-	for {
-		select {
-		case next := <-t.inbox:
-			if canMerge(first, next) {
-				first.Message += "\n" + next.Message
-				first.Sink = next.Sink
-				merged++
-			} else {
-				deferred = append(deferred, next)
+	kept := t.pending[:0]
+	for _, next := range t.pending {
+		if canMerge(first, next) {
+			if first.Prepare != nil || next.Prepare != nil {
+				head, tail := *first, *next
+				first.Prepare = func(ctx context.Context) string {
+					return head.Text(ctx) + "\n" + tail.Text(ctx)
+				}
 			}
-		default:
-			// Store non-mergeable messages for the next RunOnce call
-			// rather than pushing them back into the channel.
-			t.pending = append(t.pending, deferred...)
-			if merged > 0 {
-				logger.Info("merged wake messages",
-					"threadID", t.id,
-					"sessionKey", t.sessionKey,
-					"source", first.Source,
-					"merged", merged+1,
-					"deferred", len(deferred),
-				)
+			first.Message += "\n" + next.Message
+			first.Sink = next.Sink
+			merged++
+		} else {
+			kept = append(kept, next)
+		}
+	}
+	clear(t.pending[len(kept):])
+	t.pending = kept
+	if merged > 0 {
+		logger.Info("merged wake messages",
+			"threadID", t.id,
+			"sessionKey", t.sessionKey,
+			"source", first.Source,
+			"merged", merged+1,
+			"deferred", len(kept),
+		)
+	}
+	return first
+}
+
+// maxResultsPerTurn caps how many subagent results drainPendingResults folds
+// into one turn; the rest wait for the next turn.
+const maxResultsPerTurn = 5
+
+// isSubagentResult reports whether m is a reply from one of this session's
+// own subagents ({session}:threads:{taskID}).
+func (t *Thread) isSubagentResult(m *WakeMessage) bool {
+	return m.Source == WakeSession && strings.HasPrefix(m.CallerSessionKey, t.sessionKey+":threads:")
+}
+
+// subagentTaskID returns the task ID of a subagent session key.
+func subagentTaskID(key string) string {
+	_, id, _ := strings.Cut(key, ":threads:")
+	return id
+}
+
+// drainPendingResults folds the subagent results queued for this thread into
+// first, so a burst of finished tasks is handled in one turn. Only the first
+// result per task is kept: later ones (retried deliveries) are dropped, their
+// callbacks chained onto the kept result so their waiters still hear back.
+// The rest are ordered by completion time, and at most maxResultsPerTurn are
+// taken; later ones stay in t.pending for the next turn. The earliest
+// result's sink and caller are kept for the turn.
+func (t *Thread) drainPendingResults(first *WakeMessage) *WakeMessage {
+	t.drainInbox()
+	results := []*WakeMessage{first}
+	seen := map[string]*WakeMessage{subagentTaskID(first.CallerSessionKey): first}
+	dropped := 0
+	kept := t.pending[:0]
+	for _, next := range t.pending {
+		if !t.isSubagentResult(next) || next.AgentName != first.AgentName || next.DryRun != first.DryRun || next.Admin != first.Admin || !slices.Equal(next.AllowedTools, first.AllowedTools) {
+			kept = append(kept, next)
+			continue
+		}
+		id := subagentTaskID(next.CallerSessionKey)
+		if orig := seen[id]; orig != nil {
+			chainCallbacks(orig, next)
+			dropped++
+			continue
+		}
+		seen[id] = next
+		results = append(results, next)
+	}
+	clear(t.pending[len(kept):])
+	t.pending = kept
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].EnqueuedAt.Before(results[j].EnqueuedAt) })
+	if len(results) > maxResultsPerTurn {
+		// Spilled results rejoin the queue in arrival order.
+		t.pending = append(t.pending, results[maxResultsPerTurn:]...)
+		sort.SliceStable(t.pending, func(i, j int) bool { return t.pending[i].EnqueuedAt.Before(t.pending[j].EnqueuedAt) })
+		results = results[:maxResultsPerTurn]
+	}
+	if len(results) > 1 || dropped > 0 {
+		logger.Info("batched subagent results",
+			"threadID", t.id,
+			"sessionKey", t.sessionKey,
+			"batched", len(results),
+			"duplicates", dropped,
+			"deferred", len(t.pending),
+		)
+	}
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	batch := *results[0]
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Results from %d subagent tasks, in completion order:", len(results))
+	for _, r := range results {
+		fmt.Fprintf(&sb, "\n\n## task %s\n\n%s", subagentTaskID(r.CallerSessionKey), strings.TrimSpace(r.Message))
+	}
+	batch.Message = sb.String()
+	batch.OnComplete = func(response string) {
+		for _, r := range results {
+			if r.OnComplete != nil {
+				r.OnComplete(response)
+			}
+		}
+	}
+	batch.OnResult = func(res TurnResult) {
+		for _, r := range results {
+			if r.OnResult != nil {
+				r.OnResult(res)
+			}
+		}
+	}
+	return &batch
+}
+
+// chainCallbacks makes m also run dup's OnComplete and OnResult, after its own.
+func chainCallbacks(m, dup *WakeMessage) {
+	if done := dup.OnComplete; done != nil {
+		prev := m.OnComplete
+		m.OnComplete = func(response string) {
+			if prev != nil {
+				prev(response)
 			}
-			return first
+			done(response)
 		}
 	}
+	if done := dup.OnResult; done != nil {
+		prev := m.OnResult
+		m.OnResult = func(res TurnResult) {
+			if prev != nil {
+				prev(res)
+			}
+			done(res)
+		}
+	}
+}
+
+// isAdminWake reports whether a wake may use admin-only tools: messages from
+// the configured admin and the local one-shot CLI. System wakes (cron,
+// heartbeat, resume) are admin only when their producer carried the flag
+// over from an admin origin — the source kind alone grants nothing, so a
+// reminder set by a regular user fires as a regular turn.
+func isAdminWake(msg *WakeMessage) bool {
+	return msg.Admin || msg.Source == WakeCLI
 }
 
 func canMerge(a, b *WakeMessage) bool {
-	if a.Source != b.Source || a.AgentName != b.AgentName {
+	if a.Source != b.Source || a.AgentName != b.AgentName || a.DryRun != b.DryRun || a.Admin != b.Admin {
+		return false
+	}
+	if !slices.Equal(a.AllowedTools, b.AllowedTools) {
+		return false
+	}
+	if a.EffectivePriority() != b.EffectivePriority() {
 		return false
 	}
 	// Don't merge messages with different Sinks to prevent cross-delivery
@@ -87,20 +221,70 @@ func canMerge(a, b *WakeMessage) bool {
 	return true
 }
 
-// dequeue returns the next WakeMessage, preferring deferred messages
-// (from a previous tryMerge) over the inbox channel.
-func (t *Thread) dequeue() (*WakeMessage, bool) {
-	if len(t.pending) > 0 {
-		m := t.pending[0]
-		t.pending = t.pending[1:]
-		return m, true
+// drainInbox moves messages buffered in the inbox channel onto t.pending,
+// preserving arrival order. pending holds at most cap(t.inbox) messages; the
+// rest stay in the inbox, so a flooded thread still blocks Enqueue instead
+// of buffering without bound.
+func (t *Thread) drainInbox() {
+	for len(t.pending) < cap(t.inbox) {
+		select {
+		case m := <-t.inbox:
+			t.pending = append(t.pending, m)
+		default:
+			return
+		}
 	}
-	select {
-	case m := <-t.inbox:
-		return m, true
-	default:
+}
+
+// dequeue returns the next WakeMessage: the earliest queued message with the
+// highest EffectivePriority, so cron and admin wakes are not starved by a
+// flood of user messages. Order within a priority is FIFO.
+func (t *Thread) dequeue() (*WakeMessage, bool) {
+	t.drainInbox()
+	if len(t.pending) == 0 {
 		return nil, false
 	}
+	best := 0
+	for i := 1; i < len(t.pending); i++ {
+		if t.pending[i].EffectivePriority() > t.pending[best].EffectivePriority() {
+			best = i
+		}
+	}
+	m := t.pending[best]
+	t.pending = append(t.pending[:best], t.pending[best+1:]...)
+	return m, true
+}
+
+// setTurnCancel records (or clears, with nil) the cancel func of the turn in
+// flight so abort can stop it.
+func (t *Thread) setTurnCancel(cancel context.CancelFunc) {
+	t.mu.Lock()
+	t.turnCancel = cancel
+	if cancel == nil {
+		t.aborted = false
+	}
+	t.mu.Unlock()
+}
+
+// abort cancels the turn in flight. Returns false when no turn is running.
+func (t *Thread) abort() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.turnCancel == nil {
+		return false
+	}
+	t.aborted = true
+	t.turnCancel()
+	return true
+}
+
+// takeAborted reports whether the current turn was aborted and clears the flag.
+func (t *Thread) takeAborted() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	aborted := t.aborted
+	t.aborted = false
+	return aborted
 }
 
 // RunOnce dequeues one WakeMessage and executes a single turn.
@@ -109,7 +293,11 @@ func (t *Thread) RunOnce(ctx context.Context) {
 	if !ok {
 		return
 	}
-	msg = t.tryMerge(msg)
+	if t.isSubagentResult(msg) {
+		msg = t.drainPendingResults(msg)
+	} else {
+		msg = t.tryMerge(msg)
+	}
 	t.lastWakeSource = msg.Source
 	if name := strings.TrimSpace(msg.AgentName); name != "" {
 		a, err := t.cfg().Agents.New(name)
@@ -170,6 +358,8 @@ func (t *Thread) RunOnce(ctx context.Context) {
 				Label:     "rephrase → " + originalSink.Label,
 				React:     originalSink.React,
 				Chunkable: false,
+				SendFile:  originalSink.SendFile,
+				SendEmbed: originalSink.SendEmbed,
 				Send: func(ctx context.Context, response string) error {
 					mgr.Wake(parentKey+session.RephraseSessionSuffix, &WakeMessage{
 						Source:    WakeRephrase,
@@ -213,8 +403,34 @@ func (t *Thread) RunOnce(ctx context.Context) {
 		agentName = t.Agent.Name
 	}
 	t.mu.Unlock()
+
+	// Subagents always run under a deadline so a stuck task frees its slot;
+	// the error below reaches the caller through the paired sink.
+	runCtx := ctx
+	timeout := msg.Timeout
+	if timeout <= 0 && isSubagentSession(t.sessionKey) {
+		timeout = t.mgr.subagentTimeout()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Abort (user /stop) cancels this context; messages already persisted
+	// by the write-ahead and incremental saves are kept.
+	runCtx, cancelTurn := context.WithCancel(runCtx)
+	t.setTurnCancel(cancelTurn)
+	defer func() {
+		t.setTurnCancel(nil)
+		cancelTurn()
+	}()
+
+	// Prepare (e.g. translation) runs here rather than on the channel's
+	// dispatch loop, so a slow call neither blocks /stop nor outlives it.
+	text := msg.Text(runCtx)
 	sender := senderOrDefault(msg.Sender, msg.Source)
-	userMessage := buildWakePayload(msg.Source, msg.Message, t.id, t.sessionKey, sessionDir, deliveryLabel, modelLabel, agentName, loc, sender, msg.CallerSessionKey, msg.Vars)
+	userMessage := buildWakePayload(msg.Source, text, t.id, t.sessionKey, sessionDir, deliveryLabel, modelLabel, agentName, loc, sender, msg.CallerSessionKey, msg.Vars)
 
 	// Build injection function: between tool iterations, drain inbox for
 	// mergeable user messages and inject them into the LLM conversation.
@@ -222,11 +438,11 @@ func (t *Thread) RunOnce(ctx context.Context) {
 	// requeue deadlock.
 	injectFn := func() []provider.Message {
 		var injected []provider.Message
-		for {
+		for len(t.pending) < cap(t.inbox) {
 			select {
 			case next := <-t.inbox:
 				if canMerge(msg, next) {
-					payload := buildWakePayload(next.Source, next.Message, t.id, t.sessionKey, sessionDir, deliveryLabel, modelLabel, agentName, loc, senderOrDefault(next.Sender, next.Source), next.CallerSessionKey)
+					payload := buildWakePayload(next.Source, next.Text(runCtx), t.id, t.sessionKey, sessionDir, deliveryLabel, modelLabel, agentName, loc, senderOrDefault(next.Sender, next.Source), next.CallerSessionKey)
 					if payload != "" {
 						payload = markInjected(payload)
 						injected = append(injected, provider.UserMessage(payload))
@@ -244,9 +460,33 @@ func (t *Thread) RunOnce(ctx context.Context) {
 				return injected
 			}
 		}
+		return injected
 	}
 
-	response, err := t.run(ctx, userMessage, sink, msg.CallerSessionKey, injectFn, string(msg.Source))
+	t.setDryRun(msg.DryRun || session.ReadMeta(sessionDir).DryRun)
+	t.setAdmin(isAdminWake(msg))
+	t.setInheritedTools(msg.AllowedTools)
+	response, usage, err := t.run(runCtx, userMessage, sink, msg.CallerSessionKey, injectFn, string(msg.Source))
+	t.setDryRun(false)
+	t.setAdmin(false)
+	t.setInheritedTools(nil)
+	t.resetSubagentSpawns()
+	t.resetDraft()
+	aborted := t.takeAborted()
+	if err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("turn timed out after %s: %w", timeout, err)
+	}
+	if aborted && ctx.Err() == nil {
+		logger.Info("thread turn aborted", "threadID", t.id, "sessionKey", t.sessionKey, "source", msg.Source)
+		err = nil
+		response = ""
+		if !sink.IsZero() {
+			notice := sysmsg.BuildSystemMessage("stopped", nil, "The current turn was stopped. Steps that already completed were saved.")
+			if sinkErr := sink.WithRetry(3).Send(ctx, notice); sinkErr != nil {
+				logger.Error("sink delivery error", "threadID", t.id, "sessionKey", t.sessionKey, "err", sinkErr)
+			}
+		}
+	}
 
 	// Run post-turn hooks BEFORE consuming the per-turn flags so hooks see
 	// the state accurately. Returned strings are persisted as user-role
@@ -276,6 +516,19 @@ func (t *Thread) RunOnce(ctx context.Context) {
 	if msg.OnComplete != nil {
 		msg.OnComplete(response)
 	}
+	if msg.OnResult != nil {
+		msg.OnResult(TurnResult{
+			Response: response,
+			Usage: TurnUsage{
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+				TotalTokens:      usage.TotalTokens,
+				CachedTokens:     usage.CachedTokens,
+				ReasoningTokens:  usage.ReasoningTokens,
+			},
+			Err: err,
+		})
+	}
 }
 
 // buildWakePayload constructs the user message from a wake source and message.
@@ -331,15 +584,16 @@ func buildWakePayload(source WakeSource, message, threadID, sessionKey, sessionD
 	// Only set fields for true capabilities — false is the default and omitted.
 	if model != "" {
 		if prov, mod, ok := strings.Cut(model, "/"); ok {
-			if provider.SupportsVision(prov, mod) {
+			caps := provider.Capabilities(prov, mod)
+			if caps.Vision {
 				v := true
 				header.SupportsVision = &v
 			}
-			if provider.SupportsAudio(prov, mod) {
+			if caps.Audio {
 				a := true
 				header.SupportsAudio = &a
 			}
-			if provider.SupportsPDF(prov, mod) {
+			if caps.PDF {
 				p := true
 				header.SupportsPDF = &p
 			}
@@ -1,6 +1,8 @@
 package thread
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -157,3 +159,141 @@ func TestMarkInjected_NoFrontmatter(t *testing.T) {
 		t.Errorf("expected pass-through, got %q", got)
 	}
 }
+
+func TestDequeuePrefersHigherPriority(t *testing.T) {
+	th := &Thread{inbox: make(chan *WakeMessage, 8)}
+	th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: "u1"})
+	th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: "u2"})
+	th.Enqueue(&WakeMessage{Source: WakeCron, Message: "cron"})
+	th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: "admin", Priority: PriorityHigh})
+	th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: "u3"})
+
+	var got []string
+	for {
+		m, ok := th.dequeue()
+		if !ok {
+			break
+		}
+		got = append(got, th.tryMerge(m).Message)
+	}
+	// High-priority wakes go first in arrival order; the admin message is not
+	// merged into the user run, which still merges across the gap.
+	want := []string{"cron", "admin", "u1\nu2\nu3"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("processing order = %q, want %q", got, want)
+	}
+}
+
+func TestTryMergeKeepsEachMessagesPrepare(t *testing.T) {
+	th := &Thread{inbox: make(chan *WakeMessage, 8)}
+	upper := func(s string) func(context.Context) string {
+		return func(context.Context) string { return strings.ToUpper(s) }
+	}
+	th.Enqueue(&WakeMessage{Source: WakeFeishu, Message: "a", Prepare: upper("a")})
+	th.Enqueue(&WakeMessage{Source: WakeFeishu, Message: "b"})
+	th.Enqueue(&WakeMessage{Source: WakeFeishu, Message: "c", Prepare: upper("c")})
+
+	m, _ := th.dequeue()
+	m = th.tryMerge(m)
+	if got := m.Text(context.Background()); got != "A\nb\nC" {
+		t.Errorf("merged text = %q, want each message prepared on its own", got)
+	}
+	if m.Message != "a\nb\nc" {
+		t.Errorf("merged preview = %q, want the raw messages", m.Message)
+	}
+}
+
+func TestDrainPendingResultsDedupOrderAndCap(t *testing.T) {
+	th := &Thread{sessionKey: "telegram:1", inbox: make(chan *WakeMessage, 16)}
+	base := time.Now()
+	result := func(caller, body string, at int) *WakeMessage {
+		return &WakeMessage{Source: WakeSession, CallerSessionKey: caller, Message: body, EnqueuedAt: base.Add(time.Duration(at) * time.Second)}
+	}
+	own := func(task string) string { return "telegram:1:threads:" + task }
+
+	th.Enqueue(result(own("b"), "B done", 2))
+	th.Enqueue(result(own("a"), "A done", 1))
+	var retryDone []string
+	retry := result(own("b"), "B done, retried", 3) // retried delivery
+	retry.OnComplete = func(response string) { retryDone = append(retryDone, response) }
+	th.Enqueue(retry)
+	th.Enqueue(result(own("c"), "C done", 4))
+	th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: "hi", EnqueuedAt: base.Add(5 * time.Second)})
+	th.Enqueue(result("telegram:2:threads:z", "not ours", 6))
+	for i, task := range []string{"d", "e", "f", "g"} {
+		th.Enqueue(result(own(task), strings.ToUpper(task)+" done", 7+i))
+	}
+
+	first, _ := th.dequeue()
+	batch := th.drainPendingResults(first)
+	var order []string
+	for _, line := range strings.Split(batch.Message, "\n") {
+		if task, ok := strings.CutPrefix(line, "## task "); ok {
+			order = append(order, task)
+		}
+	}
+	if got := strings.Join(order, ","); got != "a,b,c,d,e" {
+		t.Errorf("batched tasks = %s, want a,b,c,d,e (deduplicated, completion order, capped at %d)", got, maxResultsPerTurn)
+	}
+	if strings.Count(batch.Message, "B done") != 1 {
+		t.Errorf("duplicate result injected twice:\n%s", batch.Message)
+	}
+	batch.OnComplete("ok")
+	if len(retryDone) != 1 || retryDone[0] != "ok" {
+		t.Errorf("dropped duplicate's OnComplete calls = %q, want one with the batch response", retryDone)
+	}
+	if batch.CallerSessionKey != own("a") {
+		t.Errorf("batch caller = %q, want the earliest result's", batch.CallerSessionKey)
+	}
+
+	var rest []string
+	for _, m := range th.pending {
+		rest = append(rest, m.Message)
+	}
+	if got := strings.Join(rest, "|"); got != "hi|not ours|F done|G done" {
+		t.Errorf("pending after batch = %q", got)
+	}
+
+	// The spilled results form the next batch once earlier wakes are handled.
+	th.dequeue()
+	th.dequeue()
+	next, _ := th.dequeue()
+	if batch := th.drainPendingResults(next); !strings.Contains(batch.Message, "## task f") || !strings.Contains(batch.Message, "## task g") {
+		t.Errorf("spilled batch = %q", batch.Message)
+	}
+}
+
+func TestDequeueKeepsInboxBackpressure(t *testing.T) {
+	th := &Thread{inbox: make(chan *WakeMessage, 2)}
+	for i := range 2 {
+		th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: fmt.Sprint("a", i)})
+	}
+	if _, ok := th.dequeue(); !ok {
+		t.Fatal("dequeue found nothing")
+	}
+	// One message waits in pending; refill the inbox to capacity.
+	for i := range 2 {
+		th.Enqueue(&WakeMessage{Source: WakeTelegram, Message: fmt.Sprint("b", i)})
+	}
+	if _, ok := th.dequeue(); !ok {
+		t.Fatal("dequeue found nothing")
+	}
+	if len(th.pending) > cap(th.inbox) {
+		t.Fatalf("pending holds %d messages, want at most the inbox size %d", len(th.pending), cap(th.inbox))
+	}
+	if len(th.inbox) == 0 {
+		t.Error("dequeue drained the whole inbox into pending")
+	}
+	// Everything is still delivered, in order.
+	var got []string
+	for {
+		m, ok := th.dequeue()
+		if !ok {
+			break
+		}
+		got = append(got, m.Message)
+	}
+	if strings.Join(got, ",") != "b0,b1" {
+		t.Errorf("remaining = %q, want [b0 b1]", got)
+	}
+}
@@ -0,0 +1,456 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// patchMaxFuzz is the maximum number of leading/trailing context lines that
+// may be ignored when a hunk does not match as written (same idea as GNU
+// patch's fuzz factor).
+const patchMaxFuzz = 2
+
+// ApplyPatchTool applies a unified diff to one or more files.
+type ApplyPatchTool struct {
+	workspace           string
+	restrictToWorkspace bool
+}
+
+// NewApplyPatchTool creates an apply_patch tool rooted at workspace.
+func NewApplyPatchTool(workspace string, restrictToWorkspace bool) *ApplyPatchTool {
+	return &ApplyPatchTool{workspace: workspace, restrictToWorkspace: restrictToWorkspace}
+}
+
+// Def returns the tool definition.
+func (t *ApplyPatchTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "apply_patch",
+			Description: "Apply a unified diff (---/+++/@@ format) to one or more files. Relative paths are resolved from workspace root; a/ and b/ prefixes are stripped. Context is matched fuzzily (whitespace differences and shifted line numbers are tolerated). Use --- /dev/null to create a file and +++ /dev/null to delete one. Each file is applied all-or-nothing; hunks that fail are reported back.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"patch": map[string]any{
+						"type":        "string",
+						"description": "The unified diff text.",
+					},
+				},
+				"required": []string{"patch"},
+			},
+		},
+	}
+}
+
+type applyPatchArgs struct {
+	Patch string `json:"patch" required:"true" alias:"diff"`
+}
+
+// RunResult executes the tool.
+func (t *ApplyPatchTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "apply_patch", fileToolTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *ApplyPatchTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *ApplyPatchTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a applyPatchArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	files, err := parseUnifiedDiff(a.Patch)
+	if err != nil {
+		return ErrorResult("apply_patch", err.Error())
+	}
+	if len(files) == 0 {
+		return ErrorResult("apply_patch", "no file headers (---/+++) found in patch")
+	}
+
+	dryRun := RuntimeContextFrom(ctx).DryRun
+	var report strings.Builder
+	applied, failed := 0, 0
+	for _, fp := range files {
+		if ctx.Err() != nil {
+			return ErrorResult("apply_patch", "operation cancelled before write")
+		}
+		line, ok := t.applyFile(fp, dryRun)
+		report.WriteString(line)
+		if ok {
+			applied++
+		} else {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return ErrorResult("apply_patch", fmt.Sprintf("%d of %d file(s) failed; failed files were left unchanged.\n\n%s", failed, len(files), report.String()))
+	}
+	fields := map[string]any{"files": applied}
+	if dryRun {
+		fields["dry_run"] = true
+	}
+	return okResult("apply_patch", fields, report.String())
+}
+
+// applyFile applies one file's hunks and returns a report line and whether it succeeded.
+func (t *ApplyPatchTool) applyFile(fp *filePatch, dryRun bool) (string, bool) {
+	target := fp.newPath
+	if fp.isDelete() {
+		target = fp.oldPath
+	}
+	path := resolveToolPath(target, t.workspace)
+	resolvedPath := absOrOriginal(path)
+	if t.restrictToWorkspace && !pathWithinWorkspace(resolvedPath, t.workspace) {
+		return fmt.Sprintf("- %s: FAILED: outside workspace (restrictToWorkspace is enabled)\n", target), false
+	}
+
+	var lines []string
+	trailingNewline := true
+	if !fp.isCreate() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Sprintf("- %s: FAILED: %v\n", target, err), false
+		}
+		s := string(content)
+		trailingNewline = s == "" || strings.HasSuffix(s, "\n")
+		if s != "" {
+			lines = strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+		}
+	} else if _, err := os.Stat(path); err == nil {
+		return fmt.Sprintf("- %s: FAILED: file already exists\n", target), false
+	}
+
+	var rejected []string
+	offset := 0
+	for i, h := range fp.hunks {
+		oldLines, newLines := h.oldLines(), h.newLines()
+		// Zero-length old side (pure insertion) names the line to insert after.
+		base := h.oldStart - 1
+		if len(oldLines) == 0 {
+			base = h.oldStart
+		}
+		pos, lead, trail, ok := locateHunk(lines, h, base+offset)
+		if !ok {
+			rejected = append(rejected, fmt.Sprintf("  hunk #%d %s: context not found\n%s", i+1, h.header, indentHunk(h.body)))
+			continue
+		}
+		// Rebuild the region from the hunk body, keeping the file's own
+		// copy of context lines so fuzzy matches don't rewrite whitespace.
+		// Fuzz-dropped edge context is skipped entirely.
+		matched := len(oldLines) - lead - trail
+		var repl []string
+		cursor := pos
+		for _, l := range h.body[lead : len(h.body)-trail] {
+			switch l[0] {
+			case ' ':
+				repl = append(repl, lines[cursor])
+				cursor++
+			case '-':
+				cursor++
+			case '+':
+				repl = append(repl, l[1:])
+			}
+		}
+		out := make([]string, 0, len(lines)-matched+len(repl))
+		out = append(out, lines[:pos]...)
+		out = append(out, repl...)
+		out = append(out, lines[pos+matched:]...)
+		lines = out
+		// Track drift so later hunks search from their shifted position.
+		offset = pos - lead - base + len(newLines) - len(oldLines)
+	}
+	if len(rejected) > 0 {
+		return fmt.Sprintf("- %s: FAILED: %d of %d hunk(s) rejected\n%s", target, len(rejected), len(fp.hunks), strings.Join(rejected, "")), false
+	}
+
+	action := "patched"
+	switch {
+	case fp.isDelete():
+		action = "deleted"
+	case fp.isCreate():
+		action = "created"
+	}
+	if dryRun {
+		return fmt.Sprintf("- %s: would be %s (%d hunk(s))\n", resolvedPath, action, len(fp.hunks)), true
+	}
+
+	if fp.isDelete() {
+		if err := os.Remove(path); err != nil {
+			return fmt.Sprintf("- %s: FAILED: %v\n", target, err), false
+		}
+		return fmt.Sprintf("- %s: %s\n", resolvedPath, action), true
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Sprintf("- %s: FAILED: create parent directory: %v\n", target, err), false
+	}
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 && trailingNewline {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Sprintf("- %s: FAILED: %v\n", target, err), false
+	}
+	return fmt.Sprintf("- %s: %s (%d hunk(s))\n", resolvedPath, action, len(fp.hunks)), true
+}
+
+// locateHunk finds where the hunk's old side occurs in lines, searching
+// outward from hint. It tries progressively looser line comparisons, then
+// drops up to patchMaxFuzz leading/trailing context lines. Returns the match
+// position and how many leading/trailing old lines were ignored.
+func locateHunk(lines []string, h *hunk, hint int) (pos, lead, trail int, ok bool) {
+	old := h.oldLines()
+	if len(old) == 0 {
+		return clampInt(hint, 0, len(lines)), 0, 0, true
+	}
+	leadCtx, trailCtx := h.contextEdges()
+	for fuzz := 0; fuzz <= patchMaxFuzz; fuzz++ {
+		lead, trail := min(fuzz, leadCtx), min(fuzz, trailCtx)
+		if fuzz > 0 && lead+trail == 0 {
+			break
+		}
+		want := old[lead : len(old)-trail]
+		if len(want) == 0 {
+			continue
+		}
+		for _, eq := range []func(a, b string) bool{
+			func(a, b string) bool { return a == b },
+			func(a, b string) bool { return strings.TrimRight(a, " \t\r") == strings.TrimRight(b, " \t\r") },
+			func(a, b string) bool { return strings.TrimSpace(a) == strings.TrimSpace(b) },
+		} {
+			if p, found := searchLines(lines, want, hint+lead, eq); found {
+				return p, lead, trail, true
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// searchLines looks for want in lines starting at hint and alternating
+// outward, so the closest match to the expected position wins.
+func searchLines(lines, want []string, hint int, eq func(a, b string) bool) (int, bool) {
+	maxStart := len(lines) - len(want)
+	if maxStart < 0 {
+		return 0, false
+	}
+	hint = clampInt(hint, 0, maxStart)
+	matchAt := func(p int) bool {
+		for i, w := range want {
+			if !eq(lines[p+i], w) {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0; d <= maxStart; d++ {
+		if p := hint - d; p >= 0 && matchAt(p) {
+			return p, true
+		}
+		if p := hint + d; d > 0 && p <= maxStart && matchAt(p) {
+			return p, true
+		}
+		if hint-d < 0 && hint+d > maxStart {
+			break
+		}
+	}
+	return 0, false
+}
+
+func pathWithinWorkspace(path, workspace string) bool {
+	if workspace == "" {
+		return true
+	}
+	absWorkspace, err := filepath.Abs(workspace)
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(absWorkspace); err == nil {
+		absWorkspace = resolved
+	}
+	// The target may not exist yet; resolve symlinks on its nearest existing ancestor.
+	dir, rest := path, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			path = filepath.Join(resolved, rest)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+	sep := string(filepath.Separator)
+	return path == absWorkspace || strings.HasPrefix(path+sep, absWorkspace+sep)
+}
+
+func indentHunk(body []string) string {
+	var sb strings.Builder
+	for _, l := range body {
+		sb.WriteString("    " + l + "\n")
+	}
+	return sb.String()
+}
+
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// --- unified diff parsing ---
+
+type filePatch struct {
+	oldPath string // "" when the old side is /dev/null
+	newPath string // "" when the new side is /dev/null
+	hunks   []*hunk
+}
+
+func (f *filePatch) isCreate() bool { return f.oldPath == "" }
+func (f *filePatch) isDelete() bool { return f.newPath == "" }
+
+type hunk struct {
+	header   string
+	oldStart int
+	body     []string // raw lines, each prefixed with ' ', '-' or '+'
+}
+
+func (h *hunk) oldLines() []string {
+	var out []string
+	for _, l := range h.body {
+		if l[0] == ' ' || l[0] == '-' {
+			out = append(out, l[1:])
+		}
+	}
+	return out
+}
+
+func (h *hunk) newLines() []string {
+	var out []string
+	for _, l := range h.body {
+		if l[0] == ' ' || l[0] == '+' {
+			out = append(out, l[1:])
+		}
+	}
+	return out
+}
+
+// contextEdges counts the unchanged lines at the start and end of the hunk.
+func (h *hunk) contextEdges() (lead, trail int) {
+	for _, l := range h.body {
+		if l[0] != ' ' {
+			break
+		}
+		lead++
+	}
+	for i := len(h.body) - 1; i >= 0 && h.body[i][0] == ' '; i-- {
+		trail++
+	}
+	if lead == len(h.body) {
+		trail = 0
+	}
+	return lead, trail
+}
+
+// parseUnifiedDiff splits a unified diff into per-file patches. Lines
+// outside file sections (e.g. "diff --git", "index") are ignored.
+func parseUnifiedDiff(patch string) ([]*filePatch, error) {
+	var files []*filePatch
+	var cur *filePatch
+	var h *hunk
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			cur = &filePatch{
+				oldPath: diffPath(line[4:]),
+				newPath: diffPath(lines[i+1][4:]),
+			}
+			if cur.oldPath == "" && cur.newPath == "" {
+				return nil, fmt.Errorf("line %d: both sides are /dev/null", i+1)
+			}
+			files = append(files, cur)
+			h = nil
+			i++
+		case strings.HasPrefix(line, "@@"):
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: hunk header before file header", i+1)
+			}
+			oldStart, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			h = &hunk{header: line, oldStart: oldStart}
+			cur.hunks = append(cur.hunks, h)
+		case h != nil && line != "" && (line[0] == ' ' || line[0] == '-' || line[0] == '+'):
+			h.body = append(h.body, line)
+		case h != nil && line == "" && i < len(lines)-1:
+			// Some editors strip the leading space from blank context lines.
+			h.body = append(h.body, " ")
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" — ignored.
+		default:
+			h = nil
+		}
+	}
+	for _, f := range files {
+		if len(f.hunks) == 0 {
+			return nil, fmt.Errorf("file %q has no hunks", firstNonEmpty(f.newPath, f.oldPath))
+		}
+	}
+	return files, nil
+}
+
+// diffPath extracts the path from a ---/+++ header, dropping timestamps and
+// the conventional a/ or b/ prefix. Returns "" for /dev/null.
+func diffPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// parseHunkHeader returns the old-side start line of "@@ -l,s +l,s @@".
+func parseHunkHeader(line string) (int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") {
+		return 0, fmt.Errorf("malformed hunk header %q", line)
+	}
+	start := strings.TrimPrefix(fields[1], "-")
+	if i := strings.IndexByte(start, ','); i >= 0 {
+		start = start[:i]
+	}
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, fmt.Errorf("malformed hunk header %q", line)
+	}
+	return n, nil
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
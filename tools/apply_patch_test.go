@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const patchSource = `package main
+
+func main() {
+	println("hello")
+	println("world")
+}
+`
+
+func runPatch(t *testing.T, tool *ApplyPatchTool, patch string) string {
+	t.Helper()
+	args, _ := json.Marshal(map[string]string{"patch": patch})
+	return tool.Run(context.Background(), args)
+}
+
+func writeSource(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestApplyPatch_Clean(t *testing.T) {
+	dir := t.TempDir()
+	p := writeSource(t, dir, "main.go", patchSource)
+
+	out := runPatch(t, NewApplyPatchTool(dir, false), `--- a/main.go
++++ b/main.go
+@@ -3,4 +3,5 @@
+ func main() {
+ 	println("hello")
+-	println("world")
++	println("there")
++	println("world!")
+ }
+`)
+	if !strings.Contains(out, "status: ok") {
+		t.Fatalf("expected success, got:\n%s", out)
+	}
+	b, _ := os.ReadFile(p)
+	want := strings.Replace(patchSource, "\tprintln(\"world\")\n", "\tprintln(\"there\")\n\tprintln(\"world!\")\n", 1)
+	if string(b) != want {
+		t.Fatalf("file = %q, want %q", string(b), want)
+	}
+}
+
+func TestApplyPatch_FuzzyContext(t *testing.T) {
+	dir := t.TempDir()
+	// File has drifted: an extra header line shifts everything down, and the
+	// model's copy of the context uses spaces instead of a tab.
+	p := writeSource(t, dir, "main.go", "// header\n"+patchSource)
+
+	out := runPatch(t, NewApplyPatchTool(dir, false), `--- main.go
++++ main.go
+@@ -3,4 +3,4 @@
+ func main() {
+     println("hello")
+-	println("world")
++	println("gopher")
+ }
+`)
+	if !strings.Contains(out, "status: ok") {
+		t.Fatalf("expected fuzzy success, got:\n%s", out)
+	}
+	b, _ := os.ReadFile(p)
+	if !strings.Contains(string(b), "\tprintln(\"hello\")\n\tprintln(\"gopher\")\n") || !strings.HasPrefix(string(b), "// header\n") {
+		t.Fatalf("unexpected result:\n%s", string(b))
+	}
+}
+
+func TestApplyPatch_RejectedHunk(t *testing.T) {
+	dir := t.TempDir()
+	p := writeSource(t, dir, "main.go", patchSource)
+
+	out := runPatch(t, NewApplyPatchTool(dir, false), `--- a/main.go
++++ b/main.go
+@@ -1,1 +1,1 @@
+-package main
++package app
+@@ -4,1 +4,1 @@
+-	println("nonexistent")
++	println("x")
+`)
+	if !strings.Contains(out, "status: error") || !strings.Contains(out, "hunk #2") || !strings.Contains(out, "nonexistent") {
+		t.Fatalf("expected rejected hunk #2 in report, got:\n%s", out)
+	}
+	b, _ := os.ReadFile(p)
+	if string(b) != patchSource {
+		t.Fatalf("file should be unchanged when a hunk is rejected, got:\n%s", string(b))
+	}
+}
+
+func TestApplyPatch_CreateFileOutsideWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewApplyPatchTool(dir, true)
+
+	out := runPatch(t, tool, `--- /dev/null
++++ b/pkg/new.txt
+@@ -0,0 +1,2 @@
++one
++two
+`)
+	if !strings.Contains(out, "status: ok") {
+		t.Fatalf("expected create to succeed, got:\n%s", out)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "pkg", "new.txt"))
+	if err != nil || string(b) != "one\ntwo\n" {
+		t.Fatalf("new file = %q, err = %v", string(b), err)
+	}
+
+	out = runPatch(t, tool, `--- /dev/null
++++ ../escape.txt
+@@ -0,0 +1 @@
++nope
+`)
+	if !strings.Contains(out, "outside workspace") {
+		t.Fatalf("expected workspace restriction, got:\n%s", out)
+	}
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// invalidArgsRawMaxRunes bounds the raw argument excerpt echoed back in
+// "invalid arguments" errors.
+const invalidArgsRawMaxRunes = 500
+
+// repairArgsJSON applies light, conservative repairs to malformed tool-call
+// arguments: trailing commas, single-quoted strings, unquoted object keys,
+// raw newlines/tabs inside strings, and literal \n between tokens. It returns
+// the repaired text and whether anything changed. The result is not
+// guaranteed to be valid JSON; callers must still unmarshal it.
+func repairArgsJSON(s string) (string, bool) {
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+
+	// stack tracks open containers: '{' or '['. expectKey is true right after
+	// '{' or after a ',' inside an object.
+	var stack []byte
+	expectKey := false
+	changed := false
+
+	inObject := func() bool { return len(stack) > 0 && stack[len(stack)-1] == '{' }
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\'':
+			end, str, fixed := scanArgString(s, i)
+			b.WriteString(str)
+			changed = changed || fixed
+			i = end
+			expectKey = false
+		case c == '{' || c == '[':
+			stack = append(stack, c)
+			expectKey = c == '{'
+			b.WriteByte(c)
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			expectKey = false
+			b.WriteByte(c)
+		case c == ',':
+			// Drop a comma followed only by whitespace and a closing bracket.
+			j := i + 1
+			for j < len(s) && isJSONSpace(s[j]) {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				changed = true
+				continue
+			}
+			expectKey = inObject()
+			b.WriteByte(c)
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("nrt", s[i+1]) >= 0:
+			// Literal \n between tokens, e.g. {\n  "a": 1}.
+			b.WriteByte(' ')
+			changed = true
+			i++
+		case expectKey && isIdentStart(c):
+			j := i
+			for j < len(s) && isIdentPart(s[j]) {
+				j++
+			}
+			b.WriteString(`"` + s[i:j] + `"`)
+			changed = true
+			expectKey = false
+			i = j - 1
+		default:
+			if !isJSONSpace(c) {
+				expectKey = false
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), changed
+}
+
+// scanArgString reads the string literal starting at s[start] (quoted with
+// ' or ") and returns the index of its closing quote, the literal re-encoded
+// with double quotes, and whether it had to be changed.
+func scanArgString(s string, start int) (int, string, bool) {
+	quote := s[start]
+	changed := quote == '\''
+	var b strings.Builder
+	b.WriteByte('"')
+	i := start + 1
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			next := s[i+1]
+			if quote == '\'' && next == '\'' {
+				b.WriteByte('\'') // \' is not a JSON escape
+				changed = true
+			} else {
+				b.WriteByte(c)
+				b.WriteByte(next)
+			}
+			i++
+		case c == quote:
+			b.WriteByte('"')
+			return i, b.String(), changed
+		case c == '"': // only reachable inside a single-quoted string
+			b.WriteString(`\"`)
+		case c == '\n':
+			b.WriteString(`\n`)
+			changed = true
+		case c == '\r':
+			b.WriteString(`\r`)
+			changed = true
+		case c == '\t':
+			b.WriteString(`\t`)
+			changed = true
+		default:
+			b.WriteByte(c)
+		}
+	}
+	// Unterminated: leave as-is for the decoder to report.
+	return len(s) - 1, s[start:], false
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c == '-' || (c >= '0' && c <= '9')
+}
+
+// RepairArgs applies repairArgsJSON to a tool call's raw arguments and
+// returns the result only if it is now valid JSON.
+func RepairArgs(args string) (string, bool) {
+	repaired, changed := repairArgsJSON(args)
+	if !changed || !json.Valid([]byte(repaired)) {
+		return "", false
+	}
+	return repaired, true
+}
+
+// unmarshalArgs decodes args into v, retrying once with repairArgsJSON when
+// the strict decode fails. On failure the error message quotes the raw
+// arguments so the model can see what it sent and correct it.
+func unmarshalArgs(args json.RawMessage, v any) string {
+	err := json.Unmarshal(args, v)
+	if err == nil {
+		return ""
+	}
+	if repaired, ok := repairArgsJSON(string(args)); ok {
+		// Drop anything the failed strict decode left behind.
+		rv := reflect.ValueOf(v).Elem()
+		rv.Set(reflect.Zero(rv.Type()))
+		if json.Unmarshal([]byte(repaired), v) == nil {
+			return ""
+		}
+	}
+	raw := string(args)
+	if runes := []rune(raw); len(runes) > invalidArgsRawMaxRunes {
+		raw = string(runes[:invalidArgsRawMaxRunes]) + "..."
+	}
+	return fmt.Sprintf("Error: invalid arguments: %v\nRaw arguments: %s\nSend a single JSON object with double-quoted keys and strings and no trailing commas.", err, raw)
+}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+// auditResultMaxRunes bounds the result excerpt stored per audit entry.
+const auditResultMaxRunes = 2000
+
+// auditNoSessionFile is the file used for tool calls without a session key.
+const auditNoSessionFile = "_no_session"
+
+// AuditEntry is one line of the tool-call audit log.
+type AuditEntry struct {
+	Time       time.Time       `json:"time"`
+	SessionKey string          `json:"session_key,omitempty"`
+	Tool       string          `json:"tool"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	Redacted   bool            `json:"redacted,omitempty"`
+	OK         bool            `json:"ok"`
+	Result     string          `json:"result"`
+	Truncated  bool            `json:"truncated,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// AuditLog appends tool calls as JSON lines, one file per session, under dir.
+type AuditLog struct {
+	dir    string
+	redact map[string]bool // tool names whose args are recorded as names only
+	mu     sync.Mutex
+}
+
+// NewAuditLog creates an audit log writing under dir. Tools named in
+// redactTools are flagged sensitive: only their argument names are recorded.
+func NewAuditLog(dir string, redactTools []string) *AuditLog {
+	redact := make(map[string]bool, len(redactTools))
+	for _, name := range redactTools {
+		if name = strings.TrimSpace(name); name != "" {
+			redact[name] = true
+		}
+	}
+	return &AuditLog{dir: dir, redact: redact}
+}
+
+// Record appends one entry for a completed tool call. Failures are logged and
+// never affect the tool call itself.
+func (a *AuditLog) Record(sessionKey, name string, args json.RawMessage, result string, start time.Time, latency time.Duration, ok bool) {
+	if a == nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:       start,
+		SessionKey: sessionKey,
+		Tool:       name,
+		OK:         ok,
+		DurationMs: latency.Milliseconds(),
+	}
+	if a.redact[name] {
+		entry.Args = redactArgs(args)
+		entry.Redacted = true
+	} else if redacted := logger.Redact(string(args)); json.Valid([]byte(redacted)) {
+		entry.Args = json.RawMessage(redacted)
+	} else if len(args) > 0 {
+		entry.Args, _ = json.Marshal(redacted)
+	}
+	result = logger.Redact(result)
+	entry.Result = result
+	if runes := []rune(result); len(runes) > auditResultMaxRunes {
+		entry.Result = string(runes[:auditResultMaxRunes])
+		entry.Truncated = true
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		logger.Warn("failed to encode audit entry", "tool", name, "err", err)
+		return
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		logger.Warn("failed to create audit log dir", "dir", a.dir, "err", err)
+		return
+	}
+	path := AuditLogPath(a.dir, sessionKey)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.Warn("failed to open audit log", "path", path, "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		logger.Warn("failed to write audit log", "path", path, "err", err)
+	}
+}
+
+// redactArgs keeps only the argument names so the entry still shows the
+// shape of the call.
+func redactArgs(args json.RawMessage) json.RawMessage {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(args, &m); err != nil {
+		return json.RawMessage(`"[redacted]"`)
+	}
+	out := make(map[string]string, len(m))
+	for k := range m {
+		out[k] = "[redacted]"
+	}
+	data, _ := json.Marshal(out)
+	return data
+}
+
+// AuditLogPath returns the JSONL file holding sessionKey's audit entries.
+func AuditLogPath(dir, sessionKey string) string {
+	name := auditNoSessionFile
+	if sessionKey = strings.TrimSpace(sessionKey); sessionKey != "" {
+		name = sessionFileName(sessionKey)
+	}
+	return filepath.Join(dir, name+".jsonl")
+}
+
+// sessionFileName maps a session key to a single safe path element
+// ("telegram:123" → "telegram_123").
+func sessionFileName(sessionKey string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, sessionKey)
+}
+
+// ReadAuditTail returns the last n entries (oldest first). With an empty
+// sessionKey, entries from every session file are merged by time.
+func ReadAuditTail(dir, sessionKey string, n int) ([]AuditEntry, error) {
+	var paths []string
+	if strings.TrimSpace(sessionKey) != "" {
+		paths = []string{AuditLogPath(dir, sessionKey)}
+	} else {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+		if err != nil {
+			return nil, err
+		}
+		paths = matches
+	}
+
+	var entries []AuditEntry
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			var e AuditEntry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				continue // skip partially written lines
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryRunAppendsAuditLine(t *testing.T) {
+	dir := t.TempDir()
+	reg := NewRegistry()
+	reg.Register(NewDescribeToolsTool(reg))
+	reg.SetAuditLog(NewAuditLog(dir, nil))
+
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42"})
+	reg.Run(ctx, "describe_tools", json.RawMessage(`{"names":["describe_tools"]}`))
+
+	data, err := os.ReadFile(AuditLogPath(dir, "telegram:42"))
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1:\n%s", len(lines), data)
+	}
+	var e AuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatalf("audit line is not valid JSON: %v\n%s", err, lines[0])
+	}
+	if e.Tool != "describe_tools" || e.SessionKey != "telegram:42" || !e.OK {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Time.IsZero() {
+		t.Error("entry has no timestamp")
+	}
+	if string(e.Args) != `{"names":["describe_tools"]}` {
+		t.Errorf("args = %s", e.Args)
+	}
+	if !strings.Contains(e.Result, "describe_tools") {
+		t.Errorf("result excerpt missing: %q", e.Result)
+	}
+
+	// A second call appends rather than overwrites.
+	reg.Run(ctx, "describe_tools", json.RawMessage(`{}`))
+	entries, err := ReadAuditTail(dir, "telegram:42", 0)
+	if err != nil {
+		t.Fatalf("ReadAuditTail: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2", len(entries))
+	}
+}
+
+func TestAuditLogRedactsSensitiveTools(t *testing.T) {
+	dir := t.TempDir()
+	a := NewAuditLog(dir, []string{"exec"})
+	a.Record("cli", "exec", json.RawMessage(`{"command":"export TOKEN=secret"}`), "ok", time.Now(), 0, true)
+
+	entries, err := ReadAuditTail(dir, "cli", 0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ReadAuditTail = %v, %v", entries, err)
+	}
+	e := entries[0]
+	if !e.Redacted || strings.Contains(string(e.Args), "secret") {
+		t.Errorf("args not redacted: %s", e.Args)
+	}
+	if !strings.Contains(string(e.Args), "command") {
+		t.Errorf("redacted args should keep argument names: %s", e.Args)
+	}
+}
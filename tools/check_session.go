@@ -22,7 +22,7 @@ type ToolCallRecord = msg.ToolCallRecord
 // optional — population depends on whether the session and/or thread exist.
 type SessionStatusInfo struct {
 	SessionKey       string     `json:"session_key"`
-	Exists           bool       `json:"exists"`                       // session.jsonl exists on disk
+	Exists           bool       `json:"exists"`                       // session is persisted in the session store
 	SessionDir       string     `json:"session_dir,omitempty"`
 	Agent            string     `json:"agent,omitempty"`              // from meta.json
 	MessageCount     int        `json:"message_count,omitempty"`
@@ -77,31 +77,36 @@ type checkSessionArgs struct {
 	SessionKey string `json:"session_key" required:"true"`
 }
 
-// Run executes the tool.
-func (t *CheckSessionTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "check_session", threadToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *CheckSessionTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "check_session", threadToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *CheckSessionTool) run(_ context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *CheckSessionTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *CheckSessionTool) run(_ context.Context, args json.RawMessage) ToolResult {
 	var a checkSessionArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 	if t.checker == nil {
-		return toolError("check_session", "session checker not configured")
+		return ErrorResult("check_session", "session checker not configured")
 	}
 
 	key := strings.TrimSpace(a.SessionKey)
 	if key == "" {
-		return toolError("check_session", "session_key is required")
+		return ErrorResult("check_session", "session_key is required")
 	}
 
 	info := t.checker.SessionStatus(key)
 
 	if !info.Exists && !info.ThreadActive {
-		return toolResult("check_session", map[string]any{
+		return okResult("check_session", map[string]any{
 			"session_key": key,
 			"exists":      false,
 			"thread_active": false,
@@ -161,5 +166,5 @@ func (t *CheckSessionTool) run(_ context.Context, args json.RawMessage) string {
 			"(via dispatch with to=session, or any other wake source)."
 	}
 
-	return toolResult("check_session", fields, hint)
+	return okResult("check_session", fields, hint)
 }
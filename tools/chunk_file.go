@@ -0,0 +1,263 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+const (
+	chunkFileMaxBytes      = 20 << 20
+	chunkFileDefaultLines  = 200
+	chunkFileDefaultTokens = 4000
+	chunkFileListLimit     = 200 // chunk boundaries listed in the result
+)
+
+// chunkRange is a half-open [Start, End) range of 0-based line indexes.
+type chunkRange struct {
+	Start, End int
+}
+
+// ChunkFileTool splits a large workspace file into overlapping line-aligned
+// chunks so the agent can process it map-reduce style.
+type ChunkFileTool struct {
+	workspace           string
+	restrictToWorkspace bool
+}
+
+// NewChunkFileTool creates a chunk_file tool rooted at workspace.
+func NewChunkFileTool(workspace string, restrictToWorkspace bool) *ChunkFileTool {
+	return &ChunkFileTool{workspace: workspace, restrictToWorkspace: restrictToWorkspace}
+}
+
+// Def returns the tool definition.
+func (t *ChunkFileTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "chunk_file",
+			Description: "Split a file too large for one read into overlapping chunks, by lines or by estimated tokens. " +
+				"Returns each chunk's line range (read it with read_file offset/limit), or with write=true saves the chunks to .tmp/chunks/<name>-<hash>/chunk-NNN.txt (the result lists the directory). " +
+				"Process chunks one at a time, save each partial result as result-NNN.md in that directory, then read the results back and combine them; " +
+				"lines inside an overlap appear in two chunks, so de-duplicate when merging.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "The file to split.",
+					},
+					"by": map[string]any{
+						"type":        "string",
+						"enum":        []string{"lines", "tokens"},
+						"description": "Chunk unit. Default lines. Token chunks still break on line boundaries.",
+					},
+					"size": map[string]any{
+						"type":        "integer",
+						"description": "Chunk size in the chosen unit. Default 200 lines or 4000 tokens.",
+					},
+					"overlap": map[string]any{
+						"type":        "integer",
+						"description": "How much of the previous chunk to repeat at the start of the next, in the chosen unit. Default 0; must be less than size.",
+					},
+					"write": map[string]any{
+						"type":        "boolean",
+						"description": "Write the chunks to .tmp/chunks/<name>-<hash>/ instead of only returning their boundaries.",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
+	}
+}
+
+type chunkFileArgs struct {
+	Path    string `json:"path" required:"true" alias:"file,file_path"`
+	By      string `json:"by,omitempty" alias:"unit,mode"`
+	Size    int    `json:"size,omitempty" alias:"chunk_size"`
+	Overlap int    `json:"overlap,omitempty"`
+	Write   bool   `json:"write,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *ChunkFileTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "chunk_file", chunkFileTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *ChunkFileTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *ChunkFileTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a chunkFileArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	by := strings.ToLower(strings.TrimSpace(a.By))
+	if by == "" {
+		by = "lines"
+	}
+	size := a.Size
+	switch by {
+	case "lines":
+		if size <= 0 {
+			size = chunkFileDefaultLines
+		}
+	case "tokens":
+		if size <= 0 {
+			size = chunkFileDefaultTokens
+		}
+	default:
+		return ErrorResult("chunk_file", fmt.Sprintf("by must be \"lines\" or \"tokens\", got %q", a.By))
+	}
+	if a.Overlap < 0 || a.Overlap >= size {
+		return ErrorResult("chunk_file", fmt.Sprintf("overlap must be between 0 and size-1 (%d)", size-1))
+	}
+
+	path := resolveToolPath(a.Path, t.workspace)
+	resolvedPath := absOrOriginal(path)
+	if errMsg := workspaceEscapeError("chunk_file", resolvedPath, t.workspace, t.restrictToWorkspace); errMsg != "" {
+		return errorText(errMsg)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ErrorResult("chunk_file", fmt.Sprintf("cannot access %s: %v", resolvedPath, err))
+	}
+	if !info.Mode().IsRegular() {
+		return ErrorResult("chunk_file", fmt.Sprintf("%s is not a regular file", resolvedPath))
+	}
+	if info.Size() > chunkFileMaxBytes {
+		return ErrorResult("chunk_file", fmt.Sprintf("%s is %d bytes; chunk_file handles files up to %d MB", resolvedPath, info.Size(), chunkFileMaxBytes>>20))
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ErrorResult("chunk_file", fmt.Sprintf("failed to read %s: %v", resolvedPath, err))
+	}
+	if len(content) == 0 {
+		return ErrorResult("chunk_file", fmt.Sprintf("file exists but is empty: %s", resolvedPath))
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	weights := make([]int, len(lines))
+	for i, line := range lines {
+		if by == "tokens" {
+			// +1 for the newline the split removed.
+			weights[i] = provider.EstimateTextTokens(line) + 1
+		} else {
+			weights[i] = 1
+		}
+	}
+	chunks := chunkLineRanges(weights, size, a.Overlap)
+
+	fields := map[string]any{
+		"path":    resolvedPath,
+		"by":      by,
+		"size":    size,
+		"overlap": a.Overlap,
+		"lines":   len(lines),
+		"chunks":  len(chunks),
+	}
+
+	var outDir string
+	var sb strings.Builder
+	if a.Write {
+		if t.workspace == "" {
+			return ErrorResult("chunk_file", "workspace not configured; cannot write chunks")
+		}
+		outDir = filepath.Join(t.workspace, ".tmp", "chunks", chunkDirName(resolvedPath))
+		fields["dir"] = outDir
+		if RuntimeContextFrom(ctx).DryRun {
+			fields["dry_run"] = true
+			fmt.Fprintf(&sb, "Dry run: would write %d chunk files to %s. Nothing was written.\n\n", len(chunks), outDir)
+		} else if err := writeChunks(outDir, lines, chunks); err != nil {
+			return ErrorResult("chunk_file", err.Error())
+		}
+	}
+
+	for i, c := range chunks {
+		if i == chunkFileListLimit {
+			fmt.Fprintf(&sb, "... %d more chunks\n", len(chunks)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "chunk %d: lines %d-%d (offset=%d limit=%d)", i+1, c.Start+1, c.End, c.Start+1, c.End-c.Start)
+		if outDir != "" {
+			fmt.Fprintf(&sb, " -> %s", filepath.Join(outDir, chunkFileName(i)))
+		}
+		sb.WriteString("\n")
+	}
+	return okResult("chunk_file", fields, sb.String())
+}
+
+// chunkLineRanges groups lines into consecutive chunks whose summed weight
+// stays within size (a single heavier line still forms its own chunk). Each
+// chunk after the first starts with trailing lines of the previous one
+// totalling at most overlap, and always advances by at least one line.
+func chunkLineRanges(weights []int, size, overlap int) []chunkRange {
+	var chunks []chunkRange
+	start := 0
+	for start < len(weights) {
+		end, total := start, 0
+		for end < len(weights) && (end == start || total+weights[end] <= size) {
+			total += weights[end]
+			end++
+		}
+		chunks = append(chunks, chunkRange{Start: start, End: end})
+		if end >= len(weights) {
+			break
+		}
+		next, covered := end, 0
+		for next > start+1 && covered+weights[next-1] <= overlap {
+			covered += weights[next-1]
+			next--
+		}
+		start = next
+	}
+	return chunks
+}
+
+// writeChunks replaces any chunk files left in dir by an earlier split;
+// result files written alongside them are kept.
+func writeChunks(dir string, lines []string, chunks []chunkRange) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create %s: %v", dir, err)
+	}
+	stale, _ := filepath.Glob(filepath.Join(dir, "chunk-*.txt"))
+	for _, f := range stale {
+		_ = os.Remove(f)
+	}
+	for i, c := range chunks {
+		data := strings.Join(lines[c.Start:c.End], "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(dir, chunkFileName(i)), []byte(data), 0644); err != nil {
+			return fmt.Errorf("write chunk %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func chunkFileName(i int) string {
+	return fmt.Sprintf("chunk-%03d.txt", i+1)
+}
+
+// chunkDirName derives the .tmp/chunks subdirectory name from the file name
+// plus a short hash of its resolved path, so same-named files in different
+// directories do not overwrite each other's chunks.
+func chunkDirName(path string) string {
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	if name == "" {
+		name = base
+	}
+	sum := sha256.Sum256([]byte(path))
+	return name + "-" + hex.EncodeToString(sum[:4])
+}
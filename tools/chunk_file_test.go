@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChunkLineRangesWithOverlap(t *testing.T) {
+	weights := make([]int, 250)
+	for i := range weights {
+		weights[i] = 1
+	}
+	got := chunkLineRanges(weights, 100, 20)
+	want := []chunkRange{{0, 100}, {80, 180}, {160, 250}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunks = %v, want %v", got, want)
+	}
+
+	// An overlap that would stall still advances by one line.
+	if got := chunkLineRanges([]int{1, 1, 1}, 1, 0); len(got) != 3 {
+		t.Fatalf("size 1 chunks = %v", got)
+	}
+	// A line heavier than size forms its own chunk.
+	if got, want := chunkLineRanges([]int{2, 9, 2, 2}, 5, 2), []chunkRange{{0, 1}, {1, 2}, {2, 4}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("weighted chunks = %v, want %v", got, want)
+	}
+}
+
+func TestChunkFileToolWritesChunks(t *testing.T) {
+	workspace := t.TempDir()
+	var sb strings.Builder
+	for i := 1; i <= 25; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "notes.txt"), []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewChunkFileTool(workspace, true)
+	args, _ := json.Marshal(map[string]any{"path": "notes.txt", "size": 10, "overlap": 2, "write": true})
+	out := tool.Run(context.Background(), args)
+	for _, want := range []string{"chunks: 3", "chunk 1: lines 1-10", "chunk 2: lines 9-18", "chunk 3: lines 17-25"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("result missing %q:\n%s", want, out)
+		}
+	}
+	dir := filepath.Join(workspace, ".tmp", "chunks", chunkDirName(filepath.Join(workspace, "notes.txt")))
+	data, err := os.ReadFile(filepath.Join(dir, "chunk-002.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "line 9\n") || !strings.HasSuffix(string(data), "line 18\n") {
+		t.Fatalf("chunk-002.txt = %q", data)
+	}
+
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args, _ = json.Marshal(map[string]any{"path": outside})
+	if out := tool.Run(context.Background(), args); !strings.Contains(out, "outside workspace") {
+		t.Fatalf("expected workspace rejection, got:\n%s", out)
+	}
+}
+
+func TestChunkFileToolDryRunWritesNothing(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "notes.txt"), []byte(strings.Repeat("line\n", 25)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewChunkFileTool(workspace, true)
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{DryRun: true})
+	args, _ := json.Marshal(map[string]any{"path": "notes.txt", "size": 10, "write": true})
+	out := tool.Run(ctx, args)
+	if !strings.Contains(out, "dry_run: true") || !strings.Contains(out, "Nothing was written") {
+		t.Fatalf("expected dry-run result, got:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, ".tmp")); !os.IsNotExist(err) {
+		t.Fatalf("dry run created .tmp: %v", err)
+	}
+}
+
+func TestChunkDirNameDistinguishesSameNamedFiles(t *testing.T) {
+	a := chunkDirName("/work/a/notes.txt")
+	b := chunkDirName("/work/b/notes.txt")
+	if a == b {
+		t.Fatalf("both files map to %q", a)
+	}
+	if !strings.HasPrefix(a, "notes-") || !strings.HasPrefix(b, "notes-") {
+		t.Fatalf("dir names %q, %q should keep the file stem", a, b)
+	}
+}
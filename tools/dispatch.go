@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/linanwx/nagobot/provider"
 	"github.com/linanwx/nagobot/thread/msg"
@@ -31,11 +32,12 @@ const (
 
 // DispatchSend is a single dispatch entry. Field requirements vary by To.
 type DispatchSend struct {
-	To         DispatchTarget `json:"to"`
-	Body       string         `json:"body"`
-	Agent      string         `json:"agent,omitempty"`       // subagent/fork
-	TaskID     string         `json:"task_id,omitempty"`     // subagent/fork
-	SessionKey string         `json:"session_key,omitempty"` // session
+	To             DispatchTarget `json:"to"`
+	Body           string         `json:"body"`
+	Agent          string         `json:"agent,omitempty"`           // subagent/fork
+	TaskID         string         `json:"task_id,omitempty"`         // subagent/fork
+	SessionKey     string         `json:"session_key,omitempty"`     // session
+	TimeoutSeconds int            `json:"timeout_seconds,omitempty"` // subagent
 }
 
 // DispatchHost abstracts the thread-side operations dispatch needs.
@@ -58,7 +60,9 @@ type DispatchHost interface {
 	SessionExists(key string) bool
 	SendToCaller(ctx context.Context, body string) error
 	SendToUser(ctx context.Context, body string) error
-	CreateOrWakeSubagent(ctx context.Context, agent, taskID, body string) (sessionKey, note string, err error)
+	// CreateOrWakeSubagent spawns or wakes a subagent thread. timeout bounds
+	// the woken turn; zero uses the configured subagent default.
+	CreateOrWakeSubagent(ctx context.Context, agent, taskID, body string, timeout time.Duration) (sessionKey, note string, err error)
 	CreateOrWakeFork(ctx context.Context, agent, taskID, body string) (sessionKey, note string, err error)
 	WakeSession(ctx context.Context, sessionKey, body string) error
 	SignalHalt()
@@ -85,7 +89,7 @@ func (t *DispatchTool) Def() provider.ToolDef {
 				"- caller:user — reply to whoever woke THIS turn AND assert the caller is the channel user (user-channel wake: telegram/discord/cli/web/feishu/wecom). Fails validation if the actual caller is another session or a system source.\n" +
 				"- caller:session — reply to the caller AND assert the caller is another session (cross-session wake; `caller_session_key` is present in wake YAML). Fails validation if the actual caller is the channel user or system.\n" +
 				"- user: reply to the channel user via this session's user-channel sink. Only valid for user-facing sessions. Use this when a non-user source (cron/heartbeat/another session) woke you and you want to proactively message YOUR user INSTEAD OF replying to the waker.\n" +
-				"- subagent: spawn a new subagent thread, or wake existing at same task_id. Fields: agent (optional), task_id, body.\n" +
+				"- subagent: spawn a new subagent thread, or wake existing at same task_id. Fields: agent (optional), task_id, body, timeout_seconds (optional).\n" +
 				"- fork: branch current session as new agent thread, or wake existing at same task_id. Fields: agent (optional), task_id, body.\n" +
 				"- session: wake an existing session. Fields: session_key, body. The target receives the body and its own dispatch(to=caller:session) routes back to YOUR session (ping-pong recurses until one side halts).\n\n" +
 				"Which caller form to pick: read `caller_session_key` in the wake YAML frontmatter. Present → to=caller:session; absent AND this session is user-facing → to=caller:user; system sources (cron/heartbeat/compression) have no usable caller form, use dispatch({}) or to=user instead. " +
@@ -123,6 +127,10 @@ func (t *DispatchTool) Def() provider.ToolDef {
 									"type":        "string",
 									"description": "Existing session key for to=session.",
 								},
+								"timeout_seconds": map[string]any{
+									"type":        "integer",
+									"description": "Deadline for the subagent's turn, in seconds. Optional — omitted uses the configured subagent timeout. On expiry the subagent's turn fails and the error is reported back to you.",
+								},
 							},
 							"required": []string{"to", "body"},
 						},
@@ -173,20 +181,25 @@ type DispatchError struct {
 	Detail string `json:"detail"`
 }
 
-// Run executes the tool.
-func (t *DispatchTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "dispatch", threadToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *DispatchTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "dispatch", threadToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *DispatchTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *DispatchTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *DispatchTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a dispatchArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 	if t.host == nil {
-		return toolError("dispatch", "host not configured")
+		return ErrorResult("dispatch", "host not configured")
 	}
 
 	// Reject the content+dispatch combo. When the model emits text in the
@@ -203,7 +216,7 @@ func (t *DispatchTool) run(ctx context.Context, args json.RawMessage) string {
 			preview = string(runes[:previewMaxRunes]) + "..."
 		}
 		preview = strings.ReplaceAll(preview, "\n", " ")
-		return toolResult("dispatch", map[string]any{
+		return okResult("dispatch", map[string]any{
 			"outcome": "validation-error",
 		}, "Validation failed — no sends were executed. Fix and re-call dispatch; the turn continues.\n\n"+
 			"Reason: this turn produced non-empty assistant content alongside the dispatch call. dispatch only delivers each send's `body` field — content emitted in the assistant message itself has no defined recipient and will not be delivered as you might expect.\n\n"+
@@ -217,7 +230,7 @@ func (t *DispatchTool) run(ctx context.Context, args json.RawMessage) string {
 	// Empty sends → silent turn termination.
 	if len(a.Sends) == 0 {
 		t.host.SignalHalt()
-		return toolResult("dispatch", map[string]any{
+		return okResult("dispatch", map[string]any{
 			"outcome": "turn-terminated-silent",
 		}, "Turn terminated silently. No delivery; history recorded.")
 	}
@@ -285,6 +298,12 @@ func (t *DispatchTool) validateOne(send DispatchSend, currentSession string) str
 	if strings.TrimSpace(send.Body) == "" {
 		return "body is required"
 	}
+	if send.TimeoutSeconds != 0 && send.To != TargetSubagent {
+		return "timeout_seconds is only valid for to=subagent"
+	}
+	if send.TimeoutSeconds < 0 {
+		return "timeout_seconds must be positive"
+	}
 	switch send.To {
 	case TargetCallerUser:
 		if send.Agent != "" || send.TaskID != "" || send.SessionKey != "" {
@@ -391,7 +410,7 @@ func (t *DispatchTool) execute(ctx context.Context, send DispatchSend) (Executed
 		}
 		return ExecutedItem{To: TargetUser, SessionKey: t.host.CurrentSessionKey()}, nil
 	case TargetSubagent:
-		key, note, err := t.host.CreateOrWakeSubagent(ctx, send.Agent, send.TaskID, send.Body)
+		key, note, err := t.host.CreateOrWakeSubagent(ctx, send.Agent, send.TaskID, send.Body, time.Duration(send.TimeoutSeconds)*time.Second)
 		if err != nil {
 			return ExecutedItem{}, err
 		}
@@ -465,7 +484,7 @@ const noUserReminder = "Reminder: this dispatch had no to=user entry. Any reply
 
 const callerUserRedundantHint = "Hint: this turn was woken by the channel user (caller:user). For a user-facing reply, prefer ending the turn with a plain assistant message — its content is auto-delivered to the channel user, so an explicit dispatch to user/caller:user is redundant and may double-deliver if you also produced text earlier in the turn."
 
-func buildDispatchErrorResult(errs []DispatchError) string {
+func buildDispatchErrorResult(errs []DispatchError) ToolResult {
 	var sb strings.Builder
 	sb.WriteString("Validation failed — no sends were executed. Fix and re-call dispatch; the turn continues.\n\nErrors:\n")
 	for _, e := range errs {
@@ -475,12 +494,12 @@ func buildDispatchErrorResult(errs []DispatchError) string {
 			fmt.Fprintf(&sb, "  - send #%d: %s\n", e.Index, e.Detail)
 		}
 	}
-	return toolResult("dispatch", map[string]any{
+	return errorText(toolResult("dispatch", map[string]any{
 		"outcome": "validation-error",
-	}, strings.TrimRight(sb.String(), "\n"))
+	}, strings.TrimRight(sb.String(), "\n")))
 }
 
-func buildDispatchSuccessResult(executed []ExecutedItem, isUserFacing bool, callerKind msg.CallerKind) string {
+func buildDispatchSuccessResult(executed []ExecutedItem, isUserFacing bool, callerKind msg.CallerKind) ToolResult {
 	var sb strings.Builder
 	if len(executed) == 1 {
 		sb.WriteString("Executed 1 send. Turn ended.\n\n")
@@ -497,12 +516,12 @@ func buildDispatchSuccessResult(executed []ExecutedItem, isUserFacing bool, call
 		sb.WriteString("\n")
 		sb.WriteString(noUserReminder)
 	}
-	return toolResult("dispatch", map[string]any{
+	return okResult("dispatch", map[string]any{
 		"outcome": "turn-terminated",
 	}, strings.TrimRight(sb.String(), "\n"))
 }
 
-func buildDispatchMixedResult(executed []ExecutedItem, errs []DispatchError, isUserFacing bool, callerKind msg.CallerKind) string {
+func buildDispatchMixedResult(executed []ExecutedItem, errs []DispatchError, isUserFacing bool, callerKind msg.CallerKind) ToolResult {
 	var sb strings.Builder
 	fmt.Fprintf(&sb, "Partial failure: %d send(s) executed, %d failed. Turn ended — executed deliveries cannot be unrolled.\n", len(executed), len(errs))
 	if len(executed) > 0 {
@@ -528,7 +547,7 @@ func buildDispatchMixedResult(executed []ExecutedItem, errs []DispatchError, isU
 		sb.WriteString("\n")
 		sb.WriteString(noUserReminder)
 	}
-	return toolResult("dispatch", map[string]any{
+	return errorText(toolResult("dispatch", map[string]any{
 		"outcome": "partial-failure",
-	}, strings.TrimRight(sb.String(), "\n"))
+	}, strings.TrimRight(sb.String(), "\n")))
 }
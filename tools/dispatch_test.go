@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/linanwx/nagobot/provider"
 	"github.com/linanwx/nagobot/thread/msg"
@@ -30,6 +31,7 @@ type mockDispatchHost struct {
 
 type subagentCall struct {
 	Agent, TaskID, Body string
+	Timeout             time.Duration
 }
 
 type wakeCall struct {
@@ -55,11 +57,11 @@ func (m *mockDispatchHost) SendToUser(_ context.Context, body string) error {
 	m.sentToUser = body
 	return nil
 }
-func (m *mockDispatchHost) CreateOrWakeSubagent(_ context.Context, agent, taskID, body string) (string, string, error) {
+func (m *mockDispatchHost) CreateOrWakeSubagent(_ context.Context, agent, taskID, body string, timeout time.Duration) (string, string, error) {
 	if m.failAgent != "" && agent == m.failAgent {
 		return "", "", fmt.Errorf("simulated failure")
 	}
-	m.subagentCalls = append(m.subagentCalls, subagentCall{agent, taskID, body})
+	m.subagentCalls = append(m.subagentCalls, subagentCall{agent, taskID, body, timeout})
 	key := m.currentKey + ":threads:" + taskID
 	note := "created"
 	if m.sessions[key] {
@@ -71,7 +73,7 @@ func (m *mockDispatchHost) CreateOrWakeFork(_ context.Context, agent, taskID, bo
 	if m.failAgent != "" && agent == m.failAgent {
 		return "", "", fmt.Errorf("simulated failure")
 	}
-	m.forkCalls = append(m.forkCalls, subagentCall{agent, taskID, body})
+	m.forkCalls = append(m.forkCalls, subagentCall{agent, taskID, body, 0})
 	key := m.currentKey + ":fork:" + taskID
 	note := "forked-from:" + m.currentKey
 	if m.sessions[key] {
@@ -446,6 +448,27 @@ func TestDispatch_SubagentBadTaskID(t *testing.T) {
 	}
 }
 
+func TestDispatch_SubagentTimeout(t *testing.T) {
+	host := &mockDispatchHost{currentKey: "cli", callerKind: "user"}
+	outcome, res := runDispatch(t, host,
+		`{"sends": [{"to": "subagent", "task_id": "slow", "body": "go", "timeout_seconds": 30}]}`)
+	if outcome != "turn-terminated" {
+		t.Fatalf("outcome=%q; %s", outcome, res)
+	}
+	if got := host.subagentCalls[0].Timeout; got != 30*time.Second {
+		t.Errorf("timeout = %v, want 30s", got)
+	}
+
+	for _, body := range []string{
+		`{"sends": [{"to": "subagent", "task_id": "slow", "body": "go", "timeout_seconds": -1}]}`,
+		`{"sends": [{"to": "fork", "task_id": "slow", "body": "go", "timeout_seconds": 30}]}`,
+	} {
+		if _, res := runDispatch(t, host, body); !strings.Contains(res, "validation-error") {
+			t.Errorf("expected validation-error for %s, got: %s", body, res)
+		}
+	}
+}
+
 func TestDispatch_Fork(t *testing.T) {
 	host := &mockDispatchHost{
 		currentKey: "telegram:1",
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// DraftReplyTool lets the agent hold its user-facing output during a long
+// turn and send the complete answer once.
+type DraftReplyTool struct{}
+
+// Def returns the tool definition.
+func (t *DraftReplyTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "draft_reply",
+			Description: "Hold your reply as a draft during a long, multi-step answer. action=start stops streaming " +
+				"and intermediate text from reaching the user; action=finalize sends the complete reply once " +
+				"(pass it as text, or omit text to send the text written since start) and ends your reply for this turn. " +
+				"If the turn ends without finalize, your last message is sent as usual.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"action": map[string]any{
+						"type":        "string",
+						"enum":        []string{"start", "finalize"},
+						"description": "start to begin drafting, finalize to send the complete reply.",
+					},
+					"text": map[string]any{
+						"type":        "string",
+						"description": "finalize only: the complete reply to send.",
+					},
+				},
+				"required": []string{"action"},
+			},
+		},
+	}
+}
+
+type draftReplyArgs struct {
+	Action string `json:"action" required:"true"`
+	Text   string `json:"text,omitempty" alias:"content,body"`
+}
+
+// RunResult executes the tool.
+func (t *DraftReplyTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a draftReplyArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	if rt.BeginDraft == nil || rt.FinalizeDraft == nil {
+		return ErrorResult("draft_reply", "this turn has no chat to reply to")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(a.Action)) {
+	case "start":
+		rt.BeginDraft()
+		return okResult("draft_reply", map[string]any{"action": "start"},
+			"Drafting. Your text is held until you call draft_reply with action=finalize.")
+	case "finalize":
+		if err := rt.FinalizeDraft(ctx, a.Text); err != nil {
+			return ErrorResult("draft_reply", fmt.Sprintf("finalize failed: %v", err))
+		}
+		return okResult("draft_reply", map[string]any{"action": "finalize"},
+			"Reply sent. Do not repeat it; end the turn with a brief confirmation or nothing.")
+	default:
+		return ErrorResult("draft_reply", fmt.Sprintf("unknown action %q (want start or finalize)", a.Action))
+	}
+}
+
+// Run implements Tool.
+func (t *DraftReplyTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultEmbeddingsBase = "https://api.openai.com/v1"
+	embeddingsHTTPTimeout = 60 * time.Second
+	embeddingsBatchSize   = 64
+)
+
+// Embedder turns texts into vectors for semantic recall.
+type Embedder interface {
+	// Model identifies the embedding model. Stored vectors are discarded
+	// when it changes, since vectors of different models are not comparable.
+	Model() string
+	Available() bool
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbeddingSettings configures an OpenAI-compatible embeddings endpoint.
+type EmbeddingSettings struct {
+	APIBase string // default https://api.openai.com/v1
+	APIKey  string
+	Model   string
+}
+
+// OpenAIEmbedder calls the /embeddings endpoint of OpenAI or any compatible
+// API (SiliconFlow, Ollama, vLLM, ...).
+type OpenAIEmbedder struct {
+	// SettingsFn returns the endpoint settings at call time (supports
+	// runtime config changes).
+	SettingsFn func() EmbeddingSettings
+}
+
+func (e *OpenAIEmbedder) settings() EmbeddingSettings {
+	if e == nil || e.SettingsFn == nil {
+		return EmbeddingSettings{}
+	}
+	s := e.SettingsFn()
+	s.APIBase = strings.TrimRight(strings.TrimSpace(s.APIBase), "/")
+	if s.APIBase == "" {
+		s.APIBase = defaultEmbeddingsBase
+	}
+	s.Model = strings.TrimSpace(s.Model)
+	return s
+}
+
+func (e *OpenAIEmbedder) Model() string   { return e.settings().Model }
+func (e *OpenAIEmbedder) Available() bool { return e.settings().Model != "" }
+
+// Embed returns one vector per text, in order.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	s := e.settings()
+	if s.Model == "" {
+		return nil, fmt.Errorf("embeddings model not configured (tools.embeddings.model)")
+	}
+	client := webHTTPClient(embeddingsHTTPTimeout)
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingsBatchSize {
+		batch := texts[start:min(start+embeddingsBatchSize, len(texts))]
+		vecs, err := e.embedBatch(ctx, client, s, batch)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (e *OpenAIEmbedder) embedBatch(ctx context.Context, client *http.Client, s EmbeddingSettings, texts []string) ([][]float32, error) {
+	payload, err := json.Marshal(map[string]any{"model": s.Model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.APIBase+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("embeddings API error: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	var parsed embeddingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings response: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings API returned %d vectors for %d inputs", len(parsed.Data), len(texts))
+	}
+	vecs := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings API returned out-of-range index %d", d.Index)
+		}
+		vecs[d.Index] = d.Embedding
+	}
+	return vecs, nil
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0
+// when their lengths differ or either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"regexp"
+	"time"
+)
+
+// execForceWindow is how long after a guard rejection force:true is accepted
+// for the same command in the same session.
+const execForceWindow = 10 * time.Minute
+
+// cmdStart anchors a pattern at a command position: the start of the script,
+// a new line or after a shell operator, optionally behind sudo. Argument
+// classes below stop at the same separators so a match stays in one command.
+const cmdStart = `(?:^|[|&;(\n]\s*)(?:sudo\s+)?`
+
+// destructivePatterns flag commands beyond rm that commonly destroy data.
+// The check is a heuristic: it errs toward asking, and the model can
+// confirm with the returned token or force:true.
+var destructivePatterns = []struct {
+	re     *regexp.Regexp
+	reason string
+}{
+	{regexp.MustCompile(cmdStart + `mkfs(?:\.\w+)?(?:\s|$)`), "mkfs formats a filesystem"},
+	{regexp.MustCompile(cmdStart + `dd\s[^|;&\n]*\bof=`), "dd of= overwrites its target"},
+	{regexp.MustCompile(cmdStart + `(?:shred|wipefs)(?:\s|$)`), "shred/wipefs destroy data irrecoverably"},
+	{regexp.MustCompile(cmdStart + `git\s+(?:-\S+\s+)*reset\s[^|;&\n]*--hard`), "git reset --hard discards uncommitted changes"},
+	{regexp.MustCompile(cmdStart + `git\s+(?:-\S+\s+)*clean\s[^|;&\n]*-[a-zA-Z]*f`), "git clean -f deletes untracked files"},
+	{regexp.MustCompile(cmdStart + `git\s+(?:-\S+\s+)*push\s[^|;&\n]*(?:--force\b|\s-f(?:\s|$))`), "git push --force rewrites remote history"},
+	{regexp.MustCompile(cmdStart + `find\s[^|;&\n]*\s-delete\b`), "find -delete removes every match"},
+	{regexp.MustCompile(`>\s*(?:/dev/(?:sd|hd|vd|xvd|nvme|disk|mmcblk)\S*|/(?:etc|boot|bin|sbin|usr|lib|lib64)(?:/\S*)?|(?:~|\$HOME)/\.\w\S*)`), "the redirect overwrites a system or dotfile path"},
+}
+
+// destructiveReason returns why cmd looks destructive, or "" when it does not
+// match any pattern. rm is handled separately by isRmCommand.
+func destructiveReason(cmd string) string {
+	for _, p := range destructivePatterns {
+		if p.re.MatchString(cmd) {
+			return p.reason
+		}
+	}
+	return ""
+}
+
+// markFlagged records that the guard rejected command in sessionKey, so a
+// retry with force:true is accepted within execForceWindow.
+func (t *ExecTool) markFlagged(sessionKey, command string) {
+	t.flagged.Store(sessionKey+"\x00"+command, time.Now())
+}
+
+// takeFlagged reports whether command was rejected in sessionKey within
+// execForceWindow, consuming the record.
+func (t *ExecTool) takeFlagged(sessionKey, command string) bool {
+	v, ok := t.flagged.LoadAndDelete(sessionKey + "\x00" + command)
+	if !ok {
+		return false
+	}
+	return time.Since(v.(time.Time)) <= execForceWindow
+}
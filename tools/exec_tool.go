@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/linanwx/nagobot/logger"
@@ -23,11 +25,13 @@ import (
 const (
 	execDefaultTimeoutSeconds = 60
 	execOutputMaxChars        = 50000
+	execSessionWorkDir        = "work" // per-session default workdirs live under workspace/work/
 )
 
-// rmPattern matches `rm` as a direct shell command at the start or after a
-// shell operator (|, &, ;). Single `|` and `&` also cover `||` and `&&`.
-var rmPattern = regexp.MustCompile(`(?:^|[|&;]\s*)rm(?:\s|$)`)
+// rmPattern matches `rm` as a direct shell command at the start, on a new
+// line or after a shell operator (|, &, ;). Single `|` and `&` also cover `||`
+// and `&&`.
+var rmPattern = regexp.MustCompile(`(?:^|[|&;\n]\s*)rm(?:\s|$)`)
 
 // subshellRmPattern matches `rm` inside arguments of known sub-shell executors
 // (python, osascript, bash, etc.) where quoted rm will actually be executed.
@@ -36,21 +40,40 @@ var subshellRmPattern = regexp.MustCompile(`(?:python[23]?|osascript|bash|sh|zsh
 // ExecTool executes shell commands.
 type ExecTool struct {
 	workspace           string
-	defaultTimeout      int
+	defaultTimeout      atomic.Int64 // seconds; <= 0 uses execDefaultTimeoutSeconds
 	restrictToWorkspace bool
 	hmacKey             []byte
+	confirmDestructive  atomic.Bool // guard mkfs, dd, git reset --hard, ... on non-admin turns
+	flagged             sync.Map    // session key + command → time the guard rejected it
 }
 
 // NewExecTool creates an ExecTool with a random HMAC key.
 func NewExecTool(workspace string, defaultTimeout int, restrictToWorkspace bool) *ExecTool {
 	key := make([]byte, 32)
 	_, _ = rand.Read(key)
-	return &ExecTool{
+	t := &ExecTool{
 		workspace:           workspace,
-		defaultTimeout:      defaultTimeout,
 		restrictToWorkspace: restrictToWorkspace,
 		hmacKey:             key,
 	}
+	t.defaultTimeout.Store(int64(defaultTimeout))
+	t.confirmDestructive.Store(true)
+	return t
+}
+
+// SelfTimed reports that exec bounds each call with its own timeout argument.
+func (t *ExecTool) SelfTimed() bool { return true }
+
+// SetDefaultTimeout changes the timeout, in seconds, used when a call does
+// not pass one. Safe to call while commands are running (config reload).
+func (t *ExecTool) SetDefaultTimeout(seconds int) {
+	t.defaultTimeout.Store(int64(seconds))
+}
+
+// SetConfirmDestructive turns the destructive-command guard for non-admin
+// turns on or off (tools.exec.confirmDestructive). The rm guard always applies.
+func (t *ExecTool) SetConfirmDestructive(on bool) {
+	t.confirmDestructive.Store(on)
 }
 
 // Def returns the tool definition.
@@ -69,7 +92,7 @@ func (t *ExecTool) Def() provider.ToolDef {
 					},
 					"workdir": map[string]any{
 						"type":        "string",
-						"description": "Optional working directory. If omitted, runs in the workspace root.",
+						"description": "Optional working directory. If omitted, runs in this session's own directory (<workspace>/work/<session>), so cd/git state does not collide with other sessions. Pass the workspace path explicitly to run there.",
 					},
 					"timeout": map[string]any{
 						"type":        "integer",
@@ -79,6 +102,10 @@ func (t *ExecTool) Def() provider.ToolDef {
 						"type":        "string",
 						"description": "Confirmation token returned by a previous call when a dangerous command was detected. Pass it back with the same command to confirm execution.",
 					},
+					"force": map[string]any{
+						"type":        "boolean",
+						"description": "Set to true to run a command the dangerous-command check just flagged, once you have made sure it is intended. Only accepted after the check has flagged that exact command.",
+					},
 				},
 				"required": []string{"command"},
 			},
@@ -92,6 +119,7 @@ type execArgs struct {
 	Workdir string `json:"workdir,omitempty"`
 	Timeout int    `json:"timeout,omitempty"`
 	Confirm string `json:"confirm,omitempty"`
+	Force   bool   `json:"force,omitempty"`
 }
 
 // computeHMAC returns a hex-encoded HMAC-SHA256 of the command.
@@ -110,46 +138,103 @@ func isRmCommand(cmd string) bool {
 	return rmPattern.MatchString(cmd) || subshellRmPattern.MatchString(cmd)
 }
 
-// Run executes the tool.
-func (t *ExecTool) Run(ctx context.Context, args json.RawMessage) string {
+// checkConfirmation gates a flagged command: it passes with the HMAC token
+// for the command, or with force:true when the same command was flagged in
+// this session within execForceWindow. Otherwise it returns an error built
+// from prompt that tells the model how to confirm.
+func (t *ExecTool) checkConfirmation(sessionKey string, a execArgs, prompt string) string {
+	if a.Confirm != "" {
+		if !hmac.Equal([]byte(a.Confirm), []byte(t.computeHMAC(a.Command))) {
+			return toolError("exec", "invalid confirmation token. The command may have been modified. Please retry without the confirm parameter.")
+		}
+		return ""
+	}
+	if a.Force && t.takeFlagged(sessionKey, a.Command) {
+		return ""
+	}
+	t.markFlagged(sessionKey, a.Command)
+	return toolError("exec", fmt.Sprintf("%s, re-call this tool with the same command and set force to true, or set confirm to: %s",
+		prompt, t.computeHMAC(a.Command)))
+}
+
+// defaultWorkdir returns the directory used when no workdir is given: a
+// per-session directory under workspace/work so parallel sessions don't share
+// shell state, or the workspace root when there is no session key.
+func (t *ExecTool) defaultWorkdir(sessionKey string) string {
+	if t.workspace == "" {
+		return ""
+	}
+	if sessionKey = strings.TrimSpace(sessionKey); sessionKey == "" {
+		return t.workspace
+	}
+	return filepath.Join(t.workspace, execSessionWorkDir, sessionFileName(sessionKey))
+}
+
+// RunResult executes the tool.
+func (t *ExecTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
 	var a execArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
+	}
+
+	if RuntimeContextFrom(ctx).DryRun {
+		workdir := t.defaultWorkdir(RuntimeContextFrom(ctx).SessionKey)
+		if a.Workdir != "" {
+			workdir = expandPath(a.Workdir)
+		}
+		return okResult("exec", map[string]any{
+			"workdir": workdir,
+			"dry_run": true,
+		}, fmt.Sprintf("Dry run: would execute in %s:\n\n%s\n\nNothing was run.", workdir, a.Command))
 	}
 
 	// Check for dangerous rm command.
+	rt := RuntimeContextFrom(ctx)
 	if isRmCommand(a.Command) {
-		if a.Confirm == "" {
-			return toolError("exec", fmt.Sprintf("Dangerous command detected: rm. "+
-				"Prefer using safer alternatives like `trash` or `gio trash` to move files to trash instead of permanent deletion. "+
-				"If you still need to use rm, re-call this tool with the same command and set confirm to: %s", t.computeHMAC(a.Command)))
+		if errMsg := t.checkConfirmation(rt.SessionKey, a, "Dangerous command detected: rm. "+
+			"Prefer using safer alternatives like `trash` or `gio trash` to move files to trash instead of permanent deletion. "+
+			"If you still need to use rm"); errMsg != "" {
+			return errorText(errMsg)
 		}
-		if !hmac.Equal([]byte(a.Confirm), []byte(t.computeHMAC(a.Command))) {
-			return toolError("exec", "invalid confirmation token. The command may have been modified. Please retry without the confirm parameter.")
+	} else if reason := destructiveReason(a.Command); reason != "" && !rt.Admin && t.confirmDestructive.Load() {
+		if errMsg := t.checkConfirmation(rt.SessionKey, a, fmt.Sprintf("Dangerous command detected: %s. "+
+			"Check that the target is right and that nothing unsaved will be lost. "+
+			"If the command is intended", reason)); errMsg != "" {
+			return errorText(errMsg)
 		}
 	}
 
 	timeout := a.Timeout
 	if timeout <= 0 {
-		if t.defaultTimeout > 0 {
-			timeout = t.defaultTimeout
+		if d := int(t.defaultTimeout.Load()); d > 0 {
+			timeout = d
 		} else {
 			timeout = execDefaultTimeoutSeconds
 		}
 	}
 
-	return withTimeout(ctx, "exec", time.Duration(timeout)*time.Second, func(ctx context.Context) string {
+	return withTimeout(ctx, "exec", time.Duration(timeout)*time.Second, func(ctx context.Context) ToolResult {
 		return t.run(ctx, a, timeout)
 	})
 }
 
-func (t *ExecTool) run(ctx context.Context, a execArgs, timeout int) string {
+// Run implements Tool.
+func (t *ExecTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *ExecTool) run(ctx context.Context, a execArgs, timeout int) ToolResult {
 	start := time.Now()
 	cmd := exec.CommandContext(ctx, "sh", "-c", a.Command)
 	if a.Workdir != "" {
 		cmd.Dir = expandPath(a.Workdir)
-	} else if t.workspace != "" {
-		cmd.Dir = t.workspace
+	} else if dir := t.defaultWorkdir(RuntimeContextFrom(ctx).SessionKey); dir != "" {
+		if dir != t.workspace {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return ErrorResult("exec", fmt.Sprintf("cannot create session workdir %q: %v", dir, err))
+			}
+		}
+		cmd.Dir = dir
 	}
 
 	if t.restrictToWorkspace && t.workspace != "" {
@@ -158,34 +243,34 @@ func (t *ExecTool) run(ctx context.Context, a execArgs, timeout int) string {
 			var err error
 			effectiveDir, err = os.Getwd()
 			if err != nil {
-				return fmt.Sprintf("Error: cannot determine working directory: %v", err)
+				return ErrorResult("exec", fmt.Sprintf("cannot determine working directory: %v", err))
 			}
 		}
 		absDir, err := filepath.Abs(effectiveDir)
 		if err != nil {
-			return fmt.Sprintf("Error: cannot resolve working directory %q: %v", effectiveDir, err)
+			return ErrorResult("exec", fmt.Sprintf("cannot resolve working directory %q: %v", effectiveDir, err))
 		}
 		absDir, err = filepath.EvalSymlinks(absDir)
 		if err != nil {
-			return fmt.Sprintf("Error: cannot resolve symlinks for %q: %v", absDir, err)
+			return ErrorResult("exec", fmt.Sprintf("cannot resolve symlinks for %q: %v", absDir, err))
 		}
 		absWorkspace, err := filepath.Abs(t.workspace)
 		if err != nil {
-			return fmt.Sprintf("Error: cannot resolve workspace %q: %v", t.workspace, err)
+			return ErrorResult("exec", fmt.Sprintf("cannot resolve workspace %q: %v", t.workspace, err))
 		}
 		absWorkspace, err = filepath.EvalSymlinks(absWorkspace)
 		if err != nil {
-			return fmt.Sprintf("Error: cannot resolve symlinks for workspace %q: %v", absWorkspace, err)
+			return ErrorResult("exec", fmt.Sprintf("cannot resolve symlinks for workspace %q: %v", absWorkspace, err))
 		}
 		sep := string(filepath.Separator)
 		if absDir != absWorkspace && !strings.HasPrefix(absDir+sep, absWorkspace+sep) {
-			return fmt.Sprintf("Error: working directory %q is outside workspace %q (restrictToWorkspace is enabled)", effectiveDir, t.workspace)
+			return ErrorResult("exec", fmt.Sprintf("working directory %q is outside workspace %q (restrictToWorkspace is enabled)", effectiveDir, t.workspace))
 		}
 	}
 
 	output, err := cmd.CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
-		return toolError("exec", fmt.Sprintf("command timed out after %d seconds\nPartial output:\n%s", timeout, string(output)))
+		return ErrorResult("exec", fmt.Sprintf("command timed out after %d seconds\nPartial output:\n%s", timeout, string(output)))
 	}
 
 	result := string(output)
@@ -219,5 +304,5 @@ func (t *ExecTool) run(ctx context.Context, a execArgs, timeout int) string {
 		fields["skip_trim"] = true
 	}
 
-	return toolResult("exec", fields, result)
+	return okResult("exec", fields, result)
 }
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -114,3 +116,109 @@ func TestOsascriptRmTriggered(t *testing.T) {
 		t.Fatalf("osascript with rm should trigger confirmation, got: %s", result)
 	}
 }
+
+func TestExecSessionsGetSeparateWorkdirs(t *testing.T) {
+	ws := t.TempDir()
+	tool := NewExecTool(ws, 5, true)
+	runIn := func(sessionKey, command string) string {
+		t.Helper()
+		ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: sessionKey})
+		b, _ := json.Marshal(execArgs{Command: command})
+		return tool.Run(ctx, b)
+	}
+
+	runIn("telegram:1", "echo one > notes.txt")
+	runIn("telegram:2", "echo two > notes.txt")
+
+	dirA := tool.defaultWorkdir("telegram:1")
+	dirB := tool.defaultWorkdir("telegram:2")
+	if dirA == dirB {
+		t.Fatalf("sessions share workdir %s", dirA)
+	}
+	for dir, want := range map[string]string{dirA: "one", dirB: "two"} {
+		data, err := os.ReadFile(filepath.Join(dir, "notes.txt"))
+		if err != nil {
+			t.Fatalf("read %s: %v", dir, err)
+		}
+		if strings.TrimSpace(string(data)) != want {
+			t.Errorf("%s/notes.txt = %q, want %q", dir, data, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(ws, "notes.txt")); !os.IsNotExist(err) {
+		t.Errorf("workspace root should be untouched, stat err = %v", err)
+	}
+	if out := runIn("", "pwd"); !strings.Contains(out, ws) || strings.Contains(out, execSessionWorkDir+string(filepath.Separator)) {
+		t.Errorf("no session key should run in the workspace root, got %q", out)
+	}
+}
+
+func TestDestructiveReason(t *testing.T) {
+	destructive := []string{
+		"mkfs.ext4 /dev/sdb1",
+		"sudo mkfs -t xfs /dev/nvme0n1",
+		"dd if=/dev/zero of=/dev/sda bs=1M",
+		"git reset --hard HEAD~3",
+		"cd repo && git reset --hard origin/main",
+		"git clean -fdx",
+		"git push --force origin main",
+		"find . -name '*.log' -delete",
+		"echo '' > /etc/hosts",
+		"cat key > ~/.ssh/authorized_keys",
+		"shred -u secrets.txt",
+		"cd repo\ngit reset --hard",
+		"echo formatting\nmkfs /dev/sda",
+		"set -e\n  sudo dd if=/dev/zero of=/dev/sdb",
+	}
+	for _, cmd := range destructive {
+		if destructiveReason(cmd) == "" {
+			t.Errorf("%q not classified as destructive", cmd)
+		}
+	}
+	safe := []string{
+		"ls -la",
+		"git status",
+		"git reset HEAD file.go",
+		"git push origin main",
+		"dd if=/dev/urandom bs=16 count=1",
+		"go test ./... > /dev/null 2>&1",
+		"echo hi > out.txt",
+		"find . -name '*.go'",
+		`echo "git reset --hard"`,
+		"grep mkfs notes.txt",
+		"git reset HEAD file.go\necho --hard",
+	}
+	for _, cmd := range safe {
+		if reason := destructiveReason(cmd); reason != "" {
+			t.Errorf("%q classified as destructive: %s", cmd, reason)
+		}
+	}
+}
+
+func TestDestructiveCommandGuard(t *testing.T) {
+	tool := newTestExecTool()
+	dir := t.TempDir() // not a git repository, so the command itself fails harmlessly
+	run := func(ctx context.Context, force bool) string {
+		b, _ := json.Marshal(execArgs{Command: "git reset --hard", Workdir: dir, Force: force})
+		return tool.Run(ctx, b)
+	}
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:1"})
+
+	if out := run(ctx, true); !strings.Contains(out, "Dangerous command detected") {
+		t.Fatalf("force before the guard flagged the command should be refused, got: %s", out)
+	}
+	if out := run(ctx, true); strings.Contains(out, "Dangerous command detected") {
+		t.Fatalf("force after the guard flagged the command should run it, got: %s", out)
+	}
+	if out := run(ctx, false); !strings.Contains(out, "Dangerous command detected") {
+		t.Fatalf("force is single-use, got: %s", out)
+	}
+
+	admin := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "cli", Admin: true})
+	if out := run(admin, false); strings.Contains(out, "Dangerous command detected") {
+		t.Errorf("admin turns should skip the guard, got: %s", out)
+	}
+	tool.SetConfirmDestructive(false)
+	if out := run(ctx, false); strings.Contains(out, "Dangerous command detected") {
+		t.Errorf("disabled guard still asked, got: %s", out)
+	}
+}
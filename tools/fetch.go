@@ -35,12 +35,12 @@ func (p *DirectFetchProvider) Available() bool         { return true }
 func (p *DirectFetchProvider) ReturnsMarkdown() bool   { return false }
 
 func (p *DirectFetchProvider) Fetch(ctx context.Context, rawURL string) (string, error) {
-	client := &http.Client{Timeout: webFetchHTTPTimeout}
+	client := webHTTPClient(webFetchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", webUserAgent(ctx, defaultWebUserAgent))
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 
 	resp, err := client.Do(req)
@@ -86,7 +86,7 @@ func (p *JinaFetchProvider) ReturnsMarkdown() bool { return true }
 func (p *JinaFetchProvider) Fetch(ctx context.Context, rawURL string) (string, error) {
 	jinaURL := "https://r.jina.ai/" + rawURL
 
-	client := &http.Client{Timeout: webFetchHTTPTimeout}
+	client := webHTTPClient(webFetchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "GET", jinaURL, nil)
 	if err != nil {
 		return "", err
@@ -18,8 +18,6 @@ type KimiFetchProvider struct {
 	ProviderTags []string
 }
 
-var kimiFetchClient = &http.Client{Timeout: webFetchHTTPTimeout}
-
 func (p *KimiFetchProvider) Name() string            { return "kimi" }
 func (p *KimiFetchProvider) Tags() []string          { return p.ProviderTags }
 func (p *KimiFetchProvider) Available() bool         { return p.KeyFn != nil && p.KeyFn() != "" }
@@ -46,7 +44,7 @@ func (p *KimiFetchProvider) Fetch(ctx context.Context, rawURL string) (string, e
 		req.Header.Set("Authorization", "Bearer "+key)
 	}
 
-	resp, err := kimiFetchClient.Do(req)
+	resp, err := webHTTPClient(webFetchHTTPTimeout).Do(req)
 	if err != nil {
 		return "", err
 	}
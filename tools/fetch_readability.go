@@ -22,12 +22,12 @@ func (p *ReadabilityFetchProvider) Available() bool         { return true }
 func (p *ReadabilityFetchProvider) ReturnsMarkdown() bool   { return true }
 
 func (p *ReadabilityFetchProvider) Fetch(ctx context.Context, rawURL string) (string, error) {
-	client := &http.Client{Timeout: webFetchHTTPTimeout}
+	client := webHTTPClient(webFetchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", webUserAgent(ctx, defaultWebUserAgent))
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 
 	resp, err := client.Do(req)
@@ -23,11 +23,21 @@ func formatResolvedPath(input, resolved string) string {
 	return fmt.Sprintf("%s (resolved: %s)", input, resolved)
 }
 
+// workspaceEscapeError returns a tool error when restrict is set and
+// resolvedPath escapes workspace (via ".." or a symlink), "" otherwise.
+func workspaceEscapeError(tool, resolvedPath, workspace string, restrict bool) string {
+	if !restrict || pathWithinWorkspace(resolvedPath, workspace) {
+		return ""
+	}
+	return toolError(tool, fmt.Sprintf("%s is outside workspace %q (restrictToWorkspace is enabled)", resolvedPath, workspace))
+}
+
 const readFileDefaultLimit = 2000
 
 // ReadFileTool reads the contents of a file with line-based pagination.
 type ReadFileTool struct {
-	workspace string
+	workspace           string
+	restrictToWorkspace bool
 }
 
 // Def returns the tool definition.
@@ -75,33 +85,41 @@ type readFileArgs struct {
 	Tail   int    `json:"tail,omitempty"`
 }
 
-// Run executes the tool.
-func (t *ReadFileTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "read_file", fileToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *ReadFileTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "read_file", fileToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *ReadFileTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *ReadFileTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *ReadFileTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a readFileArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	path := resolveToolPath(a.Path, t.workspace)
 	resolvedPath := absOrOriginal(path)
+	if errMsg := workspaceEscapeError("read_file", resolvedPath, t.workspace, t.restrictToWorkspace); errMsg != "" {
+		return errorText(errMsg)
+	}
 	logger.Debug("read_file resolved path", "inputPath", a.Path, "resolvedPath", resolvedPath)
 
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return toolError("read_file", fmt.Sprintf("file not found: %s", formatResolvedPath(a.Path, resolvedPath)))
+			return ErrorResult("read_file", fmt.Sprintf("file not found: %s", formatResolvedPath(a.Path, resolvedPath)))
 		}
-		return toolError("read_file", fmt.Sprintf("failed to stat file: %s: %v", formatResolvedPath(a.Path, resolvedPath), err))
+		return ErrorResult("read_file", fmt.Sprintf("failed to stat file: %s: %v", formatResolvedPath(a.Path, resolvedPath), err))
 	}
 
 	if info.IsDir() {
-		return toolError("read_file", fmt.Sprintf("path is a directory, not a file: %s", formatResolvedPath(a.Path, resolvedPath)))
+		return ErrorResult("read_file", fmt.Sprintf("path is a directory, not a file: %s", formatResolvedPath(a.Path, resolvedPath)))
 	}
 
 	// Detect file type and dispatch accordingly.
@@ -114,7 +132,7 @@ func (t *ReadFileTool) run(ctx context.Context, args json.RawMessage) string {
 	case FileTypePDF:
 		return t.handlePDF(ctx, resolvedPath, mimeType, info.Size())
 	case FileTypeBinary:
-		return toolError("read_file", fmt.Sprintf("binary file (%s), cannot read as text: %s", mimeType, resolvedPath))
+		return ErrorResult("read_file", fmt.Sprintf("binary file (%s), cannot read as text: %s", mimeType, resolvedPath))
 	default:
 		return t.handleText(a, path, resolvedPath)
 	}
@@ -122,71 +140,71 @@ func (t *ReadFileTool) run(ctx context.Context, args json.RawMessage) string {
 
 // handleImage returns image data for vision-capable models or delegation guidance.
 // absPath must be an absolute path (used for both display and media markers).
-func (t *ReadFileTool) handleImage(ctx context.Context, absPath, mimeType string, size int64) string {
+func (t *ReadFileTool) handleImage(ctx context.Context, absPath, mimeType string, size int64) ToolResult {
 	fields := map[string]any{"path": absPath, "type": mimeType, "size": size}
 	rt := RuntimeContextFrom(ctx)
 	if !rt.SupportsVision {
 		if !rt.ImageReaderConfigured {
-			return toolResult("read_file", fields,
+			return okResult("read_file", fields,
 				"This is an image file. Your current model does not support vision, "+
 					"and the 'imagereader' agent is not configured. "+
 					"To enable image reading, configure a vision-capable model or set up an imagereader agent.")
 		}
-		return toolResult("read_file", fields,
+		return okResult("read_file", fields,
 			"This is an image file. You cannot view images directly. "+
 				"Use dispatch with to=subagent, agent='imagereader', and pass the original user message as the body. "+
 				"Pick a descriptive task_id (e.g. 'read-image-<short-name>').")
 	}
-	return toolResult("read_file", fields, fmt.Sprintf("<<media:%s:%s>>", mimeType, absPath))
+	return okResult("read_file", fields, fmt.Sprintf("<<media:%s:%s>>", mimeType, absPath))
 }
 
 // handleAudio returns audio data for audio-capable models or delegation guidance.
-func (t *ReadFileTool) handleAudio(ctx context.Context, absPath, mimeType string, size int64) string {
+func (t *ReadFileTool) handleAudio(ctx context.Context, absPath, mimeType string, size int64) ToolResult {
 	fields := map[string]any{"path": absPath, "type": mimeType, "size": size}
 	rt := RuntimeContextFrom(ctx)
 	if !rt.SupportsAudio {
 		if !rt.AudioReaderConfigured {
-			return toolResult("read_file", fields,
+			return okResult("read_file", fields,
 				"This is an audio file. Your current model does not support audio, "+
 					"and the 'audioreader' agent is not configured. "+
 					"To enable audio reading, configure an audio-capable model or set up an audioreader agent.")
 		}
-		return toolResult("read_file", fields,
+		return okResult("read_file", fields,
 			"This is an audio file. You cannot listen to audio directly. "+
 				"Use dispatch with to=subagent, agent='audioreader', and pass the audio file path as the body. "+
 				"Pick a descriptive task_id (e.g. 'read-audio-<short-name>').")
 	}
-	return toolResult("read_file", fields, fmt.Sprintf("<<media:%s:%s>>", mimeType, absPath))
+	return okResult("read_file", fields, fmt.Sprintf("<<media:%s:%s>>", mimeType, absPath))
 }
 
 // handlePDF returns PDF data for PDF-capable models or delegation guidance.
-func (t *ReadFileTool) handlePDF(ctx context.Context, absPath, mimeType string, size int64) string {
+func (t *ReadFileTool) handlePDF(ctx context.Context, absPath, mimeType string, size int64) ToolResult {
 	fields := map[string]any{"path": absPath, "type": mimeType, "size": size}
 	rt := RuntimeContextFrom(ctx)
 	if !rt.SupportsPDF {
 		if !rt.PDFReaderConfigured {
-			return toolResult("read_file", fields,
+			return okResult("read_file", fields,
 				"This is a PDF document. Your current model does not support PDF input, "+
 					"and the 'pdfreader' agent is not configured. "+
 					"To enable PDF reading, configure a PDF-capable model or set up a pdfreader agent.")
 		}
-		return toolResult("read_file", fields,
+		return okResult("read_file", fields,
 			"This is a PDF document. You cannot read PDFs directly. "+
 				"Use dispatch with to=subagent, agent='pdfreader', and pass the original user message as the body. "+
 				"Pick a descriptive task_id (e.g. 'read-pdf-<short-name>').")
 	}
-	return toolResult("read_file", fields, fmt.Sprintf("<<media:%s:%s>>", mimeType, absPath))
+	return okResult("read_file", fields, fmt.Sprintf("<<media:%s:%s>>", mimeType, absPath))
 }
 
 // handleText reads a text file with line-based pagination.
 // filePath is the workspace-resolved path for reading; absPath is the absolute path for display.
-func (t *ReadFileTool) handleText(a readFileArgs, filePath, absPath string) string {
+func (t *ReadFileTool) handleText(a readFileArgs, filePath, absPath string) ToolResult {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return toolError("read_file", fmt.Sprintf("failed to read file: %s: %v", formatResolvedPath(a.Path, absPath), err))
+		return ErrorResult("read_file", fmt.Sprintf("failed to read file: %s: %v", formatResolvedPath(a.Path, absPath), err))
 	}
 	if len(content) == 0 {
-		return toolError("read_file", fmt.Sprintf("file exists but is empty: %s", absPath))
+		return ErrorResult("read_file", fmt.Sprintf("file exists but is empty: %s", absPath))
 	}
 
 	allLines := strings.Split(string(content), "\n")
@@ -212,7 +230,7 @@ func (t *ReadFileTool) handleText(a readFileArgs, filePath, absPath string) stri
 
 		startIdx = offset - 1
 		if startIdx >= totalLines {
-			return toolError("read_file", fmt.Sprintf("offset %d is beyond end of file (%d lines)", offset, totalLines))
+			return ErrorResult("read_file", fmt.Sprintf("offset %d is beyond end of file (%d lines)", offset, totalLines))
 		}
 		endIdx = startIdx + limit
 		if endIdx > totalLines {
@@ -234,12 +252,13 @@ func (t *ReadFileTool) handleText(a readFileArgs, filePath, absPath string) stri
 		fmt.Fprintf(&sb, "%d\t%s\n", i+1, allLines[i])
 	}
 
-	return toolResult("read_file", fields, sb.String())
+	return okResult("read_file", fields, sb.String())
 }
 
 // WriteFileTool writes content to a file.
 type WriteFileTool struct {
-	workspace string
+	workspace           string
+	restrictToWorkspace bool
 }
 
 // Def returns the tool definition.
@@ -273,49 +292,84 @@ type writeFileArgs struct {
 	Content string `json:"content"`
 }
 
-// Run executes the tool.
-func (t *WriteFileTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "write_file", fileToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *WriteFileTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "write_file", fileToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *WriteFileTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *WriteFileTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *WriteFileTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a writeFileArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	path := resolveToolPath(a.Path, t.workspace)
 	resolvedPath := absOrOriginal(path)
+	if errMsg := workspaceEscapeError("write_file", resolvedPath, t.workspace, t.restrictToWorkspace); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	if RuntimeContextFrom(ctx).DryRun {
+		action := "create"
+		if _, err := os.Stat(path); err == nil {
+			action = "overwrite"
+		}
+		return okResult("write_file", map[string]any{
+			"path":    resolvedPath,
+			"bytes":   len(a.Content),
+			"dry_run": true,
+		}, fmt.Sprintf("Dry run: would %s %s (%d bytes, %d lines). Nothing was written.",
+			action, resolvedPath, len(a.Content), countLines(a.Content)))
+	}
 
 	// Create parent directories
 	dir := filepath.Dir(path)
 	resolvedDir := absOrOriginal(dir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return toolError("write_file", fmt.Sprintf("failed to create parent directory: %s: %v", formatResolvedPath(dir, resolvedDir), err))
+		return ErrorResult("write_file", fmt.Sprintf("failed to create parent directory: %s: %v", formatResolvedPath(dir, resolvedDir), err))
 	}
 
 	// Bail out if the timeout already fired to avoid writing after the caller
 	// received a timeout error.
 	if ctx.Err() != nil {
-		return toolError("write_file", "operation cancelled before write")
+		return ErrorResult("write_file", "operation cancelled before write")
 	}
 
 	// Write file (overwrite)
 	if err := os.WriteFile(path, []byte(a.Content), 0644); err != nil {
-		return toolError("write_file", fmt.Sprintf("failed to write file: %s: %v", formatResolvedPath(a.Path, resolvedPath), err))
+		return ErrorResult("write_file", fmt.Sprintf("failed to write file: %s: %v", formatResolvedPath(a.Path, resolvedPath), err))
 	}
 
-	return toolResult("write_file", map[string]any{
+	return okResult("write_file", map[string]any{
 		"path":  resolvedPath,
 		"bytes": len(a.Content),
 	}, "")
 }
 
+// countLines returns the number of lines in s, counting a trailing
+// unterminated line.
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	n := strings.Count(s, "\n")
+	if !strings.HasSuffix(s, "\n") {
+		n++
+	}
+	return n
+}
+
 // EditFileTool edits a file by replacing text.
 type EditFileTool struct {
-	workspace string
+	workspace           string
+	restrictToWorkspace bool
 }
 
 // Def returns the tool definition.
@@ -370,28 +424,36 @@ func normalizeTrailingWS(s string) string {
 	return strings.Join(lines, "\n")
 }
 
-// Run executes the tool.
-func (t *EditFileTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "edit_file", fileToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *EditFileTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "edit_file", fileToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *EditFileTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *EditFileTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *EditFileTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a editFileArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	path := resolveToolPath(a.Path, t.workspace)
 	resolvedPath := absOrOriginal(path)
+	if errMsg := workspaceEscapeError("edit_file", resolvedPath, t.workspace, t.restrictToWorkspace); errMsg != "" {
+		return errorText(errMsg)
+	}
 
 	content, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return toolError("edit_file", fmt.Sprintf("file not found: %s", formatResolvedPath(a.Path, resolvedPath)))
+			return ErrorResult("edit_file", fmt.Sprintf("file not found: %s", formatResolvedPath(a.Path, resolvedPath)))
 		}
-		return toolError("edit_file", fmt.Sprintf("failed to read file: %s: %v", formatResolvedPath(a.Path, resolvedPath), err))
+		return ErrorResult("edit_file", fmt.Sprintf("failed to read file: %s: %v", formatResolvedPath(a.Path, resolvedPath), err))
 	}
 
 	contentStr := string(content)
@@ -407,10 +469,10 @@ func (t *EditFileTool) run(ctx context.Context, args json.RawMessage) string {
 		normCount := strings.Count(normContent, normOld)
 
 		if normCount == 0 {
-			return toolError("edit_file", fmt.Sprintf("text not found in file: %q (path: %s)", a.OldText, displayPath))
+			return ErrorResult("edit_file", fmt.Sprintf("text not found in file: %q (path: %s)", a.OldText, displayPath))
 		}
 		if normCount > 1 && !a.ReplaceAll {
-			return toolError("edit_file", fmt.Sprintf("text appears %d times in file (path: %s); match must be unique. Provide more context or use replace_all.", normCount, displayPath))
+			return ErrorResult("edit_file", fmt.Sprintf("text appears %d times in file (path: %s); match must be unique. Provide more context or use replace_all.", normCount, displayPath))
 		}
 
 		// Find the corresponding region in the original content and replace.
@@ -421,17 +483,20 @@ func (t *EditFileTool) run(ctx context.Context, args json.RawMessage) string {
 			newContent = normalizedReplace(contentStr, normOld, a.NewText)
 		}
 
-		if ctx.Err() != nil {
-			return toolError("edit_file", "operation cancelled before write")
-		}
-		if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
-			return toolError("edit_file", fmt.Sprintf("failed to write file: %s: %v", displayPath, err))
-		}
 		n := normCount
 		if !a.ReplaceAll {
 			n = 1
 		}
-		return toolResult("edit_file", map[string]any{
+		if RuntimeContextFrom(ctx).DryRun {
+			return editDryRunResult(displayPath, resolvedPath, a.OldText, a.NewText, n)
+		}
+		if ctx.Err() != nil {
+			return ErrorResult("edit_file", "operation cancelled before write")
+		}
+		if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
+			return ErrorResult("edit_file", fmt.Sprintf("failed to write file: %s: %v", displayPath, err))
+		}
+		return okResult("edit_file", map[string]any{
 			"path":         displayPath,
 			"replacements": n,
 			"fuzzy":        true,
@@ -439,7 +504,7 @@ func (t *EditFileTool) run(ctx context.Context, args json.RawMessage) string {
 	}
 
 	if count > 1 && !a.ReplaceAll {
-		return toolError("edit_file", fmt.Sprintf("text appears %d times in file (path: %s); match must be unique. Provide more context or use replace_all.", count, displayPath))
+		return ErrorResult("edit_file", fmt.Sprintf("text appears %d times in file (path: %s); match must be unique. Provide more context or use replace_all.", count, displayPath))
 	}
 
 	var newContent string
@@ -449,19 +514,40 @@ func (t *EditFileTool) run(ctx context.Context, args json.RawMessage) string {
 		newContent = strings.Replace(contentStr, a.OldText, a.NewText, 1)
 	}
 
+	if RuntimeContextFrom(ctx).DryRun {
+		return editDryRunResult(displayPath, resolvedPath, a.OldText, a.NewText, count)
+	}
 	if ctx.Err() != nil {
-		return toolError("edit_file", "operation cancelled before write")
+		return ErrorResult("edit_file", "operation cancelled before write")
 	}
 	if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
-		return toolError("edit_file", fmt.Sprintf("failed to write file: %s: %v", displayPath, err))
+		return ErrorResult("edit_file", fmt.Sprintf("failed to write file: %s: %v", displayPath, err))
 	}
 
-	return toolResult("edit_file", map[string]any{
+	return okResult("edit_file", map[string]any{
 		"path":         displayPath,
 		"replacements": count,
 	}, "")
 }
 
+// editDryRunResult describes an edit that was computed but not written,
+// rendering the replaced region as a minimal unified-style diff.
+func editDryRunResult(displayPath, resolvedPath, oldText, newText string, replacements int) ToolResult {
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- %s\n+++ %s\n", resolvedPath, resolvedPath)
+	for _, line := range strings.Split(strings.TrimSuffix(oldText, "\n"), "\n") {
+		diff.WriteString("-" + line + "\n")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(newText, "\n"), "\n") {
+		diff.WriteString("+" + line + "\n")
+	}
+	return okResult("edit_file", map[string]any{
+		"path":         displayPath,
+		"replacements": replacements,
+		"dry_run":      true,
+	}, fmt.Sprintf("Dry run: would apply %d replacement(s). Nothing was written.\n\n%s", replacements, diff.String()))
+}
+
 // normToOrigPos maps a character position in normalized text back to the
 // corresponding position in the original text. Since normalization only
 // trims trailing whitespace per line, positions within a line are identical;
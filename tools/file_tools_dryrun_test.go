@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func dryRunCtx() context.Context {
+	return WithRuntimeContext(context.Background(), RuntimeContext{DryRun: true})
+}
+
+func TestWriteFile_DryRunDoesNotTouchDisk(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "sub", "new.txt")
+	args, _ := json.Marshal(map[string]string{"path": p, "content": "one\ntwo\n"})
+
+	tool := &WriteFileTool{workspace: dir}
+	out := tool.Run(dryRunCtx(), args)
+
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Fatalf("file should not exist after dry run, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(p)); !os.IsNotExist(err) {
+		t.Fatalf("parent dir should not be created after dry run, stat err = %v", err)
+	}
+	for _, want := range []string{"dry_run: true", "would create", "8 bytes", "2 lines"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteFile_DryRunReportsOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(p, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := json.Marshal(map[string]string{"path": p, "content": "replaced"})
+
+	tool := &WriteFileTool{workspace: dir}
+	out := tool.Run(dryRunCtx(), args)
+
+	if !strings.Contains(out, "would overwrite") {
+		t.Errorf("expected overwrite summary, got:\n%s", out)
+	}
+	b, _ := os.ReadFile(p)
+	if string(b) != "original" {
+		t.Fatalf("file modified during dry run: %q", string(b))
+	}
+}
+
+func TestEditFile_DryRunReturnsDiff(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "f.md")
+	if err := os.WriteFile(p, []byte("hello world\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := json.Marshal(map[string]string{"path": p, "old_text": "hello", "new_text": "HELLO"})
+
+	tool := &EditFileTool{workspace: dir}
+	out := tool.Run(dryRunCtx(), args)
+
+	for _, want := range []string{"dry_run: true", "-hello", "+HELLO"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	b, _ := os.ReadFile(p)
+	if string(b) != "hello world\n" {
+		t.Fatalf("file modified during dry run: %q", string(b))
+	}
+}
+
+func TestExec_DryRunDoesNotExecute(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	args, _ := json.Marshal(execArgs{Command: "touch " + marker})
+
+	tool := NewExecTool(dir, 5, false)
+	out := tool.Run(dryRunCtx(), args)
+
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("command ran during dry run, stat err = %v", err)
+	}
+	if !strings.Contains(out, "touch "+marker) {
+		t.Errorf("output should echo the command, got:\n%s", out)
+	}
+}
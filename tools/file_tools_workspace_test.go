@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileToolsResolveRelativePathUnderWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspace, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write := &WriteFileTool{workspace: workspace, restrictToWorkspace: true}
+	args, _ := json.Marshal(map[string]any{"path": "notes/todo.md", "content": "buy milk\n"})
+	if out := write.Run(context.Background(), args); strings.Contains(out, "error") {
+		t.Fatalf("write_file failed:\n%s", out)
+	}
+	data, err := os.ReadFile(filepath.Join(workspace, "notes", "todo.md"))
+	if err != nil || string(data) != "buy milk\n" {
+		t.Fatalf("file under workspace = %q, %v", data, err)
+	}
+
+	read := &ReadFileTool{workspace: workspace, restrictToWorkspace: true}
+	args, _ = json.Marshal(map[string]any{"path": "notes/todo.md"})
+	if out := read.Run(context.Background(), args); !strings.Contains(out, "buy milk") {
+		t.Fatalf("read_file of relative path:\n%s", out)
+	}
+}
+
+func TestFileToolsRejectWorkspaceEscape(t *testing.T) {
+	root := t.TempDir()
+	workspace := filepath.Join(root, "workspace")
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(root, "secret.txt")
+	if err := os.WriteFile(secret, []byte("token=abc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(root, filepath.Join(workspace, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, path := range []string{"../secret.txt", "link/secret.txt", secret} {
+		cases := []struct {
+			name string
+			tool Tool
+			args map[string]any
+		}{
+			{"read_file", &ReadFileTool{workspace: workspace, restrictToWorkspace: true}, map[string]any{"path": path}},
+			{"write_file", &WriteFileTool{workspace: workspace, restrictToWorkspace: true}, map[string]any{"path": path, "content": "x"}},
+			{"edit_file", &EditFileTool{workspace: workspace, restrictToWorkspace: true}, map[string]any{"path": path, "old_string": "abc", "new_string": "xyz"}},
+			{"grep", &GrepTool{workspace: workspace, restrictToWorkspace: true}, map[string]any{"pattern": "token", "path": path}},
+		}
+		for _, tc := range cases {
+			args, _ := json.Marshal(tc.args)
+			if out := tc.tool.Run(ctx, args); !strings.Contains(out, "outside workspace") {
+				t.Errorf("%s %s: expected workspace error, got:\n%s", tc.name, path, out)
+			}
+		}
+	}
+	if data, _ := os.ReadFile(secret); string(data) != "token=abc\n" {
+		t.Fatalf("secret modified: %q", data)
+	}
+
+	// Without the restriction, relative paths may still leave the workspace.
+	read := &ReadFileTool{workspace: workspace}
+	args, _ := json.Marshal(map[string]any{"path": "../secret.txt"})
+	if out := read.Run(ctx, args); !strings.Contains(out, "token=abc") {
+		t.Errorf("unrestricted read_file of ../secret.txt:\n%s", out)
+	}
+}
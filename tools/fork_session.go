@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// SessionCopier is implemented by session.Manager.
+type SessionCopier interface {
+	Copy(srcKey, dstKey string) (int, error)
+}
+
+// ForkSessionTool copies the current session's messages into a new session so
+// an alternative can be explored without touching the original.
+type ForkSessionTool struct {
+	copier SessionCopier
+}
+
+// NewForkSessionTool creates the tool.
+func NewForkSessionTool(copier SessionCopier) *ForkSessionTool {
+	return &ForkSessionTool{copier: copier}
+}
+
+// Def returns the tool definition.
+func (t *ForkSessionTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "fork_session",
+			Description: "Copy this conversation's full history into a new session, e.g. to explore an alternative without affecting the main thread. " +
+				"The fork is independent from then on. A bare name like \"experiment\" creates <current session>:experiment; " +
+				"a full key elsewhere is admin only. Refuses to overwrite an existing session.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"new_key": map[string]any{
+						"type":        "string",
+						"description": "Name or full session key for the fork (letters, digits, - _ . and :).",
+					},
+				},
+				"required": []string{"new_key"},
+			},
+		},
+	}
+}
+
+type forkSessionArgs struct {
+	NewKey string `json:"new_key" required:"true" alias:"newKey,key,name"`
+}
+
+// RunResult executes the tool.
+func (t *ForkSessionTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a forkSessionArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	if t.copier == nil {
+		return ErrorResult("fork_session", "session store not configured")
+	}
+	rt := RuntimeContextFrom(ctx)
+	if rt.SessionKey == "" {
+		return ErrorResult("fork_session", "no active session")
+	}
+	dst := strings.TrimSpace(a.NewKey)
+	if !strings.Contains(dst, ":") {
+		dst = rt.SessionKey + ":" + dst
+	}
+	// Other users' keys would let a turn seed a conversation they later join.
+	if !rt.Admin && !strings.HasPrefix(dst, rt.SessionKey+":") {
+		return ErrorResult("fork_session", "admin only: forks outside "+rt.SessionKey+":* need a turn started by the admin, the local CLI, or system automation")
+	}
+	if rt.DryRun {
+		return okResult("fork_session", map[string]any{"source": rt.SessionKey, "new_key": dst, "dry_run": true},
+			fmt.Sprintf("Dry run: would copy %s into %s. Nothing was written.", rt.SessionKey, dst))
+	}
+	n, err := t.copier.Copy(rt.SessionKey, dst)
+	if err != nil {
+		return ErrorResult("fork_session", err.Error())
+	}
+	return okResult("fork_session", map[string]any{"source": rt.SessionKey, "new_key": dst, "messages": n},
+		fmt.Sprintf("Copied %d messages into %s. Continue there to explore the alternative; this session is unchanged.", n, dst))
+}
+
+// Run implements Tool.
+func (t *ForkSessionTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type recordingCopier struct {
+	src, dst string
+}
+
+func (c *recordingCopier) Copy(src, dst string) (int, error) {
+	c.src, c.dst = src, dst
+	return 3, nil
+}
+
+func TestForkSession_BareNameNestsUnderCurrentSession(t *testing.T) {
+	c := &recordingCopier{}
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42"})
+	res := NewForkSessionTool(c).Run(ctx, json.RawMessage(`{"new_key":"experiment"}`))
+	if IsToolError(res) {
+		t.Fatalf("unexpected error: %s", res)
+	}
+	if c.src != "telegram:42" || c.dst != "telegram:42:experiment" {
+		t.Errorf("Copy(%q, %q), want telegram:42 → telegram:42:experiment", c.src, c.dst)
+	}
+	if !strings.Contains(res, "messages: 3") {
+		t.Errorf("result should report the copied count: %s", res)
+	}
+}
+
+func TestForkSession_ForeignKeyRequiresAdmin(t *testing.T) {
+	c := &recordingCopier{}
+	args := json.RawMessage(`{"new_key":"telegram:7"}`)
+	user := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42"})
+	if res := NewForkSessionTool(c).Run(user, args); !IsToolError(res) || !strings.Contains(res, "admin only") {
+		t.Fatalf("expected admin-only error, got: %s", res)
+	}
+	if c.dst != "" {
+		t.Fatalf("non-admin fork reached the store: %q", c.dst)
+	}
+	admin := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42", Admin: true})
+	if res := NewForkSessionTool(c).Run(admin, args); IsToolError(res) || c.dst != "telegram:7" {
+		t.Fatalf("admin fork failed (dst %q): %s", c.dst, res)
+	}
+}
@@ -3,7 +3,6 @@ package tools
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -15,7 +14,8 @@ import (
 
 // GlobTool finds files matching a glob pattern.
 type GlobTool struct {
-	workspace string
+	workspace           string
+	restrictToWorkspace bool
 }
 
 // Def returns the tool definition.
@@ -67,21 +67,26 @@ type globEntry struct {
 	modTime int64
 }
 
-// Run executes the tool.
-func (t *GlobTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "glob", globToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *GlobTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "glob", globToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *GlobTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *GlobTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *GlobTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a globArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	if a.Pattern == "" {
-		return "Error: pattern is required"
+		return ErrorResult("glob", "pattern is required")
 	}
 
 	searchPath := t.workspace
@@ -91,15 +96,18 @@ func (t *GlobTool) run(ctx context.Context, args json.RawMessage) string {
 	if searchPath == "" {
 		searchPath = "."
 	}
+	if errMsg := workspaceEscapeError("glob", absOrOriginal(searchPath), t.workspace, t.restrictToWorkspace); errMsg != "" {
+		return errorText(errMsg)
+	}
 
 	logger.Debug("glob tool", "pattern", a.Pattern, "searchPath", searchPath)
 
 	info, err := os.Stat(searchPath)
 	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
+		return ErrorResult("glob", err.Error())
 	}
 	if !info.IsDir() {
-		return fmt.Sprintf("Error: path is not a directory: %s", absOrOriginal(searchPath))
+		return ErrorResult("glob", "path is not a directory: "+absOrOriginal(searchPath))
 	}
 
 	var entries []globEntry
@@ -145,11 +153,11 @@ func (t *GlobTool) run(ctx context.Context, args json.RawMessage) string {
 		return nil
 	})
 	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
+		return ErrorResult("glob", err.Error())
 	}
 
 	if len(entries) == 0 {
-		return toolResult("glob", map[string]any{
+		return okResult("glob", map[string]any{
 			"pattern": a.Pattern,
 			"path":    searchPath,
 			"results": 0,
@@ -178,7 +186,7 @@ func (t *GlobTool) run(ctx context.Context, args json.RawMessage) string {
 		sb.WriteByte('\n')
 	}
 
-	return toolResult("glob", fields, strings.TrimRight(sb.String(), "\n"))
+	return okResult("glob", fields, strings.TrimRight(sb.String(), "\n"))
 }
 
 // matchDoublestar handles glob patterns containing **.
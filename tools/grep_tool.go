@@ -12,7 +12,8 @@ import (
 
 // GrepTool searches file contents using regex patterns.
 type GrepTool struct {
-	workspace string
+	workspace           string
+	restrictToWorkspace bool
 }
 
 // Def returns the tool definition.
@@ -65,21 +66,26 @@ type grepArgs struct {
 	CaseInsensitive bool   `json:"case_insensitive,omitempty"`
 }
 
-// Run executes the tool.
-func (t *GrepTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "grep", grepToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *GrepTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "grep", grepToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *GrepTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *GrepTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *GrepTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a grepArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	if a.Pattern == "" {
-		return "Error: pattern is required"
+		return ErrorResult("grep", "pattern is required")
 	}
 
 	searchPath := t.workspace
@@ -89,6 +95,9 @@ func (t *GrepTool) run(ctx context.Context, args json.RawMessage) string {
 	if searchPath == "" {
 		searchPath = "."
 	}
+	if errMsg := workspaceEscapeError("grep", absOrOriginal(searchPath), t.workspace, t.restrictToWorkspace); errMsg != "" {
+		return errorText(errMsg)
+	}
 
 	maxResults := a.MaxResults
 	if maxResults <= 0 {
@@ -115,20 +124,20 @@ func (t *GrepTool) run(ctx context.Context, args json.RawMessage) string {
 	if err != nil {
 		// Exit code 1 means no matches for both rg and grep
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return toolResult("grep", map[string]any{
+			return okResult("grep", map[string]any{
 				"pattern": a.Pattern,
 				"path":    searchPath,
 				"results": 0,
 			}, "No matches found.")
 		}
 		if output != "" {
-			return toolError("grep", output)
+			return ErrorResult("grep", output)
 		}
-		return toolError("grep", fmt.Sprintf("%v", err))
+		return ErrorResult("grep", fmt.Sprintf("%v", err))
 	}
 
 	if output == "" {
-		return toolResult("grep", map[string]any{
+		return okResult("grep", map[string]any{
 			"pattern": a.Pattern,
 			"path":    searchPath,
 			"results": 0,
@@ -149,7 +158,7 @@ func (t *GrepTool) run(ctx context.Context, args json.RawMessage) string {
 		output = strings.Join(lines[:maxResults], "\n")
 	}
 
-	return toolResult("grep", fields, output)
+	return okResult("grep", fields, output)
 }
 
 func (t *GrepTool) buildRgArgs(a grepArgs, searchPath string) []string {
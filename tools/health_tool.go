@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
+
 	healthsnap "github.com/linanwx/nagobot/internal/health"
 	"github.com/linanwx/nagobot/provider"
 	"gopkg.in/yaml.v3"
@@ -40,6 +42,15 @@ type HealthWeComInfo = healthsnap.WeComInfo
 // HealthWebInfo holds Web config for health output.
 type HealthWebInfo = healthsnap.WebInfo
 
+// HealthBreakerInfo holds a provider's circuit breaker state for health output.
+type HealthBreakerInfo = healthsnap.BreakerInfo
+
+// HealthChannelQueueInfo holds a channel's inbound buffer fill for health output.
+type HealthChannelQueueInfo = healthsnap.ChannelQueueInfo
+
+// HealthTurnsInfo holds the global turn limiter state for health output.
+type HealthTurnsInfo = healthsnap.TurnsInfo
+
 // HealthTool reports runtime health info for the current process.
 type HealthTool struct {
 	Workspace     string
@@ -51,6 +62,18 @@ type HealthTool struct {
 	ChannelsFn    func() *HealthChannelsInfo
 	CtxFn         HealthContextProvider
 	ThreadsListFn func() []ThreadInfo
+	PingFn        func(ctx context.Context) error // Optional provider connectivity check for deep mode.
+	BreakersFn    func() []HealthBreakerInfo      // Optional provider circuit breaker states.
+	QueuesFn      func() []HealthChannelQueueInfo // Optional channel inbound buffer depths.
+	TurnsFn       func() *HealthTurnsInfo         // Optional in-flight turn count and limit.
+}
+
+// healthPingTimeout bounds the deep-mode provider probe so the health tool
+// stays well inside healthToolTimeout.
+const healthPingTimeout = 8 * time.Second
+
+type healthArgs struct {
+	Deep bool `json:"deep"`
 }
 
 // Def returns the tool definition.
@@ -59,10 +82,15 @@ func (t *HealthTool) Def() provider.ToolDef {
 		Type: "function",
 		Function: provider.FunctionDef{
 			Name:        "health",
-			Description: "Get runtime status of this nagobot process. Returns: LLM provider and model, current time and timezone, Go version/OS/arch, workspace/sessions/skills paths, current thread info (ID, agent name, session key), current session file stats (size, message count), all sessions scan (valid/invalid counts), all active threads, channel config (Telegram allowed IDs, Web addr), cron job list, workspace directory tree, process memory and goroutine count.",
+			Description: "Get runtime status of this nagobot process. Returns: LLM provider and model, current time and timezone, Go version/OS/arch, workspace/sessions/skills paths, current thread info (ID, agent name, session key), current session file stats (size, message count), all sessions scan (valid/invalid counts), all active threads, in-flight agent turns vs. the concurrency limit, provider circuit breaker states, channel config (Telegram allowed IDs, Web addr), cron job list, workspace directory tree, process memory and goroutine count. Set deep=true to also probe whether the LLM provider is reachable (reports latency; slower).",
 			Parameters: map[string]any{
-				"type":       "object",
-				"properties": map[string]any{},
+				"type": "object",
+				"properties": map[string]any{
+					"deep": map[string]any{
+						"type":        "boolean",
+						"description": "Also send a tiny live request to the LLM provider and report reachability and latency. Defaults to false.",
+					},
+				},
 			},
 		},
 	}
@@ -75,14 +103,24 @@ func (t *HealthTool) channels() *HealthChannelsInfo {
 	return nil
 }
 
-// Run executes the tool.
-func (t *HealthTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "health", healthToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *HealthTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "health", healthToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *HealthTool) run(ctx context.Context, _ json.RawMessage) string {
+// Run implements Tool.
+func (t *HealthTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *HealthTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a healthArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
 	const (
 		treeDepth      = 1
 		treeMaxEntries = 200
@@ -121,10 +159,38 @@ func (t *HealthTool) run(ctx context.Context, _ json.RawMessage) string {
 	if t.ThreadsListFn != nil {
 		snapshot.AllThreads = t.ThreadsListFn()
 	}
+	if t.BreakersFn != nil {
+		snapshot.Breakers = t.BreakersFn()
+	}
+	if t.QueuesFn != nil {
+		snapshot.ChannelQueues = t.QueuesFn()
+	}
+	if t.TurnsFn != nil {
+		snapshot.Turns = t.TurnsFn()
+	}
+	if a.Deep && t.PingFn != nil {
+		snapshot.ProviderProbe = t.probeProvider(ctx)
+	}
 
 	data, err := yaml.Marshal(snapshot)
 	if err != nil {
-		return fmt.Sprintf("Error: failed to serialize health snapshot: %v", err)
+		return ErrorResult("health", fmt.Sprintf("failed to serialize health snapshot: %v", err))
+	}
+	return ToolResult{Content: string(data)}
+}
+
+// probeProvider runs PingFn under healthPingTimeout and records the outcome.
+func (t *HealthTool) probeProvider(ctx context.Context) *healthsnap.ProviderProbe {
+	pingCtx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+	defer cancel()
+	start := time.Now()
+	err := t.PingFn(pingCtx)
+	probe := &healthsnap.ProviderProbe{
+		Reachable: err == nil,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		probe.Error = err.Error()
 	}
-	return string(data)
+	return probe
 }
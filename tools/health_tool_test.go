@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// unreachableProvider fails every Ping and must never be sent a chat request.
+type unreachableProvider struct{}
+
+func (unreachableProvider) Chat(context.Context, *provider.Request) (provider.ChatResult, error) {
+	return nil, errors.New("chat should not be called when Ping is implemented")
+}
+
+func (unreachableProvider) Ping(context.Context) error {
+	return errors.New("dial tcp: connection refused")
+}
+
+func TestHealthTool_DeepReportsUnreachableProvider(t *testing.T) {
+	tool := &HealthTool{
+		Workspace: t.TempDir(),
+		PingFn: func(ctx context.Context) error {
+			return provider.Ping(ctx, unreachableProvider{})
+		},
+	}
+
+	out := tool.Run(context.Background(), []byte(`{"deep":true}`))
+	if !strings.Contains(out, "provider_probe:") || !strings.Contains(out, "reachable: false") {
+		t.Fatalf("expected unreachable provider probe, got:\n%s", out)
+	}
+	if !strings.Contains(out, "connection refused") {
+		t.Errorf("expected ping error in output, got:\n%s", out)
+	}
+}
+
+func TestHealthTool_ShallowSkipsProbe(t *testing.T) {
+	pinged := false
+	tool := &HealthTool{
+		Workspace: t.TempDir(),
+		PingFn: func(context.Context) error {
+			pinged = true
+			return nil
+		},
+	}
+
+	out := tool.Run(context.Background(), []byte(`{}`))
+	if pinged || strings.Contains(out, "provider_probe:") {
+		t.Fatalf("default health check should not probe the provider:\n%s", out)
+	}
+}
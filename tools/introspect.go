@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// DescribeToolsTool returns full parameter schemas for registered tools, so
+// the model can check exact argument names instead of guessing.
+type DescribeToolsTool struct {
+	registry *Registry
+}
+
+// NewDescribeToolsTool creates a describe_tools tool over registry. The
+// registry is read at call time, so tools registered later are included.
+func NewDescribeToolsTool(registry *Registry) *DescribeToolsTool {
+	return &DescribeToolsTool{registry: registry}
+}
+
+// Def returns the tool definition.
+func (t *DescribeToolsTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "describe_tools",
+			Description: "Return the full definition (name, description, JSON parameter schema) of available tools. Use this to check exact argument names and types before calling a tool you are unsure about.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"names": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"description": "Tool names to describe. Omit to describe every tool.",
+					},
+				},
+			},
+		},
+	}
+}
+
+type describeToolsArgs struct {
+	Names []string `json:"names"`
+}
+
+// toolDescription is the compact per-tool shape returned by describe_tools.
+type toolDescription struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *DescribeToolsTool) RunResult(_ context.Context, args json.RawMessage) ToolResult {
+	var a describeToolsArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	var defs []provider.ToolDef
+	var unknown []string
+	if len(a.Names) == 0 {
+		defs = t.registry.Defs()
+	} else {
+		for _, name := range a.Names {
+			name = strings.TrimSpace(name)
+			if tool, ok := t.registry.Get(name); ok {
+				defs = append(defs, tool.Def())
+			} else {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	if len(defs) == 0 {
+		return ErrorResult("describe_tools", fmt.Sprintf("no matching tools: %s. Available: %s",
+			strings.Join(unknown, ", "), strings.Join(t.registry.Names(), ", ")))
+	}
+
+	out := make([]toolDescription, 0, len(defs))
+	for _, d := range defs {
+		out = append(out, toolDescription{
+			Name:        d.Function.Name,
+			Description: d.Function.Description,
+			Parameters:  d.Function.Parameters,
+		})
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return ErrorResult("describe_tools", fmt.Sprintf("failed to encode tool definitions: %v", err))
+	}
+
+	fields := map[string]any{"count": len(out)}
+	if len(unknown) > 0 {
+		fields["unknown"] = strings.Join(unknown, ", ")
+	}
+	return okResult("describe_tools", fields, string(data))
+}
+
+// Run implements Tool.
+func (t *DescribeToolsTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// ListSkillsTool lists installed skills with their one-line descriptions.
+type ListSkillsTool struct {
+	provider SkillProvider
+}
+
+// NewListSkillsTool creates a list_skills tool.
+func NewListSkillsTool(provider SkillProvider) *ListSkillsTool {
+	return &ListSkillsTool{provider: provider}
+}
+
+// Def returns the tool definition.
+func (t *ListSkillsTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "list_skills",
+			Description: "List installed skills with their descriptions. Load one with use_skill.",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+	}
+}
+
+// RunResult executes the tool.
+func (t *ListSkillsTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "list_skills", skillToolTimeout, func(context.Context) ToolResult {
+		var a struct{}
+		if errMsg := parseArgs(args, &a); errMsg != "" {
+			return errorText(errMsg)
+		}
+		names := t.provider.SkillNames()
+		var sb strings.Builder
+		for _, name := range names {
+			sb.WriteString("- " + name)
+			if desc := strings.TrimSpace(t.provider.SkillDescription(name)); desc != "" {
+				sb.WriteString(": " + desc)
+			}
+			sb.WriteString("\n")
+		}
+		return okResult("list_skills", map[string]any{"count": len(names)}, sb.String())
+	})
+}
+
+// Run implements Tool.
+func (t *ListSkillsTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
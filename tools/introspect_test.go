@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+// describedTools parses the JSON body of a describe_tools result.
+func describedTools(t *testing.T, out string) []toolDescription {
+	t.Helper()
+	_, body, ok := msg.SplitFrontmatter(out)
+	if !ok {
+		t.Fatalf("result has no frontmatter:\n%s", out)
+	}
+	var got []toolDescription
+	if err := json.Unmarshal([]byte(strings.TrimSpace(body)), &got); err != nil {
+		t.Fatalf("body is not JSON: %v\n%s", err, body)
+	}
+	return got
+}
+
+func TestDescribeTools_MatchesDef(t *testing.T) {
+	reg := NewRegistry()
+	edit := &EditFileTool{}
+	reg.Register(edit)
+	reg.Register(&WriteFileTool{})
+	reg.Register(NewDescribeToolsTool(reg))
+
+	out := reg.Run(context.Background(), "describe_tools", json.RawMessage(`{"names":["edit_file","nope"]}`))
+	got := describedTools(t, out)
+	if len(got) != 1 {
+		t.Fatalf("described %d tools, want 1:\n%s", len(got), out)
+	}
+	def := edit.Def().Function
+	if got[0].Name != def.Name || got[0].Description != def.Description {
+		t.Errorf("name/description mismatch: %+v", got[0])
+	}
+	// Round-trip the Def's schema through JSON so types compare equal.
+	var want map[string]any
+	raw, _ := json.Marshal(def.Parameters)
+	_ = json.Unmarshal(raw, &want)
+	if !reflect.DeepEqual(got[0].Parameters, want) {
+		t.Errorf("schema mismatch:\n got: %v\nwant: %v", got[0].Parameters, want)
+	}
+	if !strings.Contains(out, "unknown: nope") {
+		t.Errorf("expected unknown name to be reported:\n%s", out)
+	}
+}
+
+func TestDescribeTools_AllIncludesItself(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&WriteFileTool{})
+	reg.Register(NewDescribeToolsTool(reg))
+
+	got := describedTools(t, reg.Run(context.Background(), "describe_tools", nil))
+	var names []string
+	for _, d := range got {
+		names = append(names, d.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"describe_tools", "write_file"}) {
+		t.Errorf("names = %v", names)
+	}
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+)
+
+// JournalFileName is the per-session tool execution journal, kept in the
+// session directory next to meta.json.
+const JournalFileName = "tool_journal.jsonl"
+
+const (
+	// journalMaxLines bounds the journal: past it, finished calls are
+	// compacted away and only unfinished intents are kept.
+	journalMaxLines = 200
+	// journalArgsMaxRunes / journalResultMaxRunes bound each record.
+	journalArgsMaxRunes   = 2000
+	journalResultMaxRunes = 500
+)
+
+// journalTools are the tools with side effects worth journaling: the same
+// set dry-run mode simulates.
+var journalTools = map[string]bool{
+	"exec":        true,
+	"write_file":  true,
+	"edit_file":   true,
+	"apply_patch": true,
+}
+
+// JournalEntry is one line of the tool execution journal. An "intent" record
+// is written before the tool runs and a "result" record with the same ID
+// after it returns; an intent without a result means the process died
+// mid-call.
+type JournalEntry struct {
+	ID     string          `json:"id"`
+	Phase  string          `json:"phase"` // "intent" or "result"
+	Time   time.Time       `json:"time"`
+	Tool   string          `json:"tool,omitempty"`
+	Args   json.RawMessage `json:"args,omitempty"`
+	OK     bool            `json:"ok,omitempty"`
+	Result string          `json:"result,omitempty"`
+	Admin  bool            `json:"admin,omitempty"` // intent: the calling turn was admin
+}
+
+// ToolJournal records mutating tool calls per session so a crash between
+// starting and finishing a call can be reconciled on the next start.
+type ToolJournal struct {
+	mu  sync.Mutex
+	seq atomic.Int64
+}
+
+// NewToolJournal creates a tool journal.
+func NewToolJournal() *ToolJournal {
+	return &ToolJournal{}
+}
+
+// Begin writes an intent record for a mutating tool call in sessionDir and
+// returns its ID. admin records whether the calling turn was admin, so the
+// recovery wake after a crash keeps the same privileges. Returns "" (nothing
+// to finish) for non-journaled tools, an empty sessionDir, or a write failure.
+func (j *ToolJournal) Begin(sessionDir, tool string, args json.RawMessage, admin bool) string {
+	if j == nil || sessionDir == "" || !journalTools[tool] {
+		return ""
+	}
+	now := time.Now()
+	id := fmt.Sprintf("%d-%d", now.UnixNano(), j.seq.Add(1))
+	entry := JournalEntry{ID: id, Phase: "intent", Time: now, Tool: tool, Args: journalArgs(args), Admin: admin}
+	if err := j.append(sessionDir, entry); err != nil {
+		logger.Warn("tool journal intent write failed", "tool", tool, "err", err)
+		return ""
+	}
+	return id
+}
+
+// Finish writes the result record for an intent returned by Begin.
+func (j *ToolJournal) Finish(sessionDir, id string, ok bool, result string) {
+	if j == nil || sessionDir == "" || id == "" {
+		return
+	}
+	if runes := []rune(result); len(runes) > journalResultMaxRunes {
+		result = string(runes[:journalResultMaxRunes])
+	}
+	entry := JournalEntry{ID: id, Phase: "result", Time: time.Now(), OK: ok, Result: logger.Redact(result)}
+	if err := j.append(sessionDir, entry); err != nil {
+		logger.Warn("tool journal result write failed", "err", err)
+	}
+}
+
+func (j *ToolJournal) append(sessionDir string, entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := os.MkdirAll(sessionDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(sessionDir, JournalFileName)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	// The intent must reach disk before the tool runs, or a crash loses it.
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if entry.Phase == "result" {
+		return compactJournal(path)
+	}
+	return nil
+}
+
+// PendingJournalEntries returns the intents in sessionDir's journal that
+// have no result record: tool calls interrupted by a crash.
+func PendingJournalEntries(sessionDir string) ([]JournalEntry, error) {
+	entries, err := readJournal(filepath.Join(sessionDir, JournalFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pendingIntents(entries), nil
+}
+
+// ClearJournal removes sessionDir's journal once its pending entries have
+// been surfaced to the session.
+func ClearJournal(sessionDir string) error {
+	err := os.Remove(filepath.Join(sessionDir, JournalFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// compactJournal rewrites the journal with only its unfinished intents once
+// it grows past journalMaxLines.
+func compactJournal(path string) error {
+	entries, err := readJournal(path)
+	if err != nil || len(entries) <= journalMaxLines {
+		return err
+	}
+	pending := pendingIntents(entries)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range pending {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readJournal parses a journal file, skipping malformed lines (a torn last
+// line after a crash).
+func readJournal(path string) ([]JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var e JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ID == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func pendingIntents(entries []JournalEntry) []JournalEntry {
+	finished := make(map[string]bool)
+	for _, e := range entries {
+		if e.Phase == "result" {
+			finished[e.ID] = true
+		}
+	}
+	var pending []JournalEntry
+	for _, e := range entries {
+		if e.Phase == "intent" && !finished[e.ID] {
+			pending = append(pending, e)
+		}
+	}
+	return pending
+}
+
+// journalArgs redacts secrets and bounds the size of recorded arguments.
+func journalArgs(args json.RawMessage) json.RawMessage {
+	redacted := logger.Redact(string(args))
+	if runes := []rune(redacted); len(runes) > journalArgsMaxRunes {
+		data, _ := json.Marshal(string(runes[:journalArgsMaxRunes]) + "...")
+		return data
+	}
+	if json.Valid([]byte(redacted)) {
+		return json.RawMessage(redacted)
+	}
+	data, _ := json.Marshal(redacted)
+	return data
+}
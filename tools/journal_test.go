@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+func TestToolJournal_CrashBetweenIntentAndResultLeavesPendingEntry(t *testing.T) {
+	dir := t.TempDir()
+
+	// Process 1: intent written, then the process dies before Finish.
+	crashed := NewToolJournal()
+	id := crashed.Begin(dir, "exec", json.RawMessage(`{"command":"git commit -m wip"}`), false)
+	if id == "" {
+		t.Fatal("Begin() returned empty ID for a journaled tool")
+	}
+	// Simulate a torn write of the result line as the process died.
+	f, err := os.OpenFile(filepath.Join(dir, JournalFileName), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	f.WriteString(`{"id":"` + id + `","phase":"res`)
+	f.Close()
+
+	// Process 2: startup recovery reads the journal from disk.
+	pending, err := PendingJournalEntries(dir)
+	if err != nil {
+		t.Fatalf("PendingJournalEntries() error = %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("pending = %+v, want one interrupted call", pending)
+	}
+	if pending[0].ID != id || pending[0].Tool != "exec" || !strings.Contains(string(pending[0].Args), "git commit") {
+		t.Fatalf("pending[0] = %+v, want the exec intent", pending[0])
+	}
+
+	if err := ClearJournal(dir); err != nil {
+		t.Fatalf("ClearJournal() error = %v", err)
+	}
+	if pending, _ := PendingJournalEntries(dir); len(pending) != 0 {
+		t.Fatalf("pending after clear = %+v, want none", pending)
+	}
+}
+
+func TestToolJournal_FinishedCallIsNotPending(t *testing.T) {
+	dir := t.TempDir()
+	j := NewToolJournal()
+	id := j.Begin(dir, "write_file", json.RawMessage(`{"path":"a.txt","content":"x"}`), false)
+	j.Finish(dir, id, true, "ok")
+
+	pending, err := PendingJournalEntries(dir)
+	if err != nil {
+		t.Fatalf("PendingJournalEntries() error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("pending = %+v, want none", pending)
+	}
+}
+
+func TestToolJournal_SkipsReadOnlyTools(t *testing.T) {
+	dir := t.TempDir()
+	if id := NewToolJournal().Begin(dir, "read_file", json.RawMessage(`{"path":"a"}`), false); id != "" {
+		t.Fatalf("Begin(read_file) = %q, want empty", id)
+	}
+	if _, err := os.Stat(filepath.Join(dir, JournalFileName)); !os.IsNotExist(err) {
+		t.Fatalf("journal file should not exist for read-only tools, stat err = %v", err)
+	}
+}
+
+func TestToolJournal_CompactsToPendingIntents(t *testing.T) {
+	dir := t.TempDir()
+	j := NewToolJournal()
+	open := j.Begin(dir, "exec", json.RawMessage(`{"command":"sleep 100"}`), false)
+	for i := 0; i < journalMaxLines; i++ {
+		id := j.Begin(dir, "edit_file", json.RawMessage(`{}`), false)
+		j.Finish(dir, id, true, "ok")
+	}
+
+	entries, err := readJournal(filepath.Join(dir, JournalFileName))
+	if err != nil {
+		t.Fatalf("readJournal() error = %v", err)
+	}
+	if len(entries) > journalMaxLines {
+		t.Fatalf("journal has %d lines, want <= %d", len(entries), journalMaxLines)
+	}
+	pending := pendingIntents(entries)
+	if len(pending) != 1 || pending[0].ID != open {
+		t.Fatalf("pending after compaction = %+v, want only %s", pending, open)
+	}
+}
+
+// journalProbeTool stands in for exec and reports what the journal holds
+// while it is running — the state a crash at this point would leave behind.
+type journalProbeTool struct {
+	dir     string
+	pending int
+}
+
+func (p *journalProbeTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "exec"}}
+}
+
+func (p *journalProbeTool) Run(_ context.Context, _ json.RawMessage) string {
+	entries, _ := PendingJournalEntries(p.dir)
+	p.pending = len(entries)
+	return "done"
+}
+
+func TestRegistryRun_JournalsMutatingToolsOutsideDryRun(t *testing.T) {
+	dir := t.TempDir()
+	probe := &journalProbeTool{dir: dir}
+	r := NewRegistry()
+	r.SetToolJournal(NewToolJournal())
+	r.Register(probe)
+
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "cli", SessionDir: dir})
+	r.Run(ctx, "exec", json.RawMessage(`{}`))
+	if probe.pending != 1 {
+		t.Fatalf("pending during run = %d, want 1", probe.pending)
+	}
+	if pending, _ := PendingJournalEntries(dir); len(pending) != 0 {
+		t.Fatalf("pending after run = %+v, want none", pending)
+	}
+
+	dryCtx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "cli", SessionDir: dir, DryRun: true})
+	r.Run(dryCtx, "exec", json.RawMessage(`{}`))
+	if probe.pending != 0 {
+		t.Fatalf("dry run should not journal, pending during run = %d", probe.pending)
+	}
+}
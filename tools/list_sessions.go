@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+)
+
+const (
+	defaultListSessionsLimit = 20
+	maxListSessionsLimit     = 200
+)
+
+// SessionLister is implemented by session.Manager.
+type SessionLister interface {
+	List(limit int) ([]session.SessionSummary, error)
+}
+
+// ListSessionsTool lists stored sessions, most recently active first.
+// Admin-only: session keys reveal which users and chats the bot talks to.
+type ListSessionsTool struct {
+	lister SessionLister
+}
+
+// NewListSessionsTool creates the tool.
+func NewListSessionsTool(lister SessionLister) *ListSessionsTool {
+	return &ListSessionsTool{lister: lister}
+}
+
+// Def returns the tool definition.
+func (t *ListSessionsTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "list_sessions",
+			Description: "List stored sessions sorted by most recent activity, with key, channel, " +
+				"message count and last update time. Admin-only: available to turns from the " +
+				"configured admin, the local CLI, and system automation (cron/heartbeat). " +
+				"Use check_session for details on one session.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": fmt.Sprintf("Maximum sessions to return (default %d, max %d).", defaultListSessionsLimit, maxListSessionsLimit),
+					},
+					"channel": map[string]any{
+						"type":        "string",
+						"description": "Only list sessions on this channel (e.g. 'telegram', 'feishu', 'cli').",
+					},
+				},
+			},
+		},
+	}
+}
+
+type listSessionsArgs struct {
+	Limit   int    `json:"limit"`
+	Channel string `json:"channel"`
+}
+
+// RunResult executes the tool.
+func (t *ListSessionsTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a listSessionsArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	if !RuntimeContextFrom(ctx).Admin {
+		return ErrorResult("list_sessions", "admin only: this turn was not started by the admin, the local CLI, or system automation")
+	}
+	if t.lister == nil {
+		return ErrorResult("list_sessions", "session store not configured")
+	}
+
+	limit := a.Limit
+	if limit <= 0 {
+		limit = defaultListSessionsLimit
+	}
+	if limit > maxListSessionsLimit {
+		limit = maxListSessionsLimit
+	}
+	channel := strings.ToLower(strings.TrimSpace(a.Channel))
+
+	// Filtering happens after the store query, so fetch everything when a
+	// channel is given and trim afterwards.
+	fetch := limit
+	if channel != "" {
+		fetch = 0
+	}
+	summaries, err := t.lister.List(fetch)
+	if err != nil {
+		return ErrorResult("list_sessions", fmt.Sprintf("list sessions: %v", err))
+	}
+
+	var sb strings.Builder
+	shown := 0
+	for _, s := range summaries {
+		if channel != "" && s.Channel != channel {
+			continue
+		}
+		if shown == limit {
+			break
+		}
+		fmt.Fprintf(&sb, "- %s | channel=%s | messages=%d | updated=%s\n",
+			s.Key, s.Channel, s.MessageCount, s.UpdatedAt.Format(time.RFC3339))
+		shown++
+	}
+
+	fields := map[string]any{"count": shown}
+	if channel != "" {
+		fields["channel"] = channel
+	}
+	if shown == 0 {
+		return okResult("list_sessions", fields, "No sessions found.")
+	}
+	return okResult("list_sessions", fields, strings.TrimRight(sb.String(), "\n"))
+}
+
+// Run implements Tool.
+func (t *ListSessionsTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/session"
+)
+
+type mockSessionLister struct {
+	summaries []session.SessionSummary
+	gotLimit  int
+}
+
+func (m *mockSessionLister) List(limit int) ([]session.SessionSummary, error) {
+	m.gotLimit = limit
+	if limit > 0 && len(m.summaries) > limit {
+		return m.summaries[:limit], nil
+	}
+	return m.summaries, nil
+}
+
+func runListSessions(t *testing.T, lister SessionLister, admin bool, argsJSON string) string {
+	t.Helper()
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{Admin: admin})
+	return NewListSessionsTool(lister).Run(ctx, json.RawMessage(argsJSON))
+}
+
+func TestListSessions_RequiresAdmin(t *testing.T) {
+	lister := &mockSessionLister{summaries: []session.SessionSummary{{Key: "telegram:1", Channel: "telegram"}}}
+	res := runListSessions(t, lister, false, `{}`)
+	if !IsToolError(res) || !strings.Contains(res, "admin only") {
+		t.Fatalf("expected admin-only error, got: %s", res)
+	}
+	if strings.Contains(res, "telegram:1") {
+		t.Fatalf("non-admin result leaked session keys: %s", res)
+	}
+}
+
+func TestListSessions_ListsInStoreOrder(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	lister := &mockSessionLister{summaries: []session.SessionSummary{
+		{Key: "feishu:ou_admin", Channel: "feishu", MessageCount: 12, UpdatedAt: now},
+		{Key: "telegram:42", Channel: "telegram", MessageCount: 3, UpdatedAt: now.Add(-time.Hour)},
+	}}
+	res := runListSessions(t, lister, true, `{}`)
+	if IsToolError(res) {
+		t.Fatalf("unexpected error: %s", res)
+	}
+	if lister.gotLimit != defaultListSessionsLimit {
+		t.Fatalf("store limit = %d, want %d", lister.gotLimit, defaultListSessionsLimit)
+	}
+	first, second := strings.Index(res, "feishu:ou_admin"), strings.Index(res, "telegram:42")
+	if first < 0 || second < 0 || first > second {
+		t.Fatalf("expected feishu session before telegram session, got: %s", res)
+	}
+	if !strings.Contains(res, "messages=12") || !strings.Contains(res, "count: 2") {
+		t.Fatalf("missing fields in result: %s", res)
+	}
+}
+
+func TestListSessions_ChannelFilterAndLimit(t *testing.T) {
+	lister := &mockSessionLister{summaries: []session.SessionSummary{
+		{Key: "telegram:1", Channel: "telegram"},
+		{Key: "discord:9", Channel: "discord"},
+		{Key: "telegram:2", Channel: "telegram"},
+		{Key: "telegram:3", Channel: "telegram"},
+	}}
+	res := runListSessions(t, lister, true, `{"channel": "Telegram", "limit": 2}`)
+	if lister.gotLimit != 0 {
+		t.Fatalf("channel filter should fetch all sessions, got limit %d", lister.gotLimit)
+	}
+	if strings.Contains(res, "discord:9") || strings.Contains(res, "telegram:3") {
+		t.Fatalf("filter/limit not applied: %s", res)
+	}
+	if !strings.Contains(res, "telegram:1") || !strings.Contains(res, "telegram:2") {
+		t.Fatalf("expected telegram:1 and telegram:2, got: %s", res)
+	}
+}
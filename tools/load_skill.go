@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/skills"
+)
+
+// loadSkillMaxBytes bounds a skill file read from disk or a URL.
+const loadSkillMaxBytes = 1 << 20
+
+// validSkillSlug matches slugs safe to use as a directory name.
+var validSkillSlug = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SkillInstaller is implemented by skills.Registry.
+type SkillInstaller interface {
+	Install(dir, fileName, slug string, data []byte) (*skills.Skill, error)
+	Get(name string) (*skills.Skill, bool)
+}
+
+// LoadSkillTool adds a skill at runtime from a workspace file or an
+// allowlisted URL, persisting it to the skills directory.
+type LoadSkillTool struct {
+	installer           SkillInstaller
+	skillsDir           string
+	workspace           string
+	restrictToWorkspace bool
+	allowedHosts        []string
+}
+
+// NewLoadSkillTool creates a load_skill tool. URLs are accepted only when
+// their host (or a parent domain) is in allowedHosts.
+func NewLoadSkillTool(installer SkillInstaller, skillsDir, workspace string, restrictToWorkspace bool, allowedHosts []string) *LoadSkillTool {
+	return &LoadSkillTool{
+		installer:           installer,
+		skillsDir:           skillsDir,
+		workspace:           workspace,
+		restrictToWorkspace: restrictToWorkspace,
+		allowedHosts:        allowedHosts,
+	}
+}
+
+// Def returns the tool definition.
+func (t *LoadSkillTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "load_skill",
+			Description: "Add a skill at runtime from a YAML or Markdown skill file (a workspace path, or an http(s) URL on an allowlisted host). " +
+				"The skill is validated (needs a name and a prompt), saved to the skills directory so it survives restart, and available to use_skill immediately.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"source": map[string]any{
+						"type":        "string",
+						"description": "Workspace path or URL of the skill file (.md with optional frontmatter, or .yaml/.yml).",
+					},
+					"slug": map[string]any{
+						"type":        "string",
+						"description": "Invocation name for the skill. Defaults to the file name (or its directory for SKILL.md).",
+					},
+					"overwrite": map[string]any{
+						"type":        "boolean",
+						"description": "Replace an existing skill with the same slug.",
+					},
+				},
+				"required": []string{"source"},
+			},
+		},
+	}
+}
+
+type loadSkillArgs struct {
+	Source    string `json:"source" required:"true" alias:"path,url"`
+	Slug      string `json:"slug,omitempty" alias:"name"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *LoadSkillTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "load_skill", skillToolTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *LoadSkillTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *LoadSkillTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a loadSkillArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	if t.installer == nil || t.skillsDir == "" {
+		return ErrorResult("load_skill", "skills directory not configured")
+	}
+
+	source := strings.TrimSpace(a.Source)
+	var (
+		data     []byte
+		fileName string
+		err      error
+	)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, fileName, err = t.fetchURL(ctx, source)
+	} else {
+		data, fileName, err = t.readFile(source)
+	}
+	if err != nil {
+		return ErrorResult("load_skill", err.Error())
+	}
+
+	slug := strings.TrimSpace(a.Slug)
+	if slug == "" {
+		slug = slugFromFileName(fileName)
+	}
+	if !validSkillSlug.MatchString(slug) {
+		return ErrorResult("load_skill", fmt.Sprintf("invalid slug %q: use letters, digits, '.', '_' or '-'", slug))
+	}
+	if _, exists := t.installer.Get(slug); exists && !a.Overwrite {
+		return ErrorResult("load_skill", fmt.Sprintf("skill %q already exists; pass overwrite=true to replace it", slug))
+	}
+
+	if RuntimeContextFrom(ctx).DryRun {
+		skill, err := skills.ParseSkill(fileName, slug, data)
+		if err == nil {
+			err = skill.Validate()
+		}
+		if err != nil {
+			return ErrorResult("load_skill", fmt.Sprintf("invalid skill: %v", err))
+		}
+		return okResult("load_skill", map[string]any{
+			"slug":    skill.Slug,
+			"name":    skill.Name,
+			"source":  source,
+			"dry_run": true,
+		}, fmt.Sprintf("Would install skill %q into %s.", skill.Slug, t.skillsDir))
+	}
+
+	skill, err := t.installer.Install(t.skillsDir, fileName, slug, data)
+	if err != nil {
+		return ErrorResult("load_skill", fmt.Sprintf("invalid skill: %v", err))
+	}
+	return okResult("load_skill", map[string]any{
+		"slug":   skill.Slug,
+		"name":   skill.Name,
+		"source": source,
+	}, fmt.Sprintf("Skill %q loaded. Call use_skill(name=%q) to read its instructions.", skill.Slug, skill.Slug))
+}
+
+func (t *LoadSkillTool) readFile(source string) ([]byte, string, error) {
+	p := resolveToolPath(source, t.workspace)
+	resolved := absOrOriginal(p)
+	if t.restrictToWorkspace && !pathWithinWorkspace(resolved, t.workspace) {
+		return nil, "", fmt.Errorf("%s is outside workspace %q (restrictToWorkspace is enabled)", resolved, t.workspace)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read %s: %v", resolved, err)
+	}
+	defer f.Close()
+	data, err := readSkillBody(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read %s: %v", resolved, err)
+	}
+	return data, resolved, nil
+}
+
+func (t *LoadSkillTool) fetchURL(ctx context.Context, source string) ([]byte, string, error) {
+	u, err := url.Parse(source)
+	if err != nil || u.Hostname() == "" {
+		return nil, "", fmt.Errorf("invalid URL %q", source)
+	}
+	if !hostAllowed(u.Hostname(), t.allowedHosts) {
+		return nil, "", fmt.Errorf("host %q is not in tools.loadSkill.allowedHosts", u.Hostname())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	client := webHTTPClient(skillToolTimeout)
+	// Every redirect hop must stay on the allowlist too.
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if !hostAllowed(req.URL.Hostname(), t.allowedHosts) {
+			return fmt.Errorf("redirect to host %q is not in tools.loadSkill.allowedHosts", req.URL.Hostname())
+		}
+		return nil
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch returned %s", resp.Status)
+	}
+	data, err := readSkillBody(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, u.Path, nil
+}
+
+func readSkillBody(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, loadSkillMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > loadSkillMaxBytes {
+		return nil, fmt.Errorf("skill file exceeds %d KB", loadSkillMaxBytes>>10)
+	}
+	return data, nil
+}
+
+// hostAllowed reports whether host equals an allowlisted host or is a
+// subdomain of one.
+func hostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, h := range allowed {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" && (host == h || strings.HasSuffix(host, "."+h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// slugFromFileName derives a skill slug from a file path or URL path:
+// the file stem, or the parent directory for SKILL.md / SKILLS.md.
+func slugFromFileName(name string) string {
+	name = filepath.ToSlash(name)
+	base := path.Base(name)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	if strings.EqualFold(stem, "SKILL") || strings.EqualFold(stem, "SKILLS") {
+		return path.Base(path.Dir(name))
+	}
+	return stem
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+)
+
+// PinTool pins a note to the current session. Pinned notes are stored in the
+// session's meta.json and included in every system prompt, so they survive
+// compaction of the message history.
+type PinTool struct{}
+
+// Def returns the tool definition.
+func (t *PinTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "pin",
+			Description: "Pin a short note to this session: the user's real goal, hard constraints, decisions that must not be lost. " +
+				"Pinned notes appear in every prompt of the session and are never compacted or summarized away. " +
+				fmt.Sprintf("Keep them brief: at most %d pins and %d characters in total. ", session.MaxPins, session.MaxPinnedRunes) +
+				"Use unpin when a note no longer applies.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"text": map[string]any{
+						"type":        "string",
+						"description": "The note to pin, written as a self-contained fact.",
+					},
+				},
+				"required": []string{"text"},
+			},
+		},
+	}
+}
+
+type pinArgs struct {
+	Text string `json:"text" required:"true" alias:"note,content"`
+}
+
+// RunResult executes the tool.
+func (t *PinTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a pinArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	dir := RuntimeContextFrom(ctx).SessionDir
+	if dir == "" {
+		return ErrorResult("pin", "no active session")
+	}
+	pin, err := session.AddPin(dir, a.Text)
+	if err != nil {
+		return ErrorResult("pin", err.Error())
+	}
+	return okResult("pin", map[string]any{"id": pin.ID, "pins": len(session.ReadPins(dir))}, "Pinned.")
+}
+
+// Run implements Tool.
+func (t *PinTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// UnpinTool removes a pinned note from the current session.
+type UnpinTool struct{}
+
+// Def returns the tool definition.
+func (t *UnpinTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "unpin",
+			Description: "Remove a pinned note from this session by its ID (shown in brackets in the pinned notes and by list_pins).",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id": map[string]any{
+						"type":        "string",
+						"description": "Pin ID, e.g. p3.",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+	}
+}
+
+type unpinArgs struct {
+	ID string `json:"id" required:"true" alias:"pin_id"`
+}
+
+// RunResult executes the tool.
+func (t *UnpinTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a unpinArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	dir := RuntimeContextFrom(ctx).SessionDir
+	if dir == "" {
+		return ErrorResult("unpin", "no active session")
+	}
+	if !session.RemovePin(dir, a.ID) {
+		return ErrorResult("unpin", fmt.Sprintf("no pin with id %q; call list_pins to see the IDs", a.ID))
+	}
+	return okResult("unpin", map[string]any{"id": strings.TrimSpace(a.ID), "pins": len(session.ReadPins(dir))}, "Unpinned.")
+}
+
+// Run implements Tool.
+func (t *UnpinTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// ListPinsTool lists the pinned notes of the current session.
+type ListPinsTool struct{}
+
+// Def returns the tool definition.
+func (t *ListPinsTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "list_pins",
+			Description: "List this session's pinned notes with their IDs and when they were pinned.",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+	}
+}
+
+// RunResult executes the tool.
+func (t *ListPinsTool) RunResult(ctx context.Context, _ json.RawMessage) ToolResult {
+	dir := RuntimeContextFrom(ctx).SessionDir
+	if dir == "" {
+		return ErrorResult("list_pins", "no active session")
+	}
+	pins := session.ReadPins(dir)
+	if len(pins) == 0 {
+		return okResult("list_pins", map[string]any{"count": 0}, "No pinned notes.")
+	}
+	var sb strings.Builder
+	for _, p := range pins {
+		fmt.Fprintf(&sb, "- [%s] %s (pinned %s)\n", p.ID, p.Text, p.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return okResult("list_pins", map[string]any{"count": len(pins)}, strings.TrimRight(sb.String(), "\n"))
+}
+
+// Run implements Tool.
+func (t *ListPinsTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/session"
+)
+
+func runPinTool(t *testing.T, tool Tool, dir string, args map[string]any) string {
+	t.Helper()
+	raw, _ := json.Marshal(args)
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:1", SessionDir: dir})
+	return tool.Run(ctx, raw)
+}
+
+func TestPinListUnpin(t *testing.T) {
+	dir := t.TempDir()
+	out := runPinTool(t, &PinTool{}, dir, map[string]any{"text": "Budget is capped at $500."})
+	if !strings.Contains(out, "id: p1") {
+		t.Fatalf("pin = %q, want id p1", out)
+	}
+	runPinTool(t, &PinTool{}, dir, map[string]any{"text": "Reply in French."})
+
+	out = runPinTool(t, &ListPinsTool{}, dir, nil)
+	if !strings.Contains(out, "[p1] Budget is capped at $500.") || !strings.Contains(out, "[p2] Reply in French.") {
+		t.Errorf("list_pins = %q", out)
+	}
+
+	if out := runPinTool(t, &UnpinTool{}, dir, map[string]any{"id": "p1"}); strings.Contains(out, "Error") {
+		t.Fatalf("unpin = %q", out)
+	}
+	if out := runPinTool(t, &UnpinTool{}, dir, map[string]any{"id": "p1"}); !strings.Contains(out, "no pin") {
+		t.Errorf("second unpin = %q, want not-found error", out)
+	}
+	pins := session.ReadPins(dir)
+	if len(pins) != 1 || pins[0].ID != "p2" {
+		t.Errorf("pins after unpin = %+v", pins)
+	}
+}
+
+func TestPinBounds(t *testing.T) {
+	dir := t.TempDir()
+	if out := runPinTool(t, &PinTool{}, dir, map[string]any{"text": strings.Repeat("x", session.MaxPinnedRunes+1)}); !strings.Contains(out, "Error") {
+		t.Errorf("oversized pin accepted: %q", out)
+	}
+	for i := 0; i < session.MaxPins; i++ {
+		runPinTool(t, &PinTool{}, dir, map[string]any{"text": "note"})
+	}
+	if out := runPinTool(t, &PinTool{}, dir, map[string]any{"text": "one too many"}); !strings.Contains(out, "Error") {
+		t.Errorf("pin beyond MaxPins accepted: %q", out)
+	}
+	if n := len(session.ReadPins(dir)); n != session.MaxPins {
+		t.Errorf("pins = %d, want %d", n, session.MaxPins)
+	}
+}
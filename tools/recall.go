@@ -0,0 +1,390 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linanwx/nagobot/logger"
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+const (
+	// RecallIndexFileName is the per-session vector index over memory/ notes.
+	RecallIndexFileName = "recall_index.json"
+
+	recallDefaultK     = 5
+	recallMaxK         = 20
+	recallChunkRunes   = 2000 // longer sections are cut before embedding
+	recallSnippetRunes = 400
+	recallEmbedBatch   = 32 // chunks per Embed call, so a long note is not one oversized request
+)
+
+// recallIndex caches chunk embeddings per memory file. A file is re-chunked
+// and re-embedded only when its size or modification time changes.
+type recallIndex struct {
+	Model string                     `json:"model"`
+	Files map[string]recallIndexFile `json:"files"` // memory file name → chunks
+}
+
+type recallIndexFile struct {
+	ModTime time.Time     `json:"mod_time"`
+	Size    int64         `json:"size"`
+	Chunks  []recallChunk `json:"chunks"`
+}
+
+// recallChunk is one searchable unit: the frontmatter summary of a memory
+// file or one of its "## heading" sections.
+type recallChunk struct {
+	Heading string    `json:"heading"`
+	Text    string    `json:"text"`
+	Vector  []float32 `json:"vector,omitempty"`
+}
+
+type recallHit struct {
+	File  string
+	Chunk recallChunk
+	Score float64
+}
+
+// RecallTool searches the session's memory notes by meaning when an Embedder
+// is configured, and by keyword otherwise.
+type RecallTool struct {
+	embedder Embedder
+	mu       sync.Mutex // serializes index updates
+}
+
+// NewRecallTool creates a recall tool. A nil embedder means keyword search.
+func NewRecallTool(embedder Embedder) *RecallTool {
+	return &RecallTool{embedder: embedder}
+}
+
+// Def returns the tool definition.
+func (t *RecallTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "recall",
+			Description: "Search this session's memory notes (memory/*.md: past conversation summaries and saved notes) and return the most relevant sections. " +
+				"Matches by meaning when embeddings are configured, otherwise by keyword. Use it before answering questions about earlier conversations.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "What to look for, in natural language.",
+					},
+					"k": map[string]any{
+						"type":        "integer",
+						"description": fmt.Sprintf("Number of results (default %d, max %d).", recallDefaultK, recallMaxK),
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+	}
+}
+
+type recallArgs struct {
+	Query string `json:"query" required:"true" alias:"q,text"`
+	K     int    `json:"k,omitempty" alias:"limit,top_k"`
+}
+
+// RunResult executes the tool.
+func (t *RecallTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a recallArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	query := strings.TrimSpace(a.Query)
+	if query == "" {
+		return ErrorResult("recall", "query is empty")
+	}
+	k := a.K
+	if k <= 0 {
+		k = recallDefaultK
+	}
+	k = min(k, recallMaxK)
+	dir := RuntimeContextFrom(ctx).SessionDir
+	if dir == "" {
+		return ErrorResult("recall", "no active session")
+	}
+
+	fields := map[string]any{"query": query}
+	var hits []recallHit
+	if t.embedder != nil && t.embedder.Available() {
+		var err error
+		hits, err = t.semanticSearch(ctx, dir, query, k)
+		if err != nil {
+			fields["fallback"] = err.Error()
+			hits = nil
+		} else {
+			fields["mode"] = "semantic"
+		}
+	}
+	if fields["mode"] == nil {
+		fields["mode"] = "keyword"
+		chunks, err := loadMemoryChunks(filepath.Join(dir, session.MemoryDirName))
+		if err != nil {
+			return ErrorResult("recall", err.Error())
+		}
+		hits = keywordSearch(chunks, query, k)
+	}
+	fields["results"] = len(hits)
+	if len(hits) == 0 {
+		return okResult("recall", fields, "No matching memory notes.")
+	}
+	return okResult("recall", fields, formatRecallHits(hits))
+}
+
+// Run implements Tool.
+func (t *RecallTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// semanticSearch brings the session's index up to date and returns the k
+// chunks most similar to query.
+func (t *RecallTool) semanticSearch(ctx context.Context, sessionDir, query string, k int) ([]recallHit, error) {
+	t.mu.Lock()
+	idx, err := updateRecallIndex(ctx, sessionDir, t.embedder)
+	t.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	qv, err := t.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(qv) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for the query", len(qv))
+	}
+	var hits []recallHit
+	for name, f := range idx.Files {
+		for _, c := range f.Chunks {
+			hits = append(hits, recallHit{File: name, Chunk: c, Score: cosineSimilarity(qv[0], c.Vector)})
+		}
+	}
+	return topRecallHits(hits, k), nil
+}
+
+// updateRecallIndex loads <sessionDir>/recall_index.json, embeds memory
+// files that are new or changed since the last run, drops deleted ones, and
+// saves the index when anything changed. A file whose embedding fails is
+// skipped (keeping its previous entry, if any) and retried on the next run;
+// the error is returned only when no changed file could be embedded.
+func updateRecallIndex(ctx context.Context, sessionDir string, embedder Embedder) (*recallIndex, error) {
+	indexPath := filepath.Join(sessionDir, RecallIndexFileName)
+	idx := &recallIndex{}
+	if data, err := os.ReadFile(indexPath); err == nil {
+		_ = json.Unmarshal(data, idx)
+	}
+	model := embedder.Model()
+	if idx.Model != model || idx.Files == nil {
+		idx = &recallIndex{Model: model, Files: make(map[string]recallIndexFile)}
+	}
+
+	memoryDir := filepath.Join(sessionDir, session.MemoryDirName)
+	entries, err := os.ReadDir(memoryDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read memory directory: %w", err)
+	}
+	changed := false
+	var embedErr error
+	embedded := 0
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		seen[e.Name()] = true
+		if f, ok := idx.Files[e.Name()]; ok && f.Size == info.Size() && f.ModTime.Equal(info.ModTime()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(memoryDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		chunks := chunkMemoryNote(string(data))
+		if err := embedRecallChunks(ctx, embedder, chunks); err != nil {
+			logger.Warn("recall: embedding memory note failed, skipping", "file", e.Name(), "err", err)
+			if embedErr == nil {
+				embedErr = err
+			}
+			continue
+		}
+		idx.Files[e.Name()] = recallIndexFile{ModTime: info.ModTime(), Size: info.Size(), Chunks: chunks}
+		changed = true
+		embedded++
+	}
+	for name := range idx.Files {
+		if !seen[name] {
+			delete(idx.Files, name)
+			changed = true
+		}
+	}
+	if changed {
+		data, err := json.Marshal(idx)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(indexPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("write recall index: %w", err)
+		}
+	}
+	if embedErr != nil && embedded == 0 {
+		return nil, embedErr
+	}
+	return idx, nil
+}
+
+// embedRecallChunks sets each chunk's Vector, embedding at most
+// recallEmbedBatch chunks per request.
+func embedRecallChunks(ctx context.Context, embedder Embedder, chunks []recallChunk) error {
+	for start := 0; start < len(chunks); start += recallEmbedBatch {
+		batch := chunks[start:min(start+recallEmbedBatch, len(chunks))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = c.Heading + "\n" + c.Text
+		}
+		vecs, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return err
+		}
+		if len(vecs) != len(batch) {
+			return fmt.Errorf("embedder returned %d vectors for %d chunks", len(vecs), len(batch))
+		}
+		for i := range batch {
+			batch[i].Vector = vecs[i]
+		}
+	}
+	return nil
+}
+
+// loadMemoryChunks reads and chunks every memory file, keyed by file name.
+func loadMemoryChunks(memoryDir string) (map[string][]recallChunk, error) {
+	entries, err := os.ReadDir(memoryDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read memory directory: %w", err)
+	}
+	out := make(map[string][]recallChunk)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(memoryDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		out[e.Name()] = chunkMemoryNote(string(data))
+	}
+	return out, nil
+}
+
+// chunkMemoryNote splits a memory file into its frontmatter summary and its
+// "## heading" sections (the layout written by session.AppendMemory).
+func chunkMemoryNote(content string) []recallChunk {
+	var chunks []recallChunk
+	body := content
+	if yamlBlock, rest, ok := msg.SplitFrontmatter(content); ok {
+		body = rest
+		if summary := msg.ExtractFrontmatterValue(yamlBlock, "summary"); summary != "" {
+			chunks = append(chunks, recallChunk{Heading: "summary", Text: summary})
+		}
+	}
+	heading := ""
+	var sb strings.Builder
+	flush := func() {
+		text := strings.TrimSpace(sb.String())
+		sb.Reset()
+		if text == "" {
+			return
+		}
+		if r := []rune(text); len(r) > recallChunkRunes {
+			text = string(r[:recallChunkRunes])
+		}
+		chunks = append(chunks, recallChunk{Heading: heading, Text: text})
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			heading = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	flush()
+	return chunks
+}
+
+// keywordSearch ranks chunks by the fraction of query terms they contain.
+func keywordSearch(files map[string][]recallChunk, query string, k int) []recallHit {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	var hits []recallHit
+	for name, chunks := range files {
+		for _, c := range chunks {
+			text := strings.ToLower(c.Heading + "\n" + c.Text)
+			matched := 0
+			for _, term := range terms {
+				if strings.Contains(text, term) {
+					matched++
+				}
+			}
+			if matched > 0 {
+				hits = append(hits, recallHit{File: name, Chunk: c, Score: float64(matched) / float64(len(terms))})
+			}
+		}
+	}
+	return topRecallHits(hits, k)
+}
+
+// topRecallHits sorts hits by score, newest file first on ties, and keeps k.
+func topRecallHits(hits []recallHit, k int) []recallHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].File > hits[j].File
+	})
+	if len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+func formatRecallHits(hits []recallHit) string {
+	var sb strings.Builder
+	for i, h := range hits {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		label := h.File
+		if h.Chunk.Heading != "" {
+			label += " § " + h.Chunk.Heading
+		}
+		text := h.Chunk.Text
+		if r := []rune(text); len(r) > recallSnippetRunes {
+			text = string(r[:recallSnippetRunes]) + "..."
+		}
+		fmt.Fprintf(&sb, "- %s%s (score %.2f)\n%s", session.MemoryDirName+"/", label, h.Score, text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubEmbedder maps text to a fixed vector: one dimension per keyword, set
+// when the text mentions it.
+type stubEmbedder struct {
+	keywords []string
+	embedded int // texts embedded so far, queries included
+}
+
+func (e *stubEmbedder) Model() string   { return "stub" }
+func (e *stubEmbedder) Available() bool { return true }
+
+func (e *stubEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.embedded += len(texts)
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, len(e.keywords))
+		for j, kw := range e.keywords {
+			if strings.Contains(strings.ToLower(text), kw) {
+				v[j] = 1
+			}
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func writeMemoryNote(t *testing.T, sessionDir, name, content string) {
+	t.Helper()
+	dir := filepath.Join(sessionDir, "memory")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runRecall(t *testing.T, tool *RecallTool, sessionDir, query string) string {
+	t.Helper()
+	raw, _ := json.Marshal(map[string]any{"query": query, "k": 2})
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:1", SessionDir: sessionDir})
+	return tool.Run(ctx, raw)
+}
+
+func TestRecallNearestNeighbors(t *testing.T) {
+	dir := t.TempDir()
+	writeMemoryNote(t, dir, "2026-10-01.md", "## Trip 09:00\n\nBooked flights to Lisbon, window seat.\n\n## Cooking 18:00\n\nTried a pasta recipe with basil.\n")
+	writeMemoryNote(t, dir, "2026-10-02.md", "---\nsummary: Planned the garden beds\n---\n## Garden 10:00\n\nTomatoes go in the south bed.\n")
+
+	emb := &stubEmbedder{keywords: []string{"flight", "lisbon", "pasta", "basil", "garden", "tomato"}}
+	tool := NewRecallTool(emb)
+	out := runRecall(t, tool, dir, "which flight to lisbon")
+	if !strings.Contains(out, "mode: semantic") {
+		t.Fatalf("recall = %q, want semantic mode", out)
+	}
+	first := strings.Index(out, "Trip")
+	if first < 0 || strings.Contains(out, "Cooking") {
+		t.Errorf("recall did not rank the trip note first:\n%s", out)
+	}
+	if garden := strings.Index(out, "Garden"); garden >= 0 && garden < first {
+		t.Errorf("unrelated note ranked above the match:\n%s", out)
+	}
+
+	// Unchanged files are not embedded again; only the query is.
+	before := emb.embedded
+	runRecall(t, tool, dir, "garden tomato")
+	if got := emb.embedded - before; got != 1 {
+		t.Errorf("second recall embedded %d texts, want only the query", got)
+	}
+
+	// A changed file is re-embedded on the next search.
+	writeMemoryNote(t, dir, "2026-10-01.md", "## Trip 09:00\n\nBooked flights to Lisbon.\n\n## Basil 20:00\n\nBasil pesto.\n")
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(filepath.Join(dir, "memory", "2026-10-01.md"), future, future)
+	before = emb.embedded
+	if out := runRecall(t, tool, dir, "basil"); !strings.Contains(out, "Basil pesto") {
+		t.Errorf("recall after edit = %q", out)
+	}
+	if got := emb.embedded - before; got != 3 {
+		t.Errorf("recall after edit embedded %d texts, want 2 chunks + query", got)
+	}
+}
+
+func TestRecallKeywordFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeMemoryNote(t, dir, "2026-10-01.md", "## Trip 09:00\n\nBooked flights to Lisbon.\n\n## Cooking 18:00\n\nPasta night.\n")
+
+	out := runRecall(t, NewRecallTool(nil), dir, "lisbon flights")
+	if !strings.Contains(out, "mode: keyword") || !strings.Contains(out, "Booked flights to Lisbon.") || strings.Contains(out, "Pasta") {
+		t.Errorf("keyword recall = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, RecallIndexFileName)); err == nil {
+		t.Error("keyword search should not write an index")
+	}
+}
+
+// flakyEmbedder fails any batch that mentions "poison" and records the
+// largest batch it was asked to embed.
+type flakyEmbedder struct {
+	stubEmbedder
+	maxBatch int
+}
+
+func (e *flakyEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.maxBatch = max(e.maxBatch, len(texts))
+	for _, text := range texts {
+		if strings.Contains(text, "poison") {
+			return nil, errors.New("embeddings API error: HTTP 400")
+		}
+	}
+	return e.stubEmbedder.Embed(ctx, texts)
+}
+
+func TestRecallSkipsFailedNoteAndBatchesChunks(t *testing.T) {
+	dir := t.TempDir()
+	writeMemoryNote(t, dir, "2026-10-01.md", "## Trip 09:00\n\nBooked flights to Lisbon.\n")
+	writeMemoryNote(t, dir, "2026-10-02.md", "## Bad 10:00\n\npoison pill\n")
+	var long strings.Builder
+	for i := 0; i < recallEmbedBatch*2+1; i++ {
+		fmt.Fprintf(&long, "## Garden %02d:00\n\nTomatoes in bed %d.\n\n", i%24, i)
+	}
+	writeMemoryNote(t, dir, "2026-10-03.md", long.String())
+
+	emb := &flakyEmbedder{stubEmbedder: stubEmbedder{keywords: []string{"flight", "lisbon", "tomato"}}}
+	out := runRecall(t, NewRecallTool(emb), dir, "which flight to lisbon")
+	if !strings.Contains(out, "mode: semantic") || !strings.Contains(out, "Booked flights to Lisbon.") {
+		t.Fatalf("recall = %q, want a semantic hit despite the failing note", out)
+	}
+	if emb.maxBatch > recallEmbedBatch {
+		t.Errorf("largest Embed batch = %d, want at most %d", emb.maxBatch, recallEmbedBatch)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, RecallIndexFileName))
+	if err != nil {
+		t.Fatalf("index not saved after a partial failure: %v", err)
+	}
+	var idx recallIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := idx.Files["2026-10-02.md"]; ok {
+		t.Error("failed note was indexed")
+	}
+	if got := len(idx.Files["2026-10-03.md"].Chunks); got != recallEmbedBatch*2+1 {
+		t.Errorf("long note has %d indexed chunks, want %d", got, recallEmbedBatch*2+1)
+	}
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+	"github.com/linanwx/nagobot/provider"
+)
+
+// maxReminderDelay caps how far ahead a reminder may be scheduled.
+const maxReminderDelay = 366 * 24 * time.Hour
+
+// reminderDayPrefix matches a leading day component, e.g. "1d" or "1.5d2h".
+var reminderDayPrefix = regexp.MustCompile(`^(\d+(?:\.\d+)?)d`)
+
+// JobAdder schedules cron jobs. *channel.CronChannel satisfies it.
+type JobAdder interface {
+	AddJob(job cronpkg.Job) error
+}
+
+// RemindTool schedules a one-shot reminder that wakes the calling session
+// after a relative delay. The absolute time is computed server-side so the
+// model never has to do timezone arithmetic.
+type RemindTool struct {
+	jobs JobAdder
+	now  func() time.Time
+}
+
+// NewRemindTool creates a remind tool backed by the cron scheduler.
+func NewRemindTool(jobs JobAdder) *RemindTool {
+	return &RemindTool{jobs: jobs, now: time.Now}
+}
+
+// Def returns the tool definition.
+func (t *RemindTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "remind",
+			Description: "Schedule a one-time reminder that wakes THIS session after a delay, e.g. \"remind me in 30 minutes\". " +
+				"The delay is relative to now (30m, 2h, 1h30m, 1d, 2d6h) — no timestamp or timezone math needed. " +
+				"When it fires you receive the message as a cron wake; use dispatch(to=user) to pass it on. " +
+				"Returns the resolved absolute time and the job id (cancel it with cancel_reminder).",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"delay": map[string]any{
+						"type":        "string",
+						"description": "How long from now: a Go duration (30m, 2h, 1h30m) optionally led by days (1d, 2d6h).",
+					},
+					"message": map[string]any{
+						"type":        "string",
+						"description": "What to remind about. Delivered back to this session when the reminder fires.",
+					},
+				},
+				"required": []string{"delay", "message"},
+			},
+		},
+	}
+}
+
+type remindArgs struct {
+	Delay   string `json:"delay" required:"true" alias:"in,after,duration"`
+	Message string `json:"message" required:"true" alias:"task,text"`
+}
+
+// RunResult executes the tool.
+func (t *RemindTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a remindArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	if rt.SessionKey == "" {
+		return ErrorResult("remind", "reminders need a session to wake; none is active")
+	}
+	message := strings.TrimSpace(a.Message)
+	if message == "" {
+		return ErrorResult("remind", "message is required")
+	}
+	delay, err := parseReminderDelay(a.Delay)
+	if err != nil {
+		return ErrorResult("remind", err.Error())
+	}
+
+	at := t.now().Add(delay)
+	job := cronpkg.Job{
+		ID:                "remind-" + randomHex(4),
+		Kind:              cronpkg.JobKindAt,
+		AtTime:            &at,
+		Task:              "Reminder you set with remind(delay=" + strings.TrimSpace(a.Delay) + "): " + message,
+		WakeSession:       rt.SessionKey,
+		DirectWake:        true,
+		CatchUp:           true,
+		CreatorSessionKey: rt.SessionKey,
+		Admin:             rt.Admin, // the reminder wakes with the creating turn's privileges
+	}
+	fields := map[string]any{
+		"job_id": job.ID,
+		"at":     at.Format(time.RFC3339),
+		"delay":  delay.String(),
+	}
+	if rt.DryRun {
+		fields["dry_run"] = true
+		return okResult("remind", fields, "Would schedule a reminder for "+rt.SessionKey+".")
+	}
+	if t.jobs == nil {
+		return ErrorResult("remind", "cron scheduler not available")
+	}
+	if err := t.jobs.AddJob(job); err != nil {
+		return ErrorResult("remind", fmt.Sprintf("failed to schedule reminder: %v", err))
+	}
+	return okResult("remind", fields, "Reminder scheduled for "+at.Format(time.RFC3339)+".")
+}
+
+// Run implements Tool.
+func (t *RemindTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// parseReminderDelay parses a time.ParseDuration string optionally led by a
+// day component ("1d", "2d6h", "1.5d").
+func parseReminderDelay(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	if s == "" {
+		return 0, fmt.Errorf("delay is required")
+	}
+	var d time.Duration
+	rest := s
+	if m := reminderDayPrefix.FindStringSubmatch(s); m != nil {
+		days, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid delay %q: %v", s, err)
+		}
+		d = time.Duration(days * float64(24*time.Hour))
+		rest = s[len(m[0]):]
+	}
+	if rest != "" {
+		extra, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid delay %q: use forms like 30m, 2h, 1h30m, 1d", s)
+		}
+		d += extra
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("delay must be positive")
+	}
+	if d > maxReminderDelay {
+		return 0, fmt.Errorf("delay must be at most %s", maxReminderDelay)
+	}
+	return d, nil
+}
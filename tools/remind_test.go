@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+)
+
+type recordingJobAdder struct {
+	jobs []cronpkg.Job
+}
+
+func (r *recordingJobAdder) AddJob(job cronpkg.Job) error {
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func TestRemindResolvesDelay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 22, 45, 0, 0, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		delay string
+		want  time.Time
+	}{
+		{"30m", now.Add(30 * time.Minute)},
+		{"1d", now.Add(24 * time.Hour)},
+		{"1d2h", now.Add(26 * time.Hour)},
+	}
+	for _, tt := range tests {
+		jobs := &recordingJobAdder{}
+		tool := NewRemindTool(jobs)
+		tool.now = func() time.Time { return now }
+		ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42"})
+
+		args, _ := json.Marshal(map[string]string{"delay": tt.delay, "message": "stretch"})
+		out := tool.Run(ctx, args)
+		if strings.Contains(out, "status: error") {
+			t.Fatalf("%s: %s", tt.delay, out)
+		}
+		if !strings.Contains(out, tt.want.Format(time.RFC3339)) {
+			t.Errorf("%s: result missing resolved time %s:\n%s", tt.delay, tt.want.Format(time.RFC3339), out)
+		}
+		if len(jobs.jobs) != 1 {
+			t.Fatalf("%s: jobs = %d, want 1", tt.delay, len(jobs.jobs))
+		}
+		job := jobs.jobs[0]
+		if job.Kind != cronpkg.JobKindAt || !job.AtTime.Equal(tt.want) {
+			t.Errorf("%s: job = %+v, want at %s", tt.delay, job, tt.want)
+		}
+		if job.WakeSession != "telegram:42" || !job.DirectWake || !strings.Contains(job.Task, "stretch") {
+			t.Errorf("%s: job should wake the creator session: %+v", tt.delay, job)
+		}
+	}
+}
+
+func TestParseReminderDelayRejects(t *testing.T) {
+	for _, s := range []string{"", "soon", "-5m", "0s", "400d"} {
+		if _, err := parseReminderDelay(s); err == nil {
+			t.Errorf("parseReminderDelay(%q) should fail", s)
+		}
+	}
+}
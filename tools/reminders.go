@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+	"github.com/linanwx/nagobot/provider"
+)
+
+// JobManager lists and removes cron jobs. *channel.CronChannel satisfies it.
+type JobManager interface {
+	ListJobs() []cronpkg.Job
+	RemoveJob(id string) (bool, error)
+}
+
+// jobCreator returns the session that created job. Reminders stored before
+// CreatorSessionKey existed fall back to the session they wake.
+func jobCreator(job cronpkg.Job) string {
+	if job.CreatorSessionKey != "" {
+		return job.CreatorSessionKey
+	}
+	if job.DirectWake && strings.HasPrefix(job.ID, "remind-") {
+		return job.WakeSession
+	}
+	return ""
+}
+
+// pendingJobsBy returns the one-shot jobs created by sessionKey, soonest first.
+func pendingJobsBy(jobs []cronpkg.Job, sessionKey string) []cronpkg.Job {
+	var out []cronpkg.Job
+	for _, j := range jobs {
+		if j.Kind == cronpkg.JobKindAt && j.AtTime != nil && jobCreator(j) == sessionKey {
+			out = append(out, j)
+		}
+	}
+	sort.SliceStable(out, func(i, k int) bool { return out[i].AtTime.Before(*out[k].AtTime) })
+	return out
+}
+
+// ListRemindersTool lists the pending one-shot jobs (remind, schedule_message)
+// created by the current session.
+type ListRemindersTool struct {
+	jobs JobManager
+}
+
+// NewListRemindersTool creates a list_reminders tool backed by the cron scheduler.
+func NewListRemindersTool(jobs JobManager) *ListRemindersTool {
+	return &ListRemindersTool{jobs: jobs}
+}
+
+// Def returns the tool definition.
+func (t *ListRemindersTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "list_reminders",
+			Description: "List the pending reminders and scheduled messages THIS session created with remind or schedule_message, " +
+				"soonest first, with their job ids and scheduled times. Use cancel_reminder to remove one.",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+	}
+}
+
+// RunResult executes the tool.
+func (t *ListRemindersTool) RunResult(ctx context.Context, _ json.RawMessage) ToolResult {
+	rt := RuntimeContextFrom(ctx)
+	if rt.SessionKey == "" {
+		return ErrorResult("list_reminders", "no active session")
+	}
+	if t.jobs == nil {
+		return ErrorResult("list_reminders", "cron scheduler not available")
+	}
+	jobs := pendingJobsBy(t.jobs.ListJobs(), rt.SessionKey)
+	if len(jobs) == 0 {
+		return okResult("list_reminders", map[string]any{"count": 0}, "No pending reminders.")
+	}
+	var sb strings.Builder
+	for _, j := range jobs {
+		fmt.Fprintf(&sb, "- [%s] %s: %s\n", j.ID, j.AtTime.Local().Format(time.RFC3339), j.Task)
+	}
+	return okResult("list_reminders", map[string]any{"count": len(jobs)}, strings.TrimRight(sb.String(), "\n"))
+}
+
+// Run implements Tool.
+func (t *ListRemindersTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// CancelReminderTool removes a pending one-shot job created by the current
+// session. Admin turns may cancel any one-shot job.
+type CancelReminderTool struct {
+	jobs JobManager
+}
+
+// NewCancelReminderTool creates a cancel_reminder tool backed by the cron scheduler.
+func NewCancelReminderTool(jobs JobManager) *CancelReminderTool {
+	return &CancelReminderTool{jobs: jobs}
+}
+
+// Def returns the tool definition.
+func (t *CancelReminderTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "cancel_reminder",
+			Description: "Cancel a pending reminder or scheduled message this session created, by job id (from remind, schedule_message or list_reminders). Returns the time it was scheduled for.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id": map[string]any{
+						"type":        "string",
+						"description": "Job id, e.g. remind-1a2b3c4d.",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+	}
+}
+
+type cancelReminderArgs struct {
+	ID string `json:"id" required:"true" alias:"job_id"`
+}
+
+// RunResult executes the tool.
+func (t *CancelReminderTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a cancelReminderArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	if rt.SessionKey == "" && !rt.Admin {
+		return ErrorResult("cancel_reminder", "no active session")
+	}
+	if t.jobs == nil {
+		return ErrorResult("cancel_reminder", "cron scheduler not available")
+	}
+	id := strings.TrimSpace(a.ID)
+	var job *cronpkg.Job
+	for _, j := range t.jobs.ListJobs() {
+		if j.ID == id && j.Kind == cronpkg.JobKindAt && j.AtTime != nil {
+			job = &j
+			break
+		}
+	}
+	if job == nil || (!rt.Admin && jobCreator(*job) != rt.SessionKey) {
+		return ErrorResult("cancel_reminder", fmt.Sprintf("no pending reminder %q from this session; call list_reminders to see the ids", id))
+	}
+
+	at := job.AtTime.Local().Format(time.RFC3339)
+	fields := map[string]any{"job_id": id, "at": at}
+	if rt.DryRun {
+		fields["dry_run"] = true
+		return okResult("cancel_reminder", fields, "Would cancel the reminder scheduled for "+at+".")
+	}
+	removed, err := t.jobs.RemoveJob(id)
+	if err != nil {
+		return ErrorResult("cancel_reminder", fmt.Sprintf("failed to cancel reminder: %v", err))
+	}
+	if !removed {
+		return ErrorResult("cancel_reminder", fmt.Sprintf("reminder %q already fired or was removed", id))
+	}
+	return okResult("cancel_reminder", fields, "Cancelled the reminder scheduled for "+at+": "+job.Task)
+}
+
+// Run implements Tool.
+func (t *CancelReminderTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+)
+
+type fakeJobManager struct {
+	jobs []cronpkg.Job
+}
+
+func (f *fakeJobManager) ListJobs() []cronpkg.Job { return f.jobs }
+
+func (f *fakeJobManager) RemoveJob(id string) (bool, error) {
+	for i, j := range f.jobs {
+		if j.ID == id {
+			f.jobs = append(f.jobs[:i], f.jobs[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestListRemindersFiltersByCreator(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	later := soon.Add(time.Hour)
+	jobs := &fakeJobManager{jobs: []cronpkg.Job{
+		{ID: "remind-b", Kind: cronpkg.JobKindAt, AtTime: &later, Task: "call mom", CreatorSessionKey: "telegram:1"},
+		{ID: "remind-other", Kind: cronpkg.JobKindAt, AtTime: &soon, Task: "not mine", CreatorSessionKey: "telegram:2"},
+		{ID: "msg-a", Kind: cronpkg.JobKindAt, AtTime: &soon, Task: "standup", Deliver: true, CreatorSessionKey: "telegram:1"},
+		{ID: "daily", Kind: cronpkg.JobKindCron, Expr: "0 9 * * *", Task: "digest", CreatorSessionKey: "telegram:1"},
+		{ID: "remind-legacy", Kind: cronpkg.JobKindAt, AtTime: &later, Task: "old", WakeSession: "telegram:1", DirectWake: true},
+	}}
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:1"})
+
+	out := NewListRemindersTool(jobs).Run(ctx, json.RawMessage(`{}`))
+	if !strings.Contains(out, "count: 3") {
+		t.Fatalf("want 3 reminders for telegram:1:\n%s", out)
+	}
+	if strings.Contains(out, "remind-other") || strings.Contains(out, "daily") {
+		t.Errorf("listed another session's job or a recurring job:\n%s", out)
+	}
+	if strings.Index(out, "msg-a") > strings.Index(out, "remind-b") {
+		t.Errorf("reminders not sorted soonest first:\n%s", out)
+	}
+
+	cancel := NewCancelReminderTool(jobs)
+	if out := cancel.Run(ctx, json.RawMessage(`{"id":"remind-other"}`)); !IsToolError(out) {
+		t.Errorf("cancelled another session's reminder:\n%s", out)
+	}
+	out = cancel.Run(ctx, json.RawMessage(`{"id":"remind-b"}`))
+	if IsToolError(out) || !strings.Contains(out, later.Format(time.RFC3339)) {
+		t.Errorf("cancel should confirm the scheduled time %s:\n%s", later.Format(time.RFC3339), out)
+	}
+	if len(jobs.jobs) != 4 {
+		t.Errorf("jobs left = %d, want 4", len(jobs.jobs))
+	}
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ToolResult is the structured outcome of a tool call. IsError is explicit,
+// so a successful result whose text mentions "Error" is never mistaken for a
+// failure, and the runner can flag real failures to the model.
+type ToolResult struct {
+	Content string
+	IsError bool
+	// Mime is the media type of Content; empty means text. Reserved for
+	// tools that return non-text content.
+	Mime string
+}
+
+// ResultTool is implemented by tools that report a ToolResult directly; all
+// built-in tools do. Registry.RunResult prefers RunResult over Run for them.
+type ResultTool interface {
+	Tool
+	RunResult(ctx context.Context, args json.RawMessage) ToolResult
+}
+
+// TextResult adapts a string-returning tool's output: an error when it is a
+// toolError result (status: error) or a legacy "Error:"-prefixed string, a
+// plain result otherwise.
+func TextResult(content string) ToolResult {
+	return ToolResult{Content: content, IsError: IsToolError(content)}
+}
+
+// ErrorResult builds an error ToolResult in the toolError format.
+func ErrorResult(tool, message string) ToolResult {
+	return ToolResult{Content: toolError(tool, message), IsError: true}
+}
+
+// okResult builds a successful ToolResult in the toolResult format.
+func okResult(tool string, fields map[string]any, body string) ToolResult {
+	return ToolResult{Content: toolResult(tool, fields, body)}
+}
+
+// errorText marks already formatted error text (e.g. from parseArgs) as a
+// failed ToolResult.
+func errorText(content string) ToolResult {
+	return ToolResult{Content: content, IsError: true}
+}
+
+// runTool calls t, preferring the structured ResultTool path.
+func runTool(ctx context.Context, t Tool, args json.RawMessage) ToolResult {
+	if rt, ok := t.(ResultTool); ok {
+		return rt.RunResult(ctx, args)
+	}
+	return TextResult(t.Run(ctx, args))
+}
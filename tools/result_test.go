@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// stubTool returns a fixed string from Run.
+type stubTool struct {
+	name string
+	out  string
+}
+
+func (s *stubTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: s.name}}
+}
+
+func (s *stubTool) Run(context.Context, json.RawMessage) string { return s.out }
+
+// structuredTool reports its outcome through RunResult.
+type structuredTool struct {
+	stubTool
+	res ToolResult
+}
+
+func (s *structuredTool) RunResult(context.Context, json.RawMessage) ToolResult { return s.res }
+
+func TestRegistryRunResult_ErrorFlag(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubTool{name: "fails", out: toolError("fails", "boom")})
+	r.Register(&stubTool{name: "legacy", out: "Error: old style"})
+	r.Register(&stubTool{name: "grep_like", out: toolResult("grep_like", map[string]any{"matches": 1}, "main.go:3: Error: bad input")})
+	r.Register(&structuredTool{stubTool: stubTool{name: "typed", out: "unused"}, res: ToolResult{Content: "quota exhausted", IsError: true}})
+
+	cases := map[string]bool{"fails": true, "legacy": true, "grep_like": false, "typed": true, "missing": true}
+	for name, want := range cases {
+		res := r.RunResult(context.Background(), name, json.RawMessage(`{}`))
+		if res.IsError != want {
+			t.Errorf("%s: IsError = %v, want %v (content %q)", name, res.IsError, want, res.Content)
+		}
+	}
+	if got := r.Run(context.Background(), "typed", json.RawMessage(`{}`)); got != "quota exhausted" {
+		t.Errorf("Run on a ResultTool = %q, want its RunResult content", got)
+	}
+}
+
+func TestDefaultToolsReportStructuredResults(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterDefaultTools(t.TempDir(), DefaultToolsConfig{})
+	for name, tool := range r.tools {
+		if _, ok := tool.(ResultTool); !ok {
+			t.Errorf("%s does not implement ResultTool", name)
+		}
+	}
+
+	res := r.RunResult(context.Background(), "read_file", json.RawMessage(`{"path":"missing.txt"}`))
+	if !res.IsError {
+		t.Errorf("read_file on a missing file: IsError = false (content %q)", res.Content)
+	}
+	res = r.RunResult(context.Background(), "read_file", json.RawMessage(`{"path":"missing.txt","bogus":1}`))
+	if !res.IsError {
+		t.Errorf("read_file with an unknown argument: IsError = false (content %q)", res.Content)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"path/filepath"
 	"strings"
+
+	"github.com/linanwx/nagobot/thread/msg"
 )
 
 type runtimeContextKey struct{}
@@ -19,6 +21,29 @@ type RuntimeContext struct {
 	ImageReaderConfigured  bool // true if an 'imagereader' agent is available
 	AudioReaderConfigured  bool // true if an 'audioreader' agent is available
 	PDFReaderConfigured    bool // true if a 'pdfreader' agent is available
+	DryRun                 bool // true if mutating tools should describe instead of act
+	Admin                  bool // true if the turn may use admin-only tools
+
+	// SendFile uploads a file to the chat the current turn replies to.
+	// Nil when the turn's sink cannot deliver files.
+	SendFile func(ctx context.Context, path, caption string) error
+
+	// SendEmbed delivers a structured reply to the chat the current turn
+	// replies to. Nil when the turn's sink has no chat destination.
+	SendEmbed func(ctx context.Context, embed msg.Embed) error
+
+	// BeginDraft holds the turn's user-facing replies until FinalizeDraft.
+	// Nil when the turn's sink has no chat destination.
+	BeginDraft func()
+
+	// FinalizeDraft sends the complete reply once and ends draft mode. An
+	// empty text sends the replies held since BeginDraft.
+	FinalizeDraft func(ctx context.Context, text string) error
+
+	// ModelOverrideChanged tells the thread that set_model or reset_model
+	// rewrote the session's model override, dropping its cached copy.
+	// Nil outside a thread turn.
+	ModelOverrideChanged func()
 }
 
 // WithRuntimeContext injects tool runtime metadata into context.
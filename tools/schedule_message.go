@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+	"github.com/linanwx/nagobot/provider"
+)
+
+// ScheduleMessageTool posts a fixed message to a channel recipient at a later
+// time via a deliver-mode cron at job: no agent turn runs when it fires.
+// Admin-only: it can message any chat the bot can reach.
+type ScheduleMessageTool struct {
+	jobs JobAdder
+	now  func() time.Time
+}
+
+// NewScheduleMessageTool creates a schedule_message tool backed by the cron scheduler.
+func NewScheduleMessageTool(jobs JobAdder) *ScheduleMessageTool {
+	return &ScheduleMessageTool{jobs: jobs, now: time.Now}
+}
+
+// Def returns the tool definition.
+func (t *ScheduleMessageTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "schedule_message",
+			Description: "Schedule a message to be posted verbatim to a channel recipient at a later time, e.g. \"I'll remind the group at 5pm\". " +
+				"No agent turn runs when it fires — the text is sent as-is. " +
+				"when is an RFC3339 timestamp (2026-03-01T17:00:00+08:00) or a delay from now (30m, 2h, 1d). " +
+				"Returns the resolved absolute time and the job id (cancel it with cancel_reminder). " +
+				"Admin-only. To wake this session later instead, use remind.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"channel": map[string]any{
+						"type":        "string",
+						"description": "Target channel name: telegram, discord, feishu, wecom, socket.",
+					},
+					"to": map[string]any{
+						"type":        "string",
+						"description": "Channel-specific recipient, e.g. a Telegram chat ID or Discord channel ID.",
+					},
+					"when": map[string]any{
+						"type":        "string",
+						"description": "RFC3339 time, or a delay from now (30m, 2h, 1h30m, 1d).",
+					},
+					"text": map[string]any{
+						"type":        "string",
+						"description": "The message to post.",
+					},
+				},
+				"required": []string{"channel", "to", "when", "text"},
+			},
+		},
+	}
+}
+
+type scheduleMessageArgs struct {
+	Channel string `json:"channel" required:"true"`
+	To      string `json:"to" required:"true" alias:"recipient,chat_id"`
+	When    string `json:"when" required:"true" alias:"at,time,delay"`
+	Text    string `json:"text" required:"true" alias:"message"`
+}
+
+// RunResult executes the tool.
+func (t *ScheduleMessageTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a scheduleMessageArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	if !rt.Admin {
+		return ErrorResult("schedule_message", "admin only: this turn was not started by the admin, the local CLI, or system automation")
+	}
+	channel := strings.ToLower(strings.TrimSpace(a.Channel))
+	to := strings.TrimSpace(a.To)
+	text := strings.TrimSpace(a.Text)
+	if channel == "" || to == "" || text == "" {
+		return ErrorResult("schedule_message", "channel, to and text are required")
+	}
+	at, err := t.resolveWhen(a.When)
+	if err != nil {
+		return ErrorResult("schedule_message", err.Error())
+	}
+
+	job := cronpkg.Job{
+		ID:                "msg-" + randomHex(4),
+		Kind:              cronpkg.JobKindAt,
+		AtTime:            &at,
+		Task:              text,
+		Deliver:           true,
+		Channel:           channel,
+		To:                to,
+		CatchUp:           true,
+		CreatorSessionKey: rt.SessionKey,
+		Admin:             rt.Admin,
+	}
+	fields := map[string]any{
+		"job_id":  job.ID,
+		"at":      at.Format(time.RFC3339),
+		"channel": channel,
+		"to":      to,
+	}
+	if rt.DryRun {
+		fields["dry_run"] = true
+		return okResult("schedule_message", fields, "Would schedule a message to "+channel+":"+to+".")
+	}
+	if t.jobs == nil {
+		return ErrorResult("schedule_message", "cron scheduler not available")
+	}
+	if err := t.jobs.AddJob(job); err != nil {
+		return ErrorResult("schedule_message", fmt.Sprintf("failed to schedule message: %v", err))
+	}
+	return okResult("schedule_message", fields, "Message scheduled for "+at.Format(time.RFC3339)+".")
+}
+
+// Run implements Tool.
+func (t *ScheduleMessageTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// resolveWhen parses when as an RFC3339 time or a delay from now, bounded
+// like remind delays.
+func (t *ScheduleMessageTool) resolveWhen(when string) (time.Time, error) {
+	when = strings.TrimSpace(when)
+	now := t.now()
+	if at, err := time.Parse(time.RFC3339, when); err == nil {
+		if !at.After(now) {
+			return time.Time{}, fmt.Errorf("when %s is in the past", when)
+		}
+		if at.Sub(now) > maxReminderDelay {
+			return time.Time{}, fmt.Errorf("when must be at most %s from now", maxReminderDelay)
+		}
+		return at, nil
+	}
+	delay, err := parseReminderDelay(when)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid when %q: use an RFC3339 time or a delay like 30m, 2h, 1d", when)
+	}
+	return now.Add(delay), nil
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+)
+
+func TestScheduleMessageCreatesDeliveryJob(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		when string
+		want time.Time
+	}{
+		{"2026-03-01T17:00:00+08:00", time.Date(2026, 3, 1, 17, 0, 0, 0, time.FixedZone("CST", 8*3600))},
+		{"2h", now.Add(2 * time.Hour)},
+	}
+	for _, tt := range tests {
+		jobs := &recordingJobAdder{}
+		tool := NewScheduleMessageTool(jobs)
+		tool.now = func() time.Time { return now }
+		ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "cli", Admin: true})
+
+		args, _ := json.Marshal(map[string]string{"channel": "Telegram", "to": "-100123", "when": tt.when, "text": "Standup in 10 minutes"})
+		out := tool.Run(ctx, args)
+		if !strings.Contains(out, tt.want.Format(time.RFC3339)) {
+			t.Fatalf("%s: result missing resolved time %s:\n%s", tt.when, tt.want.Format(time.RFC3339), out)
+		}
+		if len(jobs.jobs) != 1 {
+			t.Fatalf("%s: jobs = %d, want 1", tt.when, len(jobs.jobs))
+		}
+		job := jobs.jobs[0]
+		if job.Kind != cronpkg.JobKindAt || !job.AtTime.Equal(tt.want) {
+			t.Errorf("%s: job = %+v, want at %s", tt.when, job, tt.want)
+		}
+		if !job.Deliver || job.Channel != "telegram" || job.To != "-100123" || job.Task != "Standup in 10 minutes" ||
+			job.WakeSession != "" || job.Agent != "" {
+			t.Errorf("%s: want a deliver-only job, got %+v", tt.when, job)
+		}
+		if ok, _ := cronpkg.ValidateStored(job, now); !ok {
+			t.Errorf("%s: job would be dropped on load: %+v", tt.when, job)
+		}
+	}
+}
+
+func TestScheduleMessageRejects(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	jobs := &recordingJobAdder{}
+	tool := NewScheduleMessageTool(jobs)
+	tool.now = func() time.Time { return now }
+	run := func(admin bool, when string) string {
+		ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42", Admin: admin})
+		args, _ := json.Marshal(map[string]string{"channel": "telegram", "to": "42", "when": when, "text": "hi"})
+		return tool.Run(ctx, args)
+	}
+
+	if out := run(false, "1h"); !strings.Contains(out, "admin only") {
+		t.Errorf("non-admin turn should be rejected, got:\n%s", out)
+	}
+	if out := run(true, "2026-03-01T08:00:00Z"); !strings.Contains(out, "in the past") {
+		t.Errorf("past time should be rejected, got:\n%s", out)
+	}
+	if out := run(true, "tomorrow"); !strings.Contains(out, "invalid when") {
+		t.Errorf("unparseable when should be rejected, got:\n%s", out)
+	}
+	if len(jobs.jobs) != 0 {
+		t.Errorf("rejected calls created jobs: %+v", jobs.jobs)
+	}
+}
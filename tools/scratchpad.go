@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// scratchpadMaxBytes caps one session's scratchpad.
+const scratchpadMaxBytes = 32 * 1024
+
+// ScratchpadTool gives the model private working notes for the current turn.
+// Notes are kept in memory per session key, never enter memory/ or the
+// session history, and are dropped when the turn ends (see Clear).
+type ScratchpadTool struct {
+	mu   sync.Mutex
+	pads map[string]string // session key → notes
+}
+
+// NewScratchpadTool creates an empty scratchpad store.
+func NewScratchpadTool() *ScratchpadTool {
+	return &ScratchpadTool{pads: make(map[string]string)}
+}
+
+// Def returns the tool definition.
+func (t *ScratchpadTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "scratchpad",
+			Description: "Private working notes for multi-step tasks in this turn: plans, intermediate results, checklists. " +
+				"Notes cost no context until you read them back with get, are never shown to the user or saved, and are cleared when the turn ends. " +
+				"Use memory files for anything that must outlive the turn.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"action": map[string]any{
+						"type":        "string",
+						"enum":        []string{"set", "append", "get", "clear"},
+						"description": "set replaces the notes, append adds a line, get returns them, clear empties them.",
+					},
+					"text": map[string]any{
+						"type":        "string",
+						"description": "Text for set/append.",
+					},
+				},
+				"required": []string{"action"},
+			},
+		},
+	}
+}
+
+type scratchpadArgs struct {
+	Action string `json:"action" required:"true" alias:"op,operation"`
+	Text   string `json:"text,omitempty" alias:"content,note"`
+}
+
+// RunResult executes the tool.
+func (t *ScratchpadTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a scratchpadArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	key := RuntimeContextFrom(ctx).SessionKey
+	if key == "" {
+		return ErrorResult("scratchpad", "no active session")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	notes := t.pads[key]
+
+	switch action := strings.ToLower(strings.TrimSpace(a.Action)); action {
+	case "set", "append":
+		next := a.Text
+		if action == "append" && notes != "" {
+			next = notes + "\n" + a.Text
+		}
+		if len(next) > scratchpadMaxBytes {
+			return ErrorResult("scratchpad", fmt.Sprintf("notes would be %d bytes, over the %d byte limit; set a condensed version instead", len(next), scratchpadMaxBytes))
+		}
+		t.pads[key] = next
+		return okResult("scratchpad", map[string]any{"action": action, "bytes": len(next)}, "Saved.")
+	case "get":
+		if notes == "" {
+			return okResult("scratchpad", map[string]any{"action": action, "bytes": 0}, "Scratchpad is empty.")
+		}
+		return okResult("scratchpad", map[string]any{"action": action, "bytes": len(notes)}, notes)
+	case "clear":
+		delete(t.pads, key)
+		return okResult("scratchpad", map[string]any{"action": action}, "Cleared.")
+	default:
+		return ErrorResult("scratchpad", fmt.Sprintf("unknown action %q: use set, append, get, or clear", a.Action))
+	}
+}
+
+// Run implements Tool.
+func (t *ScratchpadTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// Clear drops the notes for sessionKey. Threads call it when a turn ends.
+func (t *ScratchpadTool) Clear(sessionKey string) {
+	t.mu.Lock()
+	delete(t.pads, sessionKey)
+	t.mu.Unlock()
+}
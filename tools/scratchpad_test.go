@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runScratchpad(t *testing.T, tool *ScratchpadTool, sessionKey string, args map[string]any) string {
+	t.Helper()
+	raw, _ := json.Marshal(args)
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: sessionKey})
+	return tool.Run(ctx, raw)
+}
+
+func TestScratchpadSetAppendGet(t *testing.T) {
+	tool := NewScratchpadTool()
+	runScratchpad(t, tool, "telegram:1", map[string]any{"action": "set", "text": "step 1: read config"})
+	runScratchpad(t, tool, "telegram:1", map[string]any{"action": "append", "text": "step 2: patch"})
+
+	out := runScratchpad(t, tool, "telegram:1", map[string]any{"action": "get"})
+	if !strings.Contains(out, "step 1: read config\nstep 2: patch") {
+		t.Errorf("get = %q, want both notes", out)
+	}
+
+	runScratchpad(t, tool, "telegram:1", map[string]any{"action": "clear"})
+	if out := runScratchpad(t, tool, "telegram:1", map[string]any{"action": "get"}); !strings.Contains(out, "empty") {
+		t.Errorf("get after clear = %q, want empty", out)
+	}
+}
+
+func TestScratchpadIsolatedPerSession(t *testing.T) {
+	tool := NewScratchpadTool()
+	runScratchpad(t, tool, "telegram:1", map[string]any{"action": "set", "text": "secret plan"})
+
+	if out := runScratchpad(t, tool, "discord:2", map[string]any{"action": "get"}); strings.Contains(out, "secret plan") {
+		t.Errorf("other session read the notes: %q", out)
+	}
+
+	tool.Clear("discord:2")
+	if out := runScratchpad(t, tool, "telegram:1", map[string]any{"action": "get"}); !strings.Contains(out, "secret plan") {
+		t.Errorf("clearing another session dropped the notes: %q", out)
+	}
+	tool.Clear("telegram:1")
+	if out := runScratchpad(t, tool, "telegram:1", map[string]any{"action": "get"}); strings.Contains(out, "secret plan") {
+		t.Errorf("notes survived Clear: %q", out)
+	}
+}
+
+func TestScratchpadSizeLimit(t *testing.T) {
+	tool := NewScratchpadTool()
+	out := runScratchpad(t, tool, "telegram:1", map[string]any{"action": "set", "text": strings.Repeat("x", scratchpadMaxBytes+1)})
+	if !IsToolError(out) {
+		t.Errorf("oversized set should fail, got %q", out)
+	}
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// bingUserAgent is Bing's default User-Agent; Bing serves a usable page to
+// Windows browsers.
+const bingUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+
 // bingSearcher is the shared implementation for Bing search variants.
 type bingSearcher struct {
 	host string // "cn.bing.com" or "www.bing.com"
@@ -22,12 +26,12 @@ func (b *bingSearcher) search(ctx context.Context, query string, maxResults int)
 	}
 	searchURL := fmt.Sprintf("https://%s/search?q=%s&count=%d", b.host, url.QueryEscape(query), maxResults)
 
-	client := &http.Client{Timeout: webSearchHTTPTimeout}
+	client := webHTTPClient(webSearchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", webUserAgent(ctx, bingUserAgent))
 
 	resp, err := client.Do(req)
 	if err != nil {
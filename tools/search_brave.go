@@ -29,7 +29,7 @@ func (p *BraveSearchProvider) Search(ctx context.Context, query string, maxResul
 	endpoint := fmt.Sprintf("https://api.search.brave.com/res/v1/web/search?q=%s&count=%d",
 		url.QueryEscape(query), maxResults)
 
-	client := &http.Client{Timeout: webSearchHTTPTimeout}
+	client := webHTTPClient(webSearchHTTPTimeout)
 	// Retry once after 2s on HTTP 429 (Brave Free plan 1 req/s). Typical cause:
 	// LLM fires multiple searches in parallel within one turn.
 	for attempt := range 2 {
@@ -21,12 +21,12 @@ func (p *DuckDuckGoProvider) Available() bool { return true }
 func (p *DuckDuckGoProvider) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
 	searchURL := fmt.Sprintf("https://html.duckduckgo.com/html/?q=%s", url.QueryEscape(query))
 
-	client := &http.Client{Timeout: webSearchHTTPTimeout}
+	client := webHTTPClient(webSearchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", webUserAgent(ctx, defaultWebUserAgent))
 
 	resp, err := client.Do(req)
 	if err != nil {
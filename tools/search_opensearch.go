@@ -61,7 +61,7 @@ func (p *OpenSearchProvider) Search(ctx context.Context, query string, maxResult
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	client := &http.Client{Timeout: webSearchHTTPTimeout}
+	client := webHTTPClient(webSearchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearXNGProvider searches via a self-hosted (or public) SearXNG instance's
+// JSON API. The instance must have the "json" format enabled in its
+// settings.yml (search.formats).
+type SearXNGProvider struct {
+	// URLFn returns the instance base URL at call time (supports runtime config changes).
+	URLFn func() string
+}
+
+func (p *SearXNGProvider) Name() string    { return "searxng" }
+func (p *SearXNGProvider) Tags() []string  { return []string{"free", "self-hosted", "metasearch"} }
+func (p *SearXNGProvider) Available() bool { return p.URLFn != nil && p.URLFn() != "" }
+
+func (p *SearXNGProvider) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	base := ""
+	if p.URLFn != nil {
+		base = strings.TrimRight(p.URLFn(), "/")
+	}
+	if base == "" {
+		return nil, fmt.Errorf("SearXNG instance URL not configured. Use the manage-config skill to set it up")
+	}
+
+	endpoint := fmt.Sprintf("%s/search?q=%s&format=json", base, url.QueryEscape(query))
+
+	client := webHTTPClient(webSearchHTTPTimeout)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("search request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if resp.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("SearXNG API error: HTTP 403 (is the json format enabled on the instance?): %s", string(body))
+		}
+		return nil, fmt.Errorf("SearXNG API error: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return parseSearXNGResults(body, maxResults)
+}
+
+// searxngResponse is the SearXNG /search?format=json response.
+type searxngResponse struct {
+	Results []searxngResult `json:"results"`
+}
+
+type searxngResult struct {
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	Content       string   `json:"content"`
+	Engines       []string `json:"engines"`
+	PublishedDate string   `json:"publishedDate"`
+}
+
+func parseSearXNGResults(data []byte, maxResults int) ([]SearchResult, error) {
+	var resp searxngResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse SearXNG response: %w", err)
+	}
+
+	results := make([]SearchResult, 0, maxResults)
+	for _, r := range resp.Results {
+		if len(results) >= maxResults {
+			break
+		}
+		if r.URL == "" {
+			continue
+		}
+		results = append(results, SearchResult{
+			Title:       r.Title,
+			URL:         r.URL,
+			Snippet:     r.Content,
+			PublishDate: r.PublishedDate,
+			Source:      strings.Join(r.Engines, ","),
+		})
+	}
+	return results, nil
+}
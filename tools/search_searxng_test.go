@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const searxngFixture = `{
+	"query": "golang",
+	"number_of_results": 0,
+	"results": [
+		{"title": "The Go Programming Language", "url": "https://go.dev/", "content": "Go is an open source programming language.", "engines": ["google", "duckduckgo"], "score": 4.5},
+		{"title": "No URL", "url": "", "content": "dropped"},
+		{"title": "Go (programming language) - Wikipedia", "url": "https://en.wikipedia.org/wiki/Go_(programming_language)", "content": "Go is a statically typed language.", "engines": ["wikipedia"], "publishedDate": "2024-03-01T00:00:00"}
+	]
+}`
+
+func TestSearXNGProviderSearch(t *testing.T) {
+	var gotPath, gotQuery, gotFormat string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("q")
+		gotFormat = r.URL.Query().Get("format")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(searxngFixture))
+	}))
+	defer srv.Close()
+
+	p := &SearXNGProvider{URLFn: func() string { return srv.URL + "/" }}
+	if !p.Available() {
+		t.Fatal("provider with a URL should be available")
+	}
+	results, err := p.Search(context.Background(), "golang generics", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if gotPath != "/search" || gotQuery != "golang generics" || gotFormat != "json" {
+		t.Errorf("request = %s q=%q format=%q, want /search q=\"golang generics\" format=json", gotPath, gotQuery, gotFormat)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (entries without a URL are skipped)", len(results))
+	}
+	first := results[0]
+	if first.Title != "The Go Programming Language" || first.URL != "https://go.dev/" || first.Snippet != "Go is an open source programming language." {
+		t.Errorf("first result = %+v", first)
+	}
+	if first.Source != "google,duckduckgo" {
+		t.Errorf("Source = %q, want %q", first.Source, "google,duckduckgo")
+	}
+	if results[1].PublishDate != "2024-03-01T00:00:00" {
+		t.Errorf("PublishDate = %q", results[1].PublishDate)
+	}
+
+	capped, err := p.Search(context.Background(), "golang", 1)
+	if err != nil || len(capped) != 1 {
+		t.Errorf("maxResults=1: got %d results, err %v", len(capped), err)
+	}
+}
+
+func TestSearXNGProviderErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	p := &SearXNGProvider{URLFn: func() string { return srv.URL }}
+	if _, err := p.Search(context.Background(), "q", 5); err == nil || !strings.Contains(err.Error(), "json format") {
+		t.Errorf("403 error = %v, want a hint about the json format", err)
+	}
+
+	unset := &SearXNGProvider{URLFn: func() string { return "" }}
+	if unset.Available() {
+		t.Error("provider without a URL should not be available")
+	}
+	if _, err := unset.Search(context.Background(), "q", 5); err == nil {
+		t.Error("expected an error without a URL")
+	}
+}
+
+type namedSearchProvider struct {
+	name  string
+	calls int
+}
+
+func (p *namedSearchProvider) Name() string    { return p.name }
+func (p *namedSearchProvider) Tags() []string  { return nil }
+func (p *namedSearchProvider) Available() bool { return true }
+func (p *namedSearchProvider) Search(_ context.Context, query string, _ int) ([]SearchResult, error) {
+	p.calls++
+	return []SearchResult{{Title: p.name + ": " + query, URL: "https://example.com/" + p.name}}, nil
+}
+
+func TestWebSearchBackendSelection(t *testing.T) {
+	ddg := &namedSearchProvider{name: "duckduckgo"}
+	searx := &namedSearchProvider{name: "searxng"}
+	providers := map[string]SearchProvider{"duckduckgo": ddg, "searxng": searx}
+
+	run := func(tool *WebSearchTool, args map[string]any) string {
+		raw, _ := json.Marshal(args)
+		return tool.Run(context.Background(), raw)
+	}
+
+	tool := NewWebSearchTool(5, providers, "searxng", nil, "", time.Minute)
+	out := run(tool, map[string]any{"query": "golang"})
+	if searx.calls != 1 || ddg.calls != 0 {
+		t.Fatalf("calls searxng=%d duckduckgo=%d, want the configured backend only", searx.calls, ddg.calls)
+	}
+	if !strings.Contains(out, "searxng: golang") {
+		t.Errorf("output missing searxng result:\n%s", out)
+	}
+
+	// An explicit source overrides the backend.
+	run(tool, map[string]any{"query": "golang", "source": "duckduckgo"})
+	if ddg.calls != 1 {
+		t.Errorf("duckduckgo calls = %d, want 1 for an explicit source", ddg.calls)
+	}
+
+	// Without a backend the source stays required.
+	noDefault := NewWebSearchTool(5, providers, "", nil, "", time.Minute)
+	if out := run(noDefault, map[string]any{"query": "golang"}); !strings.Contains(out, "source is required") {
+		t.Errorf("expected source error without a backend:\n%s", out)
+	}
+}
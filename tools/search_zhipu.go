@@ -48,7 +48,7 @@ func (p *ZhipuSearchProvider) Search(ctx context.Context, query string, maxResul
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	client := &http.Client{Timeout: webSearchHTTPTimeout}
+	client := webHTTPClient(webSearchHTTPTimeout)
 	req, err := http.NewRequestWithContext(ctx, "POST", "https://open.bigmodel.cn/api/paas/v4/web_search", bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/thread/msg"
+)
+
+// SendEmbedTool delivers a structured report (title, description, fields) to
+// the current chat. Discord renders it as an embed; other channels get the
+// same content as Markdown text.
+type SendEmbedTool struct{}
+
+// Def returns the tool definition.
+func (t *SendEmbedTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "send_embed",
+			Description: "Send a structured report to the current chat: a title, a description, and up to 25 name/value fields per embed. Discord renders it as a rich embed (long reports are split across several); other channels receive it as Markdown text. Use for status reports, summaries, and comparisons that read better as labelled fields than as a wall of text. Your normal reply is still delivered, so keep it short when the embed carries the content.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"title": map[string]any{
+						"type":        "string",
+						"description": "Report title (up to 256 characters).",
+					},
+					"description": map[string]any{
+						"type":        "string",
+						"description": "Main body text, Markdown allowed.",
+					},
+					"fields": map[string]any{
+						"type":        "array",
+						"description": "Labelled values shown below the description.",
+						"items": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"name":   map[string]any{"type": "string", "description": "Field label."},
+								"value":  map[string]any{"type": "string", "description": "Field content, Markdown allowed."},
+								"inline": map[string]any{"type": "boolean", "description": "Show side by side with neighbouring inline fields."},
+							},
+							"required": []string{"name", "value"},
+						},
+					},
+					"color": map[string]any{
+						"type":        "string",
+						"description": "Optional accent color as hex, e.g. \"#2ecc71\".",
+					},
+				},
+				"required": []string{"title"},
+			},
+		},
+	}
+}
+
+type sendEmbedArgs struct {
+	Title       string           `json:"title" required:"true"`
+	Description string           `json:"description,omitempty" alias:"body,content"`
+	Fields      []sendEmbedField `json:"fields,omitempty"`
+	Color       string           `json:"color,omitempty"`
+}
+
+type sendEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *SendEmbedTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "send_embed", sendEmbedTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *SendEmbedTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *SendEmbedTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a sendEmbedArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	embed := msg.Embed{Title: strings.TrimSpace(a.Title), Description: strings.TrimSpace(a.Description)}
+	for _, f := range a.Fields {
+		if strings.TrimSpace(f.Name) == "" && strings.TrimSpace(f.Value) == "" {
+			continue
+		}
+		embed.Fields = append(embed.Fields, msg.EmbedField{Name: f.Name, Value: f.Value, Inline: f.Inline})
+	}
+	if a.Color != "" {
+		c, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(a.Color), "#"), 16, 24)
+		if err != nil {
+			return ErrorResult("send_embed", fmt.Sprintf("invalid color %q: use a hex value like \"#2ecc71\"", a.Color))
+		}
+		embed.Color = int(c)
+	}
+
+	rt := RuntimeContextFrom(ctx)
+	if rt.SendEmbed == nil {
+		return ErrorResult("send_embed", "the current chat cannot receive reports; put the report in your reply instead")
+	}
+
+	fields := map[string]any{
+		"title":  embed.Title,
+		"fields": len(embed.Fields),
+	}
+	if rt.DryRun {
+		fields["dry_run"] = true
+		return okResult("send_embed", fields, "Dry run: would send the report to the current chat. Nothing was sent.")
+	}
+
+	if err := rt.SendEmbed(ctx, embed); err != nil {
+		return ErrorResult("send_embed", fmt.Sprintf("failed to send report: %v", err))
+	}
+	return okResult("send_embed", fields, "Report sent to the current chat.")
+}
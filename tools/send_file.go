@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// SendFileTool uploads a workspace file to the chat the current turn replies to.
+type SendFileTool struct {
+	workspace           string
+	restrictToWorkspace bool
+}
+
+// NewSendFileTool creates a send_file tool rooted at workspace.
+func NewSendFileTool(workspace string, restrictToWorkspace bool) *SendFileTool {
+	return &SendFileTool{workspace: workspace, restrictToWorkspace: restrictToWorkspace}
+}
+
+// Def returns the tool definition.
+func (t *SendFileTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "send_file",
+			Description: "Send a local file to the current chat as an attachment (Telegram document, Discord upload, Feishu file/image). Relative paths are resolved from workspace root. Use this when the user asks for a file rather than its contents. Fails on channels that cannot upload files or when the file exceeds the channel's size limit.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "The file path to send.",
+					},
+					"caption": map[string]any{
+						"type":        "string",
+						"description": "Optional short text shown with the file.",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
+	}
+}
+
+type sendFileArgs struct {
+	Path    string `json:"path" required:"true" alias:"file,file_path"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *SendFileTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "send_file", sendFileTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *SendFileTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *SendFileTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a sendFileArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	path := resolveToolPath(a.Path, t.workspace)
+	resolvedPath := absOrOriginal(path)
+	if t.restrictToWorkspace && !pathWithinWorkspace(resolvedPath, t.workspace) {
+		return ErrorResult("send_file", fmt.Sprintf("%s is outside workspace %q (restrictToWorkspace is enabled)", resolvedPath, t.workspace))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return ErrorResult("send_file", fmt.Sprintf("cannot access %s: %v", resolvedPath, err))
+	}
+	if !info.Mode().IsRegular() {
+		return ErrorResult("send_file", fmt.Sprintf("%s is not a regular file", resolvedPath))
+	}
+
+	rt := RuntimeContextFrom(ctx)
+	if rt.SendFile == nil {
+		return ErrorResult("send_file", "the current chat does not support file uploads; share the path or contents in your reply instead")
+	}
+
+	fields := map[string]any{
+		"path":  resolvedPath,
+		"bytes": info.Size(),
+	}
+	if rt.DryRun {
+		fields["dry_run"] = true
+		return okResult("send_file", fields, fmt.Sprintf("Dry run: would send %s (%d bytes) to the current chat. Nothing was sent.", resolvedPath, info.Size()))
+	}
+
+	if err := rt.SendFile(ctx, path, a.Caption); err != nil {
+		return ErrorResult("send_file", fmt.Sprintf("failed to send %s: %v", resolvedPath, err))
+	}
+	return okResult("send_file", fields, "File sent to the current chat.")
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSendFileRestrictToWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	inside := filepath.Join(workspace, "report.txt")
+	if err := os.WriteFile(inside, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []string
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{
+		SendFile: func(_ context.Context, path, _ string) error {
+			sent = append(sent, path)
+			return nil
+		},
+	})
+	run := func(tool *SendFileTool, path string) string {
+		args, _ := json.Marshal(map[string]any{"path": path})
+		return tool.Run(ctx, args)
+	}
+
+	restricted := NewSendFileTool(workspace, true)
+	for _, path := range []string{outside, "../" + filepath.Base(filepath.Dir(outside)) + "/secret.txt"} {
+		if out := run(restricted, path); !strings.Contains(out, "outside workspace") {
+			t.Errorf("send %s: expected workspace error, got:\n%s", path, out)
+		}
+	}
+	if len(sent) != 0 {
+		t.Fatalf("files outside the workspace were sent: %v", sent)
+	}
+
+	if out := run(restricted, "report.txt"); strings.Contains(out, "status: error") {
+		t.Fatalf("send inside workspace failed:\n%s", out)
+	}
+	if len(sent) != 1 || sent[0] != inside {
+		t.Errorf("sent = %v, want [%s]", sent, inside)
+	}
+
+	// Without the restriction, absolute paths elsewhere are allowed.
+	if out := run(NewSendFileTool(workspace, false), outside); strings.Contains(out, "status: error") {
+		t.Fatalf("unrestricted send failed:\n%s", out)
+	}
+	if len(sent) != 2 {
+		t.Errorf("sent = %v, want 2 files", sent)
+	}
+}
+
+func TestSendFileUnsupportedChannel(t *testing.T) {
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := json.Marshal(map[string]any{"path": "a.txt"})
+	out := NewSendFileTool(workspace, true).Run(context.Background(), args)
+	if !strings.Contains(out, "does not support file uploads") {
+		t.Errorf("expected unsupported-channel error, got:\n%s", out)
+	}
+}
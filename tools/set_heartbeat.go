@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+	"github.com/linanwx/nagobot/provider"
+)
+
+const (
+	minHeartbeatInterval = 5 * time.Minute
+	maxHeartbeatInterval = 7 * 24 * time.Hour
+)
+
+// JobScheduler adds and removes cron jobs. *channel.CronChannel satisfies it.
+type JobScheduler interface {
+	JobAdder
+	RemoveJob(id string) (bool, error)
+}
+
+// SetHeartbeatTool lets the agent schedule its own recurring check-ins on the
+// calling session. Each session has at most one such job; setting it again
+// replaces the previous one.
+type SetHeartbeatTool struct {
+	jobs JobScheduler
+}
+
+// NewSetHeartbeatTool creates a set_heartbeat tool backed by the cron scheduler.
+func NewSetHeartbeatTool(jobs JobScheduler) *SetHeartbeatTool {
+	return &SetHeartbeatTool{jobs: jobs}
+}
+
+// Def returns the tool definition.
+func (t *SetHeartbeatTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "set_heartbeat",
+			Description: "Schedule a recurring silent check-in that wakes THIS session every interval, e.g. to watch a long-running job or follow up on pending work. " +
+				"When it fires you receive the task as a heartbeat wake: nothing reaches the user unless you dispatch(to=user). " +
+				"Setting it again replaces this session's previous heartbeat; pass clear=true to stop it.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"interval": map[string]any{
+						"type":        "string",
+						"description": "Time between check-ins: a Go duration (30m, 2h, 1h30m) optionally led by days (1d). Between 5m and 7d.",
+					},
+					"task": map[string]any{
+						"type":        "string",
+						"description": "What to check on each wake. Delivered back to this session.",
+					},
+					"clear": map[string]any{
+						"type":        "boolean",
+						"description": "Remove this session's heartbeat instead of setting one.",
+					},
+				},
+			},
+		},
+	}
+}
+
+type setHeartbeatArgs struct {
+	Interval string `json:"interval" alias:"every,delay"`
+	Task     string `json:"task" alias:"message,text"`
+	Clear    bool   `json:"clear" alias:"remove,cancel"`
+}
+
+// RunResult executes the tool.
+func (t *SetHeartbeatTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a setHeartbeatArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	if rt.SessionKey == "" {
+		return ErrorResult("set_heartbeat", "heartbeats need a session to wake; none is active")
+	}
+	jobID := heartbeatJobID(rt.SessionKey)
+
+	if a.Clear {
+		fields := map[string]any{"job_id": jobID}
+		if rt.DryRun {
+			fields["dry_run"] = true
+			return okResult("set_heartbeat", fields, "Would clear the heartbeat for "+rt.SessionKey+".")
+		}
+		if t.jobs == nil {
+			return ErrorResult("set_heartbeat", "cron scheduler not available")
+		}
+		removed, err := t.jobs.RemoveJob(jobID)
+		if err != nil {
+			return ErrorResult("set_heartbeat", fmt.Sprintf("failed to clear heartbeat: %v", err))
+		}
+		fields["removed"] = removed
+		if !removed {
+			return okResult("set_heartbeat", fields, "No heartbeat was set for this session.")
+		}
+		return okResult("set_heartbeat", fields, "Heartbeat cleared.")
+	}
+
+	task := strings.TrimSpace(a.Task)
+	if task == "" {
+		return ErrorResult("set_heartbeat", "task is required (or pass clear=true)")
+	}
+	interval, err := parseHeartbeatInterval(a.Interval)
+	if err != nil {
+		return ErrorResult("set_heartbeat", err.Error())
+	}
+
+	job := cronpkg.Job{
+		ID:          jobID,
+		Kind:        cronpkg.JobKindCron,
+		Expr:        "@every " + interval.String(),
+		Task:        "Heartbeat you set with set_heartbeat(interval=" + strings.TrimSpace(a.Interval) + "): " + task,
+		WakeSession: rt.SessionKey,
+		Heartbeat:   true,
+		Admin:       rt.Admin, // check-ins run with the creating turn's privileges
+	}
+	fields := map[string]any{
+		"job_id":   job.ID,
+		"interval": interval.String(),
+	}
+	if rt.DryRun {
+		fields["dry_run"] = true
+		return okResult("set_heartbeat", fields, "Would set a heartbeat for "+rt.SessionKey+".")
+	}
+	if t.jobs == nil {
+		return ErrorResult("set_heartbeat", "cron scheduler not available")
+	}
+	if err := t.jobs.AddJob(job); err != nil {
+		return ErrorResult("set_heartbeat", fmt.Sprintf("failed to set heartbeat: %v", err))
+	}
+	return okResult("set_heartbeat", fields, "Heartbeat set: this session wakes every "+interval.String()+".")
+}
+
+// Run implements Tool.
+func (t *SetHeartbeatTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// heartbeatJobID is the cron job ID of a session's self-scheduled heartbeat.
+func heartbeatJobID(sessionKey string) string {
+	return "heartbeat-" + sessionKey
+}
+
+// parseHeartbeatInterval parses interval like a remind delay, bounded to
+// [minHeartbeatInterval, maxHeartbeatInterval].
+func parseHeartbeatInterval(s string) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, fmt.Errorf("interval is required")
+	}
+	d, err := parseReminderDelay(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval: %v", err)
+	}
+	if d < minHeartbeatInterval || d > maxHeartbeatInterval {
+		return 0, fmt.Errorf("interval must be between %s and %s", minHeartbeatInterval, maxHeartbeatInterval)
+	}
+	return d, nil
+}
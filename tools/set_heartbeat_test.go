@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cronpkg "github.com/linanwx/nagobot/cron"
+)
+
+// memoryJobScheduler keeps jobs by ID, replacing on re-add like the real scheduler.
+type memoryJobScheduler struct {
+	jobs map[string]cronpkg.Job
+}
+
+func (m *memoryJobScheduler) AddJob(job cronpkg.Job) error {
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *memoryJobScheduler) RemoveJob(id string) (bool, error) {
+	_, ok := m.jobs[id]
+	delete(m.jobs, id)
+	return ok, nil
+}
+
+func runSetHeartbeat(tool *SetHeartbeatTool, session string, args map[string]any) string {
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: session})
+	raw, _ := json.Marshal(args)
+	return tool.Run(ctx, raw)
+}
+
+func TestSetHeartbeatTiesJobToCreatorSession(t *testing.T) {
+	jobs := &memoryJobScheduler{jobs: map[string]cronpkg.Job{}}
+	tool := NewSetHeartbeatTool(jobs)
+
+	out := runSetHeartbeat(tool, "telegram:42", map[string]any{"interval": "30m", "task": "check the build"})
+	if strings.Contains(out, "status: error") {
+		t.Fatalf("unexpected error:\n%s", out)
+	}
+	runSetHeartbeat(tool, "discord:7", map[string]any{"interval": "2h", "task": "check the deploy"})
+	// Setting again replaces the session's previous heartbeat.
+	runSetHeartbeat(tool, "telegram:42", map[string]any{"interval": "1h", "task": "check the build"})
+
+	if len(jobs.jobs) != 2 {
+		t.Fatalf("jobs = %d, want one per session: %+v", len(jobs.jobs), jobs.jobs)
+	}
+	job, ok := jobs.jobs[heartbeatJobID("telegram:42")]
+	if !ok {
+		t.Fatalf("no heartbeat job for telegram:42: %+v", jobs.jobs)
+	}
+	if job.WakeSession != "telegram:42" || !job.Heartbeat || job.DirectWake || job.Deliver {
+		t.Errorf("job not a heartbeat on its creator session: %+v", job)
+	}
+	if job.Kind != cronpkg.JobKindCron || job.Expr != "@every 1h0m0s" {
+		t.Errorf("job schedule = %s %q, want cron @every 1h0m0s", job.Kind, job.Expr)
+	}
+	if ok, _ := cronpkg.ValidateStored(job, time.Now()); !ok {
+		t.Errorf("job would be dropped on load: %+v", job)
+	}
+
+	// Clearing only removes the calling session's heartbeat.
+	out = runSetHeartbeat(tool, "telegram:42", map[string]any{"clear": true})
+	if !strings.Contains(out, "removed: true") {
+		t.Fatalf("clear result:\n%s", out)
+	}
+	if _, ok := jobs.jobs[heartbeatJobID("telegram:42")]; ok {
+		t.Error("telegram:42 heartbeat still scheduled after clear")
+	}
+	if _, ok := jobs.jobs[heartbeatJobID("discord:7")]; !ok {
+		t.Error("clearing telegram:42 removed discord:7's heartbeat")
+	}
+	out = runSetHeartbeat(tool, "telegram:42", map[string]any{"clear": true})
+	if !strings.Contains(out, "removed: false") {
+		t.Errorf("second clear result:\n%s", out)
+	}
+}
+
+func TestSetHeartbeatRejects(t *testing.T) {
+	jobs := &memoryJobScheduler{jobs: map[string]cronpkg.Job{}}
+	tool := NewSetHeartbeatTool(jobs)
+	for _, tc := range []struct {
+		session string
+		args    map[string]any
+	}{
+		{"", map[string]any{"interval": "30m", "task": "x"}},
+		{"telegram:42", map[string]any{"interval": "1m", "task": "x"}},
+		{"telegram:42", map[string]any{"interval": "30d", "task": "x"}},
+		{"telegram:42", map[string]any{"interval": "soon", "task": "x"}},
+		{"telegram:42", map[string]any{"interval": "30m"}},
+	} {
+		out := runSetHeartbeat(tool, tc.session, tc.args)
+		if !strings.Contains(out, "status: error") {
+			t.Errorf("%s %v: expected error, got:\n%s", tc.session, tc.args, out)
+		}
+	}
+	if len(jobs.jobs) != 0 {
+		t.Errorf("rejected calls scheduled jobs: %+v", jobs.jobs)
+	}
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+)
+
+// SetModelTool switches the current session to another provider/model. The
+// override is stored in the session's meta.json, so it survives restarts, and
+// takes precedence over the agent's specialty routing until reset_model.
+type SetModelTool struct{}
+
+// Def returns the tool definition.
+func (t *SetModelTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "set_model",
+			Description: "Admin only. Switch this conversation to another provider and model, e.g. when the user asks for the reasoning model. " +
+				"Applies from the next turn and persists until reset_model. Supported providers: " + strings.Join(provider.SupportedProviders(), ", ") + ".",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"provider": map[string]any{
+						"type":        "string",
+						"description": "Provider name, e.g. anthropic or deepseek.",
+					},
+					"model_type": map[string]any{
+						"type":        "string",
+						"description": "Model type supported by the provider.",
+					},
+				},
+				"required": []string{"provider", "model_type"},
+			},
+		},
+	}
+}
+
+type setModelArgs struct {
+	Provider  string `json:"provider" required:"true"`
+	ModelType string `json:"model_type" required:"true" alias:"model,modelType"`
+}
+
+// RunResult executes the tool.
+func (t *SetModelTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	var a setModelArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	if !rt.Admin {
+		return ErrorResult("set_model", "admin only: this turn was not started by the admin, the local CLI, or system automation")
+	}
+	if rt.SessionDir == "" {
+		return ErrorResult("set_model", "no active session")
+	}
+	prov := strings.TrimSpace(a.Provider)
+	model := strings.TrimSpace(a.ModelType)
+	if err := provider.ValidateProviderModelType(prov, model); err != nil {
+		models := provider.SupportedModelsForProvider(prov)
+		if len(models) > 0 {
+			return ErrorResult("set_model", fmt.Sprintf("%v (models for %s: %s)", err, prov, strings.Join(models, ", ")))
+		}
+		return ErrorResult("set_model", err.Error())
+	}
+	if rt.DryRun {
+		return okResult("set_model", map[string]any{"provider": prov, "model_type": model, "dry_run": true},
+			fmt.Sprintf("Dry run: would switch this session to %s via %s. Nothing was changed.", model, prov))
+	}
+	session.UpdateMeta(rt.SessionDir, func(m *session.Meta) {
+		m.Model = &session.ModelOverride{Provider: prov, ModelType: model}
+	})
+	if rt.ModelOverrideChanged != nil {
+		rt.ModelOverrideChanged()
+	}
+	return okResult("set_model", map[string]any{"provider": prov, "model_type": model},
+		fmt.Sprintf("This session now uses %s via %s from the next turn. Call reset_model to return to normal routing.", model, prov))
+}
+
+// Run implements Tool.
+func (t *SetModelTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// ResetModelTool clears the session's set_model override.
+type ResetModelTool struct{}
+
+// Def returns the tool definition.
+func (t *ResetModelTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "reset_model",
+			Description: "Admin only. Clear the model chosen with set_model so this conversation returns to its normal model routing.",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+	}
+}
+
+// RunResult executes the tool.
+func (t *ResetModelTool) RunResult(ctx context.Context, _ json.RawMessage) ToolResult {
+	rt := RuntimeContextFrom(ctx)
+	if !rt.Admin {
+		return ErrorResult("reset_model", "admin only: this turn was not started by the admin, the local CLI, or system automation")
+	}
+	if rt.SessionDir == "" {
+		return ErrorResult("reset_model", "no active session")
+	}
+	if rt.DryRun {
+		set := session.ReadMeta(rt.SessionDir).Model != nil
+		return okResult("reset_model", map[string]any{"cleared": false, "dry_run": true},
+			fmt.Sprintf("Dry run: would clear the model override (set: %t). Nothing was changed.", set))
+	}
+	cleared := false
+	session.UpdateMeta(rt.SessionDir, func(m *session.Meta) {
+		cleared = m.Model != nil
+		m.Model = nil
+	})
+	if rt.ModelOverrideChanged != nil {
+		rt.ModelOverrideChanged()
+	}
+	if !cleared {
+		return okResult("reset_model", map[string]any{"cleared": false}, "No model override was set.")
+	}
+	return okResult("reset_model", map[string]any{"cleared": true}, "Model override cleared; normal routing applies from the next turn.")
+}
+
+// Run implements Tool.
+func (t *ResetModelTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
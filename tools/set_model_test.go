@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/session"
+)
+
+func TestSetModelValidatesAndPersists(t *testing.T) {
+	dir := t.TempDir()
+	run := func(tool Tool, admin bool, args map[string]string) string {
+		raw, _ := json.Marshal(args)
+		ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:1", SessionDir: dir, Admin: admin})
+		return tool.Run(ctx, raw)
+	}
+
+	if out := run(&SetModelTool{}, false, map[string]string{"provider": "zhipu-cn", "model_type": "glm-5"}); !strings.Contains(out, "admin only") {
+		t.Errorf("non-admin set_model = %q", out)
+	}
+	if out := run(&SetModelTool{}, true, map[string]string{"provider": "deepseek", "model_type": "glm-5"}); !strings.Contains(out, "not supported by provider") {
+		t.Errorf("mismatched provider/model = %q", out)
+	}
+	if session.ReadMeta(dir).Model != nil {
+		t.Fatal("rejected calls stored an override")
+	}
+
+	run(&SetModelTool{}, true, map[string]string{"provider": "zhipu-cn", "model": "glm-5"})
+	if mo := session.ReadMeta(dir).Model; mo == nil || mo.Provider != "zhipu-cn" || mo.ModelType != "glm-5" {
+		t.Fatalf("stored override = %+v", mo)
+	}
+	if out := run(&ResetModelTool{}, true, nil); !strings.Contains(out, "cleared: true") {
+		t.Errorf("reset_model = %q", out)
+	}
+	if session.ReadMeta(dir).Model != nil {
+		t.Error("reset_model left the override in place")
+	}
+}
+
+func TestSetModelDryRunLeavesMetaAlone(t *testing.T) {
+	dir := t.TempDir()
+	changed := 0
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{
+		SessionKey:           "telegram:1",
+		SessionDir:           dir,
+		Admin:                true,
+		DryRun:               true,
+		ModelOverrideChanged: func() { changed++ },
+	})
+	raw, _ := json.Marshal(map[string]string{"provider": "zhipu-cn", "model_type": "glm-5"})
+	if out := (&SetModelTool{}).Run(ctx, raw); !strings.Contains(out, "dry_run: true") {
+		t.Errorf("dry-run set_model = %q", out)
+	}
+	if session.ReadMeta(dir).Model != nil || changed != 0 {
+		t.Fatalf("dry-run set_model wrote meta.json (changed callbacks %d)", changed)
+	}
+
+	ctx = WithRuntimeContext(context.Background(), RuntimeContext{
+		SessionKey:           "telegram:1",
+		SessionDir:           dir,
+		Admin:                true,
+		ModelOverrideChanged: func() { changed++ },
+	})
+	(&SetModelTool{}).Run(ctx, raw)
+	if session.ReadMeta(dir).Model == nil || changed != 1 {
+		t.Fatalf("set_model should write and notify once (changed callbacks %d)", changed)
+	}
+}
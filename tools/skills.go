@@ -14,6 +14,7 @@ import (
 type SkillProvider interface {
 	GetSkillPrompt(name string) (prompt string, dir string, ok bool)
 	SkillNames() []string
+	SkillDescription(name string) string
 	Reload() error
 }
 
@@ -52,25 +53,30 @@ type useSkillArgs struct {
 	Name string `json:"name" required:"true"`
 }
 
-// Run executes the tool.
-func (t *UseSkillTool) Run(ctx context.Context, args json.RawMessage) string {
-	return withTimeout(ctx, "use_skill", skillToolTimeout, func(ctx context.Context) string {
+// RunResult executes the tool.
+func (t *UseSkillTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "use_skill", skillToolTimeout, func(ctx context.Context) ToolResult {
 		return t.run(ctx, args)
 	})
 }
 
-func (t *UseSkillTool) run(ctx context.Context, args json.RawMessage) string {
+// Run implements Tool.
+func (t *UseSkillTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *UseSkillTool) run(ctx context.Context, args json.RawMessage) ToolResult {
 	var a useSkillArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	if a.Name == "" {
 		names := t.provider.SkillNames()
 		if len(names) == 0 {
-			return "No skills available."
+			return ToolResult{Content: "No skills available."}
 		}
-		return fmt.Sprintf("Available skills: %s", strings.Join(names, ", "))
+		return ToolResult{Content: fmt.Sprintf("Available skills: %s", strings.Join(names, ", "))}
 	}
 
 	prompt, dir, ok := t.provider.GetSkillPrompt(a.Name)
@@ -82,7 +88,7 @@ func (t *UseSkillTool) run(ctx context.Context, args json.RawMessage) string {
 		}
 		if !ok {
 			names := t.provider.SkillNames()
-			return fmt.Sprintf("Error: skill %q not found. Available skills: %s", a.Name, strings.Join(names, ", "))
+			return ErrorResult("use_skill", fmt.Sprintf("skill %q not found. Available skills: %s", a.Name, strings.Join(names, ", ")))
 		}
 	}
 
@@ -103,9 +109,9 @@ func (t *UseSkillTool) run(ctx context.Context, args json.RawMessage) string {
 	}
 	mapping, ok := msg.EncodeMapping(header)
 	if !ok {
-		return ""
+		return ToolResult{}
 	}
-	return msg.BuildFrontmatter(mapping, "\n"+prompt)
+	return ToolResult{Content: msg.BuildFrontmatter(mapping, "\n"+prompt)}
 }
 
 type skillHeader struct {
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+)
+
+// SessionSummaryRoute is the thread.models key that selects the model used
+// for session summaries. When unmapped, the default model is used.
+const SessionSummaryRoute = "summary"
+
+const (
+	summaryMessageMaxRunes    = 2000  // per-message excerpt in the transcript
+	summaryToolResultMaxRunes = 300   // tool results are context, not content
+	summaryTranscriptMaxRunes = 60000 // oldest lines are dropped beyond this
+)
+
+const summaryInstruction = "Summarize the conversation transcript below as concise Markdown bullets (at most 10). " +
+	"Cover what the user asked for, what was done or decided, and anything left open. " +
+	"Do not invent details that are not in the transcript. Output only the bullets."
+
+// SessionReader is implemented by session.Manager.
+type SessionReader interface {
+	Exists(key string) bool
+	Load(key string) (*session.Session, error)
+	Dir(key string) string
+}
+
+// SummarizeSessionTool summarizes a session's conversation with a lightweight
+// model and optionally appends the summary to the session's daily memory file.
+type SummarizeSessionTool struct {
+	sessions   SessionReader
+	providerFn func() (provider.Provider, error)
+}
+
+// NewSummarizeSessionTool creates the tool. providerFn is called per run so
+// model routing follows config hot-reload.
+func NewSummarizeSessionTool(sessions SessionReader, providerFn func() (provider.Provider, error)) *SummarizeSessionTool {
+	return &SummarizeSessionTool{sessions: sessions, providerFn: providerFn}
+}
+
+// Def returns the tool definition.
+func (t *SummarizeSessionTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "summarize_session",
+			Description: "Produce a concise bullet summary of a session's conversation using a lightweight model. " +
+				"Defaults to the current session. Use `last` to cover only the most recent turns, and " +
+				"`write_memory` to append the summary to the session's daily memory file.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session_key": map[string]any{
+						"type":        "string",
+						"description": "Session to summarize (e.g. 'telegram:123456'). Defaults to the current session.",
+					},
+					"last": map[string]any{
+						"type":        "integer",
+						"description": "Summarize only the last N turns (a turn starts at a user message). 0 = whole session.",
+					},
+					"write_memory": map[string]any{
+						"type":        "boolean",
+						"description": "Append the summary to <session>/memory/YYYY-MM-DD.md.",
+					},
+				},
+			},
+		},
+	}
+}
+
+type summarizeSessionArgs struct {
+	SessionKey  string `json:"session_key,omitempty" alias:"key,session"`
+	Last        int    `json:"last,omitempty"`
+	WriteMemory bool   `json:"write_memory,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *SummarizeSessionTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "summarize_session", summarizeToolTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *SummarizeSessionTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *SummarizeSessionTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a summarizeSessionArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	rt := RuntimeContextFrom(ctx)
+	key := strings.TrimSpace(a.SessionKey)
+	if key == "" {
+		key = rt.SessionKey
+	}
+	if key == "" {
+		return ErrorResult("summarize_session", "session_key is required outside a session")
+	}
+	if a.Last < 0 {
+		return ErrorResult("summarize_session", "last must be >= 0")
+	}
+
+	if t.sessions == nil {
+		return ErrorResult("summarize_session", "session storage is not configured")
+	}
+	if !t.sessions.Exists(key) {
+		return ErrorResult("summarize_session", fmt.Sprintf("session %q not found", key))
+	}
+	s, err := t.sessions.Load(key)
+	if err != nil {
+		return ErrorResult("summarize_session", fmt.Sprintf("failed to load session %q: %v", key, err))
+	}
+	sessionDir := t.sessions.Dir(key)
+
+	if t.providerFn == nil {
+		return ErrorResult("summarize_session", "no provider configured")
+	}
+	prov, err := t.providerFn()
+	if err != nil {
+		return ErrorResult("summarize_session", fmt.Sprintf("failed to create provider: %v", err))
+	}
+
+	summary, used, err := SummarizeMessages(ctx, prov, s.Messages, a.Last)
+	if err != nil {
+		return ErrorResult("summarize_session", err.Error())
+	}
+
+	fields := map[string]any{
+		"session_key": key,
+		"messages":    used,
+	}
+	if a.WriteMemory {
+		if rt.DryRun {
+			fields["dry_run"] = true
+		} else {
+			path, err := session.AppendMemory(sessionDir, "Summary", summary, time.Now())
+			if err != nil {
+				return ErrorResult("summarize_session", err.Error())
+			}
+			fields["memory_file"] = path
+		}
+	}
+	return okResult("summarize_session", fields, summary)
+}
+
+// SummarizeMessages asks prov for a bullet summary of msgs, restricted to the
+// last lastTurns turns when lastTurns > 0. It returns the summary and the
+// number of messages that went into the transcript.
+func SummarizeMessages(ctx context.Context, prov provider.Provider, msgs []provider.Message, lastTurns int) (string, int, error) {
+	msgs = RecentTurns(msgs, lastTurns)
+	transcript, used := buildSummaryTranscript(msgs)
+	if used == 0 {
+		return "", 0, fmt.Errorf("session has no conversation to summarize")
+	}
+
+	req := &provider.Request{Messages: []provider.Message{
+		provider.UserMessage(summaryInstruction + "\n\n<transcript>\n" + transcript + "\n</transcript>"),
+	}}
+	result, err := prov.Chat(ctx, req)
+	if err != nil {
+		return "", 0, fmt.Errorf("summary LLM call failed: %w", err)
+	}
+	resp, err := result.Wait()
+	if err != nil {
+		return "", 0, fmt.Errorf("summary LLM call failed: %w", err)
+	}
+	summary := strings.TrimSpace(resp.Content)
+	if summary == "" {
+		return "", 0, fmt.Errorf("summary LLM returned empty content")
+	}
+	return summary, used, nil
+}
+
+// RecentTurns returns the messages from the n-th last user message onward.
+// n <= 0, or fewer than n turns, returns msgs unchanged.
+func RecentTurns(msgs []provider.Message, n int) []provider.Message {
+	if n <= 0 {
+		return msgs
+	}
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role != "user" {
+			continue
+		}
+		if n--; n == 0 {
+			return msgs[i:]
+		}
+	}
+	return msgs
+}
+
+// buildSummaryTranscript renders msgs as "[role] text" lines, skipping system
+// prompts and tool-call-only turns. When the transcript exceeds its budget the
+// oldest lines are dropped.
+func buildSummaryTranscript(msgs []provider.Message) (string, int) {
+	var lines []string
+	for _, m := range msgs {
+		content := strings.TrimSpace(m.GetContent())
+		if m.Role == "system" || m.HeartbeatTrim || content == "" {
+			continue
+		}
+		label, limit := m.Role, summaryMessageMaxRunes
+		if m.Role == "tool" {
+			label, limit = "tool:"+m.Name, summaryToolResultMaxRunes
+		}
+		content, _ = truncateWithNotice(content, limit)
+		lines = append(lines, fmt.Sprintf("[%s] %s", label, content))
+	}
+
+	total := 0
+	start := len(lines)
+	for start > 0 {
+		n := len([]rune(lines[start-1])) + 1
+		if total+n > summaryTranscriptMaxRunes && start < len(lines) {
+			break
+		}
+		total += n
+		start--
+	}
+	return strings.Join(lines[start:], "\n"), len(lines) - start
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/session"
+)
+
+// stubSummaryProvider records the request and answers with a fixed summary.
+type stubSummaryProvider struct {
+	reqs []*provider.Request
+}
+
+func (p *stubSummaryProvider) Chat(_ context.Context, req *provider.Request) (provider.ChatResult, error) {
+	p.reqs = append(p.reqs, req)
+	return provider.NewBasicResult(&provider.Response{Content: "- user planned a trip to Kyoto\n"}), nil
+}
+
+// openTestSessions opens a session manager on the given backend in a temp dir.
+func openTestSessions(t *testing.T, backend string) *session.Manager {
+	t.Helper()
+	sessionsDir := t.TempDir()
+	store, err := session.OpenStore(backend, sessionsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr, err := session.NewManagerWithStore(sessionsDir, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mgr.Close() })
+	return mgr
+}
+
+func writeTestSession(t *testing.T, sessions *session.Manager, key string, msgs ...provider.Message) string {
+	t.Helper()
+	if err := sessions.Save(&session.Session{Key: key, Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+	return sessions.Dir(key)
+}
+
+func TestSummarizeSessionIncludesMessages(t *testing.T) {
+	for _, backend := range []string{session.BackendFile, session.BackendSQLite} {
+		t.Run(backend, func(t *testing.T) { testSummarizeSessionIncludesMessages(t, backend) })
+	}
+}
+
+func testSummarizeSessionIncludesMessages(t *testing.T, backend string) {
+	sessions := openTestSessions(t, backend)
+	sessionDir := writeTestSession(t, sessions, "telegram:42",
+		provider.UserMessage("old question about taxes"),
+		provider.AssistantMessage("old answer"),
+		provider.UserMessage("help me plan a trip to Kyoto"),
+		provider.AssistantMessage("Sure, how many days?"),
+	)
+
+	stub := &stubSummaryProvider{}
+	tool := NewSummarizeSessionTool(sessions, func() (provider.Provider, error) { return stub, nil })
+	ctx := WithRuntimeContext(context.Background(), RuntimeContext{SessionKey: "telegram:42"})
+
+	out := tool.Run(ctx, json.RawMessage(`{"last":1,"write_memory":true}`))
+	if strings.Contains(out, "status: error") {
+		t.Fatalf("summarize failed:\n%s", out)
+	}
+	if !strings.Contains(out, "user planned a trip to Kyoto") {
+		t.Errorf("summary not returned:\n%s", out)
+	}
+	if len(stub.reqs) != 1 {
+		t.Fatalf("provider calls = %d, want 1", len(stub.reqs))
+	}
+	prompt := stub.reqs[0].Messages[0].Content
+	for _, want := range []string{"[user] help me plan a trip to Kyoto", "[assistant] Sure, how many days?"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "taxes") {
+		t.Errorf("last=1 should exclude earlier turns:\n%s", prompt)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(sessionDir, session.MemoryDirName))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("memory file not written: %v %v", entries, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(sessionDir, session.MemoryDirName, entries[0].Name()))
+	if !strings.Contains(string(data), "## Summary") || !strings.Contains(string(data), "Kyoto") {
+		t.Errorf("memory file content:\n%s", data)
+	}
+}
+
+func TestSummarizeSessionEmpty(t *testing.T) {
+	sessions := openTestSessions(t, session.BackendFile)
+	writeTestSession(t, sessions, "cli", provider.SystemMessage("system only"))
+
+	stub := &stubSummaryProvider{}
+	tool := NewSummarizeSessionTool(sessions, func() (provider.Provider, error) { return stub, nil })
+	out := tool.Run(context.Background(), json.RawMessage(`{"session_key":"cli"}`))
+	if !strings.Contains(out, "no conversation to summarize") {
+		t.Errorf("expected empty-session error, got:\n%s", out)
+	}
+	if len(stub.reqs) != 0 {
+		t.Errorf("provider should not be called for an empty session")
+	}
+}
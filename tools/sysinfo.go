@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// SysInfoTool reports a curated, non-sensitive view of the host and runtime
+// (OS, hostname, Go version, workspace free space, current time, model)
+// without shelling out. Environment variables, user names, network
+// addresses and credentials are never included.
+type SysInfoTool struct {
+	Workspace  string
+	ModelFn    func() (providerName, modelName string) // Optional: provider/model serving the current thread.
+	LocationFn func() *time.Location                   // Optional: session timezone used when no zone is requested.
+}
+
+// sysInfo is the JSON body returned by the sysinfo tool.
+type sysInfo struct {
+	OS                 string `json:"os"`
+	Arch               string `json:"arch"`
+	Hostname           string `json:"hostname,omitempty"`
+	GoVersion          string `json:"go_version"`
+	NumCPU             int    `json:"num_cpu"`
+	Workspace          string `json:"workspace,omitempty"`
+	WorkspaceFreeBytes uint64 `json:"workspace_free_bytes,omitempty"`
+	WorkspaceFree      string `json:"workspace_free,omitempty"`
+	Time               string `json:"time"`
+	Timezone           string `json:"timezone"`
+	Provider           string `json:"provider,omitempty"`
+	Model              string `json:"model,omitempty"`
+}
+
+// Def returns the tool definition.
+func (t *SysInfoTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "sysinfo",
+			Description: "Report host and runtime facts as JSON: OS/arch, hostname, Go version, CPU count, workspace path and free disk space, current time and timezone, and the provider/model in use. Prefer this over exec uname/df/date; it is cross-platform and never exposes secrets or environment variables.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"timezone": map[string]any{
+						"type":        "string",
+						"description": "Optional IANA timezone for the time field (e.g. \"Asia/Shanghai\"). Defaults to the session timezone.",
+					},
+				},
+			},
+		},
+	}
+}
+
+type sysInfoArgs struct {
+	Timezone string `json:"timezone" alias:"tz,zone"`
+}
+
+// RunResult executes the tool.
+func (t *SysInfoTool) RunResult(_ context.Context, args json.RawMessage) ToolResult {
+	var a sysInfoArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+
+	loc := time.Local
+	if t.LocationFn != nil {
+		if l := t.LocationFn(); l != nil {
+			loc = l
+		}
+	}
+	if tz := strings.TrimSpace(a.Timezone); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return ErrorResult("sysinfo", fmt.Sprintf("unknown timezone %q: %v", tz, err))
+		}
+		loc = l
+	}
+	now := time.Now().In(loc)
+
+	info := sysInfo{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		NumCPU:    runtime.NumCPU(),
+		Time:      now.Format(time.RFC3339),
+		Timezone:  loc.String(),
+	}
+	if host, err := os.Hostname(); err == nil {
+		info.Hostname = host
+	}
+	if t.Workspace != "" {
+		ws, err := filepath.Abs(t.Workspace)
+		if err != nil {
+			ws = t.Workspace
+		}
+		info.Workspace = ws
+		if free, ok := diskFree(ws); ok {
+			info.WorkspaceFreeBytes = free
+			info.WorkspaceFree = formatBytes(free)
+		}
+	}
+	if t.ModelFn != nil {
+		info.Provider, info.Model = t.ModelFn()
+	}
+
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return ErrorResult("sysinfo", fmt.Sprintf("failed to encode: %v", err))
+	}
+	return okResult("sysinfo", nil, string(data))
+}
+
+// Run implements Tool.
+func (t *SysInfoTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+// formatBytes renders a byte count for humans.
+func formatBytes(b uint64) string {
+	switch {
+	case b >= 1<<40:
+		return fmt.Sprintf("%.1f TB", float64(b)/float64(1<<40))
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(b)/float64(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(b)/float64(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(b)/float64(1<<10))
+	default:
+		return fmt.Sprintf("%d B", b)
+	}
+}
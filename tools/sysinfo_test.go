@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSysInfoReportsOSAndWorkspace(t *testing.T) {
+	ws := t.TempDir()
+	tool := &SysInfoTool{
+		Workspace: ws,
+		ModelFn:   func() (string, string) { return "deepseek", "deepseek-chat" },
+	}
+
+	out := tool.Run(context.Background(), json.RawMessage(`{"timezone":"Asia/Shanghai"}`))
+	_, body, ok := strings.Cut(out, "---\n\n")
+	if !ok || IsToolError(out) {
+		t.Fatalf("unexpected result:\n%s", out)
+	}
+	var info sysInfo
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		t.Fatalf("body is not JSON: %v\n%s", err, out)
+	}
+	if info.OS != runtime.GOOS || info.Workspace != ws {
+		t.Errorf("os = %q, workspace = %q; want %q, %q", info.OS, info.Workspace, runtime.GOOS, ws)
+	}
+	if info.Timezone != "Asia/Shanghai" || info.Model != "deepseek-chat" {
+		t.Errorf("timezone = %q, model = %q", info.Timezone, info.Model)
+	}
+	if _, err := time.Parse(time.RFC3339, info.Time); err != nil {
+		t.Errorf("time %q is not RFC3339: %v", info.Time, err)
+	}
+
+	if out := tool.Run(context.Background(), json.RawMessage(`{"timezone":"Mars/Olympus"}`)); !IsToolError(out) {
+		t.Errorf("unknown timezone should be an error:\n%s", out)
+	}
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+	"github.com/linanwx/nagobot/tgmd"
+)
+
+// TableToCSVTool converts markdown tables into CSV text.
+type TableToCSVTool struct{}
+
+// Def returns the tool definition.
+func (t *TableToCSVTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name:        "table_to_csv",
+			Description: "Convert markdown (GFM) tables into CSV. Every table found in the input is converted, header row first; escaped pipes (\\|) become literal pipes and short rows are padded with empty cells. Use write_file to save the result as a .csv file.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"markdown": map[string]any{
+						"type":        "string",
+						"description": "Markdown text containing one or more tables.",
+					},
+				},
+				"required": []string{"markdown"},
+			},
+		},
+	}
+}
+
+type tableToCSVArgs struct {
+	Markdown string `json:"markdown" required:"true" alias:"table,text"`
+}
+
+// RunResult executes the tool.
+func (t *TableToCSVTool) RunResult(_ context.Context, args json.RawMessage) ToolResult {
+	var a tableToCSVArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	tables, err := tgmd.TableToCSV(a.Markdown)
+	if err != nil {
+		return ErrorResult("table_to_csv", err.Error())
+	}
+	if len(tables) == 1 {
+		return okResult("table_to_csv", map[string]any{"tables": 1}, tables[0])
+	}
+	var b strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Table %d:\n```csv\n%s```\n", i+1, table)
+	}
+	return okResult("table_to_csv", map[string]any{"tables": len(tables)}, b.String())
+}
+
+// Run implements Tool.
+func (t *TableToCSVTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
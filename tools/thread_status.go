@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// threadStatusMaxListed caps the threads listed individually by all=true;
+// idle threads are only counted.
+const threadStatusMaxListed = 30
+
+// ThreadLister is implemented by Manager.
+type ThreadLister interface {
+	ListThreads() []ThreadInfo
+}
+
+// ThreadStatusTool reports what the current thread is doing (state, turn
+// elapsed, last tool, queued wakes) and, for admin turns, a summary of every
+// loaded thread.
+type ThreadStatusTool struct {
+	lister ThreadLister
+}
+
+// NewThreadStatusTool creates the tool.
+func NewThreadStatusTool(lister ThreadLister) *ThreadStatusTool {
+	return &ThreadStatusTool{lister: lister}
+}
+
+// Def returns the tool definition.
+func (t *ThreadStatusTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "thread_status",
+			Description: "Report this conversation's execution state: running or idle, how long the current turn has run, iterations, the last tool called, and how many wakes are queued. " +
+				"With all=true (admin only), also summarize every loaded thread, to answer \"what is the bot doing right now?\".",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"all": map[string]any{
+						"type":        "boolean",
+						"description": "Include a summary across all threads (admin only).",
+					},
+				},
+			},
+		},
+	}
+}
+
+type threadStatusArgs struct {
+	All bool `json:"all,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *ThreadStatusTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "thread_status", threadToolTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *ThreadStatusTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *ThreadStatusTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a threadStatusArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	if t.lister == nil {
+		return ErrorResult("thread_status", "thread manager not configured")
+	}
+	rt := RuntimeContextFrom(ctx)
+	if a.All && !rt.Admin {
+		return ErrorResult("thread_status", "admin only: all=true needs a turn started by the admin, the local CLI, or system automation")
+	}
+
+	threads := t.lister.ListThreads()
+	fields := map[string]any{"session_key": rt.SessionKey}
+	var current *ThreadInfo
+	for i := range threads {
+		if threads[i].SessionKey == rt.SessionKey {
+			current = &threads[i]
+			break
+		}
+	}
+	if current == nil {
+		fields["state"] = "not_loaded"
+	} else {
+		fields["thread_id"] = current.ID
+		fields["state"] = current.State
+		fields["inbox"] = current.Pending
+		if current.State == "running" {
+			fields["turn_elapsed_sec"] = current.ElapsedSec
+			fields["iterations"] = current.Iterations
+			fields["tool_calls"] = current.TotalToolCalls
+			if current.CurrentTool != "" {
+				fields["current_tool"] = current.CurrentTool
+			}
+			if n := len(current.ToolTrace); n > 0 {
+				fields["last_tool"] = current.ToolTrace[n-1].Name
+			}
+		}
+	}
+	if !a.All {
+		return okResult("thread_status", fields, "")
+	}
+
+	counts := map[string]int{}
+	var busy []ThreadInfo
+	for _, th := range threads {
+		counts[th.State]++
+		if th.State != "idle" {
+			busy = append(busy, th)
+		}
+	}
+	fields["threads"] = len(threads)
+	fields["running"] = counts["running"]
+	fields["pending"] = counts["pending"]
+	fields["idle"] = counts["idle"]
+
+	// Running first, longest-running first; then pending by session key.
+	sort.Slice(busy, func(i, j int) bool {
+		if busy[i].State != busy[j].State {
+			return busy[i].State == "running"
+		}
+		if busy[i].ElapsedSec != busy[j].ElapsedSec {
+			return busy[i].ElapsedSec > busy[j].ElapsedSec
+		}
+		return busy[i].SessionKey < busy[j].SessionKey
+	})
+	var sb strings.Builder
+	for i, th := range busy {
+		if i == threadStatusMaxListed {
+			fmt.Fprintf(&sb, "... and %d more\n", len(busy)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "- %s: %s", th.SessionKey, th.State)
+		if th.State == "running" {
+			fmt.Fprintf(&sb, ", %ds, %d iterations", th.ElapsedSec, th.Iterations)
+			if th.CurrentTool != "" {
+				fmt.Fprintf(&sb, ", in %s", th.CurrentTool)
+			}
+		}
+		if th.Pending > 0 {
+			fmt.Fprintf(&sb, ", %d queued", th.Pending)
+		}
+		sb.WriteByte('\n')
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("No thread is running or has queued work.")
+	}
+	return okResult("thread_status", fields, strings.TrimRight(sb.String(), "\n"))
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -21,13 +22,18 @@ import (
 
 // Tool timeout defaults. Grouped here for visibility.
 const (
-	fileToolTimeout   = 10 * time.Second
-	globToolTimeout   = 30 * time.Second
-	grepToolTimeout   = 30 * time.Second
-	threadToolTimeout = 5 * time.Second
-	wakeToolTimeout   = 5 * time.Second
-	healthToolTimeout = 15 * time.Second
-	skillToolTimeout  = 10 * time.Second
+	fileToolTimeout      = 10 * time.Second
+	chunkFileTimeout     = 30 * time.Second
+	globToolTimeout      = 30 * time.Second
+	grepToolTimeout      = 30 * time.Second
+	threadToolTimeout    = 5 * time.Second
+	wakeToolTimeout      = 5 * time.Second
+	healthToolTimeout    = 15 * time.Second
+	skillToolTimeout     = 10 * time.Second
+	sendFileTimeout      = 2 * time.Minute
+	sendEmbedTimeout     = 30 * time.Second
+	summarizeToolTimeout = 2 * time.Minute
+	registryToolTimeout  = 10 * time.Minute // Registry.Run cap for tools without a configured timeout
 )
 
 // withTimeout runs fn in a goroutine with a deadline. If the operation
@@ -35,11 +41,11 @@ const (
 // returned and the goroutine is left to finish in the background.
 // This is the only safe way to bound blocking syscalls (os.ReadFile, etc.)
 // that do not respect context cancellation.
-func withTimeout(ctx context.Context, tool string, timeout time.Duration, fn func(ctx context.Context) string) string {
+func withTimeout(ctx context.Context, tool string, timeout time.Duration, fn func(ctx context.Context) ToolResult) ToolResult {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	ch := make(chan string, 1)
+	ch := make(chan ToolResult, 1)
 	go func() {
 		ch <- fn(ctx)
 	}()
@@ -55,26 +61,41 @@ func withTimeout(ctx context.Context, tool string, timeout time.Duration, fn fun
 		default:
 		}
 		if ctx.Err() == context.DeadlineExceeded {
-			return toolError(tool, fmt.Sprintf("operation timed out after %v", timeout))
+			return ErrorResult(tool, fmt.Sprintf("operation timed out after %v", timeout))
 		}
-		return toolError(tool, "operation cancelled")
+		return ErrorResult(tool, "operation cancelled")
 	}
 }
 
 const (
 	toolResultMaxRunes = 100000
 	toolLogMaxRunes    = 50000
+
+	// toolResultMaxBytes is the default hard cap Registry.Run applies to any
+	// tool result before anything else touches it, a backstop against
+	// tools that ignore their own output limits.
+	toolResultMaxBytes = 1 << 20
 )
 
 // Tool is the interface for agent tools.
 type Tool interface {
 	// Def returns the tool definition for the LLM.
 	Def() provider.ToolDef
-	// Run executes the tool with the given arguments and returns the result.
-	// Errors are returned as strings (for the LLM to interpret).
+	// Run executes the tool with the given arguments and returns the result
+	// text. Built-in tools also implement ResultTool, which the Registry
+	// prefers; for other tools a toolError result (status: error) or an
+	// "Error:"-prefixed string is reported as ToolResult.IsError.
 	Run(ctx context.Context, args json.RawMessage) string
 }
 
+// SelfTimedTool is implemented by tools that bound each call themselves with
+// a caller-chosen timeout (exec). The Registry's default timeout does not
+// apply to them; an explicitly configured per-tool timeout still does.
+type SelfTimedTool interface {
+	Tool
+	SelfTimed() bool
+}
+
 // parseArgs decodes a tool's JSON arguments into target with three guards:
 //
 //  1. Alias compat: any field tagged `alias:"foo,bar"` also accepts foo/bar as
@@ -85,6 +106,10 @@ type Tool interface {
 //     declared alias fail fast, so silent-drop bugs (e.g. Go's strings.Count
 //     with "" returning runeCount+1) cannot happen downstream.
 //
+// Arguments that are not valid JSON get one light repair attempt (trailing
+// commas, single quotes, unquoted keys, raw newlines) before failing; the
+// error then quotes the raw arguments so the model can self-correct.
+//
 // These checks run centrally so every tool gets them without duplicated code.
 func parseArgs[T any](args json.RawMessage, target *T) string {
 	trimmed := strings.TrimSpace(string(args))
@@ -96,15 +121,12 @@ func parseArgs[T any](args json.RawMessage, target *T) string {
 	if tv.Kind() != reflect.Struct {
 		// Non-struct target: fallback to plain unmarshal. None of the built-in
 		// tools hit this path today, but keep it safe.
-		if err := json.Unmarshal(args, target); err != nil {
-			return fmt.Sprintf("Error: invalid arguments: %v", err)
-		}
-		return ""
+		return unmarshalArgs(args, target)
 	}
 
 	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(args, &raw); err != nil {
-		return fmt.Sprintf("Error: invalid arguments: %v", err)
+	if errMsg := unmarshalArgs(args, &raw); errMsg != "" {
+		return errMsg
 	}
 
 	allowed := make(map[string]struct{}, tv.NumField())
@@ -206,21 +228,34 @@ func parseArgs[T any](args json.RawMessage, target *T) string {
 type Registry struct {
 	tools   map[string]Tool
 	logsDir string
+	audit   *AuditLog    // nil disables the JSONL audit log
+	journal *ToolJournal // nil disables the tool execution journal
+
+	defaultTimeout time.Duration            // 0 = registryToolTimeout, negative disables
+	timeouts       map[string]time.Duration // per-tool overrides; negative disables
+	maxResultBytes int                      // 0 = toolResultMaxBytes, negative disables
 }
 
 // DefaultToolsConfig provides defaults for built-in tools.
 type DefaultToolsConfig struct {
-	ExecTimeout         int
-	WebSearchMaxResults int
-	WebSearchGuide      string // content from WEB_SEARCH_GUIDE.md
-	SearchProviders     map[string]SearchProvider
-	SearchHealthChecker *SearchHealthChecker
-	FetchProviders      map[string]FetchProvider
-	FetchHealthChecker  *SearchHealthChecker // reused type — tracks fetch outcomes
-	WebFetchGuide       string              // content from WEB_FETCH_GUIDE.md
-	RestrictToWorkspace bool
-	Skills              SkillProvider
-	LogsDir             string // Log files directory for health diagnostics
+	ExecTimeout            int
+	ExecConfirmDestructive bool // guard destructive exec commands on non-admin turns
+	WebSearchMaxResults    int
+	WebSearchGuide         string        // content from WEB_SEARCH_GUIDE.md
+	WebSearchCacheTTL      time.Duration // how long identical searches are served from cache (0 = default)
+	SearchProviders        map[string]SearchProvider
+	SearchBackend          string // source used when web_search is called without one
+	SearchHealthChecker    *SearchHealthChecker
+	FetchProviders         map[string]FetchProvider
+	FetchHealthChecker     *SearchHealthChecker // reused type — tracks fetch outcomes
+	WebFetchGuide          string               // content from WEB_FETCH_GUIDE.md
+	RestrictToWorkspace    bool
+	Skills                 SkillProvider
+	SkillsDir              string                            // User skills directory; load_skill persists here
+	SkillAllowedHosts      []string                          // Hosts load_skill may fetch skill files from
+	LogsDir                string                            // Log files directory for health diagnostics
+	Embedder               Embedder                          // recall ranks memory notes by embedding; nil = keyword search
+	SummaryProvider        func() (provider.Provider, error) // web_summarize's model; nil omits the tool
 }
 
 // NewRegistry creates a new tool registry.
@@ -230,6 +265,58 @@ func NewRegistry() *Registry {
 	}
 }
 
+// SetAuditLog enables the per-session JSONL audit log for tool calls.
+func (r *Registry) SetAuditLog(a *AuditLog) {
+	r.audit = a
+}
+
+// SetToolJournal enables the per-session journal of mutating tool calls.
+func (r *Registry) SetToolJournal(j *ToolJournal) {
+	r.journal = j
+}
+
+// SetTimeouts sets the per-call timeout Run enforces: def for every tool
+// (0 = registryToolTimeout, negative disables) and perTool overrides.
+func (r *Registry) SetTimeouts(def time.Duration, perTool map[string]time.Duration) {
+	r.defaultTimeout = def
+	r.timeouts = perTool
+}
+
+// timeoutFor returns the timeout Run enforces for a tool, or 0 for none.
+func (r *Registry) timeoutFor(name string, t Tool) time.Duration {
+	d, ok := r.timeouts[name]
+	if !ok {
+		if st, self := t.(SelfTimedTool); self && st.SelfTimed() {
+			return 0
+		}
+		d = r.defaultTimeout
+		if d == 0 {
+			d = registryToolTimeout
+		}
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// SetMaxResultBytes sets the hard byte cap Run applies to every tool result
+// (0 = toolResultMaxBytes, negative disables).
+func (r *Registry) SetMaxResultBytes(n int) {
+	r.maxResultBytes = n
+}
+
+// resultByteLimit returns the byte cap Run enforces, or 0 for none.
+func (r *Registry) resultByteLimit() int {
+	switch {
+	case r.maxResultBytes < 0:
+		return 0
+	case r.maxResultBytes == 0:
+		return toolResultMaxBytes
+	}
+	return r.maxResultBytes
+}
+
 // SetLogsDir sets the directory for tool call log files.
 func (r *Registry) SetLogsDir(dir string) {
 	r.logsDir = strings.TrimSpace(dir)
@@ -239,12 +326,34 @@ func (r *Registry) SetLogsDir(dir string) {
 func (r *Registry) Clone() *Registry {
 	cloned := NewRegistry()
 	cloned.logsDir = r.logsDir
+	cloned.audit = r.audit
+	cloned.journal = r.journal
+	cloned.defaultTimeout = r.defaultTimeout
+	cloned.timeouts = r.timeouts
+	cloned.maxResultBytes = r.maxResultBytes
 	for name, tool := range r.tools {
 		cloned.tools[name] = tool
 	}
 	return cloned
 }
 
+// Filter returns a clone holding only the tools named in allow (all tools when
+// allow is empty), minus those named in deny.
+func (r *Registry) Filter(allow, deny []string) *Registry {
+	filtered := r.Clone()
+	if len(allow) > 0 {
+		for name := range filtered.tools {
+			if !slices.Contains(allow, name) {
+				delete(filtered.tools, name)
+			}
+		}
+	}
+	for _, name := range deny {
+		delete(filtered.tools, name)
+	}
+	return filtered
+}
+
 // Register adds a tool to the registry.
 func (r *Registry) Register(t Tool) {
 	r.tools[t.Def().Function.Name] = t
@@ -272,8 +381,14 @@ func (r *Registry) Defs() []provider.ToolDef {
 	return defs
 }
 
-// Run executes a tool by name.
+// Run executes a tool by name and returns the result text.
 func (r *Registry) Run(ctx context.Context, name string, args json.RawMessage) string {
+	return r.RunResult(ctx, name, args).Content
+}
+
+// RunResult executes a tool by name and reports whether it failed. Unknown
+// tools, timeouts and tool errors all come back with IsError set.
+func (r *Registry) RunResult(ctx context.Context, name string, args json.RawMessage) ToolResult {
 	start := time.Now()
 	logger.Debug("tool call", "tool", name, "args", string(args))
 
@@ -281,12 +396,40 @@ func (r *Registry) Run(ctx context.Context, name string, args json.RawMessage) s
 	if !ok {
 		logger.Error("tool not found", "tool", name)
 		logger.Debug("tool call finished", "tool", name, "ok", false, "latencyMs", time.Since(start).Milliseconds())
-		return fmt.Sprintf("Error: unknown tool '%s'", name)
+		return errorText(fmt.Sprintf("Error: unknown tool '%s'", name))
 	}
 
-	result := t.Run(ctx, args)
+	rt := RuntimeContextFrom(ctx)
+	var journalID string
+	if !rt.DryRun {
+		journalID = r.journal.Begin(rt.SessionDir, name, args, rt.Admin)
+	}
+
+	var res ToolResult
+	if timeout := r.timeoutFor(name, t); timeout > 0 {
+		// Tools that ignore ctx are abandoned, not stopped, so the turn
+		// can continue.
+		res = withTimeout(ctx, name, timeout, func(ctx context.Context) ToolResult {
+			return runTool(ctx, t, args)
+		})
+	} else {
+		res = runTool(ctx, t, args)
+	}
+	result := res.Content
 	latency := time.Since(start)
 	originalChars := len(result)
+	// Byte backstop first, so the rune truncation below never has to decode
+	// an unbounded result.
+	if limit := r.resultByteLimit(); limit > 0 {
+		var capped bool
+		if result, capped = capBytesWithNotice(result, limit); capped {
+			logger.Warn("tool result exceeded the size backstop",
+				"tool", name,
+				"originalBytes", originalChars,
+				"limitBytes", limit,
+			)
+		}
+	}
 	result, truncated := truncateWithNotice(result, toolResultMaxRunes)
 	if truncated {
 		logger.Warn("tool output truncated",
@@ -296,11 +439,13 @@ func (r *Registry) Run(ctx context.Context, name string, args json.RawMessage) s
 			"limit", toolResultMaxRunes,
 		)
 	}
-	okResult := !IsToolError(result)
+	res.Content = result
+	succeeded := !res.IsError
+	r.journal.Finish(rt.SessionDir, journalID, succeeded, result)
 	logger.Debug(
 		"tool call finished",
 		"tool", name,
-		"ok", okResult,
+		"ok", succeeded,
 		"truncated", truncated,
 		"resultChars", len(result),
 		"originalChars", originalChars,
@@ -308,10 +453,13 @@ func (r *Registry) Run(ctx context.Context, name string, args json.RawMessage) s
 	)
 
 	if r.logsDir != "" {
-		go r.writeToolLog(name, args, result, start, latency, okResult)
+		go r.writeToolLog(name, args, result, start, latency, succeeded)
+	}
+	if r.audit != nil {
+		r.audit.Record(rt.SessionKey, name, args, result, start, latency, succeeded)
 	}
 
-	return result
+	return res
 }
 
 // Names returns the names of all registered tools.
@@ -326,17 +474,40 @@ func (r *Registry) Names() []string {
 
 // RegisterDefaultTools registers the default file tools.
 func (r *Registry) RegisterDefaultTools(workspace string, cfg DefaultToolsConfig) {
-	r.Register(&ReadFileTool{workspace: workspace})
-	r.Register(&WriteFileTool{workspace: workspace})
-	r.Register(&GrepTool{workspace: workspace})
-	r.Register(&GlobTool{workspace: workspace})
-	r.Register(&EditFileTool{workspace: workspace})
-	r.Register(NewExecTool(workspace, cfg.ExecTimeout, cfg.RestrictToWorkspace))
+	r.Register(&ReadFileTool{workspace: workspace, restrictToWorkspace: cfg.RestrictToWorkspace})
+	r.Register(&WriteFileTool{workspace: workspace, restrictToWorkspace: cfg.RestrictToWorkspace})
+	r.Register(&GrepTool{workspace: workspace, restrictToWorkspace: cfg.RestrictToWorkspace})
+	r.Register(&GlobTool{workspace: workspace, restrictToWorkspace: cfg.RestrictToWorkspace})
+	r.Register(&EditFileTool{workspace: workspace, restrictToWorkspace: cfg.RestrictToWorkspace})
+	r.Register(NewChunkFileTool(workspace, cfg.RestrictToWorkspace))
+	r.Register(NewApplyPatchTool(workspace, cfg.RestrictToWorkspace))
+	execTool := NewExecTool(workspace, cfg.ExecTimeout, cfg.RestrictToWorkspace)
+	execTool.SetConfirmDestructive(cfg.ExecConfirmDestructive)
+	r.Register(execTool)
+	r.Register(NewSendFileTool(workspace, cfg.RestrictToWorkspace))
+	r.Register(&SendEmbedTool{})
+	r.Register(&DraftReplyTool{})
+	r.Register(NewScratchpadTool())
+	r.Register(&PinTool{})
+	r.Register(&UnpinTool{})
+	r.Register(&ListPinsTool{})
+	r.Register(NewRecallTool(cfg.Embedder))
+	r.Register(&SetModelTool{})
+	r.Register(&ResetModelTool{})
+	r.Register(&TableToCSVTool{})
 	r.Register(&HealthTool{Workspace: workspace, LogsDir: cfg.LogsDir})
-	r.Register(&WebSearchTool{defaultMaxResults: cfg.WebSearchMaxResults, providers: cfg.SearchProviders, healthChecker: cfg.SearchHealthChecker, Guide: cfg.WebSearchGuide})
-	r.Register(&WebFetchTool{providers: cfg.FetchProviders, healthChecker: cfg.FetchHealthChecker, Guide: cfg.WebFetchGuide})
+	r.Register(NewWebSearchTool(cfg.WebSearchMaxResults, cfg.SearchProviders, cfg.SearchBackend, cfg.SearchHealthChecker, cfg.WebSearchGuide, cfg.WebSearchCacheTTL))
+	webFetch := NewWebFetchTool(cfg.FetchProviders, cfg.FetchHealthChecker, cfg.WebFetchGuide)
+	r.Register(webFetch)
+	if cfg.SummaryProvider != nil {
+		r.Register(NewWebSummarizeTool(webFetch, cfg.SummaryProvider))
+	}
 	if cfg.Skills != nil {
 		r.Register(NewUseSkillTool(cfg.Skills))
+		r.Register(NewListSkillsTool(cfg.Skills))
+		if inst, ok := cfg.Skills.(SkillInstaller); ok && cfg.SkillsDir != "" {
+			r.Register(NewLoadSkillTool(inst, cfg.SkillsDir, workspace, cfg.RestrictToWorkspace, cfg.SkillAllowedHosts))
+		}
 	}
 }
 
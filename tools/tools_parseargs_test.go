@@ -123,3 +123,34 @@ func TestParseArgs_HappyPathLeavesStructPopulated(t *testing.T) {
 		t.Fatalf("unexpected result: %+v", a)
 	}
 }
+
+func TestParseArgs_RepairsMalformedJSON(t *testing.T) {
+	cases := map[string]string{
+		"trailing comma":  `{"name":"x",}`,
+		"single quotes":   `{'name':'x'}`,
+		"unquoted key":    `{name: "x"}`,
+		"raw newline":     "{\"name\":\"x\n\"}",
+		"literal newline": `{\n  "name": "x"\n}`,
+	}
+	for label, in := range cases {
+		var a minimalArgs
+		if out := parse(in, &a); out != "" {
+			t.Errorf("%s: unexpected error: %s", label, out)
+			continue
+		}
+		if strings.TrimSpace(a.Name) != "x" {
+			t.Errorf("%s: name = %q, want x", label, a.Name)
+		}
+	}
+}
+
+func TestParseArgs_UnrepairableShowsRawArgs(t *testing.T) {
+	var a minimalArgs
+	out := parse(`{"name": "x" "extra"}`, &a)
+	if !strings.HasPrefix(out, "Error: invalid arguments:") {
+		t.Fatalf("expected invalid arguments error, got %q", out)
+	}
+	if !strings.Contains(out, `Raw arguments: {"name": "x" "extra"}`) {
+		t.Errorf("error should quote the raw arguments, got %q", out)
+	}
+}
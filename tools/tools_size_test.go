@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// floodTool returns size bytes of output, ignoring any limit of its own.
+type floodTool struct {
+	size int
+}
+
+func (f *floodTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: "flood"}}
+}
+
+func (f *floodTool) Run(context.Context, json.RawMessage) string {
+	return strings.Repeat("é", f.size/2)
+}
+
+func TestRegistryRun_CapsOversizedResult(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&floodTool{size: 8 << 20})
+	r.SetMaxResultBytes(4001)
+
+	result := r.Run(context.Background(), "flood", json.RawMessage(`{}`))
+	if !strings.Contains(result, "exceeded the 4001-byte tool result limit") {
+		t.Fatalf("missing backstop note in result tail: %q", result[max(0, len(result)-200):])
+	}
+	body, _, _ := strings.Cut(result, "\n\n... [output exceeded")
+	if len(body) != 4000 || !strings.HasPrefix(body, "éé") {
+		t.Errorf("kept %d bytes, want 4000 cut on a rune boundary", len(body))
+	}
+}
+
+func TestRegistryRun_DefaultCapKeepsNoteThroughRuneTruncation(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&floodTool{size: 3 * toolResultMaxBytes})
+
+	result := r.Run(context.Background(), "flood", json.RawMessage(`{}`))
+	if !strings.Contains(result, "tool result limit") || !strings.Contains(result, "[truncated ") {
+		t.Errorf("want both the byte backstop and rune truncation notes, got tail %q", result[max(0, len(result)-200):])
+	}
+	if n := len([]rune(result)); n > toolResultMaxRunes+200 {
+		t.Errorf("result has %d runes, want about %d", n, toolResultMaxRunes)
+	}
+
+	r.SetMaxResultBytes(-1)
+	if result := r.Run(context.Background(), "flood", json.RawMessage(`{}`)); strings.Contains(result, "tool result limit") {
+		t.Error("negative limit should disable the backstop")
+	}
+}
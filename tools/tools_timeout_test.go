@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// hangingTool ignores its context and blocks until release is closed.
+type hangingTool struct {
+	name    string
+	release chan struct{}
+}
+
+func (h *hangingTool) Def() provider.ToolDef {
+	return provider.ToolDef{Type: "function", Function: provider.FunctionDef{Name: h.name}}
+}
+
+func (h *hangingTool) Run(context.Context, json.RawMessage) string {
+	<-h.release
+	return "done"
+}
+
+func TestRegistryRun_TimesOutToolIgnoringDeadline(t *testing.T) {
+	tool := &hangingTool{name: "hang", release: make(chan struct{})}
+	defer close(tool.release)
+	r := NewRegistry()
+	r.Register(tool)
+	r.SetTimeouts(time.Hour, map[string]time.Duration{"hang": 50 * time.Millisecond})
+
+	start := time.Now()
+	result := r.Run(context.Background(), "hang", json.RawMessage(`{}`))
+	elapsed := time.Since(start)
+	if !IsToolError(result) || !strings.Contains(result, "timed out after 50ms") {
+		t.Fatalf("result = %q, want timeout error", result)
+	}
+	if elapsed < 50*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("returned after %v, want about the configured 50ms", elapsed)
+	}
+}
+
+func TestRegistryTimeoutFor(t *testing.T) {
+	exec := NewExecTool("", 0, false)
+	plain := &hangingTool{name: "plain"}
+	r := NewRegistry()
+
+	if got := r.timeoutFor("plain", plain); got != registryToolTimeout {
+		t.Errorf("unconfigured default = %v, want %v", got, registryToolTimeout)
+	}
+	if got := r.timeoutFor("exec", exec); got != 0 {
+		t.Errorf("exec under default = %v, want none (self-timed)", got)
+	}
+
+	r.SetTimeouts(-1, map[string]time.Duration{"exec": 2 * time.Minute})
+	if got := r.timeoutFor("plain", plain); got != 0 {
+		t.Errorf("disabled default = %v, want none", got)
+	}
+	if got := r.timeoutFor("exec", exec); got != 2*time.Minute {
+		t.Errorf("explicit exec timeout = %v, want 2m", got)
+	}
+}
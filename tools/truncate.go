@@ -1,6 +1,9 @@
 package tools
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+)
 
 func truncateWithNotice(content string, maxChars int) (string, bool) {
 	runes := []rune(content)
@@ -18,3 +21,17 @@ func truncateWithNotice(content string, maxChars int) (string, bool) {
 
 	return head + marker + tail, true
 }
+
+// capBytesWithNotice keeps the first maxBytes bytes of content, cut on a rune
+// boundary, and appends a note saying how much was dropped. The note goes at
+// the end so a later head/tail truncation still shows it.
+func capBytesWithNotice(content string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(content) <= maxBytes {
+		return content, false
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + fmt.Sprintf("\n\n... [output exceeded the %d-byte tool result limit; %d bytes dropped] ...", maxBytes, len(content)-cut), true
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	webSearchCacheDefaultTTL = 5 * time.Minute
+	webSearchCacheMaxEntries = 200
+	webFetchCacheTTL         = 10 * time.Minute
+	webFetchCacheMaxEntries  = 32 // fetched pages can be large; keep the count small
+)
+
+// webCache is a size-bounded, concurrency-safe TTL cache for web tool
+// results. When full, the oldest entry is evicted. A nil cache never hits.
+type webCache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]webCacheEntry[V]
+	order      []string // insertion order; oldest first
+	now        func() time.Time
+}
+
+type webCacheEntry[V any] struct {
+	value    V
+	storedAt time.Time
+}
+
+func newWebCache[V any](ttl time.Duration, maxEntries int) *webCache[V] {
+	return &webCache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]webCacheEntry[V]),
+		now:        time.Now,
+	}
+}
+
+// get returns the cached value for key if it is still within the TTL.
+func (c *webCache[V]) get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || c.now().Sub(e.storedAt) > c.ttl {
+		return zero, false // expired entries are reclaimed by put
+	}
+	return e.value, true
+}
+
+// put stores value under key, evicting expired and then oldest entries to
+// stay within maxEntries.
+func (c *webCache[V]) put(key string, value V) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; exists {
+		for i, k := range c.order {
+			if k == key {
+				c.order = append(c.order[:i], c.order[i+1:]...)
+				break
+			}
+		}
+	}
+	c.entries[key] = webCacheEntry[V]{value: value, storedAt: now}
+	c.order = append(c.order, key)
+
+	i := 0
+	for ; i < len(c.order); i++ {
+		k := c.order[i]
+		if now.Sub(c.entries[k].storedAt) <= c.ttl && len(c.entries) <= c.maxEntries {
+			break
+		}
+		delete(c.entries, k)
+	}
+	c.order = c.order[i:]
+}
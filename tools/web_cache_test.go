@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingSearchProvider struct{ calls int }
+
+func (p *countingSearchProvider) Name() string    { return "mock" }
+func (p *countingSearchProvider) Tags() []string  { return nil }
+func (p *countingSearchProvider) Available() bool { return true }
+func (p *countingSearchProvider) Search(_ context.Context, query string, _ int) ([]SearchResult, error) {
+	p.calls++
+	return []SearchResult{{Title: "Result for " + query, URL: "https://example.com"}}, nil
+}
+
+type countingFetchProvider struct{ calls int }
+
+func (p *countingFetchProvider) Name() string          { return "mock" }
+func (p *countingFetchProvider) Tags() []string        { return nil }
+func (p *countingFetchProvider) Available() bool       { return true }
+func (p *countingFetchProvider) ReturnsMarkdown() bool { return true }
+func (p *countingFetchProvider) Fetch(_ context.Context, url string) (string, error) {
+	p.calls++
+	return "content of " + url, nil
+}
+
+func TestWebSearchCachesIdenticalQueries(t *testing.T) {
+	mock := &countingSearchProvider{}
+	tool := NewWebSearchTool(5, map[string]SearchProvider{"mock": mock}, "", nil, "", time.Minute)
+
+	run := func(query string) string {
+		args, _ := json.Marshal(map[string]any{"query": query, "source": "mock"})
+		return tool.Run(context.Background(), args)
+	}
+
+	first := run("golang generics")
+	if strings.Contains(first, "cached: true") {
+		t.Fatalf("first search should not be cached:\n%s", first)
+	}
+	// Same query modulo case and whitespace.
+	second := run("  Golang   GENERICS ")
+	if mock.calls != 1 {
+		t.Fatalf("provider calls = %d, want 1 (second search should be cached)", mock.calls)
+	}
+	if !strings.Contains(second, "cached: true") {
+		t.Errorf("second search should report cached:\n%s", second)
+	}
+
+	run("something else")
+	if mock.calls != 2 {
+		t.Errorf("provider calls = %d, want 2 for a different query", mock.calls)
+	}
+
+	// Past the TTL the provider is hit again.
+	tool.cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	run("golang generics")
+	if mock.calls != 3 {
+		t.Errorf("provider calls = %d, want 3 after TTL expiry", mock.calls)
+	}
+}
+
+func TestWebFetchCachesURL(t *testing.T) {
+	mock := &countingFetchProvider{}
+	tool := NewWebFetchTool(map[string]FetchProvider{"mock": mock}, nil, "")
+
+	args, _ := json.Marshal(map[string]any{"url": "https://example.com/a", "source": "mock"})
+	tool.Run(context.Background(), args)
+	out := tool.Run(context.Background(), args)
+	if mock.calls != 1 {
+		t.Fatalf("provider calls = %d, want 1", mock.calls)
+	}
+	if !strings.Contains(out, "cached: true") {
+		t.Errorf("second fetch should report cached:\n%s", out)
+	}
+}
+
+func TestWebCacheBounded(t *testing.T) {
+	c := newWebCache[int](time.Minute, 3)
+	for i, k := range []string{"a", "b", "c", "d"} {
+		c.put(k, i)
+	}
+	if len(c.entries) != 3 || len(c.order) != 3 {
+		t.Fatalf("entries=%d order=%d, want 3", len(c.entries), len(c.order))
+	}
+	if _, ok := c.get("a"); ok {
+		t.Error("oldest entry should have been evicted")
+	}
+	if v, ok := c.get("d"); !ok || v != 3 {
+		t.Errorf("get(d) = %d, %v; want 3, true", v, ok)
+	}
+
+	// Re-putting an existing key refreshes it instead of duplicating it.
+	c.put("b", 10)
+	c.put("e", 4)
+	if _, ok := c.get("b"); !ok {
+		t.Error("refreshed entry should survive eviction")
+	}
+	if _, ok := c.get("c"); ok {
+		t.Error("c should be the oldest entry and evicted")
+	}
+	if len(c.order) != 3 {
+		t.Errorf("order len = %d, want 3", len(c.order))
+	}
+}
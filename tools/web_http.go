@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// defaultWebUserAgent is sent by the scraping search and fetch providers
+// unless tools.web.userAgent or a per-call user_agent overrides it.
+const defaultWebUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"
+
+// WebHTTPConfig configures outbound HTTP for web_search and web_fetch.
+type WebHTTPConfig struct {
+	UserAgent string // replaces the default browser User-Agent; empty keeps it
+	Proxy     string // proxy URL (http, https, or socks5); empty uses HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+}
+
+type webHTTPSettings struct {
+	userAgent string
+	transport *http.Transport
+}
+
+var webHTTP atomic.Pointer[webHTTPSettings]
+
+// SetWebHTTPConfig applies cfg to all web tool requests made after the call.
+// An invalid proxy URL is rejected and the previous settings are kept.
+func SetWebHTTPConfig(cfg WebHTTPConfig) error {
+	transport, err := newWebTransport(cfg.Proxy)
+	if err != nil {
+		return err
+	}
+	webHTTP.Store(&webHTTPSettings{userAgent: strings.TrimSpace(cfg.UserAgent), transport: transport})
+	return nil
+}
+
+// newWebTransport clones the default transport with the given proxy. An
+// empty proxy keeps the environment-based proxy of the default transport.
+func newWebTransport(proxy string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxy = strings.TrimSpace(proxy)
+	if proxy == "" {
+		transport.Proxy = http.ProxyFromEnvironment
+		return transport, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid web proxy URL %q: want e.g. http://proxy.example.com:8080", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid web proxy URL %q: unsupported scheme %q", proxy, u.Scheme)
+	}
+	transport.Proxy = http.ProxyURL(u)
+	return transport, nil
+}
+
+// webHTTPClient returns a client for web tool requests using the configured
+// transport.
+func webHTTPClient(timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if s := webHTTP.Load(); s != nil {
+		client.Transport = s.transport
+	}
+	return client
+}
+
+type webUserAgentKey struct{}
+
+// withWebUserAgent overrides the User-Agent for web requests made with ctx.
+func withWebUserAgent(ctx context.Context, ua string) context.Context {
+	if ua = strings.TrimSpace(ua); ua == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, webUserAgentKey{}, ua)
+}
+
+// webUserAgent returns the User-Agent for a web request: the per-call
+// override from ctx, then tools.web.userAgent, then fallback.
+func webUserAgent(ctx context.Context, fallback string) string {
+	if ua, _ := ctx.Value(webUserAgentKey{}).(string); ua != "" {
+		return ua
+	}
+	if s := webHTTP.Load(); s != nil && s.userAgent != "" {
+		return s.userAgent
+	}
+	return fallback
+}
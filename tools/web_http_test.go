@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewWebTransportUsesConfiguredProxy(t *testing.T) {
+	transport, err := newWebTransport("http://proxy.example.com:3128")
+	if err != nil {
+		t.Fatalf("newWebTransport: %v", err)
+	}
+	req, _ := http.NewRequest("GET", "https://example.org/page", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy: %v", err)
+	}
+	if proxyURL == nil || proxyURL.String() != "http://proxy.example.com:3128" {
+		t.Errorf("proxy = %v, want http://proxy.example.com:3128", proxyURL)
+	}
+
+	for _, bad := range []string{"proxy.example.com:3128", "ftp://proxy.example.com", "://"} {
+		if _, err := newWebTransport(bad); err == nil {
+			t.Errorf("newWebTransport(%q) should fail", bad)
+		}
+	}
+}
+
+func TestWebFetchGoesThroughProxyWithUserAgent(t *testing.T) {
+	var gotURL, gotUA string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String() // absolute-form request URI when proxied
+		gotUA = r.UserAgent()
+		w.Write([]byte("<html><body>proxied</body></html>"))
+	}))
+	defer proxy.Close()
+
+	t.Cleanup(func() { webHTTP.Store(nil) })
+	if err := SetWebHTTPConfig(WebHTTPConfig{UserAgent: "nagobot-test/1.0", Proxy: proxy.URL}); err != nil {
+		t.Fatalf("SetWebHTTPConfig: %v", err)
+	}
+
+	p := &DirectFetchProvider{}
+	body, err := p.Fetch(context.Background(), "http://site.invalid/page")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if body != "<html><body>proxied</body></html>" || gotURL != "http://site.invalid/page" {
+		t.Errorf("request did not go through the proxy: url=%q body=%q", gotURL, body)
+	}
+	if gotUA != "nagobot-test/1.0" {
+		t.Errorf("User-Agent = %q, want the configured one", gotUA)
+	}
+
+	if _, err := p.Fetch(withWebUserAgent(context.Background(), "custom-agent"), "http://site.invalid/page"); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if gotUA != "custom-agent" {
+		t.Errorf("User-Agent = %q, want the per-call override", gotUA)
+	}
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+// webSummarizeMaxPageRunes caps the page text sent to the summary model; the
+// tail of longer pages is cut.
+const webSummarizeMaxPageRunes = 60000
+
+const webSummarizeInstruction = "Below is the text of the web page %s. %s " +
+	"Use only the page text; if it does not contain the answer, say so plainly. " +
+	"Keep URLs, numbers, names and dates exact. Be concise and output only the answer."
+
+// WebSummarizeTool fetches a page through web_fetch's sources and cache and
+// has a lightweight model distill it, so only the answer reaches the caller's
+// context, not the page.
+type WebSummarizeTool struct {
+	fetcher    *WebFetchTool
+	providerFn func() (provider.Provider, error)
+}
+
+// NewWebSummarizeTool creates a web_summarize tool that fetches with fetcher.
+// providerFn is called per run so model routing follows config hot-reload.
+func NewWebSummarizeTool(fetcher *WebFetchTool, providerFn func() (provider.Provider, error)) *WebSummarizeTool {
+	return &WebSummarizeTool{fetcher: fetcher, providerFn: providerFn}
+}
+
+// Def returns the tool definition.
+func (t *WebSummarizeTool) Def() provider.ToolDef {
+	return provider.ToolDef{
+		Type: "function",
+		Function: provider.FunctionDef{
+			Name: "web_summarize",
+			Description: "Fetch a web page and return a short summary, or the answer to a question about it, written by a lightweight model. " +
+				"The page itself never enters your context, so prefer this over web_fetch when you need facts from a page rather than its exact text. " +
+				"Uses the same sources and 10-minute cache as web_fetch.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"url": map[string]any{
+						"type":        "string",
+						"description": "The URL to fetch.",
+					},
+					"question": map[string]any{
+						"type":        "string",
+						"description": "What to find out from the page. Empty for a general summary.",
+					},
+					"source": map[string]any{
+						"type":        "string",
+						"description": "Fetch source, as for web_fetch. Empty to see guide.",
+					},
+				},
+				"required": []string{"url"},
+			},
+		},
+	}
+}
+
+type webSummarizeArgs struct {
+	URL      string `json:"url" required:"true"`
+	Question string `json:"question,omitempty" alias:"query,prompt"`
+	Source   string `json:"source,omitempty"`
+}
+
+// RunResult executes the tool.
+func (t *WebSummarizeTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
+	return withTimeout(ctx, "web_summarize", summarizeToolTimeout, func(ctx context.Context) ToolResult {
+		return t.run(ctx, args)
+	})
+}
+
+// Run implements Tool.
+func (t *WebSummarizeTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
+}
+
+func (t *WebSummarizeTool) run(ctx context.Context, args json.RawMessage) ToolResult {
+	var a webSummarizeArgs
+	if errMsg := parseArgs(args, &a); errMsg != "" {
+		return errorText(errMsg)
+	}
+	if t.fetcher == nil {
+		return ErrorResult("web_summarize", "web fetch is not configured")
+	}
+	if t.providerFn == nil {
+		return ErrorResult("web_summarize", "no provider configured")
+	}
+
+	page, errMsg := t.fetcher.fetch(ctx, "web_summarize", a.URL, a.Source, "")
+	if errMsg != "" {
+		return errorText(errMsg)
+	}
+	if strings.TrimSpace(page.content) == "" {
+		return ErrorResult("web_summarize", fmt.Sprintf("%s has no readable text; try another source", a.URL))
+	}
+
+	prov, err := t.providerFn()
+	if err != nil {
+		return ErrorResult("web_summarize", fmt.Sprintf("failed to create provider: %v", err))
+	}
+	text, truncated := truncateWithNotice(page.content, webSummarizeMaxPageRunes)
+	answer, err := summarizePage(ctx, prov, a.URL, strings.TrimSpace(a.Question), text)
+	if err != nil {
+		return ErrorResult("web_summarize", err.Error())
+	}
+
+	fields := map[string]any{
+		"url":         a.URL,
+		"source":      page.sourceLabel(a.Source),
+		"total_chars": len(page.content),
+	}
+	if page.cached {
+		fields["cached"] = true
+	}
+	if truncated {
+		fields["truncated"] = true
+	}
+	return okResult("web_summarize", fields, answer)
+}
+
+// summarizePage asks prov to answer question (or summarize, when empty) from
+// the page text.
+func summarizePage(ctx context.Context, prov provider.Provider, pageURL, question, text string) (string, error) {
+	task := "Summarize its main points as concise Markdown bullets."
+	if question != "" {
+		task = "Answer this question about it: " + question
+	}
+	req := &provider.Request{Messages: []provider.Message{
+		provider.UserMessage(fmt.Sprintf(webSummarizeInstruction, pageURL, task) + "\n\n<page>\n" + text + "\n</page>"),
+	}}
+	result, err := prov.Chat(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("summary LLM call failed: %w", err)
+	}
+	resp, err := result.Wait()
+	if err != nil {
+		return "", fmt.Errorf("summary LLM call failed: %w", err)
+	}
+	answer := strings.TrimSpace(resp.Content)
+	if answer == "" {
+		return "", fmt.Errorf("summary LLM returned empty content")
+	}
+	return answer, nil
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/linanwx/nagobot/provider"
+)
+
+func TestWebSummarizeReturnsOnlyTheAnswer(t *testing.T) {
+	fetch := &countingFetchProvider{}
+	stub := &stubSummaryProvider{}
+	tool := NewWebSummarizeTool(NewWebFetchTool(map[string]FetchProvider{"mock": fetch}, nil, ""),
+		func() (provider.Provider, error) { return stub, nil })
+
+	args, _ := json.Marshal(map[string]any{"url": "https://example.com/kyoto", "question": "Where is the trip?", "source": "mock"})
+	out := tool.Run(context.Background(), args)
+	if strings.Contains(out, "status: error") {
+		t.Fatalf("web_summarize failed:\n%s", out)
+	}
+	if !strings.Contains(out, "user planned a trip to Kyoto") {
+		t.Errorf("model answer not returned:\n%s", out)
+	}
+	if strings.Contains(out, "content of https://example.com/kyoto") {
+		t.Errorf("raw page leaked into the result:\n%s", out)
+	}
+
+	if fetch.calls != 1 || len(stub.reqs) != 1 {
+		t.Fatalf("fetch calls = %d, model calls = %d; want 1 each", fetch.calls, len(stub.reqs))
+	}
+	prompt := stub.reqs[0].Messages[0].Content
+	for _, want := range []string{"content of https://example.com/kyoto", "Where is the trip?"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -25,12 +24,35 @@ const (
 type WebSearchTool struct {
 	defaultMaxResults int
 	providers         map[string]SearchProvider
+	defaultSource     string // used when the call names no source; empty makes source required
 	healthChecker     *SearchHealthChecker
-	Guide             string // injected from WEB_SEARCH_GUIDE.md, appended to error responses
+	cache             *webCache[[]SearchResult] // nil disables caching
+	Guide             string                    // injected from WEB_SEARCH_GUIDE.md, appended to error responses
+}
+
+// NewWebSearchTool creates a web_search tool. Calls without a source use
+// defaultSource. Results are cached per source/query/max_results for
+// cacheTTL (<=0 uses the default).
+func NewWebSearchTool(defaultMaxResults int, providers map[string]SearchProvider, defaultSource string, hc *SearchHealthChecker, guide string, cacheTTL time.Duration) *WebSearchTool {
+	if cacheTTL <= 0 {
+		cacheTTL = webSearchCacheDefaultTTL
+	}
+	return &WebSearchTool{
+		defaultMaxResults: defaultMaxResults,
+		providers:         providers,
+		defaultSource:     defaultSource,
+		healthChecker:     hc,
+		cache:             newWebCache[[]SearchResult](cacheTTL, webSearchCacheMaxEntries),
+		Guide:             guide,
+	}
 }
 
 // Def returns the tool definition.
 func (t *WebSearchTool) Def() provider.ToolDef {
+	sourceDesc := "Search source. Empty to see guide."
+	if t.defaultSource != "" {
+		sourceDesc = fmt.Sprintf("Search source. Default: %s.", t.defaultSource)
+	}
 	return provider.ToolDef{
 		Type: "function",
 		Function: provider.FunctionDef{
@@ -49,7 +71,7 @@ func (t *WebSearchTool) Def() provider.ToolDef {
 					},
 					"source": map[string]any{
 						"type":        "string",
-						"description": "Search source. Empty to see guide.",
+						"description": sourceDesc,
 					},
 				},
 				"required": []string{"query"},
@@ -65,11 +87,11 @@ type webSearchArgs struct {
 	Source     string `json:"source,omitempty"`
 }
 
-// Run executes the tool.
-func (t *WebSearchTool) Run(ctx context.Context, args json.RawMessage) string {
+// RunResult executes the tool.
+func (t *WebSearchTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
 	var a webSearchArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
+		return errorText(errMsg)
 	}
 
 	if a.MaxResults <= 0 {
@@ -81,6 +103,9 @@ func (t *WebSearchTool) Run(ctx context.Context, args json.RawMessage) string {
 	}
 
 	source := a.Source
+	if source == "" {
+		source = t.defaultSource
+	}
 	if source == "" {
 		return t.sourceError("source is required")
 	}
@@ -93,19 +118,27 @@ func (t *WebSearchTool) Run(ctx context.Context, args json.RawMessage) string {
 		return t.sourceError(fmt.Sprintf("search source %q is not available", source))
 	}
 
-	start := time.Now()
-	results, err := p.Search(ctx, a.Query, a.MaxResults)
-	elapsed := time.Since(start).Milliseconds()
+	cacheKey := searchCacheKey(source, a.Query, a.MaxResults)
+	results, cached := t.cache.get(cacheKey)
+	if !cached {
+		start := time.Now()
+		var err error
+		results, err = p.Search(ctx, a.Query, a.MaxResults)
+		elapsed := time.Since(start).Milliseconds()
 
-	if err != nil {
-		if t.healthChecker != nil {
-			t.healthChecker.Record(source, false, 0, elapsed)
+		if err != nil {
+			if t.healthChecker != nil {
+				t.healthChecker.Record(source, false, 0, elapsed)
+			}
+			return t.searchError(source, a.Query, err)
 		}
-		return t.searchError(source, a.Query, err)
-	}
 
-	if t.healthChecker != nil {
-		t.healthChecker.Record(source, true, len(results), elapsed)
+		if t.healthChecker != nil {
+			t.healthChecker.Record(source, true, len(results), elapsed)
+		}
+		if len(results) > 0 {
+			t.cache.put(cacheKey, results)
+		}
 	}
 
 	if len(results) == 0 {
@@ -121,21 +154,36 @@ func (t *WebSearchTool) Run(ctx context.Context, args json.RawMessage) string {
 		"source":  source + sourceTags,
 		"results": len(results),
 	}
+	if cached {
+		fields["cached"] = true
+	}
 	if t.healthChecker != nil {
 		fields["source_status"] = t.healthChecker.StatusSummary()
 	}
-	return toolResult("web_search", fields, FormatSearchResults(a.Query, results))
+	return okResult("web_search", fields, FormatSearchResults(a.Query, results))
+}
+
+// Run implements Tool.
+func (t *WebSearchTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
 }
 
-func (t *WebSearchTool) sourceError(msg string) string {
-	return buildSourceError(msg, t.healthChecker, t.Guide)
+// searchCacheKey normalizes the query (case and whitespace) so trivially
+// different retries of the same search share a cache entry.
+func searchCacheKey(source, query string, maxResults int) string {
+	q := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	return fmt.Sprintf("%s\x00%d\x00%s", source, maxResults, q)
 }
 
-func (t *WebSearchTool) searchError(source, query string, err error) string {
-	return buildToolError("web_search", fmt.Sprintf("Error: search on %q failed: %v", source, err), t.healthChecker, t.Guide)
+func (t *WebSearchTool) sourceError(msg string) ToolResult {
+	return errorText(buildSourceError("web_search", msg, t.healthChecker, t.Guide))
 }
 
-func (t *WebSearchTool) emptyResults(source, query string) string {
+func (t *WebSearchTool) searchError(source, query string, err error) ToolResult {
+	return errorText(buildToolError("web_search", fmt.Sprintf("search on %q failed: %v", source, err), t.healthChecker, t.Guide))
+}
+
+func (t *WebSearchTool) emptyResults(source, query string) ToolResult {
 	fields := map[string]any{
 		"query":   query,
 		"source":  source,
@@ -147,27 +195,26 @@ func (t *WebSearchTool) emptyResults(source, query string) string {
 		body.WriteString(t.healthChecker.DetailedStatus())
 	}
 	appendGuide(&body, t.Guide)
-	return toolResult("web_search", fields, body.String())
-}
-
-// webFetchCache is a simple in-memory cache for fetched page content.
-var webFetchCache = struct {
-	sync.Mutex
-	entries map[string]webFetchCacheEntry
-}{entries: make(map[string]webFetchCacheEntry)}
-
-type webFetchCacheEntry struct {
-	content   string
-	fetchedAt time.Time
+	return okResult("web_search", fields, body.String())
 }
 
-const webFetchCacheTTL = 10 * time.Minute
-
 // WebFetchTool fetches content from a URL using pluggable providers.
 type WebFetchTool struct {
 	providers     map[string]FetchProvider
 	healthChecker *SearchHealthChecker // reused from web_search — tracks fetch outcomes
-	Guide         string              // injected from WEB_FETCH_GUIDE.md, appended to error responses
+	cache         *webCache[string]    // nil disables caching
+	Guide         string               // injected from WEB_FETCH_GUIDE.md, appended to error responses
+}
+
+// NewWebFetchTool creates a web_fetch tool. Fetched content is cached per
+// URL/source for 10 minutes.
+func NewWebFetchTool(providers map[string]FetchProvider, hc *SearchHealthChecker, guide string) *WebFetchTool {
+	return &WebFetchTool{
+		providers:     providers,
+		healthChecker: hc,
+		cache:         newWebCache[string](webFetchCacheTTL, webFetchCacheMaxEntries),
+		Guide:         guide,
+	}
 }
 
 // Def returns the tool definition.
@@ -196,6 +243,10 @@ func (t *WebFetchTool) Def() provider.ToolDef {
 						"type":        "integer",
 						"description": "Maximum number of characters to return. Default: 10000.",
 					},
+					"user_agent": map[string]any{
+						"type":        "string",
+						"description": "Optional User-Agent for this request, for sites that block the default one.",
+					},
 				},
 				"required": []string{"url"},
 			},
@@ -205,68 +256,25 @@ func (t *WebFetchTool) Def() provider.ToolDef {
 
 // webFetchArgs are the arguments for web_fetch.
 type webFetchArgs struct {
-	URL    string `json:"url" required:"true"`
-	Source string `json:"source,omitempty"`
-	Offset int    `json:"offset,omitempty"`
-	Limit  int    `json:"limit,omitempty"`
+	URL       string `json:"url" required:"true"`
+	Source    string `json:"source,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	UserAgent string `json:"user_agent,omitempty" alias:"userAgent,ua"`
 }
 
-// Run executes the tool.
-func (t *WebFetchTool) Run(ctx context.Context, args json.RawMessage) string {
+// RunResult executes the tool.
+func (t *WebFetchTool) RunResult(ctx context.Context, args json.RawMessage) ToolResult {
 	var a webFetchArgs
 	if errMsg := parseArgs(args, &a); errMsg != "" {
-		return errMsg
-	}
-
-	parsedURL, err := url.Parse(a.URL)
-	if err != nil {
-		return fmt.Sprintf("Error: invalid URL: %v", err)
-	}
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return "Error: only http and https URLs are supported"
-	}
-
-	source := a.Source
-	if source == "" {
-		return t.fetchSourceError("source is required")
-	}
-
-	p, ok := t.providers[source]
-	if !ok {
-		return t.fetchSourceError(fmt.Sprintf("unknown fetch source %q", source))
+		return errorText(errMsg)
 	}
-	if !p.Available() {
-		return t.fetchSourceError(fmt.Sprintf("fetch source %q is not available", source))
-	}
-
-	cacheKey := a.URL + "::" + source
-
-	// Check cache
-	content, cached := webFetchCacheLookup(cacheKey)
-	if !cached {
-		start := time.Now()
-		content, err = p.Fetch(ctx, a.URL)
-		elapsed := time.Since(start).Milliseconds()
 
-		if err != nil {
-			if t.healthChecker != nil {
-				t.healthChecker.Record(source, false, 0, elapsed)
-			}
-			return t.fetchError(source, a.URL, err)
-		}
-
-		if t.healthChecker != nil {
-			t.healthChecker.Record(source, true, len(content), elapsed)
-		}
-
-		// Providers that return raw HTML need content extraction.
-		if !p.ReturnsMarkdown() {
-			content = extractTextContent(content)
-		}
-
-		webFetchCacheStore(cacheKey, content)
+	page, errMsg := t.fetch(ctx, "web_fetch", a.URL, a.Source, a.UserAgent)
+	if errMsg != "" {
+		return errorText(errMsg)
 	}
-
+	content := page.content
 	totalChars := len(content)
 
 	// Apply offset/limit pagination
@@ -279,7 +287,7 @@ func (t *WebFetchTool) Run(ctx context.Context, args json.RawMessage) string {
 		offset = 0
 	}
 	if offset >= totalChars {
-		return toolError("web_fetch", fmt.Sprintf("offset %d is beyond end of content (total: %d chars)", offset, totalChars))
+		return ErrorResult("web_fetch", fmt.Sprintf("offset %d is beyond end of content (total: %d chars)", offset, totalChars))
 	}
 
 	end := offset + limit
@@ -288,17 +296,13 @@ func (t *WebFetchTool) Run(ctx context.Context, args json.RawMessage) string {
 	}
 	slice := content[offset:end]
 
-	sourceTags := ""
-	if tags := p.Tags(); len(tags) > 0 {
-		sourceTags = " [" + strings.Join(tags, ",") + "]"
-	}
 	fields := map[string]any{
 		"url":         a.URL,
-		"source":      source + sourceTags,
+		"source":      page.sourceLabel(a.Source),
 		"total_chars": totalChars,
 		"showing":     fmt.Sprintf("%d-%d", offset, end),
 	}
-	if cached {
+	if page.cached {
 		fields["cached"] = true
 	}
 	if end < totalChars {
@@ -308,43 +312,88 @@ func (t *WebFetchTool) Run(ctx context.Context, args json.RawMessage) string {
 		fields["source_status"] = t.healthChecker.StatusSummary()
 	}
 
-	return toolResult("web_fetch", fields, slice)
+	return okResult("web_fetch", fields, slice)
 }
 
-func (t *WebFetchTool) fetchSourceError(msg string) string {
-	return buildSourceError(msg, t.healthChecker, t.Guide)
+// Run implements Tool.
+func (t *WebFetchTool) Run(ctx context.Context, args json.RawMessage) string {
+	return t.RunResult(ctx, args).Content
 }
 
-func (t *WebFetchTool) fetchError(source, fetchURL string, err error) string {
-	return buildToolError("web_fetch", fmt.Sprintf("Error: fetch %q via %s failed: %v", fetchURL, source, err), t.healthChecker, t.Guide)
+// fetchedPage is a page's extracted text as returned by WebFetchTool.fetch.
+type fetchedPage struct {
+	content string
+	tags    []string // the fetch source's labels
+	cached  bool
 }
 
-func webFetchCacheLookup(key string) (string, bool) {
-	webFetchCache.Lock()
-	defer webFetchCache.Unlock()
-	entry, ok := webFetchCache.entries[key]
-	if !ok || time.Since(entry.fetchedAt) > webFetchCacheTTL {
-		if ok {
-			delete(webFetchCache.entries, key)
-		}
-		return "", false
+func (p fetchedPage) sourceLabel(source string) string {
+	if len(p.tags) == 0 {
+		return source
 	}
-	return entry.content, true
+	return source + " [" + strings.Join(p.tags, ",") + "]"
 }
 
-func webFetchCacheStore(key, content string) {
-	webFetchCache.Lock()
-	defer webFetchCache.Unlock()
-	// Evict expired entries if cache grows beyond 20
-	if len(webFetchCache.entries) >= 20 {
-		now := time.Now()
-		for k, e := range webFetchCache.entries {
-			if now.Sub(e.fetchedAt) > webFetchCacheTTL {
-				delete(webFetchCache.entries, k)
+// fetch returns the readable text of rawURL fetched via source, from the
+// cache when possible. On failure it returns an error result for tool, with
+// the source guide attached where it helps. web_fetch and web_summarize
+// share it, and with it the cache and health tracking.
+func (t *WebFetchTool) fetch(ctx context.Context, tool, rawURL, source, userAgent string) (fetchedPage, string) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fetchedPage{}, toolError(tool, fmt.Sprintf("invalid URL: %v", err))
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fetchedPage{}, toolError(tool, "only http and https URLs are supported")
+	}
+
+	if source == "" {
+		return fetchedPage{}, t.fetchSourceError(tool, "source is required")
+	}
+	p, ok := t.providers[source]
+	if !ok {
+		return fetchedPage{}, t.fetchSourceError(tool, fmt.Sprintf("unknown fetch source %q", source))
+	}
+	if !p.Available() {
+		return fetchedPage{}, t.fetchSourceError(tool, fmt.Sprintf("fetch source %q is not available", source))
+	}
+
+	cacheKey := rawURL + "::" + source
+
+	// Check cache
+	content, cached := t.cache.get(cacheKey)
+	if !cached {
+		start := time.Now()
+		content, err = p.Fetch(withWebUserAgent(ctx, userAgent), rawURL)
+		elapsed := time.Since(start).Milliseconds()
+
+		if err != nil {
+			if t.healthChecker != nil {
+				t.healthChecker.Record(source, false, 0, elapsed)
 			}
+			return fetchedPage{}, t.fetchError(tool, source, rawURL, err)
 		}
+
+		if t.healthChecker != nil {
+			t.healthChecker.Record(source, true, len(content), elapsed)
+		}
+
+		// Providers that return raw HTML need content extraction.
+		if !p.ReturnsMarkdown() {
+			content = extractTextContent(content)
+		}
+
+		t.cache.put(cacheKey, content)
 	}
-	webFetchCache.entries[key] = webFetchCacheEntry{content: content, fetchedAt: time.Now()}
+	return fetchedPage{content: content, tags: p.Tags(), cached: cached}, ""
+}
+
+func (t *WebFetchTool) fetchSourceError(tool, msg string) string {
+	return buildSourceError(tool, msg, t.healthChecker, t.Guide)
+}
+
+func (t *WebFetchTool) fetchError(tool, source, fetchURL string, err error) string {
+	return buildToolError(tool, fmt.Sprintf("fetch %q via %s failed: %v", fetchURL, source, err), t.healthChecker, t.Guide)
 }
 
 // extractTextContent extracts readable text from HTML.
@@ -377,15 +426,15 @@ func extractTextContent(html string) string {
 	return strings.Join(cleanLines, "\n")
 }
 
-// buildSourceError builds an error message with health status and guide for source selection failures.
-func buildSourceError(msg string, hc *SearchHealthChecker, guide string) string {
+// buildSourceError builds a tool error with health status and guide for source selection failures.
+func buildSourceError(toolName, msg string, hc *SearchHealthChecker, guide string) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Error: %s.\n\n", msg))
+	sb.WriteString(msg + ".\n\n")
 	if hc != nil {
 		sb.WriteString(hc.DetailedStatus())
 	}
 	appendGuide(&sb, guide)
-	return sb.String()
+	return toolError(toolName, sb.String())
 }
 
 // buildToolError builds a tool error with health status and guide.